@@ -0,0 +1,123 @@
+// Command hpe2e-server is the remote orchestration API of the e2e suites: dashboards and chatops request a suite run over HTTP, the runs are
+// queued and executed with hpe2e, --parallel at a time, and their progress is streamed as server-sent events, for e.g.
+//
+//	go build -o bin/ ./cmd/... && HPE2E_SERVER_TOKEN=<token> bin/hpe2e-server --hpe2e bin/hpe2e --listen :8080
+//	curl -H "Authorization: Bearer $HPE2E_SERVER_TOKEN" -d '{"provider": "eks", "suite": "provisioning", "k8sVersion": "1.30"}' localhost:8080/runs
+//
+// Every run is a one-run matrix of hpe2e (see hpe2e --matrix): the preflight checks, the copy of the cattle config and the artifacts of the run
+// are the ones of hpe2e, in the directory of the run. The settings not part of a run request are read from the env of the server, see README.md.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// defaultListen is the address the API listens on by default, only reachable from the host of the server
+const defaultListen = "127.0.0.1:8080"
+
+// options are the flags of the command
+type options struct {
+	listen   string
+	hpe2e    string
+	makefile string
+	output   string
+	parallel int
+	token    string
+}
+
+func main() {
+	opts, err := parseOptions(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fail(err)
+	}
+	s, err := newServer(opts)
+	if err != nil {
+		fail(err)
+	}
+
+	httpServer := &http.Server{Addr: opts.listen, Handler: s.handler(), ReadHeaderTimeout: 10 * time.Second}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		fmt.Println("Shutting down: the runs are interrupted and their specs cleaned up")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(ctx)
+	}()
+
+	fmt.Printf("Listening on %s, running %d suites at a time; the runs are written to %s\n", opts.listen, opts.parallel, opts.output)
+	if err = httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		fail(err)
+	}
+	s.shutdown()
+}
+
+// parseOptions parses the flags of the command
+func parseOptions(args []string) (*options, error) {
+	opts := &options{}
+	flags := flag.NewFlagSet("hpe2e-server", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: hpe2e-server [flags]\n\nServes the API triggering the e2e suites, run with hpe2e; see README.md.\n\n")
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&opts.listen, "listen", defaultListen, "address the API listens on; a token is required unless it is a loopback address")
+	flags.StringVar(&opts.hpe2e, "hpe2e", "hpe2e", "path of the hpe2e command the suites are run with")
+	flags.StringVar(&opts.makefile, "makefile", "Makefile", "Makefile the suites are read from, the suites are run from its directory")
+	flags.StringVar(&opts.output, "output", "server-runs", "directory the logs, reports and artifacts of the runs are written to, one directory per run")
+	flags.IntVar(&opts.parallel, "parallel", 1, "number of runs executed at a time; the runs share Rancher")
+	flags.StringVar(&opts.token, "token", os.Getenv("HPE2E_SERVER_TOKEN"), "bearer token required by the API, none if empty; HPE2E_SERVER_TOKEN")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments %v", flags.Args())
+	}
+	if opts.parallel < 1 {
+		return nil, errors.New("--parallel must be at least 1")
+	}
+	if opts.token == "" && !isLoopback(opts.listen) {
+		// anyone reaching the API could provision clusters with the credentials of the server
+		return nil, fmt.Errorf("a token is required to listen on %s; set HPE2E_SERVER_TOKEN, or listen on a loopback address, for e.g. %s", opts.listen, defaultListen)
+	}
+	var err error
+	if opts.output, err = filepath.Abs(opts.output); err != nil {
+		return nil, err
+	}
+	if opts.makefile, err = filepath.Abs(opts.makefile); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// isLoopback returns true if the address only listens on the loopback interface, for e.g. 127.0.0.1:8080 or localhost:8080;
+// an address without host, for e.g. :8080, listens on all the interfaces
+func isLoopback(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "hpe2e-server:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// States of a run
+const (
+	stateQueued   = "queued"
+	stateRunning  = "running"
+	statePassed   = "passed"
+	stateFailed   = "failed"
+	stateError    = "error"
+	stateCanceled = "canceled"
+)
+
+// Types of the events of a run
+const (
+	eventState = "state"
+	eventLog   = "log"
+)
+
+const (
+	// maxEvents is the number of events of a run kept to be streamed, the oldest log lines are dropped; the whole log is in hpe2e.log
+	maxEvents = 10000
+	// runLogFile is the file of the directory of a run the output of hpe2e is written to
+	runLogFile = "hpe2e.log"
+	// matrixFile is the file of the directory of a run the matrix given to hpe2e is written to
+	matrixFile = "matrix.json"
+	// matrixResultsFile is the file hpe2e writes the results of the matrix to, in the directory of the run
+	matrixResultsFile = "matrix-results.json"
+)
+
+var (
+	// providerRegexp matches the providers of the run requests; the provider is empty for the multi-provider suites
+	providerRegexp = regexp.MustCompile(`^(eks|gke|aks)?$`)
+	// valueRegexp matches the values of the run requests passed to hpe2e, for e.g. a k8s version or a region
+	valueRegexp = regexp.MustCompile(`^[a-zA-Z0-9._-]*$`)
+)
+
+// runRequest is the body of a run request: the suite to run, by name (see hpe2e --list or /suites), and the settings of the run
+type runRequest struct {
+	Provider   string `json:"provider,omitempty"`
+	Suite      string `json:"suite"`
+	K8sVersion string `json:"k8sVersion,omitempty"`
+	Region     string `json:"region,omitempty"`
+	// Labels is the ginkgo label filter of the specs
+	Labels string `json:"labels,omitempty"`
+	// Cleanup is the DOWNSTREAM_CLUSTER_CLEANUP of the run; the one of the env of the server if not set
+	Cleanup *bool `json:"cleanup,omitempty"`
+	DryRun  bool  `json:"dryRun,omitempty"`
+}
+
+// validate returns the problems of the request; the suites are the names of the suites of the Makefile
+func (r runRequest) validate(suites map[string]bool) (problems []string) {
+	if r.Suite == "" {
+		problems = append(problems, "suite is required")
+	} else if !suites[r.Suite] {
+		problems = append(problems, fmt.Sprintf("suite %q not found, see /suites", r.Suite))
+	}
+	if !providerRegexp.MatchString(r.Provider) {
+		problems = append(problems, fmt.Sprintf("provider %q is not supported, eks, gke or aks", r.Provider))
+	}
+	if r.Region != "" && r.Provider == "" {
+		problems = append(problems, "region needs the provider")
+	}
+	for name, value := range map[string]string{"k8sVersion": r.K8sVersion, "region": r.Region} {
+		if !valueRegexp.MatchString(value) {
+			problems = append(problems, fmt.Sprintf("%s %q is not valid", name, value))
+		}
+	}
+	return
+}
+
+// matrix returns the matrix file of the run, as read by hpe2e --matrix; the suites are matched by hpe2e like the name of a suite
+func (r runRequest) matrix() ([]byte, error) {
+	m := map[string]any{"suites": []string{r.Suite}, "parallel": 1}
+	if r.Provider != "" {
+		m["providers"] = []string{r.Provider}
+	}
+	if r.K8sVersion != "" {
+		m["k8sVersions"] = []string{r.K8sVersion}
+	}
+	if r.Region != "" {
+		m["regions"] = map[string][]string{r.Provider: {r.Region}}
+	}
+	if r.Labels != "" {
+		m["labels"] = r.Labels
+	}
+	if r.Cleanup != nil {
+		m["cleanup"] = *r.Cleanup
+	}
+	return json.MarshalIndent(m, "", "  ")
+}
+
+// runResult is the result of the suite, as written by hpe2e to matrixResultsFile
+type runResult struct {
+	Name     string `json:"name"`
+	ExitCode int    `json:"exitCode"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+	Passed   int    `json:"passed"`
+	Failed   int    `json:"failed"`
+	Skipped  int    `json:"skipped"`
+	Flaked   int    `json:"flaked"`
+}
+
+// runStatus is the status of a run, as returned by the API and sent with its state events
+type runStatus struct {
+	ID         string     `json:"id"`
+	Request    runRequest `json:"request"`
+	State      string     `json:"state"`
+	Error      string     `json:"error,omitempty"`
+	Dir        string     `json:"dir"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Result     *runResult `json:"result,omitempty"`
+}
+
+// done returns true once the run will not change anymore
+func (s runStatus) done() bool {
+	return s.State != stateQueued && s.State != stateRunning
+}
+
+// event is an event of a run, streamed as a server-sent event; its data is JSON
+type event struct {
+	ID   int
+	Type string
+	Data []byte
+}
+
+// run is a suite run of the server; its events are kept in memory to be streamed to the clients, from the start or from the last event they got
+type run struct {
+	mu      sync.Mutex
+	status  runStatus
+	events  []event
+	dropped int
+	// changed is closed and replaced when an event is added, to wake up the streams
+	changed chan struct{}
+	cancel  context.CancelFunc
+}
+
+func newRun(id, dir string, request runRequest) *run {
+	r := &run{
+		status:  runStatus{ID: id, Request: request, State: stateQueued, Dir: dir, CreatedAt: time.Now().UTC()},
+		changed: make(chan struct{}),
+	}
+	r.publishLocked(eventState, r.status)
+	return r
+}
+
+// Status returns a copy of the status of the run
+func (r *run) Status() runStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+// publishLocked adds an event to the run; it must be called with the run locked
+func (r *run) publishLocked(eventType string, data any) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		encoded, _ = json.Marshal(map[string]string{"error": err.Error()})
+	}
+	r.events = append(r.events, event{ID: r.dropped + len(r.events), Type: eventType, Data: encoded})
+	if len(r.events) > maxEvents {
+		r.events = r.events[1:]
+		r.dropped++
+	}
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// log adds a line of the output of hpe2e to the events of the run
+func (r *run) log(line string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.publishLocked(eventLog, map[string]string{"line": line})
+}
+
+// update changes the status of the run with f, and sends the new status as a state event
+func (r *run) update(f func(status *runStatus)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f(&r.status)
+	r.publishLocked(eventState, r.status)
+}
+
+// eventsFrom returns the events of the run from the ID next, the dropped events excluded, along with the channel closed on the next event
+// and whether the run is done
+func (r *run) eventsFrom(next int) ([]event, <-chan struct{}, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	start := next - r.dropped
+	if start < 0 {
+		start = 0
+	}
+	var events []event
+	if start < len(r.events) {
+		events = append(events, r.events[start:]...)
+	}
+	return events, r.changed, r.status.done()
+}
+
+/*
+execute runs the suite of the run with hpe2e, once a slot is free, and records its result.
+  - @param ctx Context of the run, canceled to interrupt it: hpe2e gets SIGTERM and forwards it to ginkgo, which runs the cleanup of the specs
+  - @param hpe2e Path of the hpe2e command
+  - @param makefile Makefile of the suites, hpe2e is run from its directory
+  - @param slots Semaphore of the runs executed at a time
+  - @returns Nothing, the outcome is the state of the run
+*/
+func (r *run) execute(ctx context.Context, hpe2e, makefile string, slots chan struct{}) {
+	select {
+	case slots <- struct{}{}:
+		defer func() { <-slots }()
+	case <-ctx.Done():
+		r.finish(stateCanceled, "canceled while queued", nil)
+		return
+	}
+	if ctx.Err() != nil {
+		r.finish(stateCanceled, "canceled while queued", nil)
+		return
+	}
+	r.update(func(status *runStatus) {
+		now := time.Now().UTC()
+		status.State, status.StartedAt = stateRunning, &now
+	})
+
+	status := r.Status()
+	if err := os.MkdirAll(status.Dir, 0o755); err != nil {
+		r.finish(stateError, err.Error(), nil)
+		return
+	}
+	matrix, err := status.Request.matrix()
+	if err == nil {
+		err = os.WriteFile(filepath.Join(status.Dir, matrixFile), matrix, 0o644)
+	}
+	if err != nil {
+		r.finish(stateError, err.Error(), nil)
+		return
+	}
+	logFile, err := os.Create(filepath.Join(status.Dir, runLogFile))
+	if err != nil {
+		r.finish(stateError, err.Error(), nil)
+		return
+	}
+	defer logFile.Close()
+
+	args := []string{"--makefile", makefile, "--matrix", filepath.Join(status.Dir, matrixFile), "--output", status.Dir, "--parallel", "1"}
+	if status.Request.DryRun {
+		args = append(args, "--dry-run")
+	}
+	cmd := exec.CommandContext(ctx, hpe2e, args...)
+	cmd.Dir = filepath.Dir(makefile)
+	cmd.Cancel = func() error { return cmd.Process.Signal(syscall.SIGTERM) }
+	output, writer := io.Pipe()
+	cmd.Stdout, cmd.Stderr = writer, writer
+	if err = cmd.Start(); err != nil {
+		r.finish(stateError, fmt.Sprintf("failed to run hpe2e: %v", err), nil)
+		return
+	}
+	streamed := make(chan struct{})
+	go func() {
+		defer close(streamed)
+		scanner := bufio.NewScanner(output)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			fmt.Fprintln(logFile, scanner.Text())
+			r.log(scanner.Text())
+		}
+		// the output left after a line too long is discarded, so that hpe2e does not block on the pipe
+		_, _ = io.Copy(logFile, output)
+	}()
+	waitErr := cmd.Wait()
+	writer.Close()
+	<-streamed
+
+	var exitErr *exec.ExitError
+	if waitErr != nil && !errors.As(waitErr, &exitErr) {
+		r.finish(stateError, fmt.Sprintf("failed to run hpe2e: %v", waitErr), nil)
+		return
+	}
+	state, message, result := runOutcome(filepath.Join(status.Dir, matrixResultsFile), cmd.ProcessState.ExitCode())
+	if ctx.Err() != nil {
+		state, message = stateCanceled, "canceled while running"
+	}
+	r.finish(state, message, result)
+}
+
+// finish sets the final state of the run
+func (r *run) finish(state, message string, result *runResult) {
+	r.update(func(status *runStatus) {
+		now := time.Now().UTC()
+		status.State, status.Error, status.Result, status.FinishedAt = state, message, result, &now
+	})
+}
+
+// runOutcome returns the state of a run from the results written by hpe2e and its exit code; there are no results if hpe2e failed
+// before running the suite, for e.g. on the preflight checks
+func runOutcome(resultsPath string, exitCode int) (string, string, *runResult) {
+	content, err := os.ReadFile(resultsPath)
+	if err != nil {
+		return stateError, fmt.Sprintf("hpe2e exited with code %d before running the suite, see %s", exitCode, runLogFile), nil
+	}
+	var results []runResult
+	if err = json.Unmarshal(content, &results); err != nil || len(results) != 1 {
+		return stateError, fmt.Sprintf("the results %s are not valid", resultsPath), nil
+	}
+	result := &results[0]
+	switch {
+	case result.Error != "":
+		return stateError, result.Error, result
+	case result.ExitCode != 0 || exitCode != 0:
+		return stateFailed, "", result
+	}
+	return statePassed, "", result
+}
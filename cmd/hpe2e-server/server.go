@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// server is the API of the runs; the runs are kept in memory, their directories stay in the output directory once the server is stopped
+type server struct {
+	opts   *options
+	hpe2e  string
+	suites map[string]bool
+	slots  chan struct{}
+	ctx    context.Context
+	stop   context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu   sync.Mutex
+	runs map[string]*run
+	seq  int
+}
+
+// newServer returns the server of the options, with the suites listed by hpe2e
+func newServer(opts *options) (*server, error) {
+	hpe2e, err := exec.LookPath(opts.hpe2e)
+	if err != nil {
+		return nil, fmt.Errorf("hpe2e not found, build it with go build -o bin/ ./cmd/...: %w", err)
+	}
+	if hpe2e, err = filepath.Abs(hpe2e); err != nil {
+		return nil, err
+	}
+	suites, err := listSuites(hpe2e, opts.makefile)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(opts.output, 0o755); err != nil {
+		return nil, err
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	return &server{opts: opts, hpe2e: hpe2e, suites: suites, slots: make(chan struct{}, opts.parallel), ctx: ctx, stop: stop, runs: map[string]*run{}}, nil
+}
+
+// listSuites returns the names of the suites listed by hpe2e --list
+func listSuites(hpe2e, makefile string) (map[string]bool, error) {
+	cmd := exec.Command(hpe2e, "--list", "--makefile", makefile)
+	cmd.Dir = filepath.Dir(makefile)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the suites with hpe2e: %w", err)
+	}
+	suites := map[string]bool{}
+	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+			suites[fields[0]] = true
+		}
+	}
+	if len(suites) == 0 {
+		return nil, fmt.Errorf("no suite listed by hpe2e from %s", makefile)
+	}
+	return suites, nil
+}
+
+// handler returns the routes of the API, behind the bearer token if set
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /suites", s.listSuites)
+	mux.HandleFunc("POST /runs", s.createRun)
+	mux.HandleFunc("GET /runs", s.listRuns)
+	mux.HandleFunc("GET /runs/{id}", s.getRun)
+	mux.HandleFunc("DELETE /runs/{id}", s.cancelRun)
+	mux.HandleFunc("GET /runs/{id}/events", s.streamEvents)
+	mux.HandleFunc("GET /runs/{id}/log", s.getLog)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	return s.authenticate(mux)
+}
+
+// authenticate rejects the requests without the bearer token of the server, except the health checks
+func (s *server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if s.opts.token != "" && r.URL.Path != "/healthz" && subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// shutdown cancels the runs and waits for them to be cleaned up
+func (s *server) shutdown() {
+	s.stop()
+	s.wg.Wait()
+}
+
+func (s *server) listSuites(w http.ResponseWriter, _ *http.Request) {
+	suites := make([]string, 0, len(s.suites))
+	for name := range s.suites {
+		suites = append(suites, name)
+	}
+	sort.Strings(suites)
+	writeJSON(w, http.StatusOK, suites)
+}
+
+func (s *server) createRun(w http.ResponseWriter, r *http.Request) {
+	var request runRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64*1024))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid run request: %v", err))
+		return
+	}
+	if problems := request.validate(s.suites); len(problems) > 0 {
+		writeError(w, http.StatusBadRequest, strings.Join(problems, "; "))
+		return
+	}
+
+	s.mu.Lock()
+	s.seq++
+	id := fmt.Sprintf("%s-%d", time.Now().UTC().Format("20060102-150405"), s.seq)
+	created := newRun(id, filepath.Join(s.opts.output, id), request)
+	ctx, cancel := context.WithCancel(s.ctx)
+	created.cancel = cancel
+	s.runs[id] = created
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+		created.execute(ctx, s.hpe2e, s.opts.makefile, s.slots)
+	}()
+	w.Header().Set("Location", "/runs/"+id)
+	writeJSON(w, http.StatusAccepted, created.Status())
+}
+
+func (s *server) listRuns(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	statuses := make([]runStatus, 0, len(s.runs))
+	for _, run := range s.runs {
+		if state := r.URL.Query().Get("state"); state == "" || run.Status().State == state {
+			statuses = append(statuses, run.Status())
+		}
+	}
+	s.mu.Unlock()
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].CreatedAt.After(statuses[j].CreatedAt) })
+	writeJSON(w, http.StatusOK, statuses)
+}
+
+// lookup returns the run of the id of the request, or writes the not found error
+func (s *server) lookup(w http.ResponseWriter, r *http.Request) *run {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, ok := s.runs[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("run %q not found", r.PathValue("id")))
+	}
+	return run
+}
+
+func (s *server) getRun(w http.ResponseWriter, r *http.Request) {
+	if run := s.lookup(w, r); run != nil {
+		writeJSON(w, http.StatusOK, run.Status())
+	}
+}
+
+// cancelRun interrupts the run; the cleanup of the specs still runs, the run is canceled once it is done
+func (s *server) cancelRun(w http.ResponseWriter, r *http.Request) {
+	run := s.lookup(w, r)
+	if run == nil {
+		return
+	}
+	if run.Status().done() {
+		writeError(w, http.StatusConflict, fmt.Sprintf("run %s is already %s", run.Status().ID, run.Status().State))
+		return
+	}
+	run.cancel()
+	writeJSON(w, http.StatusAccepted, run.Status())
+}
+
+/*
+streamEvents streams the events of the run as server-sent events until it is done: the state events, with the status of the run,
+and the log events, with a line of the output of hpe2e; a client resuming the stream with Last-Event-ID only gets the next events.
+*/
+func (s *server) streamEvents(w http.ResponseWriter, r *http.Request) {
+	run := s.lookup(w, r)
+	if run == nil {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+	next := 0
+	if id, err := strconv.Atoi(r.Header.Get("Last-Event-ID")); err == nil {
+		next = id + 1
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	for {
+		events, changed, done := run.eventsFrom(next)
+		for _, e := range events {
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, e.Data)
+			next = e.ID + 1
+		}
+		flusher.Flush()
+		if done && len(events) == 0 {
+			return
+		}
+		if done {
+			continue
+		}
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// getLog returns the whole output of hpe2e for the run
+func (s *server) getLog(w http.ResponseWriter, r *http.Request) {
+	run := s.lookup(w, r)
+	if run == nil {
+		return
+	}
+	content, err := os.ReadFile(filepath.Join(run.Status().Dir, runLogFile))
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no log for run %s yet", run.Status().ID))
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write(content)
+}
+
+func writeJSON(w http.ResponseWriter, code int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]string{"error": message})
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeHPE2E lists a suite with --list, and otherwise prints its arguments and writes the results of a passed run to --output
+const fakeHPE2E = `#!/bin/sh
+if [ "$1" = "--list" ]; then
+	echo "provisioning                          Run the 'P0Provisioning' test suite"
+	exit 0
+fi
+echo "hpe2e $@"
+while [ $# -gt 0 ]; do
+	[ "$1" = "--output" ] && output=$2
+	shift
+done
+echo '[{"name": "eks-provisioning", "exitCode": 0, "duration": "1s", "passed": 3, "skipped": 1}]' > "$output/matrix-results.json"
+`
+
+func newTestServer(t *testing.T) (*server, *httptest.Server) {
+	dir := t.TempDir()
+	hpe2e := filepath.Join(dir, "hpe2e")
+	if err := os.WriteFile(hpe2e, []byte(fakeHPE2E), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	s, err := newServer(&options{hpe2e: hpe2e, makefile: filepath.Join(dir, "Makefile"), output: filepath.Join(dir, "runs"), parallel: 1, token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s.handler())
+	t.Cleanup(func() {
+		ts.Close()
+		s.shutdown()
+	})
+	return s, ts
+}
+
+func request(t *testing.T, method, url, body string) *http.Response {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestRunRequestValidate(t *testing.T) {
+	suites := map[string]bool{"provisioning": true}
+	for _, tc := range []struct {
+		request  runRequest
+		problems int
+	}{
+		{request: runRequest{Suite: "provisioning", Provider: "eks", K8sVersion: "1.30", Region: "ap-south-1"}},
+		{request: runRequest{Suite: "provisioning"}},
+		{request: runRequest{}, problems: 1},
+		{request: runRequest{Suite: "p9-unknown", Provider: "rke2"}, problems: 2},
+		{request: runRequest{Suite: "provisioning", Region: "ap-south-1", K8sVersion: "1.30; rm -rf"}, problems: 2},
+	} {
+		if problems := tc.request.validate(suites); len(problems) != tc.problems {
+			t.Errorf("%+v: got %q, want %d problems", tc.request, problems, tc.problems)
+		}
+	}
+}
+
+func TestRunOutcome(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, matrixResultsFile)
+	if state, _, _ := runOutcome(path, 1); state != stateError {
+		t.Errorf("got %s without results, want %s", state, stateError)
+	}
+	for _, tc := range []struct {
+		results string
+		state   string
+	}{
+		{results: `[{"name": "eks-p0", "exitCode": 0, "passed": 2}]`, state: statePassed},
+		{results: `[{"name": "eks-p0", "exitCode": 1, "passed": 1, "failed": 1}]`, state: stateFailed},
+		{results: `[{"name": "eks-p0", "error": "exec: ginkgo not found"}]`, state: stateError},
+	} {
+		if err := os.WriteFile(path, []byte(tc.results), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if state, _, _ := runOutcome(path, 0); state != tc.state {
+			t.Errorf("%s: got %s, want %s", tc.results, state, tc.state)
+		}
+	}
+}
+
+func TestServerRun(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/runs", nil)
+	if resp, err := http.DefaultClient.Do(req); err != nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("got %v, %v without the token", resp, err)
+	}
+	if resp := request(t, http.MethodPost, ts.URL+"/runs", `{"suite": "provisioning", "provider": "eks", "cluster": "x"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("got %d with an unknown field", resp.StatusCode)
+	}
+
+	resp := request(t, http.MethodPost, ts.URL+"/runs", `{"suite": "provisioning", "provider": "eks", "k8sVersion": "1.30", "dryRun": true}`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("got %d", resp.StatusCode)
+	}
+	var created runStatus
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+
+	// the stream ends once the run is done, with all its events
+	events := request(t, http.MethodGet, ts.URL+"/runs/"+created.ID+"/events", "")
+	var states, logs []string
+	scanner := bufio.NewScanner(events.Body)
+	eventType := ""
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: ") && eventType == eventState:
+			var status runStatus
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &status); err != nil {
+				t.Fatal(err)
+			}
+			states = append(states, status.State)
+		case strings.HasPrefix(line, "data: ") && eventType == eventLog:
+			logs = append(logs, line)
+		}
+	}
+	if strings.Join(states, ",") != "queued,running,passed" {
+		t.Errorf("got states %v", states)
+	}
+	if len(logs) != 1 || !strings.Contains(logs[0], "--dry-run") {
+		t.Errorf("got logs %v", logs)
+	}
+
+	status := s.runs[created.ID].Status()
+	if status.Result == nil || status.Result.Passed != 3 || status.FinishedAt == nil {
+		t.Errorf("got %+v", status)
+	}
+	matrix, err := os.ReadFile(filepath.Join(status.Dir, matrixFile))
+	if err != nil || !strings.Contains(string(matrix), `"1.30"`) {
+		t.Errorf("got matrix %s, %v", matrix, err)
+	}
+	if resp = request(t, http.MethodDelete, ts.URL+"/runs/"+created.ID, ""); resp.StatusCode != http.StatusConflict {
+		t.Errorf("got %d canceling a run already done", resp.StatusCode)
+	}
+}
+
+func TestRunCanceledWhileQueued(t *testing.T) {
+	s, _ := newTestServer(t)
+	// the only slot is taken
+	s.slots <- struct{}{}
+	defer func() { <-s.slots }()
+
+	r := newRun("queued", t.TempDir(), runRequest{Suite: "provisioning"})
+	done := make(chan struct{})
+	ctx, cancel := s.ctx, s.stop
+	go func() {
+		defer close(done)
+		r.execute(ctx, s.hpe2e, s.opts.makefile, s.slots)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the queued run was not canceled")
+	}
+	if status := r.Status(); status.State != stateCanceled {
+		t.Errorf("got %s, want %s", status.State, stateCanceled)
+	}
+}
+
+func TestParseOptionsRequiresToken(t *testing.T) {
+	t.Setenv("HPE2E_SERVER_TOKEN", "")
+	for _, tc := range []struct {
+		args    []string
+		wantErr bool
+	}{
+		{args: nil},
+		{args: []string{"--listen", "localhost:9090"}},
+		{args: []string{"--listen", "[::1]:8080"}},
+		{args: []string{"--listen", ":8080"}, wantErr: true},
+		{args: []string{"--listen", "0.0.0.0:8080"}, wantErr: true},
+		{args: []string{"--listen", ":8080", "--token", "secret"}},
+	} {
+		if _, err := parseOptions(tc.args); (err != nil) != tc.wantErr {
+			t.Errorf("%v: got error %v", tc.args, err)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+// Command hpe2e runs the e2e suites of the hosted providers: the suite is chosen by name, the run is set up from flags instead of env vars,
+// and the environment is validated with the preflight checks of the suites before ginkgo is started, so that a missing credential or an
+// invalid setting is reported at once instead of after the Rancher setup. The suites and their ginkgo options are read from the e2e targets
+// of the Makefile, which it must be run next to, for e.g.
+//
+//	go run ./cmd/hpe2e --provider eks --suite P0Provisioning --k8s-version 1.30 --region ap-south-1 --cleanup
+//
+// With --dry-run, the configs of the clusters of the suite are validated without creating anything, see helpers.DryRunCluster.
+// Every other setting is still read from the env, see README.md. With --matrix, the suites of a matrix file are run instead, see matrix.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// options are the flags of the command
+type options struct {
+	makefile   string
+	list       bool
+	provider   string
+	suite      string
+	k8sVersion string
+	region     string
+	cleanup    bool
+	dryRun     bool
+	labels     string
+	// matrix is the matrix file of the suites to run instead of --suite, run parallel suites at a time, with the results written to output
+	matrix   string
+	parallel int
+	output   string
+	// set are the flags given on the command line, the env is only changed for them
+	set map[string]bool
+}
+
+func main() {
+	opts, err := parseOptions(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fail(err)
+	}
+	lines, err := readMakefile(opts.makefile)
+	if err != nil {
+		fail(fmt.Errorf("failed to read the suites of %s: %w", opts.makefile, err))
+	}
+	if opts.list {
+		suites, err := parseSuites(lines, os.Getenv)
+		if err != nil {
+			fail(err)
+		}
+		for _, s := range suites {
+			fmt.Printf("%-40s %s\n", s.Name, s.Help)
+		}
+		return
+	}
+	if opts.matrix != "" {
+		if opts.dryRun {
+			// the runs of the matrix inherit the env
+			os.Setenv("DRY_RUN", "true")
+		}
+		os.Exit(runMatrix(opts, lines))
+	}
+
+	env, err := opts.env()
+	if err != nil {
+		fail(err)
+	}
+	for key, value := range env {
+		os.Setenv(key, value)
+	}
+	// the targets are expanded once the env is set, since their directory depends on PROVIDER
+	suites, err := parseSuites(lines, os.Getenv)
+	if err != nil {
+		fail(err)
+	}
+	s, found := findSuite(suites, opts.suite)
+	if !found {
+		fail(fmt.Errorf("suite %q not found; run with --list to list the suites", opts.suite))
+	}
+
+	if problems := preflight(s, os.Getenv("PROVIDER")); len(problems) > 0 {
+		fail(fmt.Errorf("preflight checks of suite %s failed:\n - %s", s.Name, strings.Join(problems, "\n - ")))
+	}
+	code, err := runGinkgo(s, opts.labels, nil, os.Stdout)
+	if err != nil {
+		fail(err)
+	}
+	os.Exit(code)
+}
+
+// parseOptions parses the flags of the command
+func parseOptions(args []string) (*options, error) {
+	opts := &options{set: map[string]bool{}}
+	flags := flag.NewFlagSet("hpe2e", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: hpe2e --suite <suite> [flags]\n\nRuns a suite of the Makefile after validating the environment; see --list for the suites.\n\n")
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&opts.makefile, "makefile", "Makefile", "Makefile the suites are read from")
+	flags.BoolVar(&opts.list, "list", false, "list the suites and exit")
+	flags.StringVar(&opts.provider, "provider", "", "provider of the suite, eks, gke or aks; PROVIDER")
+	flags.StringVar(&opts.suite, "suite", "", "suite to run, by name (for e.g. p1-import), make target (e2e-p1-import-tests) or focus (P1Import)")
+	flags.StringVar(&opts.k8sVersion, "k8s-version", "", "k8s minor version or version constraint of the downstream clusters, for e.g. 1.30; DOWNSTREAM_K8S_MINOR_VERSION")
+	flags.StringVar(&opts.region, "region", "", "region of the clusters, or zone on GKE; EKS_REGION, GKE_REGION or GKE_ZONE, AKS_REGION")
+	flags.BoolVar(&opts.cleanup, "cleanup", false, "delete the downstream clusters once the specs are done; DOWNSTREAM_CLUSTER_CLEANUP")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "only validate the configs of the clusters against the Rancher schema and the cloud provider, without creating them; DRY_RUN")
+	flags.StringVar(&opts.labels, "labels", "", "ginkgo label filter of the specs, for e.g. 'import && !upgrade'")
+	flags.StringVar(&opts.matrix, "matrix", "", "matrix file of the suites to run instead of --suite, see README.md")
+	flags.IntVar(&opts.parallel, "parallel", 0, "number of suites of the matrix run at a time, the parallel of the matrix file if 0")
+	flags.StringVar(&opts.output, "output", "matrix-results", "directory the logs, reports and results of the matrix runs are written to")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments %v", flags.Args())
+	}
+	flags.Visit(func(f *flag.Flag) { opts.set[f.Name] = true })
+	if !opts.list && opts.suite == "" && opts.matrix == "" {
+		return nil, errors.New("--suite or --matrix is required; run with --list to list the suites")
+	}
+	return opts, nil
+}
+
+// env returns the env vars of the flags given on the command line, the ones read by the suites through helpers.LoadRunConfig
+func (o *options) env() (map[string]string, error) {
+	env := map[string]string{}
+	if o.set["provider"] {
+		env["PROVIDER"] = o.provider
+	}
+	if o.set["k8s-version"] {
+		env["DOWNSTREAM_K8S_MINOR_VERSION"] = o.k8sVersion
+	}
+	if o.set["cleanup"] {
+		env["DOWNSTREAM_CLUSTER_CLEANUP"] = strconv.FormatBool(o.cleanup)
+	}
+	if o.set["dry-run"] {
+		env["DRY_RUN"] = strconv.FormatBool(o.dryRun)
+	}
+	if o.set["region"] {
+		provider := o.provider
+		if !o.set["provider"] {
+			provider = os.Getenv("PROVIDER")
+		}
+		key, err := regionEnv(provider, o.region)
+		if err != nil {
+			return nil, err
+		}
+		env[key] = o.region
+	}
+	return env, nil
+}
+
+// regionEnv returns the env var of the region of the provider: EKS_REGION, AKS_REGION, or GKE_ZONE or GKE_REGION whether the location is a zone
+func regionEnv(provider, region string) (string, error) {
+	switch provider {
+	case "eks":
+		return "EKS_REGION", nil
+	case "gke":
+		if helpers.IsGKEZone(region) {
+			return "GKE_ZONE", nil
+		}
+		return "GKE_REGION", nil
+	case "aks":
+		return "AKS_REGION", nil
+	}
+	return "", fmt.Errorf("region %s needs the provider, eks, gke or aks; got %q", region, provider)
+}
+
+// preflight returns the problems found by the preflight checks of the suite, run from its directory as ginkgo does,
+// so that the relative paths, for e.g. CATTLE_TEST_CONFIG, are resolved the same way; ginkgo must be installed too.
+func preflight(s suite, provider string) []string {
+	var problems []string
+	if _, err := exec.LookPath("ginkgo"); err != nil {
+		problems = append(problems, "ginkgo is not installed or not in PATH; run make deps")
+	}
+	if info, err := os.Stat(s.Dir); err != nil || !info.IsDir() {
+		return append(problems, fmt.Sprintf("the directory %s of the suite does not exist; check PROVIDER", s.Dir))
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return append(problems, err.Error())
+	}
+	if err = os.Chdir(s.Dir); err != nil {
+		return append(problems, err.Error())
+	}
+	defer os.Chdir(wd)
+	// the secrets are exported to the env read by the run config
+	if _, err = helpers.LoadSecrets(); err != nil {
+		return append(problems, fmt.Sprintf("failed to load the secrets with SECRETS_PROVIDER=%s: %v", os.Getenv("SECRETS_PROVIDER"), err))
+	}
+	return append(problems, helpers.PreflightProblems(helpers.LoadRunConfig(), s.runConfigSuite(), s.providers(provider)...)...)
+}
+
+/*
+runGinkgo runs ginkgo with the options of the suite and returns its exit code; ginkgo gets the interrupts of the terminal along with the command,
+and SIGTERM is forwarded to it, so that it runs the cleanup of the specs before exiting.
+  - @param s Suite to run
+  - @param labels ginkgo label filter of the specs, none if empty
+  - @param env KEY=value variables added to the env of the command, for e.g. the ones of a matrix run
+  - @param output Writer of the output of ginkgo, for e.g. os.Stdout or the log of a matrix run
+  - @param args Arguments added to the options of the suite, for e.g. the reports of a matrix run
+  - @returns The exit code of ginkgo, or the error if it could not be run
+*/
+func runGinkgo(s suite, labels string, env []string, output io.Writer, args ...string) (int, error) {
+	args = append(append([]string{}, s.Args...), args...)
+	if labels != "" {
+		args = append(args, "--label-filter="+labels)
+	}
+	args = append(args, s.Dir)
+	fmt.Fprintf(output, "Running ginkgo %s\n", strings.Join(args, " "))
+
+	cmd := exec.Command("ginkgo", args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout, cmd.Stderr = output, output
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	if err := cmd.Start(); err != nil {
+		return 0, err
+	}
+	go func() {
+		for sig := range signals {
+			// a second interrupt would make ginkgo skip the cleanup
+			if sig == syscall.SIGTERM {
+				_ = cmd.Process.Signal(sig)
+			}
+		}
+	}()
+
+	var exitErr *exec.ExitError
+	if err := cmd.Wait(); errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	} else if err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "hpe2e:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOptionsEnv(t *testing.T) {
+	t.Setenv("PROVIDER", "aks")
+	for _, tc := range []struct {
+		name string
+		args []string
+		want map[string]string
+	}{
+		{name: "no flag", args: []string{"--suite", "p1-import"}, want: map[string]string{}},
+		{name: "eks", args: []string{"--suite", "p1-import", "--provider", "eks", "--region", "ap-south-1", "--k8s-version", "1.30", "--cleanup"},
+			want: map[string]string{"PROVIDER": "eks", "EKS_REGION": "ap-south-1", "DOWNSTREAM_K8S_MINOR_VERSION": "1.30", "DOWNSTREAM_CLUSTER_CLEANUP": "true"}},
+		{name: "gke zone", args: []string{"--suite", "p1-import", "--provider", "gke", "--region", "asia-south2-c", "--cleanup=false"},
+			want: map[string]string{"PROVIDER": "gke", "GKE_ZONE": "asia-south2-c", "DOWNSTREAM_CLUSTER_CLEANUP": "false"}},
+		{name: "gke region", args: []string{"--suite", "p1-import", "--provider", "gke", "--region", "asia-south2", "--dry-run"},
+			want: map[string]string{"PROVIDER": "gke", "GKE_REGION": "asia-south2", "DRY_RUN": "true"}},
+		{name: "provider of the env", args: []string{"--suite", "p1-import", "--region", "centralindia"}, want: map[string]string{"AKS_REGION": "centralindia"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := parseOptions(tc.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			env, err := opts.env()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(env, tc.want) {
+				t.Errorf("got %v, want %v", env, tc.want)
+			}
+		})
+	}
+
+	if _, err := parseOptions([]string{"--provider", "eks"}); err == nil {
+		t.Error("expected an error without --suite")
+	}
+	opts, _ := parseOptions([]string{"--suite", "p1-import", "--provider", "multi", "--region", "ap-south-1"})
+	if _, err := opts.env(); err == nil {
+		t.Error("expected an error for a region without provider")
+	}
+}
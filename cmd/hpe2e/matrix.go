@@ -0,0 +1,434 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/onsi/ginkgo/v2/types"
+	"sigs.k8s.io/yaml"
+)
+
+// matrixResultsFile is the file of the output directory the results of the matrix runs are written to
+const matrixResultsFile = "matrix-results.json"
+
+// runNameRegexp matches the characters replaced in the names of the runs, which are also the names of their directories
+var runNameRegexp = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+
+/*
+matrix is a matrix file: the suites are run for every provider, k8s version and region of the provider, for e.g.
+
+	providers: [eks, gke]
+	suites: [p1-import, P1Provisioning]
+	k8sVersions: ["1.30", "1.31"]
+	regions:
+	  eks: [ap-south-1, us-west-2]
+	  gke: [asia-south2-c]
+	exclude:
+	  - provider: gke
+	    k8sVersion: "1.31"
+
+runs p1-import and P1Provisioning on EKS in both regions and on GKE in asia-south2-c, with both k8s versions on EKS and 1.30 on GKE.
+*/
+type matrix struct {
+	Providers []string `json:"providers"`
+	// Suites are the suites by name, make target or focus, see suite.is; the multi-provider suites are run once, whatever the providers
+	Suites []string `json:"suites"`
+	// K8sVersions are the DOWNSTREAM_K8S_MINOR_VERSION of the runs; the one of the env if empty
+	K8sVersions []string `json:"k8sVersions"`
+	// Regions are the regions (or GKE zones) of the runs by provider; the one of the env if the provider has none
+	Regions map[string][]string `json:"regions"`
+	// Exclude are the combinations not run; the empty fields match any value
+	Exclude []matrixRun `json:"exclude"`
+	// Labels is the ginkgo label filter of the specs of all the runs
+	Labels string `json:"labels"`
+	// Cleanup is the DOWNSTREAM_CLUSTER_CLEANUP of all the runs; the one of the env if not set
+	Cleanup *bool `json:"cleanup"`
+	// Env are the env vars of all the runs, for e.g. FLAKE_ATTEMPTS
+	Env map[string]string `json:"env"`
+	// Parallel is the number of suites run at a time, 1 if not set; the runs share Rancher but each one has its own cattle config and artifacts
+	Parallel int `json:"parallel"`
+}
+
+// matrixRun is a suite run of the matrix
+type matrixRun struct {
+	Provider   string `json:"provider,omitempty"`
+	Suite      string `json:"suite,omitempty"`
+	K8sVersion string `json:"k8sVersion,omitempty"`
+	Region     string `json:"region,omitempty"`
+
+	suite suite
+	env   map[string]string
+}
+
+// matrixResult is the result of a suite run of the matrix, as written to matrixResultsFile
+type matrixResult struct {
+	Name string `json:"name"`
+	matrixRun
+	ExitCode int    `json:"exitCode"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+	Passed   int    `json:"passed"`
+	Failed   int    `json:"failed"`
+	Skipped  int    `json:"skipped"`
+	Flaked   int    `json:"flaked"`
+}
+
+// loadMatrix reads and checks the matrix file
+func loadMatrix(path string) (*matrix, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &matrix{}
+	if err = yaml.UnmarshalStrict(content, m); err != nil {
+		return nil, fmt.Errorf("matrix %s is not valid: %w", path, err)
+	}
+	if len(m.Suites) == 0 {
+		return nil, fmt.Errorf("matrix %s has no suite", path)
+	}
+	if m.Parallel < 0 {
+		return nil, fmt.Errorf("matrix %s is not valid: parallel must be positive", path)
+	}
+	return m, nil
+}
+
+// excluded returns true if the run matches one of the exclusions of the matrix
+func (m *matrix) excluded(run matrixRun) bool {
+	for _, exclude := range m.Exclude {
+		if (exclude.Provider == "" || exclude.Provider == run.Provider) && (exclude.Suite == "" || run.suite.is(exclude.Suite)) &&
+			(exclude.K8sVersion == "" || exclude.K8sVersion == run.K8sVersion) && (exclude.Region == "" || exclude.Region == run.Region) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+expand returns the runs of the matrix, with the env vars of every run; the suites are parsed from the Makefile lines for every provider,
+their directory depending on PROVIDER.
+  - @param lines Lines of the Makefile, see readMakefile
+  - @param getenv Reads the env, for e.g. os.Getenv
+  - @returns The runs, in the order of the suites, providers, k8s versions and regions, or the error of an unknown suite or provider
+*/
+func (m *matrix) expand(lines []string, getenv func(string) string) ([]matrixRun, error) {
+	k8sVersions, providers := m.K8sVersions, m.Providers
+	if len(k8sVersions) == 0 {
+		k8sVersions = []string{""}
+	}
+	if len(providers) == 0 {
+		providers = []string{getenv("PROVIDER")}
+	}
+
+	var runs []matrixRun
+	seen := map[string]bool{}
+	for _, name := range m.Suites {
+		for _, provider := range providers {
+			suites, err := parseSuites(lines, func(key string) string {
+				if key == "PROVIDER" {
+					return provider
+				}
+				if value, ok := m.Env[key]; ok {
+					return value
+				}
+				return getenv(key)
+			})
+			if err != nil {
+				return nil, err
+			}
+			s, ok := findSuite(suites, name)
+			if !ok {
+				return nil, fmt.Errorf("suite %q of the matrix not found; run with --list to list the suites", name)
+			}
+			regions := m.Regions[provider]
+			if s.multiProvider() {
+				provider, regions = "", nil
+			}
+			if len(regions) == 0 {
+				regions = []string{""}
+			}
+
+			for _, k8sVersion := range k8sVersions {
+				for _, region := range regions {
+					run := matrixRun{Provider: provider, Suite: s.Name, K8sVersion: k8sVersion, Region: region, suite: s}
+					if seen[run.name()] || m.excluded(run) {
+						continue
+					}
+					seen[run.name()] = true
+					if run.env, err = m.runEnv(run); err != nil {
+						return nil, err
+					}
+					runs = append(runs, run)
+				}
+			}
+		}
+	}
+	return runs, nil
+}
+
+// runEnv returns the env vars of the run: the ones of the matrix, along with its provider, k8s version and region
+func (m *matrix) runEnv(run matrixRun) (map[string]string, error) {
+	env := map[string]string{}
+	for key, value := range m.Env {
+		env[key] = value
+	}
+	if run.Provider != "" {
+		env["PROVIDER"] = run.Provider
+	}
+	if run.K8sVersion != "" {
+		env["DOWNSTREAM_K8S_MINOR_VERSION"] = run.K8sVersion
+	}
+	if run.Region != "" {
+		key, err := regionEnv(run.Provider, run.Region)
+		if err != nil {
+			return nil, err
+		}
+		env[key] = run.Region
+	}
+	if m.Cleanup != nil {
+		env["DOWNSTREAM_CLUSTER_CLEANUP"] = strconv.FormatBool(*m.Cleanup)
+	}
+	return env, nil
+}
+
+// name returns the name of the run, for e.g. eks-p1-import-1.30-ap-south-1; it is also the name of its directory in the output directory
+func (r matrixRun) name() string {
+	parts := []string{r.Provider, r.Suite, r.K8sVersion, r.Region}
+	var name []string
+	for _, part := range parts {
+		if part != "" {
+			name = append(name, part)
+		}
+	}
+	return runNameRegexp.ReplaceAllString(strings.Join(name, "-"), "_")
+}
+
+/*
+isolate gives the run a directory of the output directory: its artifacts are written to it, and the cattle config is copied to it
+since the suites write the credentials and the admin token to the cattle config, so that the parallel runs do not overwrite each other.
+  - @param dir Directory of the run
+  - @param getenv Reads the env, for e.g. os.Getenv
+  - @returns The error of the copy of the cattle config
+*/
+func (r *matrixRun) isolate(dir string, getenv func(string) string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	r.env["ARTIFACTS_DIR"] = filepath.Join(dir, "artifacts")
+
+	cattleConfig := r.env["CATTLE_TEST_CONFIG"]
+	if cattleConfig == "" {
+		cattleConfig = getenv("CATTLE_TEST_CONFIG")
+	}
+	if cattleConfig == "" {
+		// reported by the preflight checks
+		return nil
+	}
+	if !filepath.IsAbs(cattleConfig) {
+		// ginkgo runs the suites from their directory
+		cattleConfig = filepath.Join(r.suite.Dir, cattleConfig)
+	}
+	copied := filepath.Join(dir, filepath.Base(cattleConfig))
+	r.env["CATTLE_TEST_CONFIG"] = copied
+	content, err := os.ReadFile(cattleConfig)
+	if errors.Is(err, fs.ErrNotExist) {
+		// the suite generates the config of the run, see helpers.GenerateCattleConfig
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to copy CATTLE_TEST_CONFIG of run %s: %w", r.name(), err)
+	}
+	return os.WriteFile(copied, content, 0o600)
+}
+
+// envList returns the env vars of the run as KEY=value, sorted
+func (r matrixRun) envList() []string {
+	var env []string
+	for key, value := range r.env {
+		env = append(env, key+"="+value)
+	}
+	sort.Strings(env)
+	return env
+}
+
+// withEnv calls f with the env vars set in the env of the process, then restores them
+func withEnv(env map[string]string, f func()) {
+	previous := map[string]*string{}
+	for key, value := range env {
+		if old, found := os.LookupEnv(key); found {
+			previous[key] = &old
+		} else {
+			previous[key] = nil
+		}
+		os.Setenv(key, value)
+	}
+	defer func() {
+		for key, value := range previous {
+			if value == nil {
+				os.Unsetenv(key)
+			} else {
+				os.Setenv(key, *value)
+			}
+		}
+	}()
+	f()
+}
+
+// runMatrix runs the suites of the matrix of the options, once the preflight checks of all of them passed, and returns the exit code of the command:
+// 1 if a run failed, 0 otherwise
+func runMatrix(opts *options, lines []string) int {
+	m, err := loadMatrix(opts.matrix)
+	if err != nil {
+		fail(err)
+	}
+	runs, err := m.expand(lines, os.Getenv)
+	if err != nil {
+		fail(err)
+	}
+	if len(runs) == 0 {
+		fail(fmt.Errorf("every run of the matrix %s is excluded", opts.matrix))
+	}
+	parallel := m.Parallel
+	if opts.parallel > 0 {
+		parallel = opts.parallel
+	}
+	if parallel == 0 {
+		parallel = 1
+	}
+	output, err := filepath.Abs(opts.output)
+	if err != nil {
+		fail(err)
+	}
+
+	var problems []string
+	for i := range runs {
+		if err = runs[i].isolate(filepath.Join(output, runs[i].name()), os.Getenv); err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		withEnv(runs[i].env, func() {
+			for _, problem := range preflight(runs[i].suite, runs[i].Provider) {
+				problems = append(problems, runs[i].name()+": "+problem)
+			}
+		})
+	}
+	if len(problems) > 0 {
+		fail(fmt.Errorf("preflight checks of the matrix failed:\n - %s", strings.Join(problems, "\n - ")))
+	}
+
+	fmt.Printf("Running %d suites, %d at a time; the results are written to %s\n", len(runs), parallel, output)
+	results := make([]matrixResult, len(runs))
+	slots := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, run := range runs {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i] = runMatrixSuite(run, filepath.Join(output, run.name()), m.Labels, parallel == 1)
+			fmt.Printf("%s: %s\n", results[i].Name, results[i].status())
+		}()
+	}
+	wg.Wait()
+
+	if err = writeMatrixResults(filepath.Join(output, matrixResultsFile), results); err != nil {
+		fail(err)
+	}
+	printMatrixResults(os.Stdout, results)
+	for _, result := range results {
+		if result.ExitCode != 0 || result.Error != "" {
+			return 1
+		}
+	}
+	return 0
+}
+
+// runMatrixSuite runs the suite of the run, writing the output of ginkgo to ginkgo.log and its JSON report to report.json in the directory of the run;
+// the output is streamed too if the runs are sequential
+func runMatrixSuite(run matrixRun, dir, labels string, stream bool) matrixResult {
+	result := matrixResult{Name: run.name(), matrixRun: run}
+	log, err := os.Create(filepath.Join(dir, "ginkgo.log"))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer log.Close()
+	var output io.Writer = log
+	if stream {
+		output = io.MultiWriter(os.Stdout, log)
+	}
+
+	start := time.Now()
+	result.ExitCode, err = runGinkgo(run.suite, labels, run.envList(), output, "--output-dir="+dir, "--json-report=report.json")
+	result.Duration = time.Since(start).Round(time.Second).String()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if err = result.readReport(filepath.Join(dir, "report.json")); err != nil && result.ExitCode == 0 {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// readReport counts the specs of the ginkgo JSON report by state; the suite nodes are not counted
+func (r *matrixResult) readReport(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var reports []types.Report
+	if err = json.Unmarshal(content, &reports); err != nil {
+		return fmt.Errorf("report %s is not valid: %w", path, err)
+	}
+	for _, report := range reports {
+		specs := report.SpecReports.WithLeafNodeType(types.NodeTypeIt)
+		r.Passed += specs.CountWithState(types.SpecStatePassed)
+		r.Failed += specs.CountWithState(types.SpecStateFailureStates)
+		r.Skipped += specs.CountWithState(types.SpecStateSkipped | types.SpecStatePending)
+		r.Flaked += specs.CountOfFlakedSpecs()
+	}
+	return nil
+}
+
+// status returns the status of the run: passed, failed with the exit code of ginkgo, or the error it could not be run with
+func (r matrixResult) status() string {
+	switch {
+	case r.Error != "":
+		return "error: " + r.Error
+	case r.ExitCode != 0:
+		return fmt.Sprintf("failed (exit code %d)", r.ExitCode)
+	}
+	return "passed"
+}
+
+// writeMatrixResults writes the results of the runs as JSON
+func writeMatrixResults(path string, results []matrixResult) error {
+	content, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o644)
+}
+
+// printMatrixResults prints the results of the runs as a table
+func printMatrixResults(w io.Writer, results []matrixResult) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "RUN\tSTATUS\tPASSED\tFAILED\tSKIPPED\tFLAKED\tDURATION")
+	for _, r := range results {
+		fmt.Fprintf(table, "%s\t%s\t%d\t%d\t%d\t%d\t%s\n", r.Name, r.status(), r.Passed, r.Failed, r.Skipped, r.Flaked, r.Duration)
+	}
+	table.Flush()
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// testMakefile are the lines of a Makefile with a suite by provider and a multi-provider suite
+var testMakefile = strings.Split(`FLAKE_ATTEMPTS ?= 1
+e2e-p1-import-tests: deps ## Run the 'P1Import' test suite for a given ${PROVIDER}
+	ginkgo -v --flake-attempts=${FLAKE_ATTEMPTS} --focus "P1Import" ./hosted/${PROVIDER}/p1/
+e2e-multi-provider-concurrent-tests: deps ## Run the 'MultiProviderConcurrent' test suite; PROVIDER is not used
+	ginkgo -v --focus "MultiProviderConcurrent" ./hosted/multiprovider/concurrent/
+`, "\n")
+
+func writeMatrix(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "matrix.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMatrixExpand(t *testing.T) {
+	m, err := loadMatrix(writeMatrix(t, `
+providers: [eks, gke]
+suites: [P1Import, multi-provider-concurrent]
+k8sVersions: ["1.30", "1.31"]
+regions:
+  eks: [ap-south-1, us-west-2]
+  gke: [asia-south2-c]
+exclude:
+  - provider: gke
+    k8sVersion: "1.31"
+  - suite: e2e-multi-provider-concurrent-tests
+    k8sVersion: "1.30"
+cleanup: true
+env:
+  FLAKE_ATTEMPTS: "2"
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	runs, err := m.expand(testMakefile, func(string) string { return "" })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+	for _, run := range runs {
+		names = append(names, run.name())
+	}
+	want := []string{
+		"eks-p1-import-1.30-ap-south-1", "eks-p1-import-1.30-us-west-2", "eks-p1-import-1.31-ap-south-1", "eks-p1-import-1.31-us-west-2",
+		"gke-p1-import-1.30-asia-south2-c", "multi-provider-concurrent-1.31",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("got runs %v, want %v", names, want)
+	}
+
+	gke := runs[4]
+	if gke.suite.Dir != "./hosted/gke/p1/" || !reflect.DeepEqual(gke.suite.Args, []string{"-v", "--flake-attempts=2", "--focus", "P1Import"}) {
+		t.Errorf("got suite %+v", gke.suite)
+	}
+	wantEnv := map[string]string{"PROVIDER": "gke", "DOWNSTREAM_K8S_MINOR_VERSION": "1.30", "GKE_ZONE": "asia-south2-c",
+		"DOWNSTREAM_CLUSTER_CLEANUP": "true", "FLAKE_ATTEMPTS": "2"}
+	if !reflect.DeepEqual(gke.env, wantEnv) {
+		t.Errorf("got env %v, want %v", gke.env, wantEnv)
+	}
+	if _, found := runs[5].env["PROVIDER"]; found {
+		t.Errorf("the multi-provider run must not set PROVIDER: %v", runs[5].env)
+	}
+}
+
+func TestLoadMatrixErrors(t *testing.T) {
+	for name, content := range map[string]string{
+		"no suite":      "providers: [eks]\n",
+		"unknown field": "suites: [p1-import]\nregion: ap-south-1\n",
+		"parallel":      "suites: [p1-import]\nparallel: -1\n",
+	} {
+		if _, err := loadMatrix(writeMatrix(t, content)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	m := &matrix{Providers: []string{"eks"}, Suites: []string{"P2Scale"}}
+	if _, err := m.expand(testMakefile, os.Getenv); err == nil {
+		t.Error("expected an error for an unknown suite")
+	}
+	m = &matrix{Providers: []string{"rke2"}, Suites: []string{"p1-import"}, Regions: map[string][]string{"rke2": {"us-east-1"}}}
+	if _, err := m.expand(testMakefile, os.Getenv); err == nil {
+		t.Error("expected an error for the region of an unknown provider")
+	}
+}
+
+func TestMatrixRunIsolate(t *testing.T) {
+	suiteDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(suiteDir, "cattle-config.yaml"), []byte("rancher: {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run := matrixRun{Provider: "eks", Suite: "p1-import", suite: suite{Dir: suiteDir}, env: map[string]string{}}
+	dir := filepath.Join(t.TempDir(), run.name())
+
+	if err := run.isolate(dir, testEnv{"CATTLE_TEST_CONFIG": "cattle-config.yaml"}.get); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if run.env["ARTIFACTS_DIR"] != filepath.Join(dir, "artifacts") || run.env["CATTLE_TEST_CONFIG"] != filepath.Join(dir, "cattle-config.yaml") {
+		t.Errorf("got env %v", run.env)
+	}
+	if content, err := os.ReadFile(run.env["CATTLE_TEST_CONFIG"]); err != nil || string(content) != "rancher: {}\n" {
+		t.Errorf("the cattle config is not copied: %q, %v", content, err)
+	}
+
+	// a missing cattle config is generated by the suite in the directory of the run
+	run.env = map[string]string{"CATTLE_TEST_CONFIG": "missing.yaml"}
+	if err := run.isolate(dir, os.Getenv); err != nil {
+		t.Errorf("unexpected error for a missing cattle config: %v", err)
+	}
+	if run.env["CATTLE_TEST_CONFIG"] != filepath.Join(dir, "missing.yaml") {
+		t.Errorf("got env %v", run.env)
+	}
+}
+
+func TestWithEnv(t *testing.T) {
+	t.Setenv("HPE2E_SET", "before")
+	os.Unsetenv("HPE2E_UNSET")
+	withEnv(map[string]string{"HPE2E_SET": "during", "HPE2E_UNSET": "during"}, func() {
+		if os.Getenv("HPE2E_SET") != "during" || os.Getenv("HPE2E_UNSET") != "during" {
+			t.Error("the env is not set")
+		}
+	})
+	if _, found := os.LookupEnv("HPE2E_UNSET"); found || os.Getenv("HPE2E_SET") != "before" {
+		t.Error("the env is not restored")
+	}
+}
+
+func TestMatrixResults(t *testing.T) {
+	dir := t.TempDir()
+	specs := types.SpecReports{
+		{LeafNodeType: types.NodeTypeSynchronizedBeforeSuite, State: types.SpecStatePassed},
+		{LeafNodeType: types.NodeTypeIt, State: types.SpecStatePassed},
+		{LeafNodeType: types.NodeTypeIt, State: types.SpecStatePassed, NumAttempts: 2, MaxFlakeAttempts: 2},
+		{LeafNodeType: types.NodeTypeIt, State: types.SpecStateFailed},
+		{LeafNodeType: types.NodeTypeIt, State: types.SpecStateTimedout},
+		{LeafNodeType: types.NodeTypeIt, State: types.SpecStateSkipped},
+	}
+	content, err := json.Marshal([]types.Report{{SpecReports: specs}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, "report.json"), content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := matrixResult{Name: "eks-p1-import", ExitCode: 1, Duration: "1h2m0s"}
+	if err = result.readReport(filepath.Join(dir, "report.json")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Passed != 2 || result.Failed != 2 || result.Skipped != 1 || result.Flaked != 1 {
+		t.Errorf("got %+v", result)
+	}
+	if err = result.readReport(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected an error for a missing report")
+	}
+
+	var table bytes.Buffer
+	printMatrixResults(&table, []matrixResult{result, {Name: "gke-p1-import", Error: "ginkgo not found"}})
+	for _, want := range []string{"eks-p1-import  failed (exit code 1)", "gke-p1-import  error: ginkgo not found"} {
+		if !strings.Contains(table.String(), want) {
+			t.Errorf("%q not in the results:\n%s", want, table.String())
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var (
+	// makeVariableRegexp matches the variables of the Makefile, for e.g. PROVISIONING_PROCS ?= 2
+	makeVariableRegexp = regexp.MustCompile(`^([A-Z0-9_]+)\s*(\?=|:=|=)\s*(.*)$`)
+	// makeTargetRegexp matches the e2e targets of the Makefile along with their help, for e.g. e2e-import-tests: deps ## Run the 'P0Import' ...
+	makeTargetRegexp = regexp.MustCompile(`^(e2e-[a-z0-9-]+):[^#]*##\s*(.*)$`)
+	// makeReferenceRegexp matches the references to the variables in the Makefile, for e.g. ${PROVIDER}
+	makeReferenceRegexp = regexp.MustCompile(`\$[{(]([A-Z0-9_]+)[})]`)
+)
+
+// suite is a ginkgo suite run by an e2e target of the Makefile; the Makefile stays the only place the suites and their ginkgo options are defined
+type suite struct {
+	// Name is the name of the target without the e2e- prefix and the -tests suffix, for e.g. p1-import or k8s-chart-support-import-upgrade
+	Name   string
+	Target string
+	// Help is the help of the target, as printed by make help
+	Help string
+	// Args are the arguments of ginkgo, the directory of the suite excluded, with the variables of the Makefile and of the env expanded
+	Args []string
+	// Focus is the ginkgo --focus of the target, for e.g. P0Import
+	Focus string
+	// Dir is the directory of the suite, for e.g. ./hosted/eks/p0/
+	Dir string
+}
+
+// makeVariable is a variable of the Makefile; unlike the other ones, the variables set with ?= are overridden by the env, for e.g. FLAKE_ATTEMPTS
+type makeVariable struct {
+	value       string
+	conditional bool
+}
+
+// readMakefile returns the lines of the Makefile, the suites are parsed from with parseSuites
+func readMakefile(makefile string) ([]string, error) {
+	file, err := os.Open(makefile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// parseSuites returns the suites of the e2e targets of the Makefile lines; their ginkgo command is expanded like make does,
+// the variables not set by the Makefile, for e.g. PROVIDER, being read with getenv.
+func parseSuites(lines []string, getenv func(string) string) ([]suite, error) {
+	variables := map[string]makeVariable{}
+	var expand func(value string, depth int) string
+	expand = func(value string, depth int) string {
+		return makeReferenceRegexp.ReplaceAllStringFunc(value, func(reference string) string {
+			name := makeReferenceRegexp.FindStringSubmatch(reference)[1]
+			variable, found := variables[name]
+			if env := getenv(name); env != "" && (!found || variable.conditional) {
+				return env
+			}
+			if !found || depth > 10 {
+				return ""
+			}
+			return expand(variable.value, depth+1)
+		})
+	}
+
+	var suites []suite
+	for i, line := range lines {
+		if match := makeVariableRegexp.FindStringSubmatch(line); match != nil {
+			variables[match[1]] = makeVariable{value: strings.TrimSpace(match[3]), conditional: match[2] == "?="}
+			continue
+		}
+		match := makeTargetRegexp.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		if i+1 == len(lines) || !strings.HasPrefix(strings.TrimSpace(lines[i+1]), "ginkgo ") {
+			return nil, fmt.Errorf("target %s does not run ginkgo", match[1])
+		}
+		args, err := splitArgs(expand(strings.TrimSpace(lines[i+1]), 0))
+		if err != nil {
+			return nil, fmt.Errorf("target %s: %w", match[1], err)
+		}
+		s := suite{
+			Name:   strings.Replace(strings.TrimPrefix(match[1], "e2e-"), "-tests", "", 1),
+			Target: match[1],
+			Help:   strings.TrimSpace(match[2]),
+			Args:   args[1 : len(args)-1],
+			Dir:    args[len(args)-1],
+		}
+		for j, arg := range s.Args {
+			if arg == "--focus" && j+1 < len(s.Args) {
+				s.Focus = s.Args[j+1]
+			}
+		}
+		suites = append(suites, s)
+	}
+	return suites, nil
+}
+
+// splitArgs splits the command line of a target into its arguments; the double quotes group the words, for e.g. --focus "P0Import"
+func splitArgs(command string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	quoted, inArg := false, false
+	for _, r := range command {
+		switch {
+		case r == '"':
+			quoted, inArg = !quoted, true
+		case (r == ' ' || r == '\t') && !quoted:
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in %q", command)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	if len(args) < 2 {
+		return nil, fmt.Errorf("no suite directory in %q", command)
+	}
+	return args, nil
+}
+
+// findSuite returns the suite of the name, see suite.is
+func findSuite(suites []suite, name string) (suite, bool) {
+	for _, s := range suites {
+		if s.is(name) {
+			return s, true
+		}
+	}
+	return suite{}, false
+}
+
+// is returns true if the name is the name, the make target or the ginkgo focus of the suite, for e.g. p1-import, e2e-p1-import-tests or P1Import
+func (s suite) is(name string) bool {
+	return s.Name == name || s.Target == name || strings.EqualFold(s.Focus, name)
+}
+
+// multiProvider returns true for the suites provisioning the clusters of all the helpers.MultiProviders, which do not use PROVIDER
+func (s suite) multiProvider() bool {
+	return strings.Contains(s.Dir, "/multiprovider/")
+}
+
+// runConfigSuite returns the suite the run config is validated for, the one the suite passes to helpers.ValidateRunConfig
+func (s suite) runConfigSuite() helpers.Suite {
+	if s.multiProvider() {
+		if s.Focus == "MultiProviderDisasterRecovery" {
+			return helpers.SuiteMultiProviderBackupRestore
+		}
+		return helpers.SuiteMultiProvider
+	}
+	switch filepath.Base(s.Dir) {
+	case "upgrade":
+		return helpers.SuiteUpgrade
+	case "backup_restore", "reinstall":
+		return helpers.SuiteBackupRestore
+	case "chaos", "kontainer_driver":
+		return helpers.SuiteChaos
+	case "airgap":
+		return helpers.SuiteAirgap
+	case "soak":
+		return helpers.SuiteSoak
+	case "churn":
+		return helpers.SuiteChurn
+	case "compatibility":
+		return helpers.SuiteCompatibility
+	}
+	return helpers.SuiteCommon
+}
+
+// providers returns the providers of the suite: helpers.MultiProviders for the multi-provider suites, the provider otherwise
+func (s suite) providers(provider string) []string {
+	if s.multiProvider() {
+		return helpers.MultiProviders
+	}
+	return []string{provider}
+}
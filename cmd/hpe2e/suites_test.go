@@ -0,0 +1,111 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+func TestParseSuites(t *testing.T) {
+	makefile := strings.Split(`FLAKE_ATTEMPTS ?= 1
+STANDARD_TEST_OPTIONS = -v -r --flake-attempts=${FLAKE_ATTEMPTS}
+PROVISIONING_PROCS ?= 2
+
+prepare-rancher: deps ## Install Rancher
+	ginkgo --label-filter install -v ./
+
+e2e-import-tests: deps	## Run the 'P0Import' test suite for a given ${PROVIDER}
+	ginkgo ${STANDARD_TEST_OPTIONS} --procs=${PROVISIONING_PROCS} --focus "P0Import" ./hosted/${PROVIDER}/p0/
+
+e2e-k8s-chart-support-import-tests-upgrade: deps ## Run the 'K8sChartSupportUpgradeImport' test suite
+	ginkgo ${STANDARD_TEST_OPTIONS} --focus "K8sChartSupportUpgradeImport" ./hosted/${PROVIDER}/k8s_chart_support/upgrade
+`, "\n")
+	env := testEnv{"PROVIDER": "gke", "FLAKE_ATTEMPTS": "3", "STANDARD_TEST_OPTIONS": "ignored"}
+
+	suites, err := parseSuites(makefile, env.get)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []suite{
+		{Name: "import", Target: "e2e-import-tests", Help: "Run the 'P0Import' test suite for a given ${PROVIDER}", Focus: "P0Import", Dir: "./hosted/gke/p0/",
+			Args: []string{"-v", "-r", "--flake-attempts=3", "--procs=2", "--focus", "P0Import"}},
+		{Name: "k8s-chart-support-import-upgrade", Target: "e2e-k8s-chart-support-import-tests-upgrade", Help: "Run the 'K8sChartSupportUpgradeImport' test suite",
+			Focus: "K8sChartSupportUpgradeImport", Dir: "./hosted/gke/k8s_chart_support/upgrade", Args: []string{"-v", "-r", "--flake-attempts=3", "--focus", "K8sChartSupportUpgradeImport"}},
+	}
+	if !reflect.DeepEqual(suites, want) {
+		t.Errorf("got %+v,\nwant %+v", suites, want)
+	}
+
+	for _, name := range []string{"import", "e2e-import-tests", "p0import"} {
+		if s, found := findSuite(suites, name); !found || s.Target != "e2e-import-tests" {
+			t.Errorf("findSuite(%s) = %v, %t", name, s.Target, found)
+		}
+	}
+	if _, found := findSuite(suites, "P1Import"); found {
+		t.Error("P1Import must not be found")
+	}
+
+	if _, err = parseSuites([]string{"e2e-broken-tests: deps ## Broken", "\tginkgo --focus \"Broken ./hosted/eks/p0"}, env.get); err == nil {
+		t.Error("expected an error for an unterminated quote")
+	}
+}
+
+// testEnv is the env of the tests, read with get like os.Getenv
+type testEnv map[string]string
+
+func (e testEnv) get(key string) string {
+	return e[key]
+}
+
+// TestMakefileSuites checks the suites of the Makefile of the repository, so that a new target is run by hpe2e with the right run config
+func TestMakefileSuites(t *testing.T) {
+	for _, provider := range []string{"eks", "gke", "aks"} {
+		t.Setenv("PROVIDER", provider)
+		lines, err := readMakefile("../../Makefile")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		suites, err := parseSuites(lines, os.Getenv)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(suites) < 40 {
+			t.Fatalf("got %d suites, the e2e targets of the Makefile are not all parsed", len(suites))
+		}
+
+		names := map[string]bool{}
+		for _, s := range suites {
+			if names[s.Name] {
+				t.Errorf("suite %s is defined twice", s.Name)
+			}
+			names[s.Name] = true
+			if s.Focus == "" {
+				t.Errorf("suite %s has no focus", s.Name)
+			}
+			if _, err = os.Stat("../../" + s.Dir); err != nil && !(s.Name == "location-matrix" && provider == "aks") {
+				t.Errorf("suite %s: %v", s.Name, err)
+			}
+		}
+
+		for name, want := range map[string]helpers.Suite{
+			"p1-import":                        helpers.SuiteCommon,
+			"k8s-chart-support-import-upgrade": helpers.SuiteUpgrade,
+			"reinstall-adoption-import":        helpers.SuiteBackupRestore,
+			"kontainer-driver":                 helpers.SuiteChaos,
+			"k8s-chart-support-airgap-import":  helpers.SuiteAirgap,
+			"drift-soak":                       helpers.SuiteSoak,
+			"multi-provider-concurrent":        helpers.SuiteMultiProvider,
+			"multi-provider-disaster-recovery": helpers.SuiteMultiProviderBackupRestore,
+		} {
+			if s, _ := findSuite(suites, name); s.runConfigSuite() != want {
+				t.Errorf("suite %s is validated for %s, want %s", name, s.runConfigSuite(), want)
+			}
+		}
+		if s, _ := findSuite(suites, "multi-provider-concurrent"); !reflect.DeepEqual(s.providers(provider), helpers.MultiProviders) {
+			t.Errorf("got providers %v for the multi-provider suite", s.providers(provider))
+		}
+	}
+}
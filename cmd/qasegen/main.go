@@ -0,0 +1,332 @@
+// Command qasegen generates the package hosted/helpers/qasecases from the test cases of the Qase project: a CaseID constant per case, grouped by
+// provider and priority according to the suites of the project (for e.g. the cases of the suite EKS > P1 are named EKSP1...), so that the specs
+// reference their cases with helpers.QaseLabel(qasecases.EKSP1...) and a retired or mistyped case ID fails to compile, for e.g.
+//
+//	QASE_API_TOKEN=<token> QASE_PROJECT_CODE=HP go run ./cmd/qasegen --output hosted/helpers/qasecases/cases_gen.go
+//
+// The deleted cases are left out and the deprecated ones are marked as such. With --cases, the cases are read from a JSON export of the project
+// instead, {"suites": [...], "cases": [...]} as returned by the suite and case APIs of Qase. The specs of --specs referencing the case of another
+// suite than their own, for e.g. a P1 spec reported to a P0 case, are printed as warnings, so that they get a case of their own in Qase.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/antihax/optional"
+	qase "go.qase.io/client"
+)
+
+const (
+	// pageSize is the number of suites or cases listed per request
+	pageSize = 100
+	// statusDeprecated is the status of the deprecated cases of Qase
+	statusDeprecated = 2
+	// maxNameWords is the number of words of the title of a case kept in the name of its constant
+	maxNameWords = 8
+)
+
+var (
+	// wordRegexp matches the words of the titles, the characters that are not letters or digits are dropped
+	wordRegexp = regexp.MustCompile(`[\p{L}\p{N}]+`)
+	// referenceRegexp matches the references of the specs to the constants
+	referenceRegexp = regexp.MustCompile(`qasecases\.([A-Za-z0-9_]+)`)
+	// fillerWords are the words of the titles left out of the names of the constants, for e.g. "should successfully"
+	fillerWords = map[string]bool{"should": true, "successfully": true, "be": true, "able": true, "to": true, "the": true, "a": true, "an": true}
+)
+
+// project is the suites and the cases of a Qase project
+type project struct {
+	Suites []qase.Suite    `json:"suites"`
+	Cases  []qase.TestCase `json:"cases"`
+}
+
+// constant is the constant generated for a case
+type constant struct {
+	Name       string
+	ID         int64
+	Title      string
+	Group      string
+	Deprecated bool
+}
+
+func main() {
+	output := flag.String("output", "hosted/helpers/qasecases/cases_gen.go", "file the constants are written to")
+	casesFile := flag.String("cases", "", "JSON export of the suites and cases of the project, read instead of the Qase API")
+	projectCode := flag.String("project", os.Getenv("QASE_PROJECT_CODE"), "code of the Qase project; QASE_PROJECT_CODE")
+	specs := flag.String("specs", "hosted", "directory of the suites whose references to the cases of other suites are reported, none if empty")
+	flag.Parse()
+
+	var p *project
+	var err error
+	if *casesFile != "" {
+		p, err = readProject(*casesFile)
+	} else {
+		p, err = fetchProject(*projectCode, os.Getenv("QASE_API_TOKEN"))
+	}
+	if err != nil {
+		fail(err)
+	}
+	source, err := generate(p)
+	if err != nil {
+		fail(err)
+	}
+	if err = os.WriteFile(*output, source, 0o644); err != nil {
+		fail(err)
+	}
+	fmt.Printf("Wrote the constants of %d cases to %s\n", len(p.Cases), *output)
+
+	if *specs == "" {
+		return
+	}
+	warnings, err := mismatches(constants(p), *specs)
+	if err != nil {
+		fail(err)
+	}
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, "qasegen: warning:", warning)
+	}
+}
+
+// readProject reads the JSON export of a project
+func readProject(path string) (*project, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := &project{}
+	if err = json.Unmarshal(content, p); err != nil {
+		return nil, fmt.Errorf("%s is not a valid export of the cases: %w", path, err)
+	}
+	return p, nil
+}
+
+// fetchProject lists the suites and the cases of the project with the Qase API
+func fetchProject(code, token string) (*project, error) {
+	if code == "" || token == "" {
+		return nil, errors.New("QASE_API_TOKEN and QASE_PROJECT_CODE are required, or --cases")
+	}
+	cfg := qase.NewConfiguration()
+	cfg.AddDefaultHeader("Token", token)
+	client := qase.NewAPIClient(cfg)
+
+	p := &project{}
+	for offset := int32(0); ; offset += pageSize {
+		response, _, err := client.SuitesApi.GetSuites(context.TODO(), code, &qase.SuitesApiGetSuitesOpts{
+			Limit:  optional.NewInt32(pageSize),
+			Offset: optional.NewInt32(offset),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the suites of the Qase project %s: %w", code, err)
+		}
+		if response.Result == nil {
+			break
+		}
+		p.Suites = append(p.Suites, response.Result.Entities...)
+		if len(response.Result.Entities) < pageSize {
+			break
+		}
+	}
+	for offset := int32(0); ; offset += pageSize {
+		response, _, err := client.CasesApi.GetCases(context.TODO(), code, &qase.CasesApiGetCasesOpts{
+			Limit:  optional.NewInt32(pageSize),
+			Offset: optional.NewInt32(offset),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the cases of the Qase project %s: %w", code, err)
+		}
+		if response.Result == nil {
+			break
+		}
+		p.Cases = append(p.Cases, response.Result.Entities...)
+		if len(response.Result.Entities) < pageSize {
+			break
+		}
+	}
+	if len(p.Cases) == 0 {
+		return nil, fmt.Errorf("the Qase project %s has no case", code)
+	}
+	return p, nil
+}
+
+// suitePaths returns the path of every suite by ID, the titles of its parents and its own, for e.g. [EKS P1]
+func suitePaths(suites []qase.Suite) map[int64][]string {
+	byID := map[int64]qase.Suite{}
+	for _, suite := range suites {
+		byID[suite.Id] = suite
+	}
+	paths := map[int64][]string{}
+	for _, suite := range suites {
+		var path []string
+		seen := map[int64]bool{}
+		for id := suite.Id; id != 0 && !seen[id]; id = byID[id].ParentId {
+			seen[id] = true
+			parent, ok := byID[id]
+			if !ok {
+				break
+			}
+			path = append([]string{parent.Title}, path...)
+		}
+		paths[suite.Id] = path
+	}
+	return paths
+}
+
+// identifier returns the words as an exported Go identifier, for e.g. [add NP from azure] is AddNPFromAzure; the filler words are dropped
+// if filler is true, and at most maxWords words are kept if positive
+func identifier(text string, filler bool, maxWords int) string {
+	var name strings.Builder
+	kept := 0
+	for _, word := range wordRegexp.FindAllString(text, -1) {
+		if filler && fillerWords[strings.ToLower(word)] {
+			continue
+		}
+		if maxWords > 0 && kept == maxWords {
+			break
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		name.WriteString(string(runes))
+		kept++
+	}
+	return name.String()
+}
+
+// constants returns the constants of the cases which are not deleted, sorted by group and ID; the names of the cases of a group with the
+// same title get the ID of the case as suffix
+func constants(p *project) []constant {
+	paths := suitePaths(p.Suites)
+	var constants []constant
+	for _, c := range p.Cases {
+		if !c.Deleted.IsZero() {
+			continue
+		}
+		path := paths[c.SuiteId]
+		prefix := identifier(strings.Join(path, " "), false, 0)
+		if prefix == "" {
+			prefix = "Case"
+		} else if unicode.IsDigit([]rune(prefix)[0]) {
+			prefix = "Case" + prefix
+		}
+		constants = append(constants, constant{
+			Name:       prefix + identifier(c.Title, true, maxNameWords),
+			ID:         c.Id,
+			Title:      strings.Join(strings.Fields(c.Title), " "),
+			Group:      strings.Join(path, " > "),
+			Deprecated: c.Status == statusDeprecated,
+		})
+	}
+
+	names := map[string]int{}
+	for _, c := range constants {
+		names[c.Name]++
+	}
+	for i := range constants {
+		if names[constants[i].Name] > 1 || constants[i].Name == "Case" {
+			constants[i].Name = fmt.Sprintf("%s%d", constants[i].Name, constants[i].ID)
+		}
+	}
+	sort.SliceStable(constants, func(i, j int) bool {
+		if constants[i].Group != constants[j].Group {
+			return constants[i].Group < constants[j].Group
+		}
+		return constants[i].ID < constants[j].ID
+	})
+	return constants
+}
+
+// generate returns the source of the constants of the cases, one const block per group
+func generate(p *project) ([]byte, error) {
+	var source bytes.Buffer
+	fmt.Fprintf(&source, "// Code generated by qasegen; DO NOT EDIT.\n\npackage qasecases\n")
+	group := "\x00"
+	for _, c := range constants(p) {
+		if c.Group != group {
+			if group != "\x00" {
+				source.WriteString(")\n")
+			}
+			group = c.Group
+			title := group
+			if title == "" {
+				title = "Cases without suite"
+			}
+			fmt.Fprintf(&source, "\n// %s\nconst (\n", title)
+		}
+		fmt.Fprintf(&source, "\t// %s is %q\n", c.Name, c.Title)
+		if c.Deprecated {
+			source.WriteString("\t//\n\t// Deprecated: the case is deprecated in Qase.\n")
+		}
+		fmt.Fprintf(&source, "\t%s CaseID = %d\n", c.Name, c.ID)
+	}
+	if group != "\x00" {
+		source.WriteString(")\n")
+	}
+	return format.Source(source.Bytes())
+}
+
+/*
+mismatches returns the references of the specs to the cases of another suite than their own, for e.g. a spec of hosted/aks/p1 reporting to
+a case of AKS > P0: the suite of a spec is the path of its directory, which matches the suites of the project, and the shared helpers are left out.
+  - @param constants Constants of the cases
+  - @param specsDir Directory of the suites, for e.g. hosted
+  - @returns A warning per reference to the case of another suite, with its position
+*/
+func mismatches(constants []constant, specsDir string) ([]string, error) {
+	byName := map[string]constant{}
+	for _, c := range constants {
+		byName[c.Name] = c
+	}
+	var warnings []string
+	err := filepath.WalkDir(specsDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if entry.Name() == "helpers" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		dir, err := filepath.Rel(specsDir, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		suite := identifier(dir, false, 0)
+		for i, line := range strings.Split(string(content), "\n") {
+			for _, reference := range referenceRegexp.FindAllStringSubmatch(line, -1) {
+				c, ok := byName[reference[1]]
+				if !ok || strings.EqualFold(identifier(c.Group, false, 0), suite) {
+					continue
+				}
+				warnings = append(warnings, fmt.Sprintf("%s:%d references %s (%d), a case of %s, from a spec of %s; give the spec a case of its own",
+					path, i+1, c.Name, c.ID, c.Group, filepath.ToSlash(dir)))
+			}
+		}
+		return nil
+	})
+	return warnings, err
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "qasegen:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	qase "go.qase.io/client"
+)
+
+func TestIdentifier(t *testing.T) {
+	for _, tc := range []struct {
+		text     string
+		filler   bool
+		maxWords int
+		want     string
+	}{
+		{text: "EKS P1", want: "EKSP1"},
+		{text: "should successfully Add NP from Azure and then from Rancher", filler: true, want: "AddNPFromAzureAndThenFromRancher"},
+		{text: "should be able to upgrade k8s version of the cluster", filler: true, want: "UpgradeK8sVersionOfCluster"},
+		{text: "Do a full backup/restore test", filler: true, maxWords: 3, want: "DoFullBackup"},
+		{text: "K8sChartSupport Upgrade", want: "K8sChartSupportUpgrade"},
+	} {
+		if got := identifier(tc.text, tc.filler, tc.maxWords); got != tc.want {
+			t.Errorf("identifier(%q) = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	p := &project{
+		Suites: []qase.Suite{{Id: 1, Title: "EKS"}, {Id: 2, Title: "P1", ParentId: 1}, {Id: 3, Title: "GKE"}, {Id: 4, Title: "P0", ParentId: 3}},
+		Cases: []qase.TestCase{
+			{Id: 102, Title: "Fail to update with invalid values", SuiteId: 2},
+			{Id: 103, Title: "Fail to update with invalid values", SuiteId: 2},
+			{Id: 99, Title: "should be able to update tags", SuiteId: 2, Status: statusDeprecated},
+			{Id: 8, Title: "should successfully provision the cluster", SuiteId: 4},
+			{Id: 7, Title: "retired", SuiteId: 4, Deleted: time.Now()},
+			{Id: 5, Title: "2 nodes"},
+		},
+	}
+	source, err := generate(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"// EKS > P1\nconst (",
+		"EKSP1UpdateTags CaseID = 99",
+		"// Deprecated: the case is deprecated in Qase.\n\tEKSP1UpdateTags",
+		"EKSP1FailUpdateWithInvalidValues102 CaseID = 102",
+		"EKSP1FailUpdateWithInvalidValues103 CaseID = 103",
+		"// GKE > P0\nconst (",
+		"GKEP0ProvisionCluster CaseID = 8",
+		"// Cases without suite\nconst (",
+		"Case2Nodes CaseID = 5",
+	} {
+		if !strings.Contains(string(source), want) {
+			t.Errorf("generated source does not contain %q:\n%s", want, source)
+		}
+	}
+	if strings.Contains(string(source), "= 7\n") {
+		t.Errorf("the deleted case is generated:\n%s", source)
+	}
+	// the groups are sorted, the cases of a group by ID
+	if strings.Index(string(source), "Cases without suite") > strings.Index(string(source), "EKS > P1") ||
+		strings.Index(string(source), "= 99") > strings.Index(string(source), "= 102") {
+		t.Errorf("the constants are not sorted:\n%s", source)
+	}
+}
+
+func TestMismatches(t *testing.T) {
+	p := &project{
+		Suites: []qase.Suite{{Id: 1, Title: "AKS"}, {Id: 2, Title: "P0", ParentId: 1}, {Id: 3, Title: "P1", ParentId: 1}, {Id: 4, Title: "K8sChartSupport Upgrade", ParentId: 1}},
+		Cases: []qase.TestCase{
+			{Id: 213, Title: "should successfully import the cluster", SuiteId: 2},
+			{Id: 214, Title: "should successfully provision with network policy azure", SuiteId: 3},
+			{Id: 251, Title: "should successfully test k8s chart support import", SuiteId: 4},
+		},
+	}
+	specsDir := t.TempDir()
+	for path, content := range map[string]string{
+		"aks/p1/p1_provisioning_test.go": "It(\"azure\", helpers.QaseLabel(qasecases.AKSP1ProvisionWithNetworkPolicyAzure), func() {})\n" +
+			"It(\"calico\", helpers.QaseLabel(qasecases.AKSP0ImportCluster), func() {})\n",
+		"aks/k8s_chart_support/upgrade/upgrade_test.go": "helpers.QaseLabel(qasecases.AKSK8sChartSupportUpgradeTestK8sChartSupportImport)\n",
+		"helpers/helper_qase.go":                        "// helpers.QaseLabel(qasecases.AKSP0ImportCluster)\n",
+	} {
+		path = filepath.Join(specsDir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	warnings, err := mismatches(constants(p), specsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "p1_provisioning_test.go:2 references AKSP0ImportCluster (213), a case of AKS > P0, from a spec of aks/p1") {
+		t.Errorf("got warnings %q", warnings)
+	}
+}
@@ -17,13 +17,15 @@ package backup_restore_test
 import (
 	. "github.com/onsi/ginkgo/v2"
 	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("BackupRestoreImport", func() {
 	k := kubectl.New()
 
-	It("Do a full backup/restore test", func() {
-		testCaseID = 315 // Report to Qase
+	It("Do a full backup/restore test", helpers.QaseLabel(qasecases.AKSBackupRestoreDoFullBackupRestoreTest315), func() {
 		BackupRestoreChecks(k)
 	})
 })
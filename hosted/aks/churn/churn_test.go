@@ -0,0 +1,71 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package churn_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("RegistrationChurn", func() {
+	BeforeEach(func() {
+		k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, false)
+		Expect(err).To(BeNil())
+		GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s", k8sVersion, clusterName))
+
+		err = helper.CreateAKSClusterOnAzure(location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		if ctx.ClusterCleanup {
+			if cluster != nil && cluster.ID != "" {
+				GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
+				err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
+				Expect(err).To(BeNil())
+				// marking as nil so that the suite AfterEach does not delete it again
+				cluster = nil
+			}
+			err := helper.DeleteAKSClusteronAzure(clusterName)
+			Expect(err).To(BeNil())
+		} else {
+			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
+		}
+	})
+
+	It("should not leak resources when the cluster is imported and removed in a loop", func() {
+		// the imported cluster is kept in the suite variable so that a failed cycle does not leave it in Rancher
+		importCluster := func() (*management.Cluster, error) {
+			var err error
+			cluster, err = helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
+			return cluster, err
+		}
+		deleteCluster := func(c *management.Cluster, client *rancher.Client) error {
+			if err := helper.DeleteAKSHostCluster(c, client); err != nil {
+				return err
+			}
+			cluster = nil
+			return nil
+		}
+		helpers.CheckRegistrationChurn(ctx.RancherAdminClient, helpers.CurrentRunConfig().ChurnCycles, importCluster, deleteCluster)
+	})
+})
@@ -0,0 +1,83 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cis_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var (
+	clusterName string
+	ctx         helpers.RancherContext
+	cluster     *management.Cluster
+	location    = helpers.GetAKSLocation()
+)
+
+func TestCIS(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CIS Suite", helpers.SuiteConfig(t))
+}
+
+// The Rancher setup and the cloud credential are shared by the parallel processes
+var _ = SynchronizedBeforeSuite(helpers.ParallelSynchronizedBeforeSuite, func(setup []byte) {
+	ctx = helpers.ParallelBeforeSuite(setup)
+})
+
+var _ = BeforeEach(func() {
+	helpers.CollectSpecArtifacts()
+	helpers.RecordClusterTransitions(ctx.RancherAdminClient)
+
+	cluster = nil
+	clusterName = helpers.GenerateClusterName(helpers.ClusterNamePrefix)
+})
+
+var _ = JustAfterEach(func() {
+	// Collect the support bundle before AfterEach deletes the cluster
+	helpers.CollectSupportBundleOnFailure(clusterName)
+})
+
+var _ = AfterEach(func() {
+	if ctx.ClusterCleanup && cluster != nil {
+		err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+	} else {
+		fmt.Println("Skipping downstream cluster deletion: ", clusterName)
+	}
+})
+
+// Delete the clusters leaked by all the processes once they are done
+var _ = SynchronizedAfterSuite(func() {}, helpers.CommonSynchronizedAfterSuite)
+
+var _ = ReportAfterEach(func(report SpecReport) {
+	// Add result in Qase if asked
+	helpers.ReportToQase(report)
+	// Upload the artifacts of the failed spec to ARTIFACTS_BUCKET if set
+	helpers.UploadArtifactsOnFailure(report)
+})
+
+var _ = ReportAfterSuite("Reports", func(report Report) {
+	// Write the JSON, JUnit and summary reports to the artifacts directory
+	helpers.GenerateSuiteReports(report)
+	// Submit the Qase results of all the parallel processes
+	helpers.SubmitQaseResults()
+})
@@ -0,0 +1,69 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compatibility_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("CompatibilityMatrix", func() {
+	// an invalid matrix is reported by ValidateRunConfig
+	matrix, _ := helpers.LoadCompatibilityMatrix(helpers.CurrentRunConfig().CompatibilityMatrix)
+
+	for _, entry := range matrix {
+		for _, chartConstraint := range entry.OperatorCharts {
+			It(fmt.Sprintf("should run a cluster lifecycle with rancher %s and operator chart %s", entry.Rancher, chartConstraint), func() {
+				By(fmt.Sprintf("Installing Rancher %s", entry.Rancher), func() {
+					installRancher(entry.Rancher)
+				})
+				helpers.CommonSynchronizedBeforeSuite()
+				ctx = helpers.CommonBeforeSuite()
+				chartVersion := helpers.InstallOperatorChartVersion(chartConstraint)
+
+				k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, false)
+				Expect(err).To(BeNil())
+				GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s", k8sVersion, clusterName))
+
+				cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
+				Expect(err).To(BeNil())
+				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				Expect(err).To(BeNil())
+				helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+
+				By("scaling up the nodepool", func() {
+					initialNodeCount := *(*cluster.AKSConfig.NodePools)[0].Count
+					cluster, err = helper.ScaleNodePool(cluster, ctx.RancherAdminClient, initialNodeCount+1, true, true)
+					Expect(err).To(BeNil())
+				})
+
+				By("checking the operator chart was kept by Rancher", func() {
+					Expect(helpers.GetCurrentOperatorChartVersion()).To(Equal(chartVersion))
+				})
+
+				By("deleting the cluster", func() {
+					Expect(helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)).To(Succeed())
+					helpers.WaitUntilClusterIsRemoved(ctx.RancherAdminClient, cluster.ID)
+					cluster = nil
+				})
+			})
+		}
+	}
+})
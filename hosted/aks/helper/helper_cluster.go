@@ -3,13 +3,13 @@ package helper
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
-	"github.com/rancher/shepherd/extensions/clusters"
 	"github.com/rancher/shepherd/extensions/clusters/aks"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
@@ -22,8 +22,9 @@ import (
 	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 	"k8s.io/utils/pointer"
 
-	"github.com/epinio/epinio/acceptance/helpers/proc"
 	"github.com/pkg/errors"
+	"github.com/rancher/hosted-providers-e2e/pkg/cloudjson"
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
 )
 
 var (
@@ -46,7 +47,34 @@ func CreateAKSHostedCluster(client *rancher.Client, displayName, cloudCredential
 		updateFunc(&aksClusterConfig)
 	}
 
-	return aks.CreateAKSHostedCluster(client, displayName, cloudCredentialID, aksClusterConfig, false, false, false, false, nil)
+	if helpers.IsDryRun() {
+		versions, err := listAKSAllVersions(client, cloudCredentialID, location)
+		if err != nil {
+			return nil, err
+		}
+		return helpers.DryRunCluster(client, &helpers.ClusterSpec{Name: displayName, AKSConfig: aks.HostClusterConfig(displayName, cloudCredentialID, aksClusterConfig)},
+			helpers.DryRunChecks{Versions: versions, InstanceTypeAvailable: aksVMSizeAvailable(location)})
+	}
+	if helpers.IsTerraformBackend() {
+		return helpers.CreateClusterWithTerraform(client, &helpers.ClusterSpec{Name: displayName, AKSConfig: aks.HostClusterConfig(displayName, cloudCredentialID, aksClusterConfig)})
+	}
+	cluster, err := aks.CreateAKSHostedCluster(client, displayName, cloudCredentialID, aksClusterConfig, false, false, false, false, nil)
+	return helpers.TrackRancherCluster(helpers.OperationProvision, cluster, err, location, aksClusterConfig.Tags)
+}
+
+// aksVMSizeAvailable returns the check of the VM sizes available to the subscription in the location, see helpers.DryRunChecks
+func aksVMSizeAvailable(location string) func(vmSize string) error {
+	return func(vmSize string) error {
+		out, err := extcli.Az.Output("vm", "list-skus", "--location", location, "--size", vmSize, "--resource-type", "virtualMachines",
+			"--query", fmt.Sprintf("[?name=='%s' && length(restrictions)==`0`].name", vmSize), "-o", "tsv")
+		if err != nil {
+			return fmt.Errorf("failed to check VM size %s in %s: %w", vmSize, location, err)
+		}
+		if out == "" {
+			return fmt.Errorf("VM size %s is not available in %s", vmSize, location)
+		}
+		return nil
+	}
 }
 
 // ImportAKSHostedCluster imports an AKS cluster to Rancher
@@ -64,23 +92,30 @@ func ImportAKSHostedCluster(client *rancher.Client, clusterName, cloudCredential
 		Name: clusterName,
 	}
 
-	return client.Management.Cluster.Create(cluster)
+	clusterResp, err := client.Management.Cluster.Create(cluster)
+	return helpers.TrackRancherCluster(helpers.OperationImport, clusterResp, err, location, tags)
 }
 
 // DeleteAKSHostCluster deletes the AKS cluster
 func DeleteAKSHostCluster(cluster *management.Cluster, client *rancher.Client) error {
-	return client.Management.Cluster.Delete(cluster)
+	if err := helpers.DeleteRancherCluster(client, cluster); err != nil {
+		return err
+	}
+	helpers.MarkResourceDeleted(helpers.ResourceRancherCluster, cluster.Name)
+	return nil
 }
 
 // UpgradeClusterKubernetesVersion upgrades the k8s version to the value defined by upgradeToVersion;
 // if checkClusterConfig is set to true, it will validate that the cluster control plane has been upgrade successfully
 func UpgradeClusterKubernetesVersion(cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, checkClusterConfig bool) (*management.Cluster, error) {
+	defer helpers.TimeUpgrade(helpers.OperationUpgrade, cluster, upgradeToVersion, checkClusterConfig)()
+
 	upgradedCluster := cluster
 	currentVersion := *cluster.AKSConfig.KubernetesVersion
 	upgradedCluster.AKSConfig.KubernetesVersion = &upgradeToVersion
 
 	var err error
-	cluster, err = client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err = helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	Expect(err).To(BeNil())
 
 	if checkClusterConfig {
@@ -93,12 +128,12 @@ func UpgradeClusterKubernetesVersion(cluster *management.Cluster, upgradeToVersi
 		}
 
 		// Check if the desired config has been applied in Rancher
-		Eventually(func() string {
+		helpers.EventuallyForOperation(helpers.OperationUpgrade, func() string {
 			ginkgo.GinkgoLogr.Info("Waiting for k8s upgrade to appear in AKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			return *cluster.AKSStatus.UpstreamSpec.KubernetesVersion
-		}, tools.SetTimeout(10*time.Minute), 5*time.Second).Should(Equal(upgradeToVersion))
+		}, tools.SetTimeout(10*time.Minute)).Should(Equal(upgradeToVersion))
 		// ensure nodepool version is same in Rancher
 		for _, np := range *cluster.AKSStatus.UpstreamSpec.NodePools {
 			Expect(*np.OrchestratorVersion).To(Equal(currentVersion))
@@ -112,13 +147,15 @@ func UpgradeClusterKubernetesVersion(cluster *management.Cluster, upgradeToVersi
 // if wait is set to true, it will wait until the cluster finishes upgrading;
 // if checkClusterConfig is set to true, it will validate that nodepool has been upgraded successfully
 func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	defer helpers.TimeUpgrade(helpers.OperationNodeUpgrade, cluster, upgradeToVersion, wait)()
+
 	upgradedCluster := cluster
 	configNodePools := *upgradedCluster.AKSConfig.NodePools
 	for i := range configNodePools {
 		configNodePools[i].OrchestratorVersion = &upgradeToVersion
 	}
 	var err error
-	cluster, err = client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err = helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	Expect(err).To(BeNil())
 
 	if checkClusterConfig {
@@ -129,15 +166,15 @@ func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion
 	}
 
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+		err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 		Expect(err).To(BeNil())
 	}
 
 	if checkClusterConfig {
 		// Check if the desired config has been applied in Rancher
-		Eventually(func() bool {
+		helpers.EventuallyForOperation(helpers.OperationNodeUpgrade, func() bool {
 			ginkgo.GinkgoLogr.Info("waiting for the nodepool upgrade to appear in AKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			for _, np := range *cluster.AKSStatus.UpstreamSpec.NodePools {
 				if *np.OrchestratorVersion != upgradeToVersion {
@@ -145,15 +182,34 @@ func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion
 				}
 			}
 			return true
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeTrue())
+		}, tools.SetTimeout(12*time.Minute)).Should(BeTrue())
 	}
 	return cluster, nil
 }
 
+// UpgradeSteps returns the steps used by helpers.UpgradeAlongPath to upgrade the control plane and then the nodepools of an AKS cluster
+func UpgradeSteps() helpers.UpgradeSteps {
+	return helpers.UpgradeSteps{
+		ControlPlane: func(cluster *management.Cluster, client *rancher.Client, version string) (*management.Cluster, error) {
+			return UpgradeClusterKubernetesVersion(cluster, version, client, true)
+		},
+		NodeGroups: func(cluster *management.Cluster, client *rancher.Client, version string) (*management.Cluster, error) {
+			return UpgradeNodeKubernetesVersion(cluster, version, client, true, true)
+		},
+	}
+}
+
+// listAKSAllVersions returns all the AKS versions available in the region; the list is fetched once per run
+func listAKSAllVersions(client *rancher.Client, cloudCredentialID, region string) ([]string, error) {
+	return helpers.CachedVersions(client, fmt.Sprintf("aks/%s/%s", cloudCredentialID, region), func() ([]string, error) {
+		return kubernetesversions.ListAKSAllVersions(client, cloudCredentialID, region)
+	})
+}
+
 // ListSingleVariantAKSAllVersions returns a list of single variants of minor versions in descending order
 // For e.g 1.27.5, 1.26.6, 1.25.8
 func ListSingleVariantAKSAllVersions(client *rancher.Client, cloudCredentialID, region string) (availableVersions []string, err error) {
-	availableVersions, err = kubernetesversions.ListAKSAllVersions(client, cloudCredentialID, region)
+	availableVersions, err = listAKSAllVersions(client, cloudCredentialID, region)
 	if err != nil {
 		return nil, err
 	}
@@ -189,6 +245,8 @@ func GetK8sVersionVariantAKS(minorVersion string, client *rancher.Client, cloudC
 // if wait is set to true, it will wait until the cluster finishes upgrading;
 // if checkClusterConfig is set to true, it will validate that nodepool has been added successfully
 func AddNodePool(cluster *management.Cluster, increaseBy int, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	defer helpers.TimeOperation(helpers.OperationAddNodePool, cluster, wait)()
+
 	upgradedCluster := cluster
 	currentNodePoolNumber := len(*cluster.AKSConfig.NodePools)
 
@@ -199,6 +257,7 @@ func AddNodePool(cluster *management.Cluster, increaseBy int, client *rancher.Cl
 	npTemplate := templateNPs[0]
 
 	updateNodePoolsList := *cluster.AKSConfig.NodePools
+	added := make([]string, 0, increaseBy)
 
 	for i := 1; i <= increaseBy; i++ {
 		newNodepool := management.AKSNodePool{
@@ -220,39 +279,92 @@ func AddNodePool(cluster *management.Cluster, increaseBy int, client *rancher.Cl
 			VMSize:              npTemplate.VMSize,
 		}
 		updateNodePoolsList = append(updateNodePoolsList, newNodepool)
-
+		added = append(added, *newNodepool.Name)
 	}
 	upgradedCluster.AKSConfig.NodePools = &updateNodePoolsList
 
 	var err error
-	cluster, err = client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err = helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	Expect(err).To(BeNil())
 
 	if checkClusterConfig {
-		// Check if the desired config is set correctly
+		// Check if the desired config is set correctly; only the added nodepools are looked up
 		Expect(len(*cluster.AKSConfig.NodePools)).Should(BeNumerically("==", currentNodePoolNumber+increaseBy))
-		for i, np := range *cluster.AKSConfig.NodePools {
-			Expect(np.Name).To(Equal(updateNodePoolsList[i].Name))
-		}
+		Expect(helpers.MissingNodePools(added, nodePoolNames(*cluster.AKSConfig.NodePools))).To(BeEmpty())
 	}
 
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+		err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 		Expect(err).To(BeNil())
 	}
 	if checkClusterConfig {
 		// Check if the desired config has been applied in Rancher
-		Eventually(func() int {
-			ginkgo.GinkgoLogr.Info("Waiting for the total nodepool count to increase in AKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			return len(*cluster.AKSStatus.UpstreamSpec.NodePools)
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeNumerically("==", currentNodePoolNumber+increaseBy))
+		ginkgo.GinkgoLogr.Info("Waiting for the new nodepools to appear in AKSStatus.UpstreamSpec ...")
+		cluster, err = helpers.WaitForAddedNodePools(client, cluster.ID, added, currentNodePoolNumber+increaseBy, func(cluster *management.Cluster) []string {
+			return nodePoolNames(*cluster.AKSStatus.UpstreamSpec.NodePools)
+		}, tools.SetTimeout(12*time.Minute))
+		Expect(err).To(BeNil())
+	}
+	return cluster, nil
+}
 
-		for i, np := range *cluster.AKSStatus.UpstreamSpec.NodePools {
-			Expect(np.Name).To(Equal(updateNodePoolsList[i].Name))
+// nodePoolNames returns the names of the nodepools
+func nodePoolNames(nodePools []management.AKSNodePool) []string {
+	names := make([]string, 0, len(nodePools))
+	for _, np := range nodePools {
+		if np.Name != nil {
+			names = append(names, *np.Name)
 		}
 	}
+	return names
+}
+
+// AddNodePoolWithMetadata adds a user nodepool with the given node labels and taints, for e.g. "key=value:NoSchedule"; it copies the first nodepool of the cluster
+// if wait is set to true, it will wait until the cluster finishes upgrading;
+// if checkClusterConfig is set to true, it will validate that the nodepool has been added successfully with its labels and taints
+func AddNodePoolWithMetadata(cluster *management.Cluster, client *rancher.Client, labels map[string]string, taints []string, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	defer helpers.TimeOperation(helpers.OperationAddNodePool, cluster, wait)()
+
+	upgradedCluster := cluster
+	updateNodePoolsList := *cluster.AKSConfig.NodePools
+	newNodepool := updateNodePoolsList[0]
+	newNodepool.Name = pointer.String(namegen.RandStringLower(5))
+	newNodepool.Count = pointer.Int64(1)
+	newNodepool.Mode = "User"
+	newNodepool.NodeLabels = labels
+	newNodepool.NodeTaints = taints
+	updateNodePoolsList = append(updateNodePoolsList, newNodepool)
+	upgradedCluster.AKSConfig.NodePools = &updateNodePoolsList
+
+	var err error
+	cluster, err = helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
+	Expect(err).To(BeNil())
+
+	if checkClusterConfig {
+		// Check if the desired config is set correctly
+		configNodePools := *cluster.AKSConfig.NodePools
+		Expect(configNodePools).To(HaveLen(len(updateNodePoolsList)))
+		Expect(configNodePools[len(configNodePools)-1].NodeLabels).To(Equal(labels))
+		Expect(configNodePools[len(configNodePools)-1].NodeTaints).To(Equal(taints))
+	}
+
+	if wait {
+		err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
+		Expect(err).To(BeNil())
+	}
+	if checkClusterConfig {
+		// Check if the desired config has been applied in Rancher
+		ginkgo.GinkgoLogr.Info("Waiting for the nodepool labels and taints to appear in AKSStatus.UpstreamSpec ...")
+		cluster, err = helpers.WaitForUpstreamField(client, cluster.ID, func(cluster *management.Cluster) any {
+			for _, np := range *cluster.AKSStatus.UpstreamSpec.NodePools {
+				if *np.Name == *newNodepool.Name {
+					return []any{np.NodeLabels, np.NodeTaints}
+				}
+			}
+			return nil
+		}, []any{labels, taints}, tools.SetTimeout(12*time.Minute))
+		Expect(err).To(BeNil())
+	}
 	return cluster, nil
 }
 
@@ -260,6 +372,8 @@ func AddNodePool(cluster *management.Cluster, increaseBy int, client *rancher.Cl
 // if checkClusterConfig is set to true, it will validate that nodepool has been deleted successfully
 // TODO: Modify this method to delete a custom qty of DeleteNodePool, perhaps by adding an `decreaseBy int` arg
 func DeleteNodePool(cluster *management.Cluster, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	defer helpers.TimeOperation(helpers.OperationDeleteNodePool, cluster, wait)()
+
 	currentNodePoolNumber := len(*cluster.AKSConfig.NodePools)
 
 	upgradedCluster := cluster
@@ -268,7 +382,7 @@ func DeleteNodePool(cluster *management.Cluster, client *rancher.Client, wait, c
 	upgradedCluster.AKSConfig.NodePools = &updatedNodePoolsList
 
 	var err error
-	cluster, err = client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err = helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	Expect(err).To(BeNil())
 
 	if checkClusterConfig {
@@ -279,18 +393,17 @@ func DeleteNodePool(cluster *management.Cluster, client *rancher.Client, wait, c
 		}
 	}
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+		err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 		Expect(err).To(BeNil())
 	}
 	if checkClusterConfig {
 
 		// Check if the desired config has been applied in Rancher
-		Eventually(func() int {
-			ginkgo.GinkgoLogr.Info("Waiting for the total nodepool count to decrease in AKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
+		ginkgo.GinkgoLogr.Info("Waiting for the total nodepool count to decrease in AKSStatus.UpstreamSpec ...")
+		cluster, err = helpers.WaitForUpstreamField(client, cluster.ID, func(cluster *management.Cluster) any {
 			return len(*cluster.AKSStatus.UpstreamSpec.NodePools)
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeNumerically("==", currentNodePoolNumber-1))
+		}, BeNumerically("==", currentNodePoolNumber-1), tools.SetTimeout(12*time.Minute))
+		Expect(err).To(BeNil())
 		for i, np := range *cluster.AKSStatus.UpstreamSpec.NodePools {
 			Expect(np.Name).To(Equal(updatedNodePoolsList[i].Name))
 		}
@@ -302,6 +415,8 @@ func DeleteNodePool(cluster *management.Cluster, client *rancher.Client, wait, c
 // if wait is set to true, it will wait until the cluster finishes upgrading;
 // if checkClusterConfig is set to true, it will validate that nodepool has been scaled successfully
 func ScaleNodePool(cluster *management.Cluster, client *rancher.Client, nodeCount int64, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	defer helpers.TimeOperation(helpers.OperationScale, cluster, wait)()
+
 	upgradedCluster := cluster
 	configNodePools := *upgradedCluster.AKSConfig.NodePools
 	for i := range configNodePools {
@@ -309,7 +424,7 @@ func ScaleNodePool(cluster *management.Cluster, client *rancher.Client, nodeCoun
 	}
 
 	var err error
-	cluster, err = client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err = helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	Expect(err).To(BeNil())
 
 	if checkClusterConfig {
@@ -321,15 +436,15 @@ func ScaleNodePool(cluster *management.Cluster, client *rancher.Client, nodeCoun
 	}
 
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+		err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 		Expect(err).To(BeNil())
 	}
 
 	if checkClusterConfig {
 		// check that the desired config is applied on Rancher
-		Eventually(func() bool {
+		helpers.EventuallyForOperation(helpers.OperationScale, func() bool {
 			ginkgo.GinkgoLogr.Info("Waiting for the node count change to appear in AKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			upstreamNodePools := *cluster.AKSStatus.UpstreamSpec.NodePools
 			for i := range upstreamNodePools {
@@ -338,7 +453,7 @@ func ScaleNodePool(cluster *management.Cluster, client *rancher.Client, nodeCoun
 				}
 			}
 			return true
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeTrue())
+		}, tools.SetTimeout(12*time.Minute)).Should(BeTrue())
 	}
 
 	return cluster, nil
@@ -395,7 +510,7 @@ func UpdateAutoScaling(cluster *management.Cluster, client *rancher.Client, enab
 	}
 
 	var err error
-	cluster, err = client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err = helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	if err != nil {
 		return nil, err
 	}
@@ -423,9 +538,9 @@ func UpdateAutoScaling(cluster *management.Cluster, client *rancher.Client, enab
 	}
 
 	if checkClusterConfig {
-		Eventually(func() bool {
+		helpers.EventuallyForOperation(helpers.OperationScale, func() bool {
 			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Waiting for the autoscaling update (enable: %v) to appear in AKSStatus.UpstreamSpec ...", enabled))
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			for _, np := range *cluster.AKSStatus.UpstreamSpec.NodePools {
 				if enabled {
@@ -455,7 +570,7 @@ func UpdateAutoScaling(cluster *management.Cluster, client *rancher.Client, enab
 				}
 			}
 			return true
-		}, tools.SetTimeout(10*time.Minute), 15*time.Second).Should(BeTrue())
+		}, tools.SetTimeout(10*time.Minute)).Should(BeTrue())
 	}
 	return cluster, nil
 }
@@ -466,12 +581,13 @@ func UpdateCluster(cluster *management.Cluster, client *rancher.Client, updateFu
 
 	updateFunc(upgradedCluster)
 
-	return client.Management.Cluster.Update(cluster, &upgradedCluster)
+	return helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 }
 
 // ====================================================================Azure CLI (start)=================================
 // Create Azure AKS cluster using AZ CLI
 func CreateAKSClusterOnAzure(location string, clusterName string, k8sVersion string, nodes string, tags map[string]string, extraArgs ...string) error {
+	helpers.SkipOnDryRun("AKS cluster " + clusterName)
 	err := CreateAKSRGOnAzure(clusterName, location)
 	if err != nil {
 		return err
@@ -490,27 +606,28 @@ func CreateAKSClusterOnAzure(location string, clusterName string, k8sVersion str
 		args = append(args, extraArgs...)
 	}
 
-	fmt.Printf("Running command: az %v\n", args)
-	var out string
-	out, err = proc.RunW("az", args...)
+	start := time.Now()
+	_, err = extcli.Az.Run(args...)
+	helpers.RecordOperation(helpers.OperationCLIProvision, clusterName, start, err)
 	if err != nil {
-		return errors.Wrap(err, "Failed to create cluster: "+out)
+		return errors.Wrap(err, "Failed to create cluster")
 	}
 
 	fmt.Println("Created AKS cluster: ", clusterName)
+	registerAKSKubeconfig(clusterName, clusterName)
+	helpers.TrackResource(helpers.Resource{Kind: helpers.ResourceCloudCluster, Name: clusterName, Region: location, Tags: tags, NodePools: helpers.CLINodePools(helpers.AzDefaultVMSize, nodes)})
 
 	return nil
 }
 
 // CreateAKSRGOnAzure creates resource group on azure via CLI
 func CreateAKSRGOnAzure(name, location string) error {
+	helpers.SkipOnDryRun("AKS resource group " + name)
 	fmt.Println("Creating AKS resource group ...")
 	rgargs := []string{"group", "create", "--location", location, "--resource-group", name, "--subscription", subscriptionID}
-	fmt.Printf("Running command: az %v\n", rgargs)
-
-	out, err := proc.RunW("az", rgargs...)
+	_, err := extcli.Az.Run(rgargs...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to create resource group: "+out)
+		return errors.Wrap(err, "Failed to create resource group")
 	}
 	fmt.Println("Created AKS resource group: ", name)
 	return nil
@@ -523,10 +640,9 @@ func AddNodePoolOnAzure(npName, clusterName, resourceGroupName, nodeCount string
 	if len(extraArgs) > 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: az %v\n", args)
-	out, err := proc.RunW("az", args...)
+	_, err := extcli.Az.Run(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to add node pool: "+out)
+		return errors.Wrap(err, "Failed to add node pool")
 	}
 	fmt.Println("Added node pool: ", npName)
 	return nil
@@ -539,10 +655,9 @@ func DeleteNodePoolOnAzure(npName, clusterName, resourceGroupName string, extraA
 	if len(extraArgs) > 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: az %v\n", args)
-	out, err := proc.RunW("az", args...)
+	_, err := extcli.Az.Run(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to delete node pool: "+out)
+		return errors.Wrap(err, "Failed to delete node pool")
 	}
 	fmt.Println("Deleted node pool: ", npName)
 	return nil
@@ -555,10 +670,9 @@ func ScaleNodePoolOnAzure(npName, clusterName, resourceGroupName, nodeCount stri
 	if len(extraArgs) > 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: az %v\n", args)
-	out, err := proc.RunW("az", args...)
+	_, err := extcli.Az.Run(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to scale node pool: "+out)
+		return errors.Wrap(err, "Failed to scale node pool")
 	}
 	fmt.Println("Scaled node pool: ", npName)
 	return nil
@@ -578,10 +692,9 @@ func UpdateClusterTagOnAzure(tags map[string]string, clusterName, resourceGroupN
 	if len(extraArgs) > 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: az %v\n", args)
-	out, err := proc.RunW("az", args...)
+	_, err := extcli.Az.Run(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to add tag on Azure: "+out)
+		return errors.Wrap(err, "Failed to add tag on Azure")
 	}
 	fmt.Println("Added tags on Azure: ", clusterName)
 	return nil
@@ -591,22 +704,55 @@ func UpdateClusterTagOnAzure(tags map[string]string, clusterName, resourceGroupN
 // it returns false if the cluster does not exist or is in Deleting state.
 func ClusterExistsOnAzure(clusterName, resourceGroup string) (bool, error) {
 	fmt.Println("Showing AKS cluster ...")
-	args := []string{"aks", "show", "--subscription", subscriptionID, "--name", clusterName, "--resource-group", resourceGroup}
-	fmt.Printf("Running command: az %v\n", args)
-	out, err := proc.RunW("az", args...)
+	aksCluster, err := GetAKSClusterOnAzure(clusterName, resourceGroup)
 	if err != nil {
-		return false, errors.Wrap(err, "Failed to show cluster: "+out)
+		return false, err
 	}
-	if !strings.Contains(out, "Deleting") {
-		return true, nil
+	return aksCluster.ProvisioningState != cloudjson.AKSStateDeleting, nil
+}
+
+// GetAKSClusterOnAzure gets the cluster using az CLI
+func GetAKSClusterOnAzure(clusterName, resourceGroup string) (*cloudjson.AKSCluster, error) {
+	args := []string{"aks", "show", "--subscription", subscriptionID, "--name", clusterName, "--resource-group", resourceGroup, "--output", "json"}
+	out, err := extcli.Az.Output(args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to show cluster")
+	}
+	aksCluster, err := cloudjson.Decode[cloudjson.AKSCluster](out)
+	if err != nil {
+		return nil, err
 	}
-	return false, nil
+	return &aksCluster, nil
+}
+
+// GetAKSCredentialsOnAzure registers how to write the credentials of the AKS cluster to its kubeconfig, so that it can be reached with kubectl
+// before being imported into Rancher; the kubeconfig is only written once a spec needs it, see helpers.DownstreamKubeconfigPath
+func GetAKSCredentialsOnAzure(clusterName, resourceGroup string) error {
+	registerAKSKubeconfig(clusterName, resourceGroup)
+	return nil
+}
+
+// registerAKSKubeconfig registers the kubeconfig generator of the cluster, see GetAKSCredentialsOnAzure
+func registerAKSKubeconfig(clusterName, resourceGroup string) {
+	helpers.RegisterKubeconfigGenerator(clusterName, func(kubeconfig string) error {
+		fmt.Println("Getting AKS cluster credentials ...")
+		args := []string{"aks", "get-credentials", "--resource-group", resourceGroup, "--name", clusterName, "--overwrite-existing", "--subscription", subscriptionID, "--file", kubeconfig}
+		_, err := extcli.Az.Run(args...)
+		if err != nil {
+			return errors.Wrap(err, "Failed to get cluster credentials")
+		}
+		return nil
+	})
 }
 
 // RunCommand executes `aks command invoke` which runs a command inside a cluster;  useful when registering a private cluster with rancher
 func RunCommand(clusterName, resourceGroup, command string) error {
 	currentKubeconfig := os.Getenv("KUBECONFIG")
-	downstreamKubeconfig := helpers.DownstreamKubeconfig(clusterName)
+	dir, err := helpers.ArtifactDir(ginkgo.CurrentSpecReport())
+	if err != nil {
+		return errors.Wrap(err, "Failed to create the artifact directory")
+	}
+	downstreamKubeconfig := filepath.Join(dir, clusterName+".kubeconfig")
 	defer func() {
 		_ = os.Setenv("KUBECONFIG", currentKubeconfig)
 		_ = os.Remove(downstreamKubeconfig) // clean up
@@ -615,18 +761,15 @@ func RunCommand(clusterName, resourceGroup, command string) error {
 
 	fmt.Printf("Logging into the cluster")
 	loginArgs := []string{"aks", "get-credentials", "--resource-group", resourceGroup, "--name", clusterName, "--overwrite-existing", "--subscription", subscriptionID}
-	fmt.Printf("Running command: az %v\n", loginArgs)
-	out, err := proc.RunW("az", loginArgs...)
+	_, err = extcli.Az.Run(loginArgs...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to run command: "+out)
+		return errors.Wrap(err, "Failed to run command")
 	}
 
 	args := []string{"aks", "command", "invoke", "--resource-group", resourceGroup, "--name", clusterName, "--subscription", subscriptionID, "--command", command}
-	fmt.Printf("Running command inside the cluster: az %v\n", args)
-
-	out, err = proc.RunW("az", args...)
+	_, err = extcli.Az.Run(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to run command: "+out)
+		return errors.Wrap(err, "Failed to run command")
 	}
 	return nil
 }
@@ -640,15 +783,36 @@ func UpgradeAKSOnAzure(clusterName, resourceGroup, upgradeToVersion string, addi
 	if len(additionalArgs) > 0 {
 		args = append(args, additionalArgs...)
 	}
-	fmt.Printf("Running command: az %v\n", args)
-	out, err := proc.RunW("az", args...)
+	_, err := extcli.Az.Run(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to upgrade cluster: "+out)
+		return errors.Wrap(err, "Failed to upgrade cluster")
 	}
 	fmt.Println("Upgraded AKS cluster: ", clusterName)
 	return nil
 }
 
+// StopAKSOnAzure stops the AKS cluster on Azure; its control plane and nodes are deallocated until it is started again
+func StopAKSOnAzure(clusterName, resourceGroup string) error {
+	fmt.Println("Stopping AKS cluster ...")
+	_, err := extcli.Az.Run("aks", "stop", "--subscription", subscriptionID, "--resource-group", resourceGroup, "--name", clusterName)
+	if err != nil {
+		return errors.Wrap(err, "Failed to stop cluster")
+	}
+	fmt.Println("Stopped AKS cluster: ", clusterName)
+	return nil
+}
+
+// StartAKSOnAzure starts the AKS cluster stopped with StopAKSOnAzure
+func StartAKSOnAzure(clusterName, resourceGroup string) error {
+	fmt.Println("Starting AKS cluster ...")
+	_, err := extcli.Az.Run("aks", "start", "--subscription", subscriptionID, "--resource-group", resourceGroup, "--name", clusterName)
+	if err != nil {
+		return errors.Wrap(err, "Failed to start cluster")
+	}
+	fmt.Println("Started AKS cluster: ", clusterName)
+	return nil
+}
+
 // convertMapToAKSString converts the map of labels to a string format acceptable by azure CLI
 func convertMapToAKSString(tags map[string]string) []string {
 	var convertedString []string
@@ -660,17 +824,19 @@ func convertMapToAKSString(tags map[string]string) []string {
 
 // DeleteAKSClusteronAzure Complete cleanup steps for Azure AKS
 func DeleteAKSClusteronAzure(clusterName string) error {
+	defer helpers.RemoveDownstreamKubeconfig(clusterName) // clean up
 
 	fmt.Println("Deleting AKS resource group which will delete cluster too ...")
 	args := []string{"group", "delete", "--name", clusterName, "--yes", "--subscription", subscriptionID}
-	fmt.Printf("Running command: az %v\n", args)
-
-	out, err := proc.RunW("az", args...)
+	start := time.Now()
+	_, err := extcli.Az.Run(args...)
+	helpers.RecordOperation(helpers.OperationCLIDelete, clusterName, start, err)
 	if err != nil {
-		return errors.Wrap(err, "Failed to delete resource group: "+out)
+		return errors.Wrap(err, "Failed to delete resource group")
 	}
 
 	fmt.Println("Deleted AKS resource group: ", clusterName)
+	helpers.MarkResourceDeleted(helpers.ResourceCloudCluster, clusterName)
 
 	return nil
 }
@@ -678,9 +844,16 @@ func DeleteAKSClusteronAzure(clusterName string) error {
 //====================================================================Azure CLI (end)=================================
 
 // GetK8sVersion returns the k8s version to be used by the test;
-// this value can either be a variant of envvar DOWNSTREAM_K8S_MINOR_VERSION or the highest available version
+// this value can either be a variant of envvar DOWNSTREAM_K8S_MINOR_VERSION, the highest version matching it if it is a semver constraint, or the highest available version
 // or second-highest minor version in case of upgrade scenarios
 func GetK8sVersion(client *rancher.Client, cloudCredentialID, region string, forUpgrade bool) (string, error) {
+	if constraint := helpers.DownstreamK8sMinorVersion; helpers.IsK8sVersionConstraint(constraint) {
+		allVersions, err := listAKSAllVersions(client, cloudCredentialID, region)
+		if err != nil {
+			return "", err
+		}
+		return helpers.MatchK8sVersion(helpers.FilterUIUnsupportedVersions(allVersions, client), constraint)
+	}
 	if k8sMinorVersion := helpers.DownstreamK8sMinorVersion; k8sMinorVersion != "" {
 		return GetK8sVersionVariantAKS(k8sMinorVersion, client, cloudCredentialID, region)
 	}
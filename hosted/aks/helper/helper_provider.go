@@ -0,0 +1,97 @@
+package helper
+
+import (
+	"maps"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+func init() {
+	helpers.RegisterProvider(aksProvider{})
+}
+
+// aksProvider is the AKS implementation of helpers.HostedProvider, in the location of GetAKSLocation
+type aksProvider struct{}
+
+func (aksProvider) Name() string {
+	return "aks"
+}
+
+func (aksProvider) Location() string {
+	return helpers.GetAKSLocation()
+}
+
+func (aksProvider) K8sVersion(client *rancher.Client, cloudCredID string, forUpgrade bool) (string, error) {
+	return GetK8sVersion(client, cloudCredID, helpers.GetAKSLocation(), forUpgrade)
+}
+
+func (aksProvider) AvailableVersions(client *rancher.Client, cluster *management.Cluster) ([]string, error) {
+	return ListAKSAvailableVersions(client, cluster.ID)
+}
+
+func (aksProvider) CreateCluster(client *rancher.Client, clusterName, cloudCredID, k8sVersion string) (*management.Cluster, error) {
+	return CreateAKSHostedCluster(client, clusterName, cloudCredID, k8sVersion, helpers.GetAKSLocation(), nil)
+}
+
+func (aksProvider) ImportCluster(client *rancher.Client, clusterName, cloudCredID string) (*management.Cluster, error) {
+	return ImportAKSHostedCluster(client, clusterName, cloudCredID, helpers.GetAKSLocation(), helpers.GetCommonMetadataLabels())
+}
+
+func (aksProvider) UpgradeCluster(cluster *management.Cluster, client *rancher.Client, k8sVersion string) (*management.Cluster, error) {
+	return UpgradeClusterKubernetesVersion(cluster, k8sVersion, client, true)
+}
+
+func (aksProvider) UpdateCluster(cluster *management.Cluster, client *rancher.Client, updateFunc func(*management.Cluster)) (*management.Cluster, error) {
+	return UpdateCluster(cluster, client, updateFunc)
+}
+
+func (aksProvider) AddNodePool(cluster *management.Cluster, client *rancher.Client) (*management.Cluster, error) {
+	return AddNodePool(cluster, 1, client, true, true)
+}
+
+func (aksProvider) DeleteNodePool(cluster *management.Cluster, client *rancher.Client) (*management.Cluster, error) {
+	return DeleteNodePool(cluster, client, true, true)
+}
+
+func (aksProvider) ScaleNodePools(cluster *management.Cluster, client *rancher.Client, nodeCount int64) (*management.Cluster, error) {
+	return ScaleNodePool(cluster, client, nodeCount, true, true)
+}
+
+func (aksProvider) DeleteCluster(cluster *management.Cluster, client *rancher.Client) error {
+	return DeleteAKSHostCluster(cluster, client)
+}
+
+func (aksProvider) OutOfBand() helpers.OutOfBandClient {
+	return aksOutOfBandClient{}
+}
+
+// aksOutOfBandClient manages the AKS clusters with az; every cluster has a resource group of its own, named after it,
+// so the location is not needed to find it
+type aksOutOfBandClient struct{}
+
+func (aksOutOfBandClient) CreateCluster(clusterName, k8sVersion string) error {
+	return CreateAKSClusterOnAzure(helpers.GetAKSLocation(), clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+}
+
+func (aksOutOfBandClient) ClusterExists(clusterName, _ string) (bool, error) {
+	return ClusterExistsOnAzure(clusterName, clusterName)
+}
+
+func (aksOutOfBandClient) DeleteCluster(clusterName, _ string) error {
+	return DeleteAKSClusteronAzure(clusterName)
+}
+
+func (aksOutOfBandClient) UpdateTags(clusterName, _ string, tags map[string]string) error {
+	// the tags of the cluster are replaced by az aks update, the current ones are merged first
+	aksCluster, err := GetAKSClusterOnAzure(clusterName, clusterName)
+	if err != nil {
+		return err
+	}
+	merged := map[string]string{}
+	maps.Copy(merged, aksCluster.Tags)
+	maps.Copy(merged, tags)
+	return UpdateClusterTagOnAzure(merged, clusterName, clusterName)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("K8sChartSupportImport", func() {
@@ -33,8 +34,7 @@ var _ = Describe("K8sChartSupportImport", func() {
 		}
 	})
 
-	It("should successfully test k8s chart support import", func() {
-		testCaseID = 254 // Report to Qase
+	It("should successfully test k8s chart support import", helpers.QaseLabel(qasecases.AKSK8sChartSupportTestK8sChartSupportImport), func() {
 		commonchecks(ctx.RancherAdminClient, cluster)
 
 	})
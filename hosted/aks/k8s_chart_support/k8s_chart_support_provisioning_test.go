@@ -9,6 +9,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("K8sChartSupportProvisioning", func() {
@@ -32,8 +33,7 @@ var _ = Describe("K8sChartSupportProvisioning", func() {
 		}
 	})
 
-	It("should successfully test k8s chart support provisioning", func() {
-		testCaseID = 252 // Report to Qase
+	It("should successfully test k8s chart support provisioning", helpers.QaseLabel(qasecases.AKSK8sChartSupportTestK8sChartSupportProvisioning), func() {
 		commonchecks(ctx.RancherAdminClient, cluster)
 	})
 
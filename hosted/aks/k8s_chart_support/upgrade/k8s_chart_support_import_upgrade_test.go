@@ -9,6 +9,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("K8sChartSupportUpgradeImport", func() {
@@ -33,10 +34,9 @@ var _ = Describe("K8sChartSupportUpgradeImport", func() {
 		}
 	})
 
-	It("should successfully test k8s chart support import in an upgrade scenario", func() {
+	It("should successfully test k8s chart support import in an upgrade scenario", helpers.QaseLabel(qasecases.AKSK8sChartSupportUpgradeTestK8sChartSupportImportInUpgradeScenario), func() {
 		GinkgoLogr.Info(fmt.Sprintf("Testing K8s %s chart support for import on Rancher upgraded from %s to %s", helpers.K8sUpgradedMinorVersion, helpers.RancherFullVersion, helpers.RancherUpgradeFullVersion))
 
-		testCaseID = 253 // Report to Qase
 		commonchecks(&ctx, cluster, clusterName, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
 	})
 
@@ -25,23 +25,24 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("P0Provisioning", func() {
 	for _, testData := range []struct {
-		qaseID    int64
+		qaseID    qasecases.CaseID
 		isUpgrade bool
 		testBody  func(cluster *management.Cluster, client *rancher.Client, clusterName string)
 		testTitle string
 	}{
 		{
-			qaseID:    172,
+			qaseID:    qasecases.AKSP0ProvisionClusterAddDeleteScaleNodepool,
 			isUpgrade: false,
 			testBody:  p0NodesChecks,
 			testTitle: "should successfully provision the cluster & add, delete, scale nodepool",
 		},
 		{
-			qaseID:    175,
+			qaseID:    qasecases.AKSP0UpgradeK8sVersionOfCluster175,
 			isUpgrade: true,
 			testBody:  p0upgradeK8sVersionCheck,
 			testTitle: "should be able to upgrade k8s version of the cluster",
@@ -73,8 +74,7 @@ var _ = Describe("P0Provisioning", func() {
 					fmt.Println("Skipping downstream cluster deletion: ", clusterName)
 				}
 			})
-			It(testData.testTitle, func() {
-				testCaseID = testData.qaseID
+			It(testData.testTitle, helpers.QaseLabel(testData.qaseID), Label(helpers.CriticalSpec), func() {
 				testData.testBody(cluster, ctx.RancherAdminClient, clusterName)
 			})
 		})
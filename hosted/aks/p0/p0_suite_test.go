@@ -20,10 +20,8 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	. "github.com/rancher-sandbox/qase-ginkgo"
 	"github.com/rancher/shepherd/clients/rancher"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
@@ -37,36 +35,48 @@ var (
 	ctx         helpers.RancherContext
 	cluster     *management.Cluster
 	clusterName string
-	testCaseID  int64
 	location    = helpers.GetAKSLocation()
 )
 
 func TestP0(t *testing.T) {
 	RegisterFailHandler(Fail)
-	RunSpecs(t, "P0 Suite")
+	RunSpecs(t, "P0 Suite", helpers.SuiteConfig(t))
 }
 
-var _ = SynchronizedBeforeSuite(func() []byte {
-	helpers.CommonSynchronizedBeforeSuite()
-	return nil
-}, func() {
-	ctx = helpers.CommonBeforeSuite()
+// The Rancher setup and the cloud credential are shared by the parallel processes; every process provisions its own clusters
+var _ = SynchronizedBeforeSuite(helpers.ParallelSynchronizedBeforeSuite, func(setup []byte) {
+	ctx = helpers.ParallelBeforeSuite(setup)
 })
 
 var _ = BeforeEach(func() {
+	helpers.CollectSpecArtifacts()
+	helpers.RecordClusterTransitions(ctx.RancherAdminClient)
+
 	// Setting this to nil ensures we do not use the `cluster` variable value from another test running in parallel with this one.
 	cluster = nil
-	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+	clusterName = helpers.GenerateClusterName(helpers.ClusterNamePrefix)
 })
 
-var _ = ReportBeforeEach(func(report SpecReport) {
-	// Reset case ID
-	testCaseID = -1
+var _ = JustAfterEach(func() {
+	// Collect the support bundle before AfterEach deletes the cluster
+	helpers.CollectSupportBundleOnFailure(clusterName)
 })
 
+// Delete the clusters leaked by all the processes once they are done
+var _ = SynchronizedAfterSuite(func() {}, helpers.CommonSynchronizedAfterSuite)
+
 var _ = ReportAfterEach(func(report SpecReport) {
 	// Add result in Qase if asked
-	Qase(testCaseID, report)
+	helpers.ReportToQase(report)
+	// Upload the artifacts of the failed spec to ARTIFACTS_BUCKET if set
+	helpers.UploadArtifactsOnFailure(report)
+})
+
+var _ = ReportAfterSuite("Reports", func(report Report) {
+	// Write the JSON, JUnit and summary reports to the artifacts directory
+	helpers.GenerateSuiteReports(report)
+	// Submit the Qase results of all the parallel processes
+	helpers.SubmitQaseResults()
 })
 
 func p0upgradeK8sVersionCheck(cluster *management.Cluster, client *rancher.Client, clusterName string) {
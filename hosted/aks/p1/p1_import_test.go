@@ -3,12 +3,14 @@ package p1_test
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("P1Import", func() {
@@ -51,53 +53,45 @@ var _ = Describe("P1Import", func() {
 			Expect(err).To(BeNil())
 		})
 
-		It("should successfully update with new cloud credentials", func() {
-			testCaseID = 292
+		It("should successfully update with new cloud credentials", helpers.QaseLabel(qasecases.AKSP1UpdateWithNewCloudCredentials292), func() {
 			updateCloudCredentialsCheck(cluster, ctx.RancherAdminClient)
 		})
 
-		It("should fail to update with invalid (deleted) cloud credential and update when the cloud credentials becomes valid", func() {
-			testCaseID = 238
+		It("should fail to update with invalid (deleted) cloud credential and update when the cloud credentials becomes valid", helpers.QaseLabel(qasecases.AKSP1FailUpdateWithInvalidDeletedCloudCredentialAnd238), func() {
 			invalidateCloudCredentialsCheck(cluster, ctx.RancherAdminClient, ctx.CloudCredID)
 		})
 
-		It("should be able to update autoscaling", func() {
-			testCaseID = 266
+		It("should be able to update autoscaling", helpers.QaseLabel(qasecases.AKSP1UpdateAutoscaling266), func() {
 			updateAutoScaling(cluster, ctx.RancherAdminClient)
 		})
 
-		It("should be able to update tags", func() {
-			testCaseID = 270
+		It("should be able to update tags", helpers.QaseLabel(qasecases.AKSP1UpdateTags270), func() {
 			updateTagsCheck(cluster, ctx.RancherAdminClient)
 		})
 
-		It("should fail to change system nodepool count to 0", func() {
-			testCaseID = 290
+		It("should fail to change system nodepool count to 0", helpers.QaseLabel(qasecases.AKSP1FailChangeSystemNodepoolCount0290), func() {
 			updateSystemNodePoolCountToZeroCheck(cluster, ctx.RancherAdminClient)
 		})
 
-		It("should be able to update cluster monitoring", func() {
+		It("should be able to update cluster monitoring", helpers.QaseLabel(qasecases.AKSP1UpdateClusterMonitoring271), func() {
 			if helpers.SkipUpgradeTests {
 				Skip(helpers.SkipUpgradeTestsLog)
 			}
-			testCaseID = 271
 			updateMonitoringCheck(cluster, ctx.RancherAdminClient)
 		})
 
-		It("should fail to reimport an imported cluster", func() {
-			testCaseID = 235
+		It("should fail to reimport an imported cluster", helpers.QaseLabel(qasecases.AKSP1FailReimportImportedCluster), func() {
 			_, err := helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
 			Expect(err).To(HaveOccurred())
 
 			Expect(err.Error()).To(ContainSubstring("cluster already exists for AKS cluster"))
 		})
 
-		It("should be possible to re-import a deleted cluster", func() {
-			testCaseID = 239
+		It("should be possible to re-import a deleted cluster", helpers.QaseLabel(qasecases.AKSP1PossibleReImportDeletedCluster), func() {
 			err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 			clusterID := cluster.ID
-			Eventually(func() error {
+			helpers.EventuallyWithBackoff(func() error {
 				// Wait until the cluster no longer exists
 				_, err := ctx.RancherAdminClient.Management.Cluster.ByID(clusterID)
 				return err
@@ -109,12 +103,11 @@ var _ = Describe("P1Import", func() {
 		})
 	})
 
-	It("should successfully Import a cluster in Region without AZ", func() {
+	It("should successfully Import a cluster in Region without AZ", helpers.QaseLabel(qasecases.AKSP1ImportClusterInRegionWithoutAZ), func() {
 		if helpers.SkipUpgradeTests {
 			Skip(helpers.SkipUpgradeTestsLog)
 		}
-		location = "ukwest"
-		testCaseID = 276
+		location := "ukwest"
 
 		var err error
 		k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, true)
@@ -132,8 +125,20 @@ var _ = Describe("P1Import", func() {
 		noAvailabilityZoneP0Checks(cluster, ctx.RancherAdminClient)
 	})
 
-	It("should be able to register a cluster with no rbac", func() {
-		testCaseID = 237
+	It("should import a cluster with pre-existing workloads and leave them untouched", func() {
+		err := helper.CreateAKSClusterOnAzure(location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+		Expect(err).To(BeNil())
+		Expect(helper.GetAKSCredentialsOnAzure(clusterName, clusterName)).To(Succeed())
+		fingerprints := helpers.DeployPreImportWorkloads(clusterName)
+
+		cluster, err = helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
+		Expect(err).To(BeNil())
+		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+		helpers.CheckPreImportWorkloads(ctx.RancherAdminClient, cluster, fingerprints)
+	})
+
+	It("should be able to register a cluster with no rbac", helpers.QaseLabel(qasecases.AKSP1RegisterClusterWithNoRbac), func() {
 		err := helper.CreateAKSClusterOnAzure(location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels(), "--disable-rbac")
 		Expect(err).To(BeNil())
 
@@ -152,22 +157,20 @@ var _ = Describe("P1Import", func() {
 			}
 
 			kubeletConfigJsonData := `{"cpuManagerPolicy": "static", "cpuCfsQuota": true, "cpuCfsQuotaPeriod": "200ms", "imageGcHighThreshold": 90, "imageGcLowThreshold": 70, "topologyManagerPolicy": "best-effort", "allowedUnsafeSysctls": ["kernel.msg*","net.*"], "failSwapOn": false}`
-			kubeletConfigDotJson, err := os.CreateTemp("", "custom-kubelet-*.json")
-			Expect(err).ToNot(HaveOccurred())
-			err = os.WriteFile(kubeletConfigDotJson.Name(), []byte(kubeletConfigJsonData), 0644)
+			kubeletConfigDotJson := filepath.Join(helpers.CurrentArtifactDir(), "custom-kubelet.json")
+			err := os.WriteFile(kubeletConfigDotJson, []byte(kubeletConfigJsonData), 0644)
 			Expect(err).ToNot(HaveOccurred())
 
 			osConfigJsonData := `{"transparentHugePageEnabled": "madvise", "transparentHugePageDefrag": "defer+madvise", "swapFileSizeMB": 1500, "sysctls": {"netCoreSomaxconn": 163849, "netIpv4TcpTwReuse": true, "netIpv4IpLocalPortRange": "32000 60000"}}`
-			osConfigDotJson, err := os.CreateTemp("", "custom-os-*.json")
-			Expect(err).ToNot(HaveOccurred())
-			err = os.WriteFile(osConfigDotJson.Name(), []byte(osConfigJsonData), 0644)
+			osConfigDotJson := filepath.Join(helpers.CurrentArtifactDir(), "custom-os.json")
+			err = os.WriteFile(osConfigDotJson, []byte(osConfigJsonData), 0644)
 			Expect(err).ToNot(HaveOccurred())
 
 			k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, true)
 			Expect(err).NotTo(HaveOccurred())
 			GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
 
-			err = helper.CreateAKSClusterOnAzure(location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels(), "--kubelet-config", kubeletConfigDotJson.Name(), "--linux-os-config", osConfigDotJson.Name())
+			err = helper.CreateAKSClusterOnAzure(location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels(), "--kubelet-config", kubeletConfigDotJson, "--linux-os-config", osConfigDotJson)
 			Expect(err).To(BeNil())
 			cluster, err = helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
 			Expect(err).To(BeNil())
@@ -179,8 +182,7 @@ var _ = Describe("P1Import", func() {
 			upgradeToVersion = availableVersions[0]
 		})
 
-		It("should successfully upgrade the cluster", func() {
-			testCaseID = 260
+		It("should successfully upgrade the cluster", helpers.QaseLabel(qasecases.AKSP1UpgradeCluster), func() {
 			var err error
 			By("upgrading control plane version", func() {
 				cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, upgradeToVersion, ctx.RancherAdminClient, true)
@@ -209,27 +211,23 @@ var _ = Describe("P1Import", func() {
 			Expect(err).To(BeNil())
 		})
 
-		It("should not be able to remove system nodepool", func() {
+		It("should not be able to remove system nodepool", helpers.QaseLabel(qasecases.AKSP1NotRemoveSystemNodepool267), func() {
 			if helpers.SkipTest {
 				Skip("Skipping test for v2.8, v2.9 ...")
 			}
-			testCaseID = 267
 			removeSystemNpCheck(cluster, ctx.RancherAdminClient)
 		})
 
-		XIt("should to able to delete a nodepool and add a new one", func() {
+		XIt("should to able to delete a nodepool and add a new one", helpers.QaseLabel(qasecases.AKSP1DeleteNodepoolAndAddNewOne), func() {
 			// Blocked by: https://github.com/rancher/aks-operator/issues/667#issuecomment-2370798904
-			testCaseID = 268
 			deleteAndAddNpCheck(cluster, ctx.RancherAdminClient)
 		})
 
-		It("should successfully edit System NodePool", func() {
-			testCaseID = 289
+		It("should successfully edit System NodePool", helpers.QaseLabel(qasecases.AKSP1EditSystemNodePool289), func() {
 			updateSystemNodePoolCheck(cluster, ctx.RancherAdminClient)
 		})
 
-		It("should successfully edit mode of the nodepool", func() {
-			testCaseID = 291
+		It("should successfully edit mode of the nodepool", helpers.QaseLabel(qasecases.AKSP1EditModeOfNodepool291), func() {
 			updateNodePoolModeCheck(cluster, ctx.RancherAdminClient)
 		})
 
@@ -259,15 +257,13 @@ var _ = Describe("P1Import", func() {
 			upgradeK8sVersion = availableVersions[0]
 		})
 
-		It("NP cannot be upgraded to k8s version greater than CP k8s version", func() {
+		It("NP cannot be upgraded to k8s version greater than CP k8s version", helpers.QaseLabel(qasecases.AKSP1NPCannotUpgradedK8sVersionGreaterThanCP269), func() {
 			if helpers.SkipUpgradeTests {
 				Skip(helpers.SkipUpgradeTestsLog)
 			}
-			testCaseID = 269
 			npUpgradeToVersionGTCPCheck(cluster, ctx.RancherAdminClient, upgradeK8sVersion)
 		})
-		It("should Update a cluster when a cluster is in Updating State", func() {
-			testCaseID = 303
+		It("should Update a cluster when a cluster is in Updating State", helpers.QaseLabel(qasecases.AKSP1UpdateClusterWhenClusterIsInUpdatingState303), func() {
 			updateClusterWhenUpdating(cluster, ctx.RancherAdminClient, upgradeK8sVersion)
 		})
 	})
@@ -7,12 +7,11 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
-	"github.com/epinio/epinio/acceptance/helpers/proc"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	"github.com/rancher/shepherd/extensions/clusters"
 	"github.com/rancher/shepherd/extensions/clusters/aks"
 	"github.com/rancher/shepherd/extensions/tokenregistration"
 	namegen "github.com/rancher/shepherd/pkg/namegenerator"
@@ -20,6 +19,8 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
 )
 
 var _ = Describe("P1Provisioning", func() {
@@ -37,7 +38,8 @@ var _ = Describe("P1Provisioning", func() {
 	})
 
 	AfterEach(func() {
-		if ctx.ClusterCleanup && (cluster != nil && cluster.ID != "") {
+		// the recycled cluster is deleted at the end of the suite
+		if ctx.ClusterCleanup && (cluster != nil && cluster.ID != "" && !helpers.IsRecycledCluster(cluster)) {
 			GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
 			err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
@@ -46,12 +48,11 @@ var _ = Describe("P1Provisioning", func() {
 		}
 	})
 
-	It("should successfully Create a cluster in Region without AZ", func() {
+	It("should successfully Create a cluster in Region without AZ", helpers.QaseLabel(qasecases.AKSP1CreateClusterInRegionWithoutAZ), func() {
 		if helpers.SkipUpgradeTests {
 			Skip(helpers.SkipUpgradeTestsLog)
 		}
-		location = "ukwest"
-		testCaseID = 275
+		location := "ukwest"
 
 		var err error
 		k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, true)
@@ -73,8 +74,7 @@ var _ = Describe("P1Provisioning", func() {
 		noAvailabilityZoneP0Checks(cluster, ctx.RancherAdminClient)
 	})
 
-	It("should successfully create cluster with multiple nodepools in multiple AZs", func() {
-		testCaseID = 193
+	It("should successfully create cluster with multiple nodepools in multiple AZs", helpers.QaseLabel(qasecases.AKSP1CreateClusterWithMultipleNodepoolsInMultipleAZs), func() {
 		updateFunc := func(aksConfig *aks.ClusterConfig) {
 			nodepools := *aksConfig.NodePools
 			npTemplate := nodepools[0]
@@ -114,8 +114,7 @@ var _ = Describe("P1Provisioning", func() {
 		}
 	})
 
-	It("should be able to create a cluster with empty tag", func() {
-		testCaseID = 205
+	It("should be able to create a cluster with empty tag", helpers.QaseLabel(qasecases.AKSP1CreateClusterWithEmptyTag), func() {
 		updateFunc := func(aksConfig *aks.ClusterConfig) {
 			aksConfig.Tags["empty-tag"] = ""
 		}
@@ -127,8 +126,8 @@ var _ = Describe("P1Provisioning", func() {
 		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
-		Eventually(func() bool {
-			cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() bool {
+			cluster, err = helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 			Expect(err).NotTo(HaveOccurred())
 			// We wait until sync is complete Ref: https://github.com/rancher/aks-operator/issues/640
 			return len(cluster.AKSConfig.Tags) == len(cluster.AKSStatus.UpstreamSpec.Tags)
@@ -136,9 +135,8 @@ var _ = Describe("P1Provisioning", func() {
 		Expect(cluster.AKSStatus.UpstreamSpec.Tags).To(HaveKeyWithValue("empty-tag", ""))
 	})
 
-	It("should be able to create cluster with container monitoring enabled", func() {
+	It("should be able to create cluster with container monitoring enabled", helpers.QaseLabel(qasecases.AKSP1CreateClusterWithContainerMonitoringEnabled), func() {
 		// Refer: https://github.com/rancher/shepherd/issues/274
-		testCaseID = 199
 		updateFunc := func(aksConfig *aks.ClusterConfig) {
 			aksConfig.Monitoring = pointer.Bool(true)
 		}
@@ -155,9 +153,8 @@ var _ = Describe("P1Provisioning", func() {
 	})
 
 	// TODO: Discuss why only one nodepool is taken into account
-	XIt("updating a cluster while it is still provisioning", func() {
+	XIt("updating a cluster while it is still provisioning", helpers.QaseLabel(qasecases.AKSP1UpdatingClusterWhileItIsStillProvisioning), func() {
 		// Blocked by: https://github.com/rancher/aks-operator/issues/667
-		testCaseID = 222
 		var err error
 		k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, true)
 		Expect(err).NotTo(HaveOccurred())
@@ -166,14 +163,14 @@ var _ = Describe("P1Provisioning", func() {
 		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
 		Expect(err).To(BeNil())
 
-		Eventually(func() string {
-			cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() string {
+			cluster, err = helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 			Expect(err).NotTo(HaveOccurred())
 			return cluster.State
 		}, "1m", "1s").Should(ContainSubstring("provisioning"))
 
 		// Wait until the cluster appears on cloud before updating it
-		Eventually(func() bool {
+		helpers.EventuallyWithBackoff(func() bool {
 			var existsOnCloud bool
 			existsOnCloud, err = helper.ClusterExistsOnAzure(clusterName, clusterName)
 			if err != nil && strings.Contains(err.Error(), "NotFound") {
@@ -201,7 +198,7 @@ var _ = Describe("P1Provisioning", func() {
 		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 
-		err = clusters.WaitClusterToBeUpgraded(ctx.RancherAdminClient, cluster.ID)
+		err = helpers.WaitClusterToBeUpgraded(ctx.RancherAdminClient, cluster.ID)
 		Expect(err).To(BeNil())
 
 		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
@@ -212,8 +209,7 @@ var _ = Describe("P1Provisioning", func() {
 		Expect(cluster.AKSStatus.UpstreamSpec.KubernetesVersion).To(Equal(upgradeK8sVersion))
 	})
 
-	It("create cluster with network policy: calico and plugin: kubenet", func() {
-		testCaseID = 210
+	It("create cluster with network policy: calico and plugin: kubenet", helpers.QaseLabel(qasecases.AKSP1CreateClusterWithNetworkPolicyCalicoAndPlugin), func() {
 		updateFunc := func(aksConfig *aks.ClusterConfig) {
 			aksConfig.NetworkPolicy = pointer.String("calico")
 			aksConfig.NetworkPlugin = pointer.String("kubenet")
@@ -232,9 +228,8 @@ var _ = Describe("P1Provisioning", func() {
 		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
 	})
 
-	XIt("should successfully create cluster with underscore in the name", func() {
+	XIt("should successfully create cluster with underscore in the name", helpers.QaseLabel(qasecases.AKSP1CreateClusterWithUnderscoreInName), func() {
 		// Blocked by https://github.com/rancher/dashboard/issues/9416
-		testCaseID = 261
 		if ctx.ClusterCleanup {
 			clusterName = namegen.AppendRandomString(fmt.Sprintf("%s_hp_ci", helpers.Provider))
 		} else {
@@ -249,8 +244,7 @@ var _ = Describe("P1Provisioning", func() {
 		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
 	})
 
-	It("should successfully create cluster with custom nodepool parameters", func() {
-		testCaseID = 209
+	It("should successfully create cluster with custom nodepool parameters", helpers.QaseLabel(qasecases.AKSP1CreateClusterWithCustomNodepoolParameters), func() {
 		updateFunc := func(aksConfig *aks.ClusterConfig) {
 			nodepools := *aksConfig.NodePools
 			for i := range nodepools {
@@ -277,12 +271,11 @@ var _ = Describe("P1Provisioning", func() {
 	})
 
 	When("a cluster with invalid config is created", func() {
-		It("should fail to create 2 clusters with same name in 2 different resource groups", func() {
-			testCaseID = 217
+		It("should fail to create 2 clusters with same name in 2 different resource groups", helpers.QaseLabel(qasecases.AKSP1FailCreate2ClustersWithSameNameIn), func() {
 			var err error
 			cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
 			Expect(err).To(BeNil())
-			resourceGroup2 := namegen.AppendRandomString(helpers.ClusterNamePrefix)
+			resourceGroup2 := helpers.GenerateClusterName(helpers.ClusterNamePrefix)
 			updateFunc := func(aksConfig *aks.ClusterConfig) {
 				aksConfig.ResourceGroup = resourceGroup2
 			}
@@ -291,8 +284,7 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(err.Error()).To(ContainSubstring("cluster already exists"))
 		})
 
-		It("should fail to create a cluster with 0 nodecount", func() {
-			testCaseID = 186
+		It("should fail to create a cluster with 0 nodecount", helpers.QaseLabel(qasecases.AKSP1FailCreateClusterWith0Nodecount), func() {
 			updateFunc := func(aksConfig *aks.ClusterConfig) {
 				nodepools := *aksConfig.NodePools
 				for i := range nodepools {
@@ -304,25 +296,51 @@ var _ = Describe("P1Provisioning", func() {
 			cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
 			Expect(err).NotTo(HaveOccurred())
 
-			Eventually(func() bool {
-				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			helpers.AllowClusterErrors()
+			helpers.EventuallyWithBackoff(func() bool {
+				cluster, err = helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 				Expect(err).NotTo(HaveOccurred())
 				return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "agentPoolProfile.count was 0. It must be greater or equal to minCount:1 and less than or equal to maxCount:1000")
 			}, "1m", "2s").Should(BeTrue())
 		})
 
-		It("should fail to create a cluster with nil nodepool", func() {
-			testCaseID = 187
+		It("should surface the cloud error when the nodepools exceed the account quota", func() {
+			updateFunc := func(aksConfig *aks.ClusterConfig) {
+				nodepools := *aksConfig.NodePools
+				for i := range nodepools {
+					nodepools[i].NodeCount = pointer.Int64(helpers.QuotaExceededNodeCount)
+					nodepools[i].EnableAutoScaling = pointer.Bool(false)
+					nodepools[i].MinCount, nodepools[i].MaxCount = nil, nil
+				}
+				aksConfig.NodePools = &nodepools
+			}
+			var err error
+			cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
+			Expect(err).NotTo(HaveOccurred())
+			resourceGroup := cluster.AKSConfig.ResourceGroup
+			DeferCleanup(helpers.CleanupFailedCluster, ctx.RancherAdminClient, cluster, helper.DeleteAKSHostCluster, func() (bool, error) {
+				exists, err := helper.ClusterExistsOnAzure(clusterName, resourceGroup)
+				if err != nil && strings.Contains(err.Error(), "not found") {
+					return false, nil
+				}
+				return exists, err
+			})
+
+			cluster = helpers.WaitForQuotaError(ctx.RancherAdminClient, cluster, 10*time.Minute)
+		})
+
+		It("should fail to create a cluster with nil nodepool", helpers.QaseLabel(qasecases.AKSP1FailCreateClusterWithNilNodepool), func() {
 			updateFunc := func(aksConfig *aks.ClusterConfig) {
 				aksConfig.NodePools = nil
 			}
 			var err error
 			cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
 			Expect(err).NotTo(HaveOccurred())
-			Eventually(func() bool {
-				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			helpers.AllowClusterErrors()
+			helpers.EventuallyWithBackoff(func() bool {
+				cluster, err = helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 				Expect(err).NotTo(HaveOccurred())
-				GinkgoLogr.Info(fmt.Sprintf("cluster.Transitioning=%s cluster.TransitioningMessage=%s", cluster.Transitioning, cluster.TransitioningMessage))
+				helpers.ObserveCluster(cluster)
 				return cluster.Transitioning == "error" && cluster.TransitioningMessage == "at least one NodePool with mode System is required"
 			}, "1m", "2s").Should(BeTrue())
 		})
@@ -337,8 +355,7 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(err.Error()).To(ContainSubstring("must have at least one nodepool"))
 		})
 
-		It("should fail to create cluster with Nodepool Max pods per node 9", func() {
-			testCaseID = 203
+		It("should fail to create cluster with Nodepool Max pods per node 9", helpers.QaseLabel(qasecases.AKSP1FailCreateClusterWithNodepoolMaxPodsPer), func() {
 			updateFunc := func(aksConfig *aks.ClusterConfig) {
 				nodepools := *aksConfig.NodePools
 				for i := range nodepools {
@@ -349,8 +366,9 @@ var _ = Describe("P1Provisioning", func() {
 			var err error
 			cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
 			Expect(err).NotTo(HaveOccurred())
-			Eventually(func() bool {
-				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			helpers.AllowClusterErrors()
+			helpers.EventuallyWithBackoff(func() bool {
+				cluster, err = helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 				Expect(err).NotTo(HaveOccurred())
 				return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "InsufficientMaxPods")
 			}, "1m", "2s").Should(BeTrue())
@@ -367,23 +385,16 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
-		It("should successfully update with new cloud credentials", func() {
-			testCaseID = 221
-			updateCloudCredentialsCheck(cluster, ctx.RancherAdminClient)
-		})
-
-		It("should fail to update with invalid (deleted) cloud credential and update when the cloud credentials becomes valid", func() {
-			testCaseID = 299
+		It("should fail to update with invalid (deleted) cloud credential and update when the cloud credentials becomes valid", helpers.QaseLabel(qasecases.AKSP1FailUpdateWithInvalidDeletedCloudCredentialAnd299), func() {
 			invalidateCloudCredentialsCheck(cluster, ctx.RancherAdminClient, ctx.CloudCredID)
 		})
 
-		It("should not be able to edit availability zone of a nodepool", func() {
+		It("should not be able to edit availability zone of a nodepool", helpers.QaseLabel(qasecases.AKSP1NotEditAvailabilityZoneOfNodepool), func() {
 			if helpers.SkipTest {
 				Skip("Skipping test for v2.8, v2.9 ...")
 			}
 
 			// Refer: https://github.com/rancher/aks-operator/issues/669
-			testCaseID = 195
 			originalNPMap := make(map[string][]string)
 			newAZ := []string{"3"}
 			updateFunc := func(cluster *management.Cluster) {
@@ -400,22 +411,22 @@ var _ = Describe("P1Provisioning", func() {
 				Expect(*np.AvailabilityZones).To(Equal(newAZ))
 			}
 
-			Eventually(func() bool {
-				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			helpers.AllowClusterErrors()
+			helpers.EventuallyWithBackoff(func() bool {
+				cluster, err = helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 				Expect(err).NotTo(HaveOccurred())
 				return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "Changing availability zones for node pool") && strings.Contains(cluster.TransitioningMessage, "is not permitted")
 			}, "3m", "3s").Should(BeTrue())
 		})
 
-		It("should not delete the resource group when cluster is deleted", func() {
-			testCaseID = 207
+		It("should not delete the resource group when cluster is deleted", helpers.QaseLabel(qasecases.AKSP1NotDeleteResourceGroupWhenClusterIsDeleted), func() {
 			err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 			// marking as nil so that AfterEach does not raise an error
 			cluster = nil
 
 			// wait until the cluster is deleted from cloud console
-			Eventually(func() (exists bool) {
+			helpers.EventuallyWithBackoff(func() (exists bool) {
 				exists, err = helper.ClusterExistsOnAzure(clusterName, clusterName)
 				Expect(err).To(BeNil())
 				return exists
@@ -423,48 +434,29 @@ var _ = Describe("P1Provisioning", func() {
 
 			// check that the resource group still exists
 			var out string
-			out, err = proc.RunW("az", "group", "show", "--subscription", os.Getenv("AKS_SUBSCRIPTION_ID"), "--name", clusterName)
+			out, err = extcli.Az.Output("group", "show", "--subscription", os.Getenv("AKS_SUBSCRIPTION_ID"), "--name", clusterName)
 			Expect(err).To(BeNil())
 			Expect(out).To(ContainSubstring(fmt.Sprintf("\"name\": \"%s\"", clusterName)))
 		})
 
-		It("should be able to update autoscaling", func() {
-			testCaseID = 176
-			updateAutoScaling(cluster, ctx.RancherAdminClient)
-		})
-
-		It("should be able to update tags", func() {
-			testCaseID = 177
-			updateTagsCheck(cluster, ctx.RancherAdminClient)
-		})
-
-		It("should have cluster monitoring disabled by default", func() {
-			testCaseID = 198
-			Expect(cluster.AKSConfig.Monitoring).To(BeNil())
-			Expect(cluster.AKSStatus.UpstreamSpec.Monitoring).To(BeNil())
-		})
-
-		It("should fail to change system nodepool count to 0", func() {
-			testCaseID = 202
+		It("should fail to change system nodepool count to 0", helpers.QaseLabel(qasecases.AKSP1FailChangeSystemNodepoolCount0202), func() {
 			updateSystemNodePoolCountToZeroCheck(cluster, ctx.RancherAdminClient)
 		})
 
-		It("should be able to update cluster monitoring", func() {
+		It("should be able to update cluster monitoring", helpers.QaseLabel(qasecases.AKSP1UpdateClusterMonitoring200), func() {
 			if helpers.SkipUpgradeTests {
 				Skip(helpers.SkipUpgradeTestsLog)
 			}
-			testCaseID = 200
 			updateMonitoringCheck(cluster, ctx.RancherAdminClient)
 		})
 
-		It("recreating a cluster while it is being deleted should recreate the cluster", func() {
-			testCaseID = 219
+		It("recreating a cluster while it is being deleted should recreate the cluster", helpers.QaseLabel(qasecases.AKSP1RecreatingClusterWhileItIsBeingDeletedRecreate), func() {
 
 			err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 
 			// Wait until the cluster begins deletion process before recreating
-			Eventually(func() bool {
+			helpers.EventuallyWithBackoff(func() bool {
 				exists, err := helper.ClusterExistsOnAzure(clusterName, cluster.AKSConfig.ResourceGroup)
 				Expect(err).To(BeNil())
 				return exists
@@ -474,10 +466,11 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(err).To(BeNil())
 
 			// wait until the error is visible on the provisioned cluster
-			Eventually(func() bool {
-				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			helpers.AllowClusterErrors()
+			helpers.EventuallyWithBackoff(func() bool {
+				cluster, err = helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 				Expect(err).To(BeNil())
-				GinkgoLogr.Info(fmt.Sprintf("cluster.State=%s cluster.Transitioning=%s cluster.TransitioningMessage=%s", cluster.State, cluster.Transitioning, cluster.TransitioningMessage))
+				helpers.ObserveCluster(cluster)
 				return cluster.State == "provisioning" && cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "an AKSClusterConfig exists with the same name")
 			}, "30s", "2s").Should(BeTrue())
 
@@ -485,14 +478,56 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(err).To(BeNil())
 		})
 
+		It("should scale a User mode nodepool to zero and back", func() {
+			scaleUserNodePoolToZeroCheck(cluster, ctx.RancherAdminClient)
+		})
+
+		It("should stop the cluster on Azure and resume its management once started", func() {
+			stopStartCheck(cluster, ctx.RancherAdminClient)
+		})
+	})
+
+	When("a cluster is created and only its config is updated", func() {
+		// these specs only update the config of the cluster, they share it with RECYCLE_CLUSTER
+		BeforeEach(func() {
+			var err error
+			cluster, err = helpers.RecycleCluster(ctx.RancherAdminClient, func() (*management.Cluster, error) {
+				cluster, err := helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
+				if err != nil {
+					return cluster, err
+				}
+				return helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			}, func(cluster *management.Cluster) error {
+				return helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
+			})
+			Expect(err).NotTo(HaveOccurred())
+			// the recycled cluster is not the one named by the suite BeforeEach, for e.g. for the support bundle
+			clusterName = cluster.Name
+		})
+
+		It("should successfully update with new cloud credentials", helpers.QaseLabel(qasecases.AKSP1UpdateWithNewCloudCredentials221), func() {
+			updateCloudCredentialsCheck(cluster, ctx.RancherAdminClient)
+		})
+
+		It("should be able to update autoscaling", helpers.QaseLabel(qasecases.AKSP1UpdateAutoscaling176), func() {
+			updateAutoScaling(cluster, ctx.RancherAdminClient)
+		})
+
+		It("should be able to update tags", helpers.QaseLabel(qasecases.AKSP1UpdateTags177), func() {
+			updateTagsCheck(cluster, ctx.RancherAdminClient)
+		})
+
+		It("should have cluster monitoring disabled by default", helpers.QaseLabel(qasecases.AKSP1HaveClusterMonitoringDisabledByDefault), func() {
+			Expect(cluster.AKSConfig.Monitoring).To(BeNil())
+			Expect(cluster.AKSStatus.UpstreamSpec.Monitoring).To(BeNil())
+		})
 	})
 
 	// Refer: https://github.com/rancher/hosted-providers-e2e/issues/192
-	It("should successfully create 2 clusters in the same RG", func() {
-		testCaseID = 214
+	It("should successfully create 2 clusters in the same RG", helpers.QaseLabel(qasecases.AKSP1Create2ClustersInSameRG), func() {
 
 		// Create the resource group via CLI
-		rgName := namegen.AppendRandomString(helpers.ClusterNamePrefix + "-custom-rg")
+		rgName := helpers.GenerateClusterName(helpers.ClusterNamePrefix + "-custom-rg")
 		err := helper.CreateAKSRGOnAzure(rgName, location)
 		Expect(err).To(BeNil())
 		defer func() {
@@ -510,7 +545,7 @@ var _ = Describe("P1Provisioning", func() {
 			go func() {
 				defer GinkgoRecover()
 				defer wg.Done()
-				clusterName := namegen.AppendRandomString(helpers.ClusterNamePrefix)
+				clusterName := helpers.GenerateClusterName(helpers.ClusterNamePrefix)
 				cluster1, err := helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, updateFunc)
 				if err != nil {
 					Fail(err.Error())
@@ -549,29 +584,30 @@ var _ = Describe("P1Provisioning", func() {
 			upgradeK8sVersion = availableVersions[0]
 		})
 
-		It("NP cannot be upgraded to k8s version greater than CP k8s version", func() {
+		It("NP cannot be upgraded to k8s version greater than CP k8s version", helpers.QaseLabel(qasecases.AKSP1NPCannotUpgradedK8sVersionGreaterThanCP183), func() {
 			if helpers.SkipUpgradeTests {
 				Skip(helpers.SkipUpgradeTestsLog)
 			}
-			testCaseID = 183
 			npUpgradeToVersionGTCPCheck(cluster, ctx.RancherAdminClient, upgradeK8sVersion)
 		})
 
-		XIt("should Update a cluster when a cluster is in Updating State", func() {
+		It("should upgrade the control plane and the nodepools submitted in a single update in order", func() {
+			upgradeCPAndNPAtOnceCheck(cluster, ctx.RancherAdminClient, upgradeK8sVersion)
+		})
+
+		XIt("should Update a cluster when a cluster is in Updating State", helpers.QaseLabel(qasecases.AKSP1UpdateClusterWhenClusterIsInUpdatingState223), func() {
 			// Ref: https://github.com/rancher/aks-operator/issues/826
-			testCaseID = 223
 			updateClusterWhenUpdating(cluster, ctx.RancherAdminClient, upgradeK8sVersion)
 		})
 	})
 
-	It("deleting a cluster while it is in creation state should delete it from rancher and cloud console", func() {
-		testCaseID = 218
+	It("deleting a cluster while it is in creation state should delete it from rancher and cloud console", helpers.QaseLabel(qasecases.AKSP1DeletingClusterWhileItIsInCreationState), func() {
 		var err error
 		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
 		Expect(err).To(BeNil())
 
 		// Wait for the cluster to appear on cloud console before deleting it
-		Eventually(func() bool {
+		helpers.EventuallyWithBackoff(func() bool {
 			exists, err := helper.ClusterExistsOnAzure(clusterName, cluster.AKSConfig.ResourceGroup)
 			// ignore the error that occurs when resource group or cluster could not be found
 			if err != nil {
@@ -589,7 +625,7 @@ var _ = Describe("P1Provisioning", func() {
 		Expect(err).To(BeNil())
 
 		// Wait until the cluster finishes provisioning and then begins deletion process
-		Eventually(func() bool {
+		helpers.EventuallyWithBackoff(func() bool {
 			exists, err := helper.ClusterExistsOnAzure(clusterName, cluster.AKSConfig.ResourceGroup)
 			if err != nil {
 				if strings.Contains(err.Error(), fmt.Sprintf("Resource group '%s' could not be found", cluster.AKSConfig.ResourceGroup)) || strings.Contains(err.Error(), "not found") {
@@ -606,12 +642,11 @@ var _ = Describe("P1Provisioning", func() {
 		Expect(err.Error()).To(ContainSubstring("not found"))
 	})
 
-	It("should not be able to select NP K8s version; CP K8s version should take precedence", func() {
+	It("should not be able to select NP K8s version; CP K8s version should take precedence", helpers.QaseLabel(qasecases.AKSP1NotSelectNPK8sVersionCPK8sVersion), func() {
 		if helpers.SkipUpgradeTests {
 			Skip(helpers.SkipUpgradeTestsLog)
 		}
 
-		testCaseID = 182
 		k8sVersions, err := helper.ListSingleVariantAKSAllVersions(ctx.RancherAdminClient, ctx.CloudCredID, location)
 		Expect(err).To(BeNil())
 		Expect(len(k8sVersions)).To(BeNumerically(">=", 2))
@@ -640,9 +675,9 @@ var _ = Describe("P1Provisioning", func() {
 		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 
-		Eventually(func() bool {
+		helpers.EventuallyForOperation(helpers.OperationUpgrade, func() bool {
 			GinkgoLogr.Info("Waiting for the k8s upgrade to appear in AKSStatus.UpstreamSpec...")
-			clusterState, err := ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			clusterState, err := helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 			Expect(err).To(BeNil())
 			if *clusterState.AKSStatus.UpstreamSpec.KubernetesVersion != cpK8sVersion {
 				return false
@@ -654,13 +689,12 @@ var _ = Describe("P1Provisioning", func() {
 				}
 			}
 			return true
-		}, "5m", "5s").Should(BeTrue(), "Failed while waiting for k8s upgrade.")
+		}, "5m").Should(BeTrue(), "Failed while waiting for k8s upgrade.")
 	})
 
-	It("should Create NP with AZ for region where AZ is not supported", func() {
-		testCaseID = 196
+	It("should Create NP with AZ for region where AZ is not supported", helpers.QaseLabel(qasecases.AKSP1CreateNPWithAZForRegionWhereAZ), func() {
 		// none of the availability zones are supported in this location
-		location = "westus"
+		location := "westus"
 		var err error
 		// re-fetching k8s version based on the location to avoid unsupported k8s version errors
 		k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, false)
@@ -669,8 +703,9 @@ var _ = Describe("P1Provisioning", func() {
 		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
 		Expect(err).ToNot(HaveOccurred())
 
-		Eventually(func() bool {
-			cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+		helpers.AllowClusterErrors()
+		helpers.EventuallyWithBackoff(func() bool {
+			cluster, err = helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 			Expect(err).To(BeNil())
 			return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "Availability zone is not supported in region")
 		}, "2m", "2s").Should(BeTrue(), "Timed out while waiting for cluster to error out")
@@ -710,36 +745,30 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(err).To(BeNil())
 		})
 
-		It("should successfully create the cluster", func() {
-			testCaseID = 189
+		It("should successfully create the cluster", helpers.QaseLabel(qasecases.AKSP1CreateCluster), func() {
 			helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
 
 			Expect(len(*cluster.AKSConfig.NodePools)).To(Equal(2))
 			Expect(len(*cluster.AKSStatus.UpstreamSpec.NodePools)).To(Equal(2))
 		})
 
-		XIt("should to able to delete a nodepool and add a new one with different availability zone", func() {
+		XIt("should to able to delete a nodepool and add a new one with different availability zone", helpers.QaseLabel(qasecases.AKSP1DeleteNodepoolAndAddNewOneWithDifferent190, qasecases.AKSP1DeleteNodepoolAndAddNewOneWithDifferent194), func() {
 			// Blocked by: https://github.com/rancher/aks-operator/issues/667#issuecomment-2370798904
-			testCaseID = 190
-			// also covers testCaseID = 194
 			deleteAndAddNpCheck(cluster, ctx.RancherAdminClient)
 		})
 
-		It("should not be able to remove system nodepool", func() {
+		It("should not be able to remove system nodepool", helpers.QaseLabel(qasecases.AKSP1NotRemoveSystemNodepool191), func() {
 			if helpers.SkipTest {
 				Skip("Skipping test for v2.8, v2.9 ...")
 			}
-			testCaseID = 191
 			removeSystemNpCheck(cluster, ctx.RancherAdminClient)
 		})
 
-		It("should successfully edit System NodePool", func() {
-			testCaseID = 204
+		It("should successfully edit System NodePool", helpers.QaseLabel(qasecases.AKSP1EditSystemNodePool204), func() {
 			updateSystemNodePoolCheck(cluster, ctx.RancherAdminClient)
 		})
 
-		It("should successfully edit mode of the nodepool", func() {
-			testCaseID = 230
+		It("should successfully edit mode of the nodepool", helpers.QaseLabel(qasecases.AKSP1EditModeOfNodepool230), func() {
 			updateNodePoolModeCheck(cluster, ctx.RancherAdminClient)
 		})
 	})
@@ -756,35 +785,34 @@ var _ = Describe("P1Provisioning", func() {
 
 		for _, data := range []struct {
 			networkPlugin, networkPolicy, vnet string
-			testCaseID                         int64
+			testCaseIDs                        []qasecases.CaseID
 		}{
 			{
 				networkPlugin: kubenetPlugin,
 				networkPolicy: calicoPolicy,
-				testCaseID:    210,
+				testCaseIDs:   []qasecases.CaseID{qasecases.AKSP1CreateClusterWithNetworkPolicyCalicoAndPlugin},
 			},
 			{
 				networkPlugin: azure,
 				networkPolicy: calicoPolicy,
 				vnet:          vnet,
-				testCaseID:    211,
+				testCaseIDs:   []qasecases.CaseID{qasecases.AKSP1CreateClusterWithNetworkPolicyCalicoPolicyNetworkPluginAzure},
 			},
 			{
 				networkPlugin: azure,
 				networkPolicy: none,
-				testCaseID:    212,
+				testCaseIDs:   []qasecases.CaseID{qasecases.AKSP1CreateClusterWithNetworkPolicyNoneNetworkPluginAzure},
 				vnet:          vnet,
 			},
 			{
 				networkPlugin: azure,
 				networkPolicy: azure,
 				vnet:          vnet,
-				testCaseID:    213,
+				// No Qase case covers the azure policy yet, so its results are not reported
 			},
 		} {
 			data := data
-			It(fmt.Sprintf("Create cluster with NetworkPolicy %s & Network plugin %s", data.networkPolicy, data.networkPlugin), func() {
-				testCaseID = data.testCaseID
+			It(fmt.Sprintf("Create cluster with NetworkPolicy %s & Network plugin %s", data.networkPolicy, data.networkPlugin), helpers.QaseLabel(data.testCaseIDs...), func() {
 				createFunc := func(clusterConfig *aks.ClusterConfig) {
 					clusterConfig.NetworkPlugin = &data.networkPlugin
 					if data.networkPolicy != none {
@@ -821,8 +849,9 @@ var _ = Describe("P1Provisioning", func() {
 			cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, createFunc)
 			Expect(err).ToNot(HaveOccurred())
 
-			Eventually(func() bool {
-				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			helpers.AllowClusterErrors()
+			helpers.EventuallyWithBackoff(func() bool {
+				cluster, err = helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 				Expect(err).To(BeNil())
 				return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "failed to communicate with cluster: error generating service account token") && strings.Contains(cluster.TransitioningMessage, "cluster agent disconnected")
 			}, "12m", "10s").Should(BeTrue(), "Timed out while waiting for cluster to be ready for registration")
@@ -838,8 +867,7 @@ var _ = Describe("P1Provisioning", func() {
 			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 		})
-		It("should successfully Create a private cluster", func() {
-			testCaseID = 240 // 241, 242
+		It("should successfully Create a private cluster", helpers.QaseLabel(qasecases.AKSP1CreatePrivateCluster240, qasecases.AKSP1CreatePrivateCluster241, qasecases.AKSP1CreatePrivateCluster242), func() {
 			helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
 
 			availableVersions, err := helper.ListAKSAvailableVersions(ctx.RancherAdminClient, cluster.ID)
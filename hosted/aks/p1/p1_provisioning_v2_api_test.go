@@ -0,0 +1,47 @@
+package p1_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("P1V2ProvisioningAPI", func() {
+	BeforeEach(func() {
+		k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, false)
+		Expect(err).To(BeNil())
+		GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s", k8sVersion, clusterName))
+
+		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
+		Expect(err).To(BeNil())
+		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		if ctx.ClusterCleanup && (cluster != nil && cluster.ID != "") {
+			GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
+			err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+		} else {
+			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
+		}
+	})
+
+	It("should read the cluster through the provisioning API", func() {
+		helpers.CheckProvisioningCluster(ctx.RancherAdminClient, cluster)
+	})
+
+	It("should delete the cluster through the provisioning API", func() {
+		helpers.CheckProvisioningCluster(ctx.RancherAdminClient, cluster)
+		helpers.DeleteProvisioningCluster(ctx.RancherAdminClient, cluster, func() (bool, error) {
+			return helper.ClusterExistsOnAzure(clusterName, clusterName)
+		})
+		// marking as nil so that AfterEach does not delete it again
+		cluster = nil
+	})
+})
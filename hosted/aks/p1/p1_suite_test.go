@@ -4,10 +4,10 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	. "github.com/rancher-sandbox/qase-ginkgo"
 	"github.com/rancher/shepherd/clients/rancher"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
 	"github.com/rancher/shepherd/extensions/clusters"
@@ -22,36 +22,48 @@ var (
 	ctx                   helpers.RancherContext
 	cluster               *management.Cluster
 	clusterName, location string
-	testCaseID            int64
 )
 
 func TestP1(t *testing.T) {
 	RegisterFailHandler(Fail)
-	RunSpecs(t, "P1 Suite")
+	RunSpecs(t, "P1 Suite", helpers.SuiteConfig(t))
 }
 
-var _ = SynchronizedBeforeSuite(func() []byte {
-	helpers.CommonSynchronizedBeforeSuite()
-	return nil
-}, func() {
-	ctx = helpers.CommonBeforeSuite()
+// The Rancher setup and the cloud credential are shared by the parallel processes; every process provisions its own clusters
+var _ = SynchronizedBeforeSuite(helpers.ParallelSynchronizedBeforeSuite, func(setup []byte) {
+	ctx = helpers.ParallelBeforeSuite(setup)
 })
 
 var _ = BeforeEach(func() {
+	helpers.CollectSpecArtifacts()
+	helpers.RecordClusterTransitions(ctx.RancherAdminClient)
+
 	// Setting this to nil ensures we do not use the `cluster` variable value from another test running in parallel with this one.
 	cluster = nil
-	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+	clusterName = helpers.GenerateClusterName(helpers.ClusterNamePrefix)
 	location = helpers.GetAKSLocation()
 })
 
-var _ = ReportBeforeEach(func(report SpecReport) {
-	// Reset case ID
-	testCaseID = -1
+var _ = JustAfterEach(func() {
+	// Collect the support bundle before AfterEach deletes the cluster
+	helpers.CollectSupportBundleOnFailure(clusterName)
 })
 
+// Delete the cluster shared by the specs with RECYCLE_CLUSTER, if any, then the clusters leaked by all the processes
+var _ = SynchronizedAfterSuite(helpers.DeleteRecycledCluster, helpers.CommonSynchronizedAfterSuite)
+
 var _ = ReportAfterEach(func(report SpecReport) {
 	// Add result in Qase if asked
-	Qase(testCaseID, report)
+	helpers.ReportToQase(report)
+	// Upload the artifacts of the failed spec to ARTIFACTS_BUCKET if set
+	helpers.UploadArtifactsOnFailure(report)
+})
+
+var _ = ReportAfterSuite("Reports", func(report Report) {
+	// Write the JSON, JUnit and summary reports to the artifacts directory
+	helpers.GenerateSuiteReports(report)
+	// Submit the Qase results of all the parallel processes
+	helpers.SubmitQaseResults()
 })
 
 // updateAutoScaling tests updating `autoscaling` for AKS node pools
@@ -84,8 +96,9 @@ func removeSystemNpCheck(cluster *management.Cluster, client *rancher.Client) {
 	var err error
 	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
 	Expect(err).To(BeNil())
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+	helpers.AllowClusterErrors()
+	helpers.EventuallyWithBackoff(func() bool {
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).To(BeNil())
 		return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "cannot remove node pool") && strings.Contains(cluster.TransitioningMessage, "with mode System from cluster")
 	}, "5m", "5s").Should(BeTrue())
@@ -134,11 +147,11 @@ func deleteAndAddNpCheck(cluster *management.Cluster, client *rancher.Client) {
 	Expect(npAdded).To(BeTrue())
 	Expect(npDeleted).To(BeTrue())
 	Expect(len(*cluster.AKSConfig.NodePools)).To(BeEquivalentTo(originalLen))
-	err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+	err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 	Expect(err).To(BeNil())
 
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+	helpers.EventuallyWithBackoff(func() bool {
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).To(BeNil())
 		if len(*cluster.AKSStatus.UpstreamSpec.NodePools) != originalLen {
 			return false
@@ -169,8 +182,9 @@ func npUpgradeToVersionGTCPCheck(cluster *management.Cluster, client *rancher.Cl
 	var err error
 	cluster, err = helper.UpgradeNodeKubernetesVersion(cluster, upgradeK8sVersion, client, false, false)
 	Expect(err).To(BeNil())
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+	helpers.AllowClusterErrors()
+	helpers.EventuallyWithBackoff(func() bool {
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).NotTo(HaveOccurred())
 		return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, fmt.Sprintf("Node pool version %s and control plane version %s are incompatible.", upgradeK8sVersion, k8sVersion))
 	}, "1m", "2s").Should(BeTrue())
@@ -188,11 +202,11 @@ func updateClusterWhenUpdating(cluster *management.Cluster, client *rancher.Clie
 	cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, upgradeK8sVersion, client, true)
 	Expect(err).To(BeNil())
 
-	err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+	err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 	Expect(err).To(BeNil())
 
-	Eventually(func() int {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+	helpers.EventuallyWithBackoff(func() int {
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).NotTo(HaveOccurred())
 		return len(*cluster.AKSStatus.UpstreamSpec.NodePools)
 	}, "10m", "5s").Should(BeEquivalentTo(initialNPLength + 1))
@@ -213,9 +227,9 @@ func updateTagsCheck(cluster *management.Cluster, client *rancher.Client) {
 		Expect(cluster.AKSConfig.Tags).To(HaveKeyWithValue("empty-tag", ""))
 		Expect(cluster.AKSConfig.Tags).To(HaveKeyWithValue("new", "tag"))
 
-		Eventually(func() int {
+		helpers.EventuallyWithBackoff(func() int {
 			GinkgoLogr.Info("Waiting for the tags to be added ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 
 			var count int
@@ -240,9 +254,9 @@ func updateTagsCheck(cluster *management.Cluster, client *rancher.Client) {
 		Expect(cluster.AKSConfig.Tags).ToNot(HaveKeyWithValue("empty-tag", ""))
 		Expect(cluster.AKSConfig.Tags).ToNot(HaveKeyWithValue("new", "tag"))
 
-		Eventually(func() int {
+		helpers.EventuallyWithBackoff(func() int {
 			GinkgoLogr.Info("Waiting for the tags to be removed ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			var count int
 			for key, value := range cluster.AKSStatus.UpstreamSpec.Tags {
@@ -265,8 +279,8 @@ func updateMonitoringCheck(cluster *management.Cluster, client *rancher.Client)
 		cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
 		Expect(err).To(BeNil())
 		Expect(*cluster.AKSConfig.Monitoring).To(BeTrue())
-		Eventually(func() bool {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() bool {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			return cluster.AKSStatus.UpstreamSpec.Monitoring != nil && *cluster.AKSStatus.UpstreamSpec.Monitoring
 		}, "7m", "5s").Should(BeTrue())
@@ -281,8 +295,8 @@ func updateMonitoringCheck(cluster *management.Cluster, client *rancher.Client)
 		Expect(err).To(BeNil())
 
 		Expect(*cluster.AKSConfig.Monitoring).To(BeFalse())
-		Eventually(func() bool {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() bool {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			return cluster.AKSStatus.UpstreamSpec.Monitoring != nil && *cluster.AKSStatus.UpstreamSpec.Monitoring
 		}, "7m", "5s").Should(BeFalse())
@@ -304,10 +318,11 @@ func updateSystemNodePoolCountToZeroCheck(cluster *management.Cluster, client *r
 	var err error
 	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
 	Expect(err).ToNot(HaveOccurred())
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+	helpers.AllowClusterErrors()
+	helpers.EventuallyWithBackoff(func() bool {
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).NotTo(HaveOccurred())
-		GinkgoLogr.Info(fmt.Sprintf("cluster.Transitioning=%s cluster.TransitioningMessage=%s", cluster.Transitioning, cluster.TransitioningMessage))
+		helpers.ObserveCluster(cluster)
 		return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "It must be greater or equal to minCount:1 and less than or equal to maxCount:1000")
 	}, "1m", "2s").Should(BeTrue())
 }
@@ -346,11 +361,11 @@ func updateSystemNodePoolCheck(cluster *management.Cluster, client *rancher.Clie
 		}
 	}
 
-	err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+	err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 	Expect(err).To(BeNil())
 
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+	helpers.EventuallyWithBackoff(func() bool {
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).To(BeNil())
 		for _, np := range *cluster.AKSStatus.UpstreamSpec.NodePools {
 			if np.Mode == systemMode {
@@ -386,8 +401,8 @@ func updateNodePoolModeCheck(cluster *management.Cluster, client *rancher.Client
 	err = clusters.WaitClusterUntilUpgrade(client, cluster.ID)
 	Expect(err).To(BeNil())
 
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+	helpers.EventuallyWithBackoff(func() bool {
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).NotTo(HaveOccurred())
 		for _, np := range *cluster.AKSStatus.UpstreamSpec.NodePools {
 			if np.Mode == originalModeMap[*np.Name] {
@@ -408,8 +423,8 @@ func updateCloudCredentialsCheck(cluster *management.Cluster, client *rancher.Cl
 	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
 	Expect(err).To(BeNil())
 	Expect(cluster.AKSConfig.AzureCredentialSecret).To(Equal(newCCID))
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+	helpers.EventuallyWithBackoff(func() bool {
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).NotTo(HaveOccurred())
 		return cluster.AKSStatus.UpstreamSpec.AzureCredentialSecret == newCCID
 	}, "5m", "5s").Should(BeTrue(), "Failed while upstream cloud credentials update")
@@ -426,8 +441,8 @@ func syncAddNodePoolFromAzureAndRancher(cluster *management.Cluster, client *ran
 		err := helper.AddNodePoolOnAzure(npAzure, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup, "2")
 		Expect(err).To(BeNil())
 
-		Eventually(func() bool {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyForOperation(helpers.OperationAddNodePool, func() bool {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).NotTo(HaveOccurred())
 			for _, nodePool := range *cluster.AKSStatus.UpstreamSpec.NodePools {
 				if *nodePool.Name == npAzure {
@@ -435,7 +450,7 @@ func syncAddNodePoolFromAzureAndRancher(cluster *management.Cluster, client *ran
 				}
 			}
 			return false
-		}, "5m", "5s").Should(BeTrue(), "Timed out while waiting for sync from Azure")
+		}, "5m").Should(BeTrue(), "Timed out while waiting for sync from Azure")
 
 		if !helpers.IsImport {
 			// skip this check if the cluster is imported since the AKSConfig value will not be updated
@@ -459,11 +474,11 @@ func upgradeCPK8sFromAzureAndNPFromRancherCheck(cluster *management.Cluster, cli
 	By("upgrading control plane k8s version from Azure", func() {
 		err := helper.UpgradeAKSOnAzure(clusterName, cluster.AKSConfig.ResourceGroup, upgradeToVersion, "--control-plane-only")
 		Expect(err).To(BeNil())
-		Eventually(func() bool {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyForOperation(helpers.OperationUpgrade, func() bool {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).NotTo(HaveOccurred())
 			return *cluster.AKSStatus.UpstreamSpec.KubernetesVersion == upgradeToVersion
-		}, "5m", "5s").Should(BeTrue(), "Timed out while waiting for upgrade to appear in UpstreamSpec")
+		}, "5m").Should(BeTrue(), "Timed out while waiting for upgrade to appear in UpstreamSpec")
 
 		for _, nodepool := range *cluster.AKSStatus.UpstreamSpec.NodePools {
 			// NodePool version must remain the same
@@ -533,10 +548,10 @@ func noAvailabilityZoneP0Checks(cluster *management.Cluster, client *rancher.Cli
 		cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
 		Expect(err).To(BeNil())
 		Expect(len(*cluster.AKSConfig.NodePools)).Should(BeNumerically("==", initialNPCount+1))
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+		err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 		Expect(err).To(BeNil())
-		Eventually(func() int {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() int {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			return len(*cluster.AKSStatus.UpstreamSpec.NodePools)
 		}, "5m", "5s").Should(BeNumerically("==", initialNPCount+1))
@@ -564,8 +579,8 @@ func invalidateCloudCredentialsCheck(cluster *management.Cluster, client *ranche
 	const scaleCount int64 = 2
 	cluster, err = helper.ScaleNodePool(cluster, client, scaleCount, false, false)
 	Expect(err).To(BeNil())
-	Eventually(func() string {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+	helpers.EventuallyWithBackoff(func() string {
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).NotTo(HaveOccurred())
 		return cluster.Transitioning
 	}, "3m", "2s").Should(Equal("error"), "Timed out waiting for cluster to transition into error")
@@ -579,10 +594,10 @@ func invalidateCloudCredentialsCheck(cluster *management.Cluster, client *ranche
 	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
 	Expect(err).To(BeNil())
 	Expect(cluster.AKSConfig.AzureCredentialSecret).To(Equal(newCCID))
-	err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+	err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 	Expect(err).To(BeNil())
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+	helpers.EventuallyWithBackoff(func() bool {
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).NotTo(HaveOccurred())
 		return cluster.AKSStatus.UpstreamSpec.AzureCredentialSecret == newCCID
 	}, "5m", "5s").Should(BeTrue())
@@ -592,8 +607,8 @@ func invalidateCloudCredentialsCheck(cluster *management.Cluster, client *ranche
 	}
 
 	// This is sometimes flaky, so using Eventually
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+	helpers.EventuallyForOperation(helpers.OperationScale, func() bool {
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).NotTo(HaveOccurred())
 		for _, nodepool := range *cluster.AKSStatus.UpstreamSpec.NodePools {
 			if *nodepool.Count != scaleCount {
@@ -601,7 +616,7 @@ func invalidateCloudCredentialsCheck(cluster *management.Cluster, client *ranche
 			}
 		}
 		return true
-	}, "5m", "5s").Should(BeTrue(), "Timed out waiting for upstream spec to reflect node count")
+	}, "5m").Should(BeTrue(), "Timed out waiting for upstream spec to reflect node count")
 
 	// Update the context so that any future tests are not disrupted
 	GinkgoLogr.Info(fmt.Sprintf("Updating the new Cloud Credentials %s to the context", newCCID))
@@ -614,8 +629,8 @@ func azureSyncCheck(cluster *management.Cluster, client *rancher.Client, upgrade
 		err := helper.UpgradeAKSOnAzure(cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup, upgradeToVersion)
 		Expect(err).To(BeNil())
 
-		Eventually(func() bool {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() bool {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).NotTo(HaveOccurred())
 			allUpgraded := *cluster.AKSStatus.UpstreamSpec.KubernetesVersion == upgradeToVersion
 			if !allUpgraded {
@@ -648,8 +663,8 @@ func azureSyncCheck(cluster *management.Cluster, client *rancher.Client, upgrade
 	By("Adding a nodepool", func() {
 		err := helper.AddNodePoolOnAzure(npName, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup, fmt.Sprint(nodeCount))
 		Expect(err).To(BeNil())
-		Eventually(func() bool {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() bool {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).NotTo(HaveOccurred())
 			if len(*cluster.AKSStatus.UpstreamSpec.NodePools) == currentNPCount {
 				// Return early if the nodepool count hasn't changed.
@@ -673,8 +688,8 @@ func azureSyncCheck(cluster *management.Cluster, client *rancher.Client, upgrade
 		const scaleCount = nodeCount + 2
 		err := helper.ScaleNodePoolOnAzure(npName, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup, fmt.Sprint(scaleCount))
 		Expect(err).To(BeNil())
-		Eventually(func() bool {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() bool {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).NotTo(HaveOccurred())
 			for _, nodepool := range *cluster.AKSStatus.UpstreamSpec.NodePools {
 				if *nodepool.Name == npName {
@@ -697,8 +712,8 @@ func azureSyncCheck(cluster *management.Cluster, client *rancher.Client, upgrade
 	By("Deleting a nodepool", func() {
 		err := helper.DeleteNodePoolOnAzure(npName, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup)
 		Expect(err).To(BeNil())
-		Eventually(func() bool {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() bool {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).NotTo(HaveOccurred())
 			if len(*cluster.AKSStatus.UpstreamSpec.NodePools) != currentNPCount {
 				// Return early if the nodepool count is not back to its original state
@@ -730,8 +745,8 @@ func azureSyncCheck(cluster *management.Cluster, client *rancher.Client, upgrade
 
 		err := helper.UpdateClusterTagOnAzure(updatedTags, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup)
 		Expect(err).To(BeNil())
-		Eventually(func() bool {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() bool {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).NotTo(HaveOccurred())
 			return len(cluster.AKSStatus.UpstreamSpec.Tags) == len(updatedTags)
 		}, "7m", "10s").Should(BeTrue(), "Timed out while waiting for tags addition to appear in UpstreamSpec...")
@@ -751,8 +766,8 @@ func azureSyncCheck(cluster *management.Cluster, client *rancher.Client, upgrade
 	By("Removing tags from cluster", func() {
 		err := helper.UpdateClusterTagOnAzure(originalTags, cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup)
 		Expect(err).To(BeNil())
-		Eventually(func() bool {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() bool {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).NotTo(HaveOccurred())
 			return len(cluster.AKSStatus.UpstreamSpec.Tags) == len(originalTags)
 		}, "7m", "10s").Should(BeTrue(), "Timed out while waiting for tags deletion to appear in UpstreamSpec...")
@@ -769,3 +784,114 @@ func azureSyncCheck(cluster *management.Cluster, client *rancher.Client, upgrade
 		}
 	})
 }
+
+// scaleUserNodePoolToZeroCheck adds a User mode nodepool, scales it to zero and back; the System mode nodepools can not be scaled to zero,
+// so the cluster keeps the nodes running its agent and must remain active while the User mode nodepool has no node
+func scaleUserNodePoolToZeroCheck(cluster *management.Cluster, client *rancher.Client) {
+	userPoolName := fmt.Sprintf("zeropool%s", namegen.RandStringLower(3))
+	var systemNodeCount int64
+	for _, np := range *cluster.AKSConfig.NodePools {
+		systemNodeCount += *np.Count
+	}
+
+	setUserPoolCount := func(count int64) {
+		start := time.Now()
+		var err error
+		cluster, err = helper.UpdateCluster(cluster, client, func(cluster *management.Cluster) {
+			nodePools := *cluster.AKSConfig.NodePools
+			for i := range nodePools {
+				if *nodePools[i].Name == userPoolName {
+					nodePools[i].Count = pointer.Int64(count)
+				}
+			}
+		})
+		Expect(err).To(BeNil())
+
+		cluster, err = helpers.WaitForUpstreamField(client, cluster.ID, func(c *management.Cluster) any {
+			for _, np := range *c.AKSStatus.UpstreamSpec.NodePools {
+				if *np.Name == userPoolName {
+					return *np.Count
+				}
+			}
+			return int64(-1)
+		}, count, 15*time.Minute)
+		Expect(err).To(BeNil())
+		cluster, err = helpers.WaitUntilClusterIsReady(cluster, client)
+		Expect(err).To(BeNil())
+		helpers.WaitUntilNodeCount(client, cluster, int(systemNodeCount+count), start)
+	}
+
+	By(fmt.Sprintf("adding User mode nodepool %s", userPoolName), func() {
+		start := time.Now()
+		var err error
+		cluster, err = helper.UpdateCluster(cluster, client, func(cluster *management.Cluster) {
+			userPool := (*cluster.AKSConfig.NodePools)[0]
+			userPool.Name = pointer.String(userPoolName)
+			userPool.Mode = "User"
+			userPool.Count = pointer.Int64(1)
+			userPool.EnableAutoScaling = pointer.Bool(false)
+			userPool.MinCount, userPool.MaxCount = nil, nil
+			nodePools := append(*cluster.AKSConfig.NodePools, userPool)
+			cluster.AKSConfig.NodePools = &nodePools
+		})
+		Expect(err).To(BeNil())
+		Expect(helpers.WaitClusterToBeUpgraded(client, cluster.ID)).To(Succeed())
+		helpers.WaitUntilNodeCount(client, cluster, int(systemNodeCount+1), start)
+	})
+
+	By(fmt.Sprintf("scaling User mode nodepool %s to zero", userPoolName), func() {
+		setUserPoolCount(0)
+	})
+
+	By(fmt.Sprintf("scaling User mode nodepool %s back up", userPoolName), func() {
+		setUserPoolCount(1)
+	})
+	helpers.ClusterIsReadyChecks(cluster, client, cluster.Name)
+}
+
+// stopStartCheck hibernates the cluster by stopping it on Azure, checks that Rancher handles the stopped cluster,
+// then starts it again and checks that Rancher resumes the management of the cluster
+func stopStartCheck(cluster *management.Cluster, client *rancher.Client) {
+	var nodeCount int64
+	for _, np := range *cluster.AKSConfig.NodePools {
+		nodeCount += *np.Count
+	}
+
+	hibernated := time.Now()
+	By("stopping the cluster on Azure", func() {
+		err := helper.StopAKSOnAzure(cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup)
+		Expect(err).To(BeNil())
+		cluster = helpers.CheckClusterHibernated(client, cluster)
+	})
+
+	By("starting the cluster on Azure", func() {
+		start := time.Now()
+		err := helper.StartAKSOnAzure(cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup)
+		Expect(err).To(BeNil())
+		cluster = helpers.CheckClusterResumed(client, cluster, hibernated)
+		helpers.WaitUntilNodeCount(client, cluster, int(nodeCount), start)
+	})
+	helpers.ClusterIsReadyChecks(cluster, client, cluster.Name)
+}
+
+// upgradeCPAndNPAtOnceCheck upgrades the control plane and the nodepools in a single cluster update, and checks that the operator upgrades them in order
+func upgradeCPAndNPAtOnceCheck(cluster *management.Cluster, client *rancher.Client, upgradeToVersion string) {
+	By("upgrading the ControlPlane & NodePools in a single update", func() {
+		var err error
+		cluster, err = helper.UpdateCluster(cluster, client, func(cluster *management.Cluster) {
+			cluster.AKSConfig.KubernetesVersion = &upgradeToVersion
+			nodePools := *cluster.AKSConfig.NodePools
+			for i := range nodePools {
+				nodePools[i].OrchestratorVersion = &upgradeToVersion
+			}
+		})
+		Expect(err).To(BeNil())
+		Expect(*cluster.AKSConfig.KubernetesVersion).To(Equal(upgradeToVersion))
+		for _, np := range *cluster.AKSConfig.NodePools {
+			Expect(*np.OrchestratorVersion).To(Equal(upgradeToVersion))
+		}
+	})
+
+	cluster = helpers.WaitForOrderedUpgrade(client, cluster, upgradeToVersion)
+	helpers.ClusterIsReadyChecks(cluster, client, cluster.Name)
+}
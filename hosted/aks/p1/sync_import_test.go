@@ -8,6 +8,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("SyncImport", func() {
@@ -47,8 +48,7 @@ var _ = Describe("SyncImport", func() {
 			Expect(err).To(BeNil())
 		})
 
-		It("should successfully Add NP from Azure and then from Rancher", func() {
-			testCaseID = 293
+		It("should successfully Add NP from Azure and then from Rancher", helpers.QaseLabel(qasecases.AKSP1AddNPFromAzureAndThenFromRancher293), func() {
 			syncAddNodePoolFromAzureAndRancher(cluster, ctx.RancherAdminClient)
 		})
 	})
@@ -77,13 +77,11 @@ var _ = Describe("SyncImport", func() {
 			Expect(err).To(BeNil())
 		})
 
-		It("should successfully Change k8s version from Azure should change the CP k8s version and list of available version for NPs", func() {
-			testCaseID = 294
+		It("should successfully Change k8s version from Azure should change the CP k8s version and list of available version for NPs", helpers.QaseLabel(qasecases.AKSP1ChangeK8sVersionFromAzureChangeCPK8s294), func() {
 			upgradeCPK8sFromAzureAndNPFromRancherCheck(cluster, ctx.RancherAdminClient, k8sVersion, availableUpgradeVersions[0])
 		})
 
-		It("should sync changes from Azure console back to Rancher", func() {
-			testCaseID = 233
+		It("should sync changes from Azure console back to Rancher", helpers.QaseLabel(qasecases.AKSP1SyncChangesFromAzureConsoleBackRancher233), func() {
 			azureSyncCheck(cluster, ctx.RancherAdminClient, availableUpgradeVersions[0])
 		})
 	})
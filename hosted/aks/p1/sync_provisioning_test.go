@@ -8,6 +8,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("SyncProvisioning", func() {
@@ -40,8 +41,7 @@ var _ = Describe("SyncProvisioning", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
-		It("should successfully Add NP from Azure and then from Rancher", func() {
-			testCaseID = 224
+		It("should successfully Add NP from Azure and then from Rancher", helpers.QaseLabel(qasecases.AKSP1AddNPFromAzureAndThenFromRancher224), func() {
 			syncAddNodePoolFromAzureAndRancher(cluster, ctx.RancherAdminClient)
 		})
 	})
@@ -68,13 +68,11 @@ var _ = Describe("SyncProvisioning", func() {
 			Expect(err).To(BeNil())
 		})
 
-		It("should successfully Change k8s version from Azure should change the CP k8s version and list of available version for NPs", func() {
-			testCaseID = 225
+		It("should successfully Change k8s version from Azure should change the CP k8s version and list of available version for NPs", helpers.QaseLabel(qasecases.AKSP1ChangeK8sVersionFromAzureChangeCPK8s225), func() {
 			upgradeCPK8sFromAzureAndNPFromRancherCheck(cluster, ctx.RancherAdminClient, k8sVersion, availableUpgradeVersions[0])
 		})
 
-		It("should sync changes from Azure console back to Rancher", func() {
-			testCaseID = 302
+		It("should sync changes from Azure console back to Rancher", helpers.QaseLabel(qasecases.AKSP1SyncChangesFromAzureConsoleBackRancher302), func() {
 			azureSyncCheck(cluster, ctx.RancherAdminClient, availableUpgradeVersions[0])
 		})
 	})
@@ -0,0 +1,90 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package private_endpoint_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/clusters/aks"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("PrivateEndpoint", func() {
+	var upgradeToVersion string
+
+	BeforeEach(func() {
+		k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, !helpers.SkipUpgradeTests)
+		Expect(err).To(BeNil())
+		if !helpers.SkipUpgradeTests {
+			upgradeToVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, false)
+			Expect(err).To(BeNil())
+		}
+		GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s", k8sVersion, clusterName))
+
+		// the cluster agent is deployed through the public endpoint, which is only authorized for Rancher until the cluster is registered;
+		// the registration of private clusters is blocked on https://github.com/rancher/rancher/issues/43772
+		createFunc := func(clusterConfig *aks.ClusterConfig) {
+			clusterConfig.AuthorizedIPRanges = &[]string{helpers.GetRancherIP() + "/32"}
+		}
+		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, createFunc)
+		Expect(err).To(BeNil())
+		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+
+		By("closing the public endpoint", func() {
+			closed := []string{helpers.ClosedAuthorizedCIDR}
+			cluster, err = helper.UpdateCluster(cluster, ctx.RancherAdminClient, func(upgradedCluster *management.Cluster) {
+				upgradedCluster.AKSConfig.AuthorizedIPRanges = &closed
+			})
+			Expect(err).To(BeNil())
+			cluster, err = helpers.WaitForUpstreamField(ctx.RancherAdminClient, cluster.ID, func(c *management.Cluster) any {
+				return *c.AKSStatus.UpstreamSpec.AuthorizedIPRanges
+			}, closed, tools.SetTimeout(15*time.Minute))
+			Expect(err).To(BeNil())
+		})
+		helpers.CheckAPIServerNotReachable(cluster)
+	})
+
+	It("should manage the cluster through the cluster agent tunnel only", func() {
+		helpers.CheckKubectlThroughRancher(ctx.RancherAdminClient, cluster)
+
+		var err error
+		if helpers.SkipUpgradeTests {
+			GinkgoLogr.Info(helpers.SkipUpgradeTestsLog)
+		} else {
+			By(fmt.Sprintf("upgrading the cluster to %s", upgradeToVersion), func() {
+				cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, upgradeToVersion, ctx.RancherAdminClient, true)
+				Expect(err).To(BeNil())
+				cluster, err = helper.UpgradeNodeKubernetesVersion(cluster, upgradeToVersion, ctx.RancherAdminClient, true, true)
+				Expect(err).To(BeNil())
+			})
+		}
+
+		By("scaling the nodepool", func() {
+			cluster, err = helper.ScaleNodePool(cluster, ctx.RancherAdminClient, 2, true, true)
+			Expect(err).To(BeNil())
+		})
+
+		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		helpers.CheckKubectlThroughRancher(ctx.RancherAdminClient, cluster)
+	})
+})
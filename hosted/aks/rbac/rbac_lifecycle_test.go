@@ -0,0 +1,41 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("RBACLifecycle", func() {
+	It("should only let the cluster owner scale, upgrade and delete the cluster", func() {
+		// the upgrade is skipped if it can not run with the Rancher version under test
+		k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, !helpers.SkipUpgradeTests)
+		Expect(err).To(BeNil())
+		upgradeToVersion := k8sVersion
+		if !helpers.SkipUpgradeTests {
+			upgradeToVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, false)
+			Expect(err).To(BeNil())
+		}
+		GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s, upgrading to %s", k8sVersion, clusterName, upgradeToVersion))
+
+		clusterRolesLifecycleCheck(k8sVersion, upgradeToVersion)
+	})
+})
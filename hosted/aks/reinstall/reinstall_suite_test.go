@@ -0,0 +1,163 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reinstall_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+const (
+	increaseBy = 1
+)
+
+var (
+	clusterName string
+	ctx         helpers.RancherContext
+	cluster     *management.Cluster
+	location    = helpers.GetAKSLocation()
+	k3sVersion  = helpers.CurrentRunConfig().K3sVersion
+)
+
+func TestReinstall(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Reinstall Suite", helpers.SuiteConfig(t))
+}
+
+// The Rancher setup and the cloud credential are shared by the parallel processes
+var _ = SynchronizedBeforeSuite(func() []byte {
+	helpers.ValidateRunConfig(helpers.SuiteBackupRestore)
+	return helpers.ParallelSynchronizedBeforeSuite()
+}, func(setup []byte) {
+	ctx = helpers.ParallelBeforeSuite(setup)
+})
+
+var _ = JustAfterEach(func() {
+	// Collect the support bundle before AfterEach deletes the cluster
+	helpers.CollectSupportBundleOnFailure(clusterName)
+})
+
+// Delete the clusters leaked by all the processes once they are done
+var _ = SynchronizedAfterSuite(func() {}, helpers.CommonSynchronizedAfterSuite)
+
+var _ = ReportAfterEach(func(report SpecReport) {
+	// Add result in Qase if asked
+	helpers.ReportToQase(report)
+	// Upload the artifacts of the failed spec to ARTIFACTS_BUCKET if set
+	helpers.UploadArtifactsOnFailure(report)
+})
+
+var _ = ReportAfterSuite("Reports", func(report Report) {
+	// Write the JSON, JUnit and summary reports to the artifacts directory
+	helpers.GenerateSuiteReports(report)
+	// Submit the Qase results of all the parallel processes
+	helpers.SubmitQaseResults()
+})
+
+var _ = BeforeEach(func() {
+	helpers.CollectSpecArtifacts()
+	helpers.RecordClusterTransitions(ctx.RancherAdminClient)
+
+	clusterName = helpers.GenerateClusterName(helpers.ClusterNamePrefix)
+	k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, false)
+	Expect(err).To(BeNil())
+	GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
+
+	if helpers.IsImport {
+		By("importing the cluster")
+		err = helper.CreateAKSClusterOnAzure(location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+		Expect(err).To(BeNil())
+		cluster, err = helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
+		Expect(err).To(BeNil())
+	} else {
+		By("provisioning the cluster")
+		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
+		Expect(err).To(BeNil())
+	}
+	cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+	Expect(err).To(BeNil())
+})
+
+var _ = AfterEach(func() {
+	if ctx.ClusterCleanup {
+		if cluster != nil {
+			err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+		}
+		// the resource group is left behind by Rancher, whether the cluster is provisioned or imported
+		err := helper.DeleteAKSClusteronAzure(clusterName)
+		Expect(err).To(BeNil())
+	} else {
+		fmt.Println("Skipping downstream cluster deletion: ", clusterName)
+	}
+})
+
+func adoptedNodesChecks(cluster *management.Cluster, client *rancher.Client, clusterName string) {
+	helpers.ClusterIsReadyChecks(cluster, client, clusterName)
+	configNodePools := *cluster.AKSConfig.NodePools
+	initialNodeCount := *configNodePools[0].Count
+
+	By("scaling up the nodepool", func() {
+		var err error
+		cluster, err = helper.ScaleNodePool(cluster, client, initialNodeCount+increaseBy, true, true)
+		Expect(err).To(BeNil())
+	})
+
+	By("adding a nodepool", func() {
+		var err error
+		cluster, err = helper.AddNodePool(cluster, increaseBy, client, true, true)
+		Expect(err).To(BeNil())
+	})
+}
+
+func ReinstallAdoptionChecks(k *kubectl.Kubectl) {
+	By("Checking hosted cluster is ready", func() {
+		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+	})
+
+	var snapshot *helpers.ClusterSnapshot
+	By("Recording the hosted cluster state", func() {
+		var err error
+		snapshot, err = helpers.SnapshotHostedCluster(ctx.RancherAdminClient, cluster.ID)
+		Expect(err).To(BeNil())
+	})
+
+	ctx = helpers.ReinstallRancher(k, k3sVersion)
+	// the cluster object is lost along with the previous installation, only the cloud cluster is left
+	cluster = nil
+
+	By("Importing the cluster in the new Rancher installation", func() {
+		var err error
+		cluster, err = helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
+		Expect(err).To(BeNil())
+	})
+
+	By("Checking hosted cluster has been adopted", func() {
+		cluster = helpers.CheckHostedClusterAdopted(ctx.RancherAdminClient, cluster, snapshot)
+	})
+
+	By("Checking hosted cluster can be modified", func() {
+		adoptedNodesChecks(cluster, ctx.RancherAdminClient, clusterName)
+	})
+}
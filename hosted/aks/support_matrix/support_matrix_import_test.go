@@ -21,10 +21,10 @@ import (
 	"fmt"
 
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("SupportMatrixImport", func() {
@@ -38,7 +38,7 @@ var _ = Describe("SupportMatrixImport", func() {
 				cluster     *management.Cluster
 			)
 			BeforeEach(func() {
-				clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+				clusterName = helpers.GenerateClusterName(helpers.ClusterNamePrefix)
 				err := helper.CreateAKSClusterOnAzure(location, clusterName, version, "1", helpers.GetCommonMetadataLabels())
 				Expect(err).To(BeNil())
 				cluster, err = helper.ImportAKSHostedCluster(ctx.StdUserClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
@@ -47,6 +47,9 @@ var _ = Describe("SupportMatrixImport", func() {
 				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
 				Expect(err).To(BeNil())
 			})
+			JustAfterEach(func() {
+				helpers.CollectSupportBundleOnFailure(clusterName)
+			})
 			AfterEach(func() {
 				if ctx.ClusterCleanup && cluster != nil {
 					err := helper.DeleteAKSHostCluster(cluster, ctx.StdUserClient)
@@ -58,9 +61,7 @@ var _ = Describe("SupportMatrixImport", func() {
 				}
 			})
 
-			It("should successfully import the cluster", func() {
-				// Report to Qase
-				testCaseID = 250
+			It("should successfully import the cluster", helpers.QaseLabel(qasecases.AKSSupportMatrixImportCluster), func() {
 				helpers.ClusterIsReadyChecks(cluster, ctx.StdUserClient, clusterName)
 			})
 		})
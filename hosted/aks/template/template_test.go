@@ -0,0 +1,74 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("ClusterTemplate", func() {
+	var template *helpers.ClusterTemplate
+
+	BeforeEach(func() {
+		var err error
+		template, err = helpers.LoadClusterTemplate(helpers.ClusterTemplatePath("aks"))
+		Expect(err).To(BeNil())
+	})
+
+	It("should reject an answer overriding a locked field", func() {
+		_, err := template.Render(clusterName, map[string]any{"aksConfig.networkPlugin": "azure"})
+		Expect(err).To(MatchError(ContainSubstring("locked")))
+		_, err = template.Render(clusterName, map[string]any{"aksConfig.nodePools": []any{}})
+		Expect(err).To(MatchError(ContainSubstring("locked")))
+	})
+
+	DescribeTable("should provision a cluster from the template",
+		func(overrides map[string]any) {
+			k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, false)
+			Expect(err).To(BeNil())
+			GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s", k8sVersion, clusterName))
+
+			answers := map[string]any{
+				"aksConfig.azureCredentialSecret":           ctx.CloudCredID,
+				"aksConfig.resourceLocation":                location,
+				"aksConfig.kubernetesVersion":               k8sVersion,
+				"aksConfig.nodePools.0.orchestratorVersion": k8sVersion,
+				"aksConfig.tags":                            helpers.GetCommonMetadataLabels(),
+			}
+			for path, value := range overrides {
+				answers[path] = value
+			}
+			spec, err := template.Render(clusterName, answers)
+			Expect(err).To(BeNil())
+
+			cluster, err = helpers.CreateClusterFromSpec(ctx.RancherAdminClient, spec)
+			Expect(err).To(BeNil())
+			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+
+			helpers.CheckClusterTemplateLocks(cluster, template)
+			helpers.CheckClusterMatchesSpec(cluster, spec)
+			helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		},
+		Entry("with the template values", nil),
+		Entry("with per-cluster overrides", map[string]any{"aksConfig.nodePools.0.nodeLabels": helpers.SchedulingLabels()}),
+	)
+})
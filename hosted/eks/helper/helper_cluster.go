@@ -3,9 +3,9 @@ package helper
 import (
 	"fmt"
 	"maps"
-	"os"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
@@ -15,11 +15,11 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 
-	"github.com/epinio/epinio/acceptance/helpers/proc"
 	"github.com/pkg/errors"
+	"github.com/rancher/hosted-providers-e2e/pkg/cloudjson"
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
 	"github.com/rancher/shepherd/clients/rancher"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	"github.com/rancher/shepherd/extensions/clusters"
 	"github.com/rancher/shepherd/extensions/clusters/eks"
 	"github.com/rancher/shepherd/pkg/config"
 	namegen "github.com/rancher/shepherd/pkg/namegenerator"
@@ -38,7 +38,56 @@ func CreateEKSHostedCluster(client *rancher.Client, displayName, cloudCredential
 	if updateFunc != nil {
 		updateFunc(&eksClusterConfig)
 	}
-	return eks.CreateEKSHostedCluster(client, displayName, cloudCredentialID, eksClusterConfig, false, false, false, false, nil)
+	if helpers.IsDryRun() || helpers.IsTerraformBackend() {
+		spec, err := eksClusterSpec(displayName, cloudCredentialID, eksClusterConfig)
+		if err != nil {
+			return nil, err
+		}
+		if helpers.IsDryRun() {
+			versions, err := ListEKSAllVersions(client)
+			if err != nil {
+				return nil, err
+			}
+			return helpers.DryRunCluster(client, spec, helpers.DryRunChecks{Versions: versions, InstanceTypeAvailable: eksInstanceTypeAvailable(region)})
+		}
+		return helpers.CreateClusterWithTerraform(client, spec)
+	}
+	cluster, err := eks.CreateEKSHostedCluster(client, displayName, cloudCredentialID, eksClusterConfig, false, false, false, false, nil)
+	return helpers.TrackRancherCluster(helpers.OperationProvision, cluster, err, region, eksClusterConfig.Tags)
+}
+
+// eksClusterSpec returns the spec of the EKS cluster, created with the rancher2 Terraform provider (see helpers.CreateClusterWithTerraform)
+// or validated with DRY_RUN (see helpers.DryRunCluster); the nodegroups without version get the version of the cluster, like with eks.CreateEKSHostedCluster
+func eksClusterSpec(displayName, cloudCredentialID string, eksClusterConfig eks.ClusterConfig) (*helpers.ClusterSpec, error) {
+	eksConfig := &management.EKSClusterConfigSpec{}
+	if err := helpers.ConvertHostedConfig(eksClusterConfig, eksConfig); err != nil {
+		return nil, err
+	}
+	eksConfig.AmazonCredentialSecret = cloudCredentialID
+	eksConfig.DisplayName = displayName
+	if eksConfig.NodeGroups != nil {
+		for i := range *eksConfig.NodeGroups {
+			if (*eksConfig.NodeGroups)[i].Version == nil {
+				(*eksConfig.NodeGroups)[i].Version = eksConfig.KubernetesVersion
+			}
+		}
+	}
+	return &helpers.ClusterSpec{Name: displayName, EKSConfig: eksConfig}, nil
+}
+
+// eksInstanceTypeAvailable returns the check of the EC2 instance types offered in the region, see helpers.DryRunChecks
+func eksInstanceTypeAvailable(region string) func(instanceType string) error {
+	return func(instanceType string) error {
+		out, err := extcli.AWS.Output("ec2", "describe-instance-type-offerings", "--region", region, "--location-type", "region",
+			"--filters", "Name=instance-type,Values="+instanceType, "--query", "InstanceTypeOfferings[].InstanceType", "--output", "text")
+		if err != nil {
+			return fmt.Errorf("failed to check instance type %s in region %s: %w", instanceType, region, err)
+		}
+		if out == "" {
+			return fmt.Errorf("instance type %s is not offered in region %s", instanceType, region)
+		}
+		return nil
+	}
 }
 
 func ImportEKSHostedCluster(client *rancher.Client, displayName, cloudCredentialID, region string) (*management.Cluster, error) {
@@ -57,23 +106,29 @@ func ImportEKSHostedCluster(client *rancher.Client, displayName, cloudCredential
 	if err != nil {
 		return nil, err
 	}
-	return clusterResp, err
+	return helpers.TrackRancherCluster(helpers.OperationImport, clusterResp, err, region, nil)
 }
 
 // DeleteEKSHostCluster deletes the EKS cluster
 func DeleteEKSHostCluster(cluster *management.Cluster, client *rancher.Client) error {
-	return client.Management.Cluster.Delete(cluster)
+	if err := helpers.DeleteRancherCluster(client, cluster); err != nil {
+		return err
+	}
+	helpers.MarkResourceDeleted(helpers.ResourceRancherCluster, cluster.Name)
+	return nil
 
 }
 
 // UpgradeClusterKubernetesVersion upgrades the k8s version to the value defined by upgradeToVersion.
 // if checkClusterConfig is set to true, it will validate that the cluster control plane has been upgrade successfully
 func UpgradeClusterKubernetesVersion(cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, checkClusterConfig bool) (*management.Cluster, error) {
+	defer helpers.TimeUpgrade(helpers.OperationUpgrade, cluster, upgradeToVersion, checkClusterConfig)()
+
 	upgradedCluster := cluster
 	currentVersion := *cluster.EKSConfig.KubernetesVersion
 	upgradedCluster.EKSConfig.KubernetesVersion = &upgradeToVersion
 
-	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err := helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	Expect(err).To(BeNil())
 
 	if checkClusterConfig {
@@ -86,12 +141,12 @@ func UpgradeClusterKubernetesVersion(cluster *management.Cluster, upgradeToVersi
 
 		// Check if the desired config has been applied in Rancher
 		// Check if EKSConfig has correct KubernetesVersion after upgrade (Ref: eks-operator/issues/668)
-		Eventually(func() bool {
+		helpers.EventuallyForOperation(helpers.OperationUpgrade, func() bool {
 			ginkgo.GinkgoLogr.Info("Waiting for k8s upgrade to appear in EKSStatus.UpstreamSpec & EKSConfig ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			return *cluster.EKSStatus.UpstreamSpec.KubernetesVersion == upgradeToVersion && *cluster.EKSConfig.KubernetesVersion == upgradeToVersion
-		}, tools.SetTimeout(15*time.Minute), 30*time.Second).Should(BeTrue())
+		}, tools.SetTimeout(15*time.Minute)).Should(BeTrue())
 
 		// ensure nodegroup version is same in Rancher
 		for _, ng := range *cluster.EKSStatus.UpstreamSpec.NodeGroups {
@@ -106,6 +161,8 @@ func UpgradeClusterKubernetesVersion(cluster *management.Cluster, upgradeToVersi
 // if checkClusterConfig is set to true, it will validate that nodegroup has been upgraded successfully
 // if useEksctl is set to true, nodegroup will be upgraded using eksctl utility instead of updating it from Rancher
 func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, wait, checkClusterConfig, useEksctl bool) (*management.Cluster, error) {
+	defer helpers.TimeUpgrade(helpers.OperationNodeUpgrade, cluster, upgradeToVersion, wait)()
+
 	var err error
 
 	if !useEksctl {
@@ -115,11 +172,11 @@ func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion
 			configNodeGroups[i].Version = &upgradeToVersion
 		}
 
-		cluster, err = client.Management.Cluster.Update(cluster, &upgradedCluster)
+		cluster, err = helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 		Expect(err).To(BeNil())
 
 		if wait {
-			err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+			err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 			Expect(err).To(BeNil())
 		}
 	} else {
@@ -131,9 +188,9 @@ func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion
 	}
 
 	if checkClusterConfig {
-		Eventually(func() bool {
+		helpers.EventuallyForOperation(helpers.OperationNodeUpgrade, func() bool {
 			// Check if the desired config has been applied
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			ginkgo.GinkgoLogr.Info("waiting for the nodegroup upgrade to appear in EKSStatus.UpstreamSpec ...")
 			for _, ng := range *cluster.EKSStatus.UpstreamSpec.NodeGroups {
@@ -142,7 +199,7 @@ func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion
 				}
 			}
 			return true
-		}, tools.SetTimeout(15*time.Minute), 30*time.Second).Should(BeTrue())
+		}, tools.SetTimeout(15*time.Minute)).Should(BeTrue())
 	}
 
 	// Ensure nodegroup version is correct in Rancher after upgrade
@@ -153,66 +210,94 @@ func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion
 	return cluster, nil
 }
 
+// UpgradeSteps returns the steps used by helpers.UpgradeAlongPath to upgrade the control plane and then the nodegroups of an EKS cluster
+func UpgradeSteps() helpers.UpgradeSteps {
+	return helpers.UpgradeSteps{
+		ControlPlane: func(cluster *management.Cluster, client *rancher.Client, version string) (*management.Cluster, error) {
+			return UpgradeClusterKubernetesVersion(cluster, version, client, true)
+		},
+		NodeGroups: func(cluster *management.Cluster, client *rancher.Client, version string) (*management.Cluster, error) {
+			return UpgradeNodeKubernetesVersion(cluster, version, client, true, true, false)
+		},
+	}
+}
+
 // AddNodeGroup adds a nodegroup to the list; it uses the nodegroup template defined in CATTLE_TEST_CONFIG file
 // if checkClusterConfig is set to true, it will validate that nodegroup has been added successfully
 func AddNodeGroup(cluster *management.Cluster, increaseBy int, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	defer helpers.TimeOperation(helpers.OperationAddNodePool, cluster, wait)()
+
 	upgradedCluster := cluster
 	currentNodeGroupNumber := len(*cluster.EKSConfig.NodeGroups)
 
-	// Workaround for eks-operator/issues/406
-	// We use management.EKSClusterConfigSpec instead of the usual eks.ClusterConfig to unmarshal the data without the need of a lot of post-processing.
-	var eksClusterConfig management.EKSClusterConfigSpec
-	config.LoadConfig(eks.EKSClusterConfigConfigurationFileKey, &eksClusterConfig)
-	nodeGroups := *eksClusterConfig.NodeGroups
-	ngTemplate := nodeGroups[0]
-
+	ngTemplate := nodeGroupTemplate()
 	updateNodeGroupsList := *cluster.EKSConfig.NodeGroups
+	added := make([]string, 0, increaseBy)
 	for i := 1; i <= increaseBy; i++ {
-		newNodeGroup := management.NodeGroup{
-			NodegroupName: pointer.String(namegen.AppendRandomString("ng")),
-			DesiredSize:   ngTemplate.DesiredSize,
-			DiskSize:      ngTemplate.DiskSize,
-			InstanceType:  ngTemplate.InstanceType,
-			MaxSize:       ngTemplate.MaxSize,
-			MinSize:       ngTemplate.MinSize,
-		}
+		newNodeGroup := newNodeGroupFromTemplate(ngTemplate)
 		updateNodeGroupsList = append([]management.NodeGroup{newNodeGroup}, updateNodeGroupsList...)
+		added = append(added, *newNodeGroup.NodegroupName)
 	}
 	upgradedCluster.EKSConfig.NodeGroups = &updateNodeGroupsList
 
-	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err := helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	Expect(err).To(BeNil())
 
 	if checkClusterConfig {
-		// Check if the desired config is set correctly
+		// Check if the desired config is set correctly; only the added nodegroups are looked up
 		Expect(len(*cluster.EKSConfig.NodeGroups)).Should(BeNumerically("==", currentNodeGroupNumber+increaseBy))
-		for i, ng := range *cluster.EKSConfig.NodeGroups {
-			Expect(ng.NodegroupName).To(Equal(updateNodeGroupsList[i].NodegroupName))
-		}
+		Expect(helpers.MissingNodePools(added, nodeGroupNames(*cluster.EKSConfig.NodeGroups))).To(BeEmpty())
 	}
 
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+		err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 		Expect(err).To(BeNil())
 	}
 
 	if checkClusterConfig {
 		// Check if the desired config has been applied in Rancher
-		Eventually(func() int {
-			ginkgo.GinkgoLogr.Info("Waiting for the total nodegroup count to increase in EKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			return len(*cluster.EKSStatus.UpstreamSpec.NodeGroups)
-		}, tools.SetTimeout(15*time.Minute), 10*time.Second).Should(BeNumerically("==", currentNodeGroupNumber+increaseBy))
-
-		for i, ng := range *cluster.EKSStatus.UpstreamSpec.NodeGroups {
-			Expect(ng.NodegroupName).To(Equal(updateNodeGroupsList[i].NodegroupName))
-		}
+		ginkgo.GinkgoLogr.Info("Waiting for the new nodegroups to appear in EKSStatus.UpstreamSpec ...")
+		cluster, err = helpers.WaitForAddedNodePools(client, cluster.ID, added, currentNodeGroupNumber+increaseBy, func(cluster *management.Cluster) []string {
+			return nodeGroupNames(*cluster.EKSStatus.UpstreamSpec.NodeGroups)
+		}, tools.SetTimeout(15*time.Minute))
+		Expect(err).To(BeNil())
 	}
 
 	return cluster, nil
 }
 
+// nodeGroupTemplate returns the nodegroup template defined in CATTLE_TEST_CONFIG file, which the added nodegroups are created from
+func nodeGroupTemplate() management.NodeGroup {
+	// Workaround for eks-operator/issues/406
+	// We use management.EKSClusterConfigSpec instead of the usual eks.ClusterConfig to unmarshal the data without the need of a lot of post-processing.
+	var eksClusterConfig management.EKSClusterConfigSpec
+	config.LoadConfig(eks.EKSClusterConfigConfigurationFileKey, &eksClusterConfig)
+	return (*eksClusterConfig.NodeGroups)[0]
+}
+
+// newNodeGroupFromTemplate returns a new nodegroup with a random name, sized as the nodegroup template
+func newNodeGroupFromTemplate(ngTemplate management.NodeGroup) management.NodeGroup {
+	return management.NodeGroup{
+		NodegroupName: pointer.String(namegen.AppendRandomString("ng")),
+		DesiredSize:   ngTemplate.DesiredSize,
+		DiskSize:      ngTemplate.DiskSize,
+		InstanceType:  ngTemplate.InstanceType,
+		MaxSize:       ngTemplate.MaxSize,
+		MinSize:       ngTemplate.MinSize,
+	}
+}
+
+// nodeGroupNames returns the names of the nodegroups
+func nodeGroupNames(nodeGroups []management.NodeGroup) []string {
+	names := make([]string, 0, len(nodeGroups))
+	for _, ng := range nodeGroups {
+		if ng.NodegroupName != nil {
+			names = append(names, *ng.NodegroupName)
+		}
+	}
+	return names
+}
+
 // AddNodeGroupToConfig adds a nodegroup to the list; it uses the nodegroup template defined in CATTLE_TEST_CONFIG file
 func AddNodeGroupToConfig(eksClusterConfig eks.ClusterConfig, ngCount int) (eks.ClusterConfig, error) {
 
@@ -233,13 +318,15 @@ func AddNodeGroupToConfig(eksClusterConfig eks.ClusterConfig, ngCount int) (eks.
 // if checkClusterConfig is set to true, it will validate that nodegroup has been deleted successfully
 // TODO: Modify this method to delete a custom qty of DeleteNodeGroup, perhaps by adding an `decreaseBy int` arg
 func DeleteNodeGroup(cluster *management.Cluster, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	defer helpers.TimeOperation(helpers.OperationDeleteNodePool, cluster, wait)()
+
 	upgradedCluster := cluster
 	currentNodeGroupNumber := len(*cluster.EKSConfig.NodeGroups)
 	configNodeGroups := *cluster.EKSConfig.NodeGroups
 	updateNodeGroupsList := configNodeGroups[:1]
 	upgradedCluster.EKSConfig.NodeGroups = &updateNodeGroupsList
 
-	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err := helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	Expect(err).To(BeNil())
 
 	if checkClusterConfig {
@@ -250,18 +337,18 @@ func DeleteNodeGroup(cluster *management.Cluster, client *rancher.Client, wait,
 		}
 	}
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+		err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 		Expect(err).To(BeNil())
 	}
 	if checkClusterConfig {
 
 		// Check if the desired config has been applied in Rancher
-		Eventually(func() int {
+		helpers.EventuallyForOperation(helpers.OperationDeleteNodePool, func() int {
 			ginkgo.GinkgoLogr.Info("Waiting for the total nodegroup count to decrease in EKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			return len(*cluster.EKSStatus.UpstreamSpec.NodeGroups)
-		}, tools.SetTimeout(15*time.Minute), 10*time.Second).Should(BeNumerically("==", currentNodeGroupNumber-1))
+		}, tools.SetTimeout(15*time.Minute)).Should(BeNumerically("==", currentNodeGroupNumber-1))
 		for i, ng := range *cluster.EKSStatus.UpstreamSpec.NodeGroups {
 			Expect(ng.NodegroupName).To(Equal(updateNodeGroupsList[i].NodegroupName))
 		}
@@ -273,14 +360,15 @@ func DeleteNodeGroup(cluster *management.Cluster, client *rancher.Client, wait,
 // if wait is set to true, it will wait until the cluster finishes updating;
 // if checkClusterConfig is set to true, it will validate that nodegroup has been scaled successfully
 func ScaleNodeGroup(cluster *management.Cluster, client *rancher.Client, nodeCount int64, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	defer helpers.TimeOperation(helpers.OperationScale, cluster, wait)()
+
 	upgradedCluster := cluster
 	configNodeGroups := *upgradedCluster.EKSConfig.NodeGroups
 	for i := range configNodeGroups {
-		configNodeGroups[i].DesiredSize = pointer.Int64(nodeCount)
-		configNodeGroups[i].MaxSize = pointer.Int64(nodeCount)
+		scaleNodeGroupConfig(&configNodeGroups[i], nodeCount)
 	}
 
-	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err := helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	Expect(err).To(BeNil())
 
 	if checkClusterConfig {
@@ -292,15 +380,15 @@ func ScaleNodeGroup(cluster *management.Cluster, client *rancher.Client, nodeCou
 	}
 
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+		err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 		Expect(err).To(BeNil())
 	}
 
 	if checkClusterConfig {
 		// check that the desired config is applied on Rancher
-		Eventually(func() bool {
+		helpers.EventuallyForOperation(helpers.OperationScale, func() bool {
 			ginkgo.GinkgoLogr.Info("Waiting for the node count change to appear in EKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			upstreamNodeGroups := *cluster.EKSStatus.UpstreamSpec.NodeGroups
 			for i := range upstreamNodeGroups {
@@ -309,31 +397,39 @@ func ScaleNodeGroup(cluster *management.Cluster, client *rancher.Client, nodeCou
 				}
 			}
 			return true
-		}, tools.SetTimeout(15*time.Minute), 10*time.Second).Should(BeTrue())
+		}, tools.SetTimeout(15*time.Minute)).Should(BeTrue())
 	}
 
 	return cluster, nil
 }
 
+// scaleNodeGroupConfig sets the desired size of the nodegroup to nodeCount, and its min and max sizes so that they allow it
+func scaleNodeGroupConfig(ng *management.NodeGroup, nodeCount int64) {
+	ng.DesiredSize = pointer.Int64(nodeCount)
+	// the max size of a nodegroup can not be zero, a nodegroup scaled to zero keeps a max size of 1
+	ng.MaxSize = pointer.Int64(max(nodeCount, 1))
+	if minSize := ng.MinSize; minSize != nil && *minSize > nodeCount {
+		ng.MinSize = pointer.Int64(nodeCount)
+	}
+}
+
 // UpdateLogging updates the logging of a EKS cluster, Types: api, audit, authenticator, controllerManager, scheduler
 // if checkClusterConfig is true, it validates the update
 func UpdateLogging(cluster *management.Cluster, client *rancher.Client, loggingTypes []string, checkClusterConfig bool) (*management.Cluster, error) {
 	upgradedCluster := cluster
 	upgradedCluster.EKSConfig.LoggingTypes = &loggingTypes
 
-	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err := helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	Expect(err).To(BeNil())
 
 	if checkClusterConfig {
 		// Check if the desired config is set correctly
 		Expect(*upgradedCluster.EKSConfig.LoggingTypes).Should(HaveExactElements(loggingTypes))
 
-		Eventually(func() []string {
-			ginkgo.GinkgoLogr.Info("Waiting for the logging changes to appear in EKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
+		ginkgo.GinkgoLogr.Info("Waiting for the logging changes to appear in EKSStatus.UpstreamSpec ...")
+		return helpers.WaitForUpstreamField(client, cluster.ID, func(cluster *management.Cluster) any {
 			return *cluster.EKSStatus.UpstreamSpec.LoggingTypes
-		}, tools.SetTimeout(10*time.Minute), 15*time.Second).Should(HaveExactElements(loggingTypes))
+		}, HaveExactElements(loggingTypes), tools.SetTimeout(10*time.Minute))
 	}
 	return cluster, nil
 }
@@ -345,19 +441,18 @@ func UpdateAccess(cluster *management.Cluster, client *rancher.Client, publicAcc
 	upgradedCluster.EKSConfig.PublicAccess = &publicAccess
 	upgradedCluster.EKSConfig.PrivateAccess = &privateAccess
 
-	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err := helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 
 	if checkClusterConfig {
-		Eventually(func() bool {
-			// Check if the desired config is set correctly
-			Expect(*upgradedCluster.EKSConfig.PublicAccess).Should(Equal(publicAccess))
-			Expect(*upgradedCluster.EKSConfig.PrivateAccess).Should(Equal(privateAccess))
+		Expect(err).To(BeNil())
+		// Check if the desired config is set correctly
+		Expect(*upgradedCluster.EKSConfig.PublicAccess).Should(Equal(publicAccess))
+		Expect(*upgradedCluster.EKSConfig.PrivateAccess).Should(Equal(privateAccess))
 
-			ginkgo.GinkgoLogr.Info("Waiting for the access changes to appear in EKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			return *cluster.EKSStatus.UpstreamSpec.PublicAccess == publicAccess && *cluster.EKSStatus.UpstreamSpec.PrivateAccess == privateAccess
-		}, tools.SetTimeout(10*time.Minute), 15*time.Second).Should(BeTrue())
+		ginkgo.GinkgoLogr.Info("Waiting for the access changes to appear in EKSStatus.UpstreamSpec ...")
+		return helpers.WaitForUpstreamField(client, cluster.ID, func(cluster *management.Cluster) any {
+			return []bool{*cluster.EKSStatus.UpstreamSpec.PublicAccess, *cluster.EKSStatus.UpstreamSpec.PrivateAccess}
+		}, []bool{publicAccess, privateAccess}, tools.SetTimeout(10*time.Minute))
 	}
 	return cluster, err
 }
@@ -367,18 +462,17 @@ func UpdateAccess(cluster *management.Cluster, client *rancher.Client, publicAcc
 func UpdatePublicAccessSources(cluster *management.Cluster, client *rancher.Client, publicAccessSources []string, checkClusterConfig bool) (*management.Cluster, error) {
 	upgradedCluster := cluster
 	*upgradedCluster.EKSConfig.PublicAccessSources = append(*upgradedCluster.EKSConfig.PublicAccessSources, publicAccessSources...)
-	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err := helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
+	Expect(err).To(BeNil())
 
 	if checkClusterConfig {
 		// Check if the desired config is set correctly
 		Expect(*upgradedCluster.EKSConfig.PublicAccessSources).Should(ContainElements(publicAccessSources))
 
-		Eventually(func() []string {
-			ginkgo.GinkgoLogr.Info("Waiting for the publicaccess sources changes to appear in EKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
+		ginkgo.GinkgoLogr.Info("Waiting for the publicaccess sources changes to appear in EKSStatus.UpstreamSpec ...")
+		return helpers.WaitForUpstreamField(client, cluster.ID, func(cluster *management.Cluster) any {
 			return *cluster.EKSStatus.UpstreamSpec.PublicAccessSources
-		}, tools.SetTimeout(10*time.Minute), 15*time.Second).Should(ContainElements(publicAccessSources))
+		}, ContainElements(publicAccessSources), tools.SetTimeout(10*time.Minute))
 	}
 	return cluster, nil
 }
@@ -390,7 +484,7 @@ func UpdateClusterTags(cluster *management.Cluster, client *rancher.Client, tags
 	upgradedCluster := cluster
 	upgradedCluster.EKSConfig.Tags = &tags
 
-	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err := helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	Expect(err).To(BeNil())
 
 	if checkClusterConfig {
@@ -398,12 +492,12 @@ func UpdateClusterTags(cluster *management.Cluster, client *rancher.Client, tags
 		for key, value := range tags {
 			Expect(*cluster.EKSConfig.Tags).Should(HaveKeyWithValue(key, value))
 		}
-		Eventually(func() bool {
-			ginkgo.GinkgoLogr.Info("Waiting for the cluster tag changes to appear in EKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			return maps.Equal(tags, *cluster.EKSStatus.UpstreamSpec.Tags)
-		}, tools.SetTimeout(10*time.Minute), 15*time.Second).Should(BeTrue())
+		ginkgo.GinkgoLogr.Info("Waiting for the cluster tag changes to appear in EKSStatus.UpstreamSpec ...")
+		return helpers.WaitForUpstreamField(client, cluster.ID, func(cluster *management.Cluster) any {
+			return *cluster.EKSStatus.UpstreamSpec.Tags
+		}, Satisfy(func(upstreamTags map[string]string) bool {
+			return maps.Equal(tags, upstreamTags)
+		}), tools.SetTimeout(10*time.Minute))
 	}
 	return cluster, nil
 }
@@ -420,7 +514,7 @@ func UpdateNodegroupMetadata(cluster *management.Cluster, client *rancher.Client
 	}
 
 	var err error
-	cluster, err = client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err = helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	Expect(err).To(BeNil())
 
 	if checkClusterConfig {
@@ -434,9 +528,9 @@ func UpdateNodegroupMetadata(cluster *management.Cluster, client *rancher.Client
 			}
 		}
 
-		Eventually(func() bool {
+		helpers.EventuallyWithBackoff(func() bool {
 			ginkgo.GinkgoLogr.Info("Waiting for the nodegroup metadata changes to appear in EKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 
 			for _, ng := range *cluster.EKSStatus.UpstreamSpec.NodeGroups {
@@ -450,13 +544,118 @@ func UpdateNodegroupMetadata(cluster *management.Cluster, client *rancher.Client
 	return cluster, nil
 }
 
+// NodeGroupBatch are the nodegroup changes applied in a single cluster update by ApplyNodeGroupBatch; the changes left to their zero value are not applied
+type NodeGroupBatch struct {
+	// NodeCount is the desired size all the nodegroups are scaled to, the added ones included
+	NodeCount *int64
+	// Tags and Labels replace the tags and labels of all the nodegroups, the added ones included
+	Tags, Labels map[string]string
+	// Add is the number of nodegroups to add; they use the nodegroup template defined in CATTLE_TEST_CONFIG file
+	Add int
+}
+
+// batchNodeGroups returns the nodegroups once the changes of the batch are applied, the added nodegroups first as AddNodeGroup does,
+// and the names of the added nodegroups; the given nodegroups are not changed
+func batchNodeGroups(nodeGroups []management.NodeGroup, batch NodeGroupBatch, newNodeGroup func() management.NodeGroup) ([]management.NodeGroup, []string) {
+	updated := make([]management.NodeGroup, 0, len(nodeGroups)+batch.Add)
+	added := make([]string, 0, batch.Add)
+	for i := 0; i < batch.Add; i++ {
+		ng := newNodeGroup()
+		updated = append(updated, ng)
+		added = append(added, *ng.NodegroupName)
+	}
+	updated = append(updated, nodeGroups...)
+
+	for i := range updated {
+		if batch.NodeCount != nil {
+			scaleNodeGroupConfig(&updated[i], *batch.NodeCount)
+		}
+		if batch.Tags != nil {
+			tags := maps.Clone(batch.Tags)
+			updated[i].Tags = &tags
+		}
+		if batch.Labels != nil {
+			labels := maps.Clone(batch.Labels)
+			updated[i].Labels = &labels
+		}
+	}
+	return updated, added
+}
+
+// nodeGroupBatchApplied returns true if the nodegroups have all the changes of the batch: the added nodegroups are there, the total count is the expected one,
+// and every nodegroup has the desired size, tags and labels of the batch
+func nodeGroupBatchApplied(nodeGroups []management.NodeGroup, batch NodeGroupBatch, added []string, total int) bool {
+	if len(nodeGroups) != total || len(helpers.MissingNodePools(added, nodeGroupNames(nodeGroups))) > 0 {
+		return false
+	}
+	for _, ng := range nodeGroups {
+		if batch.NodeCount != nil && (ng.DesiredSize == nil || *ng.DesiredSize != *batch.NodeCount) {
+			return false
+		}
+		if batch.Tags != nil && (ng.Tags == nil || !maps.Equal(batch.Tags, *ng.Tags)) {
+			return false
+		}
+		if batch.Labels != nil && (ng.Labels == nil || !maps.Equal(batch.Labels, *ng.Labels)) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+ApplyNodeGroupBatch applies several nodegroup changes, scaling, metadata and nodegroup addition, in a single cluster update, and waits until the cluster
+is updated and all the changes appear in EKSStatus.UpstreamSpec. The end-to-end apply time, from the update until then, is recorded as OperationNodePoolBatch
+and returned, so that the performance suites can compare the batched changes of the operator to the same changes applied one by one.
+  - @param cluster Cluster to update
+  - @param client Rancher client
+  - @param batch Nodegroup changes to apply
+  - @returns The updated cluster, the apply time of the batch and the error of the update; the function will fail through Ginkgo if the changes are not applied
+*/
+func ApplyNodeGroupBatch(cluster *management.Cluster, client *rancher.Client, batch NodeGroupBatch) (*management.Cluster, time.Duration, error) {
+	start := time.Now()
+	defer helpers.TimeOperation(helpers.OperationNodePoolBatch, cluster, true)()
+
+	var ngTemplate management.NodeGroup
+	if batch.Add > 0 {
+		ngTemplate = nodeGroupTemplate()
+	}
+	nodeGroups, added := batchNodeGroups(*cluster.EKSConfig.NodeGroups, batch, func() management.NodeGroup {
+		return newNodeGroupFromTemplate(ngTemplate)
+	})
+	total := len(nodeGroups)
+
+	upgradedCluster := cluster
+	upgradedCluster.EKSConfig.NodeGroups = &nodeGroups
+	cluster, err := helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
+	if err != nil {
+		return nil, 0, err
+	}
+	// Check if the desired config is set correctly
+	Expect(nodeGroupBatchApplied(*cluster.EKSConfig.NodeGroups, batch, added, total)).To(BeTrue())
+
+	err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
+	Expect(err).To(BeNil())
+
+	ginkgo.GinkgoLogr.Info("Waiting for the batched nodegroup changes to appear in EKSStatus.UpstreamSpec ...")
+	helpers.EventuallyForOperation(helpers.OperationNodePoolBatch, func() bool {
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
+		Expect(err).To(BeNil())
+		return nodeGroupBatchApplied(*cluster.EKSStatus.UpstreamSpec.NodeGroups, batch, added, total)
+	}, tools.SetTimeout(20*time.Minute)).Should(BeTrue())
+
+	elapsed := time.Since(start)
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("The nodegroup batch of cluster %s (%d nodegroups added, %d nodegroups in total) was applied in %s",
+		cluster.Name, len(added), total, elapsed.Round(time.Second)))
+	return cluster, elapsed, nil
+}
+
 // UpdateCluster is a generic function to update a cluster
 func UpdateCluster(cluster *management.Cluster, client *rancher.Client, updateFunc func(*management.Cluster)) (*management.Cluster, error) {
 	upgradedCluster := cluster
 
 	updateFunc(upgradedCluster)
 
-	return client.Management.Cluster.Update(cluster, &upgradedCluster)
+	return helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 }
 
 // ListEKSAvailableVersions lists all the available and UI supported EKS versions for cluster upgrade.
@@ -520,25 +719,24 @@ func ListEKSAllVersions(client *rancher.Client) (allVersions []string, err error
 
 // <==============================EKS CLI==============================>
 
-// Create AWS EKS cluster using EKS CLI
+// Create AWS EKS cluster using EKS CLI; its kubeconfig is only written once a spec needs it, see helpers.DownstreamKubeconfigPath
 func CreateEKSClusterOnAWS(region string, clusterName string, k8sVersion string, nodes string, tags map[string]string, extraArgs ...string) error {
-	currentKubeconfig := os.Getenv("KUBECONFIG")
-	defer os.Setenv("KUBECONFIG", currentKubeconfig)
-
-	helpers.SetTempKubeConfig(clusterName)
-
+	helpers.SkipOnDryRun("EKS cluster " + clusterName)
 	formattedTags := k8slabels.SelectorFromSet(tags).String()
 	fmt.Println("Creating EKS cluster ...")
-	args := []string{"create", "cluster", "--region=" + region, "--name=" + clusterName, "--version=" + k8sVersion, "--nodegroup-name", "ranchernodes", "--nodes", nodes, "--tags", formattedTags}
+	args := []string{"create", "cluster", "--region=" + region, "--name=" + clusterName, "--version=" + k8sVersion, "--nodegroup-name", "ranchernodes", "--nodes", nodes, "--tags", formattedTags, "--write-kubeconfig=false"}
 	if len(extraArgs) != 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
+	start := time.Now()
+	_, err := extcli.Eksctl.Run(args...)
+	helpers.RecordOperation(helpers.OperationCLIProvision, clusterName, start, err)
 	if err != nil {
-		return errors.Wrap(err, "Failed to create cluster: "+out)
+		return errors.Wrap(err, "Failed to create cluster")
 	}
 	fmt.Println("Created EKS cluster: ", clusterName)
+	registerEKSKubeconfig(clusterName, region)
+	helpers.TrackResource(helpers.Resource{Kind: helpers.ResourceCloudCluster, Name: clusterName, Region: region, Tags: tags, NodePools: helpers.CLINodePools(helpers.EksctlDefaultInstanceType, nodes)})
 
 	return nil
 }
@@ -548,16 +746,35 @@ func UpgradeEKSClusterOnAWS(region string, clusterName string, upgradeToVersion
 
 	fmt.Println("Upgrading EKS cluster controlplane ...")
 	args := []string{"upgrade", "cluster", "--region=" + region, "--name=" + clusterName, "--version=" + upgradeToVersion, "--approve"}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
+	_, err := extcli.Eksctl.Run(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to upgrade cluster: "+out)
+		return errors.Wrap(err, "Failed to upgrade cluster")
 	}
 
 	fmt.Println("Upgraded EKS cluster controlplane: ", clusterName)
 	return nil
 }
 
+// GetEKSCredentialsOnAWS registers how to write the credentials of the EKS cluster to its kubeconfig, so that it can be reached with kubectl
+// without going through Rancher; the kubeconfig is only written once a spec needs it, see helpers.DownstreamKubeconfigPath
+func GetEKSCredentialsOnAWS(clusterName, region string) error {
+	registerEKSKubeconfig(clusterName, region)
+	return nil
+}
+
+// registerEKSKubeconfig registers the kubeconfig generator of the cluster, see GetEKSCredentialsOnAWS
+func registerEKSKubeconfig(clusterName, region string) {
+	helpers.RegisterKubeconfigGenerator(clusterName, func(kubeconfig string) error {
+		fmt.Println("Getting EKS cluster credentials ...")
+		args := []string{"utils", "write-kubeconfig", "--region", region, "--cluster", clusterName, "--kubeconfig", kubeconfig}
+		_, err := extcli.Eksctl.Run(args...)
+		if err != nil {
+			return errors.Wrap(err, "Failed to get cluster credentials")
+		}
+		return nil
+	})
+}
+
 // AddNodeGroupOnAWS adds nodegroup ot a cluster using EKS CLI
 func AddNodeGroupOnAWS(nodeName, clusterName, region string, extraArgs ...string) error {
 	fmt.Println("Adding nodegroup to EKS cluster ...")
@@ -565,10 +782,9 @@ func AddNodeGroupOnAWS(nodeName, clusterName, region string, extraArgs ...string
 	if len(extraArgs) != 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
+	_, err := extcli.Eksctl.Run(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to add nodegroup: "+out)
+		return errors.Wrap(err, "Failed to add nodegroup")
 	}
 	fmt.Println("Added nodegroup: ", nodeName)
 	return nil
@@ -582,10 +798,9 @@ func ScaleNodeGroupOnAWS(ngName, clusterName, region string, numOfNodes, maxCoun
 	if len(extraArgs) != 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
+	_, err := extcli.Eksctl.Run(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to scale nodegroup: "+out)
+		return errors.Wrap(err, "Failed to scale nodegroup")
 	}
 	fmt.Println("Scaled nodegroup: ", ngName)
 	return nil
@@ -622,10 +837,9 @@ func UpdateNodeGroupLabelsOnAWS(clusterName, nodegroupName, region string, addOr
 		args = append(args, extraArgs...)
 	}
 
-	fmt.Printf("Running command: aws %v\n", args)
-	out, err := proc.RunW("aws", args...)
+	out, err := extcli.AWS.Output(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to update labels to nodegroup: "+out)
+		return errors.Wrap(err, "Failed to update labels to nodegroup")
 	}
 	fmt.Println("Updated labels to nodegroup: ", nodegroupName, "\n", out)
 	return nil
@@ -633,20 +847,20 @@ func UpdateNodeGroupLabelsOnAWS(clusterName, nodegroupName, region string, addOr
 
 // AddClusterTagsOnAWS adds label to cluster using AWS cli
 func AddClusterTagsOnAWS(clusterName, region string, tags map[string]string, extraArgs ...string) error {
-	arn, err := GetFromEKS(region, clusterName, "cluster", ".[].Arn")
+	eksCluster, err := GetEKSClusterOnAWS(region, clusterName)
 	if err != nil {
 		return fmt.Errorf("failed to get ARN for cluster %s: %v", clusterName, err)
 	}
-	return UpdateResoureTagsOnAWS(arn, clusterName, region, tags, extraArgs...)
+	return UpdateResoureTagsOnAWS(eksCluster.Arn, clusterName, region, tags, extraArgs...)
 }
 
 // RemoveClusterTagsOnAWS removes label from cluster using AWS cli
 func RemoveClusterTagsOnAWS(clusterName, region string, tags []string, extraArgs ...string) error {
-	arn, err := GetFromEKS(region, clusterName, "cluster", ".[].Arn")
+	eksCluster, err := GetEKSClusterOnAWS(region, clusterName)
 	if err != nil {
 		return fmt.Errorf("failed to get ARN for cluster %s: %v", clusterName, err)
 	}
-	return RemoveResourceTagsOnAWS(arn, clusterName, region, tags, extraArgs...)
+	return RemoveResourceTagsOnAWS(eksCluster.Arn, clusterName, region, tags, extraArgs...)
 }
 
 // UpdateClusterTagOnAWS tags resource using AWS CLI
@@ -657,10 +871,9 @@ func UpdateResoureTagsOnAWS(resourceArn, clusterName, region string, tags map[st
 	if len(extraArgs) != 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: aws %v\n", args)
-	out, err := proc.RunW("aws", args...)
+	out, err := extcli.AWS.Output(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to update tag: "+out)
+		return errors.Wrap(err, "Failed to update tag")
 	}
 	fmt.Println("Updated tag on EKS cluster: ", clusterName, "\n", out)
 	return nil
@@ -677,10 +890,9 @@ func RemoveResourceTagsOnAWS(resourceArn, clusterName, region string, tags []str
 	if len(extraArgs) != 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: aws %v\n", args)
-	out, err := proc.RunW("aws", args...)
+	out, err := extcli.AWS.Output(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to remove tag: "+out)
+		return errors.Wrap(err, "Failed to remove tag")
 	}
 	fmt.Println("Removed tag on EKS cluster: ", clusterName, "\n", out)
 	return nil
@@ -702,10 +914,9 @@ func UpdateLoggingOnAWS(clusterName, region string, enableLoggingTypes, disableL
 	if len(extraArgs) != 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
+	out, err := extcli.Eksctl.Output(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to update logging: "+out)
+		return errors.Wrap(err, "Failed to update logging")
 	}
 	fmt.Println("Updated logging of EKS cluster: ", clusterName, "\n", out)
 	return nil
@@ -726,10 +937,9 @@ func UpdateVPCAccess(clusterName, region string, enablePublic, enablePrivate boo
 	if len(extraArgs) != 0 {
 		args = append(args, extraArgs...)
 	}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
+	_, err := extcli.Eksctl.Run(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to update VPC access: "+out)
+		return errors.Wrap(err, "Failed to update VPC access")
 	}
 	fmt.Println("Updated VPC access: ", clusterName)
 	return nil
@@ -739,40 +949,53 @@ func UpdateVPCAccess(clusterName, region string, enablePublic, enablePrivate boo
 func UpgradeEKSNodegroupOnAWS(region string, clusterName string, ngName string, upgradeToVersion string) error {
 	fmt.Println("Upgrading EKS cluster nodegroup ...")
 	args := []string{"upgrade", "nodegroup", "--region=" + region, "--name=" + ngName, "--cluster=" + clusterName, "--kubernetes-version=" + upgradeToVersion}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
+	_, err := extcli.Eksctl.Run(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to upgrade nodegroup: "+out)
+		return errors.Wrap(err, "Failed to upgrade nodegroup")
 	}
 
 	fmt.Println("Upgraded EKS cluster nodegroup: ", clusterName)
 	return nil
 }
 
-func GetFromEKS(region string, clusterName string, cmd string, query string, extraArgs ...string) (out string, err error) {
-	clusterArgs := []string{"eksctl", "get", "cluster", "--region=" + region, "--name=" + clusterName, "-ojson"}
-	ngArgs := []string{"eksctl", "get", "nodegroup", "--region=" + region, "--cluster=" + clusterName, "-ojson"}
-	queryArgs := []string{"|", "jq", "-r", query}
-
+// eksGetArgs returns the eksctl arguments to get the cluster (cmd is "cluster") or its nodegroups (any other cmd) as JSON
+func eksGetArgs(region string, clusterName string, cmd string, extraArgs ...string) []string {
+	args := []string{"get", "nodegroup", "--region=" + region, "--cluster=" + clusterName, "-ojson"}
 	if cmd == "cluster" {
-		// extraArgs must be appended before queryArgs
-		if len(extraArgs) != 0 {
-			clusterArgs = append(clusterArgs, extraArgs...)
-		}
-		clusterArgs = append(clusterArgs, queryArgs...)
-		cmd = strings.Join(clusterArgs, " ")
-	} else {
-		// extraArgs must be appended before queryArgs
-		if len(extraArgs) != 0 {
-			ngArgs = append(ngArgs, extraArgs...)
-		}
-		ngArgs = append(ngArgs, queryArgs...)
-		cmd = strings.Join(ngArgs, " ")
+		args = []string{"get", "cluster", "--region=" + region, "--name=" + clusterName, "-ojson"}
 	}
+	return append(args, extraArgs...)
+}
 
-	fmt.Printf("Running command: %s\n", cmd)
-	out, err = proc.RunW("bash", "-c", cmd)
-	return strings.TrimSpace(out), err
+// GetEKSClusterOnAWS gets the cluster using eksctl
+func GetEKSClusterOnAWS(region, clusterName string) (*cloudjson.EKSCluster, error) {
+	out, err := extcli.Eksctl.Output(eksGetArgs(region, clusterName, "cluster")...)
+	if err != nil {
+		return nil, err
+	}
+	return cloudjson.DecodeOne[cloudjson.EKSCluster](out)
+}
+
+// ListEKSNodegroupsOnAWS lists the nodegroups of the cluster using eksctl; extraArgs can select a nodegroup, for e.g. "--name", ngName
+func ListEKSNodegroupsOnAWS(region, clusterName string, extraArgs ...string) ([]cloudjson.EKSNodegroup, error) {
+	out, err := extcli.Eksctl.Output(eksGetArgs(region, clusterName, "nodegroup", extraArgs...)...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list nodegroups")
+	}
+	return cloudjson.Decode[[]cloudjson.EKSNodegroup](out)
+}
+
+// ClusterExistsOnAWS gets the cluster using eksctl and returns true if it is not in DELETING state;
+// it returns false if the cluster does not exist or is in DELETING state.
+func ClusterExistsOnAWS(region, clusterName string) (bool, error) {
+	eksCluster, err := GetEKSClusterOnAWS(region, clusterName)
+	if err != nil {
+		if strings.Contains(err.Error(), "ResourceNotFoundException") || strings.Contains(err.Error(), "No cluster found") {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "Failed to get cluster")
+	}
+	return eksCluster.Status != "" && eksCluster.Status != "DELETING", nil
 }
 
 // Creates/Deletes EKS cluster nodegroup using EKS CLI
@@ -782,49 +1005,85 @@ func ModifyEKSNodegroupOnAWS(region string, clusterName string, ngName string, o
 		args = append(args, "--disable-eviction")
 	}
 	args = append(args, extraArgs...)
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
+	_, err := extcli.Eksctl.Run(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to modify nodegroup: "+out)
+		return errors.Wrap(err, "Failed to modify nodegroup")
+	}
+	return nil
+}
+
+// maxParallelNodeGroupDeletions is the number of nodegroups deleted at once by DeleteEKSClusterOnAWS, to stay below the rate limits of the EKS API
+const maxParallelNodeGroupDeletions = 5
+
+// inParallel runs fn for all the names, at most limit at a time, and waits until they are all done; the errors of all the runs are returned
+func inParallel(names []string, limit int, fn func(name string) error) error {
+	var (
+		lock     sync.Mutex
+		failures []string
+		done     sync.WaitGroup
+	)
+	slots := make(chan struct{}, limit)
+	for _, name := range names {
+		done.Add(1)
+		slots <- struct{}{}
+		go func(name string) {
+			defer func() {
+				<-slots
+				done.Done()
+			}()
+			if err := fn(name); err != nil {
+				lock.Lock()
+				failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+				lock.Unlock()
+			}
+		}(name)
+	}
+	done.Wait()
+
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return errors.New(strings.Join(failures, "; "))
 	}
 	return nil
 }
 
 // Complete cleanup steps for Amazon EKS
 func DeleteEKSClusterOnAWS(region string, clusterName string) error {
-	currentKubeconfig := os.Getenv("KUBECONFIG")
-	downstreamKubeconfig := os.Getenv(helpers.DownstreamKubeconfig(clusterName))
-	defer func() {
-		_ = os.Setenv("KUBECONFIG", currentKubeconfig)
-		_ = os.Remove(downstreamKubeconfig) // clean up
-	}()
-	_ = os.Setenv("KUBECONFIG", downstreamKubeconfig)
+	defer helpers.RemoveDownstreamKubeconfig(clusterName) // clean up
 
+	// the deletion of the nodegroups is part of the cluster deletion
+	start := time.Now()
 	fmt.Println("Deleting all nodegroups ...")
-	ngNames, err := GetFromEKS(region, clusterName, "nodegroup", ".[].Name")
+	nodegroups, err := ListEKSNodegroupsOnAWS(region, clusterName)
 	if err != nil {
 		return errors.Wrap(err, "Failed to list nodegroup for deletion")
 	}
 
-	if ngNames != "" {
-		for _, ngName := range strings.Split(ngNames, "\n") {
-			err = ModifyEKSNodegroupOnAWS(region, clusterName, ngName, "delete", "--wait")
-			if err != nil {
-				return errors.Wrap(err, "Failed to delete nodegroup")
-			}
+	if len(nodegroups) != 0 {
+		var ngNames []string
+		for _, ng := range nodegroups {
+			ngNames = append(ngNames, ng.Name)
+		}
+		// the nodegroups are deleted at once, so that the teardown takes as long as the longest deletion rather than their sum
+		err = inParallel(ngNames, maxParallelNodeGroupDeletions, func(ngName string) error {
+			return ModifyEKSNodegroupOnAWS(region, clusterName, ngName, "delete", "--wait")
+		})
+		if err != nil {
+			return errors.Wrap(err, "Failed to delete nodegroup")
 		}
 	}
 
 	fmt.Println("Deleting EKS cluster ...")
 
 	args := []string{"delete", "cluster", "--region=" + region, "--name=" + clusterName}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
+	_, err = extcli.Eksctl.Run(args...)
+	helpers.RecordOperation(helpers.OperationCLIDelete, clusterName, start, err)
 	if err != nil {
-		return errors.Wrap(err, "Failed to delete cluster: "+out)
+		return errors.Wrap(err, "Failed to delete cluster")
 	}
 
 	fmt.Println("Deleted EKS cluster: ", clusterName)
+	helpers.MarkResourceDeleted(helpers.ResourceCloudCluster, clusterName)
 
 	return nil
 }
@@ -832,10 +1091,10 @@ func DeleteEKSClusterOnAWS(region string, clusterName string) error {
 // <==============================EKS CLI(end)==============================>
 
 // GetK8sVersion returns the k8s version to be used by the test;
-// this value can either be a variant of envvar DOWNSTREAM_K8S_MINOR_VERSION or the highest available version
+// this value can either be a variant of envvar DOWNSTREAM_K8S_MINOR_VERSION, the highest version matching it if it is a semver constraint, or the highest available version
 // or second-highest minor version in case of upgrade scenarios
 func GetK8sVersion(client *rancher.Client, forUpgrade bool) (string, error) {
-	if k8sVersion := helpers.DownstreamK8sMinorVersion; k8sVersion != "" {
+	if k8sVersion := helpers.DownstreamK8sMinorVersion; k8sVersion != "" && !helpers.IsK8sVersionConstraint(k8sVersion) {
 		return k8sVersion, nil
 	}
 	allVariants, err := ListEKSAllVersions(client)
@@ -843,5 +1102,9 @@ func GetK8sVersion(client *rancher.Client, forUpgrade bool) (string, error) {
 		return "", err
 	}
 
+	if constraint := helpers.DownstreamK8sMinorVersion; constraint != "" {
+		return helpers.MatchK8sVersion(allVariants, constraint)
+	}
+
 	return helpers.DefaultK8sVersion(allVariants, forUpgrade)
 }
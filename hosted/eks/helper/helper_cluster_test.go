@@ -0,0 +1,161 @@
+package helper
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"k8s.io/utils/pointer"
+)
+
+func TestEKSGetArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		cmd       string
+		extraArgs []string
+		want      []string
+	}{
+		{
+			name: "cluster",
+			cmd:  "cluster",
+			want: []string{"get", "cluster", "--region=us-west-2", "--name=my cluster'; rm -rf /", "-ojson"},
+		},
+		{
+			name: "nodegroup",
+			cmd:  "nodegroup",
+			want: []string{"get", "nodegroup", "--region=us-west-2", "--cluster=my cluster'; rm -rf /", "-ojson"},
+		},
+		{
+			name:      "extra args are appended as is",
+			cmd:       "nodegroup",
+			extraArgs: []string{"--name", "gpu $(nodes)"},
+			want:      []string{"get", "nodegroup", "--region=us-west-2", "--cluster=my cluster'; rm -rf /", "-ojson", "--name", "gpu $(nodes)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := eksGetArgs("us-west-2", "my cluster'; rm -rf /", tt.cmd, tt.extraArgs...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchNodeGroups(t *testing.T) {
+	nodeGroups := []management.NodeGroup{
+		{NodegroupName: pointer.String("ng1"), DesiredSize: pointer.Int64(1), MinSize: pointer.Int64(1), MaxSize: pointer.Int64(1)},
+		{NodegroupName: pointer.String("ng2"), DesiredSize: pointer.Int64(2), MinSize: pointer.Int64(2), MaxSize: pointer.Int64(2),
+			Labels: &map[string]string{"old": "label"}},
+	}
+	newNodeGroup := func() management.NodeGroup {
+		return management.NodeGroup{NodegroupName: pointer.String("new"), DesiredSize: pointer.Int64(1)}
+	}
+	batch := NodeGroupBatch{NodeCount: pointer.Int64(3), Labels: map[string]string{"batch": "true"}, Add: 1}
+
+	updated, added := batchNodeGroups(nodeGroups, batch, newNodeGroup)
+	if !reflect.DeepEqual(added, []string{"new"}) {
+		t.Errorf("got added %q, want [new]", added)
+	}
+	if names := nodeGroupNames(updated); !reflect.DeepEqual(names, []string{"new", "ng1", "ng2"}) {
+		t.Errorf("got nodegroups %q, want [new ng1 ng2]", names)
+	}
+	if !nodeGroupBatchApplied(updated, batch, added, 3) {
+		t.Errorf("the batch is not applied to %+v", updated)
+	}
+	for _, ng := range updated {
+		if *ng.MaxSize < 3 || (ng.MinSize != nil && *ng.MinSize > 3) {
+			t.Errorf("nodegroup %s can not be scaled to 3 nodes: min %v, max %d", *ng.NodegroupName, ng.MinSize, *ng.MaxSize)
+		}
+	}
+	// the given nodegroups are left as they are
+	if *nodeGroups[1].DesiredSize != 2 || (*nodeGroups[1].Labels)["old"] != "label" {
+		t.Errorf("the given nodegroups were changed: %+v", nodeGroups[1])
+	}
+	// the tags are left out of the batch
+	if nodeGroups[0].Tags != nil || updated[1].Tags != nil {
+		t.Errorf("the tags of the nodegroups were changed")
+	}
+}
+
+func TestNodeGroupBatchApplied(t *testing.T) {
+	batch := NodeGroupBatch{NodeCount: pointer.Int64(2), Tags: map[string]string{"owner": "e2e"}}
+	nodeGroup := func(name string, size int64, tags map[string]string) management.NodeGroup {
+		return management.NodeGroup{NodegroupName: pointer.String(name), DesiredSize: pointer.Int64(size), Tags: &tags}
+	}
+	tests := []struct {
+		name       string
+		nodeGroups []management.NodeGroup
+		want       bool
+	}{
+		{
+			name:       "applied",
+			nodeGroups: []management.NodeGroup{nodeGroup("new", 2, batch.Tags), nodeGroup("ng1", 2, batch.Tags)},
+			want:       true,
+		},
+		{
+			name:       "added nodegroup missing",
+			nodeGroups: []management.NodeGroup{nodeGroup("ng1", 2, batch.Tags), nodeGroup("ng2", 2, batch.Tags)},
+		},
+		{
+			name:       "not scaled yet",
+			nodeGroups: []management.NodeGroup{nodeGroup("new", 2, batch.Tags), nodeGroup("ng1", 1, batch.Tags)},
+		},
+		{
+			name:       "tags not updated yet",
+			nodeGroups: []management.NodeGroup{nodeGroup("new", 2, batch.Tags), nodeGroup("ng1", 2, map[string]string{"owner": "old"})},
+		},
+		{
+			name:       "nodegroup not deleted",
+			nodeGroups: []management.NodeGroup{nodeGroup("new", 2, batch.Tags), nodeGroup("ng1", 2, batch.Tags), nodeGroup("ng2", 2, batch.Tags)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nodeGroupBatchApplied(tt.nodeGroups, batch, []string{"new"}, 2); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInParallel(t *testing.T) {
+	var (
+		lock             sync.Mutex
+		running, maxRuns int
+		deleted          []string
+	)
+	err := inParallel([]string{"ng1", "ng2", "ng3", "ng4", "ng5"}, 2, func(name string) error {
+		lock.Lock()
+		running++
+		maxRuns = max(maxRuns, running)
+		lock.Unlock()
+		time.Sleep(10 * time.Millisecond)
+
+		lock.Lock()
+		defer lock.Unlock()
+		running--
+		if name == "ng2" || name == "ng4" {
+			return errors.New("nodegroup is in use")
+		}
+		deleted = append(deleted, name)
+		return nil
+	})
+
+	if maxRuns != 2 {
+		t.Errorf("got %d runs at once, want 2", maxRuns)
+	}
+	sort.Strings(deleted)
+	if !reflect.DeepEqual(deleted, []string{"ng1", "ng3", "ng5"}) {
+		t.Errorf("got %q run successfully, want all the others to run despite the failures", deleted)
+	}
+	if err == nil || err.Error() != "ng2: nodegroup is in use; ng4: nodegroup is in use" {
+		t.Errorf("got error %v, want the errors of all the failed runs", err)
+	}
+}
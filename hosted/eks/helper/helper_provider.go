@@ -0,0 +1,86 @@
+package helper
+
+import (
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+func init() {
+	helpers.RegisterProvider(eksProvider{})
+}
+
+// eksProvider is the EKS implementation of helpers.HostedProvider, in the region of GetEKSRegion
+type eksProvider struct{}
+
+func (eksProvider) Name() string {
+	return "eks"
+}
+
+func (eksProvider) Location() string {
+	return helpers.GetEKSRegion()
+}
+
+func (eksProvider) K8sVersion(client *rancher.Client, cloudCredID string, forUpgrade bool) (string, error) {
+	return GetK8sVersion(client, forUpgrade)
+}
+
+func (eksProvider) AvailableVersions(client *rancher.Client, cluster *management.Cluster) ([]string, error) {
+	return ListEKSAvailableVersions(client, cluster)
+}
+
+func (eksProvider) CreateCluster(client *rancher.Client, clusterName, cloudCredID, k8sVersion string) (*management.Cluster, error) {
+	return CreateEKSHostedCluster(client, clusterName, cloudCredID, k8sVersion, helpers.GetEKSRegion(), nil)
+}
+
+func (eksProvider) ImportCluster(client *rancher.Client, clusterName, cloudCredID string) (*management.Cluster, error) {
+	return ImportEKSHostedCluster(client, clusterName, cloudCredID, helpers.GetEKSRegion())
+}
+
+func (eksProvider) UpgradeCluster(cluster *management.Cluster, client *rancher.Client, k8sVersion string) (*management.Cluster, error) {
+	return UpgradeClusterKubernetesVersion(cluster, k8sVersion, client, true)
+}
+
+func (eksProvider) UpdateCluster(cluster *management.Cluster, client *rancher.Client, updateFunc func(*management.Cluster)) (*management.Cluster, error) {
+	return UpdateCluster(cluster, client, updateFunc)
+}
+
+func (eksProvider) AddNodePool(cluster *management.Cluster, client *rancher.Client) (*management.Cluster, error) {
+	return AddNodeGroup(cluster, 1, client, true, true)
+}
+
+func (eksProvider) DeleteNodePool(cluster *management.Cluster, client *rancher.Client) (*management.Cluster, error) {
+	return DeleteNodeGroup(cluster, client, true, true)
+}
+
+func (eksProvider) ScaleNodePools(cluster *management.Cluster, client *rancher.Client, nodeCount int64) (*management.Cluster, error) {
+	return ScaleNodeGroup(cluster, client, nodeCount, true, true)
+}
+
+func (eksProvider) DeleteCluster(cluster *management.Cluster, client *rancher.Client) error {
+	return DeleteEKSHostCluster(cluster, client)
+}
+
+func (eksProvider) OutOfBand() helpers.OutOfBandClient {
+	return eksOutOfBandClient{}
+}
+
+// eksOutOfBandClient manages the EKS clusters with eksctl; the location is the region of the cluster
+type eksOutOfBandClient struct{}
+
+func (eksOutOfBandClient) CreateCluster(clusterName, k8sVersion string) error {
+	return CreateEKSClusterOnAWS(helpers.GetEKSRegion(), clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+}
+
+func (eksOutOfBandClient) ClusterExists(clusterName, region string) (bool, error) {
+	return ClusterExistsOnAWS(region, clusterName)
+}
+
+func (eksOutOfBandClient) DeleteCluster(clusterName, region string) error {
+	return DeleteEKSClusterOnAWS(region, clusterName)
+}
+
+func (eksOutOfBandClient) UpdateTags(clusterName, region string, tags map[string]string) error {
+	return AddClusterTagsOnAWS(clusterName, region, tags)
+}
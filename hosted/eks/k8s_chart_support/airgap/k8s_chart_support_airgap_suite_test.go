@@ -0,0 +1,125 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package airgap_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var (
+	clusterName, k8sVersion string
+	ctx                     helpers.RancherContext
+	cluster                 *management.Cluster
+	region                  = helpers.GetEKSRegion()
+)
+
+func TestK8sChartSupportAirgap(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "K8sChartSupportAirgap Suite", helpers.SuiteConfig(t))
+}
+
+// The Rancher setup and the cloud credential are shared by the parallel processes
+var _ = SynchronizedBeforeSuite(func() []byte {
+	helpers.ValidateRunConfig(helpers.SuiteAirgap)
+	return helpers.ParallelSynchronizedBeforeSuite()
+}, func(setup []byte) {
+	ctx = helpers.ParallelBeforeSuite(setup)
+})
+
+var _ = BeforeEach(func() {
+	helpers.CollectSpecArtifacts()
+	helpers.RecordClusterTransitions(ctx.RancherAdminClient)
+
+	cluster = nil
+	clusterName = helpers.GenerateClusterName(helpers.ClusterNamePrefix)
+
+	var err error
+	k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, false)
+	Expect(err).To(BeNil())
+	GinkgoLogr.Info(fmt.Sprintf("Using EKS version %s for cluster %s", k8sVersion, clusterName))
+})
+
+var _ = JustAfterEach(func() {
+	// Collect the support bundle before AfterEach deletes the cluster
+	helpers.CollectSupportBundleOnFailure(clusterName)
+})
+
+// Delete the clusters leaked by all the processes once they are done
+var _ = SynchronizedAfterSuite(func() {}, helpers.CommonSynchronizedAfterSuite)
+
+var _ = ReportAfterEach(func(report SpecReport) {
+	// Add result in Qase if asked
+	helpers.ReportToQase(report)
+	// Upload the artifacts of the failed spec to ARTIFACTS_BUCKET if set
+	helpers.UploadArtifactsOnFailure(report)
+})
+
+var _ = ReportAfterSuite("Reports", func(report Report) {
+	// Write the JSON, JUnit and summary reports to the artifacts directory
+	helpers.GenerateSuiteReports(report)
+	// Submit the Qase results of all the parallel processes
+	helpers.SubmitQaseResults()
+})
+
+// airgapChartChecks checks that the operator charts come from the bundled system charts and the images from the private registry,
+// then uninstalls the operator charts and checks that rancher re-installs them the same way when the cluster is changed
+func airgapChartChecks() {
+	var originalChartVersion string
+	By("checking the chart version", func() {
+		originalChartVersion = helpers.GetCurrentOperatorChartVersion()
+		Expect(originalChartVersion).ToNot(BeEmpty())
+		GinkgoLogr.Info("Original chart version: " + originalChartVersion)
+	})
+	helpers.CheckOperatorChartsFromSystemCharts(ctx.RancherAdminClient)
+	helpers.CheckImagesFromPrivateRegistry(ctx.RancherAdminClient, cluster.ID)
+
+	By("uninstalling the operator chart", func() {
+		helpers.UninstallOperatorCharts()
+	})
+
+	initialNodeCount := *(*cluster.EKSConfig.NodeGroups)[0].DesiredSize
+	By("making a change(scaling nodegroup up) to the cluster to re-install the operator", func() {
+		var err error
+		cluster, err = helper.ScaleNodeGroup(cluster, ctx.RancherAdminClient, initialNodeCount+1, false, false)
+		Expect(err).To(BeNil())
+	})
+
+	By("ensuring that the chart is re-installed to the original version from the bundled system charts", func() {
+		helpers.WaitUntilOperatorChartInstallation(originalChartVersion, "", 0)
+		helpers.CheckRancherDeployments(kubectl.New())
+		helpers.CheckOperatorChartsFromSystemCharts(ctx.RancherAdminClient)
+	})
+
+	By("waiting for the re-installed operator to scale the nodegroup", func() {
+		var err error
+		cluster, err = helpers.WaitForUpstreamField(ctx.RancherAdminClient, cluster.ID, func(c *management.Cluster) any { return *(*c.EKSStatus.UpstreamSpec.NodeGroups)[0].DesiredSize }, initialNodeCount+1, 15*time.Minute)
+		Expect(err).To(BeNil())
+		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+	})
+
+	helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+	helpers.CheckImagesFromPrivateRegistry(ctx.RancherAdminClient, cluster.ID)
+}
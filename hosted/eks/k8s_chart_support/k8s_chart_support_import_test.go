@@ -9,6 +9,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("K8sChartSupportImport", func() {
@@ -34,8 +35,7 @@ var _ = Describe("K8sChartSupportImport", func() {
 		}
 	})
 
-	It("should successfully test k8s chart support import", func() {
-		testCaseID = 65 // Report to Qase
+	It("should successfully test k8s chart support import", helpers.QaseLabel(qasecases.EKSK8sChartSupportTestK8sChartSupportImport), func() {
 		commonchecks(ctx.RancherAdminClient, cluster)
 	})
 })
@@ -9,6 +9,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("K8sChartSupportProvisioning", func() {
@@ -29,8 +30,7 @@ var _ = Describe("K8sChartSupportProvisioning", func() {
 		}
 	})
 
-	It("should successfully test k8s chart support provisioning", func() {
-		testCaseID = 166
+	It("should successfully test k8s chart support provisioning", helpers.QaseLabel(qasecases.EKSK8sChartSupportTestK8sChartSupportProvisioning), func() {
 		commonchecks(ctx.RancherAdminClient, cluster)
 	})
 
@@ -2,6 +2,7 @@ package k8s_chart_support_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -9,10 +10,8 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
-	. "github.com/rancher-sandbox/qase-ginkgo"
 	"github.com/rancher/shepherd/clients/rancher"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
@@ -26,12 +25,11 @@ var (
 	ctx                     helpers.RancherContext
 	clusterName, k8sVersion string
 	region                  = helpers.GetEKSRegion()
-	testCaseID              int64
 )
 
 func TestK8sChartSupport(t *testing.T) {
 	RegisterFailHandler(Fail)
-	RunSpecs(t, "K8sChartSupport Suite")
+	RunSpecs(t, "K8sChartSupport Suite", helpers.SuiteConfig(t))
 }
 
 var _ = SynchronizedBeforeSuite(func() []byte {
@@ -47,8 +45,13 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 })
 
 var _ = BeforeEach(func() {
+	helpers.CollectSpecArtifacts()
+	helpers.RecordClusterTransitions(ctx.RancherAdminClient)
+	// Restore the settings and the operator charts changed by the test, for e.g. by a chart downgrade
+	DeferCleanup(helpers.SnapshotRancherSettings().Restore)
+
 	var err error
-	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+	clusterName = helpers.GenerateClusterName(helpers.ClusterNamePrefix)
 
 	k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, false)
 	Expect(err).To(BeNil())
@@ -58,21 +61,26 @@ var _ = BeforeEach(func() {
 
 })
 
-var _ = AfterEach(func() {
-
-	By("Uninstalling the existing operator charts", func() {
-		helpers.UninstallOperatorCharts()
-	})
+var _ = JustAfterEach(func() {
+	// Collect the support bundle before AfterEach deletes the cluster
+	helpers.CollectSupportBundleOnFailure(clusterName)
 })
 
-var _ = ReportBeforeEach(func(report SpecReport) {
-	// Reset case ID
-	testCaseID = -1
-})
+// Delete the clusters leaked by all the processes once they are done
+var _ = SynchronizedAfterSuite(func() {}, helpers.CommonSynchronizedAfterSuite)
 
 var _ = ReportAfterEach(func(report SpecReport) {
 	// Add result in Qase if asked
-	Qase(testCaseID, report)
+	helpers.ReportToQase(report)
+	// Upload the artifacts of the failed spec to ARTIFACTS_BUCKET if set
+	helpers.UploadArtifactsOnFailure(report)
+})
+
+var _ = ReportAfterSuite("Reports", func(report Report) {
+	// Write the JSON, JUnit and summary reports to the artifacts directory
+	helpers.GenerateSuiteReports(report)
+	// Submit the Qase results of all the parallel processes
+	helpers.SubmitQaseResults()
 })
 
 func commonchecks(client *rancher.Client, cluster *management.Cluster) {
@@ -84,25 +92,42 @@ func commonchecks(client *rancher.Client, cluster *management.Cluster) {
 		GinkgoLogr.Info("Original chart version: " + originalChartVersion)
 	})
 
-	var downgradedVersion string
-	By("obtaining a version to downgrade", func() {
-		downgradedVersion = helpers.GetDowngradeOperatorChartVersion(originalChartVersion)
-		Expect(downgradedVersion).ToNot(BeEmpty())
-		GinkgoLogr.Info("Downgrading to version: " + downgradedVersion)
-	})
-
-	By("downgrading the chart version", func() {
-		helpers.DowngradeProviderChart(downgradedVersion)
+	var downgradedVersions []string
+	By(fmt.Sprintf("obtaining up to %d versions to downgrade", helpers.ChartDowngradeVersions), func() {
+		downgradedVersions = helpers.GetDowngradeOperatorChartVersions(originalChartVersion, helpers.ChartDowngradeVersions)
+		Expect(downgradedVersions).ToNot(BeEmpty())
+		GinkgoLogr.Info("Downgrading to versions: " + strings.Join(downgradedVersions, ", "))
 	})
 
 	configNodeGroups := *cluster.EKSConfig.NodeGroups
 	initialNodeCount := *configNodeGroups[0].DesiredSize
 
-	By("making a change(scaling nodegroup up) to the cluster to validate functionality after chart downgrade", func() {
-		var err error
-		cluster, err = helper.ScaleNodeGroup(cluster, client, initialNodeCount+increaseBy, true, true)
-		Expect(err).To(BeNil())
-	})
+	for i, downgradedVersion := range downgradedVersions {
+		By(fmt.Sprintf("downgrading the chart version to %s", downgradedVersion), func() {
+			helpers.DowngradeProviderChart(downgradedVersion)
+		})
+
+		By("making a change(scaling nodegroup up) to the cluster to validate functionality after chart downgrade", func() {
+			var err error
+			cluster, err = helper.ScaleNodeGroup(cluster, client, initialNodeCount+increaseBy, true, true)
+			Expect(err).To(BeNil())
+		})
+
+		// the chart of the last cycle is upgraded by rancher, which re-installs it once uninstalled
+		if i == len(downgradedVersions)-1 {
+			break
+		}
+
+		By(fmt.Sprintf("upgrading the chart version back to %s", originalChartVersion), func() {
+			helpers.UpdateOperatorChartsVersion(originalChartVersion)
+		})
+
+		By("making a change(scaling nodegroup down) to the cluster to validate functionality after chart upgrade", func() {
+			var err error
+			cluster, err = helper.ScaleNodeGroup(cluster, client, initialNodeCount, true, true)
+			Expect(err).To(BeNil())
+		})
+	}
 
 	By("uninstalling the operator chart", func() {
 		helpers.UninstallOperatorCharts()
@@ -122,7 +147,7 @@ func commonchecks(client *rancher.Client, cluster *management.Cluster) {
 		})
 
 		// We do not use WaitClusterToBeUpgraded because it has been flaky here and times out
-		Eventually(func() bool {
+		helpers.EventuallyWithBackoff(func() bool {
 			GinkgoLogr.Info("Waiting for the node count change to appear in EKSStatus.UpstreamSpec ...")
 			Expect(err).To(BeNil())
 			cluster, err = client.Management.Cluster.ByID(cluster.ID)
@@ -137,4 +162,10 @@ func commonchecks(client *rancher.Client, cluster *management.Cluster) {
 
 	})
 
+	if helpers.IsRancherBehindProxy() {
+		By("checking that the re-installed operator works through the proxy", func() {
+			helpers.CheckProxyConfiguration()
+			helpers.CheckOperatorTrafficThroughProxy()
+		})
+	}
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("K8sChartSupportUpgradeProvisioning", func() {
@@ -28,10 +29,9 @@ var _ = Describe("K8sChartSupportUpgradeProvisioning", func() {
 			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
 		}
 	})
-	It("should successfully test k8s chart support provisioning in an upgrade scenario", func() {
+	It("should successfully test k8s chart support provisioning in an upgrade scenario", helpers.QaseLabel(qasecases.EKSK8sChartSupportUpgradeTestK8sChartSupportProvisioningInUpgradeScenario), func() {
 		GinkgoLogr.Info(fmt.Sprintf("Testing K8s %s chart support for provisioning on Rancher upgraded from %s to %s", helpers.K8sUpgradedMinorVersion, helpers.RancherFullVersion, helpers.RancherUpgradeFullVersion))
 
-		testCaseID = 165
 		commonchecks(&ctx, cluster, clusterName, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
 	})
 
@@ -0,0 +1,68 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package location_matrix_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("LocationMatrixProvisioning", func() {
+
+	for _, region := range helpers.GetEKSMatrixRegions() {
+		region := region
+
+		When(fmt.Sprintf("a cluster is created in region %s", region), func() {
+			var (
+				clusterName string
+				cluster     *management.Cluster
+			)
+			BeforeEach(func() {
+				clusterName = helpers.GenerateClusterName(helpers.ClusterNamePrefix)
+			})
+			JustAfterEach(func() {
+				helpers.CollectSupportBundleOnFailure(clusterName)
+			})
+			AfterEach(func() {
+				if ctx.ClusterCleanup && cluster != nil {
+					err := helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)
+					Expect(err).To(BeNil())
+				} else {
+					fmt.Println("Skipping downstream cluster deletion: ", clusterName)
+				}
+			})
+			It("should successfully provision the cluster in the region", func() {
+				k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, false)
+				Expect(err).To(BeNil())
+				GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s in region %s", k8sVersion, clusterName, region))
+
+				cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
+				Expect(err).To(BeNil())
+				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				Expect(err).To(BeNil())
+				helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+
+				Expect(cluster.EKSStatus.UpstreamSpec.Region).To(Equal(region))
+				Expect(*cluster.EKSStatus.UpstreamSpec.KubernetesVersion).To(Equal(k8sVersion))
+			})
+		})
+	}
+})
@@ -25,23 +25,24 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("P0Provisioning", func() {
 	for _, testData := range []struct {
-		qaseID    int64
+		qaseID    qasecases.CaseID
 		isUpgrade bool
 		testBody  func(cluster *management.Cluster, client *rancher.Client, clusterName string)
 		testTitle string
 	}{
 		{
-			qaseID:    71,
+			qaseID:    qasecases.EKSP0ProvisionClusterAddDeleteScaleNodepool71,
 			isUpgrade: false,
 			testBody:  p0NodesChecks,
 			testTitle: "should successfully provision the cluster & add, delete, scale nodepool",
 		},
 		{
-			qaseID:    74,
+			qaseID:    qasecases.EKSP0UpgradeK8sVersionOfProvisionedCluster,
 			isUpgrade: true,
 			testBody:  p0upgradeK8sVersionChecks,
 			testTitle: "should be able to upgrade k8s version of the provisioned cluster",
@@ -54,15 +55,23 @@ var _ = Describe("P0Provisioning", func() {
 					Skip(helpers.SkipUpgradeTestsLog)
 				}
 
-				k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, testData.isUpgrade)
-				Expect(err).To(BeNil())
-				GinkgoLogr.Info(fmt.Sprintf("While provisioning, using K8s version %s for cluster %s", k8sVersion, clusterName))
-				cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
-				Expect(err).To(BeNil())
-				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				var err error
+				cluster, err = helpers.PreProvisionedCluster(ctx.RancherAdminClient, func() (*management.Cluster, error) {
+					k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, testData.isUpgrade)
+					Expect(err).To(BeNil())
+					GinkgoLogr.Info(fmt.Sprintf("While provisioning, using K8s version %s for cluster %s", k8sVersion, clusterName))
+					cluster, err := helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
+					Expect(err).To(BeNil())
+					return helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				})
 				Expect(err).To(BeNil())
+				clusterName = cluster.Name
 			})
 			AfterEach(func() {
+				if helpers.IsPreProvisionedCluster(cluster) {
+					GinkgoLogr.Info(fmt.Sprintf("Keeping the pre-provisioned cluster: %s", clusterName))
+					return
+				}
 				if ctx.ClusterCleanup {
 					if cluster != nil && cluster.ID != "" {
 						GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
@@ -74,8 +83,7 @@ var _ = Describe("P0Provisioning", func() {
 				}
 			})
 
-			It(testData.testTitle, func() {
-				testCaseID = testData.qaseID
+			It(testData.testTitle, helpers.QaseLabel(testData.qaseID), Label(helpers.CriticalSpec), func() {
 				testData.testBody(cluster, ctx.RancherAdminClient, clusterName)
 			})
 
@@ -9,6 +9,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("P1Import", func() {
@@ -65,32 +66,29 @@ var _ = Describe("P1Import", func() {
 				Expect(err).To(BeNil())
 			})
 
-			It("Upgrade version of node group only", func() {
-				testCaseID = 88
+			It("Upgrade version of node group only", helpers.QaseLabel(qasecases.EKSP1UpgradeVersionOfNodeGroupOnly88), func() {
 				upgradeNodeKubernetesVersionGTCPCheck(cluster, ctx.RancherAdminClient, upgradeToVersion)
 			})
 
 			// eks-operator/issues/752
-			XIt("should successfully update a cluster while it is still in updating state", func() {
-				testCaseID = 104
+			XIt("should successfully update a cluster while it is still in updating state", helpers.QaseLabel(qasecases.EKSP1UpdateClusterWhileItIsStillInUpdating104), func() {
 				updateClusterInUpdatingState(cluster, ctx.RancherAdminClient, upgradeToVersion)
 			})
 
-			It("Update k8s version of cluster and add node groups", func() {
-				testCaseID = 90
+			It("Update k8s version of cluster and add node groups", helpers.QaseLabel(qasecases.EKSP1UpdateK8sVersionOfClusterAndAddNode90), func() {
 				upgradeCPAndAddNgCheck(cluster, ctx.RancherAdminClient, upgradeToVersion)
 			})
 		})
 	})
 
-	It("should successfully Import cluster with ONLY control plane", func() {
-		testCaseID = 94
+	It("should successfully Import cluster with ONLY control plane", helpers.QaseLabel(qasecases.EKSP1ImportClusterWithONLYControlPlane), func() {
 		err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels(), "--without-nodegroup")
 		Expect(err).To(BeNil())
 		cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
 		Expect(err).To(BeNil())
-		Eventually(func() bool {
-			cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+		helpers.AllowClusterErrors()
+		helpers.EventuallyWithBackoff(func() bool {
+			cluster, err = helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 			Expect(err).To(BeNil())
 			return cluster.Transitioning == "error" && cluster.TransitioningMessage == "Cluster must have at least one managed nodegroup or one self-managed node."
 		}, "5m", "2s").Should(BeTrue())
@@ -103,8 +101,19 @@ var _ = Describe("P1Import", func() {
 		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
 	})
 
-	It("successfully import EKS cluster with self-managed nodes", func() {
-		testCaseID = 107
+	It("should import a cluster with pre-existing workloads and leave them untouched", func() {
+		err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+		Expect(err).To(BeNil())
+		fingerprints := helpers.DeployPreImportWorkloads(clusterName)
+
+		cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
+		Expect(err).To(BeNil())
+		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+		helpers.CheckPreImportWorkloads(ctx.RancherAdminClient, cluster, fingerprints)
+	})
+
+	It("successfully import EKS cluster with self-managed nodes", helpers.QaseLabel(qasecases.EKSP1ImportEKSClusterWithSelfManagedNodes), func() {
 		err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels(), "--managed=false")
 		Expect(err).To(BeNil())
 		cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
@@ -127,8 +136,7 @@ var _ = Describe("P1Import", func() {
 			Expect(err).To(BeNil())
 		})
 
-		It("should successfully Import cluster with at least 2 nodegroups", func() {
-			testCaseID = 105
+		It("should successfully Import cluster with at least 2 nodegroups", helpers.QaseLabel(qasecases.EKSP1ImportClusterWithAtLeast2Nodegroups), func() {
 			helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
 		})
 	})
@@ -144,15 +152,14 @@ var _ = Describe("P1Import", func() {
 			Expect(err).To(BeNil())
 		})
 
-		It("Delete & re-import cluster", func() {
-			testCaseID = 106
+		It("Delete & re-import cluster", helpers.QaseLabel(qasecases.EKSP1DeleteReImportCluster), func() {
 
 			var err error
 			err = helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 
-			Eventually(func() string {
-				cluster, _ = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			helpers.EventuallyWithBackoff(func() string {
+				cluster, _ = helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 				return cluster.ID
 			}, "30s", "3s").Should(BeEmpty())
 
@@ -162,26 +169,23 @@ var _ = Describe("P1Import", func() {
 			Expect(err).To(BeNil())
 		})
 
-		It("Update cluster logging types", func() {
-			testCaseID = 77
+		It("Update cluster logging types", helpers.QaseLabel(qasecases.EKSP1UpdateClusterLoggingTypes77), func() {
 			updateLoggingCheck(cluster, ctx.RancherAdminClient)
 		})
 
-		It("Update Tags and Labels", func() {
-			testCaseID = 81
+		It("Update Tags and Labels", helpers.QaseLabel(qasecases.EKSP1UpdateTagsAndLabels81), func() {
 			updateTagsAndLabels(cluster, ctx.RancherAdminClient)
 		})
 
-		It("Add a nodegroup in EKS -> Syncs to Rancher -> Update cluster, the nodegroup is intact", func() {
-			testCaseID = 87
+		It("Add a nodegroup in EKS -> Syncs to Rancher -> Update cluster, the nodegroup is intact", helpers.QaseLabel(qasecases.EKSP1AddNodegroupInEKSSyncsRancherUpdateCluster), func() {
 			nodepoolcount := len(*cluster.EKSStatus.UpstreamSpec.NodeGroups)
 			err := helper.AddNodeGroupOnAWS(namegen.AppendRandomString("ng"), clusterName, region)
 			Expect(err).To(BeNil())
-			Eventually(func() bool {
-				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			helpers.EventuallyForOperation(helpers.OperationAddNodePool, func() bool {
+				cluster, err = helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 				Expect(err).To(BeNil())
 				return len(*cluster.EKSStatus.UpstreamSpec.NodeGroups) == nodepoolcount+1
-			}, "10m", "7s").Should(BeTrue(), "Timed out while waiting for rancher to sync")
+			}, "10m").Should(BeTrue(), "Timed out while waiting for rancher to sync")
 			cluster, err = helper.UpdateLogging(cluster, ctx.RancherAdminClient, []string{"authenticator"}, true)
 			Expect(err).To(BeNil())
 
@@ -189,14 +193,12 @@ var _ = Describe("P1Import", func() {
 			Expect(*cluster.EKSStatus.UpstreamSpec.NodeGroups).To(HaveLen(nodepoolcount + 1))
 		})
 
-		It("Update the cloud creds", func() {
-			testCaseID = 155
+		It("Update the cloud creds", helpers.QaseLabel(qasecases.EKSP1UpdateCloudCreds155), func() {
 			updateCloudCredentialsCheck(cluster, ctx.RancherAdminClient)
 		})
 
 		Context("Reimporting/Editing a cluster with invalid config", func() {
-			It("Reimport a cluster to Rancher should fail", func() {
-				testCaseID = 101
+			It("Reimport a cluster to Rancher should fail", helpers.QaseLabel(qasecases.EKSP1ReimportClusterRancherFail), func() {
 
 				// We do not assign the cluster returned by import function to `cluster` since it will be nil and the cluster won't be deleted in AfterEach
 				_, err := helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
@@ -204,8 +206,7 @@ var _ = Describe("P1Import", func() {
 				Expect(err).To(MatchError(ContainSubstring("cluster already exists for EKS cluster")))
 			})
 
-			It("Add node groups to the control-plane only cluster", func() {
-				testCaseID = 95
+			It("Add node groups to the control-plane only cluster", helpers.QaseLabel(qasecases.EKSP1AddNodeGroupsControlPlaneOnlyCluster), func() {
 
 				var err error
 				err = helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)
@@ -215,8 +216,8 @@ var _ = Describe("P1Import", func() {
 
 				cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
 				Expect(err).To(BeNil())
-				Eventually(func() bool {
-					cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+				helpers.EventuallyWithBackoff(func() bool {
+					cluster, err = helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 					Expect(err).To(BeNil())
 					return cluster.State == "waiting"
 				}, "5m", "15s").Should(BeTrue())
@@ -229,8 +230,7 @@ var _ = Describe("P1Import", func() {
 				})
 			})
 
-			It("Fail to update both Public/Private access as false and invalid values of the access", func() {
-				testCaseID = 103 // also covers 102
+			It("Fail to update both Public/Private access as false and invalid values of the access", helpers.QaseLabel(qasecases.EKSP1FailUpdateBothPublicPrivateAccessAsFalse103, qasecases.EKSP1FailUpdateBothPublicPrivateAccessAsFalse102), func() {
 				invalidEndpointCheck(cluster, ctx.RancherAdminClient)
 				invalidAccessValuesCheck(cluster, ctx.RancherAdminClient)
 			})
@@ -4,11 +4,11 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	"github.com/rancher/shepherd/extensions/clusters"
 	"github.com/rancher/shepherd/extensions/clusters/eks"
 	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 
@@ -16,6 +16,8 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
+	"github.com/rancher/hosted-providers-e2e/pkg/cloudjson"
 )
 
 var _ = Describe("P1Provisioning", func() {
@@ -33,7 +35,8 @@ var _ = Describe("P1Provisioning", func() {
 
 	AfterEach(func() {
 		if ctx.ClusterCleanup {
-			if cluster != nil && cluster.ID != "" {
+			// the recycled cluster is deleted at the end of the suite
+			if cluster != nil && cluster.ID != "" && !helpers.IsRecycledCluster(cluster) {
 				GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
 				err := helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)
 				Expect(err).To(BeNil())
@@ -45,8 +48,26 @@ var _ = Describe("P1Provisioning", func() {
 
 	Context("Provisioning/Editing a cluster with invalid config", func() {
 
-		It("should error out to provision a cluster when nodegroups is nil", func() {
-			testCaseID = 141
+		It("should surface the cloud error when the nodegroups exceed the account quota", func() {
+			updateFunc := func(clusterConfig *eks.ClusterConfig) {
+				nodeGroups := *clusterConfig.NodeGroupsConfig
+				for i := range nodeGroups {
+					nodeGroups[i].DesiredSize = pointer.Int64(helpers.QuotaExceededNodeCount)
+					nodeGroups[i].MaxSize = pointer.Int64(helpers.QuotaExceededNodeCount)
+				}
+			}
+			var err error
+			cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, updateFunc)
+			Expect(err).To(BeNil())
+			DeferCleanup(helpers.CleanupFailedCluster, ctx.RancherAdminClient, cluster, helper.DeleteEKSHostCluster, func() (bool, error) {
+				return helper.ClusterExistsOnAWS(region, clusterName)
+			})
+
+			// the nodegroups are only created once the control plane is active
+			cluster = helpers.WaitForQuotaError(ctx.RancherAdminClient, cluster, 30*time.Minute)
+		})
+
+		It("should error out to provision a cluster when nodegroups is nil", helpers.QaseLabel(qasecases.EKSP1ErrorOutProvisionClusterWhenNodegroupsIsNil), func() {
 
 			updateFunc := func(clusterConfig *eks.ClusterConfig) {
 				clusterConfig.NodeGroupsConfig = nil
@@ -56,8 +77,9 @@ var _ = Describe("P1Provisioning", func() {
 			cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, updateFunc)
 			Expect(err).To(BeNil())
 
-			Eventually(func() bool {
-				cluster, err := ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			helpers.AllowClusterErrors()
+			helpers.EventuallyWithBackoff(func() bool {
+				cluster, err := helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 				Expect(err).To(BeNil())
 				return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "Cluster must have at least one managed nodegroup or one self-managed node")
 			}, "10m", "30s").Should(BeTrue())
@@ -74,8 +96,7 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(err.Error()).To(ContainSubstring("must have at least one nodegroup"))
 		})
 
-		It("should fail to provision a cluster with duplicate nodegroup names", func() {
-			testCaseID = 255
+		It("should fail to provision a cluster with duplicate nodegroup names", helpers.QaseLabel(qasecases.EKSP1FailProvisionClusterWithDuplicateNodegroupNames), func() {
 
 			var err error
 			updateFunc := func(clusterConfig *eks.ClusterConfig) {
@@ -92,8 +113,9 @@ var _ = Describe("P1Provisioning", func() {
 			cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, updateFunc)
 			Expect(err).To(BeNil())
 
-			Eventually(func() bool {
-				cluster, err := ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			helpers.AllowClusterErrors()
+			helpers.EventuallyWithBackoff(func() bool {
+				cluster, err := helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 				Expect(err).To(BeNil())
 				// checking for both the messages since different operator version shows different messages. To be removed once the message is updated.
 				// New Message: NodePool names must be unique within the [c-dnzzk] cluster to avoid duplication
@@ -101,8 +123,7 @@ var _ = Describe("P1Provisioning", func() {
 			}, "1m", "3s").Should(BeTrue())
 		})
 
-		It("Fail to create cluster with different k8s versions on control plane and on nodegroup", func() {
-			testCaseID = 127
+		It("Fail to create cluster with different k8s versions on control plane and on nodegroup", helpers.QaseLabel(qasecases.EKSP1FailCreateClusterWithDifferentK8sVersionsOn), func() {
 
 			k8sVersions, err := helper.ListEKSAllVersions(ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
@@ -123,15 +144,15 @@ var _ = Describe("P1Provisioning", func() {
 			cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, cpK8sVersion, region, updateFunc)
 			Expect(err).To(BeNil())
 
-			Eventually(func() bool {
-				cluster, err := ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			helpers.AllowClusterErrors()
+			helpers.EventuallyWithBackoff(func() bool {
+				cluster, err := helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 				Expect(err).To(BeNil())
 				return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "version must match cluster")
 			}, "1m", "3s").Should(BeTrue())
 		})
 
-		It("Fail to create cluster with only Security groups", func() {
-			testCaseID = 120
+		It("Fail to create cluster with only Security groups", helpers.QaseLabel(qasecases.EKSP1FailCreateClusterWithOnlySecurityGroups), func() {
 
 			sg := []string{namegen.AppendRandomString("sg-"), namegen.AppendRandomString("sg-")}
 			updateFunc := func(clusterConfig *eks.ClusterConfig) {
@@ -143,8 +164,7 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(err).To(MatchError(ContainSubstring("subnets must be provided if security groups are provided")))
 		})
 
-		It("Fail to update both Public/Private access as false and invalid values of the access", func() {
-			testCaseID = 147 // also covers 146
+		It("Fail to update both Public/Private access as false and invalid values of the access", helpers.QaseLabel(qasecases.EKSP1FailUpdateBothPublicPrivateAccessAsFalse147, qasecases.EKSP1FailUpdateBothPublicPrivateAccessAsFalse146), func() {
 
 			var err error
 			cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
@@ -156,8 +176,7 @@ var _ = Describe("P1Provisioning", func() {
 		})
 	})
 
-	It("should successfully Provision EKS with secrets encryption (KMS)", func() {
-		testCaseID = 149
+	It("should successfully Provision EKS with secrets encryption (KMS)", helpers.QaseLabel(qasecases.EKSP1ProvisionEKSWithSecretsEncryptionKMS), func() {
 		createFunc := func(clusterConfig *eks.ClusterConfig) {
 			clusterConfig.KmsKey = pointer.String(os.Getenv("AWS_KMS_KEY"))
 		}
@@ -170,12 +189,11 @@ var _ = Describe("P1Provisioning", func() {
 
 	})
 
-	It("should successfully Provision EKS from Rancher with Enabled GPU feature", func() {
+	It("should successfully Provision EKS from Rancher with Enabled GPU feature", helpers.QaseLabel(qasecases.EKSP1ProvisionEKSFromRancherWithEnabledGPUFeature), func() {
 		if helpers.SkipTest {
 			Skip("Skipping test for v2.8, v2.9 ...")
 		}
 
-		testCaseID = 274
 		var gpuNodeName = "gpuenabled"
 		createFunc := func(clusterConfig *eks.ClusterConfig) {
 			nodeGroups := *clusterConfig.NodeGroupsConfig
@@ -194,16 +212,17 @@ var _ = Describe("P1Provisioning", func() {
 		Expect(err).To(BeNil())
 
 		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
-		var amiID string
-		amiID, err = helper.GetFromEKS(region, clusterName, "nodegroup", ".[].ImageID", "--name", gpuNodeName)
+		var nodegroups []cloudjson.EKSNodegroup
+		nodegroups, err = helper.ListEKSNodegroupsOnAWS(region, clusterName, "--name", gpuNodeName)
 		Expect(err).To(BeNil())
+		Expect(nodegroups).To(HaveLen(1))
+		amiID := nodegroups[0].ImageID
 		GinkgoLogr.Info(fmt.Sprintf("Used AMI for GPU enabled nodegroup in EKS cluster: %s", amiID))
 		Expect(amiID).To(Or(Equal("AL2_x86_64_GPU"), Equal("AL2023_x86_64_NVIDIA")))
 	})
 
-	XIt("Deploy a cluster with Public/Priv access then disable Public access", func() {
+	XIt("Deploy a cluster with Public/Priv access then disable Public access", helpers.QaseLabel(qasecases.EKSP1DeployClusterWithPublicPrivAccessThenDisable), func() {
 		// https://github.com/rancher/eks-operator/issues/752#issuecomment-2609144199
-		testCaseID = 151
 		createFunc := func(clusterConfig *eks.ClusterConfig) {
 			clusterConfig.PublicAccess = pointer.Bool(true)
 			clusterConfig.PrivateAccess = pointer.Bool(true)
@@ -246,19 +265,20 @@ var _ = Describe("P1Provisioning", func() {
 				Expect(err).To(BeNil())
 			})
 
-			It("Upgrade version of node group only", func() {
-				testCaseID = 126
+			It("Upgrade version of node group only", helpers.QaseLabel(qasecases.EKSP1UpgradeVersionOfNodeGroupOnly126), func() {
 				upgradeNodeKubernetesVersionGTCPCheck(cluster, ctx.RancherAdminClient, upgradeToVersion)
 			})
 
-			It("Update k8s version of cluster and add node groups", func() {
-				testCaseID = 125
+			It("Update k8s version of cluster and add node groups", helpers.QaseLabel(qasecases.EKSP1UpdateK8sVersionOfClusterAndAddNode125), func() {
 				upgradeCPAndAddNgCheck(cluster, ctx.RancherAdminClient, upgradeToVersion)
 			})
 
+			It("should upgrade the control plane and the node groups submitted in a single update in order", func() {
+				upgradeCPAndNgAtOnceCheck(cluster, ctx.RancherAdminClient, upgradeToVersion)
+			})
+
 			// eks-operator/issues/752
-			XIt("should successfully update a cluster while it is still in updating state", func() {
-				testCaseID = 148
+			XIt("should successfully update a cluster while it is still in updating state", helpers.QaseLabel(qasecases.EKSP1UpdateClusterWhileItIsStillInUpdating148), func() {
 				updateClusterInUpdatingState(cluster, ctx.RancherAdminClient, upgradeToVersion)
 			})
 		})
@@ -276,8 +296,7 @@ var _ = Describe("P1Provisioning", func() {
 				Expect(err).To(BeNil())
 			})
 
-			It("Update k8s version of node groups - sequential & simultaneous upgrade of multiple node groups", func() {
-				testCaseID = 153
+			It("Update k8s version of node groups - sequential & simultaneous upgrade of multiple node groups", helpers.QaseLabel(qasecases.EKSP1UpdateK8sVersionOfNodeGroupsSequentialSimultaneous), func() {
 				var err error
 				cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, upgradeToVersion, ctx.RancherAdminClient, true)
 				Expect(err).To(BeNil())
@@ -291,10 +310,10 @@ var _ = Describe("P1Provisioning", func() {
 				}
 				cluster, err = helper.UpdateCluster(cluster, ctx.RancherAdminClient, updateFunc)
 				Expect(err).To(BeNil())
-				err = clusters.WaitClusterToBeUpgraded(ctx.RancherAdminClient, cluster.ID)
+				err = helpers.WaitClusterToBeUpgraded(ctx.RancherAdminClient, cluster.ID)
 				Expect(err).To(BeNil())
-				Eventually(func() bool {
-					cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+				helpers.EventuallyForOperation(helpers.OperationNodeUpgrade, func() bool {
+					cluster, err = helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 					Expect(err).To(BeNil())
 					nodeGroups := *cluster.EKSStatus.UpstreamSpec.NodeGroups
 					for i := 0; i <= 1; i++ {
@@ -303,42 +322,51 @@ var _ = Describe("P1Provisioning", func() {
 						}
 					}
 					return true
-				}, "10m", "7s").Should(BeTrue())
+				}, "10m").Should(BeTrue())
 
 				// upgrade the remaining nodegroups
 				cluster, err = helper.UpgradeNodeKubernetesVersion(cluster, upgradeToVersion, ctx.RancherAdminClient, true, true, false)
 				Expect(err).To(BeNil())
 			})
 		})
+
+		It("should scale the nodegroups to zero and back", func() {
+			scaleToZeroCheck(cluster, ctx.RancherAdminClient)
+		})
 	})
 
 	When("a cluster is created", func() {
 
+		// these specs only update the config of the cluster, they share it with RECYCLE_CLUSTER
 		BeforeEach(func() {
 			var err error
-			cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
-			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			cluster, err = helpers.RecycleCluster(ctx.RancherAdminClient, func() (*management.Cluster, error) {
+				cluster, err := helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
+				if err != nil {
+					return cluster, err
+				}
+				return helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			}, func(cluster *management.Cluster) error {
+				return helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)
+			})
 			Expect(err).To(BeNil())
+			// the recycled cluster is not the one named by the suite BeforeEach, for e.g. for the support bundle
+			clusterName = cluster.Name
 		})
 
-		It("Update cluster logging types", func() {
-			testCaseID = 128
+		It("Update cluster logging types", helpers.QaseLabel(qasecases.EKSP1UpdateClusterLoggingTypes128), func() {
 			updateLoggingCheck(cluster, ctx.RancherAdminClient)
 		})
 
-		It("Update Tags and Labels", func() {
-			testCaseID = 131
+		It("Update Tags and Labels", helpers.QaseLabel(qasecases.EKSP1UpdateTagsAndLabels131), func() {
 			updateTagsAndLabels(cluster, ctx.RancherAdminClient)
 		})
 
-		It("Update the cloud creds", func() {
-			testCaseID = 109
+		It("Update the cloud creds", helpers.QaseLabel(qasecases.EKSP1UpdateCloudCreds109), func() {
 			updateCloudCredentialsCheck(cluster, ctx.RancherAdminClient)
 		})
 
-		It("should fail to Delete all Node groups", func() {
-			testCaseID = 134
+		It("should fail to Delete all Node groups", helpers.QaseLabel(qasecases.EKSP1FailDeleteAllNodeGroups), func() {
 			deleteAllNodeGroupsCheck(cluster, ctx.RancherAdminClient)
 		})
 	})
@@ -0,0 +1,69 @@
+package p1_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("P1Reimport", func() {
+	BeforeEach(func() {
+		k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, false)
+		Expect(err).To(BeNil())
+		GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s", k8sVersion, clusterName))
+
+		err = helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+		Expect(err).To(BeNil())
+		cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
+		Expect(err).To(BeNil())
+		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		if ctx.ClusterCleanup {
+			if cluster != nil && cluster.ID != "" {
+				GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
+				err := helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)
+				Expect(err).To(BeNil())
+			}
+			err := helper.DeleteEKSClusterOnAWS(region, clusterName)
+			Expect(err).To(BeNil())
+		} else {
+			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
+		}
+	})
+
+	It("should re-import the cluster once it is deleted from Rancher only", func() {
+		By("deleting the cluster from Rancher", func() {
+			Expect(helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)).To(Succeed())
+			helpers.WaitUntilClusterIsRemoved(ctx.RancherAdminClient, cluster.ID)
+			// marking as nil so that AfterEach does not delete it again
+			cluster = nil
+		})
+
+		By("checking the cluster still exists on AWS", func() {
+			exists, err := helper.ClusterExistsOnAWS(region, clusterName)
+			Expect(err).To(BeNil())
+			Expect(exists).To(BeTrue())
+		})
+
+		var err error
+		By("re-importing the cluster", func() {
+			cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
+			Expect(err).To(BeNil())
+			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+		})
+		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+
+		By("adding a nodegroup to the re-imported cluster", func() {
+			cluster, err = helper.AddNodeGroup(cluster, 1, ctx.RancherAdminClient, true, true)
+			Expect(err).To(BeNil())
+		})
+	})
+})
@@ -17,7 +17,6 @@ package p1_test
 import (
 	"fmt"
 	"maps"
-	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -25,7 +24,6 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
-	. "github.com/rancher-sandbox/qase-ginkgo"
 	"github.com/rancher/shepherd/clients/rancher"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
 	"github.com/rancher/shepherd/extensions/clusters"
@@ -36,42 +34,55 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/pkg/cloudjson"
 )
 
 var (
 	ctx         helpers.RancherContext
 	cluster     *management.Cluster
 	clusterName string
-	testCaseID  int64
 	region      = helpers.GetEKSRegion()
 )
 
 func TestP1(t *testing.T) {
 	RegisterFailHandler(Fail)
-	RunSpecs(t, "P1 Suite")
+	RunSpecs(t, "P1 Suite", helpers.SuiteConfig(t))
 }
 
-var _ = SynchronizedBeforeSuite(func() []byte {
-	helpers.CommonSynchronizedBeforeSuite()
-	return nil
-}, func() {
-	ctx = helpers.CommonBeforeSuite()
+// The Rancher setup and the cloud credential are shared by the parallel processes; every process provisions its own clusters
+var _ = SynchronizedBeforeSuite(helpers.ParallelSynchronizedBeforeSuite, func(setup []byte) {
+	ctx = helpers.ParallelBeforeSuite(setup)
 })
 
 var _ = BeforeEach(func() {
+	helpers.CollectSpecArtifacts()
+	helpers.RecordClusterTransitions(ctx.RancherAdminClient)
+
 	// Setting this to nil ensures we do not use the `cluster` variable value from another test running in parallel with this one.
 	cluster = nil
-	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+	clusterName = helpers.GenerateClusterName(helpers.ClusterNamePrefix)
 })
 
-var _ = ReportBeforeEach(func(report SpecReport) {
-	// Reset case ID
-	testCaseID = -1
+var _ = JustAfterEach(func() {
+	// Collect the support bundle before AfterEach deletes the cluster
+	helpers.CollectSupportBundleOnFailure(clusterName)
 })
 
+// Delete the cluster shared by the specs with RECYCLE_CLUSTER, if any, then the clusters leaked by all the processes
+var _ = SynchronizedAfterSuite(helpers.DeleteRecycledCluster, helpers.CommonSynchronizedAfterSuite)
+
 var _ = ReportAfterEach(func(report SpecReport) {
 	// Add result in Qase if asked
-	Qase(testCaseID, report)
+	helpers.ReportToQase(report)
+	// Upload the artifacts of the failed spec to ARTIFACTS_BUCKET if set
+	helpers.UploadArtifactsOnFailure(report)
+})
+
+var _ = ReportAfterSuite("Reports", func(report Report) {
+	// Write the JSON, JUnit and summary reports to the artifacts directory
+	helpers.GenerateSuiteReports(report)
+	// Submit the Qase results of all the parallel processes
+	helpers.SubmitQaseResults()
 })
 
 // updateClusterInUpdatingState runs checks to ensure cluster in an updating state can be updated
@@ -92,12 +103,12 @@ func updateClusterInUpdatingState(cluster *management.Cluster, client *rancher.C
 	Expect(err).To(BeNil())
 	Expect(*cluster.EKSConfig.LoggingTypes).Should(HaveExactElements(loggingTypes))
 
-	err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+	err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 	Expect(err).To(BeNil())
 
-	Eventually(func() bool {
+	helpers.EventuallyWithBackoff(func() bool {
 		GinkgoLogr.Info("Waiting for the updated changes to appear in EKSStatus.UpstreamSpec ...")
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).To(BeNil())
 
 		for _, loggingType := range loggingTypes {
@@ -115,9 +126,9 @@ func syncK8sVersionUpgradeCheck(cluster *management.Cluster, client *rancher.Cli
 		err = helper.UpgradeEKSClusterOnAWS(region, clusterName, upgradeToVersion)
 		Expect(err).To(BeNil())
 
-		Eventually(func() string {
+		helpers.EventuallyWithBackoff(func() string {
 			GinkgoLogr.Info("Waiting for k8s upgrade to appear in EKSStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			return *cluster.EKSStatus.UpstreamSpec.KubernetesVersion
 		}, tools.SetTimeout(5*time.Minute), 10*time.Second).Should(Equal(upgradeToVersion), "Failed while waiting for k8s upgrade to appear in EKSStatus.UpstreamSpec")
@@ -138,9 +149,9 @@ func syncK8sVersionUpgradeCheck(cluster *management.Cluster, client *rancher.Cli
 				Expect(err).To(BeNil())
 			}
 
-			Eventually(func() bool {
+			helpers.EventuallyWithBackoff(func() bool {
 				GinkgoLogr.Info("Waiting for the nodegroup upgrade to appear in EKSStatus.UpstreamSpec ...")
-				cluster, err = client.Management.Cluster.ByID(cluster.ID)
+				cluster, err = helpers.WatchedCluster(client, cluster.ID)
 				Expect(err).To(BeNil())
 				for _, ng := range *cluster.EKSStatus.UpstreamSpec.NodeGroups {
 					if ng.Version == nil || *ng.Version != upgradeToVersion {
@@ -168,8 +179,8 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 		err := helper.UpdateLoggingOnAWS(clusterName, region, loggingTypes, nil)
 		Expect(err).To(BeNil())
 
-		Eventually(func() bool {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() bool {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			updated := len(*cluster.EKSStatus.UpstreamSpec.LoggingTypes) == len(loggingTypes)
 			if !helpers.IsImport {
@@ -189,8 +200,8 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 	By("Disabling the LoggingTypes", func() {
 		err := helper.UpdateLoggingOnAWS(clusterName, region, nil, []string{"all"})
 		Expect(err).To(BeNil())
-		Eventually(func() bool {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() bool {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			updated := len(*cluster.EKSStatus.UpstreamSpec.LoggingTypes) == 0
 			if !helpers.IsImport {
@@ -204,8 +215,8 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 		cidrs := []string{"0.0.0.0/0", helpers.GetRancherIP() + "/32"}
 		err := helper.UpdateVPCAccess(clusterName, region, true, true, cidrs)
 		Expect(err).To(BeNil())
-		Eventually(func() bool {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() bool {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			privateUpdated := *cluster.EKSStatus.UpstreamSpec.PrivateAccess
 			publicUpdated := *cluster.EKSStatus.UpstreamSpec.PublicAccess
@@ -239,8 +250,8 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 		}
 		err := helper.ScaleNodeGroupOnAWS(*ng.NodegroupName, clusterName, region, nodeCount, nodeCount+2, nodeCount-1)
 		Expect(err).To(BeNil())
-		Eventually(func() bool {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyForOperation(helpers.OperationScale, func() bool {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			upstreamNodeGroups = *cluster.EKSStatus.UpstreamSpec.NodeGroups
 			updated := *upstreamNodeGroups[0].DesiredSize == nodeCount
@@ -249,7 +260,7 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 				updated = updated && *configNodeGroups[0].DesiredSize == nodeCount
 			}
 			return updated
-		}, "10m", "7s").Should(BeTrue(), "Timed out waiting for NodeGroup scale to show in Rancher")
+		}, "10m").Should(BeTrue(), "Timed out waiting for NodeGroup scale to show in Rancher")
 	})
 
 	var nodeName = namegen.AppendRandomString("ng")
@@ -262,8 +273,8 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 			err := helper.AddNodeGroupOnAWS(nodeName, clusterName, region)
 			Expect(err).To(BeNil())
 
-			Eventually(func() bool {
-				cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			helpers.EventuallyForOperation(helpers.OperationAddNodePool, func() bool {
+				cluster, err = helpers.WatchedCluster(client, cluster.ID)
 				Expect(err).To(BeNil())
 				if len(*cluster.EKSStatus.UpstreamSpec.NodeGroups) != ngCount+1 {
 					return false
@@ -274,7 +285,7 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 					}
 				}
 				return false
-			}, "10m", "7s").Should(BeTrue(), "Timed out waiting for new NodeGroup to appear in Rancher")
+			}, "10m").Should(BeTrue(), "Timed out waiting for new NodeGroup to appear in Rancher")
 		})
 	}
 
@@ -290,8 +301,8 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 		}
 		err := helper.ModifyEKSNodegroupOnAWS(region, clusterName, nodeName, "delete", "--wait")
 		Expect(err).To(BeNil())
-		Eventually(func() bool {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() bool {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			updated := len(*cluster.EKSStatus.UpstreamSpec.NodeGroups) != ngCount
 			if !helpers.IsImport {
@@ -326,8 +337,8 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 	By("adding tags to EKS cluster", func() {
 		err := helper.AddClusterTagsOnAWS(clusterName, region, tags)
 		Expect(err).To(BeNil())
-		Eventually(func() bool {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() bool {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			upstreamTags := *cluster.EKSStatus.UpstreamSpec.Tags
 			for key := range tags {
@@ -364,8 +375,8 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 		}
 		err := helper.RemoveClusterTagsOnAWS(clusterName, region, removeTags)
 		Expect(err).To(BeNil())
-		Eventually(func() bool {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() bool {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			upstreamTags := *cluster.EKSStatus.UpstreamSpec.Tags
 			for key := range tags {
@@ -389,8 +400,8 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 		upstreamNodeGroups := *cluster.EKSStatus.UpstreamSpec.NodeGroups
 		err := helper.UpdateNodeGroupLabelsOnAWS(clusterName, *upstreamNodeGroups[ngIndex].NodegroupName, region, addLabels, nil)
 		Expect(err).To(BeNil())
-		Eventually(func() bool {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() bool {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			upstreamNodeGroups = *cluster.EKSStatus.UpstreamSpec.NodeGroups
 			upstreamLabels := *upstreamNodeGroups[ngIndex].Labels
@@ -432,8 +443,8 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 		upstreamNodeGroups := *cluster.EKSStatus.UpstreamSpec.NodeGroups
 		err := helper.UpdateNodeGroupLabelsOnAWS(clusterName, *upstreamNodeGroups[ngIndex].NodegroupName, region, nil, removeLabels)
 		Expect(err).To(BeNil())
-		Eventually(func() bool {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() bool {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			upstreamNodeGroups = *cluster.EKSStatus.UpstreamSpec.NodeGroups
 			upstreamLabels := *upstreamNodeGroups[ngIndex].Labels
@@ -486,18 +497,18 @@ func syncRancherToAWSCheck(cluster *management.Cluster, client *rancher.Client,
 		}
 
 		// Verify the new edits reflect in AWS and existing details do NOT change
-		var out string
-		out, err = helper.GetFromEKS(region, clusterName, "cluster", "'.[]|.Version'")
-		Expect(err).To(BeNil())
-		Expect(out).To(Equal(upgradeToVersion))
-
-		out, err = helper.GetFromEKS(region, clusterName, "nodegroup", "'.|length'")
+		var eksCluster *cloudjson.EKSCluster
+		eksCluster, err = helper.GetEKSClusterOnAWS(region, clusterName)
 		Expect(err).To(BeNil())
-		Expect(strconv.Atoi(out)).To(Equal(currentNodeGroupNumber))
+		Expect(eksCluster.Version).To(Equal(upgradeToVersion))
 
-		out, err = helper.GetFromEKS(region, clusterName, "nodegroup", "'.[]|.DesiredCapacity'")
+		var nodegroups []cloudjson.EKSNodegroup
+		nodegroups, err = helper.ListEKSNodegroupsOnAWS(region, clusterName)
 		Expect(err).To(BeNil())
-		Expect(strconv.ParseInt(out, 10, 64)).To(Equal(initialNodeCount + 1))
+		Expect(nodegroups).To(HaveLen(currentNodeGroupNumber))
+		for _, ng := range nodegroups {
+			Expect(ng.DesiredCapacity).To(Equal(initialNodeCount + 1))
+		}
 	})
 
 	By("adding a NodeGroup", func() {
@@ -509,14 +520,15 @@ func syncRancherToAWSCheck(cluster *management.Cluster, client *rancher.Client,
 		Expect(*cluster.EKSConfig.LoggingTypes).ShouldNot(HaveExactElements(loggingTypes))
 
 		// Verify the new edits reflect in AWS console and existing details do NOT change
-		var out string
-		out, err = helper.GetFromEKS(region, clusterName, "cluster", "'.[]|.Version'")
+		var eksCluster *cloudjson.EKSCluster
+		eksCluster, err = helper.GetEKSClusterOnAWS(region, clusterName)
 		Expect(err).To(BeNil())
-		Expect(out).To(Equal(upgradeToVersion))
+		Expect(eksCluster.Version).To(Equal(upgradeToVersion))
 
-		out, err = helper.GetFromEKS(region, clusterName, "nodegroup", "'.|length'")
+		var nodegroups []cloudjson.EKSNodegroup
+		nodegroups, err = helper.ListEKSNodegroupsOnAWS(region, clusterName)
 		Expect(err).To(BeNil())
-		Expect(strconv.Atoi(out)).To(Equal(currentNodeGroupNumber + 1))
+		Expect(nodegroups).To(HaveLen(currentNodeGroupNumber + 1))
 	})
 
 	By("Adding the LoggingTypes", func() {
@@ -528,14 +540,15 @@ func syncRancherToAWSCheck(cluster *management.Cluster, client *rancher.Client,
 		Expect(len(*cluster.EKSConfig.NodeGroups)).To(Equal(currentNodeGroupNumber + 1))
 
 		// Verify the new edits reflect in AWS console and existing details do NOT change
-		var out string
-		out, err = helper.GetFromEKS(region, clusterName, "nodegroup", "'.|length'")
+		var nodegroups []cloudjson.EKSNodegroup
+		nodegroups, err = helper.ListEKSNodegroupsOnAWS(region, clusterName)
 		Expect(err).To(BeNil())
-		Expect(strconv.Atoi(out)).To(Equal(currentNodeGroupNumber + 1))
+		Expect(nodegroups).To(HaveLen(currentNodeGroupNumber + 1))
 
-		out, err = helper.GetFromEKS(region, clusterName, "cluster", "'.[]|.Logging|.[]|.[]|.Types'")
+		var eksCluster *cloudjson.EKSCluster
+		eksCluster, err = helper.GetEKSClusterOnAWS(region, clusterName)
 		Expect(err).To(BeNil())
-		Expect(out).ShouldNot(HaveExactElements(loggingTypes))
+		Expect(eksCluster.EnabledLoggingTypes()).ShouldNot(HaveExactElements(loggingTypes))
 	})
 
 }
@@ -548,8 +561,9 @@ func upgradeNodeKubernetesVersionGTCPCheck(cluster *management.Cluster, client *
 	Expect(err).To(BeNil())
 
 	// wait until the error is visible on the cluster
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+	helpers.AllowClusterErrors()
+	helpers.EventuallyWithBackoff(func() bool {
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).To(BeNil())
 		// checking for both the messages since different operator version shows different messages. To be removed once the message is updated.
 		// New message:  versions for cluster [1.29] and nodegroup [1.30] not compatible: all nodegroup kubernetes versions must be equal to or one minor version lower than the cluster kubernetes version
@@ -563,8 +577,9 @@ func invalidEndpointCheck(cluster *management.Cluster, client *rancher.Client) {
 	cidr := []string{namegen.AppendRandomString("invalid")}
 	cluster, _ = helper.UpdatePublicAccessSources(cluster, client, cidr, false)
 
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+	helpers.AllowClusterErrors()
+	helpers.EventuallyWithBackoff(func() bool {
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).To(BeNil())
 		return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "The following CIDRs are invalid in publicAccessCidrs")
 	}, "2m", "3s").Should(BeTrue())
@@ -577,6 +592,28 @@ func invalidAccessValuesCheck(cluster *management.Cluster, client *rancher.Clien
 	Expect(err).To(MatchError(ContainSubstring("public access, private access, or both must be enabled")))
 }
 
+// upgradeCPAndNgAtOnceCheck upgrades the control plane and the node groups in a single cluster update, and checks that the operator upgrades them in order
+func upgradeCPAndNgAtOnceCheck(cluster *management.Cluster, client *rancher.Client, upgradeToVersion string) {
+	By("upgrading the ControlPlane & NodeGroups in a single update", func() {
+		var err error
+		cluster, err = helper.UpdateCluster(cluster, client, func(cluster *management.Cluster) {
+			cluster.EKSConfig.KubernetesVersion = &upgradeToVersion
+			nodeGroups := *cluster.EKSConfig.NodeGroups
+			for i := range nodeGroups {
+				nodeGroups[i].Version = &upgradeToVersion
+			}
+		})
+		Expect(err).To(BeNil())
+		Expect(*cluster.EKSConfig.KubernetesVersion).To(Equal(upgradeToVersion))
+		for _, ng := range *cluster.EKSConfig.NodeGroups {
+			Expect(*ng.Version).To(Equal(upgradeToVersion))
+		}
+	})
+
+	cluster = helpers.WaitForOrderedUpgrade(client, cluster, upgradeToVersion)
+	helpers.ClusterIsReadyChecks(cluster, client, cluster.Name)
+}
+
 func upgradeCPAndAddNgCheck(cluster *management.Cluster, client *rancher.Client, upgradeToVersion string) {
 	var err error
 	originalLen := len(*cluster.EKSConfig.NodeGroups)
@@ -607,13 +644,13 @@ func upgradeCPAndAddNgCheck(cluster *management.Cluster, client *rancher.Client,
 		Expect(ng.NodegroupName).To(Equal(newNodeGroupName))
 	}
 
-	err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+	err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 	Expect(err).To(BeNil())
 
 	// wait until the update is visible on the cluster
-	Eventually(func() bool {
+	helpers.EventuallyWithBackoff(func() bool {
 		GinkgoLogr.Info("Waiting for the version of new nodegroup to appear in EKSStatus.UpstreamSpec ...")
-		cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+		cluster, err = helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 		Expect(err).To(BeNil())
 		for _, ng := range *cluster.EKSStatus.UpstreamSpec.NodeGroups {
 			if ng.Version == nil || *ng.Version != upgradeToVersion {
@@ -710,8 +747,8 @@ func updateCloudCredentialsCheck(cluster *management.Cluster, client *rancher.Cl
 	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
 	Expect(err).To(BeNil())
 	Expect(cluster.EKSConfig.AmazonCredentialSecret).To(Equal(newCCID))
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+	helpers.EventuallyWithBackoff(func() bool {
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).NotTo(HaveOccurred())
 		return cluster.EKSStatus.UpstreamSpec.AmazonCredentialSecret == newCCID
 	}, "5m", "5s").Should(BeTrue(), "Failed while upstream cloud credentials update")
@@ -736,3 +773,29 @@ func deleteAllNodeGroupsCheck(cluster *management.Cluster, client *rancher.Clien
 		cluster.EKSConfig.NodeGroups = nil
 	}
 }
+
+// scaleToZeroCheck hibernates the cluster by scaling all the nodegroups to zero, checks that Rancher handles the cluster left without node,
+// then scales them back and checks that Rancher resumes the management of the cluster
+func scaleToZeroCheck(cluster *management.Cluster, client *rancher.Client) {
+	initialNodeCount := *(*cluster.EKSConfig.NodeGroups)[0].DesiredSize
+
+	hibernated := time.Now()
+	By("scaling the nodegroups to zero", func() {
+		start := hibernated
+		var err error
+		// the cluster can not become active without node, so the update is not waited for
+		cluster, err = helper.ScaleNodeGroup(cluster, client, 0, false, true)
+		Expect(err).To(BeNil())
+		cluster = helpers.CheckZeroNodeCluster(client, cluster, start)
+	})
+
+	By("scaling the nodegroups back up", func() {
+		start := time.Now()
+		var err error
+		cluster, err = helper.ScaleNodeGroup(cluster, client, initialNodeCount, false, true)
+		Expect(err).To(BeNil())
+		cluster = helpers.CheckClusterResumed(client, cluster, hibernated)
+		helpers.WaitUntilNodeCount(client, cluster, int(initialNodeCount)*len(*cluster.EKSConfig.NodeGroups), start)
+	})
+	helpers.ClusterIsReadyChecks(cluster, client, cluster.Name)
+}
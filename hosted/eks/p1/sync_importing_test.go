@@ -9,6 +9,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("SyncImport", func() {
@@ -49,21 +50,18 @@ var _ = Describe("SyncImport", func() {
 			Expect(err).To(BeNil())
 		})
 
-		It("Upgrade k8s version of cluster from EKS and verify it is synced back to Rancher", func() {
-			testCaseID = 114
+		It("Upgrade k8s version of cluster from EKS and verify it is synced back to Rancher", helpers.QaseLabel(qasecases.EKSP1UpgradeK8sVersionOfClusterFromEKSAnd114), func() {
 
 			By("upgrading the ControlPlane & NodeGroup", func() {
 				syncK8sVersionUpgradeCheck(cluster, ctx.RancherAdminClient, true, k8sVersion, upgradeToVersion)
 			})
 		})
 
-		It("Sync from AWS console to Rancher", func() {
-			testCaseID = 111
+		It("Sync from AWS console to Rancher", helpers.QaseLabel(qasecases.EKSP1SyncFromAWSConsoleRancher111), func() {
 			syncAWSToRancherCheck(cluster, ctx.RancherAdminClient, k8sVersion, upgradeToVersion)
 		})
 
-		It("Sync from Rancher to AWS console after a sync from AWS console to Rancher", func() {
-			testCaseID = 112
+		It("Sync from Rancher to AWS console after a sync from AWS console to Rancher", helpers.QaseLabel(qasecases.EKSP1SyncFromRancherAWSConsoleAfterSyncFrom112), func() {
 			syncRancherToAWSCheck(cluster, ctx.RancherAdminClient, k8sVersion, upgradeToVersion)
 		})
 	})
@@ -9,6 +9,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("SyncProvisioning", func() {
@@ -46,21 +47,18 @@ var _ = Describe("SyncProvisioning", func() {
 			Expect(err).To(BeNil())
 		})
 
-		It("Upgrade k8s version of cluster from EKS and verify it is synced back to Rancher", func() {
-			testCaseID = 159
+		It("Upgrade k8s version of cluster from EKS and verify it is synced back to Rancher", helpers.QaseLabel(qasecases.EKSP1UpgradeK8sVersionOfClusterFromEKSAnd159), func() {
 
 			By("upgrading the ControlPlane & NodeGroup", func() {
 				syncK8sVersionUpgradeCheck(cluster, ctx.RancherAdminClient, true, k8sVersion, upgradeToVersion)
 			})
 		})
 
-		It("Sync from AWS console to Rancher", func() {
-			testCaseID = 156
+		It("Sync from AWS console to Rancher", helpers.QaseLabel(qasecases.EKSP1SyncFromAWSConsoleRancher156), func() {
 			syncAWSToRancherCheck(cluster, ctx.RancherAdminClient, k8sVersion, upgradeToVersion)
 		})
 
-		It("Sync from Rancher to AWS console after a sync from AWS console to Rancher", func() {
-			testCaseID = 157
+		It("Sync from Rancher to AWS console after a sync from AWS console to Rancher", helpers.QaseLabel(qasecases.EKSP1SyncFromRancherAWSConsoleAfterSyncFrom157), func() {
 			syncRancherToAWSCheck(cluster, ctx.RancherAdminClient, k8sVersion, upgradeToVersion)
 		})
 	})
@@ -0,0 +1,58 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package p2_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("P2Scale", Label("scale"), func() {
+	When("a cluster is created", func() {
+		BeforeEach(func() {
+			k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, false)
+			Expect(err).To(BeNil())
+			GinkgoLogr.Info(fmt.Sprintf("While provisioning, using K8s version %s for cluster %s", k8sVersion, clusterName))
+			cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
+			Expect(err).To(BeNil())
+			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+		})
+		AfterEach(func() {
+			if ctx.ClusterCleanup {
+				if cluster != nil && cluster.ID != "" {
+					GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
+					err := helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)
+					Expect(err).To(BeNil())
+				}
+			} else {
+				fmt.Println("Skipping downstream cluster deletion: ", clusterName)
+			}
+		})
+
+		It(fmt.Sprintf("should scale the cluster to %d nodegroups and %d nodes and back", helpers.ScaleNodePools, helpers.ScaleNodePools*int(helpers.ScaleNodesPerPool)), func() {
+			scaleChecks(cluster, ctx.RancherAdminClient, clusterName)
+		})
+
+		It(fmt.Sprintf("should scale the cluster to %d nodegroups and %d nodes in a single update", helpers.ScaleNodePools, helpers.ScaleNodePools*int(helpers.ScaleNodesPerPool)), Label("batch"), func() {
+			batchChecks(cluster, ctx.RancherAdminClient, clusterName)
+		})
+	})
+})
@@ -0,0 +1,166 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package p2_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"k8s.io/utils/pointer"
+)
+
+var (
+	ctx         helpers.RancherContext
+	cluster     *management.Cluster
+	clusterName string
+	region      = helpers.GetEKSRegion()
+)
+
+func TestP2(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "P2 Suite", helpers.SuiteConfig(t))
+}
+
+// The Rancher setup and the cloud credential are shared by the parallel processes; every process provisions its own clusters
+var _ = SynchronizedBeforeSuite(helpers.ParallelSynchronizedBeforeSuite, func(setup []byte) {
+	ctx = helpers.ParallelBeforeSuite(setup)
+})
+
+var _ = BeforeEach(func() {
+	helpers.CollectSpecArtifacts()
+	helpers.RecordClusterTransitions(ctx.RancherAdminClient)
+
+	// Setting this to nil ensures we do not use the `cluster` variable value from another test running in parallel with this one.
+	cluster = nil
+	clusterName = helpers.GenerateClusterName(helpers.ClusterNamePrefix)
+})
+
+var _ = JustAfterEach(func() {
+	// Collect the support bundle before AfterEach deletes the cluster
+	helpers.CollectSupportBundleOnFailure(clusterName)
+})
+
+// Delete the clusters leaked by all the processes once they are done
+var _ = SynchronizedAfterSuite(func() {}, helpers.CommonSynchronizedAfterSuite)
+
+var _ = ReportAfterEach(func(report SpecReport) {
+	// Add result in Qase if asked
+	helpers.ReportToQase(report)
+	// Upload the artifacts of the failed spec to ARTIFACTS_BUCKET if set
+	helpers.UploadArtifactsOnFailure(report)
+})
+
+var _ = ReportAfterSuite("Reports", func(report Report) {
+	// Write the JSON, JUnit and summary reports to the artifacts directory
+	helpers.GenerateSuiteReports(report)
+	// Submit the Qase results of all the parallel processes
+	helpers.SubmitQaseResults()
+})
+
+// scaleChecks grows the cluster to helpers.ScaleNodePools nodegroups of helpers.ScaleNodesPerPool nodes and shrinks it back to a single nodegroup;
+// the reconcile latency of every step is recorded in the operation metrics
+func scaleChecks(cluster *management.Cluster, client *rancher.Client, clusterName string) {
+	helpers.ClusterIsReadyChecks(cluster, client, clusterName)
+	var err error
+
+	By(fmt.Sprintf("adding NodeGroups up to %d NodeGroups", helpers.ScaleNodePools), func() {
+		start := time.Now()
+		cluster, err = helper.AddNodeGroup(cluster, helpers.ScaleNodePools-len(*cluster.EKSConfig.NodeGroups), client, true, true)
+		Expect(err).To(BeNil())
+		helpers.WaitUntilNodeCount(client, cluster, clusterNodeCount(cluster), start)
+	})
+
+	By(fmt.Sprintf("scaling up the NodeGroups to %d nodes each", helpers.ScaleNodesPerPool), func() {
+		start := time.Now()
+		cluster, err = helper.ScaleNodeGroup(cluster, client, helpers.ScaleNodesPerPool, true, true)
+		Expect(err).To(BeNil())
+		helpers.WaitUntilNodeCount(client, cluster, helpers.ScaleNodePools*int(helpers.ScaleNodesPerPool), start)
+	})
+
+	By("scaling down the NodeGroups to 1 node each", func() {
+		start := time.Now()
+		cluster, err = helper.ScaleNodeGroup(cluster, client, 1, true, true)
+		Expect(err).To(BeNil())
+		helpers.WaitUntilNodeCount(client, cluster, helpers.ScaleNodePools, start)
+	})
+
+	By("deleting all the NodeGroups but one", func() {
+		start := time.Now()
+		cluster = deleteNodeGroupsButOne(cluster, client)
+		helpers.WaitUntilNodeCount(client, cluster, clusterNodeCount(cluster), start)
+	})
+
+	helpers.ClusterIsReadyChecks(cluster, client, clusterName)
+}
+
+// batchChecks grows the cluster to helpers.ScaleNodePools nodegroups of helpers.ScaleNodesPerPool nodes and changes the labels of the nodegroups
+// in a single update; the apply time of the batch and the reconcile latency of the nodes are recorded in the operation metrics
+func batchChecks(cluster *management.Cluster, client *rancher.Client, clusterName string) {
+	helpers.ClusterIsReadyChecks(cluster, client, clusterName)
+
+	By(fmt.Sprintf("scaling, labelling and adding NodeGroups up to %d NodeGroups in a single update", helpers.ScaleNodePools), func() {
+		start := time.Now()
+		batch := helper.NodeGroupBatch{
+			NodeCount: pointer.Int64(helpers.ScaleNodesPerPool),
+			Labels:    map[string]string{"batch": "true"},
+			Add:       helpers.ScaleNodePools - len(*cluster.EKSConfig.NodeGroups),
+		}
+		var err error
+		// the apply time of the batch is logged and recorded by ApplyNodeGroupBatch
+		cluster, _, err = helper.ApplyNodeGroupBatch(cluster, client, batch)
+		Expect(err).To(BeNil())
+		helpers.WaitUntilNodeCount(client, cluster, helpers.ScaleNodePools*int(helpers.ScaleNodesPerPool), start)
+	})
+
+	helpers.ClusterIsReadyChecks(cluster, client, clusterName)
+}
+
+// clusterNodeCount returns the number of nodes of the cluster according to the desired size of its nodegroups
+func clusterNodeCount(cluster *management.Cluster) (count int) {
+	for _, ng := range *cluster.EKSConfig.NodeGroups {
+		count += int(*ng.DesiredSize)
+	}
+	return count
+}
+
+// deleteNodeGroupsButOne deletes all the nodegroups of the cluster but the first one in a single update
+func deleteNodeGroupsButOne(cluster *management.Cluster, client *rancher.Client) *management.Cluster {
+	defer helpers.TimeOperation(helpers.OperationDeleteNodePool, cluster, true)()
+
+	cluster, err := helper.UpdateCluster(cluster, client, func(upgradedCluster *management.Cluster) {
+		nodeGroups := (*upgradedCluster.EKSConfig.NodeGroups)[:1]
+		upgradedCluster.EKSConfig.NodeGroups = &nodeGroups
+	})
+	Expect(err).To(BeNil())
+	Expect(*cluster.EKSConfig.NodeGroups).To(HaveLen(1))
+
+	err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
+	Expect(err).To(BeNil())
+
+	GinkgoLogr.Info("Waiting for the nodegroups to be deleted in EKSStatus.UpstreamSpec ...")
+	cluster, err = helpers.WaitForUpstreamField(client, cluster.ID, func(cluster *management.Cluster) any {
+		return len(*cluster.EKSStatus.UpstreamSpec.NodeGroups)
+	}, 1, helpers.Timeout)
+	Expect(err).To(BeNil())
+	return cluster
+}
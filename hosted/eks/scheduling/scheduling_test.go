@@ -0,0 +1,57 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling_test
+
+import (
+	"fmt"
+	"maps"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("NodeScheduling", func() {
+	BeforeEach(func() {
+		k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, false)
+		Expect(err).To(BeNil())
+		GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s", k8sVersion, clusterName))
+
+		cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
+		Expect(err).To(BeNil())
+		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+	})
+
+	// the EKS config of Rancher has no nodegroup taints, only the labels can be checked
+	It("should schedule pods according to the nodegroup labels set through Rancher", func() {
+		configNodeGroups := *cluster.EKSConfig.NodeGroups
+		// the labels and tags of the nodegroups are replaced, they must contain the original ones
+		labels := make(map[string]string)
+		maps.Copy(labels, *configNodeGroups[0].Labels)
+		maps.Copy(labels, helpers.SchedulingLabels())
+
+		var err error
+		By("setting the nodegroup labels", func() {
+			cluster, err = helper.UpdateNodegroupMetadata(cluster, ctx.RancherAdminClient, *configNodeGroups[0].Tags, labels, true)
+			Expect(err).To(BeNil())
+		})
+
+		helpers.CheckNodeScheduling(ctx.RancherAdminClient, cluster, helpers.SchedulingLabels(), nil)
+		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+	})
+})
@@ -0,0 +1,71 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent_customization_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("ClusterAgentCustomization", func() {
+	var upgradeToVersion string
+
+	BeforeEach(func() {
+		k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "", !helpers.SkipUpgradeTests)
+		Expect(err).To(BeNil())
+		if !helpers.SkipUpgradeTests {
+			upgradeToVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "", false)
+			Expect(err).To(BeNil())
+		}
+		GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s", k8sVersion, clusterName))
+
+		cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, nil)
+		Expect(err).To(BeNil())
+		// the customization is set while the cluster is provisioning, the cluster agent is then deployed with it
+		cluster = helpers.SetClusterAgentCustomization(ctx.RancherAdminClient, cluster, helpers.ClusterAgentCustomization("100m"))
+		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+	})
+
+	It("should deploy the cluster agent with its customization and keep it after upgrades", func() {
+		customization := helpers.ClusterAgentCustomization("100m")
+		helpers.CheckClusterAgentCustomization(ctx.RancherAdminClient, cluster, customization)
+
+		var err error
+		if helpers.SkipUpgradeTests {
+			GinkgoLogr.Info(helpers.SkipUpgradeTestsLog)
+		} else {
+			By(fmt.Sprintf("upgrading the cluster to %s", upgradeToVersion), func() {
+				cluster, err = helper.UpgradeKubernetesVersion(cluster, upgradeToVersion, ctx.RancherAdminClient, true, true, true)
+				Expect(err).To(BeNil())
+			})
+			helpers.CheckClusterAgentCustomization(ctx.RancherAdminClient, cluster, customization)
+		}
+
+		// the cluster agent is redeployed when its customization changes
+		customization = helpers.ClusterAgentCustomization("150m")
+		cluster = helpers.SetClusterAgentCustomization(ctx.RancherAdminClient, cluster, customization)
+		helpers.CheckClusterAgentCustomization(ctx.RancherAdminClient, cluster, customization)
+
+		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+	})
+})
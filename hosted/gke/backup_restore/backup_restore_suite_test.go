@@ -16,18 +16,14 @@ package backup_restore_test
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
-	. "github.com/rancher-sandbox/qase-ginkgo"
 
 	"github.com/rancher/shepherd/clients/rancher"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
@@ -40,34 +36,49 @@ const (
 )
 
 var (
-	testCaseID              int64
 	clusterName, backupFile string
 	ctx                     helpers.RancherContext
 	cluster                 *management.Cluster
 	project                 = helpers.GetGKEProjectID()
 	zone                    = helpers.GetGKEZone()
-	k3sVersion              = os.Getenv("INSTALL_K3S_VERSION")
+	k3sVersion              = helpers.CurrentRunConfig().K3sVersion
 )
 
 func TestBackupRestore(t *testing.T) {
 	RegisterFailHandler(Fail)
+	helpers.ValidateRunConfig(helpers.SuiteBackupRestore)
 	helpers.CommonSynchronizedBeforeSuite()
 	ctx = helpers.CommonBeforeSuite()
-	RunSpecs(t, "BackupRestore Suite")
+	RunSpecs(t, "BackupRestore Suite", helpers.SuiteConfig(t))
 }
 
-var _ = ReportBeforeEach(func(report SpecReport) {
-	// Reset case ID
-	testCaseID = -1
+var _ = JustAfterEach(func() {
+	// Collect the support bundle before AfterEach deletes the cluster
+	helpers.CollectSupportBundleOnFailure(clusterName)
 })
 
+// Delete the clusters leaked by all the processes once they are done
+var _ = SynchronizedAfterSuite(func() {}, helpers.CommonSynchronizedAfterSuite)
+
 var _ = ReportAfterEach(func(report SpecReport) {
 	// Add result in Qase if asked
-	Qase(testCaseID, report)
+	helpers.ReportToQase(report)
+	// Upload the artifacts of the failed spec to ARTIFACTS_BUCKET if set
+	helpers.UploadArtifactsOnFailure(report)
+})
+
+var _ = ReportAfterSuite("Reports", func(report Report) {
+	// Write the JSON, JUnit and summary reports to the artifacts directory
+	helpers.GenerateSuiteReports(report)
+	// Submit the Qase results of all the parallel processes
+	helpers.SubmitQaseResults()
 })
 
 var _ = BeforeEach(func() {
-	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+	helpers.CollectSpecArtifacts()
+	helpers.RecordClusterTransitions(ctx.RancherAdminClient)
+
+	clusterName = helpers.GenerateClusterName(helpers.ClusterNamePrefix)
 	k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "", false)
 	Expect(err).NotTo(HaveOccurred())
 	GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
@@ -123,34 +134,25 @@ func BackupRestoreChecks(k *kubectl.Kubectl) {
 		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
 	})
 
-	By("Performing a backup", func() {
-		backupFile = helpers.ExecuteBackup(k, backupResourceName)
-	})
-
-	By("Perform restore pre-requisites: Uninstalling k3s", func() {
-		out, err := exec.Command("k3s-uninstall.sh").CombinedOutput()
-		Expect(err).To(Not(HaveOccurred()), out)
-	})
-
-	By("Perform restore pre-requisites: Getting k3s ready", func() {
-		helpers.InstallK3S(k, k3sVersion, "none", "none")
+	var snapshot *helpers.ClusterSnapshot
+	By("Recording the hosted cluster state", func() {
+		var err error
+		snapshot, err = helpers.SnapshotHostedCluster(ctx.RancherAdminClient, cluster.ID)
+		Expect(err).To(BeNil())
 	})
 
-	By("Performing a restore", func() {
-		helpers.ExecuteRestore(k, restoreResourceName, backupFile)
+	By("Performing a backup", func() {
+		backupFile = helpers.ExecuteBackup(k, backupResourceName)
 	})
 
-	By("Performing post migration installations: Installing CertManager", func() {
-		helpers.InstallCertManager(k, "none", "none")
+	By("Perform restore pre-requisites: Wiping Rancher", func() {
+		helpers.WipeRancher(k, k3sVersion)
 	})
 
-	By("Performing post migration installations: Installing Rancher Manager", func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(helpers.RancherFullVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "none", "none")
-	})
+	helpers.RestoreRancher(k, restoreResourceName, backupFile)
 
-	By("Performing post migration installations: Checking Rancher Deployments", func() {
-		helpers.CheckRancherDeployments(k)
+	By("Checking hosted cluster has been recovered", func() {
+		cluster = helpers.CheckHostedClusterRecovered(ctx.RancherAdminClient, snapshot)
 	})
 
 	By("Checking hosted cluster can be modified", func() {
@@ -0,0 +1,91 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("DeletionProtection", func() {
+	var k8sVersion string
+	existsOnCloud := func() (bool, error) {
+		return helper.ClusterExistsOnGCloud(clusterName, project, zone)
+	}
+
+	BeforeEach(func() {
+		var err error
+		k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "", false)
+		Expect(err).To(BeNil())
+		GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s", k8sVersion, clusterName))
+	})
+
+	Context("Imported cluster", func() {
+		BeforeEach(func() {
+			err := helper.CreateGKEClusterOnGCloud(zone, clusterName, project, k8sVersion)
+			Expect(err).To(BeNil())
+			cluster, err = helper.ImportGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, zone, project)
+			Expect(err).To(BeNil())
+			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			if ctx.ClusterCleanup {
+				if cluster != nil && cluster.ID != "" {
+					GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
+					err := helper.DeleteGKEHostCluster(cluster, ctx.RancherAdminClient)
+					Expect(err).To(BeNil())
+					// marking as nil so that the suite AfterEach does not delete it again
+					cluster = nil
+				}
+				err := helper.DeleteGKEClusterOnGCloud(zone, project, clusterName)
+				Expect(err).To(BeNil())
+			} else {
+				fmt.Println("Skipping downstream cluster deletion: ", clusterName)
+			}
+		})
+
+		It("should keep the cloud cluster when it is deleted from Rancher", func() {
+			helpers.CheckClusterDeletion(ctx.RancherAdminClient, cluster, helper.DeleteGKEHostCluster, existsOnCloud)
+			cluster = nil
+		})
+
+		It("should keep the cloud cluster when it is deleted through the provisioning API", func() {
+			helpers.CheckClusterDeletion(ctx.RancherAdminClient, cluster, helpers.DeleteThroughProvisioningAPI, existsOnCloud)
+			cluster = nil
+		})
+	})
+
+	Context("Provisioned cluster", func() {
+		BeforeEach(func() {
+			var err error
+			cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, nil)
+			Expect(err).To(BeNil())
+			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+		})
+
+		It("should destroy the cloud cluster when it is deleted from Rancher", func() {
+			helpers.CheckClusterDeletion(ctx.RancherAdminClient, cluster, helper.DeleteGKEHostCluster, existsOnCloud)
+			cluster = nil
+		})
+	})
+})
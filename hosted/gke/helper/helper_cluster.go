@@ -10,15 +10,15 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
 
-	"github.com/rancher/shepherd/extensions/clusters"
 	"github.com/rancher/shepherd/extensions/clusters/gke"
 	k8slabels "k8s.io/apimachinery/pkg/labels"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 
 	"github.com/Masterminds/semver/v3"
-	"github.com/epinio/epinio/acceptance/helpers/proc"
 	"github.com/pkg/errors"
+	"github.com/rancher/hosted-providers-e2e/pkg/cloudjson"
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
 	"github.com/rancher/shepherd/clients/rancher"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
 	"github.com/rancher/shepherd/extensions/clusters/kubernetesversions"
@@ -42,7 +42,61 @@ func CreateGKEHostedCluster(client *rancher.Client, displayName, cloudCredential
 		updateFunc(&gkeClusterConfig)
 	}
 
-	return gke.CreateGKEHostedCluster(client, displayName, cloudCredentialID, gkeClusterConfig, false, false, false, false, nil)
+	if helpers.IsDryRun() || helpers.IsTerraformBackend() {
+		spec, err := gkeClusterSpec(displayName, cloudCredentialID, gkeClusterConfig)
+		if err != nil {
+			return nil, err
+		}
+		if helpers.IsDryRun() {
+			versions, err := listGKEAllVersions(client, project, cloudCredentialID, zone, region)
+			if err != nil {
+				return nil, err
+			}
+			return helpers.DryRunCluster(client, spec, helpers.DryRunChecks{Versions: versions, InstanceTypeAvailable: gkeMachineTypeAvailable(project, zone, region)})
+		}
+		return helpers.CreateClusterWithTerraform(client, spec)
+	}
+	location := zone
+	if location == "" {
+		location = region
+	}
+	cluster, err := gke.CreateGKEHostedCluster(client, displayName, cloudCredentialID, gkeClusterConfig, false, false, false, false, nil)
+	return helpers.TrackRancherCluster(helpers.OperationProvision, cluster, err, location, gkeClusterConfig.Labels)
+}
+
+// gkeClusterSpec returns the spec of the GKE cluster, created with the rancher2 Terraform provider (see helpers.CreateClusterWithTerraform)
+// or validated with DRY_RUN (see helpers.DryRunCluster); the nodepools get the version of the cluster, like with gke.CreateGKEHostedCluster
+func gkeClusterSpec(displayName, cloudCredentialID string, gkeClusterConfig gke.ClusterConfig) (*helpers.ClusterSpec, error) {
+	gkeConfig := &management.GKEClusterConfigSpec{}
+	if err := helpers.ConvertHostedConfig(gkeClusterConfig, gkeConfig); err != nil {
+		return nil, err
+	}
+	gkeConfig.ClusterName = displayName
+	gkeConfig.GoogleCredentialSecret = cloudCredentialID
+	if gkeConfig.NodePools != nil {
+		for i := range *gkeConfig.NodePools {
+			(*gkeConfig.NodePools)[i].Version = gkeConfig.KubernetesVersion
+		}
+	}
+	return &helpers.ClusterSpec{Name: displayName, GKEConfig: gkeConfig}, nil
+}
+
+// gkeMachineTypeAvailable returns the check of the machine types available in the zone, or in a zone of the region of a regional cluster, see helpers.DryRunChecks
+func gkeMachineTypeAvailable(project, zone, region string) func(machineType string) error {
+	location, filter := zone, "zone="+zone
+	if zone == "" {
+		location, filter = region, "zone~^"+region+"-"
+	}
+	return func(machineType string) error {
+		out, err := extcli.Gcloud.Output("compute", "machine-types", "list", "--project", project, "--filter", fmt.Sprintf("name=%s AND %s", machineType, filter), "--format", "value(zone)")
+		if err != nil {
+			return fmt.Errorf("failed to check machine type %s in %s: %w", machineType, location, err)
+		}
+		if out == "" {
+			return fmt.Errorf("machine type %s is not available in %s", machineType, location)
+		}
+		return nil
+	}
 }
 
 // ImportGKEHostedCluster imports the GKE cluster
@@ -63,17 +117,23 @@ func ImportGKEHostedCluster(client *rancher.Client, displayName, cloudCredential
 	if err != nil {
 		return nil, err
 	}
-	return clusterResp, err
+	return helpers.TrackRancherCluster(helpers.OperationImport, clusterResp, err, zone, nil)
 }
 
 // DeleteGKEHostCluster deletes the GKE cluster
 func DeleteGKEHostCluster(cluster *management.Cluster, client *rancher.Client) error {
-	return client.Management.Cluster.Delete(cluster)
+	if err := helpers.DeleteRancherCluster(client, cluster); err != nil {
+		return err
+	}
+	helpers.MarkResourceDeleted(helpers.ResourceRancherCluster, cluster.Name)
+	return nil
 }
 
 // UpgradeKubernetesVersion upgrades the k8s version to the value defined by upgradeToVersion; if upgradeNodePool is true, it also upgrades nodepool k8s version;
 // if wait is set to true, it waits until the update is complete; if checkClusterConfig is true, it validates the update
 func UpgradeKubernetesVersion(cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, upgradeNodePool, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	defer helpers.TimeUpgrade(helpers.OperationUpgrade, cluster, upgradeToVersion, wait)()
+
 	currentVersion := *cluster.GKEConfig.KubernetesVersion
 	upgradedCluster := new(management.Cluster)
 	upgradedCluster.Name = cluster.Name
@@ -94,7 +154,7 @@ func UpgradeKubernetesVersion(cluster *management.Cluster, upgradeToVersion stri
 
 	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Kubernetes version for cluster %s will be upgraded to %s", cluster.Name, upgradeToVersion))
 
-	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err := helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	if err != nil {
 		return nil, err
 	}
@@ -110,17 +170,17 @@ func UpgradeKubernetesVersion(cluster *management.Cluster, upgradeToVersion stri
 		}
 	}
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+		err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 		Expect(err).To(BeNil())
 	}
 	if checkClusterConfig {
 		if upgradeCP {
-			Eventually(func() string {
+			helpers.EventuallyForOperation(helpers.OperationUpgrade, func() string {
 				ginkgo.GinkgoLogr.Info("Waiting for k8s upgrade to appear in GKEStatus.UpstreamSpec ...")
-				cluster, err = client.Management.Cluster.ByID(cluster.ID)
+				cluster, err = helpers.WatchedCluster(client, cluster.ID)
 				Expect(err).NotTo(HaveOccurred())
 				return *cluster.GKEStatus.UpstreamSpec.KubernetesVersion
-			}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(Equal(upgradeToVersion))
+			}, tools.SetTimeout(12*time.Minute)).Should(Equal(upgradeToVersion))
 
 			if !upgradeNodePool {
 				for _, np := range *cluster.GKEConfig.NodePools {
@@ -130,9 +190,9 @@ func UpgradeKubernetesVersion(cluster *management.Cluster, upgradeToVersion stri
 		}
 
 		if upgradeNodePool {
-			Eventually(func() bool {
+			helpers.EventuallyForOperation(helpers.OperationNodeUpgrade, func() bool {
 				ginkgo.GinkgoLogr.Info("Waiting for the nodepool upgrade to appear in GKEStatus.UpstreamSpec ...")
-				cluster, err = client.Management.Cluster.ByID(cluster.ID)
+				cluster, err = helpers.WatchedCluster(client, cluster.ID)
 				Expect(err).To(BeNil())
 				for _, np := range *cluster.GKEStatus.UpstreamSpec.NodePools {
 					if *np.Version != upgradeToVersion {
@@ -140,7 +200,7 @@ func UpgradeKubernetesVersion(cluster *management.Cluster, upgradeToVersion stri
 					}
 				}
 				return true
-			}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeTrue())
+			}, tools.SetTimeout(12*time.Minute)).Should(BeTrue())
 		}
 
 		Expect(*cluster.GKEStatus.UpstreamSpec.KubernetesVersion).To(Equal(upgradeToVersion))
@@ -152,13 +212,15 @@ func UpgradeKubernetesVersion(cluster *management.Cluster, upgradeToVersion stri
 // if wait is set to true, it will wait until the cluster finishes upgrading;
 // if checkClusterConfig is set to true, it will validate that nodepool has been upgraded successfully
 func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	defer helpers.TimeUpgrade(helpers.OperationNodeUpgrade, cluster, upgradeToVersion, wait)()
+
 	upgradedCluster := cluster
 	configNodePools := *upgradedCluster.GKEConfig.NodePools
 	for i := range configNodePools {
 		configNodePools[i].Version = &upgradeToVersion
 	}
 	var err error
-	cluster, err = client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err = helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	Expect(err).To(BeNil())
 
 	if checkClusterConfig {
@@ -169,15 +231,15 @@ func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion
 	}
 
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+		err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 		Expect(err).To(BeNil())
 	}
 
 	if checkClusterConfig {
 		// Check if the desired config has been applied in Rancher
-		Eventually(func() bool {
+		helpers.EventuallyForOperation(helpers.OperationNodeUpgrade, func() bool {
 			ginkgo.GinkgoLogr.Info("Waiting for the nodepool upgrade to appear in GKEStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			for _, np := range *cluster.GKEStatus.UpstreamSpec.NodePools {
 				if *np.Version != upgradeToVersion {
@@ -185,15 +247,29 @@ func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion
 				}
 			}
 			return true
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeTrue())
+		}, tools.SetTimeout(12*time.Minute)).Should(BeTrue())
 	}
 	return cluster, nil
 }
 
+// UpgradeSteps returns the steps used by helpers.UpgradeAlongPath to upgrade the control plane and then the nodepools of a GKE cluster
+func UpgradeSteps() helpers.UpgradeSteps {
+	return helpers.UpgradeSteps{
+		ControlPlane: func(cluster *management.Cluster, client *rancher.Client, version string) (*management.Cluster, error) {
+			return UpgradeKubernetesVersion(cluster, version, client, false, true, true)
+		},
+		NodeGroups: func(cluster *management.Cluster, client *rancher.Client, version string) (*management.Cluster, error) {
+			return UpgradeNodeKubernetesVersion(cluster, version, client, true, true)
+		},
+	}
+}
+
 // AddNodePool adds a nodepool to the list; it uses the nodepool template defined in CATTLE_TEST_CONFIG file
 // if wait is set to true, it waits until the update is complete; if checkClusterConfig is true, it validates the update
 // TODO(pvala): Enhance this method to accept a nodepool with different configuration
 func AddNodePool(cluster *management.Cluster, client *rancher.Client, increaseBy int, imageType string, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	defer helpers.TimeOperation(helpers.OperationAddNodePool, cluster, wait)()
+
 	currentNodePoolNumber := len(*cluster.GKEConfig.NodePools)
 	upgradedCluster := new(management.Cluster)
 	upgradedCluster.Name = cluster.Name
@@ -208,6 +284,7 @@ func AddNodePool(cluster *management.Cluster, client *rancher.Client, increaseBy
 		npTemplate.Config.ImageType = imageType
 	}
 	updateNodePoolsList := *cluster.GKEConfig.NodePools
+	added := make([]string, 0, increaseBy)
 	for i := 1; i <= increaseBy; i++ {
 		newNodepool := management.GKENodePoolConfig{
 			InitialNodeCount:  npTemplate.InitialNodeCount,
@@ -219,40 +296,100 @@ func AddNodePool(cluster *management.Cluster, client *rancher.Client, increaseBy
 			Name:              pointer.String(namegen.AppendRandomString("np")),
 		}
 		updateNodePoolsList = append(updateNodePoolsList, newNodepool)
+		added = append(added, *newNodepool.Name)
 	}
 	upgradedCluster.GKEConfig.NodePools = &updateNodePoolsList
 
-	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err := helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	if err != nil {
 		return nil, err
 	}
 
 	if checkClusterConfig {
-		// Check if the desired config is set correctly
+		// Check if the desired config is set correctly; only the added nodepools are looked up
 		Expect(len(*cluster.GKEConfig.NodePools)).Should(BeNumerically("==", currentNodePoolNumber+increaseBy))
-		for i, np := range *cluster.GKEConfig.NodePools {
-			Expect(np.Name).To(Equal(updateNodePoolsList[i].Name))
-		}
+		Expect(helpers.MissingNodePools(added, nodePoolNames(*cluster.GKEConfig.NodePools))).To(BeEmpty())
 	}
 
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+		err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 		Expect(err).To(BeNil())
 	}
 
 	if checkClusterConfig {
 		// Check if the desired config has been applied in Rancher
-		Eventually(func() int {
-			ginkgo.GinkgoLogr.Info("Waiting for the total nodepool count to increase in GKEStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			return len(*cluster.GKEStatus.UpstreamSpec.NodePools)
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeNumerically("==", currentNodePoolNumber+increaseBy))
+		ginkgo.GinkgoLogr.Info("Waiting for the new nodepools to appear in GKEStatus.UpstreamSpec ...")
+		cluster, err = helpers.WaitForAddedNodePools(client, cluster.ID, added, currentNodePoolNumber+increaseBy, func(cluster *management.Cluster) []string {
+			return nodePoolNames(*cluster.GKEStatus.UpstreamSpec.NodePools)
+		}, tools.SetTimeout(12*time.Minute))
+		Expect(err).To(BeNil())
+	}
+	return cluster, nil
+}
 
-		for i, np := range *cluster.GKEStatus.UpstreamSpec.NodePools {
-			Expect(np.Name).To(Equal(updateNodePoolsList[i].Name))
+// nodePoolNames returns the names of the nodepools
+func nodePoolNames(nodePools []management.GKENodePoolConfig) []string {
+	names := make([]string, 0, len(nodePools))
+	for _, np := range nodePools {
+		if np.Name != nil {
+			names = append(names, *np.Name)
 		}
 	}
+	return names
+}
+
+// AddNodePoolWithMetadata adds a nodepool with the given node labels and taints, for e.g. {Key: "key", Value: "value", Effect: "NO_SCHEDULE"}; it copies the first nodepool of the cluster
+// if wait is set to true, it waits until the update is complete; if checkClusterConfig is true, it validates the nodepool has been added with its labels and taints
+func AddNodePoolWithMetadata(cluster *management.Cluster, client *rancher.Client, labels map[string]string, taints []management.GKENodeTaintConfig, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	defer helpers.TimeOperation(helpers.OperationAddNodePool, cluster, wait)()
+
+	upgradedCluster := new(management.Cluster)
+	upgradedCluster.Name = cluster.Name
+	upgradedCluster.GKEConfig = cluster.GKEConfig
+
+	updateNodePoolsList := *cluster.GKEConfig.NodePools
+	newNodepool := updateNodePoolsList[0]
+	// the node config is shared with the first nodepool, it is copied before being modified
+	nodeConfig := *newNodepool.Config
+	nodeConfig.Labels = labels
+	nodeConfig.Taints = taints
+	newNodepool.Config = &nodeConfig
+	newNodepool.Name = pointer.String(namegen.AppendRandomString("np"))
+	newNodepool.InitialNodeCount = pointer.Int64(1)
+	updateNodePoolsList = append(updateNodePoolsList, newNodepool)
+	upgradedCluster.GKEConfig.NodePools = &updateNodePoolsList
+
+	cluster, err := helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if checkClusterConfig {
+		// Check if the desired config is set correctly
+		configNodePools := *cluster.GKEConfig.NodePools
+		Expect(configNodePools).To(HaveLen(len(updateNodePoolsList)))
+		Expect(configNodePools[len(configNodePools)-1].Config.Labels).To(Equal(labels))
+		Expect(configNodePools[len(configNodePools)-1].Config.Taints).To(Equal(taints))
+	}
+
+	if wait {
+		err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
+		Expect(err).To(BeNil())
+	}
+
+	if checkClusterConfig {
+		// Check if the desired config has been applied in Rancher
+		ginkgo.GinkgoLogr.Info("Waiting for the nodepool labels and taints to appear in GKEStatus.UpstreamSpec ...")
+		cluster, err = helpers.WaitForUpstreamField(client, cluster.ID, func(cluster *management.Cluster) any {
+			for _, np := range *cluster.GKEStatus.UpstreamSpec.NodePools {
+				if *np.Name == *newNodepool.Name {
+					return []any{np.Config.Labels, np.Config.Taints}
+				}
+			}
+			return nil
+		}, []any{labels, taints}, tools.SetTimeout(12*time.Minute))
+		Expect(err).To(BeNil())
+	}
 	return cluster, nil
 }
 
@@ -260,6 +397,8 @@ func AddNodePool(cluster *management.Cluster, client *rancher.Client, increaseBy
 // if wait is set to true, it waits until the update is complete; if checkClusterConfig is true, it validates the update
 // TODO: Modify this method to delete a custom qty of nodepool, perhaps by adding an `decreaseBy int` arg
 func DeleteNodePool(cluster *management.Cluster, client *rancher.Client, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	defer helpers.TimeOperation(helpers.OperationDeleteNodePool, cluster, wait)()
+
 	currentNodePoolNumber := len(*cluster.GKEConfig.NodePools)
 	upgradedCluster := new(management.Cluster)
 	upgradedCluster.Name = cluster.Name
@@ -268,7 +407,7 @@ func DeleteNodePool(cluster *management.Cluster, client *rancher.Client, wait, c
 	updatedNodePoolsList := configNodePools[1:]
 	upgradedCluster.GKEConfig.NodePools = &updatedNodePoolsList
 
-	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err := helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	if err != nil {
 		return nil, err
 	}
@@ -281,18 +420,17 @@ func DeleteNodePool(cluster *management.Cluster, client *rancher.Client, wait, c
 		}
 	}
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+		err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 		Expect(err).To(BeNil())
 	}
 	if checkClusterConfig {
 
 		// Check if the desired config has been applied in Rancher
-		Eventually(func() int {
-			ginkgo.GinkgoLogr.Info("Waiting for the total nodepool count to decrease in GKEStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
+		ginkgo.GinkgoLogr.Info("Waiting for the total nodepool count to decrease in GKEStatus.UpstreamSpec ...")
+		cluster, err = helpers.WaitForUpstreamField(client, cluster.ID, func(cluster *management.Cluster) any {
 			return len(*cluster.GKEStatus.UpstreamSpec.NodePools)
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeNumerically("==", currentNodePoolNumber-1))
+		}, BeNumerically("==", currentNodePoolNumber-1), tools.SetTimeout(12*time.Minute))
+		Expect(err).To(BeNil())
 		for i, np := range *cluster.GKEStatus.UpstreamSpec.NodePools {
 			Expect(np.Name).To(Equal(updatedNodePoolsList[i].Name))
 		}
@@ -304,6 +442,8 @@ func DeleteNodePool(cluster *management.Cluster, client *rancher.Client, wait, c
 // ScaleNodePool modifies the number of initialNodeCount of all the nodepools as defined by nodeCount
 // if wait is set to true, it waits until the update is complete; if checkClusterConfig is true, it validates the update
 func ScaleNodePool(cluster *management.Cluster, client *rancher.Client, nodeCount int64, wait, checkClusterConfig bool) (*management.Cluster, error) {
+	defer helpers.TimeOperation(helpers.OperationScale, cluster, wait)()
+
 	upgradedCluster := new(management.Cluster)
 	upgradedCluster.Name = cluster.Name
 	upgradedCluster.GKEConfig = cluster.GKEConfig
@@ -312,7 +452,7 @@ func ScaleNodePool(cluster *management.Cluster, client *rancher.Client, nodeCoun
 		configNodePools[i].InitialNodeCount = pointer.Int64(nodeCount)
 	}
 
-	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err := helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	if err != nil {
 		return nil, err
 	}
@@ -325,15 +465,15 @@ func ScaleNodePool(cluster *management.Cluster, client *rancher.Client, nodeCoun
 	}
 
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+		err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 		Expect(err).To(BeNil())
 	}
 
 	if checkClusterConfig {
 		// check that the desired config is applied on Rancher
-		Eventually(func() bool {
+		helpers.EventuallyForOperation(helpers.OperationScale, func() bool {
 			ginkgo.GinkgoLogr.Info("Waiting for the node count change to appear in GKEStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			upstreamNodePools := *cluster.GKEStatus.UpstreamSpec.NodePools
 			for i := range upstreamNodePools {
@@ -342,7 +482,7 @@ func ScaleNodePool(cluster *management.Cluster, client *rancher.Client, nodeCoun
 				}
 			}
 			return true
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeTrue())
+		}, tools.SetTimeout(12*time.Minute)).Should(BeTrue())
 	}
 
 	return cluster, nil
@@ -358,7 +498,7 @@ func UpdateMonitoringAndLoggingService(cluster *management.Cluster, client *ranc
 	upgradedCluster.GKEConfig.LoggingService = &loggingService
 	upgradedCluster.GKEConfig.MonitoringService = &monitoringService
 
-	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err := helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	if err != nil {
 		return nil, err
 	}
@@ -367,16 +507,14 @@ func UpdateMonitoringAndLoggingService(cluster *management.Cluster, client *ranc
 		Expect(*cluster.GKEConfig.LoggingService).To(BeEquivalentTo(loggingService))
 	}
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+		err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 		Expect(err).To(BeNil())
 	}
 	if checkClusterConfig {
-		Eventually(func() bool {
-			ginkgo.GinkgoLogr.Info("Waiting for the service change to appear in GKEStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
-			Expect(err).To(BeNil())
-			return *cluster.GKEStatus.UpstreamSpec.MonitoringService == monitoringService && *cluster.GKEStatus.UpstreamSpec.LoggingService == loggingService
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeTrue())
+		ginkgo.GinkgoLogr.Info("Waiting for the service change to appear in GKEStatus.UpstreamSpec ...")
+		return helpers.WaitForUpstreamField(client, cluster.ID, func(cluster *management.Cluster) any {
+			return []string{*cluster.GKEStatus.UpstreamSpec.MonitoringService, *cluster.GKEStatus.UpstreamSpec.LoggingService}
+		}, []string{monitoringService, loggingService}, tools.SetTimeout(12*time.Minute))
 	}
 	return cluster, nil
 }
@@ -394,7 +532,7 @@ func UpdateAutoScaling(cluster *management.Cluster, client *rancher.Client, enab
 			Enabled: enabled,
 		}
 	}
-	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
+	cluster, err := helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 	if err != nil {
 		return nil, err
 	}
@@ -404,13 +542,13 @@ func UpdateAutoScaling(cluster *management.Cluster, client *rancher.Client, enab
 		}
 	}
 	if wait {
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+		err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 		Expect(err).To(BeNil())
 	}
 	if checkClusterConfig {
-		Eventually(func() bool {
+		helpers.EventuallyForOperation(helpers.OperationScale, func() bool {
 			ginkgo.GinkgoLogr.Info("Waiting for the autoscaling update to appear in GKEStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			for _, np := range *cluster.GKEStatus.UpstreamSpec.NodePools {
 				if np.Autoscaling != nil && np.Autoscaling.Enabled != enabled {
@@ -418,7 +556,7 @@ func UpdateAutoScaling(cluster *management.Cluster, client *rancher.Client, enab
 				}
 			}
 			return true
-		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeTrue())
+		}, tools.SetTimeout(12*time.Minute)).Should(BeTrue())
 	}
 	return cluster, nil
 }
@@ -428,7 +566,7 @@ func UpdateCluster(cluster *management.Cluster, client *rancher.Client, updateFu
 	upgradedCluster := cluster
 	updateFunc(upgradedCluster)
 
-	return client.Management.Cluster.Update(cluster, &upgradedCluster)
+	return helpers.UpdateRancherCluster(client, cluster, &upgradedCluster)
 }
 
 // ListGKEAvailableVersions is a function to list and return only available GKE versions for a specific cluster.
@@ -445,10 +583,17 @@ func ListGKEAvailableVersions(client *rancher.Client, clusterID string) ([]strin
 	return helpers.FilterUIUnsupportedVersions(availableVersions, client), nil
 }
 
+// listGKEAllVersions returns all the GKE versions available in the zone/region; the list is fetched once per run
+func listGKEAllVersions(client *rancher.Client, projectID, cloudCredentialID, zone, region string) ([]string, error) {
+	return helpers.CachedVersions(client, fmt.Sprintf("gke/%s/%s/%s/%s", projectID, cloudCredentialID, zone, region), func() ([]string, error) {
+		return kubernetesversions.ListGKEAllVersions(client, projectID, cloudCredentialID, zone, region)
+	})
+}
+
 // ListSingleVariantGKEAvailableVersions returns a list of single variants of minor versions
 // For e.g 1.27.5-gke.1700, 1.26.6-gke.2100, 1.25.8-gke.200
 func ListSingleVariantGKEAvailableVersions(client *rancher.Client, projectID, cloudCredentialID, zone, region string) (availableVersions []string, err error) {
-	availableVersions, err = kubernetesversions.ListGKEAllVersions(client, projectID, cloudCredentialID, zone, region)
+	availableVersions, err = listGKEAllVersions(client, projectID, cloudCredentialID, zone, region)
 	if err != nil {
 		return nil, err
 	}
@@ -481,46 +626,87 @@ func GetK8sVersionVariantGKE(minorVersion string, client *rancher.Client, projec
 
 // <==============================================================================GCLOUD CLI==============================>
 
+// Machine type and number of nodes of the clusters created with gcloud
+const (
+	gcloudMachineType = "n2-standard-2"
+	gcloudNodes       = "1"
+)
+
 // Create Google GKE cluster using gcloud CLI
 func CreateGKEClusterOnGCloud(zone string, clusterName string, project string, k8sVersion string, extraArgs ...string) error {
+	helpers.SkipOnDryRun("GKE cluster " + clusterName)
 
 	labels := helpers.GetCommonMetadataLabels()
 	labelsAsString := k8slabels.SelectorFromSet(labels).String()
 
-	// creating GKE using gcloud changes the kubeconfig to use GKE; this can be problematic for test cases that need to use local cluster;
-	// this workaround helps to keep the original kubeconfig
-	currentKubeconfig := os.Getenv("KUBECONFIG")
-	defer os.Setenv("KUBECONFIG", currentKubeconfig)
-
-	helpers.SetTempKubeConfig(clusterName)
+	// creating GKE using gcloud writes its credentials to the kubeconfig; they are written to a throwaway kubeconfig so that neither the kubeconfig
+	// of the local cluster nor the KUBECONFIG env var change, the kubeconfig of the cluster being only written once a spec needs it
+	discardedKubeconfig, err := os.CreateTemp("", clusterName+"-*.kubeconfig")
+	if err != nil {
+		return err
+	}
+	_ = discardedKubeconfig.Close()
+	defer os.Remove(discardedKubeconfig.Name())
 
 	fmt.Println("Creating GKE cluster ...")
-	args := []string{"container", "clusters", "create", clusterName, "--project", project, "--zone", zone, "--cluster-version", k8sVersion, "--labels", labelsAsString, "--network", "default", "--release-channel", "None", "--machine-type", "n2-standard-2", "--disk-size", "100", "--num-nodes", "1", "--no-enable-master-authorized-networks"}
+	args := []string{"container", "clusters", "create", clusterName, "--project", project, "--zone", zone, "--cluster-version", k8sVersion, "--labels", labelsAsString, "--network", "default", "--release-channel", "None", "--machine-type", gcloudMachineType, "--disk-size", "100", "--num-nodes", gcloudNodes, "--no-enable-master-authorized-networks"}
 	args = append(args, extraArgs...)
-	fmt.Printf("Running command: gcloud %v\n", args)
-	out, err := proc.RunW("gcloud", args...)
+	start := time.Now()
+	_, err = extcli.Gcloud.WithEnv("KUBECONFIG=" + discardedKubeconfig.Name()).Run(args...)
+	helpers.RecordOperation(helpers.OperationCLIProvision, clusterName, start, err)
 	if err != nil {
-		return errors.Wrap(err, "Failed to create cluster: "+out)
+		return errors.Wrap(err, "Failed to create cluster")
 	}
 
 	fmt.Println("Created GKE cluster: ", clusterName)
+	registerGKEKubeconfig(clusterName, zone, project)
+	helpers.TrackResource(helpers.Resource{Kind: helpers.ResourceCloudCluster, Name: clusterName, Region: zone, Tags: labels, NodePools: helpers.CLINodePools(gcloudMachineType, gcloudNodes)})
+
+	return nil
+}
 
+// GetGKECredentialsOnGCloud registers how to write the credentials of the GKE cluster to its kubeconfig, so that it can be reached with kubectl
+// without going through Rancher; the kubeconfig is only written once a spec needs it, see helpers.DownstreamKubeconfigPath
+func GetGKECredentialsOnGCloud(clusterName, zone, project string) error {
+	registerGKEKubeconfig(clusterName, zone, project)
 	return nil
 }
 
+// registerGKEKubeconfig registers the kubeconfig generator of the cluster, see GetGKECredentialsOnGCloud
+func registerGKEKubeconfig(clusterName, zone, project string) {
+	helpers.RegisterKubeconfigGenerator(clusterName, func(kubeconfig string) error {
+		fmt.Println("Getting GKE cluster credentials ...")
+		args := []string{"container", "clusters", "get-credentials", clusterName, "--project", project, "--zone", zone}
+		_, err := extcli.Gcloud.WithEnv("KUBECONFIG=" + kubeconfig).Run(args...)
+		if err != nil {
+			return errors.Wrap(err, "Failed to get cluster credentials")
+		}
+		return nil
+	})
+}
+
+// ListGKEClustersOnGCloud lists the clusters of the zone matching the gcloud filter, for e.g. the name of a cluster
+func ListGKEClustersOnGCloud(filter, project, zone string) ([]cloudjson.GKECluster, error) {
+	args := []string{"container", "clusters", "list", "--filter", filter, "--project", project, "--zone", zone, "--format", "json"}
+	out, err := extcli.Gcloud.Output(args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list cluster")
+	}
+	return cloudjson.Decode[[]cloudjson.GKECluster](out)
+}
+
 // ClusterExistsOnGCloud gets a list of cluster based on the name filter and returns true if the cluster is in RUNNING or PROVISIONING state;
 // it returns false if the cluster does not exist or is in STOPPING state.
 func ClusterExistsOnGCloud(clusterName, project, zone string) (bool, error) {
 	fmt.Println("Listing GKE cluster ...")
-	args := []string{"container", "clusters", "list", "--filter", clusterName, "--project", project, "--zone", zone}
-
-	fmt.Printf("Running command: gcloud %v\n", args)
-	out, err := proc.RunW("gcloud", args...)
+	gkeClusters, err := ListGKEClustersOnGCloud(clusterName, project, zone)
 	if err != nil {
-		return false, errors.Wrap(err, "Failed to list cluster: "+out)
+		return false, err
 	}
-	if strings.Contains(out, "RUNNING") || strings.Contains(out, "PROVISIONING") {
-		return true, nil
+	for _, gkeCluster := range gkeClusters {
+		if gkeCluster.Name == clusterName && gkeCluster.Usable() {
+			return true, nil
+		}
 	}
 	return false, nil
 }
@@ -535,10 +721,9 @@ func AddNodePoolOnGCloud(clusterName, zone, project, npName string, extraArgs ..
 	args := []string{"container", "node-pools", "create", npName, "--cluster", clusterName, "--project", project, "--zone", zone, "--num-nodes", "1", "--enable-autoscaling", "--max-nodes", "1", "--min-nodes", "0"}
 
 	args = append(args, extraArgs...)
-	fmt.Printf("Running command: gcloud %v\n", args)
-	out, err := proc.RunW("gcloud", args...)
+	_, err := extcli.Gcloud.Run(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to add node pool: "+out)
+		return errors.Wrap(err, "Failed to add node pool")
 	}
 	fmt.Println("Node pool added on GKE cluster ...")
 	return nil
@@ -548,10 +733,9 @@ func AddNodePoolOnGCloud(clusterName, zone, project, npName string, extraArgs ..
 func DeleteNodePoolOnGCloud(zone, project, clusterName, poolName string) error {
 	fmt.Println("Deleting node pool on GKE cluster ...")
 	args := []string{"container", "node-pools", "delete", poolName, "--cluster", clusterName, "--project", project, "--zone", zone, "--quiet"}
-	fmt.Printf("Running command: gcloud %v\n", args)
-	out, err := proc.RunW("gcloud", args...)
+	_, err := extcli.Gcloud.Run(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to delete node pool: "+out)
+		return errors.Wrap(err, "Failed to delete node pool")
 	}
 	fmt.Println("Node pool deleted on GKE cluster ...")
 	return nil
@@ -577,10 +761,9 @@ func UpgradeGKEClusterOnGCloud(zone, clusterName, project, k8sVersion string, up
 
 	args = append(args, exrtaArgs...)
 
-	fmt.Printf("Running command: gcloud %v\n", args)
-	out, err := proc.RunW("gcloud", args...)
+	_, err := extcli.Gcloud.Run(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to upgrade cluster: "+out)
+		return errors.Wrap(err, "Failed to upgrade cluster")
 	}
 
 	if upgradeNodePool {
@@ -591,25 +774,36 @@ func UpgradeGKEClusterOnGCloud(zone, clusterName, project, k8sVersion string, up
 	return nil
 }
 
+// UpdateClusterLabelsOnGCloud adds or updates the resource labels of the GKE cluster via gcloud CLI
+func UpdateClusterLabelsOnGCloud(zone, clusterName, project string, labels map[string]string) error {
+	var updatedLabels []string
+	for key, value := range labels {
+		updatedLabels = append(updatedLabels, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	fmt.Println("Updating labels of GKE cluster ...")
+	args := []string{"container", "clusters", "update", clusterName, "--update-labels", strings.Join(updatedLabels, ","), "--project", project, "--zone", zone, "--quiet"}
+	_, err := extcli.Gcloud.Run(args...)
+	if err != nil {
+		return errors.Wrap(err, "Failed to update labels")
+	}
+	fmt.Println("Updated labels of GKE cluster: ", clusterName)
+	return nil
+}
+
 // Complete cleanup steps for Google GKE
 func DeleteGKEClusterOnGCloud(zone, project, clusterName string) error {
-	currentKubeconfig := os.Getenv("KUBECONFIG")
-	downstreamKubeconfig := os.Getenv(helpers.DownstreamKubeconfig(clusterName))
-	defer func() {
-		_ = os.Setenv("KUBECONFIG", currentKubeconfig)
-		_ = os.Remove(downstreamKubeconfig) // clean up
-	}()
-	_ = os.Setenv("KUBECONFIG", downstreamKubeconfig)
+	defer helpers.RemoveDownstreamKubeconfig(clusterName) // clean up
 
 	fmt.Println("Deleting GKE cluster ...")
 	args := []string{"container", "clusters", "delete", clusterName, "--zone", zone, "--quiet", "--project", project, "--async"}
-	fmt.Printf("Running command: gcloud %v\n", args)
-	out, err := proc.RunW("gcloud", args...)
+	_, err := extcli.Gcloud.Run(args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to delete cluster: "+out)
+		return errors.Wrap(err, "Failed to delete cluster")
 	}
 
 	fmt.Println("Deleted GKE cluster: ", clusterName)
+	helpers.MarkResourceDeleted(helpers.ResourceCloudCluster, clusterName)
 
 	return nil
 }
@@ -621,8 +815,7 @@ func EnableDisableServiceAccountOnGCloud(clientID, project, op string) error {
 	}
 	fmt.Printf("%s service account on GKE cluster...\n", op)
 	var args = []string{"iam", "service-accounts", op, fmt.Sprintf("%s@%s.iam.gserviceaccount.com", clientID, project), "--project", project}
-	fmt.Printf("Running command: gcloud %v\n", args)
-	out, err := proc.RunW("gcloud", args...)
+	out, err := extcli.Gcloud.Output(args...)
 	if err != nil {
 		return errors.Wrap(err, fmt.Sprintf("Failed to %s service-account: %s", op, out))
 	}
@@ -635,9 +828,16 @@ func EnableDisableServiceAccountOnGCloud(clientID, project, op string) error {
 // <==============================================================================GCLOUD CLI (end)==============================>
 
 // GetK8sVersion returns the k8s version to be used by the test;
-// this value can either be a variant of envvar DOWNSTREAM_K8S_MINOR_VERSION or the highest available version
+// this value can either be a variant of envvar DOWNSTREAM_K8S_MINOR_VERSION, the highest version matching it if it is a semver constraint, or the highest available version
 // or second-highest minor version in case of upgrade scenarios
 func GetK8sVersion(client *rancher.Client, projectID, cloudCredentialID, zone, region string, forUpgrade bool) (string, error) {
+	if constraint := helpers.DownstreamK8sMinorVersion; helpers.IsK8sVersionConstraint(constraint) {
+		allVersions, err := listGKEAllVersions(client, projectID, cloudCredentialID, zone, region)
+		if err != nil {
+			return "", err
+		}
+		return helpers.MatchK8sVersion(helpers.FilterUIUnsupportedVersions(allVersions, client), constraint)
+	}
 	if k8sMinorVersion := helpers.DownstreamK8sMinorVersion; k8sMinorVersion != "" {
 		return GetK8sVersionVariantGKE(k8sMinorVersion, client, projectID, cloudCredentialID, zone, region)
 	}
@@ -0,0 +1,86 @@
+package helper
+
+import (
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+func init() {
+	helpers.RegisterProvider(gkeProvider{})
+}
+
+// gkeProvider is the GKE implementation of helpers.HostedProvider; zonal clusters are used, in the zone of GetGKEZone, like in the GKE suites
+type gkeProvider struct{}
+
+func (gkeProvider) Name() string {
+	return "gke"
+}
+
+func (gkeProvider) Location() string {
+	return helpers.GetGKEZone()
+}
+
+func (gkeProvider) K8sVersion(client *rancher.Client, cloudCredID string, forUpgrade bool) (string, error) {
+	return GetK8sVersion(client, helpers.GetGKEProjectID(), cloudCredID, helpers.GetGKEZone(), "", forUpgrade)
+}
+
+func (gkeProvider) AvailableVersions(client *rancher.Client, cluster *management.Cluster) ([]string, error) {
+	return ListGKEAvailableVersions(client, cluster.ID)
+}
+
+func (gkeProvider) CreateCluster(client *rancher.Client, clusterName, cloudCredID, k8sVersion string) (*management.Cluster, error) {
+	return CreateGKEHostedCluster(client, clusterName, cloudCredID, k8sVersion, helpers.GetGKEZone(), "", helpers.GetGKEProjectID(), nil)
+}
+
+func (gkeProvider) ImportCluster(client *rancher.Client, clusterName, cloudCredID string) (*management.Cluster, error) {
+	return ImportGKEHostedCluster(client, clusterName, cloudCredID, helpers.GetGKEZone(), helpers.GetGKEProjectID())
+}
+
+func (gkeProvider) UpgradeCluster(cluster *management.Cluster, client *rancher.Client, k8sVersion string) (*management.Cluster, error) {
+	return UpgradeKubernetesVersion(cluster, k8sVersion, client, false, true, true)
+}
+
+func (gkeProvider) UpdateCluster(cluster *management.Cluster, client *rancher.Client, updateFunc func(*management.Cluster)) (*management.Cluster, error) {
+	return UpdateCluster(cluster, client, updateFunc)
+}
+
+func (gkeProvider) AddNodePool(cluster *management.Cluster, client *rancher.Client) (*management.Cluster, error) {
+	return AddNodePool(cluster, client, 1, "", true, true)
+}
+
+func (gkeProvider) DeleteNodePool(cluster *management.Cluster, client *rancher.Client) (*management.Cluster, error) {
+	return DeleteNodePool(cluster, client, true, true)
+}
+
+func (gkeProvider) ScaleNodePools(cluster *management.Cluster, client *rancher.Client, nodeCount int64) (*management.Cluster, error) {
+	return ScaleNodePool(cluster, client, nodeCount, true, true)
+}
+
+func (gkeProvider) DeleteCluster(cluster *management.Cluster, client *rancher.Client) error {
+	return DeleteGKEHostCluster(cluster, client)
+}
+
+func (gkeProvider) OutOfBand() helpers.OutOfBandClient {
+	return gkeOutOfBandClient{}
+}
+
+// gkeOutOfBandClient manages the GKE clusters of the GKE_PROJECT_ID project with gcloud; the location is the zone of the cluster
+type gkeOutOfBandClient struct{}
+
+func (gkeOutOfBandClient) CreateCluster(clusterName, k8sVersion string) error {
+	return CreateGKEClusterOnGCloud(helpers.GetGKEZone(), clusterName, helpers.GetGKEProjectID(), k8sVersion)
+}
+
+func (gkeOutOfBandClient) ClusterExists(clusterName, zone string) (bool, error) {
+	return ClusterExistsOnGCloud(clusterName, helpers.GetGKEProjectID(), zone)
+}
+
+func (gkeOutOfBandClient) DeleteCluster(clusterName, zone string) error {
+	return DeleteGKEClusterOnGCloud(zone, helpers.GetGKEProjectID(), clusterName)
+}
+
+func (gkeOutOfBandClient) UpdateTags(clusterName, zone string, labels map[string]string) error {
+	return UpdateClusterLabelsOnGCloud(zone, clusterName, helpers.GetGKEProjectID(), labels)
+}
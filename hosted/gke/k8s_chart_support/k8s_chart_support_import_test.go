@@ -38,8 +38,8 @@ var _ = Describe("K8sChartSupportImport", func() {
 		}
 	})
 
+	// No GKE case covers the import yet in Qase, so its results are not reported
 	It("should successfully test k8s chart support import", func() {
-		testCaseID = 65 // Report to Qase
 		commonChartSupport(ctx.RancherAdminClient, cluster)
 	})
 })
@@ -9,6 +9,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("K8sChartSupportProvisioning", func() {
@@ -33,8 +34,7 @@ var _ = Describe("K8sChartSupportProvisioning", func() {
 		}
 	})
 
-	It("should successfully test k8s chart support provisioning", func() {
-		testCaseID = 63 // Report to Qase
+	It("should successfully test k8s chart support provisioning", helpers.QaseLabel(qasecases.GKEK8sChartSupportTestK8sChartSupportProvisioning), func() {
 		commonChartSupport(ctx.RancherAdminClient, cluster)
 	})
 
@@ -2,6 +2,7 @@ package k8s_chart_support_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,11 +11,8 @@ import (
 	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
 	"github.com/rancher/shepherd/clients/rancher"
-	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 
-	. "github.com/rancher-sandbox/qase-ginkgo"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	"github.com/rancher/shepherd/extensions/clusters"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
@@ -23,14 +21,13 @@ import (
 var (
 	ctx                     helpers.RancherContext
 	clusterName, k8sVersion string
-	testCaseID              int64
 	zone                    = helpers.GetGKEZone()
 	project                 = helpers.GetGKEProjectID()
 )
 
 func TestK8sChartSupport(t *testing.T) {
 	RegisterFailHandler(Fail)
-	RunSpecs(t, "K8sChartSupport Suite")
+	RunSpecs(t, "K8sChartSupport Suite", helpers.SuiteConfig(t))
 }
 
 var _ = SynchronizedBeforeSuite(func() []byte {
@@ -47,8 +44,13 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 })
 
 var _ = BeforeEach(func() {
+	helpers.CollectSpecArtifacts()
+	helpers.RecordClusterTransitions(ctx.RancherAdminClient)
+	// Restore the settings and the operator charts changed by the test, for e.g. by a chart downgrade
+	DeferCleanup(helpers.SnapshotRancherSettings().Restore)
+
 	var err error
-	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+	clusterName = helpers.GenerateClusterName(helpers.ClusterNamePrefix)
 
 	k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "", false)
 	Expect(err).To(BeNil())
@@ -56,20 +58,26 @@ var _ = BeforeEach(func() {
 	GinkgoLogr.Info(fmt.Sprintf("Using GKE version %s for cluster %s", k8sVersion, clusterName))
 })
 
-var _ = AfterEach(func() {
-	By("Uninstalling the existing operator charts", func() {
-		helpers.UninstallOperatorCharts()
-	})
+var _ = JustAfterEach(func() {
+	// Collect the support bundle before AfterEach deletes the cluster
+	helpers.CollectSupportBundleOnFailure(clusterName)
 })
 
-var _ = ReportBeforeEach(func(report SpecReport) {
-	// Reset case ID
-	testCaseID = -1
-})
+// Delete the clusters leaked by all the processes once they are done
+var _ = SynchronizedAfterSuite(func() {}, helpers.CommonSynchronizedAfterSuite)
 
 var _ = ReportAfterEach(func(report SpecReport) {
 	// Add result in Qase if asked
-	Qase(testCaseID, report)
+	helpers.ReportToQase(report)
+	// Upload the artifacts of the failed spec to ARTIFACTS_BUCKET if set
+	helpers.UploadArtifactsOnFailure(report)
+})
+
+var _ = ReportAfterSuite("Reports", func(report Report) {
+	// Write the JSON, JUnit and summary reports to the artifacts directory
+	helpers.GenerateSuiteReports(report)
+	// Submit the Qase results of all the parallel processes
+	helpers.SubmitQaseResults()
 })
 
 // commonChartSupport runs the common checks required for testing chart support
@@ -81,24 +89,42 @@ func commonChartSupport(client *rancher.Client, cluster *management.Cluster) {
 		GinkgoLogr.Info("Original chart version: " + originalChartVersion)
 	})
 
-	var downgradedVersion string
-	By("obtaining a version to downgrade", func() {
-		downgradedVersion = helpers.GetDowngradeOperatorChartVersion(originalChartVersion)
-		Expect(downgradedVersion).ToNot(BeEmpty())
-		GinkgoLogr.Info("Downgrading to version: " + downgradedVersion)
+	var downgradedVersions []string
+	By(fmt.Sprintf("obtaining up to %d versions to downgrade", helpers.ChartDowngradeVersions), func() {
+		downgradedVersions = helpers.GetDowngradeOperatorChartVersions(originalChartVersion, helpers.ChartDowngradeVersions)
+		Expect(downgradedVersions).ToNot(BeEmpty())
+		GinkgoLogr.Info("Downgrading to versions: " + strings.Join(downgradedVersions, ", "))
 	})
 
-	By("downgrading the chart version", func() {
-		helpers.DowngradeProviderChart(downgradedVersion)
-	})
+	configNodePools := *cluster.GKEConfig.NodePools
+	initialNodeCount := *configNodePools[0].InitialNodeCount
 
-	By("making a change to the cluster to validate functionality after chart downgrade", func() {
-		configNodePools := *cluster.GKEConfig.NodePools
-		initialNodeCount := *configNodePools[0].InitialNodeCount
-		var err error
-		cluster, err = helper.ScaleNodePool(cluster, client, initialNodeCount+1, true, true)
-		Expect(err).To(BeNil())
-	})
+	for i, downgradedVersion := range downgradedVersions {
+		By(fmt.Sprintf("downgrading the chart version to %s", downgradedVersion), func() {
+			helpers.DowngradeProviderChart(downgradedVersion)
+		})
+
+		By("making a change to the cluster to validate functionality after chart downgrade", func() {
+			var err error
+			cluster, err = helper.ScaleNodePool(cluster, client, initialNodeCount+1, true, true)
+			Expect(err).To(BeNil())
+		})
+
+		// the chart of the last cycle is upgraded by rancher, which re-installs it once uninstalled
+		if i == len(downgradedVersions)-1 {
+			break
+		}
+
+		By(fmt.Sprintf("upgrading the chart version back to %s", originalChartVersion), func() {
+			helpers.UpdateOperatorChartsVersion(originalChartVersion)
+		})
+
+		By("making a change(scaling nodepool down) to the cluster to validate functionality after chart upgrade", func() {
+			var err error
+			cluster, err = helper.ScaleNodePool(cluster, client, initialNodeCount, true, true)
+			Expect(err).To(BeNil())
+		})
+	}
 
 	By("uninstalling the operator chart", func() {
 		helpers.UninstallOperatorCharts()
@@ -120,9 +146,9 @@ func commonChartSupport(client *rancher.Client, cluster *management.Cluster) {
 			helpers.CheckRancherDeployments(kubectl.New())
 		})
 
-		err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+		err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 		Expect(err).To(BeNil())
-		Eventually(func() int {
+		helpers.EventuallyWithBackoff(func() int {
 			GinkgoLogr.Info("Waiting for the total nodepool count to increase in GKEStatus.UpstreamSpec ...")
 			cluster, err = client.Management.Cluster.ByID(cluster.ID)
 			Expect(err).To(BeNil())
@@ -130,4 +156,11 @@ func commonChartSupport(client *rancher.Client, cluster *management.Cluster) {
 		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeNumerically("==", currentNodePoolNumber+1))
 
 	})
+
+	if helpers.IsRancherBehindProxy() {
+		By("checking that the re-installed operator works through the proxy", func() {
+			helpers.CheckProxyConfiguration()
+			helpers.CheckOperatorTrafficThroughProxy()
+		})
+	}
 }
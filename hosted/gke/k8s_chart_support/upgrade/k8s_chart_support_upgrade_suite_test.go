@@ -10,14 +10,9 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
-	. "github.com/rancher-sandbox/qase-ginkgo"
-	"github.com/rancher/shepherd/clients/rancher"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	"github.com/rancher/shepherd/extensions/clusters"
 	nodestat "github.com/rancher/shepherd/extensions/nodes"
 	"github.com/rancher/shepherd/extensions/workloads/pods"
-	"github.com/rancher/shepherd/pkg/config"
-	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
@@ -26,7 +21,6 @@ import (
 var (
 	ctx                     helpers.RancherContext
 	clusterName, k8sVersion string
-	testCaseID              int64
 	zone                    = helpers.GetGKEZone()
 	project                 = helpers.GetGKEProjectID()
 	k                       = kubectl.New()
@@ -34,16 +28,14 @@ var (
 
 func TestK8sChartSupportUpgrade(t *testing.T) {
 	RegisterFailHandler(Fail)
-	RunSpecs(t, "K8sChartSupportUpgrade Suite")
+	RunSpecs(t, "K8sChartSupportUpgrade Suite", helpers.SuiteConfig(t))
 }
 
 var _ = BeforeEach(func() {
-	// For upgrade tests, the rancher version should not be an unreleased version (for e.g. 2.9-head)
-	Expect(helpers.RancherFullVersion).To(SatisfyAll(Not(BeEmpty()), Not(ContainSubstring("devel"))))
+	helpers.CollectSpecArtifacts()
 
-	Expect(helpers.RancherUpgradeFullVersion).ToNot(BeEmpty())
-	Expect(helpers.K8sUpgradedMinorVersion).ToNot(BeEmpty())
-	Expect(helpers.Kubeconfig).ToNot(BeEmpty())
+	// For upgrade tests, the rancher version should not be an unreleased version (for e.g. 2.9-head)
+	helpers.ValidateRunConfig(helpers.SuiteUpgrade)
 
 	By("Adding the necessary chart repos", func() {
 		helpers.AddRancherCharts()
@@ -51,27 +43,16 @@ var _ = BeforeEach(func() {
 
 	By(fmt.Sprintf("Installing Rancher Manager %s", helpers.RancherFullVersion), func() {
 		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(helpers.RancherFullVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
+		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, helpers.RancherBehindProxy, "none")
 		helpers.CheckRancherDeployments(k)
 	})
 
 	helpers.CommonSynchronizedBeforeSuite()
 	ctx = helpers.CommonBeforeSuite()
+	helpers.RecordClusterTransitions(ctx.RancherAdminClient)
+	DeferCleanup(helpers.SnapshotRancherSettings().Restore)
 
-	By("creating and using a more permanent token", func() {
-		token, err := ctx.RancherAdminClient.Management.Token.Create(&management.Token{})
-		Expect(err).NotTo(HaveOccurred())
-		rancherConfig := new(rancher.Config)
-		config.LoadConfig(rancher.ConfigurationFileKey, rancherConfig)
-		rancherConfig.AdminToken = token.Token
-		config.UpdateConfig(rancher.ConfigurationFileKey, rancherConfig)
-
-		rancherAdminClient, err := rancher.NewClient(rancherConfig.AdminToken, ctx.Session)
-		Expect(err).To(BeNil())
-		ctx.RancherAdminClient = rancherAdminClient
-	})
-
-	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+	clusterName = helpers.GenerateClusterName(helpers.ClusterNamePrefix)
 
 	var err error
 	// For k8s chart support upgrade we want to begin with the default k8s version; we will upgrade rancher and then upgrade k8s to the default available there.
@@ -81,26 +62,35 @@ var _ = BeforeEach(func() {
 })
 
 var _ = AfterEach(func() {
-	// The test must restore the env to its original state, so we install rancher back to its original version and uninstall the operator charts
+	// The test must restore the env to its original state, so we install rancher back to its original version;
+	// the settings and the operator charts are then restored from the snapshot taken by BeforeEach
 	By(fmt.Sprintf("Installing Rancher back to its original version %s", helpers.RancherFullVersion), func() {
 		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(helpers.RancherFullVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
+		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, helpers.RancherBehindProxy, "none")
 		helpers.CheckRancherDeployments(k)
 	})
-
-	By("Uninstalling the existing operator charts", func() {
-		helpers.UninstallOperatorCharts()
-	})
 })
 
-var _ = ReportBeforeEach(func(report SpecReport) {
-	// Reset case ID
-	testCaseID = -1
+var _ = JustAfterEach(func() {
+	// Collect the support bundle before AfterEach deletes the cluster
+	helpers.CollectSupportBundleOnFailure(clusterName)
 })
 
+// Delete the clusters leaked by all the processes once they are done
+var _ = SynchronizedAfterSuite(func() {}, helpers.CommonSynchronizedAfterSuite)
+
 var _ = ReportAfterEach(func(report SpecReport) {
 	// Add result in Qase if asked
-	Qase(testCaseID, report)
+	helpers.ReportToQase(report)
+	// Upload the artifacts of the failed spec to ARTIFACTS_BUCKET if set
+	helpers.UploadArtifactsOnFailure(report)
+})
+
+var _ = ReportAfterSuite("Reports", func(report Report) {
+	// Write the JSON, JUnit and summary reports to the artifacts directory
+	helpers.GenerateSuiteReports(report)
+	// Submit the Qase results of all the parallel processes
+	helpers.SubmitQaseResults()
 })
 
 // commonChartSupportUpgrade runs the common checks required for testing chart support
@@ -116,11 +106,11 @@ func commonChartSupportUpgrade(ctx *helpers.RancherContext, cluster *management.
 
 	By("upgrading rancher", func() {
 		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(rancherUpgradedVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
+		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, helpers.RancherBehindProxy, "none")
 		helpers.CheckRancherDeployments(k)
 
 		By("ensuring operator pods are also up", func() {
-			Eventually(func() error {
+			helpers.EventuallyWithBackoff(func() error {
 				return k.WaitForNamespaceWithPod(helpers.CattleSystemNS, fmt.Sprintf("ke.cattle.io/operator=%s", helpers.Provider))
 			}, tools.SetTimeout(4*time.Minute), 30*time.Second).Should(BeNil())
 		})
@@ -219,10 +209,10 @@ func commonChartSupportUpgrade(ctx *helpers.RancherContext, cluster *management.
 			helpers.WaitUntilOperatorChartInstallation(upgradedChartVersion, "", 0)
 		})
 
-		err = clusters.WaitClusterToBeUpgraded(ctx.RancherAdminClient, cluster.ID)
+		err = helpers.WaitClusterToBeUpgraded(ctx.RancherAdminClient, cluster.ID)
 		Expect(err).To(BeNil())
 
-		Eventually(func() int {
+		helpers.EventuallyWithBackoff(func() int {
 			GinkgoLogr.Info("Waiting for the total nodepool count to increase in GKEStatus.UpstreamSpec ...")
 			cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
 			Expect(err).To(BeNil())
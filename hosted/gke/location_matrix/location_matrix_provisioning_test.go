@@ -0,0 +1,87 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package location_matrix_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/clusters/gke"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var project = helpers.GetGKEProjectID()
+
+var _ = Describe("LocationMatrixProvisioning", func() {
+
+	for _, location := range helpers.GKELocationMatrix(helpers.GetGKEMatrixZones()) {
+		location := location
+
+		When(fmt.Sprintf("a %s is created", location.Description), func() {
+			var (
+				clusterName string
+				cluster     *management.Cluster
+			)
+			BeforeEach(func() {
+				clusterName = helpers.GenerateClusterName(helpers.ClusterNamePrefix)
+			})
+			JustAfterEach(func() {
+				helpers.CollectSupportBundleOnFailure(clusterName)
+			})
+			AfterEach(func() {
+				if ctx.ClusterCleanup && cluster != nil {
+					err := helper.DeleteGKEHostCluster(cluster, ctx.RancherAdminClient)
+					Expect(err).To(BeNil())
+				} else {
+					fmt.Println("Skipping downstream cluster deletion: ", clusterName)
+				}
+			})
+			It("should successfully provision the cluster in the location", func() {
+				// the available versions depend on the location
+				k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, project, ctx.CloudCredID, location.Zone, location.Region, false)
+				Expect(err).To(BeNil())
+				GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for %s %s", k8sVersion, location.Description, clusterName))
+
+				cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location.Zone, location.Region, project, func(clusterConfig *gke.ClusterConfig) {
+					clusterConfig.Locations = location.Locations
+				})
+				Expect(err).To(BeNil())
+				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				Expect(err).To(BeNil())
+				helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+
+				upstreamSpec := cluster.GKEStatus.UpstreamSpec
+				Expect(upstreamSpec.Zone).To(Equal(location.Zone))
+				Expect(upstreamSpec.Region).To(Equal(location.Region))
+				Expect(upstreamSpec.Locations).ToNot(BeNil())
+				switch {
+				case len(location.Locations) > 0:
+					Expect(*upstreamSpec.Locations).To(ConsistOf(location.Locations))
+				case location.Zone != "":
+					Expect(*upstreamSpec.Locations).To(ConsistOf(location.Zone))
+				default:
+					// GKE spreads the nodes of a regional cluster across the zones of the region
+					for _, zone := range *upstreamSpec.Locations {
+						Expect(helpers.GKERegionOfZone(zone)).To(Equal(location.Region))
+					}
+				}
+			})
+		})
+	}
+})
@@ -25,23 +25,24 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("P0Import", func() {
 	for _, testData := range []struct {
-		qaseID    int64
+		qaseID    qasecases.CaseID
 		isUpgrade bool
 		testBody  func(cluster *management.Cluster, client *rancher.Client, clusterName string)
 		testTitle string
 	}{
 		{
-			qaseID:    9,
+			qaseID:    qasecases.GKEP0ImportClusterAddDeleteScaleNodepool,
 			isUpgrade: false,
 			testBody:  p0NodesChecks,
 			testTitle: "should successfully import the cluster & add, delete, scale nodepool",
 		},
 		{
-			qaseID:    10,
+			qaseID:    qasecases.GKEP0UpgradeK8sVersionOfImportedCluster,
 			isUpgrade: true,
 			testBody:  p0upgradeK8sVersionChecks,
 			testTitle: "should be able to upgrade k8s version of the imported cluster",
@@ -79,8 +80,7 @@ var _ = Describe("P0Import", func() {
 				}
 			})
 
-			It(testData.testTitle, func() {
-				testCaseID = testData.qaseID
+			It(testData.testTitle, helpers.QaseLabel(testData.qaseID), Label(helpers.CriticalSpec), func() {
 				testData.testBody(cluster, ctx.RancherAdminClient, clusterName)
 			})
 		})
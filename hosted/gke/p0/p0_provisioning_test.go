@@ -27,35 +27,36 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("P0Provisioning", func() {
 	for _, testData := range []struct {
-		qaseID    int64
+		qaseID    qasecases.CaseID
 		isUpgrade bool
 		testBody  func(cluster *management.Cluster, client *rancher.Client, clusterName string)
 		testTitle string
 	}{
 		{
-			qaseID:    8,
+			qaseID:    qasecases.GKEP0ProvisionZonalClusterAddDeleteScaleNodepool,
 			isUpgrade: false,
 			testBody:  p0NodesChecks,
 			testTitle: "should successfully provision the zonal cluster & add, delete, scale nodepool",
 		},
 		{
-			qaseID:    11,
+			qaseID:    qasecases.GKEP0UpgradeK8sVersionOfZonalProvisionedCluster,
 			isUpgrade: true,
 			testBody:  p0upgradeK8sVersionChecks,
 			testTitle: "should be able to upgrade k8s version of the zonal provisioned cluster",
 		},
 		{
-			qaseID:    300,
+			qaseID:    qasecases.GKEP0ProvisionRegionalClusterAddDeleteScaleNodepool,
 			isUpgrade: false,
 			testBody:  p0NodesChecks,
 			testTitle: "should successfully provision the regional cluster & add, delete, scale nodepool",
 		},
 		{
-			qaseID:    301,
+			qaseID:    qasecases.GKEP0UpgradeK8sVersionOfRegionalProvisionedCluster,
 			isUpgrade: true,
 			testBody:  p0upgradeK8sVersionChecks,
 			testTitle: "should be able to upgrade k8s version of the regional provisioned cluster",
@@ -68,6 +69,8 @@ var _ = Describe("P0Provisioning", func() {
 					Skip(helpers.SkipUpgradeTestsLog)
 				}
 
+				// the zone and region of the suite are left as is, the other specs of the process rely on them
+				zone, region := zone, region
 				if strings.Contains(testData.testTitle, "regional") {
 					zone = ""
 					updateFunc = func(clusterConfig *gke.ClusterConfig) {
@@ -98,8 +101,7 @@ var _ = Describe("P0Provisioning", func() {
 				}
 			})
 
-			It(testData.testTitle, func() {
-				testCaseID = testData.qaseID
+			It(testData.testTitle, helpers.QaseLabel(testData.qaseID), Label(helpers.CriticalSpec), func() {
 				testData.testBody(cluster, ctx.RancherAdminClient, clusterName)
 			})
 
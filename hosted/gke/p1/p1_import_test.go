@@ -10,6 +10,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("P1Import", func() {
@@ -45,16 +46,25 @@ var _ = Describe("P1Import", func() {
 			Expect(err).To(BeNil())
 		})
 
-		It("User should not be able to import a cluster using an expired GKE creds", func() {
-			testCaseID = 305
+		It("User should not be able to import a cluster using an expired GKE creds", helpers.QaseLabel(qasecases.GKEP1UserNotImportClusterUsingExpiredGKECreds), func() {
 			expiredCredCheck(cluster, ctx.RancherAdminClient)
 		})
 
-		It("User should not be able to import cluster with invalid GKE creds in Rancher", func() {
-			testCaseID = 306
+		It("User should not be able to import cluster with invalid GKE creds in Rancher", helpers.QaseLabel(qasecases.GKEP1UserNotImportClusterWithInvalidGKECreds), func() {
 			invalidCredCheck(cluster, ctx.RancherAdminClient)
 		})
 
+		It("should import a cluster with pre-existing workloads and leave them untouched", func() {
+			fingerprints := helpers.DeployPreImportWorkloads(clusterName)
+
+			var err error
+			cluster, err = helper.ImportGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, zone, project)
+			Expect(err).To(BeNil())
+			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+			helpers.CheckPreImportWorkloads(ctx.RancherAdminClient, cluster, fingerprints)
+		})
+
 		When("the cluster is imported", func() {
 
 			BeforeEach(func() {
@@ -65,15 +75,13 @@ var _ = Describe("P1Import", func() {
 				Expect(err).To(BeNil())
 			})
 
-			It("should fail to reimport an imported cluster", func() {
-				testCaseID = 49
+			It("should fail to reimport an imported cluster", helpers.QaseLabel(qasecases.GKEP1FailReimportImportedCluster), func() {
 				_, err := helper.ImportGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, zone, project)
 				Expect(err).ToNot(BeNil())
 				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("cluster already exists for GKE cluster [%s] in zone [%s]", clusterName, zone)))
 			})
 
-			It("should be able to update mutable parameter", func() {
-				testCaseID = 52
+			It("should be able to update mutable parameter", helpers.QaseLabel(qasecases.GKEP1UpdateMutableParameter), func() {
 				By("disabling the services", func() {
 					updateLoggingAndMonitoringServiceCheck(cluster, ctx.RancherAdminClient, "none", "none")
 				})
@@ -82,8 +90,7 @@ var _ = Describe("P1Import", func() {
 				})
 			})
 
-			It("should be able to update autoscaling", func() {
-				testCaseID = 53
+			It("should be able to update autoscaling", helpers.QaseLabel(qasecases.GKEP1UpdateAutoscaling53), func() {
 				By("enabling autoscaling", func() {
 					updateAutoScaling(cluster, ctx.RancherAdminClient, true)
 				})
@@ -92,12 +99,11 @@ var _ = Describe("P1Import", func() {
 				})
 			})
 
-			It("should be able to reimport a deleted cluster", func() {
-				testCaseID = 57
+			It("should be able to reimport a deleted cluster", helpers.QaseLabel(qasecases.GKEP1ReimportDeletedCluster), func() {
 				err := helper.DeleteGKEHostCluster(cluster, ctx.RancherAdminClient)
 				Expect(err).To(BeNil())
 				clusterID := cluster.ID
-				Eventually(func() error {
+				helpers.EventuallyWithBackoff(func() error {
 					_, err := ctx.RancherAdminClient.Management.Cluster.ByID(clusterID)
 					return err
 				}, "10s", "1s").ShouldNot(BeNil())
@@ -107,15 +113,13 @@ var _ = Describe("P1Import", func() {
 				Expect(err).To(BeNil())
 			})
 
-			It("should successfully add a windows nodepool", func() {
-				testCaseID = 54
+			It("should successfully add a windows nodepool", helpers.QaseLabel(qasecases.GKEP1AddWindowsNodepool54), func() {
 				var err error
 				_, err = helper.AddNodePool(cluster, ctx.RancherAdminClient, 1, "WINDOWS_LTSC_CONTAINERD", true, true)
 				Expect(err).To(BeNil())
 			})
 
-			It("updating a cluster to all windows nodepool should fail", func() {
-				testCaseID = 264
+			It("updating a cluster to all windows nodepool should fail", helpers.QaseLabel(qasecases.GKEP1UpdatingClusterAllWindowsNodepoolFail264), func() {
 				_, err := helper.UpdateCluster(cluster, ctx.RancherAdminClient, func(upgradedCluster *management.Cluster) {
 					updateNodePoolsList := *cluster.GKEConfig.NodePools
 					for i := 0; i < len(updateNodePoolsList); i++ {
@@ -128,8 +132,7 @@ var _ = Describe("P1Import", func() {
 				Expect(err.Error()).To(ContainSubstring("at least 1 Linux node pool is required"))
 			})
 
-			It("should be able to update combination mutable parameter", func() {
-				testCaseID = 56
+			It("should be able to update combination mutable parameter", helpers.QaseLabel(qasecases.GKEP1UpdateCombinationMutableParameter56), func() {
 				combinationMutableParameterUpdate(cluster, ctx.RancherAdminClient)
 			})
 
@@ -155,8 +158,7 @@ var _ = Describe("P1Import", func() {
 			Expect(err).To(BeNil())
 		})
 
-		It("for a given NodePool with a non-windows imageType, updating it to a windows imageType should fail", func() {
-			testCaseID = 55
+		It("for a given NodePool with a non-windows imageType, updating it to a windows imageType should fail", helpers.QaseLabel(qasecases.GKEP1ForGivenNodePoolWithNonWindowsImageTypeUpdating55), func() {
 			var err error
 			cluster, err = helper.UpdateCluster(cluster, ctx.RancherAdminClient, func(upgradedCluster *management.Cluster) {
 				updateNodePoolsList := *cluster.GKEConfig.NodePools
@@ -166,8 +168,9 @@ var _ = Describe("P1Import", func() {
 			})
 			Expect(err).To(BeNil())
 
-			Eventually(func() bool {
-				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			helpers.AllowClusterErrors()
+			helpers.EventuallyWithBackoff(func() bool {
+				cluster, err = helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 				Expect(err).To(BeNil())
 				return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "Node pools cannot be upgraded between Windows and non-Windows image families")
 			}, "30s", "2s").Should(BeTrue())
@@ -195,8 +198,7 @@ var _ = Describe("P1Import", func() {
 			Expect(err).To(BeNil())
 		})
 
-		It("should successfully update a cluster while it is still in updating state", func() {
-			testCaseID = 265
+		It("should successfully update a cluster while it is still in updating state", helpers.QaseLabel(qasecases.GKEP1UpdateClusterWhileItIsStillInUpdating265), func() {
 			updateClusterInUpdatingState(cluster, ctx.RancherAdminClient)
 		})
 
@@ -5,6 +5,7 @@ import (
 	"math/rand"
 	"net"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("P1Provisioning", func() {
@@ -31,7 +33,8 @@ var _ = Describe("P1Provisioning", func() {
 
 	AfterEach(func() {
 		if ctx.ClusterCleanup {
-			if cluster != nil && cluster.ID != "" {
+			// the recycled cluster is deleted at the end of the suite
+			if cluster != nil && cluster.ID != "" && !helpers.IsRecycledCluster(cluster) {
 				GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
 				err := helper.DeleteGKEHostCluster(cluster, ctx.RancherAdminClient)
 				Expect(err).To(BeNil())
@@ -43,26 +46,38 @@ var _ = Describe("P1Provisioning", func() {
 
 	Context("Provisioning a cluster with invalid config", func() {
 
-		It("should fail to provision a cluster when creating cluster with invalid name", func() {
-			testCaseID = 36
+		It("should surface the cloud error when the nodepools exceed the account quota", func() {
+			updateFunc := func(clusterConfig *gke.ClusterConfig) {
+				for i := range clusterConfig.NodePools {
+					clusterConfig.NodePools[i].InitialNodeCount = pointer.Int64(helpers.QuotaExceededNodeCount)
+				}
+			}
+			var err error
+			cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, updateFunc)
+			Expect(err).To(BeNil())
+			DeferCleanup(helpers.CleanupFailedCluster, ctx.RancherAdminClient, cluster, helper.DeleteGKEHostCluster, func() (bool, error) {
+				return helper.ClusterExistsOnGCloud(clusterName, project, zone)
+			})
+
+			cluster = helpers.WaitForQuotaError(ctx.RancherAdminClient, cluster, 10*time.Minute)
+		})
+
+		It("should fail to provision a cluster when creating cluster with invalid name", helpers.QaseLabel(qasecases.GKEP1FailProvisionClusterWhenCreatingClusterWithInvalid), func() {
 			var err error
 			cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, "@!invalid-gke-name-@#", ctx.CloudCredID, k8sVersion, zone, "", project, nil)
 			Expect(err).ToNot(BeNil())
 			Expect(err.Error()).To(ContainSubstring("InvalidFormat"))
 		})
 
-		It("User should not be able to add cluster with invalid GKE creds in Rancher", func() {
-			testCaseID = 2
+		It("User should not be able to add cluster with invalid GKE creds in Rancher", helpers.QaseLabel(qasecases.GKEP1UserNotAddClusterWithInvalidGKECreds), func() {
 			invalidCredCheck(cluster, ctx.RancherAdminClient)
 		})
 
-		It("User should not be able to add a cluster using an expired GKE creds", func() {
-			testCaseID = 6
+		It("User should not be able to add a cluster using an expired GKE creds", helpers.QaseLabel(qasecases.GKEP1UserNotAddClusterUsingExpiredGKECreds), func() {
 			expiredCredCheck(cluster, ctx.RancherAdminClient)
 		})
 
-		It("should fail to provision a cluster with invalid nodepool name", func() {
-			testCaseID = 37
+		It("should fail to provision a cluster with invalid nodepool name", helpers.QaseLabel(qasecases.GKEP1FailProvisionClusterWithInvalidNodepoolName), func() {
 
 			updateFunc := func(clusterConfig *gke.ClusterConfig) {
 				for _, np := range clusterConfig.NodePools {
@@ -74,16 +89,16 @@ var _ = Describe("P1Provisioning", func() {
 			cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, updateFunc)
 			Expect(err).To(BeNil())
 
-			Eventually(func() bool {
-				clusterState, err := ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			helpers.AllowClusterErrors()
+			helpers.EventuallyWithBackoff(func() bool {
+				clusterState, err := helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 				Expect(err).To(BeNil())
 				return clusterState.Transitioning == "error" && strings.Contains(clusterState.TransitioningMessage, "Invalid value for field \"node_pool.name\"")
 			}, "60s", "2s").Should(BeTrue())
 
 		})
 
-		It("should fail to provision a cluster nodepools is nil", func() {
-			testCaseID = 27
+		It("should fail to provision a cluster nodepools is nil", helpers.QaseLabel(qasecases.GKEP1FailProvisionClusterNodepoolsIsNil), func() {
 
 			updateFunc := func(clusterConfig *gke.ClusterConfig) {
 				clusterConfig.NodePools = nil
@@ -93,8 +108,9 @@ var _ = Describe("P1Provisioning", func() {
 			cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, updateFunc)
 			Expect(err).To(BeNil())
 
-			Eventually(func() bool {
-				clusterState, err := ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			helpers.AllowClusterErrors()
+			helpers.EventuallyWithBackoff(func() bool {
+				clusterState, err := helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 				Expect(err).To(BeNil())
 				return clusterState.Transitioning == "error" && strings.Contains(clusterState.TransitioningMessage, "Cluster.initial_node_count must be greater than zero")
 			}, "60s", "2s").Should(BeTrue())
@@ -113,14 +129,13 @@ var _ = Describe("P1Provisioning", func() {
 		})
 	})
 
-	It("deleting a cluster while it is in creation state should delete it from rancher and cloud console", func() {
-		testCaseID = 25
+	It("deleting a cluster while it is in creation state should delete it from rancher and cloud console", helpers.QaseLabel(qasecases.GKEP1DeletingClusterWhileItIsInCreationState), func() {
 		var err error
 		cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, nil)
 		Expect(err).To(BeNil())
 
 		// Wait for the cluster to appear on cloud console before deleting it
-		Eventually(func() bool {
+		helpers.EventuallyWithBackoff(func() bool {
 			exists, err := helper.ClusterExistsOnGCloud(clusterName, project, zone)
 			Expect(err).To(BeNil())
 			return exists
@@ -130,7 +145,7 @@ var _ = Describe("P1Provisioning", func() {
 		Expect(err).To(BeNil())
 
 		// Wait until the cluster finishes provisioning and then begins deletion process
-		Eventually(func() bool {
+		helpers.EventuallyWithBackoff(func() bool {
 			exists, err := helper.ClusterExistsOnGCloud(clusterName, project, zone)
 			Expect(err).To(BeNil())
 			return exists
@@ -142,11 +157,10 @@ var _ = Describe("P1Provisioning", func() {
 		Expect(err.Error()).To(ContainSubstring("not found"))
 	})
 
-	It("should be able to create a cluster with CP K8s version v-XX-1 and NP K8s version v-XX should use v-XX-1 for both CP and NP", func() {
+	It("should be able to create a cluster with CP K8s version v-XX-1 and NP K8s version v-XX should use v-XX-1 for both CP and NP", helpers.QaseLabel(qasecases.GKEP1CreateClusterWithCPK8sVersionVXX), func() {
 		if helpers.SkipUpgradeTests {
 			Skip(helpers.SkipUpgradeTestsLog)
 		}
-		testCaseID = 33
 
 		k8sVersions, err := helper.ListSingleVariantGKEAvailableVersions(ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "")
 		Expect(err).To(BeNil())
@@ -171,9 +185,9 @@ var _ = Describe("P1Provisioning", func() {
 		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
 		Expect(err).To(BeNil())
 
-		Eventually(func() bool {
+		helpers.EventuallyForOperation(helpers.OperationUpgrade, func() bool {
 			GinkgoLogr.Info("Waiting for the k8s upgrade to appear in GKEStatus.UpstreamSpec...")
-			clusterState, err := ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			clusterState, err := helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 			Expect(err).To(BeNil())
 			if *clusterState.GKEStatus.UpstreamSpec.KubernetesVersion != cpK8sVersion {
 				return false
@@ -185,7 +199,7 @@ var _ = Describe("P1Provisioning", func() {
 				}
 			}
 			return true
-		}, "5m", "5s").Should(BeTrue(), "Failed while waiting for k8s upgrade.")
+		}, "5m").Should(BeTrue(), "Failed while waiting for k8s upgrade.")
 	})
 
 	When("a cluster is created", func() {
@@ -198,14 +212,13 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(err).To(BeNil())
 		})
 
-		It("recreating a cluster while it is being deleted should recreate the cluster", func() {
-			testCaseID = 26
+		It("recreating a cluster while it is being deleted should recreate the cluster", helpers.QaseLabel(qasecases.GKEP1RecreatingClusterWhileItIsBeingDeletedRecreate), func() {
 
 			err := helper.DeleteGKEHostCluster(cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 
 			// Wait until the cluster begins deletion process before recreating
-			Eventually(func() bool {
+			helpers.EventuallyWithBackoff(func() bool {
 				exists, err := helper.ClusterExistsOnGCloud(clusterName, project, zone)
 				Expect(err).To(BeNil())
 				return exists
@@ -215,8 +228,9 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(err).To(BeNil())
 
 			// wait until the error is visible on the provisioned cluster
-			Eventually(func() bool {
-				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			helpers.AllowClusterErrors()
+			helpers.EventuallyWithBackoff(func() bool {
+				cluster, err = helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 				Expect(err).To(BeNil())
 				return cluster.State == "provisioning" && cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "a cluster in GKE exists with the same name")
 			}, "30s", "2s").Should(BeTrue())
@@ -225,8 +239,41 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(err).To(BeNil())
 		})
 
-		It("should be able to update mutable parameter loggingService and monitoringService", func() {
-			testCaseID = 28
+		It("should successfully add a windows nodepool", helpers.QaseLabel(qasecases.GKEP1AddWindowsNodepool30), func() {
+			var err error
+			if helpers.SkipUpgradeTests {
+				Skip(helpers.SkipUpgradeTestsLog)
+			}
+
+			_, err = helper.AddNodePool(cluster, ctx.RancherAdminClient, 1, "WINDOWS_LTSC_CONTAINERD", true, true)
+			Expect(err).To(BeNil())
+		})
+
+		It("should scale the nodepools to zero and back", func() {
+			scaleToZeroCheck(cluster, ctx.RancherAdminClient)
+		})
+	})
+
+	When("a cluster is created and only its config is updated", func() {
+
+		// these specs only update the config of the cluster, they share it with RECYCLE_CLUSTER
+		BeforeEach(func() {
+			var err error
+			cluster, err = helpers.RecycleCluster(ctx.RancherAdminClient, func() (*management.Cluster, error) {
+				cluster, err := helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, nil)
+				if err != nil {
+					return cluster, err
+				}
+				return helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			}, func(cluster *management.Cluster) error {
+				return helper.DeleteGKEHostCluster(cluster, ctx.RancherAdminClient)
+			})
+			Expect(err).To(BeNil())
+			// the recycled cluster is not the one named by the suite BeforeEach, for e.g. for the support bundle
+			clusterName = cluster.Name
+		})
+
+		It("should be able to update mutable parameter loggingService and monitoringService", helpers.QaseLabel(qasecases.GKEP1UpdateMutableParameterLoggingServiceAndMonitoringService), func() {
 			By("disabling the services", func() {
 				updateLoggingAndMonitoringServiceCheck(cluster, ctx.RancherAdminClient, "none", "none")
 			})
@@ -235,8 +282,7 @@ var _ = Describe("P1Provisioning", func() {
 			})
 		})
 
-		It("should be able to update autoscaling", func() {
-			testCaseID = 29
+		It("should be able to update autoscaling", helpers.QaseLabel(qasecases.GKEP1UpdateAutoscaling29), func() {
 			By("enabling autoscaling", func() {
 				updateAutoScaling(cluster, ctx.RancherAdminClient, true)
 			})
@@ -245,19 +291,7 @@ var _ = Describe("P1Provisioning", func() {
 			})
 		})
 
-		It("should successfully add a windows nodepool", func() {
-			testCaseID = 30
-			var err error
-			if helpers.SkipUpgradeTests {
-				Skip(helpers.SkipUpgradeTestsLog)
-			}
-
-			_, err = helper.AddNodePool(cluster, ctx.RancherAdminClient, 1, "WINDOWS_LTSC_CONTAINERD", true, true)
-			Expect(err).To(BeNil())
-		})
-
-		It("updating a cluster to all windows nodepool should fail", func() {
-			testCaseID = 263
+		It("updating a cluster to all windows nodepool should fail", helpers.QaseLabel(qasecases.GKEP1UpdatingClusterAllWindowsNodepoolFail263), func() {
 
 			_, err := helper.UpdateCluster(cluster, ctx.RancherAdminClient, func(upgradedCluster *management.Cluster) {
 				updateNodePoolsList := *cluster.GKEConfig.NodePools
@@ -271,13 +305,11 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(err.Error()).To(ContainSubstring("at least 1 Linux node pool is required"))
 		})
 
-		It("should be able to update combination mutable parameter", func() {
-			testCaseID = 31
+		It("should be able to update combination mutable parameter", helpers.QaseLabel(qasecases.GKEP1UpdateCombinationMutableParameter31), func() {
 			combinationMutableParameterUpdate(cluster, ctx.RancherAdminClient)
 		})
 
-		It("should successfully update with new cloud credentials", func() {
-			testCaseID = 5
+		It("should successfully update with new cloud credentials", helpers.QaseLabel(qasecases.GKEP1UpdateWithNewCloudCredentials), func() {
 			updateCloudCredentialsCheck(cluster, ctx.RancherAdminClient)
 		})
 	})
@@ -309,8 +341,7 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(err).To(BeNil())
 		})
 
-		It("for a given NodePool with a non-windows imageType, updating it to a windows imageType should fail", func() {
-			testCaseID = 34
+		It("for a given NodePool with a non-windows imageType, updating it to a windows imageType should fail", helpers.QaseLabel(qasecases.GKEP1ForGivenNodePoolWithNonWindowsImageTypeUpdating34), func() {
 			var err error
 			cluster, err = helper.UpdateCluster(cluster, ctx.RancherAdminClient, func(upgradedCluster *management.Cluster) {
 				updateNodePoolsList := *cluster.GKEConfig.NodePools
@@ -319,8 +350,9 @@ var _ = Describe("P1Provisioning", func() {
 				upgradedCluster.GKEConfig.NodePools = &updateNodePoolsList
 			})
 
-			Eventually(func() bool {
-				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			helpers.AllowClusterErrors()
+			helpers.EventuallyWithBackoff(func() bool {
+				cluster, err = helpers.WatchedCluster(ctx.RancherAdminClient, cluster.ID)
 				Expect(err).To(BeNil())
 				return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "Node pools cannot be upgraded between Windows and non-Windows image families")
 			}, "30s", "2s").Should(BeTrue())
@@ -345,8 +377,7 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(err).To(BeNil())
 		})
 
-		It("should successfully update a cluster while it is still in updating state", func() {
-			testCaseID = 35
+		It("should successfully update a cluster while it is still in updating state", helpers.QaseLabel(qasecases.GKEP1UpdateClusterWhileItIsStillInUpdating35), func() {
 			updateClusterInUpdatingState(cluster, ctx.RancherAdminClient)
 		})
 
@@ -390,15 +421,13 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(cluster.GKEStatus.UpstreamSpec.PrivateClusterConfig.EnablePrivateNodes).To(BeTrue())
 		})
 
-		It("should successfully create with public endpoint", func() {
-			testCaseID = 22
+		It("should successfully create with public endpoint", helpers.QaseLabel(qasecases.GKEP1CreateWithPublicEndpoint), func() {
 
 			cluster, err = helper.AddNodePool(cluster, ctx.RancherAdminClient, 1, "", true, true)
 			Expect(err).To(BeNil())
 		})
 
-		It("should successfully create with public endpoint and MasterAuthorizedNetworks", func() {
-			testCaseID = 24
+		It("should successfully create with public endpoint and MasterAuthorizedNetworks", helpers.QaseLabel(qasecases.GKEP1CreateWithPublicEndpointAndMasterAuthorizedNetworks), func() {
 
 			Expect(cluster.GKEConfig.MasterAuthorizedNetworksConfig.Enabled).To(BeTrue())
 			Expect(cluster.GKEStatus.UpstreamSpec.MasterAuthorizedNetworksConfig.Enabled).To(BeTrue())
@@ -24,7 +24,6 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
-	. "github.com/rancher-sandbox/qase-ginkgo"
 	"github.com/rancher/shepherd/clients/rancher"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
 	"github.com/rancher/shepherd/extensions/cloudcredentials"
@@ -41,37 +40,49 @@ var (
 	ctx                     helpers.RancherContext
 	cluster                 *management.Cluster
 	clusterName, k8sVersion string
-	testCaseID              int64
 	zone                    = helpers.GetGKEZone()
 	project                 = helpers.GetGKEProjectID()
 )
 
 func TestP1(t *testing.T) {
 	RegisterFailHandler(Fail)
-	RunSpecs(t, "P1 Suite")
+	RunSpecs(t, "P1 Suite", helpers.SuiteConfig(t))
 }
 
-var _ = SynchronizedBeforeSuite(func() []byte {
-	helpers.CommonSynchronizedBeforeSuite()
-	return nil
-}, func() {
-	ctx = helpers.CommonBeforeSuite()
+// The Rancher setup and the cloud credential are shared by the parallel processes; every process provisions its own clusters
+var _ = SynchronizedBeforeSuite(helpers.ParallelSynchronizedBeforeSuite, func(setup []byte) {
+	ctx = helpers.ParallelBeforeSuite(setup)
 })
 
 var _ = BeforeEach(func() {
+	helpers.CollectSpecArtifacts()
+	helpers.RecordClusterTransitions(ctx.RancherAdminClient)
+
 	// Setting this to nil ensures we do not use the `cluster` variable value from another test running in parallel with this one.
 	cluster = nil
-	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+	clusterName = helpers.GenerateClusterName(helpers.ClusterNamePrefix)
 })
 
-var _ = ReportBeforeEach(func(report SpecReport) {
-	// Reset case ID
-	testCaseID = -1
+var _ = JustAfterEach(func() {
+	// Collect the support bundle before AfterEach deletes the cluster
+	helpers.CollectSupportBundleOnFailure(clusterName)
 })
 
+// Delete the cluster shared by the specs with RECYCLE_CLUSTER, if any, then the clusters leaked by all the processes
+var _ = SynchronizedAfterSuite(helpers.DeleteRecycledCluster, helpers.CommonSynchronizedAfterSuite)
+
 var _ = ReportAfterEach(func(report SpecReport) {
 	// Add result in Qase if asked
-	Qase(testCaseID, report)
+	helpers.ReportToQase(report)
+	// Upload the artifacts of the failed spec to ARTIFACTS_BUCKET if set
+	helpers.UploadArtifactsOnFailure(report)
+})
+
+var _ = ReportAfterSuite("Reports", func(report Report) {
+	// Write the JSON, JUnit and summary reports to the artifacts directory
+	helpers.GenerateSuiteReports(report)
+	// Submit the Qase results of all the parallel processes
+	helpers.SubmitQaseResults()
 })
 
 // updateLoggingAndMonitoringServiceCheck tests updating `loggingService` and `monitoringService`
@@ -103,14 +114,16 @@ func syncK8sVersionUpgradeCheck(cluster *management.Cluster, client *rancher.Cli
 	By("upgrading control plane", func() {
 		currentVersion := cluster.GKEConfig.KubernetesVersion
 
+		// the cluster is in error once its version is changed on GKE, until it is synced to Rancher
+		helpers.AllowClusterErrors()
 		err = helper.UpgradeGKEClusterOnGCloud(zone, clusterName, project, upgradeToVersion, false, "")
 		Expect(err).To(BeNil())
 		// The cluster errors out and becomes unavailable at some point due to the upgrade , so we wait until the cluster is ready
 		cluster, err = helpers.WaitUntilClusterIsReady(cluster, client)
 		Expect(err).To(BeNil())
-		Eventually(func() string {
+		helpers.EventuallyWithBackoff(func() string {
 			GinkgoLogr.Info("Waiting for k8s upgrade to appear in GKEStatus.UpstreamSpec ...")
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			return *cluster.GKEStatus.UpstreamSpec.KubernetesVersion
 		}, tools.SetTimeout(10*time.Minute), 10*time.Second).Should(Equal(upgradeToVersion), "Failed while waiting for k8s upgrade to appear in GKEStatus.UpstreamSpec")
@@ -140,10 +153,10 @@ func syncK8sVersionUpgradeCheck(cluster *management.Cluster, client *rancher.Cli
 			_ = helper.UpgradeGKEClusterOnGCloud(zone, clusterName, project, upgradeToVersion, true, *np.Name)
 		}
 
-		Eventually(func() bool {
+		helpers.EventuallyWithBackoff(func() bool {
 			GinkgoLogr.Info("Waiting for the nodepool upgrade to appear in GKEStatus.UpstreamSpec ...")
 
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			for _, np := range *cluster.GKEStatus.UpstreamSpec.NodePools {
 				if *np.Version != upgradeToVersion {
@@ -172,10 +185,10 @@ func syncNodepoolsCheck(cluster *management.Cluster, client *rancher.Client) {
 		Expect(err).To(BeNil())
 
 		// The cluster does not go into updating state, so we simply wait until the number of nodepools increases
-		Eventually(func() int {
+		helpers.EventuallyWithBackoff(func() int {
 			GinkgoLogr.Info("Waiting for the total nodepool count to increase in GKEStatus.UpstreamSpec ...")
 
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			return len(*cluster.GKEStatus.UpstreamSpec.NodePools)
 		}, tools.SetTimeout(10*time.Minute), 15*time.Second).Should(Equal(currentNodeCount + 1))
@@ -211,8 +224,8 @@ func syncNodepoolsCheck(cluster *management.Cluster, client *rancher.Client) {
 		Expect(err).To(BeNil())
 
 		// The cluster does not go into updating state, so we simply wait until the number of nodepools decreases
-		Eventually(func() int {
-			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		helpers.EventuallyWithBackoff(func() int {
+			cluster, err = helpers.WatchedCluster(client, cluster.ID)
 			Expect(err).To(BeNil())
 			return len(*cluster.GKEStatus.UpstreamSpec.NodePools)
 		}, tools.SetTimeout(15*time.Minute), 15*time.Second).Should(Equal(currentNodeCount))
@@ -266,12 +279,12 @@ func updateClusterInUpdatingState(cluster *management.Cluster, client *rancher.C
 
 	Expect(len(*cluster.GKEConfig.NodePools)).Should(BeNumerically("==", currentNodePoolCount+1))
 
-	err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+	err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 	Expect(err).To(BeNil())
 
-	Eventually(func() bool {
+	helpers.EventuallyWithBackoff(func() bool {
 		GinkgoLogr.Info("Waiting for the changes to appear in GKEStatus.UpstreamSpec ...")
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).To(BeNil())
 		return len(*cluster.GKEStatus.UpstreamSpec.NodePools) == currentNodePoolCount+1 && *cluster.GKEStatus.UpstreamSpec.KubernetesVersion == upgradeK8sVersion
 	}, "5m", "5s").Should(BeTrue())
@@ -320,13 +333,13 @@ func combinationMutableParameterUpdate(cluster *management.Cluster, client *ranc
 		Expect(np.Autoscaling.MinNodeCount).Should(Equal(minCount))
 	}
 
-	err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+	err = helpers.WaitClusterToBeUpgraded(client, cluster.ID)
 	Expect(err).To(BeNil())
 
-	Eventually(func() bool {
+	helpers.EventuallyWithBackoff(func() bool {
 		GinkgoLogr.Info("Waiting for the combination changes to appear in GKEStatus.UpstreamSpec...")
 		var clusterState *management.Cluster
-		clusterState, err = client.Management.Cluster.ByID(cluster.ID)
+		clusterState, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).To(BeNil())
 		if !(*clusterState.GKEStatus.UpstreamSpec.LoggingService == disableService && *clusterState.GKEStatus.UpstreamSpec.MonitoringService == disableService) {
 			return false
@@ -350,8 +363,8 @@ func updateCloudCredentialsCheck(cluster *management.Cluster, client *rancher.Cl
 	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
 	Expect(err).To(BeNil())
 	Expect(cluster.GKEConfig.GoogleCredentialSecret).To(Equal(newCCID))
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+	helpers.EventuallyWithBackoff(func() bool {
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).NotTo(HaveOccurred())
 		return cluster.GKEStatus.UpstreamSpec.GoogleCredentialSecret == newCCID
 	}, "5m", "5s").Should(BeTrue(), "Failed while upstream cloud credentials update")
@@ -367,10 +380,18 @@ func upgradeK8sVersionChecks(cluster *management.Cluster, client *rancher.Client
 	upgradeToVersion := versions[0]
 	GinkgoLogr.Info(fmt.Sprintf("Upgrading cluster to GKE version %s", upgradeToVersion))
 
-	By("upgrading the ControlPlane & Nodepools", func() {
-		cluster, err = helper.UpgradeKubernetesVersion(cluster, upgradeToVersion, client, true, true, true)
+	By("upgrading the ControlPlane & Nodepools in a single update", func() {
+		// the upgrade is not waited for by the helper, so that its ordering can be checked while it runs
+		cluster, err = helper.UpgradeKubernetesVersion(cluster, upgradeToVersion, client, true, false, false)
 		Expect(err).To(BeNil())
+		Expect(*cluster.GKEConfig.KubernetesVersion).To(Equal(upgradeToVersion))
+		for _, np := range *cluster.GKEConfig.NodePools {
+			Expect(*np.Version).To(Equal(upgradeToVersion))
+		}
 	})
+
+	cluster = helpers.WaitForOrderedUpgrade(client, cluster, upgradeToVersion)
+	helpers.ClusterIsReadyChecks(cluster, client, cluster.Name)
 }
 
 // Automates Qase 2 and 306
@@ -391,8 +412,9 @@ func invalidCredCheck(cluster *management.Cluster, client *rancher.Client) {
 		Expect(err).To(BeNil())
 	}
 
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+	helpers.AllowClusterErrors()
+	helpers.EventuallyWithBackoff(func() bool {
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).To(BeNil())
 		return cluster.Transitioning == "error"
 	}, "2m", "3s").Should(BeTrue())
@@ -426,9 +448,36 @@ func expiredCredCheck(cluster *management.Cluster, client *rancher.Client) {
 		Expect(err).To(BeNil())
 	}
 
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+	helpers.AllowClusterErrors()
+	helpers.EventuallyWithBackoff(func() bool {
+		cluster, err = helpers.WatchedCluster(client, cluster.ID)
 		Expect(err).To(BeNil())
 		return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "cannot fetch token") || strings.Contains(cluster.TransitioningMessage, "unexpected end of JSON input")
 	}, "2m", "3s").Should(BeTrue())
 }
+
+// scaleToZeroCheck hibernates the cluster by scaling all the nodepools to zero, checks that Rancher handles the cluster left without node,
+// then scales them back and checks that Rancher resumes the management of the cluster
+func scaleToZeroCheck(cluster *management.Cluster, client *rancher.Client) {
+	initialNodeCount := *(*cluster.GKEConfig.NodePools)[0].InitialNodeCount
+
+	hibernated := time.Now()
+	By("scaling the nodepools to zero", func() {
+		start := hibernated
+		var err error
+		// the cluster can not become active without node, so the update is not waited for
+		cluster, err = helper.ScaleNodePool(cluster, client, 0, false, true)
+		Expect(err).To(BeNil())
+		cluster = helpers.CheckZeroNodeCluster(client, cluster, start)
+	})
+
+	By("scaling the nodepools back up", func() {
+		start := time.Now()
+		var err error
+		cluster, err = helper.ScaleNodePool(cluster, client, initialNodeCount, false, true)
+		Expect(err).To(BeNil())
+		cluster = helpers.CheckClusterResumed(client, cluster, hibernated)
+		helpers.WaitUntilNodeCount(client, cluster, int(initialNodeCount)*len(*cluster.GKEConfig.NodePools), start)
+	})
+	helpers.ClusterIsReadyChecks(cluster, client, cluster.Name)
+}
@@ -10,23 +10,24 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("SyncImport", func() {
 	for _, testData := range []struct {
-		qaseID    int64
+		qaseID    qasecases.CaseID
 		isUpgrade bool
 		testBody  func(cluster *management.Cluster, client *rancher.Client)
 		testTitle string
 	}{
 		{
-			qaseID:    58,
+			qaseID:    qasecases.GKEP1SyncFromGCERancherChangedK8sVersion58,
 			isUpgrade: true,
 			testBody:  syncK8sVersionUpgradeCheck,
 			testTitle: "should Sync from GCE to Rancher - changed k8s version",
 		},
 		{
-			qaseID:    61,
+			qaseID:    qasecases.GKEP1SyncFromGCERancherAddDeleteNodepool61,
 			isUpgrade: false,
 			testBody:  syncNodepoolsCheck,
 			testTitle: "should Sync from GCE to Rancher - add/delete nodepool",
@@ -61,8 +62,7 @@ var _ = Describe("SyncImport", func() {
 				}
 			})
 
-			It(testData.testTitle, func() {
-				testCaseID = testData.qaseID
+			It(testData.testTitle, helpers.QaseLabel(testData.qaseID), func() {
 				testData.testBody(cluster, ctx.RancherAdminClient)
 			})
 		})
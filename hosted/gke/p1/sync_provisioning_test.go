@@ -10,23 +10,24 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("SyncProvisioning", func() {
 	for _, testData := range []struct {
-		qaseID    int64
+		qaseID    qasecases.CaseID
 		isUpgrade bool
 		testBody  func(cluster *management.Cluster, client *rancher.Client)
 		testTitle string
 	}{
 		{
-			qaseID:    39,
+			qaseID:    qasecases.GKEP1SyncFromGCERancherChangedK8sVersion39,
 			isUpgrade: true,
 			testBody:  syncK8sVersionUpgradeCheck,
 			testTitle: "should Sync from GCE to Rancher - changed k8s version",
 		},
 		{
-			qaseID:    40,
+			qaseID:    qasecases.GKEP1SyncFromGCERancherAddDeleteNodepool40,
 			isUpgrade: false,
 			testBody:  syncNodepoolsCheck,
 			testTitle: "should Sync from GCE to Rancher - add/delete nodepool",
@@ -57,8 +58,7 @@ var _ = Describe("SyncProvisioning", func() {
 				}
 			})
 
-			It(testData.testTitle, func() {
-				testCaseID = testData.qaseID
+			It(testData.testTitle, helpers.QaseLabel(testData.qaseID), func() {
 				testData.testBody(cluster, ctx.RancherAdminClient)
 			})
 		})
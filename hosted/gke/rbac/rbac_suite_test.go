@@ -0,0 +1,133 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var (
+	clusterName string
+	ctx         helpers.RancherContext
+	cluster     *management.Cluster
+	project     = helpers.GetGKEProjectID()
+	zone        = helpers.GetGKEZone()
+)
+
+func TestRBAC(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RBAC Suite", helpers.SuiteConfig(t))
+}
+
+// The Rancher setup and the cloud credential are shared by the parallel processes
+var _ = SynchronizedBeforeSuite(helpers.ParallelSynchronizedBeforeSuite, func(setup []byte) {
+	ctx = helpers.ParallelBeforeSuite(setup)
+	// the clusters are owned by the std user, which creates them with its own cloud credentials
+	helpers.CreateStdUserClient(&ctx)
+})
+
+var _ = BeforeEach(func() {
+	helpers.CollectSpecArtifacts()
+	helpers.RecordClusterTransitions(ctx.RancherAdminClient)
+
+	cluster = nil
+	clusterName = helpers.GenerateClusterName(helpers.ClusterNamePrefix)
+})
+
+var _ = JustAfterEach(func() {
+	// Collect the support bundle before AfterEach deletes the cluster
+	helpers.CollectSupportBundleOnFailure(clusterName)
+})
+
+var _ = AfterEach(func() {
+	if ctx.ClusterCleanup && cluster != nil {
+		err := helper.DeleteGKEHostCluster(cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+	} else {
+		fmt.Println("Skipping downstream cluster deletion: ", clusterName)
+	}
+})
+
+// Delete the clusters leaked by all the processes once they are done
+var _ = SynchronizedAfterSuite(func() {}, helpers.CommonSynchronizedAfterSuite)
+
+var _ = ReportAfterEach(func(report SpecReport) {
+	// Add result in Qase if asked
+	helpers.ReportToQase(report)
+	// Upload the artifacts of the failed spec to ARTIFACTS_BUCKET if set
+	helpers.UploadArtifactsOnFailure(report)
+})
+
+var _ = ReportAfterSuite("Reports", func(report Report) {
+	// Write the JSON, JUnit and summary reports to the artifacts directory
+	helpers.GenerateSuiteReports(report)
+	// Submit the Qase results of all the parallel processes
+	helpers.SubmitQaseResults()
+})
+
+// clusterRolesLifecycleCheck provisions a cluster as a std user, which makes it the cluster owner, checks that a cluster member can neither
+// scale, upgrade nor delete the cluster, then scales, upgrades and deletes it as the owner
+func clusterRolesLifecycleCheck(k8sVersion, upgradeToVersion string) {
+	ownerClient := ctx.StdUserClient
+	var err error
+
+	By("provisioning the cluster as the cluster owner", func() {
+		cluster, err = helper.CreateGKEHostedCluster(ownerClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, nil)
+		Expect(err).To(BeNil())
+		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ownerClient)
+		Expect(err).To(BeNil())
+	})
+	helpers.ClusterIsReadyChecks(cluster, ownerClient, clusterName)
+
+	_, memberClient := helpers.NewStdUserClient(ctx.RancherAdminClient)
+	helpers.AddClusterMember(ctx.RancherAdminClient, cluster, memberClient, helpers.ClusterMemberRole)
+	helpers.CheckClusterUpdateDenied(ctx.RancherAdminClient, memberClient, cluster.ID,
+		func(cluster *management.Cluster) {
+			nodePools := *cluster.GKEConfig.NodePools
+			*nodePools[0].InitialNodeCount++
+		},
+		func(cluster *management.Cluster) {
+			cluster.GKEConfig.KubernetesVersion = &upgradeToVersion
+		},
+	)
+
+	By("scaling up the nodepool as the cluster owner", func() {
+		initialNodeCount := *(*cluster.GKEConfig.NodePools)[0].InitialNodeCount
+		cluster, err = helper.ScaleNodePool(cluster, ownerClient, initialNodeCount+1, true, true)
+		Expect(err).To(BeNil())
+	})
+
+	if upgradeToVersion != k8sVersion {
+		By("upgrading the cluster as the cluster owner", func() {
+			cluster, err = helper.UpgradeKubernetesVersion(cluster, upgradeToVersion, ownerClient, false, true, true)
+			Expect(err).To(BeNil())
+		})
+	}
+
+	By("deleting the cluster as the cluster owner", func() {
+		Expect(helper.DeleteGKEHostCluster(cluster, ownerClient)).To(Succeed())
+		helpers.WaitUntilClusterIsRemoved(ctx.RancherAdminClient, cluster.ID)
+		// marking as nil so that AfterEach does not delete it again
+		cluster = nil
+	})
+}
@@ -0,0 +1,54 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("NodeScheduling", func() {
+	BeforeEach(func() {
+		k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "", false)
+		Expect(err).To(BeNil())
+		GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s", k8sVersion, clusterName))
+
+		cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, nil)
+		Expect(err).To(BeNil())
+		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+	})
+
+	It("should schedule pods according to the nodepool labels and taints set through Rancher", func() {
+		taint := helpers.SchedulingTaint()
+
+		var err error
+		By("adding a nodepool with labels and taints", func() {
+			// the taint effects of GKE are in upper snake case, for e.g. NO_SCHEDULE
+			taints := []management.GKENodeTaintConfig{{Key: taint.Key, Value: taint.Value, Effect: "NO_SCHEDULE"}}
+			cluster, err = helper.AddNodePoolWithMetadata(cluster, ctx.RancherAdminClient, helpers.SchedulingLabels(), taints, true, true)
+			Expect(err).To(BeNil())
+		})
+
+		helpers.CheckNodeScheduling(ctx.RancherAdminClient, cluster, helpers.SchedulingLabels(), &taint)
+		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+	})
+})
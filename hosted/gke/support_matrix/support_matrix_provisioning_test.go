@@ -21,10 +21,10 @@ import (
 	"fmt"
 
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
 )
 
 var _ = Describe("SupportMatrixProvisioning", func() {
@@ -38,7 +38,7 @@ var _ = Describe("SupportMatrixProvisioning", func() {
 				cluster     *management.Cluster
 			)
 			BeforeEach(func() {
-				clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+				clusterName = helpers.GenerateClusterName(helpers.ClusterNamePrefix)
 				var err error
 				cluster, err = helper.CreateGKEHostedCluster(ctx.StdUserClient, clusterName, ctx.CloudCredID, version, zone, "", project, nil)
 				Expect(err).To(BeNil())
@@ -46,6 +46,9 @@ var _ = Describe("SupportMatrixProvisioning", func() {
 				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
 				Expect(err).To(BeNil())
 			})
+			JustAfterEach(func() {
+				helpers.CollectSupportBundleOnFailure(clusterName)
+			})
 			AfterEach(func() {
 				if ctx.ClusterCleanup && cluster != nil {
 					err := helper.DeleteGKEHostCluster(cluster, ctx.StdUserClient)
@@ -54,9 +57,7 @@ var _ = Describe("SupportMatrixProvisioning", func() {
 					fmt.Println("Skipping downstream cluster deletion: ", clusterName)
 				}
 			})
-			It("should successfully provision the cluster", func() {
-				// Report to Qase
-				testCaseID = 12
+			It("should successfully provision the cluster", helpers.QaseLabel(qasecases.GKESupportMatrixProvisionCluster), func() {
 
 				helpers.ClusterIsReadyChecks(cluster, ctx.StdUserClient, clusterName)
 			})
@@ -17,7 +17,6 @@ package support_matrix_test
 import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	. "github.com/rancher-sandbox/qase-ginkgo"
 
 	"testing"
 
@@ -27,7 +26,6 @@ import (
 
 var (
 	availableVersionList []string
-	testCaseID           int64
 	ctx                  helpers.RancherContext
 	project              = helpers.GetGKEProjectID()
 	zone                 = helpers.GetGKEZone()
@@ -41,15 +39,22 @@ func TestSupportMatrix(t *testing.T) {
 	var err error
 	availableVersionList, err = helper.ListSingleVariantGKEAvailableVersions(ctx.StdUserClient, project, ctx.CloudCredID, zone, "")
 	Expect(err).To(BeNil())
-	RunSpecs(t, "SupportMatrix Suite")
+	RunSpecs(t, "SupportMatrix Suite", helpers.SuiteConfig(t))
 }
 
-var _ = ReportBeforeEach(func(report SpecReport) {
-	// Reset case ID
-	testCaseID = -1
-})
+// Delete the clusters leaked by all the processes once they are done
+var _ = SynchronizedAfterSuite(func() {}, helpers.CommonSynchronizedAfterSuite)
 
 var _ = ReportAfterEach(func(report SpecReport) {
 	// Add result in Qase if asked
-	Qase(testCaseID, report)
+	helpers.ReportToQase(report)
+	// Upload the artifacts of the failed spec to ARTIFACTS_BUCKET if set
+	helpers.UploadArtifactsOnFailure(report)
+})
+
+var _ = ReportAfterSuite("Reports", func(report Report) {
+	// Write the JSON, JUnit and summary reports to the artifacts directory
+	helpers.GenerateSuiteReports(report)
+	// Submit the Qase results of all the parallel processes
+	helpers.SubmitQaseResults()
 })
@@ -0,0 +1,74 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version_skew_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("VersionSkew", func() {
+	var versions []string
+
+	BeforeEach(func() {
+		if helpers.SkipUpgradeTests {
+			Skip(helpers.SkipUpgradeTestsLog)
+		}
+		var err error
+		versions, err = helper.ListSingleVariantGKEAvailableVersions(ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "")
+		Expect(err).To(BeNil())
+	})
+
+	for _, skew := range helpers.VersionSkews("gke") {
+		It(fmt.Sprintf("should handle a %s", skew.Description), func() {
+			base, steps, err := helpers.VersionSkewPath(versions, skew.NodeMinorOffset)
+			if err != nil {
+				Skip(err.Error())
+			}
+			GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s, then %v", base, clusterName, steps))
+
+			cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, base, zone, "", project, nil)
+			Expect(err).To(BeNil())
+			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+
+			// the last step is only submitted, its outcome is checked by CheckVersionSkew
+			last := steps[len(steps)-1]
+			if skew.NodeMinorOffset > 0 {
+				By(fmt.Sprintf("upgrading the nodepools only to %s", last), func() {
+					cluster, err = helper.UpgradeNodeKubernetesVersion(cluster, last, ctx.RancherAdminClient, false, false)
+					Expect(err).To(BeNil())
+				})
+			} else {
+				By(fmt.Sprintf("upgrading the control plane only to %v", steps), func() {
+					for _, version := range steps[:len(steps)-1] {
+						cluster, err = helper.UpgradeKubernetesVersion(cluster, version, ctx.RancherAdminClient, false, true, true)
+						Expect(err).To(BeNil())
+						cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+						Expect(err).To(BeNil())
+					}
+					cluster, err = helper.UpgradeKubernetesVersion(cluster, last, ctx.RancherAdminClient, false, false, false)
+					Expect(err).To(BeNil())
+				})
+			}
+			cluster = helpers.CheckVersionSkew(ctx.RancherAdminClient, cluster, skew)
+		})
+	}
+})
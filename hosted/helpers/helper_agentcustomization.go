@@ -0,0 +1,175 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Pods of the cluster agent deployed by Rancher on the downstream clusters
+const (
+	clusterAgentSelector  = "app=cattle-cluster-agent"
+	clusterAgentContainer = "cluster-register"
+)
+
+// ClusterAgentCustomization returns a cluster agent customization with a toleration, a node affinity and resource requirements
+// which can be scheduled on any linux node; requestsCPU makes the customizations distinguishable, for e.g. to change it once applied.
+func ClusterAgentCustomization(requestsCPU string) *management.AgentDeploymentCustomization {
+	return &management.AgentDeploymentCustomization{
+		AppendTolerations: []management.Toleration{
+			{Key: "hosted-providers-e2e/agent", Operator: "Exists", Effect: "NoSchedule"},
+		},
+		OverrideAffinity: &management.Affinity{
+			NodeAffinity: &management.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &management.NodeSelector{
+					NodeSelectorTerms: []management.NodeSelectorTerm{{
+						MatchExpressions: []management.NodeSelectorRequirement{
+							{Key: "kubernetes.io/os", Operator: "In", Values: []string{"linux"}},
+						},
+					}},
+				},
+			},
+		},
+		OverrideResourceRequirements: &management.ResourceRequirements{
+			Requests: map[string]string{"cpu": requestsCPU, "memory": "128Mi"},
+			Limits:   map[string]string{"memory": "512Mi"},
+		},
+	}
+}
+
+// convertManagementType converts a type of the management API to its kubernetes equivalent, for e.g. management.Affinity to corev1.Affinity
+func convertManagementType(from, to any) error {
+	content, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, to)
+}
+
+// clusterAgentCustomizationProblems returns the differences between a cluster agent customization and the spec of a cluster agent pod:
+// the tolerations must have been appended to the default ones, and the affinity and the resource requirements overridden.
+func clusterAgentCustomizationProblems(customization *management.AgentDeploymentCustomization, pod *corev1.Pod) (problems []string) {
+	for _, toleration := range customization.AppendTolerations {
+		expected := corev1.Toleration{}
+		if err := convertManagementType(toleration, &expected); err != nil {
+			return []string{err.Error()}
+		}
+		found := false
+		for _, podToleration := range pod.Spec.Tolerations {
+			found = found || equality.Semantic.DeepEqual(expected, podToleration)
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("pod %s does not tolerate %s", pod.Name, toleration.Key))
+		}
+	}
+
+	if customization.OverrideAffinity != nil {
+		expected := &corev1.Affinity{}
+		if err := convertManagementType(customization.OverrideAffinity, expected); err != nil {
+			return []string{err.Error()}
+		}
+		if !equality.Semantic.DeepEqual(expected, pod.Spec.Affinity) {
+			problems = append(problems, fmt.Sprintf("the affinity of pod %s is not overridden", pod.Name))
+		}
+	}
+
+	if requirements := customization.OverrideResourceRequirements; requirements != nil {
+		var container *corev1.Container
+		for i := range pod.Spec.Containers {
+			if pod.Spec.Containers[i].Name == clusterAgentContainer {
+				container = &pod.Spec.Containers[i]
+			}
+		}
+		if container == nil {
+			return append(problems, fmt.Sprintf("pod %s has no %s container", pod.Name, clusterAgentContainer))
+		}
+		for kind, quantities := range map[string]map[string]string{"requests": requirements.Requests, "limits": requirements.Limits} {
+			actual := container.Resources.Requests
+			if kind == "limits" {
+				actual = container.Resources.Limits
+			}
+			for name, value := range quantities {
+				want, err := resource.ParseQuantity(value)
+				if err != nil {
+					return append(problems, err.Error())
+				}
+				got, found := actual[corev1.ResourceName(name)]
+				if !found || got.Cmp(want) != 0 {
+					problems = append(problems, fmt.Sprintf("the %s %s of pod %s is %s instead of %s", name, kind, pod.Name, got.String(), value))
+				}
+			}
+		}
+	}
+	return problems
+}
+
+/*
+Set the cluster agent customization of a downstream cluster; Rancher redeploys the cluster agent with it, or deploys it with it
+if the cluster is still provisioning.
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @param customization Cluster agent customization, for e.g. from ClusterAgentCustomization
+  - @returns The updated cluster; the function will fail through Ginkgo in case of issue
+*/
+func SetClusterAgentCustomization(client *rancher.Client, cluster *management.Cluster, customization *management.AgentDeploymentCustomization) *management.Cluster {
+	var updatedCluster *management.Cluster
+	ginkgo.By(fmt.Sprintf("Setting the cluster agent customization of cluster %s", cluster.Name), func() {
+		var err error
+		updatedCluster, err = UpdateRancherCluster(client, cluster, map[string]any{
+			management.ClusterFieldClusterAgentDeploymentCustomization: customization,
+		})
+		Expect(err).To(BeNil())
+	})
+	return updatedCluster
+}
+
+/*
+Check that the cluster agent pods of a downstream cluster reflect a cluster agent customization; the pods being terminated are ignored,
+the cluster agent may be redeployed when the check starts.
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @param customization Cluster agent customization set with SetClusterAgentCustomization
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckClusterAgentCustomization(client *rancher.Client, cluster *management.Cluster, customization *management.AgentDeploymentCustomization) {
+	steveClient, err := client.Steve.ProxyDownstream(cluster.ID)
+	Expect(err).To(BeNil())
+
+	ginkgo.By(fmt.Sprintf("Checking the cluster agent customization on cluster %s", cluster.Name), func() {
+		EventuallyWithBackoff(func() ([]string, error) {
+			collection, err := steveClient.SteveType("pod").NamespacedSteveClient(CattleSystemNS).List(map[string][]string{"labelSelector": {clusterAgentSelector}})
+			if err != nil {
+				return nil, err
+			}
+			var problems []string
+			running := 0
+			for _, object := range collection.Data {
+				pod := &corev1.Pod{}
+				if err = steveV1.ConvertToK8sType(object.JSONResp, pod); err != nil {
+					return nil, err
+				}
+				if pod.DeletionTimestamp != nil {
+					continue
+				}
+				if pod.Status.Phase == corev1.PodRunning {
+					running++
+				}
+				problems = append(problems, clusterAgentCustomizationProblems(customization, pod)...)
+			}
+			if running == 0 {
+				problems = append(problems, "no cluster agent pod is running")
+			}
+			return problems, nil
+		}, tools.SetTimeout(10*time.Minute), 15*time.Second).Should(BeEmpty(), fmt.Sprintf("The cluster agent of cluster %s does not reflect its customization", cluster.Name))
+	})
+}
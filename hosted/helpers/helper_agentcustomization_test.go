@@ -0,0 +1,50 @@
+package helpers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestClusterAgentCustomizationProblems(t *testing.T) {
+	customization := ClusterAgentCustomization("100m")
+	newPod := func() *corev1.Pod {
+		return &corev1.Pod{
+			Spec: corev1.PodSpec{
+				Tolerations: []corev1.Toleration{
+					{Key: "node-role.kubernetes.io/control-plane", Effect: corev1.TaintEffectNoSchedule},
+					{Key: "hosted-providers-e2e/agent", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+				},
+				Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "kubernetes.io/os", Operator: corev1.NodeSelectorOpIn, Values: []string{"linux"}}},
+					}}},
+				}},
+				Containers: []corev1.Container{{
+					Name: clusterAgentContainer,
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("0.1"), corev1.ResourceMemory: resource.MustParse("128Mi")},
+						Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+					},
+				}},
+			},
+		}
+	}
+
+	if problems := clusterAgentCustomizationProblems(customization, newPod()); len(problems) != 0 {
+		t.Errorf("clusterAgentCustomizationProblems() = %v, want none", problems)
+	}
+
+	pod := newPod()
+	pod.Spec.Tolerations = pod.Spec.Tolerations[:1]
+	pod.Spec.Affinity = nil
+	pod.Spec.Containers[0].Resources.Limits = nil
+	if problems := clusterAgentCustomizationProblems(customization, pod); len(problems) != 3 {
+		t.Errorf("clusterAgentCustomizationProblems() with the default agent = %v, want 3 problems", problems)
+	}
+
+	if problems := clusterAgentCustomizationProblems(ClusterAgentCustomization("200m"), newPod()); len(problems) != 1 {
+		t.Errorf("clusterAgentCustomizationProblems() with the previous customization = %v, want 1 problem", problems)
+	}
+}
@@ -0,0 +1,196 @@
+package helpers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+	"github.com/rancher/shepherd/clients/rancher"
+	"github.com/rancher/shepherd/clients/rancher/catalog"
+	v1 "github.com/rancher/shepherd/clients/rancher/v1"
+	"github.com/rancher/shepherd/extensions/workloads/pods"
+)
+
+// system-catalog Setting value making rancher use the charts bundled in its image instead of the chart repositories
+const (
+	systemCatalogSetting = "system-catalog"
+	bundledSystemCatalog = "bundled"
+)
+
+// airgapNamespaces are the downstream namespaces whose pods must only use images from the private registry
+var airgapNamespaces = []string{CattleSystemNS, "cattle-fleet-system"}
+
+// IsAirgap returns true if the tests run in airgap mode, i.e. PRIVATE_REGISTRY is set
+func IsAirgap() bool {
+	return PrivateRegistry != ""
+}
+
+// AirgapImages returns the images needed by the provider operator and the agents of a hosted cluster, for the rancher server version;
+// the list is taken from the rancher-images.txt file published with each rancher release
+func AirgapImages(client *rancher.Client) ([]string, error) {
+	serverVersion, err := GetRancherServerVersion(client)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(serverVersion, "v") {
+		serverVersion = "v" + serverVersion
+	}
+
+	resp, err := http.Get(fmt.Sprintf("https://github.com/rancher/rancher/releases/download/%s/rancher-images.txt", serverVersion))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to download rancher-images.txt")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download rancher-images.txt for %s: %s", serverVersion, resp.Status)
+	}
+
+	repositories := []string{"rancher/rancher-agent", "rancher/fleet-agent", "rancher/rancher-webhook", "rancher/shell", fmt.Sprintf("rancher/%s-operator", Provider)}
+	var images []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		image := strings.TrimSpace(scanner.Text())
+		repository, _, _ := strings.Cut(image, ":")
+		if ContainsString(repositories, repository) {
+			images = append(images, image)
+		}
+	}
+	return images, scanner.Err()
+}
+
+// MirrorImages pulls the images from the public registry and pushes them to the registry using docker
+func MirrorImages(images []string, registry string) error {
+	for _, image := range images {
+		mirroredImage := fmt.Sprintf("%s/%s", registry, image)
+		for _, args := range [][]string{{"pull", image}, {"tag", image, mirroredImage}, {"push", mirroredImage}} {
+			if _, err := extcli.Docker.Run(args...); err != nil {
+				return errors.Wrap(err, "Failed to mirror image "+image)
+			}
+		}
+		fmt.Println("Mirrored image: ", mirroredImage)
+	}
+	return nil
+}
+
+// SetSystemDefaultRegistry sets the system-default-registry Setting used by rancher for the system images, operator and agents included
+func SetSystemDefaultRegistry(client *rancher.Client, registry string) error {
+	return setRancherSetting(client, "system-default-registry", registry)
+}
+
+// setRancherSetting sets the value of a rancher Setting, and drops its cached value
+func setRancherSetting(client *rancher.Client, id, value string) error {
+	setting, err := client.Management.Setting.ByID(id)
+	if err != nil {
+		return err
+	}
+	updatedSetting := *setting
+	updatedSetting.Value = value
+	_, err = client.Management.Setting.Update(setting, &updatedSetting)
+	forgetCachedSetting(id)
+	return err
+}
+
+// SetupAirgap mirrors the images to the private registry and configures rancher to use it as system-default-registry
+func SetupAirgap(client *rancher.Client) {
+	ginkgo.By(fmt.Sprintf("mirroring the operator and agent images to %s", PrivateRegistry), func() {
+		images, err := AirgapImages(client)
+		Expect(err).To(BeNil())
+		Expect(images).ToNot(BeEmpty())
+		Expect(MirrorImages(images, PrivateRegistry)).To(Succeed())
+	})
+
+	ginkgo.By(fmt.Sprintf("setting system-default-registry to %s", PrivateRegistry), func() {
+		Expect(SetSystemDefaultRegistry(client, PrivateRegistry)).To(Succeed())
+	})
+
+	// rancher can not reach the chart repositories, the operator charts must be installed from the system charts bundled in the rancher image
+	ginkgo.By(fmt.Sprintf("setting %s to %s", systemCatalogSetting, bundledSystemCatalog), func() {
+		Expect(setRancherSetting(client, systemCatalogSetting, bundledSystemCatalog)).To(Succeed())
+	})
+}
+
+// chartSystemDefaultRegistry returns the registry of the system images in the values of a helm release, as output by `helm get values -o json`
+func chartSystemDefaultRegistry(values []byte) (string, error) {
+	var chartValues struct {
+		Global struct {
+			Cattle struct {
+				SystemDefaultRegistry string `json:"systemDefaultRegistry"`
+			} `json:"cattle"`
+		} `json:"global"`
+	}
+	if err := json.Unmarshal(values, &chartValues); err != nil {
+		return "", err
+	}
+	return chartValues.Global.Cattle.SystemDefaultRegistry, nil
+}
+
+/*
+Check that the operator charts of the Provider are installed from the system charts bundled in the rancher image rather than from the internet:
+the system catalog must be bundled, the installed chart versions must be served by the rancher-charts repository and the charts must be
+configured with the private registry.
+It requires the upstream cluster to be reachable via kubectl and helm.
+  - @param client Rancher admin client
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckOperatorChartsFromSystemCharts(client *rancher.Client) {
+	ginkgo.By(fmt.Sprintf("checking the %s-operator charts are installed from the bundled system charts", Provider), func() {
+		setting, err := client.Management.Setting.ByID(systemCatalogSetting)
+		Expect(err).To(BeNil())
+		Expect(setting.Value).To(Equal(bundledSystemCatalog))
+
+		operatorCharts := ListOperatorChart()
+		Expect(operatorCharts).ToNot(BeEmpty())
+		for _, chart := range operatorCharts {
+			versions, err := client.Catalog.GetListChartVersions(chart.Name, catalog.RancherChartRepo)
+			Expect(err).To(BeNil())
+			Expect(versions).To(ContainElement(chart.DerivedVersion), "chart %s is not served by the %s repository", chart.Chart, catalog.RancherChartRepo)
+
+			values, err := extcli.Helm.Output("get", "values", chart.Name, "--namespace", CattleSystemNS, "--all", "-o", "json")
+			Expect(err).To(BeNil())
+			registry, err := chartSystemDefaultRegistry([]byte(values))
+			Expect(err).To(BeNil())
+			Expect(registry).To(Equal(PrivateRegistry), "chart %s does not use the private registry", chart.Chart)
+		}
+	})
+}
+
+// CheckImagesFromPrivateRegistry checks that the operator and the pods of the downstream cluster agents use images from the private registry
+func CheckImagesFromPrivateRegistry(client *rancher.Client, clusterID string) {
+	registryPrefix := PrivateRegistry + "/"
+
+	ginkgo.By("checking the operator image is pulled from the private registry", func() {
+		out, err := runUpstreamKubectl("get", "pods", "--namespace", CattleSystemNS, "-l", operatorSelector(Provider), "-o", "jsonpath={.items[*].spec.containers[*].image}")
+		Expect(err).To(BeNil())
+		images := strings.Fields(out)
+		Expect(images).ToNot(BeEmpty())
+		for _, image := range images {
+			Expect(image).To(HavePrefix(registryPrefix))
+		}
+	})
+
+	ginkgo.By("checking the downstream agent images are pulled from the private registry", func() {
+		downstreamClient, err := client.Steve.ProxyDownstream(clusterID)
+		Expect(err).To(BeNil())
+		for _, namespace := range airgapNamespaces {
+			podList, err := downstreamClient.SteveType(pods.PodResourceSteveType).NamespacedSteveClient(namespace).List(nil)
+			Expect(err).To(BeNil())
+			for _, pod := range podList.Data {
+				podSpec := struct {
+					Containers []struct {
+						Image string `json:"image"`
+					} `json:"containers"`
+				}{}
+				Expect(v1.ConvertToK8sType(pod.Spec, &podSpec)).To(Succeed())
+				for _, container := range podSpec.Containers {
+					Expect(container.Image).To(HavePrefix(registryPrefix), "pod %s/%s does not use the private registry", namespace, pod.Name)
+				}
+			}
+		}
+	})
+}
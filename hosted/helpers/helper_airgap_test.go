@@ -0,0 +1,16 @@
+package helpers
+
+import "testing"
+
+func TestChartSystemDefaultRegistry(t *testing.T) {
+	values := `{"global":{"cattle":{"systemDefaultRegistry":"registry.local:5000"}},"httpProxy":""}`
+	if got, err := chartSystemDefaultRegistry([]byte(values)); err != nil || got != "registry.local:5000" {
+		t.Errorf("got %q, %v, want registry.local:5000", got, err)
+	}
+	if got, err := chartSystemDefaultRegistry([]byte(`{}`)); err != nil || got != "" {
+		t.Errorf("got %q, %v, want no registry", got, err)
+	}
+	if _, err := chartSystemDefaultRegistry([]byte(`null-`)); err == nil {
+		t.Error("got no error for invalid values")
+	}
+}
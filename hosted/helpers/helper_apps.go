@@ -0,0 +1,174 @@
+package helpers
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/rancher/tests/v2/actions/charts"
+	"github.com/rancher/rancher/tests/v2/actions/projects"
+	"github.com/rancher/shepherd/clients/rancher"
+	"github.com/rancher/shepherd/clients/rancher/catalog"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	extcharts "github.com/rancher/shepherd/extensions/charts"
+	shepherdclusters "github.com/rancher/shepherd/extensions/clusters"
+	"github.com/rancher/shepherd/extensions/workloads/pods"
+	"github.com/rancher/shepherd/pkg/api/steve/catalog/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Longhorn chart of the Rancher chart repository
+const (
+	LonghornName      = "longhorn"
+	LonghornNamespace = "longhorn-system"
+)
+
+// istioIngressGatewayID is the Steve ID of the load balancer service of the Istio ingress gateway
+var istioIngressGatewayID = charts.RancherIstioNamespace + "/istio-ingressgateway"
+
+// SmokeApps returns the Rancher charts installed by the apps smoke suite on the clusters of the provider; Longhorn is skipped on GKE,
+// since the Container-Optimized OS images of the nodes do not support iSCSI.
+func SmokeApps(provider string) []string {
+	apps := []string{charts.RancherLoggingName, charts.RancherIstioName}
+	if provider != "gke" {
+		apps = append(apps, LonghornName)
+	}
+	return apps
+}
+
+/*
+Install Rancher charts on a downstream cluster through the Rancher catalog API, in the System project, and check that their workloads are ready
+and healthy; rancher-monitoring is installed along with rancher-istio, which depends on it. The charts are uninstalled when the client session is
+cleaned up.
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @param apps Names of the charts, for e.g. from SmokeApps; only the charts of SmokeApps are supported
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func InstallRancherApps(client *rancher.Client, cluster *management.Cluster, apps ...string) {
+	clusterMeta, err := shepherdclusters.NewClusterMeta(client, cluster.Name)
+	Expect(err).To(BeNil())
+	systemProject, err := projects.GetProjectByName(client, cluster.ID, "System")
+	Expect(err).To(BeNil())
+	catalogClient, err := client.GetClusterCatalogClient(cluster.ID)
+	Expect(err).To(BeNil())
+
+	for _, app := range apps {
+		if app == charts.RancherIstioName {
+			InstallRancherMonitoring(client, cluster, "")
+		}
+
+		version, err := catalogClient.GetLatestChartVersion(app, catalog.RancherChartRepo)
+		Expect(err).To(BeNil())
+		installOptions := &charts.InstallOptions{Cluster: clusterMeta, Version: version, ProjectID: systemProject.ID}
+
+		var namespace string
+		ginkgo.By(fmt.Sprintf("Installing %s %s on cluster %s", app, version, cluster.Name), func() {
+			defer TimeOperation(OperationChartInstall, cluster, true)()
+
+			switch app {
+			case charts.RancherLoggingName:
+				namespace = charts.RancherLoggingNamespace
+				err = charts.InstallRancherLoggingChart(client, installOptions, &charts.RancherLoggingOpts{AdditionalLoggingSources: true})
+			case charts.RancherIstioName:
+				namespace = charts.RancherIstioNamespace
+				err = charts.InstallRancherIstioChart(client, installOptions, &charts.RancherIstioOpts{
+					IngressGateways: true,
+					Pilot:           true,
+					Telemetry:       true,
+					Kiali:           true,
+				})
+			case LonghornName:
+				namespace = LonghornNamespace
+				err = installLonghorn(client, catalogClient, installOptions)
+			default:
+				ginkgo.Fail(fmt.Sprintf("Chart %s is not supported", app))
+			}
+			Expect(err).To(BeNil())
+
+			Expect(extcharts.WatchAndWaitDeployments(client, cluster.ID, namespace, metav1.ListOptions{})).To(Succeed())
+			Expect(extcharts.WatchAndWaitDaemonSets(client, cluster.ID, namespace, metav1.ListOptions{})).To(Succeed())
+		})
+
+		if app == charts.RancherIstioName {
+			checkIstioIngressGateway(client, cluster)
+		}
+	}
+
+	ginkgo.By(fmt.Sprintf("Checking the pods of cluster %s", cluster.Name), func() {
+		Expect(pods.StatusPods(client, cluster.ID)).To(BeEmpty())
+	})
+}
+
+// installLonghorn installs the Longhorn chart, along with its CRD chart if the repository has one for the same version; the deleting confirmation
+// flag is set so that the chart can be uninstalled once the client session is cleaned up.
+func installLonghorn(client *rancher.Client, catalogClient *catalog.Client, installOptions *charts.InstallOptions) error {
+	registry, err := cachedSettingValue(client, "system-default-registry")
+	if err != nil {
+		return err
+	}
+	// the recent Longhorn charts ship their CRDs, the CRD chart is not found in the repository then
+	crdVersions, _ := catalogClient.GetListChartVersions(LonghornName+"-crd", catalog.RancherChartRepo)
+
+	newChartInstall := func(name string, values map[string]any) types.ChartInstall {
+		values["global"] = map[string]any{
+			"cattle": map[string]any{
+				"clusterId":             installOptions.Cluster.ID,
+				"clusterName":           installOptions.Cluster.Name,
+				"systemDefaultRegistry": registry,
+				"systemProjectId":       installOptions.ProjectID,
+			},
+			"systemDefaultRegistry": registry,
+		}
+		return types.ChartInstall{ChartName: name, ReleaseName: name, Version: installOptions.Version, Values: values}
+	}
+	chartInstalls := []types.ChartInstall{
+		newChartInstall(LonghornName, map[string]any{"defaultSettings": map[string]any{"deletingConfirmationFlag": true}}),
+	}
+	if slices.Contains(crdVersions, installOptions.Version) {
+		chartInstalls = append([]types.ChartInstall{newChartInstall(LonghornName+"-crd", map[string]any{})}, chartInstalls...)
+	}
+
+	client.Session.RegisterCleanupFunc(func() error {
+		// the CRD chart is uninstalled last
+		for i := len(chartInstalls) - 1; i >= 0; i-- {
+			if err := catalogClient.UninstallChart(chartInstalls[i].ReleaseName, LonghornNamespace, &types.ChartUninstallAction{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return catalogClient.InstallChart(&types.ChartInstallAction{
+		Timeout:   &metav1.Duration{Duration: 10 * time.Minute},
+		Wait:      true,
+		Namespace: LonghornNamespace,
+		ProjectID: installOptions.ProjectID,
+		Charts:    chartInstalls,
+	}, catalog.RancherChartRepo)
+}
+
+// checkIstioIngressGateway waits for the cloud provider to assign an address to the load balancer of the Istio ingress gateway
+func checkIstioIngressGateway(client *rancher.Client, cluster *management.Cluster) {
+	steveClient, err := client.Steve.ProxyDownstream(cluster.ID)
+	Expect(err).To(BeNil())
+
+	ginkgo.By(fmt.Sprintf("Checking the load balancer of the Istio ingress gateway on cluster %s", cluster.Name), func() {
+		EventuallyWithBackoff(func() ([]corev1.LoadBalancerIngress, error) {
+			object, err := steveClient.SteveType("service").ByID(istioIngressGatewayID)
+			if err != nil {
+				return nil, err
+			}
+			service := &corev1.Service{}
+			if err = steveV1.ConvertToK8sType(object.JSONResp, service); err != nil {
+				return nil, err
+			}
+			return service.Status.LoadBalancer.Ingress, nil
+		}, tools.SetTimeout(10*time.Minute), 10*time.Second).ShouldNot(BeEmpty(), fmt.Sprintf("Service %s has no load balancer address", istioIngressGatewayID))
+	})
+}
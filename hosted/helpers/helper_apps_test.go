@@ -0,0 +1,18 @@
+package helpers
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSmokeApps(t *testing.T) {
+	for provider, want := range map[string][]string{
+		"eks": {"rancher-logging", "rancher-istio", "longhorn"},
+		"gke": {"rancher-logging", "rancher-istio"},
+		"aks": {"rancher-logging", "rancher-istio", "longhorn"},
+	} {
+		if got := SmokeApps(provider); !slices.Equal(got, want) {
+			t.Errorf("SmokeApps(%s) = %v, want %v", provider, got, want)
+		}
+	}
+}
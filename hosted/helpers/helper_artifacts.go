@@ -0,0 +1,96 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+)
+
+var unsafeArtifactChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// ArtifactDir creates and returns the artifact directory of the spec, where the helpers write the outputs of the spec:
+// downstream kubeconfigs, operator logs, records of the external commands and support bundle.
+// The directory is ArtifactsDir/<sanitized spec text>_p<process number>; the suite level nodes (for e.g. SynchronizedBeforeSuite)
+// get a directory named after the node type.
+func ArtifactDir(spec ginkgo.SpecReport) (string, error) {
+	name := spec.FullText()
+	if name == "" {
+		name = spec.LeafNodeType.String()
+	}
+	name = strings.Trim(unsafeArtifactChars.ReplaceAllString(name, "_"), "_")
+	// keep the directory name within a sane length limit
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	if name == "" {
+		name = "spec"
+	}
+	dir := filepath.Join(ArtifactsDir, fmt.Sprintf("%s_p%d", name, ginkgo.GinkgoParallelProcess()))
+	return dir, os.MkdirAll(dir, 0o755)
+}
+
+// CurrentArtifactDir returns the artifact directory of the running spec, see ArtifactDir
+func CurrentArtifactDir() string {
+	dir, err := ArtifactDir(ginkgo.CurrentSpecReport())
+	Expect(err).To(BeNil())
+	return dir
+}
+
+// specOutputFile is the file of the spec artifact directory the GinkgoWriter output of the spec is copied to
+const specOutputFile = "ginkgo-writer.log"
+
+// CollectSpecArtifacts records the external commands run during the spec (see pkg/extcli) in the spec artifact directory
+// instead of ArtifactsDir, copies the GinkgoWriter output of the spec there as it is written (see teeSpecOutput),
+// and streams the operator logs there (see StreamOperatorLogs).
+// It must be called from a BeforeEach node; the commands run by the AfterEach nodes, for e.g. the cluster deletion, are recorded as well.
+func CollectSpecArtifacts() {
+	dir, err := ArtifactDir(ginkgo.CurrentSpecReport())
+	if err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to create the artifact directory, the commands will be recorded in %s: %v", ArtifactsDir, err))
+	} else {
+		extcli.SetLogDir(dir)
+		ginkgo.DeferCleanup(extcli.SetLogDir, ArtifactsDir)
+		teeSpecOutput(dir)
+	}
+
+	StreamOperatorLogs()
+}
+
+// openSpecOutput opens the spec output file of the directory for appending, and writes the header of the attempt;
+// every attempt of a spec retried with FLAKE_ATTEMPTS is appended to the same file.
+func openSpecOutput(dir, specText string, attempt int) (*os.File, error) {
+	f, err := os.OpenFile(filepath.Join(dir, specOutputFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = fmt.Fprintf(f, "=== %s %s (attempt %d)\n", time.Now().UTC().Format(time.RFC3339), specText, attempt); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// teeSpecOutput copies the GinkgoWriter output of the spec, which includes GinkgoLogr and the commands run by the helpers, to ginkgo-writer.log
+// in the spec artifact directory as it is written. Ginkgo only emits the output of a parallel spec once it is done, and interleaves the output
+// of the processes when streaming it, so that this file is the only complete output of a spec which timed out or was interrupted.
+func teeSpecOutput(dir string) {
+	spec := ginkgo.CurrentSpecReport()
+	f, err := openSpecOutput(dir, spec.FullText(), spec.NumAttempts)
+	if err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to open the spec output file: %v", err))
+		return
+	}
+	ginkgo.GinkgoWriter.TeeTo(f)
+	ginkgo.DeferCleanup(func() {
+		ginkgo.GinkgoWriter.ClearTeeWriters()
+		_ = f.Close()
+	})
+}
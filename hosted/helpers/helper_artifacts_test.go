@@ -0,0 +1,68 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+func TestArtifactDir(t *testing.T) {
+	defer func(dir string) { ArtifactsDir = dir }(ArtifactsDir)
+	ArtifactsDir = t.TempDir()
+
+	for _, tt := range []struct {
+		spec types.SpecReport
+		want string
+	}{
+		{
+			spec: types.SpecReport{ContainerHierarchyTexts: []string{"P1Provisioning", "with 2 nodepools"}, LeafNodeText: "should scale/upgrade", LeafNodeType: types.NodeTypeIt},
+			want: "P1Provisioning_with_2_nodepools_should_scale_upgrade_p1",
+		},
+		{
+			spec: types.SpecReport{LeafNodeType: types.NodeTypeSynchronizedBeforeSuite},
+			want: "SynchronizedBeforeSuite_p1",
+		},
+		{
+			spec: types.SpecReport{LeafNodeText: strings.Repeat("a", 150), LeafNodeType: types.NodeTypeIt},
+			want: strings.Repeat("a", 100) + "_p1",
+		},
+	} {
+		dir, err := ArtifactDir(tt.spec)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if dir != filepath.Join(ArtifactsDir, tt.want) {
+			t.Errorf("got %q, want %q", dir, filepath.Join(ArtifactsDir, tt.want))
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			t.Errorf("%s was not created: %v", dir, err)
+		}
+	}
+}
+
+func TestOpenSpecOutput(t *testing.T) {
+	dir := t.TempDir()
+	// every attempt of a retried spec is appended to the same file
+	for attempt := 1; attempt <= 2; attempt++ {
+		f, err := openSpecOutput(dir, "P0 should provision", attempt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = f.WriteString("output of the attempt\n"); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, specOutputFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 4 || !strings.HasSuffix(lines[0], " P0 should provision (attempt 1)") || !strings.HasSuffix(lines[2], "(attempt 2)") || lines[3] != "output of the attempt" {
+		t.Errorf("unexpected spec output:\n%s", content)
+	}
+}
@@ -0,0 +1,199 @@
+package helpers
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+)
+
+// Backoff computes the polling intervals of an exponential backoff with jitter;
+// its parameters come from the run config (POLL_BACKOFF_FACTOR, POLL_MAX_INTERVAL and POLL_JITTER).
+type Backoff struct {
+	initial     time.Duration
+	interval    time.Duration
+	maxInterval time.Duration
+	factor      float64
+	jitter      float64
+}
+
+// NewBackoff returns a backoff starting at the given interval, up to POLL_MAX_INTERVAL
+func NewBackoff(initial time.Duration) *Backoff {
+	return newBackoff(PollProfile{Initial: initial, Max: runConfig.PollMaxInterval})
+}
+
+// newBackoff returns a backoff following the polling profile, with the factor and the jitter of the run config
+func newBackoff(profile PollProfile) *Backoff {
+	b := &Backoff{
+		initial:     profile.Initial,
+		interval:    profile.Initial,
+		maxInterval: profile.Max,
+		factor:      runConfig.PollBackoffFactor,
+		jitter:      runConfig.PollJitter,
+	}
+	if b.maxInterval < b.initial {
+		b.maxInterval = b.initial
+	}
+	return b
+}
+
+// PollProfile is the first and the longest polling intervals of the waits of an operation
+type PollProfile struct {
+	Initial time.Duration
+	Max     time.Duration
+}
+
+// defaultPollProfiles are the polling profiles of the operations, see EventuallyForOperation; the long operations, which take up to 15 minutes
+// and are waited on by many specs at once, poll fast at first to catch the quick changes and slow down to a poll every 2 minutes
+var defaultPollProfiles = map[string]PollProfile{
+	OperationProvision:      {Initial: 15 * time.Second, Max: 2 * time.Minute},
+	OperationImport:         {Initial: 15 * time.Second, Max: 2 * time.Minute},
+	OperationUpgrade:        {Initial: 15 * time.Second, Max: 2 * time.Minute},
+	OperationNodeUpgrade:    {Initial: 15 * time.Second, Max: 2 * time.Minute},
+	OperationScale:          {Initial: 10 * time.Second, Max: time.Minute},
+	OperationAddNodePool:    {Initial: 10 * time.Second, Max: time.Minute},
+	OperationDeleteNodePool: {Initial: 10 * time.Second, Max: time.Minute},
+	OperationNodesReady:     {Initial: 5 * time.Second, Max: 30 * time.Second},
+	OperationNodePoolBatch:  {Initial: 15 * time.Second, Max: 2 * time.Minute},
+}
+
+// defaultPollInitial is the first polling interval of the operations without a polling profile; they poll up to POLL_MAX_INTERVAL
+const defaultPollInitial = 10 * time.Second
+
+// parsePollProfiles parses the POLL_PROFILES overrides, for e.g. upgrade=30s:3m; the initial or the longest interval can be omitted, for e.g. scale=:2m
+func parsePollProfiles(values []string) (map[string]PollProfile, error) {
+	profiles := map[string]PollProfile{}
+	for _, value := range values {
+		operation, intervals, found := strings.Cut(value, "=")
+		initial, longest, foundMax := strings.Cut(intervals, ":")
+		if !found || operation == "" || !foundMax {
+			return nil, fmt.Errorf("%q is not an <operation>=<initial>:<max> polling profile", value)
+		}
+
+		profile := pollProfile(operation, nil)
+		var err error
+		if profile.Initial, err = parsePollInterval(initial, profile.Initial); err != nil {
+			return nil, fmt.Errorf("the initial interval of %q is not valid: %w", value, err)
+		}
+		if profile.Max, err = parsePollInterval(longest, profile.Max); err != nil {
+			return nil, fmt.Errorf("the longest interval of %q is not valid: %w", value, err)
+		}
+		if profile.Max < profile.Initial {
+			return nil, fmt.Errorf("the longest interval of %q is shorter than the initial one", value)
+		}
+		profiles[operation] = profile
+	}
+	return profiles, nil
+}
+
+// parsePollInterval parses an interval of a polling profile, or returns defaultValue if it is omitted
+func parsePollInterval(value string, defaultValue time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%s is not a positive duration", value)
+	}
+	return d, nil
+}
+
+// pollProfile returns the polling profile of the operation: its override, its default profile, or the default intervals
+func pollProfile(operation string, overrides map[string]PollProfile) PollProfile {
+	if profile, found := overrides[operation]; found {
+		return profile
+	}
+	if profile, found := defaultPollProfiles[operation]; found {
+		return profile
+	}
+	return PollProfile{Initial: defaultPollInitial, Max: runConfig.PollMaxInterval}
+}
+
+// NewOperationBackoff returns a backoff following the polling profile of the operation, for e.g. OperationUpgrade; see EventuallyForOperation
+func NewOperationBackoff(operation string) *Backoff {
+	// the overrides are checked by Validate
+	overrides, _ := parsePollProfiles(runConfig.PollProfiles)
+	return newBackoff(pollProfile(operation, overrides))
+}
+
+// Next returns the interval to wait before the next poll and grows the following one;
+// the jitter spreads the polls of the parallel processes so that they do not hit Rancher at the same time.
+func (b *Backoff) Next() time.Duration {
+	next := time.Duration(float64(b.interval) * (1 + b.jitter*(2*rand.Float64()-1)))
+	b.interval = min(time.Duration(float64(b.interval)*b.factor), b.maxInterval)
+	return next
+}
+
+// withBackoff wraps the function polled by Eventually so that every call but the first one waits for the next backoff interval;
+// the wait is shortened to the deadline so that the last poll happens when Eventually times out, and polls past the deadline
+// (Eventually may still poll while noticing the timeout) wait for the initial interval.
+func withBackoff(actual any, timeout time.Duration, backoff *Backoff) any {
+	f := reflect.ValueOf(actual)
+	if f.Kind() != reflect.Func {
+		return actual
+	}
+
+	deadline := time.Now().Add(timeout)
+	first := true
+	return reflect.MakeFunc(f.Type(), func(args []reflect.Value) []reflect.Value {
+		if !first {
+			wait := backoff.Next()
+			if remaining := time.Until(deadline); remaining <= 0 {
+				wait = backoff.initial
+			} else if remaining < wait {
+				wait = remaining
+			}
+			time.Sleep(wait)
+		}
+		first = false
+		if f.Type().IsVariadic() {
+			return f.CallSlice(args)
+		}
+		return f.Call(args)
+	}).Interface()
+}
+
+// toDuration converts a timeout or interval given as a time.Duration or a duration string, like Eventually accepts them
+func toDuration(value any) time.Duration {
+	switch v := value.(type) {
+	case time.Duration:
+		return v
+	case string:
+		d, err := time.ParseDuration(v)
+		Expect(err).To(BeNil())
+		return d
+	}
+	ginkgo.Fail(fmt.Sprintf("%#v is not a valid duration", value))
+	return 0
+}
+
+// EventuallyWithBackoff is Eventually polling with an exponential backoff and jitter starting at interval, instead of a fixed interval;
+// it must be preferred when polling the Rancher API, to reduce the load on Rancher during parallel runs.
+// For e.g. EventuallyWithBackoff(func() bool {...}, tools.SetTimeout(5*time.Minute), 10*time.Second).Should(BeTrue())
+func EventuallyWithBackoff(actual any, timeout, interval any) types.AsyncAssertion {
+	t := toDuration(timeout)
+	return EventuallyWithOffset(1, withBackoff(actual, t, NewBackoff(toDuration(interval))), t).WithPolling(0)
+}
+
+/*
+EventuallyForOperation is Eventually polling with the adaptive backoff of the operation: it polls fast at first and slows down up to the longest
+interval of the polling profile of the operation, so that the specs waiting on the same long operations at once do not load the Rancher API;
+the profiles can be overridden with POLL_PROFILES. It must be preferred to EventuallyWithBackoff when waiting on a cluster operation,
+for e.g. EventuallyForOperation(OperationUpgrade, func() bool {...}, tools.SetTimeout(15*time.Minute)).Should(BeTrue())
+  - @param operation Operation waited on, for e.g. OperationUpgrade; the operations without a profile poll from 10s up to POLL_MAX_INTERVAL
+  - @param actual Function polled
+  - @param timeout Timeout of the wait, a time.Duration or a duration string
+  - @returns The async assertion
+*/
+func EventuallyForOperation(operation string, actual any, timeout any) types.AsyncAssertion {
+	t := toDuration(timeout)
+	return EventuallyWithOffset(1, withBackoff(actual, t, NewOperationBackoff(operation)), t).WithPolling(0)
+}
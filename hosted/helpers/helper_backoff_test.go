@@ -0,0 +1,60 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePollProfiles(t *testing.T) {
+	profiles, err := parsePollProfiles([]string{"upgrade=30s:3m", "scale=:2m", "chart-install=5s:20s"})
+	if err != nil {
+		t.Fatalf("parsePollProfiles() = %v", err)
+	}
+	want := map[string]PollProfile{
+		OperationUpgrade:      {Initial: 30 * time.Second, Max: 3 * time.Minute},
+		OperationScale:        {Initial: 10 * time.Second, Max: 2 * time.Minute},
+		OperationChartInstall: {Initial: 5 * time.Second, Max: 20 * time.Second},
+	}
+	for operation, profile := range want {
+		if profiles[operation] != profile {
+			t.Errorf("profile of %s = %+v, want %+v", operation, profiles[operation], profile)
+		}
+	}
+
+	for _, invalid := range []string{"upgrade", "upgrade=30s", "=30s:3m", "upgrade=thirty:3m", "upgrade=30s:-1m", "upgrade=3m:30s"} {
+		if _, err := parsePollProfiles([]string{invalid}); err == nil {
+			t.Errorf("parsePollProfiles(%q) must fail", invalid)
+		}
+	}
+}
+
+func TestOperationBackoff(t *testing.T) {
+	defer func(config RunConfig) { *runConfig = config }(*runConfig)
+	runConfig.PollBackoffFactor, runConfig.PollJitter, runConfig.PollMaxInterval = 2, 0, time.Minute
+	runConfig.PollProfiles = []string{"upgrade=1m:4m"}
+
+	intervals := func(backoff *Backoff, n int) []time.Duration {
+		var got []time.Duration
+		for i := 0; i < n; i++ {
+			got = append(got, backoff.Next())
+		}
+		return got
+	}
+	for _, test := range []struct {
+		operation string
+		want      []time.Duration
+	}{
+		{OperationUpgrade, []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 4 * time.Minute}},
+		{OperationScale, []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}},
+		// without a profile, the operations poll up to POLL_MAX_INTERVAL
+		{OperationChartInstall, []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute}},
+	} {
+		got := intervals(NewOperationBackoff(test.operation), len(test.want))
+		for i := range test.want {
+			if got[i] != test.want[i] {
+				t.Errorf("intervals of %s = %v, want %v", test.operation, got, test.want)
+				break
+			}
+		}
+	}
+}
@@ -2,7 +2,6 @@ package helpers
 
 import (
 	"os"
-	"os/exec"
 	"strings"
 	"time"
 
@@ -11,6 +10,7 @@ import (
 	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
 	"github.com/rancher-sandbox/ele-testhelpers/rancher"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
 )
 
 /*
@@ -25,20 +25,19 @@ func InstallBackupOperator(k *kubectl.Kubectl) {
 	if backupRestoreVersion != "" {
 		chartRepo = "https://github.com/rancher/backup-restore-operator/releases/download/" + backupRestoreVersion
 	} else {
-		RunHelmCmdWithRetry("repo", "add", chartRepo, "https://charts.rancher.io")
-		RunHelmCmdWithRetry("repo", "update")
+		AddHelmRepo(chartRepo, "https://charts.rancher.io")
 	}
 
 	for _, chart := range []string{"rancher-backup-crd", "rancher-backup"} {
 		// Set the filename in chart if a custom version is defined
-		chartName := chart
-		if backupRestoreVersion != "" {
-			chartName = chart + "-" + strings.TrimPrefix(backupRestoreVersion, "v") + ".tgz"
+		chartRef := chartRepo + "/" + chart + "-" + strings.TrimPrefix(backupRestoreVersion, "v") + ".tgz"
+		if backupRestoreVersion == "" {
+			chartRef = CachedChart(chartRepo, chart, "")
 		}
 
 		// Global installation flags
 		flags := []string{
-			"upgrade", "--install", chart, chartRepo + "/" + chartName,
+			"upgrade", "--install", chart, chartRef,
 			"--namespace", "cattle-resources-system",
 			"--create-namespace",
 			"--wait", "--wait-for-jobs",
@@ -54,7 +53,7 @@ func InstallBackupOperator(k *kubectl.Kubectl) {
 
 		RunHelmCmdWithRetry(flags...)
 
-		Eventually(func() error {
+		EventuallyWithBackoff(func() error {
 			return rancher.CheckPod(k, [][]string{{"cattle-resources-system", "app.kubernetes.io/name=rancher-backup"}})
 		}, tools.SetTimeout(5*time.Minute), 30*time.Second).Should(Not(HaveOccurred()))
 	}
@@ -73,14 +72,14 @@ func CheckOperation(resourceName string, message string) {
 	} else {
 		operation = "restore"
 	}
-	Eventually(func() string {
+	EventuallyWithBackoff(func() string {
 		out, _ := kubectl.RunWithoutErr("get", operation, resourceName,
 			"-o", "jsonpath={.metadata.name}")
 		return out
 	}, tools.SetTimeout(5*time.Minute), 10*time.Second).Should(ContainSubstring(resourceName))
 
 	// Wait for operation to be done
-	Eventually(func() string {
+	EventuallyWithBackoff(func() string {
 		out, _ := kubectl.RunWithoutErr("logs", "-l app.kubernetes.io/name=rancher-backup",
 			"--tail=-1", "--since=5m",
 			"--namespace", "cattle-resources-system")
@@ -137,7 +136,7 @@ func ExecuteBackup(k *kubectl.Kubectl, backupResourceName string) string {
 		localPath := GetLocalPath()
 
 		// Copy backup file
-		err = exec.Command("sudo", "cp", localPath+"/"+backupFile, ".").Run()
+		_, err = extcli.New("sudo").WithTimeout(5*time.Minute).Run("cp", localPath+"/"+backupFile, ".")
 		Expect(err).To(Not(HaveOccurred()))
 	})
 	return backupFile
@@ -158,7 +157,7 @@ func ExecuteRestore(k *kubectl.Kubectl, restoreResourceName, backupFile string)
 		localPath := GetLocalPath()
 
 		// Copy backup file
-		err := exec.Command("sudo", "cp", backupFile, localPath).Run()
+		_, err := extcli.New("sudo").WithTimeout(5*time.Minute).Run("cp", backupFile, localPath)
 		Expect(err).To(Not(HaveOccurred()))
 	})
 
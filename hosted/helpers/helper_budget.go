@@ -0,0 +1,119 @@
+package helpers
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// CriticalSpec is the label of the specs which keep running once the suite time budget is nearly exhausted, for e.g. the p0 specs;
+// the other specs are skipped first, see SkipOverBudget
+const CriticalSpec = "critical"
+
+// timeBudget is the time left to the suite: the specs are started until the deadline, minus the time reserved for the cleanup
+type timeBudget struct {
+	deadline time.Time
+	// reserve is the time kept for the deletion of the clusters and the reports once the last spec ends
+	reserve time.Duration
+	// specEstimate is the time a spec needs, which must be left for a spec not labelled CriticalSpec to start
+	specEstimate time.Duration
+}
+
+// suiteTimeBudget is the time budget of the suite, set by SuiteConfig; its deadline is zero if the suite has no deadline
+var suiteTimeBudget struct {
+	sync.Mutex
+	timeBudget
+}
+
+// parseSuiteDeadline returns the SUITE_DEADLINE time, zero if it is not set
+func parseSuiteDeadline(deadline string) (time.Time, error) {
+	if deadline == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, deadline)
+}
+
+// newTimeBudget returns the budget of a suite started at the given time: its deadline is the earliest of SUITE_DEADLINE, the start plus SUITE_TIME_BUDGET
+// and the start plus the ginkgo --timeout if it was set, which kills the suite once it is reached; the settings which are not set are ignored.
+func newTimeBudget(config *RunConfig, start time.Time, ginkgoTimeout time.Duration) timeBudget {
+	budget := timeBudget{reserve: config.CleanupReserve, specEstimate: config.SpecTimeEstimate}
+	earliest := func(deadline time.Time) {
+		if !deadline.IsZero() && (budget.deadline.IsZero() || deadline.Before(budget.deadline)) {
+			budget.deadline = deadline
+		}
+	}
+	if deadline, err := parseSuiteDeadline(config.SuiteDeadline); err == nil {
+		earliest(deadline)
+	}
+	if config.SuiteTimeBudget > 0 {
+		earliest(start.Add(config.SuiteTimeBudget))
+	}
+	if ginkgoTimeout > 0 {
+		earliest(start.Add(ginkgoTimeout))
+	}
+	return budget
+}
+
+// skipReason returns why a spec can not start at the given time without eating into the cleanup reserve, empty if it can start:
+// a critical spec starts as long as the reserve is not reached, the other specs need the estimated time of a spec on top of it
+func (b timeBudget) skipReason(now time.Time, critical bool) string {
+	if b.deadline.IsZero() {
+		return ""
+	}
+	left := b.deadline.Sub(now) - b.reserve
+	switch {
+	case left <= 0:
+		return fmt.Sprintf("the suite time budget is exhausted, the %s left to the deadline %s are reserved for the cleanup",
+			b.deadline.Sub(now).Round(time.Second), b.deadline.Format(time.RFC3339))
+	case !critical && left < b.specEstimate:
+		return fmt.Sprintf("the suite time budget is nearly exhausted, only %s are left before the cleanup reserve of the deadline %s, less than the %s a spec needs",
+			left.Round(time.Second), b.deadline.Format(time.RFC3339), b.specEstimate)
+	}
+	return ""
+}
+
+// explicitGinkgoTimeout returns the ginkgo --timeout of the suite if it was set, zero if it is the default one of ginkgo (1h):
+// most runs do not set it and last longer, their specs must not be skipped after a few minutes
+func explicitGinkgoTimeout(suiteConfig types.SuiteConfig) time.Duration {
+	if suiteConfig.Timeout == types.NewDefaultSuiteConfig().Timeout {
+		return 0
+	}
+	return suiteConfig.Timeout
+}
+
+// startTimeBudget sets the time budget of the suite from its ginkgo config, see SkipOverBudget; it is called by SuiteConfig before the specs are run
+func startTimeBudget(suiteConfig types.SuiteConfig) {
+	suiteTimeBudget.Lock()
+	suiteTimeBudget.timeBudget = newTimeBudget(runConfig, time.Now(), explicitGinkgoTimeout(suiteConfig))
+	suiteTimeBudget.Unlock()
+}
+
+// Skip the specs over the time budget before every spec; it is registered by the package, ahead of the BeforeEach of the suites,
+// so that a skipped spec does not run their setup first. The suites not using SuiteConfig have no budget, their specs are never skipped
+var _ = ginkgo.BeforeEach(SkipOverBudget)
+
+/*
+SkipOverBudget skips the current spec if the suite has not enough time left to run it and still delete its clusters before the suite deadline,
+the earliest of SUITE_DEADLINE, SUITE_TIME_BUDGET and the ginkgo --timeout if it was set, instead of being killed mid-provisioning and leaking the clusters.
+The last CLEANUP_RESERVE before the deadline are always kept for the cleanup; the specs labelled CriticalSpec start as long as the reserve is not reached,
+the others once SPEC_TIME_ESTIMATE is left on top of it. It is run before every spec, ahead of the BeforeEach of the suites.
+  - @returns Nothing, the spec is skipped through Ginkgo if it is over the budget
+*/
+func SkipOverBudget() {
+	suiteTimeBudget.Lock()
+	budget := suiteTimeBudget.timeBudget
+	suiteTimeBudget.Unlock()
+
+	critical := false
+	for _, label := range ginkgo.CurrentSpecReport().Labels() {
+		if label == CriticalSpec {
+			critical = true
+		}
+	}
+	if reason := budget.skipReason(time.Now(), critical); reason != "" {
+		ginkgo.Skip(reason)
+	}
+}
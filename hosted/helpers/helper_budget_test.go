@@ -0,0 +1,75 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+func TestNewTimeBudget(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		config        RunConfig
+		ginkgoTimeout time.Duration
+		want          time.Time
+	}{
+		{name: "no deadline", want: time.Time{}},
+		{name: "ginkgo timeout", ginkgoTimeout: 3 * time.Hour, want: start.Add(3 * time.Hour)},
+		{name: "time budget before the ginkgo timeout", config: RunConfig{SuiteTimeBudget: 2 * time.Hour}, ginkgoTimeout: 3 * time.Hour, want: start.Add(2 * time.Hour)},
+		{name: "CI deadline", config: RunConfig{SuiteDeadline: "2024-06-01T13:30:00Z", SuiteTimeBudget: 2 * time.Hour}, ginkgoTimeout: 3 * time.Hour, want: start.Add(90 * time.Minute)},
+		{name: "invalid deadline", config: RunConfig{SuiteDeadline: "13:30"}, ginkgoTimeout: 3 * time.Hour, want: start.Add(3 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newTimeBudget(&tt.config, start, tt.ginkgoTimeout).deadline; !got.Equal(tt.want) {
+				t.Errorf("got deadline %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExplicitGinkgoTimeout(t *testing.T) {
+	suiteConfig := types.NewDefaultSuiteConfig()
+	if timeout := explicitGinkgoTimeout(suiteConfig); timeout != 0 {
+		t.Errorf("the default ginkgo timeout must not be a deadline, got %s", timeout)
+	}
+	suiteConfig.Timeout = 3 * time.Hour
+	if timeout := explicitGinkgoTimeout(suiteConfig); timeout != 3*time.Hour {
+		t.Errorf("got timeout %s, want 3h", timeout)
+	}
+}
+
+func TestTimeBudgetSkipReason(t *testing.T) {
+	deadline := time.Date(2024, 6, 1, 15, 0, 0, 0, time.UTC)
+	budget := timeBudget{deadline: deadline, reserve: 20 * time.Minute, specEstimate: 30 * time.Minute}
+	tests := []struct {
+		name     string
+		left     time.Duration
+		critical bool
+		skip     bool
+	}{
+		{name: "enough time", left: 2 * time.Hour},
+		{name: "spec estimate left on top of the reserve", left: 50 * time.Minute},
+		{name: "budget nearly exhausted", left: 40 * time.Minute, skip: true},
+		{name: "critical spec with the budget nearly exhausted", left: 40 * time.Minute, critical: true},
+		{name: "cleanup reserve reached", left: 15 * time.Minute, skip: true},
+		{name: "critical spec within the cleanup reserve", left: 15 * time.Minute, critical: true, skip: true},
+		{name: "deadline passed", left: -time.Minute, critical: true, skip: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := budget.skipReason(deadline.Add(-tt.left), tt.critical)
+			if (reason != "") != tt.skip {
+				t.Errorf("got skip reason %q, want skip %t", reason, tt.skip)
+			}
+		})
+	}
+
+	if reason := (timeBudget{}).skipReason(deadline, false); reason != "" {
+		t.Errorf("a suite without deadline must not skip its specs, got %q", reason)
+	}
+}
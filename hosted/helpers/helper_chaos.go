@@ -0,0 +1,80 @@
+package helpers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+)
+
+// operatorPodsJSONPath prints the name and the readiness of the containers of every operator pod, one pod per line
+const operatorPodsJSONPath = `jsonpath={range .items[*]}{.metadata.name}{" "}{.status.containerStatuses[*].ready}{"\n"}{end}`
+
+// operatorSelector returns the label selector of the operator pods of the provider, for e.g. ke.cattle.io/operator=eks
+func operatorSelector(provider string) string {
+	return fmt.Sprintf("ke.cattle.io/operator=%s", provider)
+}
+
+// readyOperatorPods returns the pods of the operatorPodsJSONPath output whose containers are all ready, skipping the given pods
+func readyOperatorPods(out string, skipped []string) []string {
+	var ready []string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || slices.Contains(skipped, fields[0]) {
+			continue
+		}
+		allReady := true
+		for _, status := range fields[1:] {
+			allReady = allReady && status == "true"
+		}
+		if allReady {
+			ready = append(ready, fields[0])
+		}
+	}
+	return ready
+}
+
+// GetOperatorPods returns the names of the operator pods of the Provider on the upstream cluster
+func GetOperatorPods() ([]string, error) {
+	out, err := runUpstreamKubectl("get", "pods", "--namespace", CattleSystemNS, "-l", operatorSelector(Provider), "-o", "jsonpath={.items[*].metadata.name}")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
+
+/*
+Kill the operator pods of the Provider, without grace period as a crash would, and wait for their replacement to be ready;
+it is meant to be used while the operator is reconciling a cluster, so that the reconciliation has to be resumed by the new pod.
+It requires the upstream cluster to be reachable via kubectl.
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func KillOperatorPod() {
+	ginkgo.By(fmt.Sprintf("Killing the %s-operator pod", Provider), func() {
+		var killed []string
+		EventuallyWithBackoff(func() ([]string, error) {
+			var err error
+			killed, err = GetOperatorPods()
+			return killed, err
+		}, tools.SetTimeout(5*time.Minute), 5*time.Second).ShouldNot(BeEmpty(), fmt.Sprintf("No %s-operator pod found", Provider))
+
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Killing %s-operator pods %s", Provider, strings.Join(killed, ", ")))
+		args := append([]string{"delete", "pods", "--namespace", CattleSystemNS, "--grace-period=0", "--force"}, killed...)
+		_, err := runUpstreamKubectl(args...)
+		Expect(err).To(BeNil())
+
+		waitForOperatorPod(killed)
+	})
+}
+
+// waitForOperatorPod waits for an operator pod of the Provider other than the skipped ones to be ready
+func waitForOperatorPod(skipped []string) {
+	EventuallyWithBackoff(func() ([]string, error) {
+		out, err := runUpstreamKubectl("get", "pods", "--namespace", CattleSystemNS, "-l", operatorSelector(Provider), "-o", operatorPodsJSONPath)
+		return readyOperatorPods(out, skipped), err
+	}, tools.SetTimeout(5*time.Minute), 5*time.Second).ShouldNot(BeEmpty(), fmt.Sprintf("No %s-operator pod is ready", Provider))
+}
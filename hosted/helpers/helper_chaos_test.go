@@ -0,0 +1,19 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadyOperatorPods(t *testing.T) {
+	out := "eks-config-operator-old true\neks-config-operator-new true\neks-config-operator-starting false\n\neks-config-operator-pending\n"
+	if got, want := readyOperatorPods(out, []string{"eks-config-operator-old"}), []string{"eks-config-operator-new"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := readyOperatorPods("eks-config-operator-old true\n", []string{"eks-config-operator-old"}); len(got) != 0 {
+		t.Errorf("got %v, want no ready pod", got)
+	}
+	if got, want := readyOperatorPods("aks-config-operator-a true true\naks-config-operator-b true false\n", nil), []string{"aks-config-operator-a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -3,22 +3,22 @@ package helpers
 import (
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"strings"
 	"time"
 
 	"github.com/blang/semver"
 	"github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+	"github.com/rancher/hosted-providers-e2e/pkg/helmsdk"
 	"github.com/rancher/shepherd/clients/rancher/catalog"
 )
 
-// AddRancherCharts adds the repo from which rancher operator charts can be installed
+// AddRancherCharts adds the repo from which rancher operator charts can be installed, OPERATOR_CHARTS_REPO_URL;
+// the repo is replaced if it was added with another URL, for e.g. the community charts before testing the prime ones, see AddHelmRepo
 func AddRancherCharts() {
-	err := kubectl.RunHelmBinaryWithCustomErr("repo", "add", catalog.RancherChartRepo, "https://charts.rancher.io")
-	Expect(err).To(BeNil())
+	AddHelmRepo(catalog.RancherChartRepo, runConfig.OperatorChartsRepoURL)
 }
 
 // GetCurrentOperatorChartVersion returns the current version of a Provider chart.
@@ -32,22 +32,54 @@ func GetCurrentOperatorChartVersion() string {
 
 // GetDowngradeOperatorChartVersion returns a version to downgrade to from a given chart version.
 func GetDowngradeOperatorChartVersion(currentChartVersion string) string {
+	if versions := GetDowngradeOperatorChartVersions(currentChartVersion, 1); len(versions) > 0 {
+		return versions[0]
+	}
+	return ""
+}
+
+// GetDowngradeOperatorChartVersions returns up to n versions to downgrade to from a given chart version, from the highest to the lowest;
+// fewer versions are returned if the chart repository does not have as many previous versions.
+func GetDowngradeOperatorChartVersions(currentChartVersion string, n int) []string {
 	var chartName string
 	if charts := ListOperatorChart(); len(charts) > 0 {
 		chartName = charts[0].Name
 	} else {
 		ginkgo.GinkgoLogr.Info("Could not find downgrade chart; chart is not installed")
-		return ""
+		return nil
+	}
+	return previousChartVersions(ListChartVersions(chartName), currentChartVersion, n)
+}
+
+// previousChartVersions returns up to n distinct versions of the charts lower than currentChartVersion, from the highest to the lowest
+func previousChartVersions(charts []HelmChart, currentChartVersion string, n int) []string {
+	current, err := semver.ParseTolerant(currentChartVersion)
+	if err != nil {
+		return nil
 	}
-	chartVersions := ListChartVersions(chartName)
-	for _, chartVersion := range chartVersions {
-		if VersionCompare(chartVersion.DerivedVersion, currentChartVersion) == -1 {
-			return chartVersion.DerivedVersion
+
+	var previous []semver.Version
+	versions := map[string]string{}
+	for _, chart := range charts {
+		version, err := semver.ParseTolerant(chart.DerivedVersion)
+		if err != nil || version.GTE(current) {
+			continue
+		}
+		if _, found := versions[version.String()]; !found {
+			versions[version.String()] = chart.DerivedVersion
+			previous = append(previous, version)
 		}
 	}
-	return ""
+	semver.Sort(previous)
+
+	var downgradeVersions []string
+	for i := len(previous) - 1; i >= 0 && len(downgradeVersions) < n; i-- {
+		downgradeVersions = append(downgradeVersions, versions[previous[i].String()])
+	}
+	return downgradeVersions
 }
 
+// DowngradeProviderChart downgrades the operator charts to a lower chart version with the helm Go SDK, see UpdateOperatorChartsVersion
 func DowngradeProviderChart(downgradeChartVersion string) {
 	currentChartVersion := GetCurrentOperatorChartVersion()
 	Expect(currentChartVersion).ToNot(BeEmpty())
@@ -79,35 +111,35 @@ func WaitUntilOperatorChartInstallation(chartVersion, comparator string, compare
 
 }
 
-// UpdateOperatorChartsVersion updates the operator charts to a given chart version and validates that the current version is same as provided
+// UpdateOperatorChartsVersion updates the operator charts to a given chart version with the helm Go SDK, keeping their values,
+// and validates that the current version is same as provided
 func UpdateOperatorChartsVersion(updateChartVersion string) {
 	for _, chart := range ListOperatorChart() {
-		err := kubectl.RunHelmBinaryWithCustomErr("upgrade", "--install", chart.Name, fmt.Sprintf("%s/%s", catalog.RancherChartRepo, chart.Name), "--namespace", CattleSystemNS, "--version", updateChartVersion, "--wait")
-		if err != nil {
-			Expect(err).To(BeNil(), "UpdateOperatorChartsVersion Failed")
-		}
+		installRelease(helmsdk.Release{
+			Name:      chart.Name,
+			Namespace: CattleSystemNS,
+			Chart:     CachedChart(catalog.RancherChartRepo, chart.Name, updateChartVersion),
+			Version:   updateChartVersion,
+			Wait:      true,
+		})
 	}
 	Expect(VersionCompare(GetCurrentOperatorChartVersion(), updateChartVersion)).To(Equal(0))
 }
 
-// UninstallOperatorCharts uninstalls the operator charts
+// UninstallOperatorCharts uninstalls the operator charts with the helm Go SDK
 func UninstallOperatorCharts() {
 	for _, chart := range ListOperatorChart() {
-		args := []string{"uninstall", chart.Name, "--namespace", CattleSystemNS}
-		err := kubectl.RunHelmBinaryWithCustomErr(args...)
-		if err != nil {
-			Expect(err).To(BeNil(), "Failed to uninstall chart %s", chart.Name)
-		}
+		err := helmClient.Uninstall(chart.Name, CattleSystemNS)
+		Expect(err).To(BeNil(), "Failed to uninstall chart %s", chart.Name)
 	}
 }
 
 // ListOperatorChart lists the installed provider charts for a provider in cattle-system; it fetches the provider value using Provider
 func ListOperatorChart() (operatorCharts []HelmChart) {
-	cmd := exec.Command("helm", "list", "--namespace", CattleSystemNS, "-o", "json", "--filter", fmt.Sprintf("%s-operator", Provider))
-	output, err := cmd.Output()
+	output, err := extcli.Helm.Output("list", "--namespace", CattleSystemNS, "-o", "json", "--filter", fmt.Sprintf("%s-operator", Provider))
 	Expect(err).To(BeNil(), "Failed to list chart %s", Provider)
-	ginkgo.GinkgoLogr.Info(string(output))
-	err = json.Unmarshal(output, &operatorCharts)
+	ginkgo.GinkgoLogr.Info(output)
+	err = json.Unmarshal([]byte(output), &operatorCharts)
 	Expect(err).To(BeNil(), "Failed to unmarshal chart %s", Provider)
 	for i := range operatorCharts {
 		operatorCharts[i].DerivedVersion = strings.TrimPrefix(operatorCharts[i].Chart, fmt.Sprintf("%s-", operatorCharts[i].Name))
@@ -117,11 +149,10 @@ func ListOperatorChart() (operatorCharts []HelmChart) {
 
 // ListChartVersions lists all the available the chart version for a given chart name
 func ListChartVersions(chartName string) (charts []HelmChart) {
-	cmd := exec.Command("helm", "search", "repo", chartName, "--versions", "-ojson", "--devel")
-	output, err := cmd.Output()
+	output, err := extcli.Helm.Output("search", "repo", chartName, "--versions", "-ojson", "--devel")
 	Expect(err).To(BeNil())
-	ginkgo.GinkgoLogr.Info(string(output))
-	err = json.Unmarshal(output, &charts)
+	ginkgo.GinkgoLogr.Info(output)
+	err = json.Unmarshal([]byte(output), &charts)
 	Expect(err).To(BeNil())
 	return
 }
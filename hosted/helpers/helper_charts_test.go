@@ -0,0 +1,26 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPreviousChartVersions(t *testing.T) {
+	charts := []HelmChart{
+		{DerivedVersion: "106.0.0+up1.9.0"},
+		{DerivedVersion: "105.1.0+up1.8.1"},
+		{DerivedVersion: "104.2.0+up1.7.2"},
+		{DerivedVersion: "105.0.0+up1.8.0"},
+		{DerivedVersion: "105.1.0+up1.8.1"},
+		{DerivedVersion: "not-a-version"},
+	}
+	if got, want := previousChartVersions(charts, "106.0.0+up1.9.0", 2), []string{"105.1.0+up1.8.1", "105.0.0+up1.8.0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := previousChartVersions(charts, "105.1.0+up1.8.1", 5), []string{"105.0.0+up1.8.0", "104.2.0+up1.7.2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := previousChartVersions(charts, "104.2.0+up1.7.2", 1); len(got) != 0 {
+		t.Errorf("got %v, want no version", got)
+	}
+}
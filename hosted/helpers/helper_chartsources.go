@@ -0,0 +1,98 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// Default chart sources, see RunConfig
+const (
+	defaultPrimeRegistry         = "registry.rancher.com"
+	defaultOperatorChartsRepoURL = "https://charts.rancher.io"
+)
+
+// rancherChartRepoURLs are the helm repositories of the Rancher chart of the community and prime release streams
+var rancherChartRepoURLs = map[string]string{
+	"latest": "https://releases.rancher.com/server-charts/latest",
+	"stable": "https://releases.rancher.com/server-charts/stable",
+	"alpha":  "https://releases.rancher.com/server-charts/alpha",
+	"prime":  "https://charts.rancher.com/server-charts/prime",
+}
+
+// IsPrimeChannel returns true if the Rancher channel belongs to the prime release stream, for e.g. prime or prime-optimus
+func IsPrimeChannel(channel string) bool {
+	return strings.HasPrefix(channel, "prime")
+}
+
+// isChartRepoURL returns true if the value can be added as a helm repository
+func isChartRepoURL(value string) bool {
+	return strings.HasPrefix(value, "https://") || strings.HasPrefix(value, "http://")
+}
+
+// rancherChartRepoURL returns the helm repository of the Rancher chart of a channel: primeChartRepoURL for the prime channels if set,
+// the repository of the release stream otherwise; empty if the channel has no known repository, for e.g. prime-optimus
+func rancherChartRepoURL(channel, primeChartRepoURL string) string {
+	if IsPrimeChannel(channel) && primeChartRepoURL != "" {
+		return primeChartRepoURL
+	}
+	return rancherChartRepoURLs[channel]
+}
+
+// rancherImageProblem returns why the Rancher image does not belong to the release stream of the channel, for e.g. a community image
+// installed from the prime channel; empty if it does. Only the prime channel is checked, prime-optimus images come from the staging registry.
+func rancherImageProblem(channel, image, primeRegistry string) string {
+	fromPrime := strings.HasPrefix(image, primeRegistry+"/")
+	switch {
+	case channel == "prime" && !fromPrime:
+		return fmt.Sprintf("image %s of channel %s is not pulled from the prime registry %s", image, channel, primeRegistry)
+	case !IsPrimeChannel(channel) && fromPrime:
+		return fmt.Sprintf("image %s of channel %s is pulled from the prime registry %s", image, channel, primeRegistry)
+	}
+	return ""
+}
+
+// rancherStagingChartRepoURLs are the helm repositories of the prime-optimus channels, only added if the repository was not added beforehand
+var rancherStagingChartRepoURLs = map[string]string{
+	"prime-optimus":       "https://charts.optimus.rancher.io/server-charts/latest",
+	"prime-optimus-alpha": "https://charts.optimus.rancher.io/server-charts/alpha",
+}
+
+// AddRancherManagerRepo adds the rancher-<channel> helm repository the Rancher chart is installed and upgraded from;
+// for the channels without a known repository, the repository added beforehand is used (see PRIME_CHART_REPO_URL),
+// or the staging repository of the prime-optimus channels if there is none.
+func AddRancherManagerRepo(channel string) {
+	url := rancherChartRepoURL(channel, runConfig.PrimeChartRepoURL)
+	if url == "" {
+		repos, err := helmClient.Repositories()
+		Expect(err).To(BeNil())
+		for _, repo := range repos {
+			if repo.Name == "rancher-"+channel {
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("No known helm repository for channel %s, using rancher-%s (%s)", channel, channel, repo.URL))
+				return
+			}
+		}
+		url = rancherStagingChartRepoURLs[channel]
+		Expect(url).ToNot(BeEmpty(), "No known helm repository for channel %s, rancher-%s must be added", channel, channel)
+	}
+	// the repository is replaced if it was added with another URL, for e.g. when switching release streams
+	AddHelmRepo("rancher-"+channel, url)
+}
+
+// primeRancherValues returns the values pulling the Rancher image of the prime channels from PRIME_REGISTRY, if it is not the default one
+func primeRancherValues(channel string) []string {
+	if !IsPrimeChannel(channel) || runConfig.PrimeRegistry == defaultPrimeRegistry {
+		return nil
+	}
+	return []string{fmt.Sprintf("rancherImage=%s/rancher/rancher", runConfig.PrimeRegistry)}
+}
+
+// CheckRancherImageSource checks that the Rancher image belongs to the release stream of the channel it was installed from
+func CheckRancherImageSource(channel string) {
+	image, err := runUpstreamKubectl("get", "deployment", "rancher", "--namespace", CattleSystemNS, "-o", "jsonpath={.spec.template.spec.containers[0].image}")
+	Expect(err).To(BeNil())
+	Expect(rancherImageProblem(channel, image, runConfig.PrimeRegistry)).To(BeEmpty())
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Rancher image of channel %s: %s", channel, image))
+}
@@ -0,0 +1,37 @@
+package helpers
+
+import "testing"
+
+func TestRancherChartRepoURL(t *testing.T) {
+	for _, tc := range []struct {
+		channel, primeChartRepoURL, want string
+	}{
+		{channel: "latest", want: "https://releases.rancher.com/server-charts/latest"},
+		{channel: "latest", primeChartRepoURL: "https://example.com/prime", want: "https://releases.rancher.com/server-charts/latest"},
+		{channel: "prime", want: "https://charts.rancher.com/server-charts/prime"},
+		{channel: "prime", primeChartRepoURL: "https://example.com/prime", want: "https://example.com/prime"},
+		{channel: "prime-optimus", want: ""},
+		{channel: "prime-optimus", primeChartRepoURL: "https://example.com/prime", want: "https://example.com/prime"},
+	} {
+		if got := rancherChartRepoURL(tc.channel, tc.primeChartRepoURL); got != tc.want {
+			t.Errorf("rancherChartRepoURL(%s, %q) = %q, want %q", tc.channel, tc.primeChartRepoURL, got, tc.want)
+		}
+	}
+}
+
+func TestRancherImageProblem(t *testing.T) {
+	for _, tc := range []struct {
+		channel, image string
+		problem        bool
+	}{
+		{channel: "latest", image: "rancher/rancher:v2.9.3"},
+		{channel: "latest", image: "registry.rancher.com/rancher/rancher:v2.9.3", problem: true},
+		{channel: "prime", image: "registry.rancher.com/rancher/rancher:v2.9.3"},
+		{channel: "prime", image: "rancher/rancher:v2.9.3", problem: true},
+		{channel: "prime-optimus", image: "stgregistry.suse.com/rancher/rancher:v2.9.3-rc2"},
+	} {
+		if got := rancherImageProblem(tc.channel, tc.image, defaultPrimeRegistry); (got != "") != tc.problem {
+			t.Errorf("rancherImageProblem(%s, %s) = %q, want a problem: %t", tc.channel, tc.image, got, tc.problem)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+)
+
+// ResourceUsage is the CPU and memory used by the pods of a component of the upstream cluster
+type ResourceUsage struct {
+	CPUMillicores int64 `json:"cpuMillicores"`
+	MemoryMiB     int64 `json:"memoryMiB"`
+}
+
+// ChurnSample is the state of the upstream cluster after a churn cycle; cycle 0 is the state before the churn
+type ChurnSample struct {
+	Cycle      int                      `json:"cycle"`
+	Time       time.Time                `json:"time"`
+	Usage      map[string]ResourceUsage `json:"usage"`
+	Namespaces int                      `json:"namespaces"`
+	Secrets    int                      `json:"secrets"`
+}
+
+// churnComponents returns the deployments of the cattle-system namespace whose resource usage is measured during the churn
+func churnComponents(provider string) []string {
+	return []string{"rancher", provider + "-config-operator"}
+}
+
+// parsePodUsage sums the usage of the pods of every component from the output of `kubectl top pod --no-headers`;
+// the pods of a component are the ones of its deployment, for e.g. rancher-5d8f9c-x2k4z but not rancher-webhook-7b6d5f-q9z8w.
+func parsePodUsage(output string, components []string) (map[string]ResourceUsage, error) {
+	patterns := map[string]*regexp.Regexp{}
+	for _, component := range components {
+		patterns[component] = regexp.MustCompile(fmt.Sprintf(`^%s-[a-z0-9]+-[a-z0-9]+$`, regexp.QuoteMeta(component)))
+	}
+	usage := map[string]ResourceUsage{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected line %q", line)
+		}
+		for component, pattern := range patterns {
+			if !pattern.MatchString(fields[0]) {
+				continue
+			}
+			cpu, err := resource.ParseQuantity(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("CPU of pod %s: %w", fields[0], err)
+			}
+			memory, err := resource.ParseQuantity(fields[2])
+			if err != nil {
+				return nil, fmt.Errorf("memory of pod %s: %w", fields[0], err)
+			}
+			componentUsage := usage[component]
+			componentUsage.CPUMillicores += cpu.MilliValue()
+			componentUsage.MemoryMiB += memory.Value() / (1024 * 1024)
+			usage[component] = componentUsage
+		}
+	}
+	return usage, nil
+}
+
+// upstreamResource is a namespace or a secret of the upstream cluster
+type upstreamResource struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Metadata is the JSON of the labels, annotations and owner references of the resource, which may reference a cluster its name does not
+	Metadata string
+}
+
+func (r upstreamResource) String() string {
+	if r.Namespace == "" {
+		return fmt.Sprintf("%s %s", r.Kind, r.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", r.Kind, r.Namespace, r.Name)
+}
+
+// listUpstreamResources returns the resources of the given kind in all the namespaces of the upstream cluster
+func listUpstreamResources(kind string) ([]upstreamResource, error) {
+	out, err := extcli.Kubectl.Output(upstreamKubectlArgs("get", kind, "--all-namespaces", "-o", "json")...)
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name            string            `json:"name"`
+				Namespace       string            `json:"namespace"`
+				Labels          map[string]string `json:"labels"`
+				Annotations     map[string]string `json:"annotations"`
+				OwnerReferences []any             `json:"ownerReferences"`
+			} `json:"metadata"`
+		} `json:"items"`
+	}
+	if err = json.Unmarshal([]byte(out), &list); err != nil {
+		return nil, err
+	}
+	resources := make([]upstreamResource, 0, len(list.Items))
+	for _, item := range list.Items {
+		metadata, err := json.Marshal([]any{item.Metadata.Labels, item.Metadata.Annotations, item.Metadata.OwnerReferences})
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, upstreamResource{Kind: kind, Namespace: item.Metadata.Namespace, Name: item.Metadata.Name, Metadata: string(metadata)})
+	}
+	return resources, nil
+}
+
+// leakedResources returns the resources which did not exist before the churn and still reference one of the churned clusters, by ID
+func leakedResources(resources []upstreamResource, baseline map[string]bool, clusterIDs []string) []string {
+	var leaked []string
+	for _, r := range resources {
+		if baseline[r.String()] {
+			continue
+		}
+		for _, clusterID := range clusterIDs {
+			if strings.Contains(r.Namespace, clusterID) || strings.Contains(r.Name, clusterID) || strings.Contains(r.Metadata, clusterID) {
+				leaked = append(leaked, fmt.Sprintf("%s references cluster %s", r, clusterID))
+				break
+			}
+		}
+	}
+	return leaked
+}
+
+// listChurnResources returns the namespaces and the secrets of the upstream cluster
+func listChurnResources() (namespaces, secrets []upstreamResource, err error) {
+	if namespaces, err = listUpstreamResources("namespaces"); err != nil {
+		return nil, nil, err
+	}
+	secrets, err = listUpstreamResources("secrets")
+	return namespaces, secrets, err
+}
+
+// sampleChurn records the resource usage of the churn components and the number of namespaces and secrets of the upstream cluster
+func sampleChurn(cycle int) ChurnSample {
+	out, err := extcli.Kubectl.Output(upstreamKubectlArgs("top", "pod", "--namespace", CattleSystemNS, "--no-headers")...)
+	Expect(err).To(BeNil(), "Failed to get the resource usage of the %s pods; the metrics server of the upstream cluster is required", CattleSystemNS)
+	usage, err := parsePodUsage(out, churnComponents(Provider))
+	Expect(err).To(BeNil())
+	namespaces, secrets, err := listChurnResources()
+	Expect(err).To(BeNil())
+
+	sample := ChurnSample{Cycle: cycle, Time: time.Now(), Usage: usage, Namespaces: len(namespaces), Secrets: len(secrets)}
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Churn cycle %d: usage %+v, %d namespaces, %d secrets", cycle, usage, sample.Namespaces, sample.Secrets))
+	return sample
+}
+
+/*
+Churn the registration of a hosted cluster: the cloud cluster is imported into Rancher and removed from it once per cycle, once active on even cycles
+and while still registering on odd ones. The resource usage of Rancher and of the operator is sampled after every cycle and written to the
+artifacts directory (churn-usage.json); once the churn is over, no namespace or secret created for the churned clusters must be left on the upstream cluster.
+  - @param client Rancher client
+  - @param cycles Number of import/removal cycles, for e.g. RunConfig.ChurnCycles
+  - @param importCluster Function importing the cloud cluster into Rancher, for e.g. a wrapper of ImportEKSHostedCluster
+  - @param deleteCluster Function deleting the cluster from Rancher, for e.g. DeleteEKSHostCluster
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckRegistrationChurn(client *rancher.Client, cycles int, importCluster func() (*management.Cluster, error), deleteCluster func(*management.Cluster, *rancher.Client) error) {
+	namespaces, secrets, err := listChurnResources()
+	Expect(err).To(BeNil())
+	baseline := map[string]bool{}
+	for _, r := range append(namespaces, secrets...) {
+		baseline[r.String()] = true
+	}
+
+	samples := []ChurnSample{sampleChurn(0)}
+	var clusterIDs []string
+	for cycle := 1; cycle <= cycles; cycle++ {
+		waitActive := cycle%2 == 0
+		ginkgo.By(fmt.Sprintf("Churn cycle %d/%d: importing and removing the cluster, active=%t", cycle, cycles, waitActive), func() {
+			cluster, err := importCluster()
+			Expect(err).To(BeNil())
+			clusterIDs = append(clusterIDs, cluster.ID)
+			if waitActive {
+				cluster, err = WaitUntilClusterIsReady(cluster, client)
+				Expect(err).To(BeNil())
+			}
+			Expect(deleteCluster(cluster, client)).To(Succeed())
+			WaitUntilClusterIsRemoved(client, cluster.ID)
+		})
+		samples = append(samples, sampleChurn(cycle))
+	}
+
+	content, err := json.MarshalIndent(samples, "", "  ")
+	Expect(err).To(BeNil())
+	path := filepath.Join(CurrentArtifactDir(), "churn-usage.json")
+	Expect(os.WriteFile(path, content, 0o644)).To(Succeed())
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Wrote the churn samples to %s", path))
+
+	ginkgo.By(fmt.Sprintf("Checking no namespace or secret of the %d churned clusters is left", len(clusterIDs)), func() {
+		// the namespaces of a removed cluster are deleted asynchronously
+		EventuallyWithBackoff(func() ([]string, error) {
+			namespaces, secrets, err := listChurnResources()
+			if err != nil {
+				return nil, err
+			}
+			return leakedResources(append(namespaces, secrets...), baseline, clusterIDs), nil
+		}, tools.SetTimeout(10*time.Minute), 30*time.Second).Should(BeEmpty(), "Namespaces or secrets of the churned clusters were leaked")
+	})
+}
@@ -0,0 +1,58 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePodUsage(t *testing.T) {
+	output := `
+rancher-5d8f9c7b6d-x2k4z                  250m   1210Mi
+rancher-5d8f9c7b6d-q8w7e                  150m   1024Mi
+rancher-webhook-7b6d5f8c9-q9z8w           5m     64Mi
+eks-config-operator-6c7d8e9f0a-abcde      12m    48Mi
+helm-operation-xyz12                      1m     8Mi
+`
+	usage, err := parsePodUsage(output, churnComponents("eks"))
+	if err != nil {
+		t.Fatalf("parsePodUsage() = %v", err)
+	}
+	expected := map[string]ResourceUsage{
+		"rancher":             {CPUMillicores: 400, MemoryMiB: 2234},
+		"eks-config-operator": {CPUMillicores: 12, MemoryMiB: 48},
+	}
+	if !reflect.DeepEqual(usage, expected) {
+		t.Errorf("parsePodUsage() = %+v, want %+v", usage, expected)
+	}
+
+	if _, err = parsePodUsage("rancher-5d8f9c7b6d-x2k4z 250m", churnComponents("eks")); err == nil {
+		t.Errorf("parsePodUsage() of a truncated line returned no error")
+	}
+	if _, err = parsePodUsage("rancher-5d8f9c7b6d-x2k4z 250x 1Gi", churnComponents("eks")); err == nil {
+		t.Errorf("parsePodUsage() of an invalid CPU returned no error")
+	}
+}
+
+func TestLeakedResources(t *testing.T) {
+	baseline := map[string]bool{"namespaces local": true, "secrets cattle-global-data/c-old12-token": true}
+	resources := []upstreamResource{
+		{Kind: "namespaces", Name: "local"},
+		{Kind: "secrets", Namespace: "cattle-global-data", Name: "c-old12-token"},
+		{Kind: "namespaces", Name: "c-abc12"},
+		{Kind: "namespaces", Name: "c-abc12-p-x7k9m"},
+		{Kind: "secrets", Namespace: "cattle-global-data", Name: "cluster-serviceaccounttoken-q2w3e", Metadata: `[null,{"field.cattle.io/clusterId":"c-def34"},null]`},
+		{Kind: "secrets", Namespace: "cattle-system", Name: "tls-rancher"},
+	}
+	leaked := leakedResources(resources, baseline, []string{"c-abc12", "c-def34"})
+	expected := []string{
+		"namespaces c-abc12 references cluster c-abc12",
+		"namespaces c-abc12-p-x7k9m references cluster c-abc12",
+		"secrets cattle-global-data/cluster-serviceaccounttoken-q2w3e references cluster c-def34",
+	}
+	if !reflect.DeepEqual(leaked, expected) {
+		t.Errorf("leakedResources() = %v, want %v", leaked, expected)
+	}
+	if leaked = leakedResources(resources, baseline, nil); len(leaked) != 0 {
+		t.Errorf("leakedResources() without churned clusters = %v, want none", leaked)
+	}
+}
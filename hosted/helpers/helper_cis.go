@@ -0,0 +1,185 @@
+package helpers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/rancher/tests/v2/actions/charts"
+	"github.com/rancher/rancher/tests/v2/actions/projects"
+	"github.com/rancher/shepherd/clients/rancher"
+	"github.com/rancher/shepherd/clients/rancher/catalog"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	extcharts "github.com/rancher/shepherd/extensions/charts"
+	shepherdclusters "github.com/rancher/shepherd/extensions/clusters"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Steve types of the cis-operator resources
+const (
+	cisScanSteveType        = "cis.cattle.io.clusterscan"
+	cisScanProfileSteveType = "cis.cattle.io.clusterscanprofile"
+)
+
+// cisScanRunCompleted is the condition set by cis-operator once the report of a scan is generated
+const cisScanRunCompleted = "RunCompleted"
+
+// cisScanStatus is the part of the ClusterScan status of cis-operator needed to check a scan
+type cisScanStatus struct {
+	LastRunTimestamp       string          `json:"lastRunTimestamp"`
+	LastRunScanProfileName string          `json:"lastRunScanProfileName"`
+	Display                *cisScanDisplay `json:"display"`
+	Summary                *struct {
+		Total         int `json:"total"`
+		Pass          int `json:"pass"`
+		Fail          int `json:"fail"`
+		Skip          int `json:"skip"`
+		Warn          int `json:"warn"`
+		NotApplicable int `json:"notApplicable"`
+	} `json:"summary"`
+	Conditions []cisScanCondition `json:"conditions"`
+}
+
+// cisScanDisplay is the state of a scan shown by the Rancher UI
+type cisScanDisplay struct {
+	State   string `json:"state"`
+	Message string `json:"message"`
+	Error   bool   `json:"error"`
+}
+
+// cisScanCondition is a condition of the ClusterScan status
+type cisScanCondition struct {
+	Type   string `json:"type"`
+	Status string `json:"status"`
+}
+
+// cisScanProfile returns the latest scan profile of the managed provider among the profiles of the chart, for e.g. eks-profile-1.2.0;
+// the benchmark version is part of the profile names, so the greatest name is the latest benchmark.
+func cisScanProfile(profiles []string, provider string) string {
+	var latest string
+	for _, profile := range profiles {
+		if strings.HasPrefix(profile, provider+"-profile") && profile > latest {
+			latest = profile
+		}
+	}
+	return latest
+}
+
+// cisScanDone returns whether the scan has run and its report is generated; an error is returned if cis-operator failed to run the scan.
+// The failed checks do not make the scan fail, the managed providers are not expected to pass every check.
+func cisScanDone(status *cisScanStatus) (bool, error) {
+	if status.Display != nil && status.Display.Error {
+		return false, fmt.Errorf("scan is in error: %s", status.Display.Message)
+	}
+	completed := slices.ContainsFunc(status.Conditions, func(condition cisScanCondition) bool {
+		return condition.Type == cisScanRunCompleted && condition.Status == "True"
+	})
+	return completed && status.LastRunTimestamp != "", nil
+}
+
+/*
+Install the rancher-cis-benchmark chart on a downstream cluster through the Rancher catalog API, in the System project, and wait for cis-operator
+to be ready; the chart is uninstalled when the client session is cleaned up.
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @param version Version of the chart; the latest version of the Rancher chart repository is used if empty
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func InstallRancherCISBenchmark(client *rancher.Client, cluster *management.Cluster, version string) {
+	status, err := extcharts.GetChartStatus(client, cluster.ID, charts.CISBenchmarkNamespace, charts.CISBenchmarkName)
+	Expect(err).To(BeNil())
+	if status.IsAlreadyInstalled {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("%s is already installed on cluster %s", charts.CISBenchmarkName, cluster.Name))
+		return
+	}
+
+	catalogClient, err := client.GetClusterCatalogClient(cluster.ID)
+	Expect(err).To(BeNil())
+	if version == "" {
+		version, err = catalogClient.GetLatestChartVersion(charts.CISBenchmarkName, catalog.RancherChartRepo)
+		Expect(err).To(BeNil())
+	}
+
+	clusterMeta, err := shepherdclusters.NewClusterMeta(client, cluster.Name)
+	Expect(err).To(BeNil())
+	systemProject, err := projects.GetProjectByName(client, cluster.ID, "System")
+	Expect(err).To(BeNil())
+
+	ginkgo.By(fmt.Sprintf("Installing %s %s on cluster %s", charts.CISBenchmarkName, version, cluster.Name), func() {
+		defer TimeOperation(OperationChartInstall, cluster, true)()
+
+		err = charts.InstallCISBenchmarkChart(client, &charts.InstallOptions{
+			Cluster:   clusterMeta,
+			Version:   version,
+			ProjectID: systemProject.ID,
+		})
+		Expect(err).To(BeNil())
+
+		Expect(extcharts.WatchAndWaitDeployments(client, cluster.ID, charts.CISBenchmarkNamespace, metav1.ListOptions{})).To(Succeed())
+	})
+}
+
+/*
+Run a CIS benchmark scan on a downstream cluster with the latest scan profile of the Provider, and check that the scan completes with results
+  - @param client Rancher client
+  - @param cluster Downstream cluster on which rancher-cis-benchmark is installed
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func RunCISBenchmarkScan(client *rancher.Client, cluster *management.Cluster) {
+	steveClient, err := client.Steve.ProxyDownstream(cluster.ID)
+	Expect(err).To(BeNil())
+
+	var profile string
+	// the scan profiles are created by cis-operator once it is running
+	EventuallyWithBackoff(func() (string, error) {
+		collection, err := steveClient.SteveType(cisScanProfileSteveType).List(nil)
+		if err != nil {
+			return "", err
+		}
+		var profiles []string
+		for _, object := range collection.Data {
+			profiles = append(profiles, object.Name)
+		}
+		profile = cisScanProfile(profiles, Provider)
+		return profile, nil
+	}, tools.SetTimeout(3*time.Minute), 5*time.Second).ShouldNot(BeEmpty(), fmt.Sprintf("No CIS scan profile found for %s", Provider))
+
+	ginkgo.By(fmt.Sprintf("Running CIS scan with profile %s on cluster %s", profile, cluster.Name), func() {
+		scan, err := steveClient.SteveType(cisScanSteveType).Create(map[string]any{
+			"metadata": map[string]any{"name": namegen.AppendRandomString("hp-cis-scan")},
+			"spec":     map[string]any{"scanProfileName": profile},
+		})
+		Expect(err).To(BeNil())
+
+		status := &cisScanStatus{}
+		var scanErr error
+		Eventually(func() bool {
+			current, err := steveClient.SteveType(cisScanSteveType).ByID(scan.ID)
+			if err != nil {
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to get scan %s: %v", scan.ID, err))
+				return false
+			}
+			if err = steveV1.ConvertToK8sType(current.Status, status); err != nil {
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to read the status of scan %s: %v", scan.ID, err))
+				return false
+			}
+			var done bool
+			done, scanErr = cisScanDone(status)
+			// a scan error is final, there is no need to wait any longer
+			return done || scanErr != nil
+		}, tools.SetTimeout(20*time.Minute), 15*time.Second).Should(BeTrue(), fmt.Sprintf("Scan %s did not complete", scan.ID))
+		Expect(scanErr).To(BeNil())
+
+		Expect(status.LastRunScanProfileName).To(Equal(profile))
+		Expect(status.Summary).ToNot(BeNil(), fmt.Sprintf("Scan %s has no result", scan.ID))
+		Expect(status.Summary.Total).To(BeNumerically(">", 0), fmt.Sprintf("Scan %s has no result", scan.ID))
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Scan %s with profile %s: total=%d pass=%d fail=%d warn=%d skip=%d notApplicable=%d", scan.ID, profile,
+			status.Summary.Total, status.Summary.Pass, status.Summary.Fail, status.Summary.Warn, status.Summary.Skip, status.Summary.NotApplicable))
+	})
+}
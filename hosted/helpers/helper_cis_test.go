@@ -0,0 +1,37 @@
+package helpers
+
+import "testing"
+
+func TestCISScanProfile(t *testing.T) {
+	profiles := []string{"cis-1.8-profile", "eks-profile-1.0.1", "eks-profile-1.2.0", "gke-profile-1.6.0", "aks-profile", "rke2-cis-1.8-profile"}
+	for provider, want := range map[string]string{"eks": "eks-profile-1.2.0", "gke": "gke-profile-1.6.0", "aks": "aks-profile", "k3s": ""} {
+		if got := cisScanProfile(profiles, provider); got != want {
+			t.Errorf("cisScanProfile(%s) = %q, want %q", provider, got, want)
+		}
+	}
+}
+
+func TestCISScanDone(t *testing.T) {
+	completed := []cisScanCondition{{Type: "Created", Status: "True"}, {Type: cisScanRunCompleted, Status: "True"}}
+	for _, tc := range []struct {
+		name    string
+		status  cisScanStatus
+		want    bool
+		wantErr bool
+	}{
+		{name: "new", status: cisScanStatus{}, want: false},
+		{name: "running", status: cisScanStatus{Conditions: []cisScanCondition{{Type: cisScanRunCompleted, Status: "False"}}}, want: false},
+		{name: "completed", status: cisScanStatus{LastRunTimestamp: "2024-11-20T10:00:00Z", Conditions: completed}, want: true},
+	} {
+		got, err := cisScanDone(&tc.status)
+		if got != tc.want || (err != nil) != tc.wantErr {
+			t.Errorf("%s: cisScanDone() = %t, %v, want %t, error %t", tc.name, got, err, tc.want, tc.wantErr)
+		}
+	}
+
+	status := cisScanStatus{LastRunTimestamp: "2024-11-20T10:00:00Z", Conditions: completed}
+	status.Display = &cisScanDisplay{State: "error", Message: "job failed", Error: true}
+	if _, err := cisScanDone(&status); err == nil {
+		t.Errorf("error: cisScanDone() did not return the scan error")
+	}
+}
@@ -0,0 +1,330 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/norman/clientbase"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	v1 "github.com/rancher/shepherd/clients/rancher/v1"
+	"github.com/rancher/shepherd/extensions/cloudcredentials"
+	"github.com/rancher/shepherd/extensions/cloudcredentials/aws"
+	"github.com/rancher/shepherd/extensions/cloudcredentials/azure"
+	"github.com/rancher/shepherd/extensions/cloudcredentials/google"
+	"github.com/rancher/shepherd/extensions/defaults/namespaces"
+	"github.com/rancher/shepherd/extensions/defaults/stevetypes"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// cloudCredentialDrivers maps the provider to the driver annotation set on its cloud credential secrets
+var cloudCredentialDrivers = map[string]string{
+	"aks": "azure",
+	"eks": "aws",
+	"gke": "gcp",
+}
+
+// profileEnv returns the value of the env variable for the given credential profile, i.e. <name>_<PROFILE>
+func profileEnv(name, profile string) (string, error) {
+	key := fmt.Sprintf("%s_%s", name, strings.ToUpper(profile))
+	value := os.Getenv(key)
+	if value == "" {
+		return "", fmt.Errorf("%s is not set", key)
+	}
+	return value, nil
+}
+
+// LoadCloudCredentialProfile returns the cloud credential config of the current provider for the given profile.
+// The default profile ("") is read from the cattle config file; any other profile is read from the usual provider env variables
+// suffixed with the profile name, e.g. AWS_ACCESS_KEY_ID_READONLY and AWS_SECRET_ACCESS_KEY_READONLY for the "readonly" profile.
+func LoadCloudCredentialProfile(profile string) (cloudcredentials.CloudCredential, error) {
+	return loadCloudCredentialProfile(Provider, profile)
+}
+
+// loadCloudCredentialProfile returns the cloud credential config of the given provider for the given profile, see LoadCloudCredentialProfile
+func loadCloudCredentialProfile(provider, profile string) (cloudcredentials.CloudCredential, error) {
+	var (
+		err             error
+		cloudCredential cloudcredentials.CloudCredential
+	)
+
+	switch provider {
+	case "aks":
+		cloudCredential = cloudcredentials.LoadCloudCredential("azure")
+		if profile == "" {
+			return cloudCredential, nil
+		}
+		credentialConfig := *cloudCredential.AzureCredentialConfig
+		if credentialConfig.ClientID, err = profileEnv("AKS_CLIENT_ID", profile); err != nil {
+			return cloudCredential, err
+		}
+		if credentialConfig.ClientSecret, err = profileEnv("AKS_CLIENT_SECRET", profile); err != nil {
+			return cloudCredential, err
+		}
+		if credentialConfig.SubscriptionID, err = profileEnv("AKS_SUBSCRIPTION_ID", profile); err != nil {
+			return cloudCredential, err
+		}
+		cloudCredential.AzureCredentialConfig = &credentialConfig
+	case "eks":
+		cloudCredential = cloudcredentials.LoadCloudCredential("aws")
+		if profile == "" {
+			return cloudCredential, nil
+		}
+		credentialConfig := *cloudCredential.AmazonEC2CredentialConfig
+		if credentialConfig.AccessKey, err = profileEnv("AWS_ACCESS_KEY_ID", profile); err != nil {
+			return cloudCredential, err
+		}
+		if credentialConfig.SecretKey, err = profileEnv("AWS_SECRET_ACCESS_KEY", profile); err != nil {
+			return cloudCredential, err
+		}
+		cloudCredential.AmazonEC2CredentialConfig = &credentialConfig
+	case "gke":
+		cloudCredential = cloudcredentials.LoadCloudCredential("google")
+		if profile == "" {
+			return cloudCredential, nil
+		}
+		credentialConfig := *cloudCredential.GoogleCredentialConfig
+		if credentialConfig.AuthEncodedJSON, err = profileEnv("GCP_CREDENTIALS", profile); err != nil {
+			return cloudCredential, err
+		}
+		cloudCredential.GoogleCredentialConfig = &credentialConfig
+	default:
+		return cloudCredential, fmt.Errorf("unsupported provider %q", provider)
+	}
+	return cloudCredential, nil
+}
+
+// CreateCloudCredentialsFromConfig creates a cloud credential of the current provider from the given config
+// and returns its ID in the <namespace>:<name> format used by the cluster configs
+func CreateCloudCredentialsFromConfig(client *rancher.Client, cloudCredentialConfig cloudcredentials.CloudCredential) (string, error) {
+	return createCloudCredentialsFromConfig(client, Provider, cloudCredentialConfig)
+}
+
+// createCloudCredentialsFromConfig creates a cloud credential of the given provider from the given config, see CreateCloudCredentialsFromConfig
+func createCloudCredentialsFromConfig(client *rancher.Client, provider string, cloudCredentialConfig cloudcredentials.CloudCredential) (string, error) {
+	var (
+		err             error
+		cloudCredential *v1.SteveAPIObject
+	)
+
+	switch provider {
+	case "aks":
+		cloudCredential, err = azure.CreateAzureCloudCredentials(client, cloudCredentialConfig)
+	case "eks":
+		cloudCredential, err = aws.CreateAWSCloudCredentials(client, cloudCredentialConfig)
+	case "gke":
+		cloudCredential, err = google.CreateGoogleCloudCredentials(client, cloudCredentialConfig)
+	default:
+		err = fmt.Errorf("unsupported provider %q", provider)
+	}
+	if err != nil {
+		return "", err
+	}
+	cloudCredID := fmt.Sprintf("%s:%s", cloudCredential.Namespace, cloudCredential.Name)
+	TrackResource(Resource{Kind: ResourceCloudCredential, Name: cloudCredID, ID: cloudCredID, Provider: provider})
+	return cloudCredID, nil
+}
+
+// CreateProviderCloudCredentials creates a cloud credential of the given provider from the cattle config file,
+// for the suites using several providers; the other helpers use the credentials of the current provider.
+func CreateProviderCloudCredentials(client *rancher.Client, provider string) (string, error) {
+	cloudCredentialConfig, err := loadCloudCredentialProfile(provider, "")
+	if err != nil {
+		return "", err
+	}
+	return createCloudCredentialsFromConfig(client, provider, cloudCredentialConfig)
+}
+
+// CreateCloudCredentialsForProfile creates a cloud credential of the current provider for the given profile;
+// see LoadCloudCredentialProfile for the profile definition
+func CreateCloudCredentialsForProfile(client *rancher.Client, profile string) (string, error) {
+	cloudCredentialConfig, err := LoadCloudCredentialProfile(profile)
+	if err != nil {
+		return "", err
+	}
+	return CreateCloudCredentialsFromConfig(client, cloudCredentialConfig)
+}
+
+// ListCloudCredentials returns the IDs of the cloud credentials of the current provider visible to the client
+func ListCloudCredentials(client *rancher.Client) ([]string, error) {
+	secrets, err := client.Steve.SteveType(stevetypes.Secret).NamespacedSteveClient(namespaces.CattleData).List(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, secret := range secrets.Data {
+		if secret.Annotations["provisioning.cattle.io/driver"] != cloudCredentialDrivers[Provider] {
+			continue
+		}
+		ids = append(ids, fmt.Sprintf("%s:%s", secret.Namespace, secret.Name))
+	}
+	return ids, nil
+}
+
+// DeleteCloudCredential deletes the cloud credential with the given <namespace>:<name> ID
+func DeleteCloudCredential(client *rancher.Client, cloudCredID string) error {
+	cloudCredential, err := client.Management.CloudCredential.ByID(cloudCredID)
+	if err != nil {
+		return err
+	}
+	if err = client.Management.CloudCredential.Delete(cloudCredential); err != nil {
+		return err
+	}
+	MarkResourceDeleted(ResourceCloudCredential, cloudCredID)
+	return nil
+}
+
+// ClusterCloudCredential returns the ID of the cloud credential referenced by the provider config of the cluster
+func ClusterCloudCredential(cluster *management.Cluster) string {
+	switch Provider {
+	case "aks":
+		return cluster.AKSConfig.AzureCredentialSecret
+	case "eks":
+		return cluster.EKSConfig.AmazonCredentialSecret
+	case "gke":
+		return cluster.GKEConfig.GoogleCredentialSecret
+	}
+	return ""
+}
+
+// SetClusterCloudCredential points the provider config of the cluster to the given cloud credential and waits for the upstream spec to be synced
+func SetClusterCloudCredential(cluster *management.Cluster, client *rancher.Client, cloudCredID string) (*management.Cluster, error) {
+	upgradedCluster := cluster
+	switch Provider {
+	case "aks":
+		upgradedCluster.AKSConfig.AzureCredentialSecret = cloudCredID
+	case "eks":
+		upgradedCluster.EKSConfig.AmazonCredentialSecret = cloudCredID
+	case "gke":
+		upgradedCluster.GKEConfig.GoogleCredentialSecret = cloudCredID
+	}
+
+	cluster, err := UpdateRancherCluster(client, cluster, &upgradedCluster)
+	if err != nil {
+		return cluster, err
+	}
+
+	Eventually(func() string {
+		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		Expect(err).NotTo(HaveOccurred())
+		_, upstreamSpec := providerSpecs(cluster)
+		switch spec := upstreamSpec.(type) {
+		case *management.AKSClusterConfigSpec:
+			return spec.AzureCredentialSecret
+		case *management.EKSClusterConfigSpec:
+			return spec.AmazonCredentialSecret
+		case *management.GKEClusterConfigSpec:
+			return spec.GoogleCredentialSecret
+		}
+		return ""
+	}, "5m", "5s").Should(Equal(cloudCredID), "Failed while upstream cloud credentials update")
+	return cluster, nil
+}
+
+// CheckDeleteCloudCredentialInUse attempts to delete the cloud credential backing the active cluster and expects Rancher to reject it.
+// If the deletion goes through anyway, a new cloud credential is created and set on the cluster before failing the spec,
+// so that the cluster (and the AfterEach cleanup) keeps working.
+// It returns the up-to-date cluster.
+func CheckDeleteCloudCredentialInUse(cluster *management.Cluster, client *rancher.Client) *management.Cluster {
+	cloudCredID := ClusterCloudCredential(cluster)
+	Expect(cloudCredID).ToNot(BeEmpty())
+
+	ginkgo.By(fmt.Sprintf("attempting to delete the cloud credential %s in use by cluster %s", cloudCredID, cluster.Name), func() {
+		err := DeleteCloudCredential(client, cloudCredID)
+		if err == nil {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Cloud credential %s has been deleted, restoring a new one on cluster %s", cloudCredID, cluster.Name))
+			newCCID, err := CreateCloudCredentials(client)
+			Expect(err).To(BeNil())
+			cluster, err = SetClusterCloudCredential(cluster, client, newCCID)
+			Expect(err).To(BeNil())
+			ginkgo.Fail(fmt.Sprintf("Rancher allowed the deletion of cloud credential %s in use by cluster %s", cloudCredID, cluster.Name))
+		}
+
+		var apiErr *clientbase.APIError
+		Expect(errors.As(err, &apiErr)).To(BeTrue(), "unexpected error: %v", err)
+		Expect(apiErr.StatusCode).To(SatisfyAll(BeNumerically(">=", 400), BeNumerically("<", 500)), apiErr.Error())
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Deletion of cloud credential %s rejected: %s", cloudCredID, apiErr.Error()))
+	})
+
+	ginkgo.By("checking the cloud credential and the cluster are unaffected", func() {
+		_, err := client.Management.CloudCredential.ByID(cloudCredID)
+		Expect(err).To(BeNil())
+
+		Consistently(func() string {
+			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			Expect(err).NotTo(HaveOccurred())
+			return cluster.State
+		}, time.Minute, 10*time.Second).Should(Equal("active"))
+		Expect(ClusterCloudCredential(cluster)).To(Equal(cloudCredID))
+	})
+
+	return cluster
+}
+
+// RotatedCredentialProfile is the cloud credential profile holding the new keys used by RotateCloudCredential,
+// for e.g. AWS_ACCESS_KEY_ID_ROTATED and AWS_SECRET_ACCESS_KEY_ROTATED; see LoadCloudCredentialProfile
+const RotatedCredentialProfile = "rotated"
+
+// cloudCredentialSecretData returns the keys of the cloud credential secret of the provider holding the given config
+func cloudCredentialSecretData(provider string, cloudCredentialConfig cloudcredentials.CloudCredential) (map[string][]byte, error) {
+	switch {
+	case provider == "aks" && cloudCredentialConfig.AzureCredentialConfig != nil:
+		config := cloudCredentialConfig.AzureCredentialConfig
+		return map[string][]byte{
+			"azurecredentialConfig-clientId":       []byte(config.ClientID),
+			"azurecredentialConfig-clientSecret":   []byte(config.ClientSecret),
+			"azurecredentialConfig-subscriptionId": []byte(config.SubscriptionID),
+		}, nil
+	case provider == "eks" && cloudCredentialConfig.AmazonEC2CredentialConfig != nil:
+		config := cloudCredentialConfig.AmazonEC2CredentialConfig
+		return map[string][]byte{
+			"amazonec2credentialConfig-accessKey": []byte(config.AccessKey),
+			"amazonec2credentialConfig-secretKey": []byte(config.SecretKey),
+		}, nil
+	case provider == "gke" && cloudCredentialConfig.GoogleCredentialConfig != nil:
+		return map[string][]byte{
+			"googlecredentialConfig-authEncodedJson": []byte(cloudCredentialConfig.GoogleCredentialConfig.AuthEncodedJSON),
+		}, nil
+	}
+	return nil, fmt.Errorf("no %s cloud credential config found", provider)
+}
+
+// RotateCloudCredential replaces the keys of an existing cloud credential of the provider with the keys of the given profile, as an admin
+// rotating the keys of the cloud account would; the ID of the credential does not change, so the clusters using it are not updated.
+func RotateCloudCredential(client *rancher.Client, provider, cloudCredID, profile string) error {
+	cloudCredentialConfig, err := loadCloudCredentialProfile(provider, profile)
+	if err != nil {
+		return err
+	}
+	data, err := cloudCredentialSecretData(provider, cloudCredentialConfig)
+	if err != nil {
+		return err
+	}
+
+	secretClient := client.Steve.SteveType(stevetypes.Secret)
+	secret, err := secretClient.ByID(strings.Replace(cloudCredID, ":", "/", 1))
+	if err != nil {
+		return err
+	}
+	updatedSecret := &corev1.Secret{}
+	if err = v1.ConvertToK8sType(secret.JSONResp, updatedSecret); err != nil {
+		return err
+	}
+	if updatedSecret.Data == nil {
+		updatedSecret.Data = map[string][]byte{}
+	}
+	for key, value := range data {
+		updatedSecret.Data[key] = value
+	}
+	if _, err = secretClient.Update(secret, updatedSecret); err != nil {
+		return err
+	}
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Rotated the keys of cloud credential %s with profile %s", cloudCredID, profile))
+	return nil
+}
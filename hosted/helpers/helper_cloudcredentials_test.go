@@ -0,0 +1,33 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/rancher/shepherd/extensions/cloudcredentials"
+)
+
+func TestCloudCredentialSecretData(t *testing.T) {
+	data, err := cloudCredentialSecretData("eks", cloudcredentials.CloudCredential{
+		AmazonEC2CredentialConfig: &cloudcredentials.AmazonEC2CredentialConfig{AccessKey: "new-key", SecretKey: "new-secret", DefaultRegion: "us-west-2"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(data) != 2 || string(data["amazonec2credentialConfig-accessKey"]) != "new-key" || string(data["amazonec2credentialConfig-secretKey"]) != "new-secret" {
+		t.Errorf("unexpected eks secret data %v", data)
+	}
+
+	data, err = cloudCredentialSecretData("gke", cloudcredentials.CloudCredential{
+		GoogleCredentialConfig: &cloudcredentials.GoogleCredentialConfig{AuthEncodedJSON: "{}"},
+	})
+	if err != nil || string(data["googlecredentialConfig-authEncodedJson"]) != "{}" {
+		t.Errorf("unexpected gke secret data %v: %v", data, err)
+	}
+
+	// the config of another provider must not be written to the secret
+	if _, err = cloudCredentialSecretData("aks", cloudcredentials.CloudCredential{
+		GoogleCredentialConfig: &cloudcredentials.GoogleCredentialConfig{AuthEncodedJSON: "{}"},
+	}); err == nil {
+		t.Error("expected an error for an aks credential without azure config")
+	}
+}
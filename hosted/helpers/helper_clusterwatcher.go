@@ -0,0 +1,171 @@
+package helpers
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+var (
+	// clusterWatchInterval is how often a watched cluster is fetched while it is read; the reads of the same interval are served from the cache
+	clusterWatchInterval = 10 * time.Second
+	// clusterWatchIdle is how long a cluster is watched once it is no longer read
+	clusterWatchIdle = 3 * time.Minute
+)
+
+// clusterWatcher caches the latest state of a cluster, fetched by its background poller; see WatchedCluster
+type clusterWatcher struct {
+	sync.Mutex
+	fetchCluster func() (*management.Cluster, error)
+	// content is the JSON of the latest cluster fetched, copied for every read since the callers change the clusters they get
+	content   []byte
+	err       error
+	fetchedAt time.Time
+	readAt    time.Time
+	// generation is bumped once the cluster is updated, the fetches started before are not cached; see invalidateWatchedCluster
+	generation int
+	// errorState tracks the error state of the cluster, terminal is its terminal error if any; see WaitClusterToBeUpgraded
+	errorState clusterErrorState
+	terminal   error
+}
+
+// clusterWatcherKey identifies a watcher: the clusters are watched by client, since the clients of different users do not get the same fields
+type clusterWatcherKey struct {
+	client    *rancher.Client
+	clusterID string
+}
+
+// clusterWatchers are the clusters watched by the process
+var clusterWatchers = struct {
+	sync.Mutex
+	byKey map[clusterWatcherKey]*clusterWatcher
+}{byKey: map[clusterWatcherKey]*clusterWatcher{}}
+
+// store caches the cluster fetched, or the error of the fetch, unless the cluster was updated since the fetch was started at the given generation:
+// the cluster fetched may be the one before the update
+func (w *clusterWatcher) store(cluster *management.Cluster, err error, generation int) {
+	var content []byte
+	if err == nil {
+		content, err = json.Marshal(cluster)
+	}
+	w.Lock()
+	defer w.Unlock()
+	if generation != w.generation {
+		return
+	}
+	w.content, w.err, w.fetchedAt = content, err, time.Now()
+	if err == nil {
+		w.terminal = w.errorState.observe(cluster, w.fetchedAt, runConfig.FailFastAfter)
+	}
+}
+
+// fetch fetches the cluster and caches it; the state of the cluster is recorded in the timeline of RecordClusterTransitions
+func (w *clusterWatcher) fetch() {
+	w.Lock()
+	generation := w.generation
+	w.Unlock()
+	cluster, err := w.fetchCluster()
+	if err == nil {
+		ObserveCluster(cluster)
+	}
+	w.store(cluster, err, generation)
+}
+
+// read returns a copy of the cached cluster; it is fetched first if the poller did not fetch it for a while, for e.g. since the last update
+func (w *clusterWatcher) read() (*management.Cluster, error) {
+	w.Lock()
+	w.readAt = time.Now()
+	w.Unlock()
+	for {
+		w.Lock()
+		stale := time.Since(w.fetchedAt) > 2*clusterWatchInterval
+		w.Unlock()
+		if !stale {
+			break
+		}
+		// fetched again if the cluster is updated meanwhile
+		w.fetch()
+	}
+
+	w.Lock()
+	defer w.Unlock()
+	if w.err != nil {
+		return nil, w.err
+	}
+	cluster := &management.Cluster{}
+	if err := json.Unmarshal(w.content, cluster); err != nil {
+		return nil, err
+	}
+	if w.terminal != nil && failFast() {
+		// the cluster is returned along with the error, so that the callers keep it for its deletion
+		return cluster, w.terminal
+	}
+	return cluster, nil
+}
+
+// poll fetches the cluster every clusterWatchInterval while it is read, and stops once it was not read for clusterWatchIdle
+func (w *clusterWatcher) poll(key clusterWatcherKey) {
+	ticker := time.NewTicker(clusterWatchInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		w.Lock()
+		idle := time.Since(w.readAt) > clusterWatchIdle
+		read := w.readAt.After(w.fetchedAt)
+		w.Unlock()
+
+		if idle {
+			clusterWatchers.Lock()
+			if clusterWatchers.byKey[key] == w {
+				delete(clusterWatchers.byKey, key)
+			}
+			clusterWatchers.Unlock()
+			return
+		}
+		if read {
+			w.fetch()
+		}
+	}
+}
+
+/*
+WatchedCluster returns the latest state of a cluster from the cache of its background poller, instead of fetching it: every watched cluster
+is fetched once every 10s at most while it is read, however many checks poll it, which must be preferred in the functions polled by Eventually,
+for e.g. cluster, err = helpers.WatchedCluster(client, cluster.ID). The poller is started by the first read and stops once the cluster is no longer read;
+the cluster is fetched again on the next read once it is updated with UpdateRancherCluster. Once the cluster enters a terminal error state,
+the error is returned along with the cluster so that the checks polling it fail at once; see WaitClusterToBeUpgraded.
+  - @param client Rancher client
+  - @param clusterID ID of the cluster
+  - @returns A copy of the latest state of the cluster, and the error of its last fetch or its terminal *ClusterError
+*/
+func WatchedCluster(client *rancher.Client, clusterID string) (*management.Cluster, error) {
+	key := clusterWatcherKey{client: client, clusterID: clusterID}
+	clusterWatchers.Lock()
+	watcher, found := clusterWatchers.byKey[key]
+	if !found {
+		watcher = &clusterWatcher{fetchCluster: func() (*management.Cluster, error) { return client.Management.Cluster.ByID(clusterID) }}
+		clusterWatchers.byKey[key] = watcher
+		go watcher.poll(key)
+	}
+	clusterWatchers.Unlock()
+	return watcher.read()
+}
+
+// invalidateWatchedCluster drops the cached state of the cluster, so that it is fetched on its next read, and gives it the whole time to recover
+// from its error; it is called once the cluster is updated
+func invalidateWatchedCluster(clusterID string) {
+	clusterWatchers.Lock()
+	defer clusterWatchers.Unlock()
+	for key, watcher := range clusterWatchers.byKey {
+		if key.clusterID == clusterID {
+			watcher.Lock()
+			watcher.fetchedAt = time.Time{}
+			watcher.generation++
+			watcher.errorState.reset()
+			watcher.terminal = nil
+			watcher.Unlock()
+		}
+	}
+}
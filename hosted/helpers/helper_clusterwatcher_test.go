@@ -0,0 +1,79 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+func TestClusterWatcher(t *testing.T) {
+	fetches := 0
+	var fetchErr error
+	watcher := &clusterWatcher{fetchCluster: func() (*management.Cluster, error) {
+		fetches++
+		return &management.Cluster{Name: "c-m-abc12", State: "active", Labels: map[string]string{"owner": "e2e"}}, fetchErr
+	}}
+
+	// the reads are served from the cache, with a copy of the cluster
+	for i := 0; i < 3; i++ {
+		cluster, err := watcher.read()
+		if err != nil || cluster.State != "active" || cluster.Labels["owner"] != "e2e" {
+			t.Fatalf("read() = %+v, %v", cluster, err)
+		}
+		cluster.State, cluster.Labels["owner"] = "updating", "changed"
+	}
+	if fetches != 1 {
+		t.Errorf("got %d fetches, want the cluster to be fetched once", fetches)
+	}
+
+	// once invalidated, the cluster is fetched on the next read, and the errors are returned
+	key := clusterWatcherKey{clusterID: "c-m-abc12"}
+	clusterWatchers.Lock()
+	clusterWatchers.byKey[key] = watcher
+	clusterWatchers.Unlock()
+	defer func() {
+		clusterWatchers.Lock()
+		delete(clusterWatchers.byKey, key)
+		clusterWatchers.Unlock()
+	}()
+	invalidateWatchedCluster("c-m-abc12")
+	fetchErr = errors.New("rancher is not reachable")
+	if _, err := watcher.read(); err == nil {
+		t.Error("read() must return the error of the fetch")
+	}
+	if fetches != 2 {
+		t.Errorf("got %d fetches, want the invalidated cluster to be fetched again", fetches)
+	}
+}
+
+func TestClusterWatcherUpdatedDuringFetch(t *testing.T) {
+	fetches := 0
+	var watcher *clusterWatcher
+	watcher = &clusterWatcher{fetchCluster: func() (*management.Cluster, error) {
+		fetches++
+		if fetches == 1 {
+			// the cluster is updated while the poller fetches it, the cluster fetched is the one before the update
+			invalidateWatchedCluster("c-m-def34")
+			return &management.Cluster{Name: "c-m-def34", State: "active"}, nil
+		}
+		return &management.Cluster{Name: "c-m-def34", State: "updating"}, nil
+	}}
+	key := clusterWatcherKey{clusterID: "c-m-def34"}
+	clusterWatchers.Lock()
+	clusterWatchers.byKey[key] = watcher
+	clusterWatchers.Unlock()
+	defer func() {
+		clusterWatchers.Lock()
+		delete(clusterWatchers.byKey, key)
+		clusterWatchers.Unlock()
+	}()
+
+	watcher.fetch()
+	if cluster, err := watcher.read(); err != nil || cluster.State != "updating" {
+		t.Errorf("read() = %+v, %v, want the cluster fetched after the update", cluster, err)
+	}
+	if fetches != 2 {
+		t.Errorf("got %d fetches, want the cluster to be fetched again after the update", fetches)
+	}
+}
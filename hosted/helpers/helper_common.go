@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"encoding/json"
 	"fmt"
 	"net"
 	"os"
@@ -14,11 +15,7 @@ import (
 	"github.com/rancher/rancher/tests/v2/actions/pipeline"
 	"github.com/rancher/shepherd/clients/rancher"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	v1 "github.com/rancher/shepherd/clients/rancher/v1"
 	"github.com/rancher/shepherd/extensions/cloudcredentials"
-	"github.com/rancher/shepherd/extensions/cloudcredentials/aws"
-	"github.com/rancher/shepherd/extensions/cloudcredentials/azure"
-	"github.com/rancher/shepherd/extensions/cloudcredentials/google"
 	shepherdclusters "github.com/rancher/shepherd/extensions/clusters"
 	"github.com/rancher/shepherd/extensions/defaults"
 	nodestat "github.com/rancher/shepherd/extensions/nodes"
@@ -28,7 +25,6 @@ import (
 	"github.com/rancher/shepherd/pkg/config"
 	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 	"github.com/rancher/shepherd/pkg/session"
-	"github.com/rancher/shepherd/pkg/wait"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 )
@@ -36,6 +32,41 @@ import (
 func CommonSynchronizedBeforeSuite() {
 	ginkgo.GinkgoLogr.Info("Using Common SynchronizedBeforeSuite ...")
 
+	setupSecrets(nil)
+	Expect(GenerateCattleConfig()).To(Succeed())
+	PreflightChecks()
+	AcquireCloudCredentials(Provider)
+	setupRancher()
+	updateCredentialsConfig(Provider)
+}
+
+// MultiProviderSynchronizedBeforeSuite is the CommonSynchronizedBeforeSuite of the multi-provider suites;
+// the credentials of all the MultiProviders are written to the cattle config file.
+func MultiProviderSynchronizedBeforeSuite() {
+	ginkgo.GinkgoLogr.Info("Using Multi-Provider SynchronizedBeforeSuite ...")
+
+	setupSecrets(nil)
+	Expect(GenerateCattleConfig()).To(Succeed())
+	MultiProviderPreflightChecks()
+	AcquireCloudCredentials(MultiProviders...)
+	setupRancher()
+	for _, provider := range MultiProviders {
+		updateCredentialsConfig(provider)
+	}
+}
+
+// CommonSynchronizedAfterSuite is the cleanup of CommonSynchronizedBeforeSuite and MultiProviderSynchronizedBeforeSuite, run once all the parallel processes are done;
+// the suites register it with `var _ = SynchronizedAfterSuite(func() {}, helpers.CommonSynchronizedAfterSuite)`
+func CommonSynchronizedAfterSuite() {
+	ginkgo.GinkgoLogr.Info("Using Common SynchronizedAfterSuite ...")
+
+	CleanupLeakedClusters()
+	// after the janitor, which deletes the leaked clusters with the minted credentials
+	RevokeCloudCredentials()
+}
+
+// setupRancher creates the admin token of the cattle config file, and sets up the airgap and feature flags of Rancher if needed
+func setupRancher() {
 	rancherConfig := new(rancher.Config)
 
 	// Attempt at manually loading and updating the rancher config to avoid `nil map entry assignment`
@@ -50,7 +81,24 @@ func CommonSynchronizedBeforeSuite() {
 
 	config.UpdateConfig(rancher.ConfigurationFileKey, rancherConfig)
 
-	switch Provider {
+	featureFlags, err := ParseFeatureFlags(runConfig.FeatureFlags)
+	Expect(err).To(BeNil())
+	if IsAirgap() || len(featureFlags) > 0 {
+		// Done only once for all the parallel processes
+		rancherAdminClient, err := rancher.NewClient(rancherConfig.AdminToken, session.NewSession())
+		Expect(err).To(BeNil())
+		ProfileClient(rancherAdminClient)
+		if IsAirgap() {
+			SetupAirgap(rancherAdminClient)
+		}
+		// the flags are left as they are after the run, like the rest of the rancher setup
+		SetFeatureFlags(rancherAdminClient, featureFlags)
+	}
+}
+
+// updateCredentialsConfig writes the credentials of the provider, read from the env, to the cattle config file
+func updateCredentialsConfig(provider string) {
+	switch provider {
 	case "aks":
 		credentialConfig := new(cloudcredentials.AzureCredentialConfig)
 		config.LoadAndUpdateConfig("azureCredentials", credentialConfig, func() {
@@ -72,18 +120,144 @@ func CommonSynchronizedBeforeSuite() {
 			credentialConfig.AuthEncodedJSON = os.Getenv("GCP_CREDENTIALS")
 		})
 	}
-
 }
 
 func CommonBeforeSuite() RancherContext {
 	ginkgo.GinkgoLogr.Info("Using Common BeforeSuite ...")
 
+	setupSecrets(nil)
+	exportMintedCredentials(Provider)
+	ctx := newRancherContext()
+	cloudCredID, err := CreateCloudCredentials(ctx.RancherAdminClient)
+	Expect(err).To(BeNil())
+	ctx.CloudCredID = cloudCredID
+	RecordRunMetadata(ctx.RancherAdminClient, Provider)
+	return ctx
+}
+
+// SuiteSetup is the setup done once by the first parallel process of a suite and shared with all of them, see ParallelSynchronizedBeforeSuite
+type SuiteSetup struct {
+	CloudCredID string `json:"cloudCredID"`
+	// CloudCredIDs holds the cloud credentials by provider of the multi-provider suites, see MultiProviderParallelSynchronizedBeforeSuite
+	CloudCredIDs map[string]string `json:"cloudCredIDs,omitempty"`
+	// Secrets are the secrets fetched by the first process, see LoadSecrets
+	Secrets map[string]string `json:"secrets,omitempty"`
+}
+
+// setupSecrets exports the secrets passed by the first parallel process, or loads them if none were passed, see LoadSecrets
+func setupSecrets(secrets map[string]string) {
+	if len(secrets) > 0 && !secretsLoaded {
+		Expect(applySecrets(secrets)).To(Succeed())
+	}
+	_, err := LoadSecrets()
+	Expect(err).To(BeNil(), fmt.Sprintf("Failed to load the secrets with SECRETS_PROVIDER=%s", runConfig.SecretsProvider))
+}
+
+/*
+ParallelSynchronizedBeforeSuite is the CommonSynchronizedBeforeSuite of the suites run with `ginkgo -p`: along with the Rancher setup,
+the cloud credential is created once and shared by all the parallel processes instead of one per process, along with the secrets fetched
+by LoadSecrets. It is used with ParallelBeforeSuite,
+for e.g. SynchronizedBeforeSuite(helpers.ParallelSynchronizedBeforeSuite, func(setup []byte) { ctx = helpers.ParallelBeforeSuite(setup) }).
+  - @returns The encoded SuiteSetup, passed by Ginkgo to ParallelBeforeSuite on every process
+*/
+func ParallelSynchronizedBeforeSuite() []byte {
+	CommonSynchronizedBeforeSuite()
+
+	rancherConfig := new(rancher.Config)
+	config.LoadConfig(rancher.ConfigurationFileKey, rancherConfig)
+	rancherAdminClient, err := rancher.NewClient(rancherConfig.AdminToken, session.NewSession())
+	Expect(err).To(BeNil())
+	cloudCredID, err := CreateCloudCredentials(rancherAdminClient)
+	Expect(err).To(BeNil())
+
+	setup, err := json.Marshal(SuiteSetup{CloudCredID: cloudCredID, Secrets: loadedSecrets})
+	Expect(err).To(BeNil())
+	return setup
+}
+
+/*
+ParallelBeforeSuite is the CommonBeforeSuite of the suites run with `ginkgo -p`, see ParallelSynchronizedBeforeSuite:
+every process gets its own admin client and token, and the cloud credential created by the first process.
+  - @param setup The encoded SuiteSetup returned by ParallelSynchronizedBeforeSuite
+  - @returns The Rancher context of the process
+*/
+func ParallelBeforeSuite(setup []byte) RancherContext {
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Using Parallel BeforeSuite on process %d ...", ginkgo.GinkgoParallelProcess()))
+
+	var suiteSetup SuiteSetup
+	Expect(json.Unmarshal(setup, &suiteSetup)).To(Succeed())
+	Expect(suiteSetup.CloudCredID).NotTo(BeEmpty())
+	setupSecrets(suiteSetup.Secrets)
+
+	exportMintedCredentials(Provider)
+	ctx := newRancherContext()
+	ctx.CloudCredID = suiteSetup.CloudCredID
+	RecordRunMetadata(ctx.RancherAdminClient, Provider)
+	return ctx
+}
+
+/*
+MultiProviderParallelSynchronizedBeforeSuite is the ParallelSynchronizedBeforeSuite of the multi-provider suites: along with the Rancher setup,
+a cloud credential is created once for each of the MultiProviders and shared by all the parallel processes. It is used with MultiProviderParallelBeforeSuite,
+for e.g. SynchronizedBeforeSuite(helpers.MultiProviderParallelSynchronizedBeforeSuite, func(setup []byte) { ctx = helpers.MultiProviderParallelBeforeSuite(setup) }).
+  - @returns The encoded SuiteSetup, passed by Ginkgo to MultiProviderParallelBeforeSuite on every process
+*/
+func MultiProviderParallelSynchronizedBeforeSuite() []byte {
+	MultiProviderSynchronizedBeforeSuite()
+
+	rancherConfig := new(rancher.Config)
+	config.LoadConfig(rancher.ConfigurationFileKey, rancherConfig)
+	rancherAdminClient, err := rancher.NewClient(rancherConfig.AdminToken, session.NewSession())
+	Expect(err).To(BeNil())
+	suiteSetup := SuiteSetup{CloudCredIDs: map[string]string{}, Secrets: loadedSecrets}
+	for _, provider := range MultiProviders {
+		cloudCredID, err := CreateProviderCloudCredentials(rancherAdminClient, provider)
+		Expect(err).To(BeNil())
+		suiteSetup.CloudCredIDs[provider] = cloudCredID
+	}
+
+	setup, err := json.Marshal(suiteSetup)
+	Expect(err).To(BeNil())
+	return setup
+}
+
+/*
+MultiProviderParallelBeforeSuite is the ParallelBeforeSuite of the multi-provider suites, see MultiProviderParallelSynchronizedBeforeSuite:
+every process gets its own admin client and token, and the cloud credentials of the MultiProviders created by the first process.
+  - @param setup The encoded SuiteSetup returned by MultiProviderParallelSynchronizedBeforeSuite
+  - @returns The Rancher context of the process
+*/
+func MultiProviderParallelBeforeSuite(setup []byte) RancherContext {
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Using Multi-Provider Parallel BeforeSuite on process %d ...", ginkgo.GinkgoParallelProcess()))
+
+	var suiteSetup SuiteSetup
+	Expect(json.Unmarshal(setup, &suiteSetup)).To(Succeed())
+	for _, provider := range MultiProviders {
+		Expect(suiteSetup.CloudCredIDs[provider]).NotTo(BeEmpty(), "No cloud credential was created for %s", provider)
+	}
+	setupSecrets(suiteSetup.Secrets)
+
+	exportMintedCredentials(MultiProviders...)
+	ctx := newRancherContext()
+	ctx.CloudCredIDs = suiteSetup.CloudCredIDs
+	RecordRunMetadata(ctx.RancherAdminClient, MultiProviders...)
+	return ctx
+}
+
+// newRancherContext returns the context of the admin client, without cloud credential
+func newRancherContext() RancherContext {
 	rancherConfig := new(rancher.Config)
 	config.LoadConfig(rancher.ConfigurationFileKey, rancherConfig)
 
 	testSession := session.NewSession()
 	rancherAdminClient, err := rancher.NewClient(rancherConfig.AdminToken, testSession)
 	Expect(err).To(BeNil())
+	ProfileClient(rancherAdminClient)
+
+	// Switch to a long-lived token that is renewed before it expires
+	tokenManager, err := NewTokenManager(rancherAdminClient, testSession)
+	Expect(err).To(BeNil())
+	activeTokenManager = tokenManager
 
 	// This is done by testhelpers already
 	//setting := new(management.Setting)
@@ -95,20 +269,32 @@ func CommonBeforeSuite() RancherContext {
 	//_, err = rancherAdminClient.Management.Setting.Update(resp, setting)
 	//Expect(err).To(BeNil())
 
-	cloudCredID, err := CreateCloudCredentials(rancherAdminClient)
-	Expect(err).To(BeNil())
-
 	return RancherContext{
 		RancherAdminClient: rancherAdminClient,
 		Session:            testSession,
 		ClusterCleanup:     clusterCleanup,
-		CloudCredID:        cloudCredID,
+		TokenManager:       tokenManager,
 	}
 }
 
 func CreateStdUserClient(ctx *RancherContext) {
 	ginkgo.GinkgoLogr.Info("Creating Std User client ...")
 
+	stdUser, stdUserClient := NewStdUserClient(ctx.RancherAdminClient)
+	cloudCredID, err := CreateCloudCredentials(stdUserClient)
+	Expect(err).To(BeNil())
+
+	if ctx.TokenManager != nil {
+		ctx.TokenManager.TrackStdUser(stdUser, stdUserClient)
+	}
+
+	ctx.StdUserClient = stdUserClient
+	ctx.CloudCredID = cloudCredID
+}
+
+// NewStdUserClient creates a user with the standard user global role and returns it along with its client; the user has no access to
+// the existing clusters until a role is bound to it, for e.g. with AddClusterMember
+func NewStdUserClient(client *rancher.Client) (*management.User, *rancher.Client) {
 	var stduser = namegen.AppendRandomString("stduser-")
 	var stduserpassword = password.GenerateUserPassword("testpass-")
 	newuser := &management.User{
@@ -118,24 +304,21 @@ func CreateStdUserClient(ctx *RancherContext) {
 		Enabled:  pointer.Bool(true),
 	}
 
-	stdUser, err := users.CreateUserWithRole(ctx.RancherAdminClient, newuser, "user")
+	stdUser, err := users.CreateUserWithRole(client, newuser, "user")
 	Expect(err).To(BeNil())
 
 	stdUser.Password = newuser.Password
-	stdUserClient, err := ctx.RancherAdminClient.AsUser(stdUser)
-	Expect(err).To(BeNil())
-
-	cloudCredID, err := CreateCloudCredentials(stdUserClient)
+	stdUserClient, err := client.AsUser(stdUser)
 	Expect(err).To(BeNil())
-
-	ctx.StdUserClient = stdUserClient
-	ctx.CloudCredID = cloudCredID
+	ProfileClient(stdUserClient)
+	return stdUser, stdUserClient
 }
 
 // WaitUntilClusterIsReady waits until the cluster is in a Ready state,
 // fetch the cluster again once it's ready so that it has everything up to date and then return it.
 // For e.g. once the cluster has been updated, it contains information such as Version.GitVersion which it does not have before it's ready
 // If the cluster is imported; it also updates the ProviderConfig with ProviderStatus.UpstreamSpec data
+// The wait is aborted once the cluster enters a terminal error state, see WaitClusterToBeUpgraded
 func WaitUntilClusterIsReady(cluster *management.Cluster, client *rancher.Client) (*management.Cluster, error) {
 	opts := metav1.ListOptions{FieldSelector: "metadata.name=" + cluster.ID, TimeoutSeconds: &defaults.WatchTimeoutSeconds}
 	watchInterface, err := client.GetManagementWatchInterface(management.ClusterType, opts)
@@ -145,7 +328,10 @@ func WaitUntilClusterIsReady(cluster *management.Cluster, client *rancher.Client
 
 	watchFunc := shepherdclusters.IsHostedProvisioningClusterReady
 
-	err = wait.WatchWait(watchInterface, watchFunc)
+	err = waitFailFast(client, cluster.ID, func(abort <-chan struct{}) error {
+		return watchWait(abort, watchInterface, watchFunc)
+	})
+	recordClusterReady(cluster.ID, err)
 	if err != nil {
 		return cluster, err
 	}
@@ -159,20 +345,36 @@ func WaitUntilClusterIsReady(cluster *management.Cluster, client *rancher.Client
 	// Workaround to null values in ProviderConfig for an imported cluster
 	// Ref: https://github.com/rancher/aks-operator/issues/251 (won't fix)
 	if IsImport {
-		switch Provider {
-		case "aks":
-			updatedCluster.AKSConfig = updatedCluster.AKSStatus.UpstreamSpec
-		case "gke":
-			updatedCluster.GKEConfig = updatedCluster.GKEStatus.UpstreamSpec
-		case "eks":
-			updatedCluster.EKSConfig = updatedCluster.EKSStatus.UpstreamSpec
-		}
+		useUpstreamSpec(updatedCluster)
 	}
 	return updatedCluster, nil
 
 }
 
-// ClusterIsReadyChecks runs the basic checks on a cluster such as cluster name, service account, nodes and pods check
+// useUpstreamSpec replaces the provider config of an imported cluster with its upstream spec, so that it can be updated by the helpers
+func useUpstreamSpec(cluster *management.Cluster) {
+	switch Provider {
+	case "aks":
+		cluster.AKSConfig = cluster.AKSStatus.UpstreamSpec
+	case "gke":
+		cluster.GKEConfig = cluster.GKEStatus.UpstreamSpec
+	case "eks":
+		cluster.EKSConfig = cluster.EKSStatus.UpstreamSpec
+	}
+}
+
+// WaitUntilClusterIsRemoved waits until the cluster object is removed from Rancher, i.e. once its finalizers are done;
+// for an imported cluster, the cloud cluster is left untouched and can be imported again afterwards, while for a provisioned cluster
+// it is deleted by the operator first, which can take a while.
+func WaitUntilClusterIsRemoved(client *rancher.Client, clusterID string) {
+	EventuallyWithBackoff(func() bool {
+		_, err := client.Management.Cluster.ByID(clusterID)
+		return err != nil && strings.Contains(err.Error(), "not found")
+	}, tools.SetTimeout(30*time.Minute), 5*time.Second).Should(BeTrue(), fmt.Sprintf("Cluster %s was not removed from Rancher", clusterID))
+}
+
+// ClusterIsReadyChecks runs the basic checks on a cluster such as cluster name, service account, nodes (and their conditions, with client-go) and pods check,
+// along with the provisioning.cattle.io cluster the UI reads it from, see CheckProvisioningCluster
 func ClusterIsReadyChecks(cluster *management.Cluster, client *rancher.Client, clusterName string) {
 
 	ginkgo.By("checking cluster name is same", func() {
@@ -190,21 +392,35 @@ func ClusterIsReadyChecks(cluster *management.Cluster, client *rancher.Client, c
 		Expect(err).To(BeNil())
 	})
 
+	ginkgo.By("checking the node conditions", func() {
+		CheckDownstreamNodes(client, cluster)
+	})
+
 	ginkgo.By("checking all pods are ready", func() {
-		Eventually(func() []error {
+		EventuallyWithBackoff(func() []error {
 			return pods.StatusPods(client, cluster.ID)
 		}, tools.SetTimeout(Timeout), 30*time.Second).Should(BeEmpty(), "All pods are not running")
 	})
+
+	CheckProvisioningCluster(client, cluster)
+
+	if IsAirgap() {
+		CheckImagesFromPrivateRegistry(client, cluster.ID)
+	}
+
+	if IsRancherPrivateCA() {
+		CheckRancherPrivateCA(client, cluster.ID)
+	}
 }
 
 // GetGKEZone fetches the value of GKE zone;
 // it first obtains the value from env var GKE_ZONE, if the value is empty, it fetches the information from config file(cattle_config-import.yaml/cattle_config-provisioning.yaml)
 // if none of the sources can provide a value, it returns the default value
 func GetGKEZone() string {
-	zone := os.Getenv("GKE_ZONE")
+	zone := runConfig.GKEZone
 	if zone == "" {
 		gkeConfig := new(management.GKEClusterConfigSpec)
-		config.LoadConfig("gkeClusterConfig", gkeConfig)
+		loadCattleConfig("gkeClusterConfig", gkeConfig)
 		if gkeConfig.Zone != "" {
 			zone = gkeConfig.Zone
 		}
@@ -219,10 +435,10 @@ func GetGKEZone() string {
 // it first obtains the value from env var GKE_REGION, if the value is empty, it fetches the information from config file(cattle_config-provisioning.yaml)
 // if none of the sources can provide a value, it returns the default value
 func GetGKERegion() string {
-	region := os.Getenv("GKE_REGION")
+	region := runConfig.GKERegion
 	if region == "" {
 		gkeConfig := new(management.GKEClusterConfigSpec)
-		config.LoadConfig("gkeClusterConfig", gkeConfig)
+		loadCattleConfig("gkeClusterConfig", gkeConfig)
 		if gkeConfig.Region != "" {
 			region = gkeConfig.Region
 		}
@@ -237,10 +453,10 @@ func GetGKERegion() string {
 // it first obtains the value from env var AKS_REGION, if the value is empty, it fetches the information from config file(cattle_config-import.yaml/cattle_config-provisioning.yaml)
 // if none of the sources can provide a value, it returns the default value
 func GetAKSLocation() string {
-	region := os.Getenv("AKS_REGION")
+	region := runConfig.AKSRegion
 	if region == "" {
 		aksClusterConfig := new(management.AKSClusterConfigSpec)
-		config.LoadConfig("aksClusterConfig", aksClusterConfig)
+		loadCattleConfig("aksClusterConfig", aksClusterConfig)
 		region = aksClusterConfig.ResourceLocation
 		if region == "" {
 			region = "centralindia"
@@ -253,10 +469,10 @@ func GetAKSLocation() string {
 // it first obtains the value from env var EKS_REGION, if the value is empty, it fetches the information from config file(cattle_config-import.yaml/cattle_config-provisioning.yaml)
 // if none of the sources can provide a value, it returns the default value
 func GetEKSRegion() string {
-	region := os.Getenv("EKS_REGION")
+	region := runConfig.EKSRegion
 	if region == "" {
 		eksClusterConfig := new(management.EKSClusterConfigSpec)
-		config.LoadConfig("eksClusterConfig", eksClusterConfig)
+		loadCattleConfig("eksClusterConfig", eksClusterConfig)
 		region = eksClusterConfig.Region
 		if region == "" {
 			region = "ap-south-1"
@@ -267,10 +483,11 @@ func GetEKSRegion() string {
 
 // GetGKEProjectID returns the value of GKE project by fetching the value of env var GKE_PROJECT_ID
 func GetGKEProjectID() string {
-	return os.Getenv("GKE_PROJECT_ID")
+	return runConfig.GKEProjectID
 }
 
-// GetCommonMetadataLabels returns a list of common metadata labels/tabs
+// GetCommonMetadataLabels returns a list of common metadata labels/tabs identifying the owner, the run and the spec creating the resource;
+// the values comply with the label/tag policy of the provider
 func GetCommonMetadataLabels() map[string]string {
 	specReport := ginkgo.CurrentSpecReport()
 	// filename indicates the filename and line number of the test
@@ -288,8 +505,15 @@ func GetCommonMetadataLabels() map[string]string {
 	}
 
 	metadataLabels := map[string]string{
-		"owner":          "hosted-providers-qa-ci-" + testuser.Username,
+		"owner":          sanitizeLabelValue(runConfig.Owner),
 		"testfilenumber": filename,
+		"run-id":         sanitizeLabelValue(runConfig.RunID),
+	}
+	if runConfig.PipelineURL != "" {
+		metadataLabels["pipeline-url"] = sanitizeLabelValue(runConfig.PipelineURL)
+	}
+	if id := CorrelationID(); id != "" {
+		metadataLabels[correlationIDLabel] = id
 	}
 
 	if !clusterCleanup {
@@ -300,23 +524,11 @@ func GetCommonMetadataLabels() map[string]string {
 	return metadataLabels
 }
 
-func SetTempKubeConfig(clusterName string) {
-	downstreamKubeconfig := os.Getenv(DownstreamKubeconfig(clusterName))
-	if downstreamKubeconfig == "" {
-		tmpKubeConfig, err := os.CreateTemp("", clusterName)
-		Expect(err).To(BeNil())
-		downstreamKubeconfig = tmpKubeConfig.Name()
-		_ = os.Setenv(DownstreamKubeconfig(clusterName), downstreamKubeconfig)
-	}
-	_ = os.Setenv("KUBECONFIG", downstreamKubeconfig)
-}
-
 // HighestK8sMinorVersionSupportedByUI returns the highest k8s version supported by UI
 // TODO(pvala): Use this by default when fetching a list of k8s version for all the downstream providers.
 func HighestK8sMinorVersionSupportedByUI(client *rancher.Client) (value string) {
-	uiValue, err := client.Management.Setting.ByID("ui-k8s-default-version-range")
+	value, err := cachedSettingValue(client, "ui-k8s-default-version-range")
 	Expect(err).To(BeNil())
-	value = uiValue.Value
 	Expect(value).ToNot(BeEmpty())
 	value = strings.TrimPrefix(value, "<=v")
 	value = strings.TrimSuffix(value, ".x")
@@ -360,27 +572,9 @@ func DefaultK8sVersion(descVersions []string, forUpgrade bool) (string, error) {
 }
 
 func CreateCloudCredentials(client *rancher.Client) (string, error) {
-	var (
-		err                   error
-		cloudCredential       *v1.SteveAPIObject
-		cloudCredentialConfig cloudcredentials.CloudCredential
-	)
-
-	switch Provider {
-	case "aks":
-		cloudCredentialConfig = cloudcredentials.LoadCloudCredential("azure")
-		cloudCredential, err = azure.CreateAzureCloudCredentials(client, cloudCredentialConfig)
-		Expect(err).To(BeNil())
-	case "eks":
-		cloudCredentialConfig = cloudcredentials.LoadCloudCredential("aws")
-		cloudCredential, err = aws.CreateAWSCloudCredentials(client, cloudCredentialConfig)
-		Expect(err).To(BeNil())
-	case "gke":
-		cloudCredentialConfig = cloudcredentials.LoadCloudCredential("google")
-		cloudCredential, err = google.CreateGoogleCloudCredentials(client, cloudCredentialConfig)
-		Expect(err).To(BeNil())
-	}
-	return fmt.Sprintf("%s:%s", cloudCredential.Namespace, cloudCredential.Name), nil
+	cloudCredID, err := CreateCloudCredentialsForProfile(client, "")
+	Expect(err).To(BeNil())
+	return cloudCredID, nil
 }
 
 // Returns Rancher ipv4 address based on hostname
@@ -418,9 +612,5 @@ func GetRancherVersions(rancherFullVersion string) (string, string, string) {
 
 // GetRancherServerVersion returns the value of `server-version` Setting
 func GetRancherServerVersion(client *rancher.Client) (string, error) {
-	serverVersion, err := client.Management.Setting.ByID("server-version")
-	if err != nil {
-		return "", err
-	}
-	return serverVersion.Value, nil
+	return cachedSettingValue(client, "server-version")
 }
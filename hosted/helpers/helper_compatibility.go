@@ -0,0 +1,123 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"sigs.k8s.io/yaml"
+)
+
+// CompatibilityEntry is a Rancher version of the compatibility matrix, along with the operator chart versions it must work with
+type CompatibilityEntry struct {
+	// Rancher is the Rancher version to install, in the RANCHER_VERSION format, for e.g. latest/2.10.3
+	Rancher string `json:"rancher"`
+	// OperatorCharts are semver constraints of the operator chart versions, for e.g. "105.x"; the highest matching version of the chart repository is installed
+	OperatorCharts []string `json:"operatorCharts"`
+}
+
+// LoadCompatibilityMatrix reads and validates the compatibility matrix of the operator charts, for e.g. COMPATIBILITY_MATRIX
+func LoadCompatibilityMatrix(path string) ([]CompatibilityEntry, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var matrix []CompatibilityEntry
+	if err = yaml.UnmarshalStrict(content, &matrix); err != nil {
+		return nil, err
+	}
+	if len(matrix) == 0 {
+		return nil, fmt.Errorf("compatibility matrix %s is empty", path)
+	}
+	for _, entry := range matrix {
+		if _, _, err = splitRancherVersion(entry.Rancher); err != nil {
+			return nil, err
+		}
+		if len(entry.OperatorCharts) == 0 {
+			return nil, fmt.Errorf("rancher %s has no operator chart version", entry.Rancher)
+		}
+		for _, constraint := range entry.OperatorCharts {
+			if _, err = semver.NewConstraint(constraint); err != nil {
+				return nil, fmt.Errorf("operator chart version %q of rancher %s is not valid: %w", constraint, entry.Rancher, err)
+			}
+		}
+	}
+	return matrix, nil
+}
+
+// splitRancherVersion returns the channel and the version of a Rancher version in the RANCHER_VERSION format
+func splitRancherVersion(rancherVersion string) (channel, version string, err error) {
+	channel, version, _ = strings.Cut(rancherVersion, "/")
+	if channel == "" || version == "" {
+		return "", "", fmt.Errorf("rancher version %q is not valid; the expected format is channel/version, for e.g. latest/2.10.3", rancherVersion)
+	}
+	return channel, version, nil
+}
+
+// rancherDowngrade returns true if the Rancher version of a matrix entry is lower than the original Rancher version, both in the RANCHER_VERSION format,
+// since installing it would downgrade Rancher; versions which are not released ones, for e.g. latest/devel/2.10, can not be compared and are accepted.
+func rancherDowngrade(entryVersion, originalVersion string) bool {
+	_, entry, _ := strings.Cut(entryVersion, "/")
+	_, original, _ := strings.Cut(originalVersion, "/")
+	entrySemver, err := semver.NewVersion(entry)
+	if err != nil {
+		return false
+	}
+	originalSemver, err := semver.NewVersion(original)
+	if err != nil {
+		return false
+	}
+	return entrySemver.LessThan(originalSemver)
+}
+
+// resolveChartVersion returns the highest version of the charts matching the constraint
+func resolveChartVersion(charts []HelmChart, constraint string) (string, error) {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return "", err
+	}
+	var (
+		best        string
+		bestVersion *semver.Version
+	)
+	for _, chart := range charts {
+		v, err := semver.NewVersion(chart.DerivedVersion)
+		if err != nil || !c.Check(v) {
+			continue
+		}
+		if bestVersion == nil || v.GreaterThan(bestVersion) {
+			best, bestVersion = chart.DerivedVersion, v
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("no operator chart version matches %q", constraint)
+	}
+	return best, nil
+}
+
+/*
+Install the highest operator chart version of the chart repository matching a constraint of the compatibility matrix, in place of the one
+installed by Rancher; see UpdateOperatorChartsVersion.
+  - @param constraint Operator chart version constraint, for e.g. "105.x"
+  - @returns The installed chart version; the function will fail through Ginkgo in case of issue
+*/
+func InstallOperatorChartVersion(constraint string) string {
+	// the operator charts are installed by Rancher once started
+	var charts []HelmChart
+	Eventually(func() []HelmChart {
+		charts = ListOperatorChart()
+		return charts
+	}, tools.SetTimeout(4*time.Minute), 10*time.Second).ShouldNot(BeEmpty(), "The operator charts of %s are not installed", Provider)
+	version, err := resolveChartVersion(ListChartVersions(charts[0].Name), constraint)
+	Expect(err).To(BeNil())
+
+	ginkgo.By(fmt.Sprintf("Installing the operator chart version %s, matching %s", version, constraint), func() {
+		UpdateOperatorChartsVersion(version)
+	})
+	return version
+}
@@ -0,0 +1,76 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadCompatibilityMatrix(t *testing.T) {
+	matrix, err := LoadCompatibilityMatrix("assets/compatibility-matrix.yaml")
+	if err != nil {
+		t.Fatalf("LoadCompatibilityMatrix() of the default matrix = %v", err)
+	}
+	if len(matrix) == 0 || matrix[0].Rancher == "" || len(matrix[0].OperatorCharts) == 0 {
+		t.Errorf("LoadCompatibilityMatrix() = %+v, want entries", matrix)
+	}
+
+	for content, expected := range map[string]string{
+		"[]": "is empty",
+		"- rancher: 2.10.3\n  operatorCharts: [\"105.x\"]":       "channel/version",
+		"- rancher: latest/2.10.3":                               "no operator chart version",
+		"- rancher: latest/2.10.3\n  operatorCharts: [\"next\"]": "is not valid",
+		"- rancher: latest/2.10.3\n  operatorChart: [\"105.x\"]": "unknown field",
+	} {
+		path := filepath.Join(t.TempDir(), "matrix.yaml")
+		if err = os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = LoadCompatibilityMatrix(path); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("LoadCompatibilityMatrix() of %q = %v, want an error containing %q", content, err, expected)
+		}
+	}
+}
+
+func TestResolveChartVersion(t *testing.T) {
+	charts := []HelmChart{
+		{Name: "rancher-charts/rancher-eks-operator", DerivedVersion: "106.0.0-rc.1+up1.7.0-rc.1"},
+		{Name: "rancher-charts/rancher-eks-operator", DerivedVersion: "105.1.0+up1.6.1"},
+		{Name: "rancher-charts/rancher-eks-operator", DerivedVersion: "105.0.1+up1.6.0"},
+		{Name: "rancher-charts/rancher-eks-operator", DerivedVersion: "105.0.0+up1.6.0"},
+		{Name: "rancher-charts/rancher-eks-operator-crd", DerivedVersion: "105.1.0+up1.6.1"},
+		{Name: "rancher-charts/rancher-eks-operator", DerivedVersion: "104.2.0+up1.5.2"},
+	}
+	for constraint, expected := range map[string]string{
+		"105.x":   "105.1.0+up1.6.1",
+		"105.0.x": "105.0.1+up1.6.0",
+		"<105":    "104.2.0+up1.5.2",
+	} {
+		if version, err := resolveChartVersion(charts, constraint); err != nil || version != expected {
+			t.Errorf("resolveChartVersion(%s) = %s, %v, want %s", constraint, version, err, expected)
+		}
+	}
+	// the release candidates are not matched by a release constraint
+	if version, err := resolveChartVersion(charts, "106.x"); err == nil {
+		t.Errorf("resolveChartVersion(106.x) = %s, want an error", version)
+	}
+}
+
+func TestRancherDowngrade(t *testing.T) {
+	for _, tc := range []struct {
+		entry, original string
+		downgrade       bool
+	}{
+		{"latest/2.10.3", "latest/2.9.3", false},
+		{"latest/2.10.3", "prime/2.10.3", false},
+		{"latest/2.9.3", "latest/2.10.3", true},
+		{"latest/2.10.0-rc1", "latest/2.10.0", true},
+		{"latest/devel/2.9", "latest/2.10.3", false},
+		{"latest/2.9.3", "latest/devel/2.10", false},
+	} {
+		if downgrade := rancherDowngrade(tc.entry, tc.original); downgrade != tc.downgrade {
+			t.Errorf("rancherDowngrade(%s, %s) = %t, want %t", tc.entry, tc.original, downgrade, tc.downgrade)
+		}
+	}
+}
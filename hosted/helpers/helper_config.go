@@ -0,0 +1,260 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/rancher/shepherd/clients/rancher"
+	"github.com/rancher/shepherd/extensions/cloudcredentials"
+	"github.com/rancher/shepherd/extensions/clusters/aks"
+	"github.com/rancher/shepherd/extensions/clusters/eks"
+	"github.com/rancher/shepherd/extensions/clusters/gke"
+	"github.com/rancher/shepherd/pkg/config"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/yaml"
+)
+
+// CattleConfig is the content of the cattle test config file (CATTLE_TEST_CONFIG)
+type CattleConfig struct {
+	Rancher           *rancher.Config                             `json:"rancher"`
+	AWSCredentials    *cloudcredentials.AmazonEC2CredentialConfig `json:"awsCredentials"`
+	AzureCredentials  *cloudcredentials.AzureCredentialConfig     `json:"azureCredentials"`
+	GoogleCredentials *cloudcredentials.GoogleCredentialConfig    `json:"googleCredentials"`
+	EKSClusterConfig  *eks.ClusterConfig                          `json:"eksClusterConfig,omitempty"`
+	GKEClusterConfig  *gke.ClusterConfig                          `json:"gkeClusterConfig,omitempty"`
+	AKSClusterConfig  *aks.ClusterConfig                          `json:"aksClusterConfig,omitempty"`
+}
+
+/*
+GenerateCattleConfig writes the cattle test config of NewCattleConfig to CATTLE_TEST_CONFIG if the file does not exist; an existing file is never
+overwritten. It is called at the start of the suite by the first process, before the admin token and the credentials are written to the config,
+see CommonSynchronizedBeforeSuite.
+  - @returns The error of the generation
+*/
+func GenerateCattleConfig() error {
+	configPath := runConfig.CattleConfigPath
+	if configPath == "" {
+		// reported by the preflight checks
+		return nil
+	}
+	if _, err := os.Stat(configPath); err == nil {
+		return nil
+	}
+	content, err := yaml.Marshal(NewCattleConfig())
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(configPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err = file.Write(content); err != nil {
+		return fmt.Errorf("failed to generate the cattle config %s: %w", configPath, err)
+	}
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Generated the cattle config %s from the defaults of the helpers", configPath))
+	return nil
+}
+
+// loadCattleConfig is config.LoadConfig reading the config of NewCattleConfig while CATTLE_TEST_CONFIG is not generated yet,
+// for e.g. when the package variables of the suites are initialized; see GenerateCattleConfig
+func loadCattleConfig(key string, value any) {
+	if configPath := runConfig.CattleConfigPath; configPath != "" {
+		if _, err := os.Stat(configPath); errors.Is(err, fs.ErrNotExist) {
+			cattleConfig := map[string]any{}
+			content, err := yaml.Marshal(NewCattleConfig())
+			if err == nil {
+				err = yaml.Unmarshal(content, &cattleConfig)
+			}
+			if err == nil {
+				content, err = yaml.Marshal(cattleConfig[key])
+			}
+			if err == nil {
+				err = yaml.Unmarshal(content, value)
+			}
+			if err != nil {
+				panic(fmt.Sprintf("failed to load %s from the default cattle config: %v", key, err))
+			}
+			return
+		}
+	}
+	config.LoadConfig(key, value)
+}
+
+// NewCattleConfig returns the default cattle test config for all the providers, with the env var overrides applied;
+// credentials are left empty since they are filled by CommonSynchronizedBeforeSuite
+func NewCattleConfig() CattleConfig {
+	cattleConfig := CattleConfig{
+		Rancher: &rancher.Config{
+			Host:     RancherHostname,
+			Insecure: pointer.Bool(true),
+			Cleanup:  pointer.Bool(false),
+		},
+		AWSCredentials:    &cloudcredentials.AmazonEC2CredentialConfig{},
+		AzureCredentials:  &cloudcredentials.AzureCredentialConfig{Environment: "AzurePublicCloud"},
+		GoogleCredentials: &cloudcredentials.GoogleCredentialConfig{},
+		EKSClusterConfig:  defaultEKSClusterConfig(),
+		GKEClusterConfig:  defaultGKEClusterConfig(),
+		AKSClusterConfig:  defaultAKSClusterConfig(),
+	}
+
+	if region := os.Getenv("EKS_REGION"); region != "" {
+		cattleConfig.EKSClusterConfig.Region = region
+	}
+	if projectID := GetGKEProjectID(); projectID != "" {
+		cattleConfig.GKEClusterConfig.ProjectID = projectID
+	}
+	if zone := os.Getenv("GKE_ZONE"); zone != "" {
+		cattleConfig.GKEClusterConfig.Zone = zone
+	}
+	if region := os.Getenv("GKE_REGION"); region != "" {
+		// a regional cluster must not have a zone
+		cattleConfig.GKEClusterConfig.Region = region
+		cattleConfig.GKEClusterConfig.Zone = ""
+	}
+	if location := os.Getenv("AKS_REGION"); location != "" {
+		cattleConfig.AKSClusterConfig.ResourceLocation = location
+	}
+	return cattleConfig
+}
+
+func defaultEKSClusterConfig() *eks.ClusterConfig {
+	return &eks.ClusterConfig{
+		KmsKey:              pointer.String(""),
+		KubernetesVersion:   pointer.String("1.29"),
+		LoggingTypes:        []string{},
+		PrivateAccess:       pointer.Bool(false),
+		PublicAccess:        pointer.Bool(true),
+		PublicAccessSources: []string{},
+		Region:              "ap-south-1",
+		SecretsEncryption:   pointer.Bool(false),
+		SecurityGroups:      []string{},
+		ServiceRole:         pointer.String(""),
+		Subnets:             []string{},
+		Tags:                map[string]string{},
+		NodeGroupsConfig: &[]eks.NodeGroupConfig{
+			{
+				DesiredSize:          pointer.Int64(1),
+				DiskSize:             pointer.Int64(20),
+				Ec2SshKey:            pointer.String(""),
+				Gpu:                  pointer.Bool(false),
+				ImageID:              pointer.String(""),
+				InstanceType:         pointer.String("t3.large"),
+				Labels:               map[string]string{},
+				MaxSize:              pointer.Int64(1),
+				MinSize:              pointer.Int64(1),
+				NodeRole:             pointer.String(""),
+				NodegroupName:        pointer.String("ng"),
+				RequestSpotInstances: pointer.Bool(false),
+				ResourceTags:         map[string]string{},
+				SpotInstanceTypes:    []string{},
+				Subnets:              []string{},
+				Tags:                 map[string]string{},
+				UserData:             pointer.String(""),
+			},
+		},
+	}
+}
+
+func defaultGKEClusterConfig() *gke.ClusterConfig {
+	return &gke.ClusterConfig{
+		AutopilotConfig: &gke.AutopilotConfig{Enabled: false},
+		ClusterAddons: &gke.ClusterAddons{
+			HorizontalPodAutoscaling: true,
+			HTTPLoadBalancing:        true,
+			NetworkPolicyConfig:      false,
+		},
+		ClusterIpv4CidrBlock:  pointer.String(""),
+		EnableKubernetesAlpha: pointer.Bool(false),
+		IPAllocationPolicy: &gke.IPAllocationPolicy{
+			UseIPAliases: true,
+		},
+		KubernetesVersion: pointer.String("1.29.8-gke.1211000"),
+		Labels:            map[string]string{},
+		Locations:         []string{},
+		LoggingService:    pointer.String("logging.googleapis.com/kubernetes"),
+		MaintenanceWindow: pointer.String(""),
+		MasterAuthorizedNetworksConfig: &gke.MasterAuthorizedNetworksConfig{
+			CidrBlocks: []gke.CidrBlock{},
+			Enabled:    false,
+		},
+		MonitoringService:    pointer.String("monitoring.googleapis.com/kubernetes"),
+		Network:              pointer.String("hosted-providers-ci"),
+		NetworkPolicyEnabled: pointer.Bool(false),
+		NodePools: []gke.NodePool{
+			{
+				Autoscaling: &gke.Autoscaling{Enabled: false},
+				Config: &gke.NodeConfig{
+					DiskSizeGb:  50,
+					DiskType:    "pd-standard",
+					ImageType:   "COS_CONTAINERD",
+					Labels:      map[string]string{},
+					MachineType: "n1-standard-2",
+					OauthScopes: []string{
+						"https://www.googleapis.com/auth/devstorage.read_only",
+						"https://www.googleapis.com/auth/logging.write",
+						"https://www.googleapis.com/auth/monitoring",
+						"https://www.googleapis.com/auth/servicecontrol",
+						"https://www.googleapis.com/auth/service.management.readonly",
+						"https://www.googleapis.com/auth/trace.append",
+					},
+					Tags:   []string{},
+					Taints: []gke.NodeTaintConfig{},
+				},
+				InitialNodeCount:  pointer.Int64(1),
+				Management:        &gke.NodePoolManagement{AutoRepair: true, AutoUpgrade: true},
+				MaxPodsConstraint: pointer.Int64(110),
+				Name:              pointer.String("np"),
+				Version:           pointer.String("1.29.8-gke.1211000"),
+			},
+		},
+		PrivateClusterConfig: &gke.PrivateClusterConfig{
+			EnablePrivateEndpoint: false,
+			EnablePrivateNodes:    false,
+			MasterIpv4CidrBlock:   "",
+		},
+		Subnetwork: pointer.String("hosted-providers-ci"),
+		Zone:       "asia-south2-c",
+	}
+}
+
+func defaultAKSClusterConfig() *aks.ClusterConfig {
+	return &aks.ClusterConfig{
+		DNSPrefix:               pointer.String(""),
+		KubernetesVersion:       pointer.String("1.29.7"),
+		LinuxAdminUsername:      pointer.String("azureuser"),
+		LoadBalancerSKU:         pointer.String("Standard"),
+		NetworkDNSServiceIP:     pointer.String("10.0.0.10"),
+		NetworkDockerBridgeCIDR: pointer.String("172.17.0.1/16"),
+		NetworkPlugin:           pointer.String("kubenet"),
+		NetworkServiceCIDR:      pointer.String("10.0.0.0/16"),
+		OutboundType:            pointer.String("LoadBalancer"),
+		PrivateCluster:          pointer.Bool(false),
+		ResourceLocation:        "centralindia",
+		Tags:                    map[string]string{},
+		NodePools: &[]aks.NodePool{
+			{
+				AvailabilityZones: &[]string{"1", "2", "3"},
+				EnableAutoScaling: pointer.Bool(false),
+				MaxPods:           pointer.Int64(110),
+				Mode:              "System",
+				Name:              pointer.String("agentpool"),
+				NodeCount:         pointer.Int64(1),
+				OsDiskSizeGB:      pointer.Int64(128),
+				OsDiskType:        "Managed",
+				OsType:            "Linux",
+				VMSize:            "Standard_DS2_v2",
+			},
+		},
+	}
+}
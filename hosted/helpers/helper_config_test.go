@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rancher/shepherd/extensions/clusters/eks"
+	"sigs.k8s.io/yaml"
+)
+
+func TestGenerateCattleConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config", "cattle-config-provisioning.yaml")
+	previous := runConfig.CattleConfigPath
+	runConfig.CattleConfigPath = configPath
+	t.Cleanup(func() { runConfig.CattleConfigPath = previous })
+
+	// the defaults are read until the config is generated
+	var eksClusterConfig eks.ClusterConfig
+	loadCattleConfig(eks.EKSClusterConfigConfigurationFileKey, &eksClusterConfig)
+	if eksClusterConfig.Region != NewCattleConfig().EKSClusterConfig.Region || eksClusterConfig.NodeGroupsConfig == nil {
+		t.Errorf("got the default EKS config %+v", eksClusterConfig)
+	}
+
+	if err := GenerateCattleConfig(); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cattleConfig CattleConfig
+	if err = yaml.Unmarshal(content, &cattleConfig); err != nil || cattleConfig.EKSClusterConfig == nil || cattleConfig.Rancher == nil {
+		t.Errorf("got the config %s, %v", content, err)
+	}
+
+	// an existing config, for e.g. with the admin token written by the first process, is never overwritten
+	if err = os.WriteFile(configPath, []byte("rancher:\n  adminToken: token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err = GenerateCattleConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ = os.ReadFile(configPath); string(content) != "rancher:\n  adminToken: token\n" {
+		t.Errorf("the existing config is overwritten: %s", content)
+	}
+}
@@ -0,0 +1,67 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// correlationIDEntry is the name of the report entry holding the correlation ID of a spec execution
+const correlationIDEntry = "Correlation ID"
+
+// correlationIDLabel is the label/tag holding the correlation ID on the clusters and cloud resources, see GetCommonMetadataLabels
+const correlationIDLabel = "correlation-id"
+
+// correlation is the correlation ID of the spec execution running on this process; the specs of a process run one at a time
+var correlation = struct {
+	sync.Mutex
+	execution string
+	id        string
+}{}
+
+// specExecution identifies an execution of a spec within the process: every attempt of a spec retried with FLAKE_ATTEMPTS is a new execution
+func specExecution(spec ginkgo.SpecReport) string {
+	return fmt.Sprintf("%s:%d:%s:%d", spec.FileName(), spec.LineNumber(), spec.FullText(), spec.NumAttempts)
+}
+
+// newCorrelationID returns a random ID prefixed with the parallel process, valid as a label value for all the providers, for e.g. p2-3f2a9c0b1d4e
+func newCorrelationID(process int) string {
+	id := make([]byte, 6)
+	_, _ = rand.Read(id)
+	return fmt.Sprintf("p%d-%s", process, hex.EncodeToString(id))
+}
+
+/*
+CorrelationID returns the correlation ID of the current spec execution, generated at the first call of the execution; it is set as the correlation-id label/tag
+of the clusters by GetCommonMetadataLabels and added to the report of the spec, so that a leaked or misbehaving cloud resource can be mapped back to
+the spec execution which created it. It is empty outside of a running spec, for e.g. while the specs are being built.
+  - @returns The correlation ID, for e.g. p2-3f2a9c0b1d4e
+*/
+func CorrelationID() string {
+	spec := ginkgo.CurrentSpecReport()
+	if spec.LeafNodeType == types.NodeTypeInvalid {
+		return ""
+	}
+
+	correlation.Lock()
+	defer correlation.Unlock()
+	if execution := specExecution(spec); correlation.execution != execution {
+		correlation.execution, correlation.id = execution, newCorrelationID(ginkgo.GinkgoParallelProcess())
+		ginkgo.AddReportEntry(correlationIDEntry, correlation.id, ginkgo.ReportEntryVisibilityFailureOrVerbose)
+	}
+	return correlation.id
+}
+
+// specCorrelationID returns the correlation ID of a spec execution, see CorrelationID; empty if the spec created no resource
+func specCorrelationID(spec ginkgo.SpecReport) string {
+	for _, entry := range spec.ReportEntries {
+		if entry.Name == correlationIDEntry {
+			return entry.StringRepresentation()
+		}
+	}
+	return ""
+}
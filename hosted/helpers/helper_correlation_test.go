@@ -0,0 +1,24 @@
+package helpers
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestNewCorrelationID(t *testing.T) {
+	id := newCorrelationID(2)
+	if !regexp.MustCompile(`^p2-[0-9a-f]{12}$`).MatchString(id) {
+		t.Fatalf("unexpected correlation ID %q", id)
+	}
+	defer func(provider string) { Provider = provider }(Provider)
+	// the ID is used as is as a label value by all the providers
+	for provider := range namingPolicies {
+		Provider = provider
+		if sanitized := sanitizeLabelValue(id); sanitized != id {
+			t.Errorf("the correlation ID %q is not a valid %s label value: %q", id, provider, sanitized)
+		}
+	}
+	if other := newCorrelationID(2); other == id {
+		t.Errorf("the correlation IDs must differ: %q", id)
+	}
+}
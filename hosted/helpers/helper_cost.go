@@ -0,0 +1,180 @@
+package helpers
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// controlPlanePriceSuffix is the suffix of the price keys of the control planes, for e.g. eks-control-plane
+const controlPlanePriceSuffix = "-control-plane"
+
+// defaultCostPrices are the approximate on-demand prices in USD per hour of the control planes and of the instance types used by the suites;
+// they can be overridden with COST_PRICES.
+var defaultCostPrices = map[string]float64{
+	"eks" + controlPlanePriceSuffix: 0.10,
+	"gke" + controlPlanePriceSuffix: 0.10,
+	// the AKS control plane of the free tier is not charged
+	"aks" + controlPlanePriceSuffix: 0,
+
+	"t3.medium":       0.0416,
+	"t3.large":        0.0832,
+	"m5.large":        0.096,
+	"e2-medium":       0.0335,
+	"n1-standard-2":   0.095,
+	"n2-standard-2":   0.0971,
+	"Standard_D2s_v3": 0.096,
+	"Standard_DS2_v2": 0.146,
+}
+
+// Default instance types of the clusters created with the provider CLIs
+const (
+	EksctlDefaultInstanceType = "m5.large"
+	AzDefaultVMSize           = "Standard_DS2_v2"
+)
+
+// NodePoolSize is the instance type and the number of nodes of a nodegroup/nodepool
+type NodePoolSize struct {
+	InstanceType string `json:"instanceType"`
+	Nodes        int64  `json:"nodes"`
+}
+
+// CostEstimate is the approximate cloud spend of the clusters of a suite or a run
+type CostEstimate struct {
+	// ControlPlaneHours are the hours of the cluster control planes, by provider
+	ControlPlaneHours map[string]float64 `json:"controlPlaneHours"`
+	// InstanceHours are the hours of the nodes, by instance type
+	InstanceHours map[string]float64 `json:"instanceHours"`
+	USD           float64            `json:"usd"`
+	// Unpriced are the instance types without price, left out of USD
+	Unpriced []string `json:"unpriced,omitempty"`
+}
+
+// parseCostPrices returns the prices of COST_PRICES, for e.g. t3.large=0.0832,eks-control-plane=0.10, over the default ones
+func parseCostPrices(values []string) (map[string]float64, error) {
+	prices := map[string]float64{}
+	for key, price := range defaultCostPrices {
+		prices[key] = price
+	}
+	for _, value := range values {
+		key, priceValue, found := strings.Cut(value, "=")
+		price, err := strconv.ParseFloat(strings.TrimSpace(priceValue), 64)
+		if !found || strings.TrimSpace(key) == "" || err != nil || price < 0 {
+			return nil, fmt.Errorf("%q is not a valid price; <instance type or provider-control-plane>=<USD per hour> is expected, for e.g. t3.large=0.0832", value)
+		}
+		prices[strings.TrimSpace(key)] = price
+	}
+	return prices, nil
+}
+
+// clusterNodePools returns the instance type and the number of nodes of every nodegroup/nodepool of the cluster config;
+// imported clusters usually have none, their nodes are recorded with the cloud cluster.
+func clusterNodePools(cluster *management.Cluster) []NodePoolSize {
+	var nodePools []NodePoolSize
+	switch {
+	case cluster.EKSConfig != nil && cluster.EKSConfig.NodeGroups != nil:
+		for _, ng := range *cluster.EKSConfig.NodeGroups {
+			size := NodePoolSize{Nodes: derefCount(ng.DesiredSize)}
+			if ng.InstanceType != nil {
+				size.InstanceType = *ng.InstanceType
+			}
+			nodePools = append(nodePools, size)
+		}
+	case cluster.GKEConfig != nil && cluster.GKEConfig.NodePools != nil:
+		for _, np := range *cluster.GKEConfig.NodePools {
+			size := NodePoolSize{Nodes: derefCount(np.InitialNodeCount)}
+			if np.Config != nil {
+				size.InstanceType = np.Config.MachineType
+			}
+			nodePools = append(nodePools, size)
+		}
+	case cluster.AKSConfig != nil && cluster.AKSConfig.NodePools != nil:
+		for _, np := range *cluster.AKSConfig.NodePools {
+			nodePools = append(nodePools, NodePoolSize{InstanceType: np.VMSize, Nodes: derefCount(np.Count)})
+		}
+	}
+	return nodePools
+}
+
+// derefCount returns the count, or 0 if it is not set
+func derefCount(count *int64) int64 {
+	if count == nil {
+		return 0
+	}
+	return *count
+}
+
+// CLINodePools returns the node pool of a cluster created with a provider CLI, from the --nodes value of the command
+func CLINodePools(instanceType, nodes string) []NodePoolSize {
+	count, _ := strconv.ParseInt(nodes, 10, 64)
+	return []NodePoolSize{{InstanceType: instanceType, Nodes: count}}
+}
+
+// estimateCost estimates the spend of the clusters of the resources, from their creation to their deletion or end if they were not deleted.
+// The node count of the clusters at creation is used, the scaling operations are not accounted for; a cluster imported into Rancher
+// is only counted once, as the cloud cluster.
+func estimateCost(resources []*Resource, end time.Time, prices map[string]float64) CostEstimate {
+	estimate := CostEstimate{ControlPlaneHours: map[string]float64{}, InstanceHours: map[string]float64{}}
+	cloudClusters := map[string]bool{}
+	for _, resource := range resources {
+		if resource.Kind == ResourceCloudCluster {
+			cloudClusters[resource.Name] = true
+		}
+	}
+	unpriced := map[string]bool{}
+	for _, resource := range resources {
+		if resource.Kind != ResourceCloudCluster && (resource.Kind != ResourceRancherCluster || cloudClusters[resource.Name]) {
+			continue
+		}
+		deleted := end
+		if resource.DeletedAt != nil {
+			deleted = *resource.DeletedAt
+		}
+		hours := max(deleted.Sub(resource.CreatedAt).Hours(), 0)
+
+		estimate.ControlPlaneHours[resource.Provider] += hours
+		estimate.USD += hours * prices[resource.Provider+controlPlanePriceSuffix]
+		for _, nodePool := range resource.NodePools {
+			instanceHours := hours * float64(nodePool.Nodes)
+			estimate.InstanceHours[nodePool.InstanceType] += instanceHours
+			if price, ok := prices[nodePool.InstanceType]; ok {
+				estimate.USD += instanceHours * price
+			} else if instanceHours > 0 {
+				unpriced[nodePool.InstanceType] = true
+			}
+		}
+	}
+	for instanceType := range unpriced {
+		estimate.Unpriced = append(estimate.Unpriced, instanceType)
+	}
+	sort.Strings(estimate.Unpriced)
+	return estimate
+}
+
+// Add adds the estimate of another suite to the estimate
+func (e *CostEstimate) Add(other CostEstimate) {
+	if e.ControlPlaneHours == nil {
+		e.ControlPlaneHours = map[string]float64{}
+	}
+	if e.InstanceHours == nil {
+		e.InstanceHours = map[string]float64{}
+	}
+	for provider, hours := range other.ControlPlaneHours {
+		e.ControlPlaneHours[provider] += hours
+	}
+	for instanceType, hours := range other.InstanceHours {
+		e.InstanceHours[instanceType] += hours
+	}
+	e.USD += other.USD
+	for _, instanceType := range other.Unpriced {
+		if !slices.Contains(e.Unpriced, instanceType) {
+			e.Unpriced = append(e.Unpriced, instanceType)
+		}
+	}
+	sort.Strings(e.Unpriced)
+}
@@ -0,0 +1,79 @@
+package helpers
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+func TestEstimateCost(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	deleted := start.Add(2 * time.Hour)
+	resources := []*Resource{
+		// provisioned cluster, deleted after 2 hours
+		{Kind: ResourceRancherCluster, Name: "eks-hp-ci-abcde", Provider: "eks", CreatedAt: start, DeletedAt: &deleted, NodePools: []NodePoolSize{{InstanceType: "t3.large", Nodes: 2}}},
+		// imported cluster, only counted as the cloud cluster which is not deleted
+		{Kind: ResourceCloudCluster, Name: "eks-hp-ci-fghij", Provider: "eks", CreatedAt: start, NodePools: []NodePoolSize{{InstanceType: "m5.large", Nodes: 1}}},
+		{Kind: ResourceRancherCluster, Name: "eks-hp-ci-fghij", Provider: "eks", CreatedAt: start.Add(time.Hour)},
+		{Kind: ResourceRancherCluster, Name: "aks-hp-ci-klmno", Provider: "aks", CreatedAt: start, DeletedAt: &deleted, NodePools: []NodePoolSize{{InstanceType: "Standard_X", Nodes: 1}}},
+		{Kind: ResourceCloudCredential, Name: "cc-abcde", Provider: "eks", CreatedAt: start},
+	}
+	estimate := estimateCost(resources, start.Add(4*time.Hour), defaultCostPrices)
+
+	if !reflect.DeepEqual(estimate.ControlPlaneHours, map[string]float64{"eks": 6, "aks": 2}) {
+		t.Errorf("estimateCost() control plane hours = %v", estimate.ControlPlaneHours)
+	}
+	if !reflect.DeepEqual(estimate.InstanceHours, map[string]float64{"t3.large": 4, "m5.large": 4, "Standard_X": 2}) {
+		t.Errorf("estimateCost() instance hours = %v", estimate.InstanceHours)
+	}
+	// 6h of eks control plane, 4h of t3.large and 4h of m5.large
+	if usd := 6*0.10 + 4*0.0832 + 4*0.096; math.Abs(estimate.USD-usd) > 1e-9 {
+		t.Errorf("estimateCost() = $%f, want $%f", estimate.USD, usd)
+	}
+	if !reflect.DeepEqual(estimate.Unpriced, []string{"Standard_X"}) {
+		t.Errorf("estimateCost() unpriced = %v, want [Standard_X]", estimate.Unpriced)
+	}
+
+	total := CostEstimate{}
+	total.Add(estimate)
+	total.Add(estimate)
+	if total.ControlPlaneHours["eks"] != 12 || math.Abs(total.USD-2*estimate.USD) > 1e-9 || len(total.Unpriced) != 1 {
+		t.Errorf("Add() = %+v", total)
+	}
+}
+
+func TestParseCostPrices(t *testing.T) {
+	prices, err := parseCostPrices([]string{"t3.large=0.09", "aks-control-plane = 0.10"})
+	if err != nil {
+		t.Fatalf("parseCostPrices() = %v", err)
+	}
+	if prices["t3.large"] != 0.09 || prices["aks-control-plane"] != 0.10 || prices["m5.large"] != defaultCostPrices["m5.large"] {
+		t.Errorf("parseCostPrices() = %v", prices)
+	}
+	if defaultCostPrices["t3.large"] == 0.09 {
+		t.Errorf("parseCostPrices() changed the default prices")
+	}
+	for _, value := range []string{"t3.large", "t3.large=cheap", "=0.1", "t3.large=-1"} {
+		if _, err = parseCostPrices([]string{value}); err == nil {
+			t.Errorf("parseCostPrices(%q) returned no error", value)
+		}
+	}
+}
+
+func TestClusterNodePools(t *testing.T) {
+	instanceType, desiredSize, count := "t3.large", int64(2), int64(3)
+	eks := &management.Cluster{EKSConfig: &management.EKSClusterConfigSpec{NodeGroups: &[]management.NodeGroup{{InstanceType: &instanceType, DesiredSize: &desiredSize}, {}}}}
+	if nodePools := clusterNodePools(eks); !reflect.DeepEqual(nodePools, []NodePoolSize{{InstanceType: "t3.large", Nodes: 2}, {}}) {
+		t.Errorf("clusterNodePools() of eks = %v", nodePools)
+	}
+	gke := &management.Cluster{GKEConfig: &management.GKEClusterConfigSpec{NodePools: &[]management.GKENodePoolConfig{{Config: &management.GKENodeConfig{MachineType: "n1-standard-2"}, InitialNodeCount: &count}}}}
+	if nodePools := clusterNodePools(gke); !reflect.DeepEqual(nodePools, []NodePoolSize{{InstanceType: "n1-standard-2", Nodes: 3}}) {
+		t.Errorf("clusterNodePools() of gke = %v", nodePools)
+	}
+	if nodePools := clusterNodePools(&management.Cluster{AKSConfig: &management.AKSClusterConfigSpec{Imported: true}}); nodePools != nil {
+		t.Errorf("clusterNodePools() of an imported cluster = %v, want none", nodePools)
+	}
+}
@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// importedClusterGracePeriod is how long an imported cluster must keep existing on the cloud provider once deleted from Rancher,
+// long enough for the operator to have processed the deletion
+const importedClusterGracePeriod = 5 * time.Minute
+
+// clusterImported returns whether the hosted config of a cluster marks it as imported, in which case its operator must not delete it from the cloud provider
+func clusterImported(cluster *management.Cluster) (bool, error) {
+	switch {
+	case cluster.EKSConfig != nil:
+		return cluster.EKSConfig.Imported, nil
+	case cluster.GKEConfig != nil:
+		return cluster.GKEConfig.Imported, nil
+	case cluster.AKSConfig != nil:
+		return cluster.AKSConfig.Imported, nil
+	}
+	return false, fmt.Errorf("cluster %s has no hosted config", cluster.Name)
+}
+
+/*
+Delete a hosted cluster from Rancher and check the deletion semantics of its kind, verified through the cloud API: an imported cluster must
+keep existing on the cloud provider, while a provisioned cluster must be destroyed along with it.
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @param deleteCluster Function deleting the cluster from Rancher, for e.g. DeleteEKSHostCluster or DeleteThroughProvisioningAPI
+  - @param existsOnCloud Provider function checking whether the cloud cluster still exists, for e.g. a wrapper of ClusterExistsOnAWS
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckClusterDeletion(client *rancher.Client, cluster *management.Cluster, deleteCluster func(*management.Cluster, *rancher.Client) error, existsOnCloud func() (bool, error)) {
+	imported, err := clusterImported(cluster)
+	Expect(err).To(BeNil())
+
+	ginkgo.By(fmt.Sprintf("Deleting cluster %s from Rancher: imported=%t", cluster.Name, imported), func() {
+		Expect(deleteCluster(cluster, client)).To(Succeed())
+		WaitUntilClusterIsRemoved(client, cluster.ID)
+	})
+
+	if imported {
+		ginkgo.By(fmt.Sprintf("Checking cluster %s is kept on the cloud provider", cluster.Name), func() {
+			Consistently(existsOnCloud, importedClusterGracePeriod, 30*time.Second).Should(BeTrue(), fmt.Sprintf("Imported cluster %s was deleted from the cloud provider", cluster.Name))
+		})
+		return
+	}
+
+	ginkgo.By(fmt.Sprintf("Checking cluster %s is deleted from the cloud provider", cluster.Name), func() {
+		EventuallyWithBackoff(existsOnCloud, tools.SetTimeout(30*time.Minute), 30*time.Second).Should(BeFalse(), fmt.Sprintf("Provisioned cluster %s still exists on the cloud provider", cluster.Name))
+	})
+}
@@ -0,0 +1,26 @@
+package helpers
+
+import (
+	"testing"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+func TestClusterImported(t *testing.T) {
+	for _, tc := range []struct {
+		cluster *management.Cluster
+		want    bool
+	}{
+		{cluster: &management.Cluster{EKSConfig: &management.EKSClusterConfigSpec{Imported: true}}, want: true},
+		{cluster: &management.Cluster{GKEConfig: &management.GKEClusterConfigSpec{}}, want: false},
+		{cluster: &management.Cluster{AKSConfig: &management.AKSClusterConfigSpec{Imported: true}}, want: true},
+	} {
+		if got, err := clusterImported(tc.cluster); err != nil || got != tc.want {
+			t.Errorf("clusterImported() = %t, %v, want %t", got, err, tc.want)
+		}
+	}
+
+	if _, err := clusterImported(&management.Cluster{Name: "custom"}); err == nil {
+		t.Errorf("clusterImported() of a cluster without hosted config returned no error")
+	}
+}
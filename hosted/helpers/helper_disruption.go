@@ -0,0 +1,79 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// clusterAgentDeployment is the deployment of the cluster agent in the cattle-system namespace of the downstream clusters
+	clusterAgentDeployment = "cattle-cluster-agent"
+	// blackholeIP is a documentation address (TEST-NET-1) no server answers on
+	blackholeIP = "192.0.2.1"
+)
+
+// agentDisruptionPatch returns the strategic merge patch of the cluster agent deployment resolving the Rancher hostname to blackholeIP;
+// the pods are recreated rather than rolled out, since a new agent which can not connect may never get ready and the old one would be kept.
+func agentDisruptionPatch(rancherHostname string) (string, error) {
+	if net.ParseIP(rancherHostname) != nil {
+		return "", fmt.Errorf("Rancher is reached through IP %s, its resolution can not be overridden", rancherHostname)
+	}
+	patch := map[string]any{"spec": map[string]any{
+		"strategy": map[string]any{"type": "Recreate", "rollingUpdate": nil},
+		"template": map[string]any{"spec": map[string]any{
+			"hostAliases": []map[string]any{{"ip": blackholeIP, "hostnames": []string{rancherHostname}}},
+		}},
+	}}
+	content, err := json.Marshal(patch)
+	return string(content), err
+}
+
+// agentRestorationPatch is the strategic merge patch of the cluster agent deployment reverting agentDisruptionPatch
+const agentRestorationPatch = `{"spec":{"strategy":{"type":"RollingUpdate"},"template":{"spec":{"hostAliases":null}}}}`
+
+/*
+Disrupt the connection between the cluster agent of a downstream cluster and Rancher: the Rancher hostname is resolved to an address
+no server answers on for the agent pods, which are recreated. Rancher can not reach the cluster anymore, so the cluster is reached
+with client-go from the kubeconfig of the provider CLI, for e.g. written by GetEKSCredentialsOnAWS (see ProviderDownstreamClients). The cluster can still be deleted through Rancher while
+disrupted, the cloud provider being reached by the operator.
+  - @param clusterName Name of the cluster
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func DisruptAgentConnection(clusterName string) {
+	clients, err := ProviderDownstreamClients(clusterName)
+	Expect(err).To(BeNil())
+	patch, err := agentDisruptionPatch(RancherHostname)
+	Expect(err).To(BeNil())
+
+	ginkgo.By(fmt.Sprintf("Disrupting the connection of the agent of cluster %s to Rancher", clusterName), func() {
+		_, err = clients.Clientset.AppsV1().Deployments(CattleSystemNS).Patch(context.Background(), clusterAgentDeployment, types.StrategicMergePatchType,
+			[]byte(patch), metav1.PatchOptions{})
+		Expect(err).To(BeNil())
+	})
+}
+
+/*
+Restore the connection between the cluster agent of a downstream cluster and Rancher disrupted by DisruptAgentConnection; the agent pods are recreated
+and must become ready, while the cluster is waited for by the caller, for e.g. with CheckClusterResumed.
+  - @param clusterName Name of the cluster
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func RestoreAgentConnection(clusterName string) {
+	clients, err := ProviderDownstreamClients(clusterName)
+	Expect(err).To(BeNil())
+	ginkgo.By(fmt.Sprintf("Restoring the connection of the agent of cluster %s to Rancher", clusterName), func() {
+		_, err := clients.Clientset.AppsV1().Deployments(CattleSystemNS).Patch(context.Background(), clusterAgentDeployment, types.StrategicMergePatchType,
+			[]byte(agentRestorationPatch), metav1.PatchOptions{})
+		Expect(err).To(BeNil())
+		Expect(clients.WaitForRollout(context.Background(), CattleSystemNS, clusterAgentDeployment, 10*time.Minute)).To(Succeed(),
+			"The agent of cluster %s is not rolled out", clusterName)
+	})
+}
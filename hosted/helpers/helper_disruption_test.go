@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"encoding/json"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+func TestAgentDisruptionPatch(t *testing.T) {
+	if _, err := agentDisruptionPatch("1.2.3.4"); err == nil {
+		t.Errorf("agentDisruptionPatch(1.2.3.4) returned no error, the resolution of an IP can not be overridden")
+	}
+
+	patch, err := agentDisruptionPatch("1.2.3.4.sslip.io")
+	if err != nil {
+		t.Fatalf("agentDisruptionPatch(1.2.3.4.sslip.io) = %v", err)
+	}
+	original, _ := json.Marshal(appsv1.Deployment{Spec: appsv1.DeploymentSpec{Strategy: appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType, RollingUpdate: &appsv1.RollingUpdateDeployment{},
+	}}})
+
+	disrupted := appsv1.Deployment{}
+	content, err := strategicpatch.StrategicMergePatch(original, []byte(patch), appsv1.Deployment{})
+	if err != nil {
+		t.Fatalf("StrategicMergePatch(disruption) = %v", err)
+	}
+	_ = json.Unmarshal(content, &disrupted)
+	aliases := disrupted.Spec.Template.Spec.HostAliases
+	if len(aliases) != 1 || aliases[0].IP != blackholeIP || len(aliases[0].Hostnames) != 1 || aliases[0].Hostnames[0] != "1.2.3.4.sslip.io" {
+		t.Errorf("disrupted host aliases = %v, want 1.2.3.4.sslip.io resolved to %s", aliases, blackholeIP)
+	}
+	if disrupted.Spec.Strategy.Type != appsv1.RecreateDeploymentStrategyType || disrupted.Spec.Strategy.RollingUpdate != nil {
+		t.Errorf("disrupted strategy = %v, want Recreate", disrupted.Spec.Strategy)
+	}
+
+	restored := appsv1.Deployment{}
+	content, err = strategicpatch.StrategicMergePatch(content, []byte(agentRestorationPatch), appsv1.Deployment{})
+	if err != nil {
+		t.Fatalf("StrategicMergePatch(restoration) = %v", err)
+	}
+	_ = json.Unmarshal(content, &restored)
+	if len(restored.Spec.Template.Spec.HostAliases) != 0 || restored.Spec.Strategy.Type != appsv1.RollingUpdateDeploymentStrategyType {
+		t.Errorf("restored deployment spec = %v, want no host alias and a rolling update", restored.Spec)
+	}
+}
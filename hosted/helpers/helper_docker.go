@@ -0,0 +1,100 @@
+package helpers
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+)
+
+const (
+	// rancherDockerContainer is the name of the rancher container started by InstallRancherDocker
+	rancherDockerContainer = "rancher"
+	// rancherBackendDocker is the RANCHER_INSTALL_BACKEND running rancher as a single docker container
+	rancherBackendDocker = "docker"
+)
+
+// IsDockerBackend returns true if rancher must be run as a single docker container instead of being installed with helm on k3s (RANCHER_INSTALL_BACKEND=docker)
+func IsDockerBackend() bool {
+	return RancherInstallBackend == rancherBackendDocker
+}
+
+// RancherDockerImage returns the rancher image matching the channel/version/headVersion format of RANCHER_VERSION
+func RancherDockerImage(rancherChannel, rancherVersion, rancherHeadVersion string) string {
+	repository := "rancher/rancher"
+	if strings.HasPrefix(rancherChannel, "prime") {
+		repository = runConfig.PrimeRegistry + "/rancher/rancher"
+	}
+
+	var tag string
+	switch {
+	case rancherVersion == "" || rancherVersion == "latest":
+		tag = "latest"
+	case rancherVersion == "devel" && rancherHeadVersion == "head":
+		tag = "head"
+	case rancherVersion == "devel":
+		tag = fmt.Sprintf("v%s-head", rancherHeadVersion)
+	default:
+		tag = "v" + strings.TrimPrefix(rancherVersion, "v")
+	}
+	return fmt.Sprintf("%s:%s", repository, tag)
+}
+
+/*
+*
+Run Rancher Manager as a single docker container; quicker to set up than k3s + helm, but it can not be upgraded with helm,
+so it is only meant for the suites that do not need the upstream cluster, for e.g. provisioning and import suites
+  - @param rancherHostname, Rancher Hostname
+  - @param rancherChannel, Rancher Channel [eg. latest, prime]
+  - @param rancherVersion, Rancher Version [eg. 2.9.3-rc2]
+  - @param rancherHeadVersion, Rancher Head version [eg. 2.9-head]
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func InstallRancherDocker(rancherHostname, rancherChannel, rancherVersion, rancherHeadVersion string) {
+	image := RancherDockerImage(rancherChannel, rancherVersion, rancherHeadVersion)
+
+	By(fmt.Sprintf("Running rancher container %s", image), func() {
+		args := []string{"run", "-d", "--restart=unless-stopped", "--privileged",
+			"--name", rancherDockerContainer,
+			"-p", "80:80", "-p", "443:443",
+			"-e", "CATTLE_BOOTSTRAP_PASSWORD=" + RancherPassword,
+			"-e", "CATTLE_SERVER_URL=https://" + rancherHostname,
+			image,
+		}
+		out, err := extcli.Docker.Output(args...)
+		GinkgoWriter.Println(out)
+		Expect(err).To(Not(HaveOccurred()))
+	})
+
+	By("Waiting for rancher to be up", func() {
+		WaitUntilRancherIsUp(rancherHostname)
+	})
+
+	// The supported versions may change with the Rancher version
+	ResetVersionCatalog()
+}
+
+// WaitUntilRancherIsUp waits until the rancher /ping endpoint answers pong
+func WaitUntilRancherIsUp(rancherHostname string) {
+	httpClient := &http.Client{
+		Timeout: 10 * time.Second,
+		// rancher uses a self-signed certificate
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
+	EventuallyWithBackoff(func() (string, error) {
+		resp, err := httpClient.Get(fmt.Sprintf("https://%s/ping", rancherHostname))
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return string(body), err
+	}, tools.SetTimeout(10*time.Minute), 10*time.Second).Should(Equal("pong"), "Rancher is not up")
+}
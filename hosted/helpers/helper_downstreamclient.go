@@ -0,0 +1,275 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// crdResource is the resource of the custom resource definitions, read with the dynamic client
+var crdResource = apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions")
+
+// nodePressureConditions are the node conditions which must be false on a healthy node
+var nodePressureConditions = []corev1.NodeConditionType{corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure, corev1.NodeNetworkUnavailable}
+
+// DownstreamClients are the client-go clients of a downstream cluster, see RancherDownstreamClients and ProviderDownstreamClients;
+// unlike kubectl, they need neither the KUBECONFIG env var nor a kubeconfig file, so that the parallel specs can reach their clusters at the same time.
+type DownstreamClients struct {
+	Config    *rest.Config
+	Clientset kubernetes.Interface
+	Dynamic   dynamic.Interface
+}
+
+// downstreamClients are the clients of the downstream clusters reached by the process, by Rancher client and cluster ID, or by kubeconfig path;
+// the kubeconfig generated by Rancher holds a new token every time, so it is only generated once per client.
+var downstreamClients = struct {
+	sync.Mutex
+	byKey map[string]*DownstreamClients
+}{byKey: map[string]*DownstreamClients{}}
+
+// newDownstreamClients returns the clients of the REST config
+func newDownstreamClients(config *rest.Config) (*DownstreamClients, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &DownstreamClients{Config: config, Clientset: clientset, Dynamic: dynamicClient}, nil
+}
+
+// cachedDownstreamClients returns the clients of the key, created from the REST config of newConfig the first time
+func cachedDownstreamClients(key string, newConfig func() (*rest.Config, error)) (*DownstreamClients, error) {
+	downstreamClients.Lock()
+	defer downstreamClients.Unlock()
+	if clients, found := downstreamClients.byKey[key]; found {
+		return clients, nil
+	}
+	config, err := newConfig()
+	if err != nil {
+		return nil, err
+	}
+	clients, err := newDownstreamClients(config)
+	if err != nil {
+		return nil, err
+	}
+	downstreamClients.byKey[key] = clients
+	return clients, nil
+}
+
+// forgetDownstreamClients drops the clients of a kubeconfig once it is removed, see RemoveDownstreamKubeconfig
+func forgetDownstreamClients(kubeconfig string) {
+	downstreamClients.Lock()
+	defer downstreamClients.Unlock()
+	delete(downstreamClients.byKey, kubeconfig)
+}
+
+/*
+RancherDownstreamClients returns the client-go clients of a downstream cluster, from the kubeconfig generated by Rancher for the user of the client;
+the requests go through the Rancher proxy and the tunnel of the cluster agent, as the kubectl shell of the Rancher UI does.
+  - @param client Rancher client, of the user the kubeconfig is generated for
+  - @param cluster Downstream cluster
+  - @returns The clients of the cluster, or the error of the kubeconfig generation
+*/
+func RancherDownstreamClients(client *rancher.Client, cluster *management.Cluster) (*DownstreamClients, error) {
+	return cachedDownstreamClients(fmt.Sprintf("%p/%s", client, cluster.ID), func() (*rest.Config, error) {
+		output, err := client.Management.Cluster.ActionGenerateKubeconfig(cluster)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate the kubeconfig of cluster %s: %w", cluster.Name, err)
+		}
+		return clientcmd.RESTConfigFromKubeConfig([]byte(output.Config))
+	})
+}
+
+/*
+ProviderDownstreamClients returns the client-go clients of a cluster created with the provider CLI, from the kubeconfig of the provider CLI
+written on first use (see DownstreamKubeconfigPath); the cluster is reached without going through Rancher.
+  - @param clusterName Name of the cluster
+  - @returns The clients of the cluster, or the error of the kubeconfig generation
+*/
+func ProviderDownstreamClients(clusterName string) (*DownstreamClients, error) {
+	kubeconfig, err := DownstreamKubeconfigPath(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return cachedDownstreamClients(kubeconfig, func() (*rest.Config, error) {
+		return clientcmd.BuildConfigFromFlags("", kubeconfig)
+	})
+}
+
+// nodeProblems returns why the nodes are not healthy: not ready, or under the pressure of one of nodePressureConditions
+func nodeProblems(nodes []corev1.Node) []string {
+	var problems []string
+	for _, node := range nodes {
+		ready := false
+		for _, condition := range node.Status.Conditions {
+			switch {
+			case condition.Type == corev1.NodeReady:
+				ready = condition.Status == corev1.ConditionTrue
+			case condition.Status == corev1.ConditionTrue:
+				for _, pressure := range nodePressureConditions {
+					if condition.Type == pressure {
+						problems = append(problems, fmt.Sprintf("node %s has condition %s: %s", node.Name, condition.Type, condition.Message))
+					}
+				}
+			}
+		}
+		if !ready {
+			problems = append(problems, fmt.Sprintf("node %s is not ready", node.Name))
+		}
+	}
+	if len(nodes) == 0 {
+		problems = append(problems, "the cluster has no node")
+	}
+	return problems
+}
+
+// podProblems returns why the pods are not healthy: neither running with all their containers ready nor succeeded,
+// along with the reason of the waiting containers, for e.g. CrashLoopBackOff or ImagePullBackOff
+func podProblems(pods []corev1.Pod) []string {
+	var problems []string
+	for _, pod := range pods {
+		name := pod.Namespace + "/" + pod.Name
+		switch pod.Status.Phase {
+		case corev1.PodSucceeded:
+			continue
+		case corev1.PodRunning:
+		default:
+			problems = append(problems, fmt.Sprintf("pod %s is %s", name, pod.Status.Phase))
+			continue
+		}
+		for _, container := range pod.Status.ContainerStatuses {
+			if container.Ready {
+				continue
+			}
+			if container.State.Waiting != nil && container.State.Waiting.Reason != "" {
+				problems = append(problems, fmt.Sprintf("container %s of pod %s is waiting: %s", container.Name, name, container.State.Waiting.Reason))
+			} else {
+				problems = append(problems, fmt.Sprintf("container %s of pod %s is not ready", container.Name, name))
+			}
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// crdEstablished returns true once the custom resource definition is established, i.e. its custom resources can be created
+func crdEstablished(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	for _, condition := range crd.Status.Conditions {
+		if condition.Type == apiextensionsv1.Established {
+			return condition.Status == apiextensionsv1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// deploymentRolledOut returns true once the deployment runs its latest spec on all its replicas, like kubectl rollout status
+func deploymentRolledOut(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	return deployment.Generation <= status.ObservedGeneration && status.UpdatedReplicas == replicas &&
+		status.Replicas == status.UpdatedReplicas && status.AvailableReplicas == status.UpdatedReplicas
+}
+
+// NodeProblems returns why the nodes of the cluster are not healthy, see nodeProblems
+func (c *DownstreamClients) NodeProblems(ctx context.Context) ([]string, error) {
+	nodes, err := c.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return nodeProblems(nodes.Items), nil
+}
+
+// PodProblems returns why the pods of the namespaces are not healthy, see podProblems
+func (c *DownstreamClients) PodProblems(ctx context.Context, namespaces ...string) ([]string, error) {
+	var problems []string
+	for _, namespace := range namespaces {
+		pods, err := c.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, podProblems(pods.Items)...)
+	}
+	return problems, nil
+}
+
+// WaitForCRD waits until the custom resource definition is established
+func (c *DownstreamClients) WaitForCRD(ctx context.Context, name string, timeout time.Duration) error {
+	return wait.PollUntilContextTimeout(ctx, 2*time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		object, err := c.Dynamic.Resource(crdResource).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, crd); err != nil {
+			return false, err
+		}
+		return crdEstablished(crd), nil
+	})
+}
+
+// WaitForRollout waits until the deployment is rolled out, see deploymentRolledOut
+func (c *DownstreamClients) WaitForRollout(ctx context.Context, namespace, name string, timeout time.Duration) error {
+	return wait.PollUntilContextTimeout(ctx, 5*time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		deployment, err := c.Clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		return deploymentRolledOut(deployment), nil
+	})
+}
+
+/*
+CheckDownstreamNodes checks the conditions of the nodes of a downstream cluster with client-go, through Rancher: all the nodes must be ready
+and none of them under memory, disk or PID pressure, unlike the nodes of the Rancher API which only tell whether they are ready.
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckDownstreamNodes(client *rancher.Client, cluster *management.Cluster) {
+	clients, err := RancherDownstreamClients(client, cluster)
+	Expect(err).To(BeNil())
+	EventuallyWithBackoff(func() ([]string, error) {
+		return clients.NodeProblems(context.Background())
+	}, tools.SetTimeout(Timeout), 30*time.Second).Should(BeEmpty(), fmt.Sprintf("The nodes of cluster %s are not healthy", cluster.Name))
+}
+
+/*
+CheckDownstreamPods checks the status of the pods of the namespaces of a downstream cluster with client-go, through Rancher; the waiting containers
+are reported with their reason, for e.g. CrashLoopBackOff.
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @param namespaces Namespaces of the pods, for e.g. cattle-system and kube-system
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckDownstreamPods(client *rancher.Client, cluster *management.Cluster, namespaces ...string) {
+	clients, err := RancherDownstreamClients(client, cluster)
+	Expect(err).To(BeNil())
+	EventuallyWithBackoff(func() ([]string, error) {
+		return clients.PodProblems(context.Background(), namespaces...)
+	}, tools.SetTimeout(Timeout), 30*time.Second).Should(BeEmpty(), fmt.Sprintf("The pods of cluster %s are not healthy", cluster.Name))
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("The pods of namespaces %v of cluster %s are healthy", namespaces, cluster.Name))
+}
@@ -0,0 +1,140 @@
+package helpers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func TestNodeProblems(t *testing.T) {
+	node := func(name string, conditions ...corev1.NodeCondition) corev1.Node {
+		return corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}, Status: corev1.NodeStatus{Conditions: conditions}}
+	}
+	ready := corev1.NodeCondition{Type: corev1.NodeReady, Status: corev1.ConditionTrue}
+	notReady := corev1.NodeCondition{Type: corev1.NodeReady, Status: corev1.ConditionFalse}
+	diskPressure := corev1.NodeCondition{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue, Message: "kubelet has disk pressure"}
+	noMemoryPressure := corev1.NodeCondition{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse}
+
+	if got := nodeProblems([]corev1.Node{node("a", ready, noMemoryPressure), node("b", ready)}); len(got) != 0 {
+		t.Errorf("got %v for healthy nodes", got)
+	}
+	got := nodeProblems([]corev1.Node{node("a", ready, diskPressure), node("b", notReady), node("c")})
+	want := []string{"node a has condition DiskPressure: kubelet has disk pressure", "node b is not ready", "node c is not ready"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got = nodeProblems(nil); len(got) != 1 {
+		t.Errorf("got %v, a cluster without nodes must be reported", got)
+	}
+}
+
+func TestPodProblems(t *testing.T) {
+	pod := func(name string, phase corev1.PodPhase, containers ...corev1.ContainerStatus) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "cattle-system", Name: name}, Status: corev1.PodStatus{Phase: phase, ContainerStatuses: containers}}
+	}
+	ready := corev1.ContainerStatus{Name: "agent", Ready: true}
+	crashing := corev1.ContainerStatus{Name: "agent", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}}
+	starting := corev1.ContainerStatus{Name: "agent", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}
+
+	got := podProblems([]corev1.Pod{
+		pod("agent-1", corev1.PodRunning, ready),
+		pod("job", corev1.PodSucceeded),
+		pod("agent-2", corev1.PodRunning, crashing),
+		pod("agent-3", corev1.PodRunning, starting),
+		pod("agent-4", corev1.PodPending),
+	})
+	want := []string{
+		"container agent of pod cattle-system/agent-2 is waiting: CrashLoopBackOff",
+		"container agent of pod cattle-system/agent-3 is not ready",
+		"pod cattle-system/agent-4 is Pending",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCRDEstablished(t *testing.T) {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if crdEstablished(crd) {
+		t.Error("a CRD without conditions must not be established")
+	}
+	crd.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{
+		{Type: apiextensionsv1.NamesAccepted, Status: apiextensionsv1.ConditionTrue},
+		{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue},
+	}
+	if !crdEstablished(crd) {
+		t.Error("the CRD must be established")
+	}
+}
+
+func TestDeploymentRolledOut(t *testing.T) {
+	replicas := int32(2)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterAgentDeployment, Namespace: CattleSystemNS, Generation: 3},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 3, Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 2},
+	}
+	if deploymentRolledOut(deployment) {
+		t.Error("a deployment with an old replica left must not be rolled out")
+	}
+	deployment.Status.Replicas = 2
+	if !deploymentRolledOut(deployment) {
+		t.Error("the deployment must be rolled out")
+	}
+	deployment.Generation = 4
+	if deploymentRolledOut(deployment) {
+		t.Error("a deployment whose spec is not observed yet must not be rolled out")
+	}
+
+	deployment.Generation = 3
+	clients := &DownstreamClients{Clientset: fake.NewSimpleClientset(deployment)}
+	if err := clients.WaitForRollout(context.Background(), CattleSystemNS, clusterAgentDeployment, 5*time.Second); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := clients.WaitForRollout(context.Background(), CattleSystemNS, "missing", time.Second); err == nil {
+		t.Error("expected a timeout for a missing deployment")
+	}
+}
+
+func TestDownstreamClientsPodProblems(t *testing.T) {
+	clients := &DownstreamClients{Clientset: fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "coredns"}, Status: corev1.PodStatus{Phase: corev1.PodFailed}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ignored"}, Status: corev1.PodStatus{Phase: corev1.PodFailed}},
+	)}
+	got, err := clients.PodProblems(context.Background(), "kube-system", CattleSystemNS)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"pod kube-system/coredns is Failed"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCachedDownstreamClients(t *testing.T) {
+	created := 0
+	newConfig := func() (*rest.Config, error) {
+		created++
+		return &rest.Config{Host: "https://rancher.example.com/k8s/clusters/c-abcde"}, nil
+	}
+	first, err := cachedDownstreamClients("test/c-abcde", newConfig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, _ := cachedDownstreamClients("test/c-abcde", newConfig)
+	if first != second || created != 1 {
+		t.Errorf("the clients must be created once, got %d configs", created)
+	}
+	forgetDownstreamClients("test/c-abcde")
+	if _, _ = cachedDownstreamClients("test/c-abcde", newConfig); created != 2 {
+		t.Error("the forgotten clients must be created again")
+	}
+	forgetDownstreamClients("test/c-abcde")
+}
@@ -0,0 +1,302 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/rancher/norman/types"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"sigs.k8s.io/yaml"
+)
+
+// IsDryRun returns true if the configs of the clusters are only validated, without creating anything (DRY_RUN=true); see DryRunCluster
+func IsDryRun() bool {
+	return runConfig.DryRun
+}
+
+// DryRunChecks are the cloud-side constraints the config of a cluster is validated against in dry-run mode
+type DryRunChecks struct {
+	// Versions are the k8s versions available in the region/zone of the cluster, for e.g. from the version catalog; not checked if empty
+	Versions []string
+	// InstanceTypeAvailable returns an error if an instance type of the nodegroups/nodepools is not available in the region/zone of the cluster; not checked if nil
+	InstanceTypeAvailable func(instanceType string) error
+}
+
+// hostedConfigSchema returns the Rancher schema of the hosted config of the spec, for e.g. eksClusterConfigSpec, along with its generic JSON form
+func (spec *ClusterSpec) hostedConfigSchema() (string, any) {
+	var schemaID string
+	var config any
+	switch {
+	case spec.EKSConfig != nil:
+		schemaID, config = "eksClusterConfigSpec", spec.EKSConfig
+	case spec.GKEConfig != nil:
+		schemaID, config = "gkeClusterConfigSpec", spec.GKEConfig
+	case spec.AKSConfig != nil:
+		schemaID, config = "aksClusterConfigSpec", spec.AKSConfig
+	}
+	generic, _ := toGeneric(config)
+	return schemaID, generic
+}
+
+// versions returns the k8s version of the control plane of the spec and the ones of its nodegroups/nodepools, when set
+func (spec *ClusterSpec) versions() (string, []string) {
+	var cluster *string
+	var nodes []string
+	switch {
+	case spec.EKSConfig != nil:
+		cluster = spec.EKSConfig.KubernetesVersion
+		if spec.EKSConfig.NodeGroups != nil {
+			for _, ng := range *spec.EKSConfig.NodeGroups {
+				if ng.Version != nil {
+					nodes = append(nodes, *ng.Version)
+				}
+			}
+		}
+	case spec.GKEConfig != nil:
+		cluster = spec.GKEConfig.KubernetesVersion
+		if spec.GKEConfig.NodePools != nil {
+			for _, np := range *spec.GKEConfig.NodePools {
+				if np.Version != nil {
+					nodes = append(nodes, *np.Version)
+				}
+			}
+		}
+	case spec.AKSConfig != nil:
+		cluster = spec.AKSConfig.KubernetesVersion
+		if spec.AKSConfig.NodePools != nil {
+			for _, np := range *spec.AKSConfig.NodePools {
+				if np.OrchestratorVersion != nil {
+					nodes = append(nodes, *np.OrchestratorVersion)
+				}
+			}
+		}
+	}
+	if cluster == nil {
+		return "", nodes
+	}
+	return *cluster, nodes
+}
+
+// instanceTypes returns the instance types of the nodegroups/nodepools of the spec (the spot instance types included), without duplicates
+func (spec *ClusterSpec) instanceTypes() []string {
+	found := map[string]bool{}
+	add := func(instanceType string) {
+		if instanceType != "" {
+			found[instanceType] = true
+		}
+	}
+	switch {
+	case spec.EKSConfig != nil && spec.EKSConfig.NodeGroups != nil:
+		for _, ng := range *spec.EKSConfig.NodeGroups {
+			if ng.InstanceType != nil {
+				add(*ng.InstanceType)
+			}
+			if ng.SpotInstanceTypes != nil {
+				for _, instanceType := range *ng.SpotInstanceTypes {
+					add(instanceType)
+				}
+			}
+		}
+	case spec.GKEConfig != nil && spec.GKEConfig.NodePools != nil:
+		for _, np := range *spec.GKEConfig.NodePools {
+			if np.Config != nil {
+				add(np.Config.MachineType)
+			}
+		}
+	case spec.AKSConfig != nil && spec.AKSConfig.NodePools != nil:
+		for _, np := range *spec.AKSConfig.NodePools {
+			add(np.VMSize)
+		}
+	}
+	instanceTypes := make([]string, 0, len(found))
+	for instanceType := range found {
+		instanceTypes = append(instanceTypes, instanceType)
+	}
+	sort.Strings(instanceTypes)
+	return instanceTypes
+}
+
+/*
+schemaProblems returns the values of a hosted config which do not comply with the Rancher schema of its type, for e.g.
+"nodeGroups[0].desiredSize: 1.5 is not an int": the unknown fields, the missing required fields, and the values of the wrong type,
+not in the options of the field, or out of its bounds. The types which are not in the schemas are not checked.
+  - @param schemas Schemas of the Rancher API, by ID
+  - @param schemaID Type of the value, for e.g. eksClusterConfigSpec
+  - @param value Generic JSON form of the value, see toGeneric
+  - @param path Path of the value, for the problems
+  - @returns The problems, if any
+*/
+func schemaProblems(schemas map[string]types.Schema, schemaID string, value any, path string) []string {
+	field := func(name string) string {
+		if path == "" {
+			return name
+		}
+		return path + "." + name
+	}
+	if value == nil {
+		return nil
+	}
+
+	switch {
+	case strings.HasPrefix(schemaID, "array["):
+		values, ok := value.([]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: %v is not an array", path, value)}
+		}
+		var problems []string
+		for i, item := range values {
+			problems = append(problems, schemaProblems(schemas, strings.TrimSuffix(strings.TrimPrefix(schemaID, "array["), "]"), item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return problems
+	case strings.HasPrefix(schemaID, "map["):
+		values, ok := value.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: %v is not a map", path, value)}
+		}
+		var problems []string
+		for key, item := range values {
+			problems = append(problems, schemaProblems(schemas, strings.TrimSuffix(strings.TrimPrefix(schemaID, "map["), "]"), item, field(key))...)
+		}
+		return problems
+	case strings.HasPrefix(schemaID, "reference["), schemaID == "string", schemaID == "password", schemaID == "enum", schemaID == "dnsLabel", schemaID == "hostname":
+		if _, ok := value.(string); !ok {
+			return []string{fmt.Sprintf("%s: %v is not a string", path, value)}
+		}
+		return nil
+	case schemaID == "int":
+		if number, ok := value.(float64); !ok || number != float64(int64(number)) {
+			return []string{fmt.Sprintf("%s: %v is not an int", path, value)}
+		}
+		return nil
+	case schemaID == "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{fmt.Sprintf("%s: %v is not a boolean", path, value)}
+		}
+		return nil
+	}
+
+	schema, found := schemas[schemaID]
+	if !found {
+		return nil
+	}
+	fields, ok := value.(map[string]any)
+	if !ok {
+		return []string{fmt.Sprintf("%s: %v is not a %s", path, value, schemaID)}
+	}
+	var problems []string
+	for name, fieldValue := range fields {
+		resourceField, found := schema.ResourceFields[name]
+		if !found {
+			problems = append(problems, fmt.Sprintf("%s: not a field of %s", field(name), schemaID))
+			continue
+		}
+		problems = append(problems, schemaProblems(schemas, resourceField.Type, fieldValue, field(name))...)
+		problems = append(problems, fieldBoundsProblems(resourceField, fieldValue, field(name))...)
+	}
+	for name, resourceField := range schema.ResourceFields {
+		if resourceField.Required && resourceField.Create && fields[name] == nil {
+			problems = append(problems, fmt.Sprintf("%s: required by %s", field(name), schemaID))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// fieldBoundsProblems returns the problem of a value which is not in the options of its schema field, or out of its bounds
+func fieldBoundsProblems(field types.Field, value any, path string) []string {
+	switch value := value.(type) {
+	case string:
+		if len(field.Options) > 0 && !ContainsString(field.Options, value) {
+			return []string{fmt.Sprintf("%s: %q is not one of %s", path, value, strings.Join(field.Options, ", "))}
+		}
+		if (field.MinLength != nil && int64(len(value)) < *field.MinLength) || (field.MaxLength != nil && int64(len(value)) > *field.MaxLength) {
+			return []string{fmt.Sprintf("%s: the length of %q is out of bounds", path, value)}
+		}
+	case float64:
+		if (field.Min != nil && value < float64(*field.Min)) || (field.Max != nil && value > float64(*field.Max)) {
+			return []string{fmt.Sprintf("%s: %v is out of bounds", path, value)}
+		}
+	}
+	return nil
+}
+
+// versionProblems returns the problems of the k8s versions of a spec: the control plane version must be one of the available versions,
+// and the nodegroups/nodepools can not be newer than the control plane
+func versionProblems(cluster string, nodes, available []string) []string {
+	var problems []string
+	if len(available) > 0 && !ContainsString(available, cluster) {
+		problems = append(problems, fmt.Sprintf("kubernetesVersion: %s is not available, the available versions are %s", cluster, strings.Join(available, ", ")))
+	}
+	clusterVersion, err := semver.NewVersion(cluster)
+	if err != nil {
+		return append(problems, fmt.Sprintf("kubernetesVersion: %q is not a valid version", cluster))
+	}
+	for _, node := range nodes {
+		if nodeVersion, err := semver.NewVersion(node); err != nil {
+			problems = append(problems, fmt.Sprintf("node version %q is not a valid version", node))
+		} else if nodeVersion.GreaterThan(clusterVersion) {
+			problems = append(problems, fmt.Sprintf("node version %s is newer than the control plane version %s", node, cluster))
+		}
+	}
+	return problems
+}
+
+// DryRunProblems returns the problems of the config of a cluster: the values not complying with the Rancher schema of the hosted config,
+// and the cloud-side constraints of the checks it does not meet
+func DryRunProblems(schemas map[string]types.Schema, spec *ClusterSpec, checks DryRunChecks) []string {
+	schemaID, config := spec.hostedConfigSchema()
+	if _, found := schemas[schemaID]; !found {
+		return []string{fmt.Sprintf("schema %s not found on Rancher", schemaID)}
+	}
+	problems := schemaProblems(schemas, schemaID, config, "")
+
+	cluster, nodes := spec.versions()
+	problems = append(problems, versionProblems(cluster, nodes, checks.Versions)...)
+
+	if checks.InstanceTypeAvailable != nil {
+		for _, instanceType := range spec.instanceTypes() {
+			if err := checks.InstanceTypeAvailable(instanceType); err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+	}
+	return problems
+}
+
+/*
+DryRunCluster validates the config of a cluster instead of creating it, with DRY_RUN=true: against the Rancher schema of the hosted config,
+for e.g. eksClusterConfigSpec, and against the cloud-side constraints of the checks, for e.g. the k8s versions and the instance types available
+in the region. The config is written to the artifacts directory of the spec, and the spec is skipped if it is valid, since there is no cluster
+to run it against; it fails with the problems of the config otherwise. It is called by the provider helpers creating the clusters.
+  - @param client Rancher client, whose schemas are used
+  - @param spec Cluster spec, as it would be created
+  - @param checks Cloud-side constraints of the provider
+  - @returns The problems of the config as an error, the spec is skipped through Ginkgo if the config is valid
+*/
+func DryRunCluster(client *rancher.Client, spec *ClusterSpec, checks DryRunChecks) (*management.Cluster, error) {
+	if content, err := yaml.Marshal(spec); err == nil {
+		path := filepath.Join(CurrentArtifactDir(), spec.Name+"-dry-run.yaml")
+		if err = os.WriteFile(path, content, 0o644); err != nil {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to write the config of cluster %s: %v", spec.Name, err))
+		}
+	}
+
+	if problems := DryRunProblems(client.Management.Types, spec, checks); len(problems) > 0 {
+		return nil, fmt.Errorf("dry run: the config of cluster %s is not valid:\n%s", spec.Name, strings.Join(problems, "\n"))
+	}
+	ginkgo.Skip(fmt.Sprintf("dry run: the config of cluster %s is valid", spec.Name))
+	return nil, nil
+}
+
+// SkipOnDryRun skips the spec with DRY_RUN=true before a cloud resource is created outside of Rancher, for e.g. the clusters of the import suites
+func SkipOnDryRun(resource string) {
+	if IsDryRun() {
+		ginkgo.Skip(fmt.Sprintf("dry run: %s is not created", resource))
+	}
+}
@@ -0,0 +1,105 @@
+package helpers
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/rancher/norman/types"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"k8s.io/utils/pointer"
+)
+
+// testSchemas are a subset of the Rancher schemas of the EKS hosted config
+var testSchemas = map[string]types.Schema{
+	"eksClusterConfigSpec": {ID: "eksClusterConfigSpec", ResourceFields: map[string]types.Field{
+		"amazonCredentialSecret": {Type: "string", Required: true, Create: true},
+		"displayName":            {Type: "string", Required: true, Create: true},
+		"region":                 {Type: "string"},
+		"kubernetesVersion":      {Type: "string"},
+		"tags":                   {Type: "map[string]"},
+		"nodeGroups":             {Type: "array[nodeGroup]"},
+	}},
+	"nodeGroup": {ID: "nodeGroup", ResourceFields: map[string]types.Field{
+		"nodegroupName":     {Type: "string", Required: true, Create: true},
+		"instanceType":      {Type: "string"},
+		"spotInstanceTypes": {Type: "array[string]"},
+		"desiredSize":       {Type: "int", Min: pointer.Int64(0)},
+		"version":           {Type: "string"},
+		"capacityType":      {Type: "enum", Options: []string{"ON_DEMAND", "SPOT"}},
+	}},
+}
+
+func TestSchemaProblems(t *testing.T) {
+	config := map[string]any{
+		"amazonCredentialSecret": "cattle-global-data:cc-abcde",
+		"region":                 "ap-south-1",
+		"tags":                   map[string]any{"owner": "qa", "run-id": 42.0},
+		"ebsCSIDriver":           true,
+		"nodeGroups": []any{
+			map[string]any{"nodegroupName": "ng-1", "desiredSize": 1.5, "capacityType": "SPOT"},
+			map[string]any{"desiredSize": -1.0, "capacityType": "RESERVED"},
+		},
+	}
+	want := []string{
+		"displayName: required by eksClusterConfigSpec",
+		"ebsCSIDriver: not a field of eksClusterConfigSpec",
+		"nodeGroups[0].desiredSize: 1.5 is not an int",
+		"nodeGroups[1].capacityType: \"RESERVED\" is not one of ON_DEMAND, SPOT",
+		"nodeGroups[1].desiredSize: -1 is out of bounds",
+		"nodeGroups[1].nodegroupName: required by nodeGroup",
+		"tags.run-id: 42 is not a string",
+	}
+	if problems := schemaProblems(testSchemas, "eksClusterConfigSpec", config, ""); !reflect.DeepEqual(problems, want) {
+		t.Errorf("got %q,\nwant %q", problems, want)
+	}
+}
+
+func TestVersionProblems(t *testing.T) {
+	for _, tc := range []struct {
+		cluster   string
+		nodes     []string
+		available []string
+		problems  int
+	}{
+		{cluster: "1.30", nodes: []string{"1.29", "1.30"}, available: []string{"1.31", "1.30"}},
+		{cluster: "1.30.5-gke.1014001", nodes: []string{"1.30.5-gke.1014001"}, available: []string{"1.30.5-gke.1014001"}},
+		{cluster: "1.30", problems: 0},
+		{cluster: "1.28", available: []string{"1.31", "1.30"}, problems: 1},
+		{cluster: "1.30", nodes: []string{"1.31"}, available: []string{"1.31", "1.30"}, problems: 1},
+		{cluster: "", available: []string{"1.30"}, problems: 2},
+	} {
+		if problems := versionProblems(tc.cluster, tc.nodes, tc.available); len(problems) != tc.problems {
+			t.Errorf("versionProblems(%s, %v, %v) = %v, want %d problems", tc.cluster, tc.nodes, tc.available, problems, tc.problems)
+		}
+	}
+}
+
+func TestDryRunProblems(t *testing.T) {
+	spec := &ClusterSpec{Name: "hp-ci-abcde", EKSConfig: &management.EKSClusterConfigSpec{
+		AmazonCredentialSecret: "cattle-global-data:cc-abcde",
+		DisplayName:            "hp-ci-abcde",
+		Region:                 "ap-south-1",
+		KubernetesVersion:      pointer.String("1.30"),
+		NodeGroups: &[]management.NodeGroup{
+			{NodegroupName: pointer.String("ng-1"), InstanceType: pointer.String("t3.large"), DesiredSize: pointer.Int64(1)},
+			{NodegroupName: pointer.String("ng-2"), InstanceType: pointer.String("t3.large"), SpotInstanceTypes: &[]string{"m5.large", "x9.huge"}},
+		},
+	}}
+	if instanceTypes := spec.instanceTypes(); !reflect.DeepEqual(instanceTypes, []string{"m5.large", "t3.large", "x9.huge"}) {
+		t.Errorf("got instance types %v", instanceTypes)
+	}
+
+	checks := DryRunChecks{Versions: []string{"1.31", "1.30"}, InstanceTypeAvailable: func(instanceType string) error {
+		if instanceType == "x9.huge" {
+			return errors.New("instance type x9.huge is not offered in region ap-south-1")
+		}
+		return nil
+	}}
+	if problems := DryRunProblems(testSchemas, spec, checks); !reflect.DeepEqual(problems, []string{"instance type x9.huge is not offered in region ap-south-1"}) {
+		t.Errorf("got %v", problems)
+	}
+	if problems := DryRunProblems(map[string]types.Schema{}, spec, checks); len(problems) != 1 {
+		t.Errorf("got %v, want the missing schema only", problems)
+	}
+}
@@ -0,0 +1,23 @@
+package helpers
+
+import (
+	"github.com/onsi/ginkgo/v2"
+
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+)
+
+func init() {
+	// keep the full output of every external command with the other artifacts of the run;
+	// CollectSpecArtifacts moves the records of the commands run by a spec to the spec artifact directory
+	extcli.SetLogDir(ArtifactsDir)
+	// print the commands and their retries with the output of the spec running them rather than on the shared stdout
+	extcli.Logf = func(format string, args ...any) {
+		ginkgo.GinkgoWriter.Printf(format+"\n", args...)
+	}
+}
+
+// WriteFileAsRoot writes the content to the file using sudo, without going through a shell
+func WriteFileAsRoot(path, content string) error {
+	_, err := extcli.New("sudo").WithStdin([]byte(content)).Run("tee", path)
+	return err
+}
@@ -0,0 +1,219 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/defaults"
+	"github.com/rancher/shepherd/pkg/wait"
+	"github.com/rancher/wrangler/pkg/summary"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// unrecoverableErrorKeywords are the words of the cloud errors no retry of the operator can fix, for e.g. an exceeded quota, missing permissions
+// or an invalid request; the waits abort as soon as a cluster reports one of them
+var unrecoverableErrorKeywords = []string{"quota", "accessdenied", "access denied", "unauthorized", "permission_denied", "permission denied",
+	"authorizationfailed", "invalidclienttokenid", "invalid_grant", "invalidparameter"}
+
+// throttlingErrorKeywords are the words of the rate limiting errors of the cloud providers, which the operator recovers from,
+// for e.g. the exceeded API request quotas of GCP
+var throttlingErrorKeywords = []string{"throttl", "rate exceeded", "ratelimit", "rate limit", "too many requests", "error 429", "per minute"}
+
+// isUnrecoverableClusterError returns true if the message is a cloud error no retry of the operator can fix
+func isUnrecoverableClusterError(message string) bool {
+	message = strings.ToLower(message)
+	for _, keyword := range throttlingErrorKeywords {
+		if strings.Contains(message, keyword) {
+			return false
+		}
+	}
+	for _, keyword := range unrecoverableErrorKeywords {
+		if strings.Contains(message, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClusterError is the terminal error state of a cluster, which the waits abort on instead of waiting out their timeout; see FAIL_FAST_AFTER
+type ClusterError struct {
+	Cluster string
+	Message string
+	// For is how long the cluster has been in error
+	For time.Duration
+}
+
+func (e *ClusterError) Error() string {
+	return fmt.Sprintf("cluster %s is in a terminal error state for %s: %s", e.Cluster, e.For.Round(time.Second), e.Message)
+}
+
+// clusterErrorState tracks the error state of a cluster, to tell a terminal error from a transient one
+type clusterErrorState struct {
+	// message is the error of the cluster, and since when the cluster is in error; empty if it is not in error
+	message string
+	since   time.Time
+	// updated is the error of the cluster when it was last updated, which the operator may not have reconciled yet
+	updated string
+}
+
+// observe records the state of the cluster and returns its error if it is terminal: either a cloud error no retry can fix, unless it is the error
+// of before the last update of the cluster, or any error the cluster stays in for the given time; nothing is terminal if after is 0
+func (s *clusterErrorState) observe(cluster *management.Cluster, now time.Time, after time.Duration) error {
+	if cluster.Transitioning != "error" {
+		*s = clusterErrorState{}
+		return nil
+	}
+	if s.since.IsZero() {
+		s.since = now
+	}
+	s.message = cluster.TransitioningMessage
+	if after <= 0 {
+		return nil
+	}
+
+	inError := now.Sub(s.since)
+	stale := s.updated != "" && s.message == s.updated
+	if (isUnrecoverableClusterError(s.message) && !stale) || inError >= after {
+		return &ClusterError{Cluster: cluster.Name, Message: s.message, For: inError}
+	}
+	return nil
+}
+
+// reset gives the cluster the whole time to recover once it is updated, since the update may fix the error
+func (s *clusterErrorState) reset() {
+	s.updated, s.since = s.message, time.Time{}
+}
+
+// clusterErrorsAllowed is true while the current spec expects its cluster to be in error, see AllowClusterErrors
+var clusterErrorsAllowed atomic.Bool
+
+// AllowClusterErrors disables the fail-fast of the waits for the rest of the current spec, which expects its cluster to be in error,
+// for e.g. while checking that Rancher reports a quota error; it must be called from a spec
+func AllowClusterErrors() {
+	clusterErrorsAllowed.Store(true)
+	ginkgo.DeferCleanup(func() {
+		clusterErrorsAllowed.Store(false)
+	})
+}
+
+// failFast returns true if the waits must abort on the terminal error states of the clusters
+func failFast() bool {
+	return runConfig.FailFastAfter > 0 && !clusterErrorsAllowed.Load()
+}
+
+// waitFailFast runs the wait on the cluster, and aborts it if the cluster enters a terminal error state meanwhile (see ClusterError);
+// the cluster is read from its watcher, see WatchedCluster. The abort channel of the wait is closed once it is aborted, so that it stops its watches,
+// see watchWait; waitFailFast returns once the wait is done.
+func waitFailFast(client *rancher.Client, clusterID string, wait func(abort <-chan struct{}) error) error {
+	if !failFast() {
+		return wait(nil)
+	}
+
+	abort := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- wait(abort)
+	}()
+	ticker := time.NewTicker(clusterWatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-ticker.C:
+			var clusterErr *ClusterError
+			if _, err := WatchedCluster(client, clusterID); errors.As(err, &clusterErr) {
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Aborting the wait on cluster %s: %v", clusterID, err))
+				close(abort)
+				<-done
+				return err
+			}
+		}
+	}
+}
+
+// watchWait is wait.WatchWait stopping the watch once the abort channel is closed, see waitFailFast
+func watchWait(abort <-chan struct{}, watchInterface watch.Interface, check wait.WatchCheckFunc) error {
+	finished := make(chan struct{})
+	defer close(finished)
+	go func() {
+		select {
+		case <-abort:
+			watchInterface.Stop()
+		case <-finished:
+		}
+	}()
+	return wait.WatchWait(watchInterface, check)
+}
+
+// watchManagementCluster watches the cluster with the client until the check is done, see watchWait
+func watchManagementCluster(abort <-chan struct{}, client *rancher.Client, clusterID string, check wait.WatchCheckFunc) error {
+	select {
+	case <-abort:
+		return fmt.Errorf("the wait on cluster %s is aborted", clusterID)
+	default:
+	}
+	opts := metav1.ListOptions{FieldSelector: "metadata.name=" + clusterID, TimeoutSeconds: &defaults.WatchTimeoutSeconds}
+	watchInterface, err := client.GetManagementWatchInterface(management.ClusterType, opts)
+	if err != nil {
+		return err
+	}
+	return watchWait(abort, watchInterface, check)
+}
+
+// clusterUpgradeCheck returns the check of the watch events of the cluster done once the summary of the cluster is done; the cluster errors
+// Rancher reports while it can not reach the cluster are ignored, the other ones end the wait as in clusters.WaitClusterToBeUpgraded
+func clusterUpgradeCheck(done func(summary.Summary) bool) wait.WatchCheckFunc {
+	acceptableErrorMessages := []string{
+		"Cluster health check failed: Failed to communicate with API server during namespace check",
+		"the object has been modified",
+	}
+	return func(event watch.Event) (bool, error) {
+		clusterUnstructured, ok := event.Object.(*unstructured.Unstructured)
+		if !ok {
+			return false, nil
+		}
+		summarizedCluster := summary.Summarize(clusterUnstructured)
+		if done(summarizedCluster) {
+			return true, nil
+		}
+		if summarizedCluster.Error && !slices.ContainsFunc(summarizedCluster.Message, func(message string) bool {
+			return slices.Contains(acceptableErrorMessages, message)
+		}) {
+			return false, fmt.Errorf("cluster is in error state: %s", strings.Join(summarizedCluster.Message, "; "))
+		}
+		return false, nil
+	}
+}
+
+/*
+WaitClusterToBeUpgraded is clusters.WaitClusterToBeUpgraded aborting as soon as the cluster enters a terminal error state, instead of waiting out
+its timeout: a cloud error no retry of the operator can fix, for e.g. an exceeded quota, or any error the cluster stays in for FAIL_FAST_AFTER.
+It must be preferred to the shepherd function, for e.g. err = helpers.WaitClusterToBeUpgraded(client, cluster.ID).
+  - @param client Rancher client
+  - @param clusterID ID of the cluster
+  - @returns The error of the wait, a *ClusterError if it was aborted
+*/
+func WaitClusterToBeUpgraded(client *rancher.Client, clusterID string) error {
+	// the watches of clusters.WaitClusterToBeUpgraded, which can be stopped once the wait is aborted
+	return waitFailFast(client, clusterID, func(abort <-chan struct{}) error {
+		err := watchManagementCluster(abort, client, clusterID, clusterUpgradeCheck(func(s summary.Summary) bool {
+			return s.Transitioning && !s.Error && (s.State == "updating" || s.State == "upgrading")
+		}))
+		if err != nil {
+			return err
+		}
+		return watchManagementCluster(abort, client, clusterID, clusterUpgradeCheck(func(s summary.Summary) bool {
+			return s.IsReady()
+		}))
+	})
+}
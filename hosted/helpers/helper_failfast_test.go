@@ -0,0 +1,113 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/wrangler/pkg/summary"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestIsUnrecoverableClusterError(t *testing.T) {
+	tests := []struct {
+		message string
+		want    bool
+	}{
+		{message: "AccessDeniedException: User is not authorized to perform eks:CreateNodegroup", want: true},
+		{message: "googleapi: Error 403: Insufficient regional quota to satisfy request: resource CPUS", want: true},
+		{message: "InvalidParameterException: Subnets specified must be in at least two different AZs", want: true},
+		{message: "ThrottlingException: Rate exceeded", want: false},
+		{message: "googleapi: Error 429: Quota exceeded for quota metric 'Read requests' and limit 'Read requests per minute'", want: false},
+		{message: "waiting for the nodegroup ng-1 to be active", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			if got := isUnrecoverableClusterError(tt.message); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClusterErrorState(t *testing.T) {
+	start := time.Now()
+	inError := func(message string) *management.Cluster {
+		return &management.Cluster{Name: "c-m-abc12", Transitioning: "error", TransitioningMessage: message}
+	}
+	var state clusterErrorState
+
+	// a transient error is terminal once the cluster stays in error for the given time
+	if err := state.observe(inError("ResourceInUseException: nodegroup is updating"), start, 5*time.Minute); err != nil {
+		t.Errorf("a new transient error must not be terminal: %v", err)
+	}
+	if err := state.observe(inError("ResourceInUseException: nodegroup is updating"), start.Add(5*time.Minute), 5*time.Minute); err == nil {
+		t.Error("an error the cluster stays in for 5m must be terminal")
+	}
+	if err := state.observe(inError("AccessDeniedException"), start, 0); err != nil {
+		t.Errorf("nothing is terminal once the fail-fast is disabled: %v", err)
+	}
+
+	// the error is forgotten once the cluster leaves it
+	state.observe(&management.Cluster{Name: "c-m-abc12", Transitioning: "yes"}, start.Add(6*time.Minute), 5*time.Minute)
+	if err := state.observe(inError("ResourceInUseException"), start.Add(7*time.Minute), 5*time.Minute); err != nil {
+		t.Errorf("the error of the cluster must be forgotten once it left it: %v", err)
+	}
+
+	// an unrecoverable error is terminal at once, unless it is the error of before the last update
+	var clusterErr *ClusterError
+	if err := state.observe(inError("AccessDeniedException"), start.Add(8*time.Minute), 5*time.Minute); !errors.As(err, &clusterErr) {
+		t.Errorf("an unrecoverable error must be terminal at once, got %v", err)
+	}
+	state.reset()
+	if err := state.observe(inError("AccessDeniedException"), start.Add(9*time.Minute), 5*time.Minute); err != nil {
+		t.Errorf("the error of before the update must not be terminal: %v", err)
+	}
+	if err := state.observe(inError("AccessDeniedException"), start.Add(14*time.Minute), 5*time.Minute); err == nil {
+		t.Error("the error of before the update must be terminal once the cluster stays in it for 5m")
+	}
+}
+
+func TestWatchWaitAborted(t *testing.T) {
+	watcher := watch.NewFake()
+	abort := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- watchWait(abort, watcher, func(watch.Event) (bool, error) { return false, nil })
+	}()
+	close(abort)
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("got no error from an aborted wait")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("the watch of the aborted wait is not stopped")
+	}
+	if !watcher.IsStopped() {
+		t.Error("the watch of the aborted wait is not stopped")
+	}
+}
+
+func TestClusterUpgradeCheck(t *testing.T) {
+	clusterEvent := func(conditions ...any) watch.Event {
+		return watch.Event{Object: &unstructured.Unstructured{Object: map[string]any{
+			"metadata": map[string]any{"name": "c-m-abc12"},
+			"status":   map[string]any{"conditions": conditions},
+		}}}
+	}
+	check := clusterUpgradeCheck(func(s summary.Summary) bool { return s.IsReady() })
+
+	if done, err := check(clusterEvent(map[string]any{"type": "Ready", "status": "True"})); !done || err != nil {
+		t.Errorf("got %t, %v for a ready cluster", done, err)
+	}
+	if _, err := check(clusterEvent(map[string]any{"type": "Ready", "status": "False", "reason": "Error", "message": "the object has been modified"})); err != nil {
+		t.Errorf("got %v for an acceptable error", err)
+	}
+	if _, err := check(clusterEvent(map[string]any{"type": "Ready", "status": "False", "reason": "Error", "message": "AccessDeniedException"})); err == nil {
+		t.Error("got no error for a cluster in error")
+	}
+}
@@ -0,0 +1,140 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher"
+
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+)
+
+// ParseFeatureFlags parses a comma separated list of Rancher feature flags, for e.g. "aggregated-roletemplates=true,ui-extension=false"
+func ParseFeatureFlags(value string) (map[string]bool, error) {
+	flags := map[string]bool{}
+	for _, flag := range strings.Split(value, ",") {
+		if flag = strings.TrimSpace(flag); flag == "" {
+			continue
+		}
+		name, enabled, found := strings.Cut(flag, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("%q is not a valid feature flag; the expected format is name=true|false", flag)
+		}
+		b, err := strconv.ParseBool(enabled)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a valid feature flag; the expected format is name=true|false", flag)
+		}
+		flags[name] = b
+	}
+	return flags, nil
+}
+
+// GetFeatureFlag returns the effective value of the Rancher feature flag: its value if it is set, its default value otherwise
+func GetFeatureFlag(client *rancher.Client, name string) (bool, error) {
+	feature, err := client.Management.Feature.ByID(name)
+	if err != nil {
+		return false, err
+	}
+	if feature.Value != nil {
+		return *feature.Value, nil
+	}
+	return feature.Status != nil && feature.Status.Default, nil
+}
+
+// rancherRestartCount returns the number of restarts of the rancher pods, or of the rancher container with the docker backend
+func rancherRestartCount() (int, error) {
+	var out string
+	var err error
+	if IsDockerBackend() {
+		out, err = extcli.Docker.Output("inspect", "--format", "{{.RestartCount}}", rancherDockerContainer)
+	} else {
+		out, err = runUpstreamKubectl("get", "pods", "--namespace", CattleSystemNS, "-l", "app=rancher", "-o", "jsonpath={.items[*].status.containerStatuses[*].restartCount}")
+	}
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, field := range strings.Fields(out) {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return 0, err
+		}
+		count += n
+	}
+	return count, nil
+}
+
+/*
+Set a Rancher feature flag; Rancher restarts when a non dynamic feature is toggled, in which case the function waits for it to be up again
+  - @param client Rancher client
+  - @param name Name of the feature flag, for e.g. aggregated-roletemplates
+  - @param value Value of the feature flag
+  - @returns The previous value of the feature flag; the function will fail through Ginkgo in case of issue, for e.g. if the flag is locked
+*/
+func SetFeatureFlag(client *rancher.Client, name string, value bool) (previous bool) {
+	feature, err := client.Management.Feature.ByID(name)
+	Expect(err).To(BeNil())
+	previous, err = GetFeatureFlag(client, name)
+	Expect(err).To(BeNil())
+	if previous == value {
+		return previous
+	}
+	if feature.Status != nil && feature.Status.LockedValue != nil {
+		ginkgo.Fail(fmt.Sprintf("Feature flag %s is locked to %t", name, *feature.Status.LockedValue))
+	}
+	dynamic := feature.Status != nil && feature.Status.Dynamic
+
+	ginkgo.By(fmt.Sprintf("Setting feature flag %s to %t", name, value), func() {
+		restarts, restartsErr := rancherRestartCount()
+		if !dynamic && restartsErr != nil {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to get the restart count of rancher, not waiting for the restart: %v", restartsErr))
+		}
+
+		_, err = client.Management.Feature.Update(feature, map[string]interface{}{"value": value})
+		Expect(err).To(BeNil())
+
+		if !dynamic && restartsErr == nil {
+			EventuallyWithBackoff(func() (int, error) {
+				return rancherRestartCount()
+			}, tools.SetTimeout(5*time.Minute), 5*time.Second).Should(BeNumerically(">", restarts), "Rancher did not restart")
+		}
+		if !dynamic {
+			WaitUntilRancherIsUp(RancherHostname)
+		}
+
+		EventuallyWithBackoff(func() (bool, error) {
+			return GetFeatureFlag(client, name)
+		}, tools.SetTimeout(5*time.Minute), 5*time.Second).Should(Equal(value), fmt.Sprintf("Feature flag %s is not %t", name, value))
+	})
+	return previous
+}
+
+/*
+Set several Rancher feature flags, see SetFeatureFlag
+  - @param client Rancher client
+  - @param flags Values of the feature flags by name
+  - @returns A function restoring the previous values of the flags, for e.g. DeferCleanup(helpers.SetFeatureFlags(client, flags))
+*/
+func SetFeatureFlags(client *rancher.Client, flags map[string]bool) (restore func()) {
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	previous := map[string]bool{}
+	for _, name := range names {
+		previous[name] = SetFeatureFlag(client, name, flags[name])
+	}
+	return func() {
+		for _, name := range names {
+			SetFeatureFlag(client, name, previous[name])
+		}
+	}
+}
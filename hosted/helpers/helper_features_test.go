@@ -0,0 +1,25 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFeatureFlags(t *testing.T) {
+	flags, err := ParseFeatureFlags(" aggregated-roletemplates=true, ui-extension=false,,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := map[string]bool{"aggregated-roletemplates": true, "ui-extension": false}; !reflect.DeepEqual(flags, want) {
+		t.Errorf("got %v, want %v", flags, want)
+	}
+
+	if flags, err = ParseFeatureFlags(""); err != nil || len(flags) != 0 {
+		t.Errorf("got %v, %v for an empty value", flags, err)
+	}
+	for _, value := range []string{"aggregated-roletemplates", "=true", "ui-extension=maybe"} {
+		if _, err = ParseFeatureFlags(value); err == nil {
+			t.Errorf("expected an error for %q", value)
+		}
+	}
+}
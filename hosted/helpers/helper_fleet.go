@@ -0,0 +1,132 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/fleet/pkg/apis/fleet.cattle.io/v1alpha1"
+	"github.com/rancher/rancher/tests/v2/actions/fleet"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	extensionsfleet "github.com/rancher/shepherd/extensions/fleet"
+	"github.com/rancher/shepherd/extensions/workloads/pods"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fleetClusterNameLabel is the label Rancher sets on the Fleet cluster of a downstream cluster, with the ID of the cluster as value
+const fleetClusterNameLabel = "management.cattle.io/cluster-name"
+
+// NewFleetGitRepo returns a GitRepo deploying the paths of the repository to the downstream cluster only;
+// the Fleet examples repository is used if repo is empty, with its simple example if no path is given.
+func NewFleetGitRepo(cluster *management.Cluster, repo, branch string, paths ...string) *v1alpha1.GitRepo {
+	if repo == "" {
+		repo, branch = fleet.ExampleRepo, fleet.BranchName
+		if len(paths) == 0 {
+			paths = []string{fleet.GitRepoPathLinux}
+		}
+	}
+	return &v1alpha1.GitRepo{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      namegen.AppendRandomString("hp-gitrepo"),
+			Namespace: fleet.Namespace,
+		},
+		Spec: v1alpha1.GitRepoSpec{
+			Repo:   repo,
+			Branch: branch,
+			Paths:  paths,
+			Targets: []v1alpha1.GitTarget{{
+				ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{fleetClusterNameLabel: cluster.ID}},
+			}},
+		},
+	}
+}
+
+/*
+Create a Fleet GitRepo on the upstream cluster; it is deleted once the spec is done, which removes its bundles from the targeted clusters
+  - @param client Rancher client
+  - @param gitRepo GitRepo to create, for e.g. from NewFleetGitRepo
+  - @returns The created GitRepo; the function will fail through Ginkgo in case of issue
+*/
+func CreateFleetGitRepo(client *rancher.Client, gitRepo *v1alpha1.GitRepo) *steveV1.SteveAPIObject {
+	var repoObject *steveV1.SteveAPIObject
+	ginkgo.By(fmt.Sprintf("Creating Fleet GitRepo %s for %s", gitRepo.Name, gitRepo.Spec.Repo), func() {
+		var err error
+		repoObject, err = client.Steve.SteveType(extensionsfleet.FleetGitRepoResourceType).Create(gitRepo)
+		Expect(err).To(BeNil())
+	})
+	ginkgo.DeferCleanup(DeleteFleetGitRepo, client, repoObject)
+	return repoObject
+}
+
+/*
+Delete a Fleet GitRepo and wait for it to be removed
+  - @param client Rancher client
+  - @param gitRepo GitRepo to delete
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func DeleteFleetGitRepo(client *rancher.Client, gitRepo *steveV1.SteveAPIObject) {
+	gitRepoClient := client.Steve.SteveType(extensionsfleet.FleetGitRepoResourceType)
+	current, err := gitRepoClient.ByID(gitRepo.ID)
+	if err != nil && strings.Contains(err.Error(), "not found") {
+		return
+	}
+	Expect(err).To(BeNil())
+	Expect(gitRepoClient.Delete(current)).To(Succeed())
+
+	EventuallyWithBackoff(func() bool {
+		_, err := gitRepoClient.ByID(gitRepo.ID)
+		return err != nil && strings.Contains(err.Error(), "not found")
+	}, tools.SetTimeout(5*time.Minute), 5*time.Second).Should(BeTrue(), fmt.Sprintf("GitRepo %s was not deleted", gitRepo.ID))
+}
+
+// fleetGitRepoReady returns whether all the bundles of the GitRepo are ready on its clusters; an error is returned if Fleet reports one,
+// for e.g. if the repository can not be cloned or a bundle fails to deploy.
+func fleetGitRepoReady(gitRepo *steveV1.SteveAPIObject) (bool, error) {
+	if gitRepo.State != nil && gitRepo.State.Error {
+		return false, fmt.Errorf("GitRepo %s is in error: %s", gitRepo.ID, gitRepo.State.Message)
+	}
+	status := &v1alpha1.GitRepoStatus{}
+	if err := steveV1.ConvertToK8sType(gitRepo.Status, status); err != nil {
+		return false, err
+	}
+	if status.Display.Error {
+		return false, fmt.Errorf("GitRepo %s is in error: %s", gitRepo.ID, status.Display.Message)
+	}
+	return status.Summary.DesiredReady > 0 && status.Summary.NotReady == 0 && status.Summary.ErrApplied == 0 &&
+		status.ReadyClusters > 0 && status.ReadyClusters == status.DesiredReadyClusters, nil
+}
+
+/*
+Verify that the bundles of a Fleet GitRepo are deployed and ready on the downstream cluster, and that its pods are healthy
+  - @param client Rancher client
+  - @param gitRepo GitRepo targeting the cluster
+  - @param cluster Downstream cluster
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func VerifyFleetGitRepo(client *rancher.Client, gitRepo *steveV1.SteveAPIObject, cluster *management.Cluster) {
+	ginkgo.By(fmt.Sprintf("Waiting for the bundles of GitRepo %s to be ready on cluster %s", gitRepo.ID, cluster.Name), func() {
+		var fleetErr error
+		Eventually(func() bool {
+			current, err := client.Steve.SteveType(extensionsfleet.FleetGitRepoResourceType).ByID(gitRepo.ID)
+			if err != nil {
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to get GitRepo %s: %v", gitRepo.ID, err))
+				return false
+			}
+			var ready bool
+			ready, fleetErr = fleetGitRepoReady(current)
+			// a Fleet error is final, there is no need to wait any longer
+			return ready || fleetErr != nil
+		}, tools.SetTimeout(15*time.Minute), 15*time.Second).Should(BeTrue(), fmt.Sprintf("GitRepo %s is not ready", gitRepo.ID))
+		Expect(fleetErr).To(BeNil())
+	})
+
+	ginkgo.By(fmt.Sprintf("Checking the pods of cluster %s", cluster.Name), func() {
+		Expect(pods.StatusPods(client, cluster.ID)).To(BeEmpty())
+	})
+}
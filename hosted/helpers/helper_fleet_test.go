@@ -0,0 +1,35 @@
+package helpers
+
+import (
+	"testing"
+
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+)
+
+func TestFleetGitRepoReady(t *testing.T) {
+	for name, tc := range map[string]struct {
+		status  map[string]any
+		ready   bool
+		failure bool
+	}{
+		"ready": {
+			status: map[string]any{"readyClusters": 1, "desiredReadyClusters": 1, "summary": map[string]any{"ready": 1, "desiredReady": 1}},
+			ready:  true,
+		},
+		"not deployed yet": {
+			status: map[string]any{"readyClusters": 0, "desiredReadyClusters": 1, "summary": map[string]any{"ready": 0, "desiredReady": 0}},
+		},
+		"bundle not ready": {
+			status: map[string]any{"readyClusters": 1, "desiredReadyClusters": 1, "summary": map[string]any{"ready": 0, "notReady": 1, "desiredReady": 1}},
+		},
+		"clone error": {
+			status:  map[string]any{"display": map[string]any{"error": true, "message": "repository not found"}},
+			failure: true,
+		},
+	} {
+		ready, err := fleetGitRepoReady(&steveV1.SteveAPIObject{Status: tc.status})
+		if ready != tc.ready || (err != nil) != tc.failure {
+			t.Errorf("%s: got %t, %v", name, ready, err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+// grafanaAnnotation is the body of the Grafana annotations API, see https://grafana.com/docs/grafana/latest/developers/http_api/annotations/
+type grafanaAnnotation struct {
+	// Time and TimeEnd are epoch milliseconds; an annotation with TimeEnd is a region
+	Time    int64    `json:"time"`
+	TimeEnd int64    `json:"timeEnd,omitempty"`
+	Tags    []string `json:"tags"`
+	Text    string   `json:"text"`
+}
+
+// grafanaAnnotationTags returns the tags of the annotations of a suite, so that the dashboards can filter them by provider and Rancher version
+func grafanaAnnotationTags(suite, provider, rancherVersion, runID string) []string {
+	tags := []string{"hosted-providers-e2e", "suite:" + suite, "run:" + runID}
+	if provider != "" {
+		tags = append(tags, "provider:"+provider)
+	}
+	if rancherVersion != "" {
+		tags = append(tags, "rancher:"+rancherVersion)
+	}
+	return tags
+}
+
+// postGrafanaAnnotation posts the annotation to GRAFANA_URL
+func postGrafanaAnnotation(annotation grafanaAnnotation) error {
+	body, err := json.Marshal(annotation)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, runConfig.GrafanaURL+"/api/annotations", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+runConfig.GrafanaAPIToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("grafana returned %s", resp.Status)
+	}
+	return nil
+}
+
+// annotateSuiteStart annotates the start of the suite on GRAFANA_URL; it is called by SuiteConfig, from the first parallel process only,
+// before the specs run. The name of the suite is the one of its report files, see SuiteReportName.
+func annotateSuiteStart() error {
+	suitePath, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	suite := SuiteReportName(ginkgo.Report{SuitePath: suitePath})
+	return postGrafanaAnnotation(grafanaAnnotation{
+		Time: time.Now().UnixMilli(),
+		Tags: grafanaAnnotationTags(suite, Provider, runConfig.RancherVersion, runConfig.RunID),
+		Text: fmt.Sprintf("Suite %s started (run %s)", suite, runConfig.RunID),
+	})
+}
+
+// annotateSuiteEnd annotates the suite on GRAFANA_URL as a region, from its start to its end, with its result;
+// it is called by GenerateSuiteReports once all the parallel processes are done.
+func annotateSuiteEnd(summary SuiteSummary) error {
+	result := "failed"
+	if summary.Succeeded {
+		result = "passed"
+	}
+	return postGrafanaAnnotation(grafanaAnnotation{
+		Time:    summary.StartTime.UnixMilli(),
+		TimeEnd: summary.EndTime.UnixMilli(),
+		Tags:    append(grafanaAnnotationTags(summary.Suite, summary.Provider, summary.RancherVersion, summary.RunID), "result:"+result),
+		Text: fmt.Sprintf("Suite %s %s in %s (run %s): %s", summary.Suite, result, (time.Duration(summary.Seconds) * time.Second).String(),
+			summary.RunID, formatCounts(summary.Counts)),
+	})
+}
+
+// formatCounts returns the spec counts by state, sorted by state, for e.g. "passed=2 skipped=1"
+func formatCounts(counts map[string]int) string {
+	states := make([]string, 0, len(counts))
+	for state := range counts {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+	for i, state := range states {
+		states[i] = fmt.Sprintf("%s=%d", state, counts[state])
+	}
+	return strings.Join(states, " ")
+}
@@ -0,0 +1,56 @@
+package helpers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAnnotateSuiteEnd(t *testing.T) {
+	defer func(url, token string) {
+		runConfig.GrafanaURL, runConfig.GrafanaAPIToken = url, token
+	}(runConfig.GrafanaURL, runConfig.GrafanaAPIToken)
+
+	var annotation grafanaAnnotation
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/annotations" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		authorization = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&annotation)
+	}))
+	defer server.Close()
+	runConfig.GrafanaURL, runConfig.GrafanaAPIToken = server.URL, "glsa_token"
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	summary := SuiteSummary{
+		Suite: "eks-p0", Provider: "eks", RancherVersion: "2.10.3", RunID: "42",
+		StartTime: start, EndTime: start.Add(time.Hour), Seconds: 3600, Succeeded: true, Counts: map[string]int{"passed": 2, "skipped": 1},
+	}
+	if err := annotateSuiteEnd(summary); err != nil {
+		t.Fatal(err)
+	}
+	if authorization != "Bearer glsa_token" {
+		t.Errorf("got authorization %q", authorization)
+	}
+	if annotation.Time != start.UnixMilli() || annotation.TimeEnd != start.Add(time.Hour).UnixMilli() {
+		t.Errorf("the annotation must be a region from the start to the end of the suite: %+v", annotation)
+	}
+	wantTags := []string{"hosted-providers-e2e", "suite:eks-p0", "run:42", "provider:eks", "rancher:2.10.3", "result:passed"}
+	if !reflect.DeepEqual(annotation.Tags, wantTags) {
+		t.Errorf("got tags %v, want %v", annotation.Tags, wantTags)
+	}
+	if annotation.Text != "Suite eks-p0 passed in 1h0m0s (run 42): passed=2 skipped=1" {
+		t.Errorf("got text %q", annotation.Text)
+	}
+
+	runConfig.GrafanaURL = server.URL + "/missing"
+	if err := annotateSuiteEnd(summary); err == nil {
+		t.Error("annotateSuiteEnd() must fail if grafana does not accept the annotation")
+	}
+}
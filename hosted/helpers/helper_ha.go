@@ -0,0 +1,111 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+)
+
+const (
+	// haK3SNodePrefix is the name prefix of the k3s server containers joined to the local k3s cluster
+	haK3SNodePrefix = "k3s-server-"
+	// haK3SNodes is the number of k3s server nodes of an HA setup, including the local one
+	haK3SNodes = 3
+)
+
+// IsRancherHA returns true if rancher must be installed in HA mode (RANCHER_HA=true)
+func IsRancherHA() bool {
+	ha, _ := strconv.ParseBool(RancherHA)
+	return ha
+}
+
+// rancherReplicas returns the number of rancher replicas; one per k3s server node in HA mode
+func rancherReplicas() int {
+	if IsRancherHA() {
+		return haK3SNodes
+	}
+	return 1
+}
+
+/*
+*
+Add k3s server nodes to the local k3s cluster (installed with --cluster-init by InstallK3S) to get a 3-node embedded etcd cluster;
+the nodes are run as privileged docker containers of the rancher/k3s image and join the cluster through K3S_SERVER_IP
+  - @param k kubectl structure
+  - @param k3sVersion version to install, must be the same as the local k3s
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func AddK3SServerNodes(k *kubectl.Kubectl, k3sVersion string) {
+	var token string
+	By("Getting the k3s join token", func() {
+		out, err := extcli.New("sudo").WithTimeout(time.Minute).Sensitive().Output("cat", "/var/lib/rancher/k3s/server/node-token")
+		Expect(err).To(Not(HaveOccurred()))
+		token = out
+	})
+
+	// docker image tags can not contain '+'
+	image := "rancher/k3s:" + strings.ReplaceAll(k3sVersion, "+", "-")
+	for i := 1; i < haK3SNodes; i++ {
+		name := fmt.Sprintf("%s%d", haK3SNodePrefix, i)
+		By(fmt.Sprintf("Joining k3s server node %s", name), func() {
+			args := []string{"run", "-d", "--privileged", "--restart", "unless-stopped",
+				"--name", name, "--hostname", name,
+				"--tmpfs", "/run", "--tmpfs", "/var/run",
+				"-e", "K3S_TOKEN=" + token,
+				image, "server", "--server", fmt.Sprintf("https://%s:6443", K3SServerIP),
+			}
+			out, err := extcli.Docker.Output(args...)
+			GinkgoWriter.Println(out)
+			Expect(err).To(Not(HaveOccurred()))
+		})
+	}
+
+	By("Waiting for all the k3s server nodes to be ready", func() {
+		EventuallyWithBackoff(func() int {
+			out, err := kubectl.RunWithoutErr("get", "nodes", "-l", "node-role.kubernetes.io/control-plane=true",
+				"-o", `jsonpath={range .items[*]}{.status.conditions[?(@.type=="Ready")].status}{"\n"}{end}`)
+			if err != nil {
+				return 0
+			}
+			return strings.Count(out, "True")
+		}, tools.SetTimeout(10*time.Minute), 30*time.Second).Should(Equal(haK3SNodes), "k3s server nodes are not ready")
+	})
+}
+
+/*
+*
+Check that Rancher runs in HA mode: one ready replica per k3s server node, spread across the nodes, and a leader elected
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckRancherHA() {
+	By("Waiting for all the rancher replicas to be ready", func() {
+		EventuallyWithBackoff(func() string {
+			out, _ := kubectl.RunWithoutErr("get", "deployment", "rancher", "--namespace", CattleSystemNS, "-o", "jsonpath={.status.readyReplicas}")
+			return strings.TrimSpace(out)
+		}, tools.SetTimeout(10*time.Minute), 30*time.Second).Should(Equal(strconv.Itoa(haK3SNodes)), "Rancher replicas are not ready")
+	})
+
+	By("Checking the rancher replicas are spread across the nodes", func() {
+		out, err := kubectl.RunWithoutErr("get", "pods", "--namespace", CattleSystemNS, "-l", "app=rancher", "-o", "jsonpath={.items[*].spec.nodeName}")
+		Expect(err).To(Not(HaveOccurred()), out)
+		nodes := map[string]bool{}
+		for _, node := range strings.Fields(out) {
+			nodes[node] = true
+		}
+		Expect(nodes).To(HaveLen(haK3SNodes))
+	})
+
+	By("Checking a rancher leader is elected", func() {
+		EventuallyWithBackoff(func() string {
+			out, _ := kubectl.RunWithoutErr("get", "lease", "cattle-controllers", "--namespace", "kube-system", "-o", "jsonpath={.spec.holderIdentity}")
+			return strings.TrimSpace(out)
+		}, tools.SetTimeout(5*time.Minute), 10*time.Second).Should(HavePrefix("rancher-"), "No rancher leader elected")
+	})
+}
@@ -0,0 +1,210 @@
+package helpers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"sigs.k8s.io/yaml"
+)
+
+// helmRepoIndexTTL is how long the index of a helm repository added with AddHelmRepo is reused before it is fetched again
+const helmRepoIndexTTL = 30 * time.Minute
+
+// helmChartCacheOff is the value of HELM_CHART_CACHE_DIR disabling the chart cache
+const helmChartCacheOff = "off"
+
+// defaultHelmChartCacheDir returns the chart cache shared by the suites of the user, see CachedChart; empty if the user has no cache directory
+func defaultHelmChartCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "hosted-providers-e2e", "charts")
+}
+
+// helmRepo is a helm repository added to the repository file of helm
+type helmRepo struct {
+	Name string
+	URL  string
+}
+
+// helmIndex holds the versions of the charts of the index of a helm repository, from the newest to the oldest
+type helmIndex struct {
+	Entries map[string][]helmIndexEntry `json:"entries"`
+}
+
+// helmIndexEntry is a version of a chart in the index of a helm repository, along with the sha256 digest of its archive
+type helmIndexEntry struct {
+	Version string `json:"version"`
+	Digest  string `json:"digest"`
+}
+
+// helmRepositoryCache returns the directory helm keeps the indexes of the repositories in
+func helmRepositoryCache() string {
+	return helmClient.RepositoryCache()
+}
+
+// helmRepoIndexPath returns the index of the helm repository in the helm repository cache
+func helmRepoIndexPath(repoCache, repo string) string {
+	return filepath.Join(repoCache, repo+"-index.yaml")
+}
+
+// isHelmRepoFresh returns true if the repository is added with the URL and its index was fetched within the TTL
+func isHelmRepoFresh(repos []helmRepo, indexPath, name, url string, ttl time.Duration, now time.Time) bool {
+	added := false
+	for _, repo := range repos {
+		if repo.Name == name && strings.TrimSuffix(repo.URL, "/") == strings.TrimSuffix(url, "/") {
+			added = true
+		}
+	}
+	if !added {
+		return false
+	}
+	info, err := os.Stat(indexPath)
+	return err == nil && now.Sub(info.ModTime()) < ttl
+}
+
+/*
+AddHelmRepo adds the helm repository, or replaces it if it was added with another URL; a repository already added with the same URL is not fetched again
+while its index is less than 30m old, so that the suites adding the same repositories in every spec, for e.g. before installing Rancher back in AfterEach,
+do not fetch the same indexes again and again. The index is shared by all the suites run by the user, like the charts of CachedChart.
+The repository is added with the helm Go SDK, to the same repository file as the helm binary.
+  - @param name Name of the repository, for e.g. rancher-latest
+  - @param url URL of the repository
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func AddHelmRepo(name, url string) {
+	if entries, err := helmClient.Repositories(); err == nil {
+		var repos []helmRepo
+		for _, entry := range entries {
+			repos = append(repos, helmRepo{Name: entry.Name, URL: entry.URL})
+		}
+		if isHelmRepoFresh(repos, helmRepoIndexPath(helmRepositoryCache(), name), name, url, helmRepoIndexTTL, time.Now()) {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Reusing the index of helm repository %s (%s)", name, url))
+			return
+		}
+	}
+	// the repository is replaced if it was added with another URL, and its index is fetched again otherwise
+	Eventually(func() error {
+		return helmClient.AddRepo(name, url)
+	}, tools.SetTimeout(2*time.Minute), 20*time.Second).Should(Succeed())
+}
+
+// readHelmIndex reads the index of a helm repository
+func readHelmIndex(indexPath string) (*helmIndex, error) {
+	content, err := os.ReadFile(indexPath)
+	if err != nil {
+		return nil, err
+	}
+	index := &helmIndex{}
+	if err = yaml.Unmarshal(content, index); err != nil {
+		return nil, fmt.Errorf("failed to read the helm index %s: %w", indexPath, err)
+	}
+	return index, nil
+}
+
+// chartEntry returns the version of the chart in the index, or its newest stable version if version is empty; nil if there is none
+func (index *helmIndex) chartEntry(chart, version string) *helmIndexEntry {
+	for i, entry := range index.Entries[chart] {
+		if entry.Version == version || strings.TrimPrefix(entry.Version, "v") == strings.TrimPrefix(version, "v") ||
+			(version == "" && !strings.Contains(entry.Version, "-")) {
+			return &index.Entries[chart][i]
+		}
+	}
+	return nil
+}
+
+// fileDigest returns the sha256 digest of the file content
+func fileDigest(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// cachedChartArchive returns the archive of the chart version of the entry in the cache directory, pulled in the cache with pull
+// if it is not there yet; pull writes the archive in the given directory. The archive is stored by its digest, which it is checked against.
+func cachedChartArchive(cacheDir string, entry *helmIndexEntry, pull func(dir string) error) (string, error) {
+	if entry.Digest == "" {
+		return "", fmt.Errorf("version %s has no digest in the index", entry.Version)
+	}
+	archive := filepath.Join(cacheDir, "sha256", entry.Digest+".tgz")
+	if _, err := os.Stat(archive); err == nil {
+		return archive, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(archive), 0o755); err != nil {
+		return "", err
+	}
+	// the archive is pulled next to its final path, so that it is moved in the cache at once; the parallel processes may pull the same archive
+	pullDir, err := os.MkdirTemp(filepath.Dir(archive), "pull-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(pullDir)
+	if err = pull(pullDir); err != nil {
+		return "", err
+	}
+	pulled, err := filepath.Glob(filepath.Join(pullDir, "*.tgz"))
+	if err != nil || len(pulled) != 1 {
+		return "", fmt.Errorf("expected a single chart archive to be pulled, got %v", pulled)
+	}
+	digest, err := fileDigest(pulled[0])
+	if err != nil {
+		return "", err
+	}
+	if digest != entry.Digest {
+		return "", fmt.Errorf("the digest of the pulled archive %s is %s, the index has %s", filepath.Base(pulled[0]), digest, entry.Digest)
+	}
+	return archive, os.Rename(pulled[0], archive)
+}
+
+/*
+CachedChart returns the archive of a chart version from the chart cache HELM_CHART_CACHE_DIR, which the chart is installed from instead of
+being downloaded again, for e.g. helm upgrade --install rancher-backup <archive>; the archive is pulled in the cache the first time it is needed.
+The archives are stored by the sha256 digest of the index of the repository, so that the same archive is shared by the suites and the repositories
+and a chart republished under the same version is pulled again. The chart reference repo/chart is returned if the cache is disabled, or if the
+version is not found in the index, so that helm resolves it as before.
+  - @param repo Name of the helm repository, added beforehand, for e.g. with AddHelmRepo
+  - @param chart Name of the chart
+  - @param version Version of the chart; its newest stable version if empty
+  - @returns The path of the chart archive, or the chart reference; the function will fail through Ginkgo in case of issue
+*/
+func CachedChart(repo, chart, version string) string {
+	reference := repo + "/" + chart
+	if runConfig.HelmChartCacheDir == "" || runConfig.HelmChartCacheDir == helmChartCacheOff {
+		return reference
+	}
+	index, err := readHelmIndex(helmRepoIndexPath(helmRepositoryCache(), repo))
+	Expect(err).To(BeNil())
+	entry := index.chartEntry(chart, version)
+	if entry == nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Version %q of chart %s is not in the index of the repository, it is not cached", version, reference))
+		return reference
+	}
+
+	archive, err := cachedChartArchive(runConfig.HelmChartCacheDir, entry, func(dir string) error {
+		Eventually(func() error {
+			return helmClient.Pull(reference, entry.Version, dir)
+		}, tools.SetTimeout(2*time.Minute), 20*time.Second).Should(Succeed())
+		return nil
+	})
+	Expect(err).To(BeNil(), "Failed to cache version %s of chart %s", entry.Version, reference)
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Using version %s of chart %s from the chart cache: %s", entry.Version, reference, archive))
+	return archive
+}
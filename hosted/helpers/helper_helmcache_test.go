@@ -0,0 +1,86 @@
+package helpers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIsHelmRepoFresh(t *testing.T) {
+	now := time.Now()
+	indexPath := filepath.Join(t.TempDir(), "rancher-latest-index.yaml")
+	if err := os.WriteFile(indexPath, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	repos := []helmRepo{{Name: "rancher-latest", URL: "https://releases.rancher.com/server-charts/latest/"}}
+
+	if !isHelmRepoFresh(repos, indexPath, "rancher-latest", "https://releases.rancher.com/server-charts/latest", time.Hour, now) {
+		t.Error("a repository added with the same URL and a recent index must be reused")
+	}
+	if isHelmRepoFresh(repos, indexPath, "rancher-latest", "https://charts.rancher.com/server-charts/prime", time.Hour, now) {
+		t.Error("a repository added with another URL must be replaced")
+	}
+	if isHelmRepoFresh(repos, indexPath, "rancher-latest", "https://releases.rancher.com/server-charts/latest", time.Hour, now.Add(2*time.Hour)) {
+		t.Error("an old index must be fetched again")
+	}
+	if isHelmRepoFresh(nil, indexPath, "rancher-latest", "https://releases.rancher.com/server-charts/latest", time.Hour, now) {
+		t.Error("a repository not added yet must be added")
+	}
+}
+
+func TestChartEntry(t *testing.T) {
+	index := &helmIndex{Entries: map[string][]helmIndexEntry{
+		"rancher-eks-operator": {
+			{Version: "106.0.0+up1.7.0-rc.1", Digest: "rc"},
+			{Version: "105.1.0+up1.6.2", Digest: "stable"},
+			{Version: "105.0.0+up1.6.0", Digest: "old"},
+		},
+	}}
+
+	for version, want := range map[string]string{"": "stable", "105.0.0+up1.6.0": "old", "106.0.0+up1.7.0-rc.1": "rc"} {
+		if entry := index.chartEntry("rancher-eks-operator", version); entry == nil || entry.Digest != want {
+			t.Errorf("chartEntry(%q) = %+v, want the entry %s", version, entry, want)
+		}
+	}
+	if entry := index.chartEntry("rancher-eks-operator", "104.0.0"); entry != nil {
+		t.Errorf("chartEntry() of a missing version = %+v", entry)
+	}
+}
+
+func TestCachedChartArchive(t *testing.T) {
+	content := []byte("chart archive")
+	sum := sha256.Sum256(content)
+	entry := &helmIndexEntry{Version: "105.1.0", Digest: hex.EncodeToString(sum[:])}
+	cacheDir := t.TempDir()
+	pulls := 0
+	pull := func(dir string) error {
+		pulls++
+		return os.WriteFile(filepath.Join(dir, "rancher-eks-operator-105.1.0.tgz"), content, 0o644)
+	}
+
+	for i := 0; i < 2; i++ {
+		archive, err := cachedChartArchive(cacheDir, entry, pull)
+		if err != nil {
+			t.Fatalf("cachedChartArchive() = %v", err)
+		}
+		if want := filepath.Join(cacheDir, "sha256", entry.Digest+".tgz"); archive != want {
+			t.Errorf("got archive %s, want %s", archive, want)
+		}
+	}
+	if pulls != 1 {
+		t.Errorf("got %d pulls, want the archive to be pulled once", pulls)
+	}
+
+	// an archive which does not match the digest of the index is not cached
+	changed := &helmIndexEntry{Version: "105.1.0", Digest: "0000"}
+	if _, err := cachedChartArchive(cacheDir, changed, pull); err == nil {
+		t.Error("an archive not matching the digest of the index must be rejected")
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "sha256", "0000.tgz")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("the rejected archive must not be cached: %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/hosted-providers-e2e/pkg/helmsdk"
+)
+
+// helmClient adds the helm repositories and installs the charts with the helm Go SDK, logging through GinkgoLogr
+var helmClient = helmsdk.New(func(format string, args ...any) {
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf(format, args...))
+})
+
+// rancherHeadRegexp matches the head versions whose images are published on Docker Hub, 2.11 and up; the older ones are on the staging registry
+var rancherHeadRegexp = regexp.MustCompile(`^[2-9]\.(1[1-9]|[2-9]\d)$`)
+
+// rancherStagingRegistry is the registry of the Rancher head images before 2.11 and of the prime-optimus release candidates
+const rancherStagingRegistry = "stgregistry.suse.com"
+
+/*
+rancherManagerRelease returns the release of the Rancher chart of the channel, with the values the helm flags of rancher.DeployRancherManager
+of ele-testhelpers set, so that the index of the extraEnv values is the same: the head versions and the prime-optimus release candidates set extraEnv[1].
+  - @param hostname Hostname of Rancher
+  - @param channel Rancher channel, the chart is installed from the rancher-<channel> repository
+  - @param version Rancher version, for e.g. 2.9.3-rc2; devel for the head version, the newest stable version if empty or latest
+  - @param headVersion Head version installed with devel, for e.g. 2.9 or head
+  - @param privateCA true if Rancher uses the certificate of the private CA, see CreateRancherTLSSecrets
+  - @returns The release of the Rancher chart
+*/
+func rancherManagerRelease(hostname, channel, version, headVersion string, privateCA bool) (helmsdk.Release, error) {
+	release := helmsdk.Release{
+		Name:            "rancher",
+		Namespace:       CattleSystemNS,
+		Chart:           "rancher-" + channel + "/rancher",
+		CreateNamespace: true,
+		Wait:            true,
+		WaitForJobs:     true,
+		Values:          map[string]any{},
+	}
+	set := []string{
+		"hostname=" + hostname,
+		"bootstrapPassword=" + RancherPassword,
+		"extraEnv[0].name=CATTLE_SERVER_URL",
+		"extraEnv[0].value=https://" + hostname,
+		"replicas=1",
+		"useBundledSystemChart=true",
+	}
+
+	switch {
+	case version == "" || version == "latest":
+	case version == "devel":
+		release.Devel = true
+		switch {
+		case headVersion == "head":
+			set = append(set, "rancherImageTag=head", "extraEnv[1].name=CATTLE_AGENT_IMAGE", "extraEnv[1].value=rancher/rancher-agent:head")
+		case rancherHeadRegexp.MatchString(headVersion):
+			set = append(set, "rancherImageTag=v"+headVersion+"-head", "extraEnv[1].name=CATTLE_AGENT_IMAGE",
+				"extraEnv[1].value=rancher/rancher-agent:v"+headVersion+"-head")
+		default:
+			set = append(set, "rancherImageTag=v"+headVersion+"-head", "rancherImage="+rancherStagingRegistry+"/rancher/rancher",
+				"extraEnv[1].name=CATTLE_AGENT_IMAGE", "extraEnv[1].value="+rancherStagingRegistry+"/rancher/rancher-agent:v"+headVersion+"-head")
+		}
+	case strings.Contains(version, "-rc") || strings.Contains(version, "-alpha"):
+		release.Devel, release.Version = true, version
+		if strings.Contains(channel, "prime-optimus") {
+			set = append(set, "rancherImage="+rancherStagingRegistry+"/rancher/rancher", "extraEnv[1].name=CATTLE_AGENT_IMAGE",
+				"extraEnv[1].value="+rancherStagingRegistry+"/rancher/rancher-agent:v"+version)
+		}
+	default:
+		release.Version = version
+	}
+
+	if privateCA {
+		set = append(set, "ingress.tls.source=secret", "privateCA=true")
+	}
+	return release, helmsdk.SetValues(release.Values, set...)
+}
+
+// installRelease installs or upgrades the release with the helm SDK, see helmsdk.Client.InstallOrUpgrade
+func installRelease(release helmsdk.Release) {
+	installed, err := helmClient.InstallOrUpgrade(context.Background(), release)
+	Expect(err).To(BeNil(), "Failed to install release %s", release.Name)
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Release %s revision %d: chart %s, status %s", installed.Name, installed.Version,
+		installed.Chart.Metadata.Version, installed.Info.Status))
+}
@@ -0,0 +1,63 @@
+package helpers
+
+import "testing"
+
+func TestRancherManagerRelease(t *testing.T) {
+	for _, tc := range []struct {
+		name, channel, version, headVersion string
+		privateCA                           bool
+		wantVersion                         string
+		wantDevel                           bool
+		// want are the values expected besides the ones of every release
+		want map[string]string
+	}{
+		{name: "latest", channel: "latest", version: "latest"},
+		{name: "stable version", channel: "stable", version: "2.9.3", wantVersion: "2.9.3"},
+		{name: "release candidate", channel: "latest", version: "2.10.1-rc2", wantVersion: "2.10.1-rc2", wantDevel: true},
+		{name: "prime-optimus release candidate", channel: "prime-optimus", version: "2.10.1-rc2", wantVersion: "2.10.1-rc2", wantDevel: true,
+			want: map[string]string{"rancherImage": "stgregistry.suse.com/rancher/rancher", "agent": "stgregistry.suse.com/rancher/rancher-agent:v2.10.1-rc2"}},
+		{name: "head", channel: "latest", version: "devel", headVersion: "head", wantDevel: true,
+			want: map[string]string{"rancherImageTag": "head", "agent": "rancher/rancher-agent:head"}},
+		{name: "2.11 head", channel: "latest", version: "devel", headVersion: "2.11", wantDevel: true,
+			want: map[string]string{"rancherImageTag": "v2.11-head", "agent": "rancher/rancher-agent:v2.11-head"}},
+		{name: "2.9 head", channel: "latest", version: "devel", headVersion: "2.9", wantDevel: true,
+			want: map[string]string{"rancherImageTag": "v2.9-head", "rancherImage": "stgregistry.suse.com/rancher/rancher", "agent": "stgregistry.suse.com/rancher/rancher-agent:v2.9-head"}},
+		{name: "private CA", channel: "prime", privateCA: true, want: map[string]string{"privateCA": "true"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			release, err := rancherManagerRelease("rancher.example.com", tc.channel, tc.version, tc.headVersion, tc.privateCA)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if release.Chart != "rancher-"+tc.channel+"/rancher" || release.Namespace != CattleSystemNS || !release.Wait || !release.CreateNamespace {
+				t.Errorf("got release %+v", release)
+			}
+			if release.Version != tc.wantVersion || release.Devel != tc.wantDevel {
+				t.Errorf("got version %q devel %t, want %q devel %t", release.Version, release.Devel, tc.wantVersion, tc.wantDevel)
+			}
+			if release.Values["hostname"] != "rancher.example.com" || release.Values["replicas"] != int64(1) {
+				t.Errorf("got values %v", release.Values)
+			}
+
+			extraEnv, _ := release.Values["extraEnv"].([]any)
+			if len(extraEnv) == 0 || extraEnv[0].(map[string]any)["value"] != "https://rancher.example.com" {
+				t.Fatalf("got extraEnv %v, want CATTLE_SERVER_URL first", extraEnv)
+			}
+			agent := ""
+			if len(extraEnv) > 1 {
+				agent, _ = extraEnv[1].(map[string]any)["value"].(string)
+			}
+			if agent != tc.want["agent"] {
+				t.Errorf("got agent image %q, want %q", agent, tc.want["agent"])
+			}
+			for _, key := range []string{"rancherImage", "rancherImageTag"} {
+				if got, _ := release.Values[key].(string); got != tc.want[key] {
+					t.Errorf("got %s %q, want %q", key, got, tc.want[key])
+				}
+			}
+			if _, privateCA := release.Values["privateCA"]; privateCA != tc.privateCA {
+				t.Errorf("got privateCA %t, want %t", privateCA, tc.privateCA)
+			}
+		})
+	}
+}
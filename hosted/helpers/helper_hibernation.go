@@ -0,0 +1,90 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// activeState is the state of a cluster managed by Rancher and reachable through its agent
+const activeState = "active"
+
+// leftAndResumedState returns true if the transitions show the cluster leaving the given state and ending back in it
+func leftAndResumedState(transitions []ClusterTransition, state string) bool {
+	if len(transitions) == 0 || transitions[len(transitions)-1].State != state {
+		return false
+	}
+	for _, transition := range transitions {
+		if transition.State != state {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Check that Rancher handles a hibernated cluster, for e.g. an AKS cluster stopped on Azure or a cluster whose nodegroups/nodepools are all
+scaled to zero: the cluster agent, which can not run anymore, must be reported as disconnected, while the cluster is kept and remains
+provisioned so that it can be resumed.
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @returns The up-to-date cluster; the function will fail through Ginkgo in case of issue
+*/
+func CheckClusterHibernated(client *rancher.Client, cluster *management.Cluster) *management.Cluster {
+	ginkgo.By(fmt.Sprintf("Waiting for the agent of cluster %s to be reported as disconnected", cluster.Name), func() {
+		EventuallyWithBackoff(func() (bool, error) {
+			var err error
+			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			if err != nil {
+				return false, err
+			}
+			return clusterAgentDisconnected(cluster), nil
+		}, tools.SetTimeout(15*time.Minute), 10*time.Second).Should(BeTrue(), fmt.Sprintf("The agent of cluster %s is still connected", cluster.Name))
+	})
+
+	ginkgo.By(fmt.Sprintf("Checking cluster %s is still provisioned", cluster.Name), func() {
+		provisioned := clusterCondition(cluster, "Provisioned")
+		Expect(provisioned).ToNot(BeNil())
+		Expect(provisioned.Status).To(Equal("True"), provisioned.Message)
+	})
+	return cluster
+}
+
+/*
+Check that Rancher resumes the management of a cluster once it is woken up from hibernation: the cluster must become active again, and the
+transitions recorded by RecordClusterTransitions since the hibernation must show it leaving the active state and coming back to it.
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @param since Time the cluster was hibernated
+  - @returns The up-to-date cluster; the function will fail through Ginkgo in case of issue
+*/
+func CheckClusterResumed(client *rancher.Client, cluster *management.Cluster, since time.Time) *management.Cluster {
+	ginkgo.By(fmt.Sprintf("Waiting for cluster %s to be resumed", cluster.Name), func() {
+		var err error
+		cluster, err = WaitUntilClusterIsReady(cluster, client)
+		Expect(err).To(BeNil())
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Cluster %s was resumed after %s", cluster.Name, time.Since(since).Round(time.Second)))
+	})
+
+	ginkgo.By(fmt.Sprintf("Checking the transitions of cluster %s", cluster.Name), func() {
+		// the last transition may be recorded a bit after the cluster is reported active
+		Eventually(func() []ClusterTransition {
+			return ClusterTransitionsOf(cluster.ID, since)
+		}, "1m", "5s").Should(Satisfy(func(transitions []ClusterTransition) bool {
+			return leftAndResumedState(transitions, activeState)
+		}), func() string {
+			var lines []string
+			for _, transition := range ClusterTransitionsOf(cluster.ID, since) {
+				lines = append(lines, transition.String())
+			}
+			return fmt.Sprintf("Cluster %s did not go through hibernation and back to %s:\n%s", cluster.Name, activeState, strings.Join(lines, "\n"))
+		})
+	})
+	return cluster
+}
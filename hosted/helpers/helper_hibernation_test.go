@@ -0,0 +1,24 @@
+package helpers
+
+import "testing"
+
+func TestLeftAndResumedState(t *testing.T) {
+	for _, tc := range []struct {
+		states []string
+		want   bool
+	}{
+		{states: nil, want: false},
+		{states: []string{"active"}, want: false},
+		{states: []string{"active", "unavailable"}, want: false},
+		{states: []string{"unavailable", "active"}, want: true},
+		{states: []string{"active", "unavailable", "updating", "active"}, want: true},
+	} {
+		var transitions []ClusterTransition
+		for _, state := range tc.states {
+			transitions = append(transitions, ClusterTransition{State: state})
+		}
+		if got := leftAndResumedState(transitions, "active"); got != tc.want {
+			t.Errorf("leftAndResumedState(%v) = %t, want %t", tc.states, got, tc.want)
+		}
+	}
+}
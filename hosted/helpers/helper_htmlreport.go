@@ -0,0 +1,188 @@
+package helpers
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+// RunReport is the content of the HTML report of a run, built from the summaries of its suites
+type RunReport struct {
+	RunID       string
+	PipelineURL string
+	Generated   time.Time
+	Suites      []SuiteSummary
+	// Clusters are the rancher clusters created by the specs of the run
+	Clusters []Resource
+	// Operations are the timed operations of the specs of the run, oldest first
+	Operations []OperationMetric
+	// Upgrades are the operations with a kubernetes version transition
+	Upgrades []OperationMetric
+	Failures []RunReportFailure
+	// Cost is the estimated spend of the clusters of all the suites
+	Cost CostEstimate
+}
+
+// RunReportFailure is a failed spec of a suite of the run
+type RunReportFailure struct {
+	Suite string
+	SpecSummary
+}
+
+// newRunReport returns the report of the run from the summaries of its suites; the summaries of other runs are left out
+func newRunReport(runID, pipelineURL string, summaries []SuiteSummary) RunReport {
+	report := RunReport{RunID: runID, PipelineURL: pipelineURL, Generated: time.Now().UTC()}
+	for _, summary := range summaries {
+		if summary.RunID == runID {
+			report.Suites = append(report.Suites, summary)
+		}
+	}
+	sort.SliceStable(report.Suites, func(i, j int) bool {
+		return report.Suites[i].StartTime.Before(report.Suites[j].StartTime)
+	})
+
+	for _, suite := range report.Suites {
+		report.Cost.Add(suite.Cost)
+		for _, spec := range suite.Specs {
+			report.Clusters = append(report.Clusters, spec.Clusters...)
+			report.Operations = append(report.Operations, spec.Operations...)
+			if spec.Failure != "" {
+				report.Failures = append(report.Failures, RunReportFailure{Suite: suite.Suite, SpecSummary: spec})
+			}
+		}
+	}
+	sort.SliceStable(report.Operations, func(i, j int) bool {
+		return report.Operations[i].Start.Before(report.Operations[j].Start)
+	})
+	for _, operation := range report.Operations {
+		if operation.ToVersion != "" {
+			report.Upgrades = append(report.Upgrades, operation)
+		}
+	}
+	return report
+}
+
+// runReportTemplate renders a RunReport; the values are escaped by html/template
+var runReportTemplate = template.Must(template.New("run-report").Funcs(template.FuncMap{
+	"duration": func(seconds float64) string {
+		return (time.Duration(seconds) * time.Second).String()
+	},
+	"hours": func(hours float64) string {
+		return fmt.Sprintf("%.1f", hours)
+	},
+	"usd": func(usd float64) string {
+		return fmt.Sprintf("$%.2f", usd)
+	},
+	"pairs": joinByProvider,
+	"time": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>hosted-providers-e2e run {{.RunID}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #eee; }
+.passed { color: #1a7f37; }
+.failed { color: #cf222e; }
+pre { background: #f6f8fa; padding: 8px; white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>Run {{.RunID}}</h1>
+<p>{{if .PipelineURL}}<a href="{{.PipelineURL}}">{{.PipelineURL}}</a> - {{end}}generated at {{time .Generated}}</p>
+
+<h2>Suites</h2>
+<table>
+<tr><th>Suite</th><th>Provider</th><th>Rancher</th><th>Start</th><th>Duration</th><th>Result</th><th>Specs</th><th>Estimated cost</th></tr>
+{{range .Suites}}<tr><td>{{.Suite}}</td><td>{{.Provider}}</td><td>{{.RancherVersion}}</td><td>{{time .StartTime}}</td><td>{{duration .Seconds}}</td>
+<td class="{{if .Succeeded}}passed">passed{{else}}failed">failed{{end}}</td><td>{{range $state, $count := .Counts}}{{$state}}: {{$count}} {{end}}{{if .PassedOnRetry}}(passed on retry: {{.PassedOnRetry}}){{end}}</td><td>{{usd .Cost.USD}}</td></tr>
+{{end}}</table>
+
+<h2>Environment</h2>
+<table>
+<tr><th>Suite</th><th>Rancher server</th><th>Operator charts</th><th>Upstream k8s</th><th>Downstream k8s</th><th>Locations</th><th>CLI versions</th><th>Git SHA</th></tr>
+{{range .Suites}}{{$suite := .Suite}}{{with .Metadata}}<tr><td>{{$suite}}</td><td>{{.RancherServerVersion}}</td><td>{{pairs .OperatorChartVersions}}</td><td>{{.UpstreamK8sVersion}}</td>
+<td>{{.DownstreamK8sVersion}}</td><td>{{pairs .Locations}}</td><td>{{pairs .CLIVersions}}</td><td>{{.GitSHA}}</td></tr>
+{{end}}{{end}}</table>
+
+<h2>Estimated cost: {{usd .Cost.USD}}</h2>
+<p>Approximate on-demand spend, from the node count of the clusters at creation (see COST_PRICES).</p>
+<table>
+<tr><th>Control plane</th><th>Hours</th></tr>
+{{range $provider, $hours := .Cost.ControlPlaneHours}}<tr><td>{{$provider}}</td><td>{{hours $hours}}</td></tr>
+{{end}}<tr><th>Instance type</th><th>Instance hours</th></tr>
+{{range $instanceType, $hours := .Cost.InstanceHours}}<tr><td>{{$instanceType}}</td><td>{{hours $hours}}</td></tr>
+{{end}}</table>
+{{if .Cost.Unpriced}}<p>Instance types without price, left out of the estimate: {{range .Cost.Unpriced}}{{.}} {{end}}</p>{{end}}
+
+<h2>Clusters created</h2>
+{{if .Clusters}}<table>
+<tr><th>Name</th><th>ID</th><th>Provider</th><th>Region</th><th>Kubernetes version</th><th>Created</th><th>Deleted</th><th>Spec</th><th>Correlation ID</th></tr>
+{{range .Clusters}}<tr><td>{{.Name}}</td><td>{{.ID}}</td><td>{{.Provider}}</td><td>{{.Region}}</td><td>{{.KubernetesVersion}}</td>
+<td>{{time .CreatedAt}}</td><td>{{if .DeletedAt}}{{time .DeletedAt}}{{else}}not deleted{{end}}</td><td>{{.Spec}}</td><td>{{.CorrelationID}}</td></tr>
+{{end}}</table>{{else}}<p>None</p>{{end}}
+
+<h2>Operations</h2>
+{{if .Operations}}<table>
+<tr><th>Start</th><th>Operation</th><th>Provider</th><th>Cluster</th><th>Duration</th><th>Result</th><th>Spec</th></tr>
+{{range .Operations}}<tr><td>{{time .Start}}</td><td>{{.Operation}}</td><td>{{.Provider}}</td><td>{{.Cluster}}</td><td>{{duration .Seconds}}</td>
+<td class="{{if .Success}}passed">passed{{else}}failed">failed{{end}}</td><td>{{.Spec}}</td></tr>
+{{end}}</table>{{else}}<p>None</p>{{end}}
+
+<h2>Version transitions</h2>
+{{if .Upgrades}}<table>
+<tr><th>Start</th><th>Operation</th><th>Cluster</th><th>From</th><th>To</th><th>Duration</th><th>Result</th></tr>
+{{range .Upgrades}}<tr><td>{{time .Start}}</td><td>{{.Operation}}</td><td>{{.Cluster}}</td><td>{{.FromVersion}}</td><td>{{.ToVersion}}</td><td>{{duration .Seconds}}</td>
+<td class="{{if .Success}}passed">passed{{else}}failed">failed{{end}}</td></tr>
+{{end}}</table>{{else}}<p>None</p>{{end}}
+
+<h2>Failures</h2>
+{{range .Failures}}<h3 class="failed">{{.Suite}}: {{.Name}}</h3>
+<p>{{.Location}}{{if .CorrelationID}} - correlation ID {{.CorrelationID}}{{end}}</p>
+<pre>{{.Failure}}</pre>
+{{if .KnownIssues}}<p>Known issue: {{range .KnownIssues}}{{.}} {{end}}</p>{{end}}
+{{if .Lifecycle}}<h4>Cluster lifecycle</h4>
+<table>
+<tr><th>Time</th><th>Cluster</th><th>Call</th><th>Step</th><th>Resulting states</th></tr>
+{{range .Lifecycle}}<tr{{if .Failed}} class="failed"{{end}}><td>{{time .Time}}</td><td>{{.ClusterName}}</td><td>{{.Call}}{{if .Error}}: {{.Error}}{{end}}</td><td>{{.Step}}</td>
+<td>{{range .States}}{{.}}<br>{{end}}{{if .Failed}}<b>the spec failed during this step</b>{{end}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Transitions}}<h4>Cluster transitions</h4>
+<pre>{{range .Transitions}}{{.}}
+{{end}}</pre>{{end}}
+{{else}}<p>None</p>
+{{end}}</body>
+</html>
+`))
+
+// GenerateRunReport renders the HTML report of the current run, run-report.html in ArtifactsDir, from the <suite>-summary.json files
+// of its suites: the estimated cost, the clusters created, the operations performed with their duration, the kubernetes version transitions and the failures
+// with the timeline of the cluster transitions. GenerateSuiteReports regenerates it at the end of every suite, so that it covers all the
+// suites of the run sharing ArtifactsDir.
+func GenerateRunReport() error {
+	report := newRunReport(runConfig.RunID, runConfig.PipelineURL, readArtifacts[SuiteSummary]("*-summary.json"))
+	var content bytes.Buffer
+	if err := runReportTemplate.Execute(&content, report); err != nil {
+		return err
+	}
+	path := filepath.Join(ArtifactsDir, "run-report.html")
+	if err := os.WriteFile(path, content.Bytes(), 0o644); err != nil {
+		return err
+	}
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Wrote the report of run %s (%d suites) to %s", report.RunID, len(report.Suites), path))
+	return nil
+}
@@ -0,0 +1,82 @@
+package helpers
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewRunReport(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	upgrade := OperationMetric{Operation: OperationUpgrade, Cluster: "eks-hp-ci-abcde", Start: start.Add(time.Hour), Seconds: 600, Success: true, FromVersion: "1.30", ToVersion: "1.31"}
+	scale := OperationMetric{Operation: OperationScale, Cluster: "eks-hp-ci-abcde", Start: start.Add(30 * time.Minute), Seconds: 120, Success: true}
+	summaries := []SuiteSummary{
+		{Suite: "eks-p1", RunID: "42", StartTime: start.Add(2 * time.Hour), Cost: CostEstimate{ControlPlaneHours: map[string]float64{"eks": 2}, USD: 0.2}, Specs: []SpecSummary{
+			{Name: "should upgrade", Failure: "timed out", Transitions: []string{"eks-hp-ci-abcde state=updating"}, Operations: []OperationMetric{upgrade}},
+		}},
+		{Suite: "eks-p0", RunID: "42", StartTime: start, Cost: CostEstimate{ControlPlaneHours: map[string]float64{"eks": 1}, USD: 0.1}, Specs: []SpecSummary{
+			{Name: "should provision", Clusters: []Resource{{Kind: ResourceRancherCluster, Name: "eks-hp-ci-abcde"}}, Operations: []OperationMetric{scale}},
+		}},
+		{Suite: "eks-p0", RunID: "41", Specs: []SpecSummary{{Name: "should provision", Failure: "other run"}}},
+	}
+
+	report := newRunReport("42", "", summaries)
+	if len(report.Suites) != 2 || report.Suites[0].Suite != "eks-p0" || report.Suites[1].Suite != "eks-p1" {
+		t.Fatalf("unexpected suites: %+v", report.Suites)
+	}
+	if len(report.Clusters) != 1 || report.Clusters[0].Name != "eks-hp-ci-abcde" {
+		t.Errorf("unexpected clusters: %+v", report.Clusters)
+	}
+	if len(report.Operations) != 2 || report.Operations[0].Operation != OperationScale {
+		t.Errorf("unexpected operations: %+v", report.Operations)
+	}
+	if len(report.Upgrades) != 1 || report.Upgrades[0].ToVersion != "1.31" {
+		t.Errorf("unexpected upgrades: %+v", report.Upgrades)
+	}
+	if report.Cost.ControlPlaneHours["eks"] != 3 || math.Abs(report.Cost.USD-0.3) > 1e-9 {
+		t.Errorf("unexpected cost: %+v", report.Cost)
+	}
+	if len(report.Failures) != 1 || report.Failures[0].Suite != "eks-p1" || report.Failures[0].Failure != "timed out" {
+		t.Errorf("unexpected failures: %+v", report.Failures)
+	}
+}
+
+func TestGenerateRunReport(t *testing.T) {
+	defer func(dir, runID string) { ArtifactsDir, runConfig.RunID = dir, runID }(ArtifactsDir, runConfig.RunID)
+	ArtifactsDir, runConfig.RunID = t.TempDir(), "42"
+
+	deleted := time.Now()
+	writeJSON := func(name string, value any) {
+		content, _ := json.Marshal(value)
+		if err := os.WriteFile(filepath.Join(ArtifactsDir, name), content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	metadata := &RunMetadata{OperatorChartVersions: map[string]string{"eks": "105.0.0"}, CLIVersions: map[string]string{"eksctl": "0.190.0"}, GitSHA: "0a1b2c3"}
+	writeJSON("eks-p1-summary.json", SuiteSummary{Suite: "eks-p1", RunID: "42", Counts: map[string]int{"failed": 1}, Metadata: metadata, Specs: []SpecSummary{{
+		Name:        "should upgrade <script>",
+		Failure:     "timed out",
+		Transitions: []string{`eks-hp-ci-abcde state=updating message="waiting"`},
+		Clusters:    []Resource{{Name: "eks-hp-ci-abcde", DeletedAt: &deleted}},
+		Operations:  []OperationMetric{{Operation: OperationUpgrade, Seconds: 600, FromVersion: "1.30", ToVersion: "1.31"}},
+	}}})
+	if err := GenerateRunReport(); err != nil {
+		t.Fatalf("GenerateRunReport() = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(ArtifactsDir, "run-report.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(content)
+	for _, want := range []string{"<h1>Run 42</h1>", "should upgrade &lt;script&gt;", "<td>1.30</td><td>1.31</td><td>10m0s</td>", "state=updating message=&#34;waiting&#34;", "failed: 1", "<h2>Estimated cost: $0.00</h2>",
+		"<td>eks=105.0.0</td>", "<td>eksctl=0.190.0</td><td>0a1b2c3</td>"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("%q not found in:\n%s", want, html)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package helpers
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 	"time"
 
@@ -12,6 +11,8 @@ import (
 	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
 	"github.com/rancher-sandbox/ele-testhelpers/rancher"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+	"github.com/rancher/hosted-providers-e2e/pkg/helmsdk"
 )
 
 /**
@@ -20,14 +21,10 @@ import (
  * @returns Nothing, the function will fail through Ginkgo in case of issue
  */
 func RunHelmCmdWithRetry(s ...string) {
-	Eventually(func() error {
-		output, err := kubectl.RunHelmBinaryWithOutput(s...)
-		GinkgoWriter.Write([]byte(output))
-		if err != nil {
-			return err
-		}
-		return nil
-	}, tools.SetTimeout(2*time.Minute), 20*time.Second).Should(Not(HaveOccurred()))
+	// every attempt is bounded, a hung helm call is retried rather than waited for until the default timeout of the CLIs
+	output, err := extcli.Helm.WithTimeout(10*time.Minute).WithRetries(6, 20*time.Second).Output(s...)
+	GinkgoWriter.Println(output)
+	Expect(err).To(Not(HaveOccurred()))
 }
 
 /*
@@ -48,10 +45,10 @@ func InstallK3S(k *kubectl.Kubectl, k3sVersion, proxy, proxyHost string) {
 			cwd, _ := os.Getwd()
 			GinkgoLogr.Info("Current working directory: " + cwd)
 
-			out, err := exec.Command("docker", "run", "-d", "--rm", "--name", "squid_proxy",
+			out, err := extcli.Docker.Output("run", "-d", "--rm", "--name", "squid_proxy",
 				"--volume", cwd+"/.github/scripts/squid.conf:/etc/squid/squid.conf",
-				"-p", "3128:3128", "ubuntu/squid").CombinedOutput()
-			GinkgoWriter.Println(string(out))
+				"-p", "3128:3128", "ubuntu/squid")
+			GinkgoWriter.Println(out)
 			Expect(err).To(Not(HaveOccurred()))
 		})
 
@@ -59,24 +56,27 @@ func InstallK3S(k *kubectl.Kubectl, k3sVersion, proxy, proxyHost string) {
 			k3sConfigPath := "/etc/default/k3s"
 			k3sConfig := fmt.Sprintf(`HTTP_PROXY=http://%s
 HTTPS_PROXY=http://%s
-NO_PROXY=127.0.0.0/8,10.0.0.0/8,cattle-system.svc,172.16.0.0/12,192.168.0.0/16,.svc,.cluster.local`, proxyHost, proxyHost)
+NO_PROXY=%s`, proxyHost, proxyHost, NoProxy)
 			// Write the k3s proxy config file as root
-			out, err := exec.Command("sh", "-c", fmt.Sprintf("echo '%s' | sudo tee %s", k3sConfig, k3sConfigPath)).CombinedOutput()
-			GinkgoWriter.Println(string(out))
+			err := WriteFileAsRoot(k3sConfigPath, k3sConfig+"\n")
 			Expect(err).To(Not(HaveOccurred()))
 		})
 	}
 
 	By("Getting k3s ready", func() {
-		installCmd := exec.Command("sh", "-c", "curl -sfL https://get.k3s.io | sh -s - server --cluster-init")
-		installCmd.Env = append(os.Environ(), "INSTALL_K3S_VERSION="+k3sVersion, "INSTALL_K3S_EXEC=--write-kubeconfig-mode 644")
+		installExec := "--write-kubeconfig-mode 644"
+		if IsRancherHA() {
+			// the k3s server nodes joined by AddK3SServerNodes reach this node through K3S_SERVER_IP
+			installExec += " --tls-san " + K3SServerIP
+		}
+		installCLI := extcli.New("sh").WithEnv("INSTALL_K3S_VERSION="+k3sVersion, "INSTALL_K3S_EXEC="+installExec).WithTimeout(10 * time.Minute)
 
 		// Execute k3s installation
 		count := 1
 		Eventually(func() error {
 			// Execute k3s installation
-			out, err := installCmd.CombinedOutput()
-			GinkgoWriter.Printf("K3s installation loop %d:\n%s\n", count, out)
+			result, err := installCLI.Run("-c", "curl -sfL https://get.k3s.io | sh -s - server --cluster-init")
+			GinkgoWriter.Printf("K3s installation loop %d:\n%s%s\n", count, result.Stdout, result.Stderr)
 			count++
 			return err
 		}, tools.SetTimeout(2*time.Minute), 5*time.Second).Should(BeNil(), "K3s installation failed")
@@ -91,7 +91,7 @@ NO_PROXY=127.0.0.0/8,10.0.0.0/8,cattle-system.svc,172.16.0.0/12,192.168.0.0/16,.
 			{"kube-system", "app.kubernetes.io/name=traefik"},
 			{"kube-system", "svccontroller.k3s.cattle.io/svcname=traefik"},
 		}
-		Eventually(func() error {
+		EventuallyWithBackoff(func() error {
 			return rancher.CheckPod(k, checkList)
 		}, tools.SetTimeout(4*time.Minute), 30*time.Second).Should(BeNil(), "K3s pods are not running")
 	})
@@ -107,12 +107,11 @@ Install CertManager
 */
 func InstallCertManager(k *kubectl.Kubectl, proxy, proxyHost string) {
 	By("Installing CertManager", func() {
-		RunHelmCmdWithRetry("repo", "add", "jetstack", "https://charts.jetstack.io")
-		RunHelmCmdWithRetry("repo", "update")
+		AddHelmRepo("jetstack", "https://charts.jetstack.io")
 
 		// Set flags for cert-manager installation
 		flags := []string{
-			"upgrade", "--install", "cert-manager", "jetstack/cert-manager",
+			"upgrade", "--install", "cert-manager", CachedChart("jetstack", "cert-manager", ""),
 			"--namespace", "cert-manager",
 			"--create-namespace",
 			"--set", "crds.enabled=true",
@@ -122,7 +121,7 @@ func InstallCertManager(k *kubectl.Kubectl, proxy, proxyHost string) {
 		if proxy == "enabled" {
 			flags = append(flags, "--set", "http_proxy=http://"+proxyHost,
 				"--set", "https_proxy=http://"+proxyHost,
-				"--set", "no_proxy=127.0.0.0/8\\,10.0.0.0/8\\,cattle-system.svc\\,172.16.0.0/12\\,192.168.0.0/16\\,.svc\\,.cluster.local")
+				"--set", "no_proxy="+helmEscapeCommas(NoProxy))
 		}
 		GinkgoWriter.Printf("Helm flags: %v\n", flags)
 		RunHelmCmdWithRetry(flags...)
@@ -132,7 +131,7 @@ func InstallCertManager(k *kubectl.Kubectl, proxy, proxyHost string) {
 			{"cert-manager", "app.kubernetes.io/component=webhook"},
 			{"cert-manager", "app.kubernetes.io/component=cainjector"},
 		}
-		Eventually(func() error {
+		EventuallyWithBackoff(func() error {
 			return rancher.CheckPod(k, checkList)
 		}, tools.SetTimeout(4*time.Minute), 30*time.Second).Should(BeNil(), "CertManager pods are not running")
 	})
@@ -152,45 +151,77 @@ Install Rancher Manager
 
   - @param rancherHeadVersion, Rancher Head version [eg. 2.9-head]
 
-  - @param proxy, enable proxy
+  - @param proxy, enable proxy; Rancher is configured to use the proxy PROXY_HOST, except for RANCHER_NO_PROXY
 
   - @param nightlyChart, enable operator nightly chart
 
+  - @remarks if RANCHER_HA is set to true, Rancher is installed with one replica per k3s server node
+
+  - @remarks if RANCHER_CA is set to private, Rancher is installed with a certificate signed by a private CA (see CreateRancherTLSSecrets)
+
+  - @remarks the chart is installed from the helm repository of the channel (see AddRancherManagerRepo), and the Rancher image of the prime channels is pulled from PRIME_REGISTRY
+
+  - @remarks the chart is installed with the helm Go SDK, with the values of rancher.DeployRancherManager of ele-testhelpers (see rancherManagerRelease)
+
   - @returns Nothing, the function will fail through Ginkgo in case of issue
 */
 func InstallRancherManager(k *kubectl.Kubectl, rancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, proxy, nightlyChart string) {
-	var proxyEnabled string
+	var set, setString []string
 	if proxy == "enabled" {
-		proxyEnabled = "rancher"
-	} else {
-		proxyEnabled = "none"
+		// Rancher uses PROXY_HOST and RANCHER_NO_PROXY, consistently with k3s and cert-manager
+		set = append(set,
+			"proxy=http://"+ProxyHost,
+			"noProxy="+helmEscapeCommas(NoProxy),
+		)
+	}
+
+	if IsRancherHA() {
+		// rancherManagerRelease sets replicas=1, the last value set wins
+		set = append(set, fmt.Sprintf("replicas=%d", rancherReplicas()))
 	}
 
-	var extraFlags []string
 	if nightlyChart == "enabled" {
 		// Ensure proper extraEnv index sequence for helm rendering
 		// All head versions and releases from prime-optimus[-alpha] channel require an extraEnv index of 2
-		// See https://github.com/rancher-sandbox/ele-testhelpers/blob/main/rancher/install.go
+		// See rancherManagerRelease
 		extraEnvIndex := 1
 		if rancherHeadVersion != "" || strings.Contains(rancherChannel, "prime-optimus") {
 			extraEnvIndex = 2
 		}
-		extraFlags = []string{
-			"--set", fmt.Sprintf("extraEnv[%d].name=CATTLE_SKIP_HOSTED_CLUSTER_CHART_INSTALLATION", extraEnvIndex),
-			"--set-string", fmt.Sprintf("extraEnv[%d].value=true", extraEnvIndex),
-		}
+		set = append(set, fmt.Sprintf("extraEnv[%d].name=CATTLE_SKIP_HOSTED_CLUSTER_CHART_INSTALLATION", extraEnvIndex))
+		setString = append(setString, fmt.Sprintf("extraEnv[%d].value=true", extraEnvIndex))
 	}
 
-	err := rancher.DeployRancherManager(rancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "none", proxyEnabled, extraFlags)
-	Expect(err).To(Not(HaveOccurred()))
+	set = append(set, primeRancherValues(rancherChannel)...)
+	AddRancherManagerRepo(rancherChannel)
+
+	if IsRancherPrivateCA() {
+		CreateRancherTLSSecrets(rancherHostname)
+	}
+
+	release, err := rancherManagerRelease(rancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, IsRancherPrivateCA())
+	Expect(err).To(BeNil())
+	Expect(helmsdk.SetValues(release.Values, set...)).To(Succeed())
+	Expect(helmsdk.SetStringValues(release.Values, setString...)).To(Succeed())
+	installRelease(release)
+
+	// The supported versions may change with the Rancher version
+	ResetVersionCatalog()
 
 	// Wait for all pods to be started
 	checkList := [][]string{
 		{"cattle-system", "app=rancher"},
 	}
-	Eventually(func() error {
+	EventuallyWithBackoff(func() error {
 		return rancher.CheckPod(k, checkList)
 	}, tools.SetTimeout(4*time.Minute), 30*time.Second).Should(BeNil(), "Rancher pod is not running")
+
+	CheckRancherImageSource(rancherChannel)
+}
+
+// helmEscapeCommas escapes the commas of a list value, so that helm --set and helmsdk.SetValues do not split it
+func helmEscapeCommas(value string) string {
+	return strings.ReplaceAll(value, ",", "\\,")
 }
 
 /*
@@ -216,7 +247,7 @@ func CheckRancherDeployments(k *kubectl.Kubectl) {
 		checkList := [][]string{
 			{"cattle-fleet-system", "app=fleet-controller"},
 		}
-		Eventually(func() error {
+		EventuallyWithBackoff(func() error {
 			return rancher.CheckPod(k, checkList)
 		}, tools.SetTimeout(4*time.Minute), 30*time.Second).Should(BeNil(), "Fleet-controller pod is not running")
 	})
@@ -237,7 +268,7 @@ func CheckRancherDeployments(k *kubectl.Kubectl) {
 		checkList := [][]string{
 			{"cattle-system", "app=rancher-webhook"},
 		}
-		Eventually(func() error {
+		EventuallyWithBackoff(func() error {
 			return rancher.CheckPod(k, checkList)
 		}, tools.SetTimeout(4*time.Minute), 30*time.Second).Should(BeNil(), "Rancher-webhook pod is not running")
 	})
@@ -258,8 +289,12 @@ func CheckRancherDeployments(k *kubectl.Kubectl) {
 		checkList := [][]string{
 			{"cattle-provisioning-capi-system", "cluster.x-k8s.io/provider=cluster-api"},
 		}
-		Eventually(func() error {
+		EventuallyWithBackoff(func() error {
 			return rancher.CheckPod(k, checkList)
 		}, tools.SetTimeout(4*time.Minute), 30*time.Second).Should(BeNil(), "Capi-controller-manager pod is not running")
 	})
+
+	if IsRancherHA() {
+		CheckRancherHA()
+	}
 }
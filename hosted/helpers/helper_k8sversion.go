@@ -0,0 +1,58 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// IsK8sVersionConstraint returns true if the value of DOWNSTREAM_K8S_MINOR_VERSION is a semver constraint (for e.g. "<=1.29" or "1.28.x")
+// rather than a plain X.Y minor version
+func IsK8sVersionConstraint(value string) bool {
+	return value != "" && !minorVersionRegex.MatchString(value)
+}
+
+// parseK8sVersionConstraint parses a k8s version constraint; the usual semver constraints syntax is supported, for e.g. "<=1.29", "1.28.x", "~1.28" or ">=1.28, <1.30"
+func parseK8sVersionConstraint(constraint string) (*semver.Constraints, error) {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid k8s version constraint %q: %w", constraint, err)
+	}
+	return c, nil
+}
+
+// MatchK8sVersion returns the highest version of the list matching the constraint.
+// Provider specific suffixes (for e.g. 1.30.5-gke.1014001) are ignored when matching, since they would be considered as pre-releases.
+func MatchK8sVersion(versions []string, constraint string) (string, error) {
+	c, err := parseK8sVersionConstraint(constraint)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		best        string
+		bestVersion *semver.Version
+	)
+	for _, version := range versions {
+		v, err := semver.NewVersion(version)
+		if err != nil {
+			return "", fmt.Errorf("unable to parse version %s: %w", version, err)
+		}
+		release, err := v.SetPrerelease("")
+		if err != nil {
+			return "", err
+		}
+		if !c.Check(&release) {
+			continue
+		}
+		if bestVersion == nil || v.GreaterThan(bestVersion) {
+			best, bestVersion = version, v
+		}
+	}
+
+	if best == "" {
+		return "", fmt.Errorf("no version matching %q found in: %s", constraint, strings.Join(versions, ", "))
+	}
+	return best, nil
+}
@@ -0,0 +1,88 @@
+package helpers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+// knownIssuesEntry is the name of the report entry holding the known issues matched by a failed spec
+const knownIssuesEntry = "Known issues"
+
+// KnownIssue is an open issue of an operator or of Rancher whose symptom is a recognizable error message
+type KnownIssue struct {
+	// URL of the issue, for e.g. https://github.com/rancher/eks-operator/issues/752
+	URL string
+	// Provider restricts the issue to the clusters of a provider, for e.g. eks; empty for all of them
+	Provider string
+	// Pattern matches the TransitioningMessage of the cluster, or the failure message of the spec
+	Pattern *regexp.Regexp
+}
+
+// knownIssues is the registry of the known issues the failures are checked against, so that a failure caused by an open issue
+// does not need to be triaged again; remove an issue once its fix is released.
+var knownIssues = []KnownIssue{
+	{
+		// updating a cluster while it is still updating
+		URL:      "https://github.com/rancher/eks-operator/issues/752",
+		Provider: "eks",
+		Pattern:  regexp.MustCompile(`ResourceInUseException.*(in progress|in-progress)`),
+	},
+	{
+		// updating a cluster while it is still updating
+		URL:      "https://github.com/rancher/aks-operator/issues/826",
+		Provider: "aks",
+		Pattern:  regexp.MustCompile(`OperationNotAllowed.*in progress`),
+	},
+}
+
+// Name returns the short name of the issue, <repository>#<number>, for e.g. eks-operator#752
+func (i KnownIssue) Name() string {
+	repository, number, found := strings.Cut(strings.TrimPrefix(i.URL, "https://github.com/"), "/issues/")
+	if !found {
+		return i.URL
+	}
+	return fmt.Sprintf("%s#%s", repository[strings.LastIndex(repository, "/")+1:], number)
+}
+
+// matchKnownIssues returns the annotation of every issue of the registry matching one of the messages, for e.g.
+// "matches eks-operator#752 (https://github.com/rancher/eks-operator/issues/752)"
+func matchKnownIssues(issues []KnownIssue, provider string, messages []string) []string {
+	var matches []string
+	for _, issue := range issues {
+		if issue.Provider != "" && issue.Provider != provider {
+			continue
+		}
+		for _, message := range messages {
+			if message != "" && issue.Pattern.MatchString(message) {
+				matches = append(matches, fmt.Sprintf("matches %s (%s)", issue.Name(), issue.URL))
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// annotateKnownIssues adds the known issues matched by the failure message of the spec or by the TransitioningMessage of its clusters
+// to the report of the spec; the annotations are part of the failure report, the suite summary and the Qase result comment.
+func annotateKnownIssues(spec ginkgo.SpecReport, timeline []ClusterTransition) {
+	messages := []string{spec.Failure.Message}
+	for _, transition := range timeline {
+		messages = append(messages, transition.TransitioningMessage, transition.Error)
+	}
+	if matches := matchKnownIssues(knownIssues, Provider, messages); len(matches) > 0 {
+		ginkgo.AddReportEntry(knownIssuesEntry, strings.Join(matches, "\n"), ginkgo.ReportEntryVisibilityFailureOrVerbose)
+	}
+}
+
+// specKnownIssues returns the known issues matched by a failed spec, see annotateKnownIssues
+func specKnownIssues(spec ginkgo.SpecReport) []string {
+	for _, entry := range spec.ReportEntries {
+		if entry.Name == knownIssuesEntry {
+			return strings.Split(entry.StringRepresentation(), "\n")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package helpers
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestMatchKnownIssues(t *testing.T) {
+	issues := []KnownIssue{
+		{URL: "https://github.com/rancher/eks-operator/issues/752", Provider: "eks", Pattern: regexp.MustCompile(`ResourceInUseException.*in progress`)},
+		{URL: "https://github.com/rancher/rancher/issues/43772", Pattern: regexp.MustCompile(`cluster agent is not connected`)},
+	}
+	messages := []string{
+		"Expected <bool>: false to be true",
+		"",
+		"ResourceInUseException: Update already in progress",
+	}
+
+	if got, want := matchKnownIssues(issues, "eks", messages), []string{"matches eks-operator#752 (https://github.com/rancher/eks-operator/issues/752)"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("matchKnownIssues() = %v, want %v", got, want)
+	}
+	// the issue of another provider is left out
+	if got := matchKnownIssues(issues, "aks", messages); got != nil {
+		t.Errorf("matchKnownIssues() of aks = %v, want none", got)
+	}
+	if got := matchKnownIssues(issues, "gke", []string{"waiting: cluster agent is not connected"}); len(got) != 1 || got[0] != "matches rancher#43772 (https://github.com/rancher/rancher/issues/43772)" {
+		t.Errorf("matchKnownIssues() = %v", got)
+	}
+}
+
+func TestKnownIssuesRegistry(t *testing.T) {
+	for _, issue := range knownIssues {
+		if issue.Pattern == nil || issue.Name() == issue.URL {
+			t.Errorf("known issue %s must have a pattern and a GitHub issue URL", issue.URL)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package helpers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher"
+)
+
+// kontainerDrivers are the names of the built-in kontainer drivers of the providers
+var kontainerDrivers = map[string]string{
+	"eks": "amazonelasticcontainerservice",
+	"gke": "googlekubernetesengine",
+	"aks": "azurekubernetesservice",
+}
+
+// KontainerDriverName returns the name of the kontainer driver of the provider, for e.g. amazonelasticcontainerservice for eks
+func KontainerDriverName(provider string) string {
+	return kontainerDrivers[provider]
+}
+
+/*
+Activate or deactivate the kontainer driver of the Provider, as an admin would from the Cluster Drivers page, and wait for the driver to be in
+the expected state; use DeferCleanup(SnapshotRancherSettings().Restore) to restore the driver once the spec is done.
+  - @param client Rancher admin client
+  - @param active Whether the driver must be active
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func SetKontainerDriverActive(client *rancher.Client, active bool) {
+	name := KontainerDriverName(Provider)
+	expectedState := "inactive"
+	if active {
+		expectedState = "active"
+	}
+
+	ginkgo.By(fmt.Sprintf("Setting kontainer driver %s %s", name, expectedState), func() {
+		driver, err := client.Management.KontainerDriver.ByID(name)
+		Expect(err).To(BeNil())
+		if active {
+			Expect(client.Management.KontainerDriver.ActionActivate(driver)).To(Succeed())
+		} else {
+			Expect(client.Management.KontainerDriver.ActionDeactivate(driver)).To(Succeed())
+		}
+
+		EventuallyWithBackoff(func() (string, error) {
+			driver, err := client.Management.KontainerDriver.ByID(name)
+			if err != nil {
+				return "", err
+			}
+			return driver.State, nil
+		}, tools.SetTimeout(5*time.Minute), 5*time.Second).Should(Equal(expectedState), fmt.Sprintf("Kontainer driver %s is not %s", name, expectedState))
+	})
+}
+
+/*
+Wait for Rancher to install the operator charts of the Provider and for the operator pod to be ready; Rancher installs them once a cluster of
+the Provider is created. It requires the upstream cluster to be reachable via kubectl and helm.
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func WaitUntilOperatorChartsInstalled() {
+	ginkgo.By(fmt.Sprintf("Waiting for the %s-operator charts to be installed", Provider), func() {
+		// the operator chart and its CRD chart
+		Eventually(func() int {
+			return len(ListOperatorChart())
+		}, tools.SetTimeout(10*time.Minute), 10*time.Second).Should(BeNumerically(">=", 2), fmt.Sprintf("The %s-operator charts are not installed", Provider))
+		waitForOperatorPod(nil)
+	})
+}
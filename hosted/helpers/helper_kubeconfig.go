@@ -0,0 +1,89 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+// kubeconfigGenerator writes the kubeconfig of a cluster created with the provider CLI to the given path
+type kubeconfigGenerator func(kubeconfig string) error
+
+// downstreamKubeconfigs are the kubeconfig generators of the clusters created with the provider CLIs, by cluster name;
+// the path of a generated kubeconfig is kept in the <clusterName>_KUBECONFIG env var, see DownstreamKubeconfig
+var downstreamKubeconfigs = struct {
+	sync.Mutex
+	generators map[string]kubeconfigGenerator
+	// generated is the number of kubeconfigs generated by the process, out of the registered ones
+	generated, registered int
+}{generators: map[string]kubeconfigGenerator{}}
+
+// RegisterKubeconfigGenerator registers how to write the kubeconfig of a cluster created with the provider CLI, for e.g. with eksctl utils write-kubeconfig;
+// the kubeconfig is only written once a spec needs to reach the cluster with kubectl, see DownstreamKubeconfigPath. The kubeconfig written
+// by a previous generator of the cluster is dropped.
+func RegisterKubeconfigGenerator(clusterName string, generate func(kubeconfig string) error) {
+	downstreamKubeconfigs.Lock()
+	defer downstreamKubeconfigs.Unlock()
+	dropDownstreamKubeconfig(clusterName)
+	downstreamKubeconfigs.generators[clusterName] = generate
+	downstreamKubeconfigs.registered++
+}
+
+/*
+DownstreamKubeconfigPath returns the kubeconfig of a cluster created with the provider CLI, <clusterName>.kubeconfig in the spec artifact directory,
+written by the generator of the cluster (see RegisterKubeconfigGenerator) the first time it is needed; the following calls for the cluster reuse it.
+Neither the KUBECONFIG env var nor the kubeconfig of the user are changed, the kubeconfig must be given to the commands, for e.g. with
+extcli.Kubectl.WithEnv("KUBECONFIG=" + kubeconfig).
+  - @param clusterName Name of the cluster
+  - @returns The path of the kubeconfig and the error of its generation
+*/
+func DownstreamKubeconfigPath(clusterName string) (string, error) {
+	downstreamKubeconfigs.Lock()
+	defer downstreamKubeconfigs.Unlock()
+	if kubeconfig := os.Getenv(DownstreamKubeconfig(clusterName)); kubeconfig != "" {
+		return kubeconfig, nil
+	}
+	generate, found := downstreamKubeconfigs.generators[clusterName]
+	if !found {
+		return "", fmt.Errorf("no kubeconfig for cluster %s, it was not created with the provider CLI", clusterName)
+	}
+
+	dir, err := ArtifactDir(ginkgo.CurrentSpecReport())
+	if err != nil {
+		return "", err
+	}
+	kubeconfig := filepath.Join(dir, clusterName+".kubeconfig")
+	// the kubeconfig holds the credentials of the cluster
+	if err = os.WriteFile(kubeconfig, nil, 0o600); err != nil {
+		return "", err
+	}
+	if err = generate(kubeconfig); err != nil {
+		_ = os.Remove(kubeconfig)
+		return "", fmt.Errorf("failed to write the kubeconfig of cluster %s: %w", clusterName, err)
+	}
+	_ = os.Setenv(DownstreamKubeconfig(clusterName), kubeconfig)
+	downstreamKubeconfigs.generated++
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Wrote the kubeconfig of cluster %s, %d of the %d kubeconfigs registered so far",
+		clusterName, downstreamKubeconfigs.generated, downstreamKubeconfigs.registered))
+	return kubeconfig, nil
+}
+
+// RemoveDownstreamKubeconfig removes the kubeconfig of a cluster created with the provider CLI once it is deleted, along with its generator
+func RemoveDownstreamKubeconfig(clusterName string) {
+	downstreamKubeconfigs.Lock()
+	defer downstreamKubeconfigs.Unlock()
+	dropDownstreamKubeconfig(clusterName)
+	delete(downstreamKubeconfigs.generators, clusterName)
+}
+
+// dropDownstreamKubeconfig removes the kubeconfig of the cluster if it was written, along with its clients; downstreamKubeconfigs must be locked
+func dropDownstreamKubeconfig(clusterName string) {
+	if kubeconfig := os.Getenv(DownstreamKubeconfig(clusterName)); kubeconfig != "" {
+		forgetDownstreamClients(kubeconfig)
+		_ = os.Remove(kubeconfig)
+		_ = os.Unsetenv(DownstreamKubeconfig(clusterName))
+	}
+}
@@ -0,0 +1,45 @@
+package helpers
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDownstreamKubeconfigPath(t *testing.T) {
+	defer func(dir string) { ArtifactsDir = dir }(ArtifactsDir)
+	ArtifactsDir = t.TempDir()
+	const clusterName = "hpe2e-kubeconfig"
+	defer RemoveDownstreamKubeconfig(clusterName)
+
+	if _, err := DownstreamKubeconfigPath(clusterName); err == nil {
+		t.Fatal("DownstreamKubeconfigPath() must fail for a cluster not created with the provider CLI")
+	}
+
+	// the kubeconfig is only written once needed, then reused
+	generated := 0
+	RegisterKubeconfigGenerator(clusterName, func(kubeconfig string) error {
+		generated++
+		return os.WriteFile(kubeconfig, []byte("apiVersion: v1\nkind: Config\n"), 0o600)
+	})
+	if generated != 0 {
+		t.Fatal("the kubeconfig must not be written when the cluster is created")
+	}
+	kubeconfig, err := DownstreamKubeconfigPath(clusterName)
+	if err != nil {
+		t.Fatalf("DownstreamKubeconfigPath() = %v", err)
+	}
+	if again, _ := DownstreamKubeconfigPath(clusterName); again != kubeconfig || generated != 1 {
+		t.Errorf("got %q after %d generations, want %q to be written once", again, generated, kubeconfig)
+	}
+	if os.Getenv("KUBECONFIG") == kubeconfig {
+		t.Error("KUBECONFIG must not point to the kubeconfig of the cluster")
+	}
+
+	RemoveDownstreamKubeconfig(clusterName)
+	if _, err = os.Stat(kubeconfig); !os.IsNotExist(err) {
+		t.Errorf("the kubeconfig %s must be removed with the cluster", kubeconfig)
+	}
+	if _, err = DownstreamKubeconfigPath(clusterName); err == nil {
+		t.Error("DownstreamKubeconfigPath() must fail once the cluster is deleted")
+	}
+}
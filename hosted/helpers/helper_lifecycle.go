@@ -0,0 +1,170 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// clusterLifecycleEntry is the name of the report entry holding the lifecycle of the clusters of a failed spec
+const clusterLifecycleEntry = "Cluster lifecycle"
+
+// LifecycleStep is a Rancher API call creating or updating a cluster during a spec, with the states the cluster went through until the next call
+type LifecycleStep struct {
+	Time        time.Time `json:"time"`
+	ClusterName string    `json:"clusterName"`
+	// Call is the operation creating the cluster or the helper updating it, see ClusterTransition
+	Call  string `json:"call"`
+	Step  string `json:"step,omitempty"`
+	Error string `json:"error,omitempty"`
+	// States are the states of the cluster and of its nodegroups/nodepools which followed the call, oldest first
+	States []string `json:"states,omitempty"`
+	// Failed is true for the last call before the failure of the spec
+	Failed bool `json:"failed,omitempty"`
+}
+
+func (s LifecycleStep) String() string {
+	line := ClusterTransition{Time: s.Time, ClusterName: s.ClusterName, Call: s.Call, Step: s.Step, Error: s.Error}.String()
+	if s.Failed {
+		line += " <- the spec failed during this step"
+	}
+	for _, state := range s.States {
+		line += "\n    " + state
+	}
+	return line
+}
+
+// clusterLifecycle groups the timeline of the clusters of a spec by call: every call is followed by the transitions of its cluster
+// until the next call on the cluster; the transitions before the first call of a cluster are left out. The last call before failureTime
+// is marked as failed, so that the report shows which lifecycle step the spec failed in; failureTime is zero if the spec did not fail.
+func clusterLifecycle(timeline []ClusterTransition, failureTime time.Time) []LifecycleStep {
+	sorted := slices.Clone(timeline)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+
+	var steps []LifecycleStep
+	current := map[string]int{}
+	for _, transition := range sorted {
+		if transition.Call != "" {
+			current[transition.ClusterID] = len(steps)
+			steps = append(steps, LifecycleStep{
+				Time:        transition.Time,
+				ClusterName: transition.ClusterName,
+				Call:        transition.Call,
+				Step:        transition.Step,
+				Error:       transition.Error,
+			})
+			continue
+		}
+		if i, ok := current[transition.ClusterID]; ok {
+			steps[i].States = append(steps[i].States, fmt.Sprintf("%s %s", transition.Time.Format(time.RFC3339), transition.stateString()))
+		}
+	}
+
+	if !failureTime.IsZero() {
+		for i := len(steps) - 1; i >= 0; i-- {
+			if !steps[i].Time.After(failureTime) {
+				steps[i].Failed = true
+				break
+			}
+		}
+	}
+	return steps
+}
+
+// formatLifecycle returns the lifecycle steps, one per paragraph
+func formatLifecycle(steps []LifecycleStep) string {
+	lines := make([]string, 0, len(steps))
+	for _, step := range steps {
+		lines = append(lines, step.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// specLifecycle returns the lifecycle of the clusters of a failed spec, from the timeline recorded by RecordClusterTransitions in its report
+func specLifecycle(spec types.SpecReport) []LifecycleStep {
+	if !spec.Failed() {
+		return nil
+	}
+	for _, entry := range spec.ReportEntries {
+		if entry.Name != clusterTimelineEntry {
+			continue
+		}
+		// the raw value is only kept within the process of the spec, the JSON one is sent to the reporting process
+		var timeline []ClusterTransition
+		if err := json.Unmarshal([]byte(entry.Value.AsJSON), &timeline); err != nil {
+			return nil
+		}
+		return clusterLifecycle(timeline, spec.Failure.TimelineLocation.Time)
+	}
+	return nil
+}
+
+/*
+Update the cluster through the Rancher API and record the call in the timeline of the spec (see RecordClusterTransitions), named after
+the calling helper and the current By step, so that the report shows the lifecycle step a failure occurred in; the cluster is watched from then on,
+and its state cached by WatchedCluster is fetched again on its next read.
+  - @param client Rancher client
+  - @param cluster Cluster to update
+  - @param updates Updated cluster, for e.g. &upgradedCluster, or map of the updated fields
+  - @returns The updated cluster and the error of the update
+*/
+func UpdateRancherCluster(client *rancher.Client, cluster *management.Cluster, updates interface{}) (*management.Cluster, error) {
+	start := time.Now()
+	updatedCluster, err := client.Management.Cluster.Update(cluster, updates)
+	invalidateWatchedCluster(cluster.ID)
+	recordClusterCall(cluster, callerName(1), start, err)
+	return updatedCluster, err
+}
+
+// recordClusterCall appends the call made on the cluster to the timeline and starts watching the cluster
+func recordClusterCall(cluster *management.Cluster, call string, start time.Time, err error) {
+	transition := ClusterTransition{Time: start.UTC(), ClusterID: cluster.ID, ClusterName: cluster.Name, Call: call, Step: currentByStep()}
+	if err != nil {
+		transition.Error = err.Error()
+	}
+	transitionHistory.Lock()
+	if transitionHistory.recording {
+		transitionHistory.timeline = append(transitionHistory.timeline, transition)
+	}
+	transitionHistory.Unlock()
+
+	recordClusterTransition(cluster, true)
+}
+
+// currentByStep returns the text of the last By step of the running spec, if any
+func currentByStep() string {
+	events := ginkgo.CurrentSpecReport().SpecEvents
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].SpecEventType == types.SpecEventByStart {
+			return events[i].Message
+		}
+	}
+	return ""
+}
+
+// closureSuffix matches the suffix of the names of the closures, for e.g. .func1 or .func2.1
+var closureSuffix = regexp.MustCompile(`(\.func\d+)+(\.\d+)*$`)
+
+// callerName returns the name of the function skip frames above the caller of callerName, without its package path and closure suffix,
+// for e.g. helper.UpgradeNodeKubernetesVersion
+func callerName(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return "unknown"
+	}
+	name := runtime.FuncForPC(pc).Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	return closureSuffix.ReplaceAllString(name, "")
+}
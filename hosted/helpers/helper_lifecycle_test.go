@@ -0,0 +1,79 @@
+package helpers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+func TestClusterLifecycle(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+	timeline := []ClusterTransition{
+		{Time: at(0), ClusterID: "c-1", ClusterName: "eks-1", Call: OperationProvision},
+		{Time: at(1), ClusterID: "c-1", ClusterName: "eks-1", State: "provisioning", Transitioning: "yes"},
+		{Time: at(15), ClusterID: "c-1", ClusterName: "eks-1", State: "active", Transitioning: "no"},
+		{Time: at(20), ClusterID: "c-1", ClusterName: "eks-1", Call: "helper.UpgradeClusterKubernetesVersion", Step: "upgrading the control plane"},
+		// recorded out of order by the watch
+		{Time: at(22), ClusterID: "c-1", ClusterName: "eks-1", State: "updating", Transitioning: "error", TransitioningMessage: "InvalidParameterException"},
+		{Time: at(21), ClusterID: "c-1", ClusterName: "eks-1", State: "updating", Transitioning: "yes"},
+		// transitions of a cluster without call are left out
+		{Time: at(23), ClusterID: "c-2", ClusterName: "other", State: "active"},
+	}
+
+	steps := clusterLifecycle(timeline, at(40))
+	if len(steps) != 2 {
+		t.Fatalf("got %d steps, want 2: %v", len(steps), steps)
+	}
+	if steps[0].Call != OperationProvision || len(steps[0].States) != 2 || steps[0].Failed {
+		t.Errorf("unexpected provisioning step: %v", steps[0])
+	}
+	upgrade := steps[1]
+	if upgrade.Step != "upgrading the control plane" || !upgrade.Failed || len(upgrade.States) != 2 ||
+		!strings.Contains(upgrade.States[0], "transitioning=yes") || !strings.Contains(upgrade.States[1], `message="InvalidParameterException"`) {
+		t.Errorf("unexpected upgrade step: %v", upgrade)
+	}
+	if !strings.Contains(upgrade.String(), "call=helper.UpgradeClusterKubernetesVersion") || !strings.Contains(upgrade.String(), "the spec failed during this step") {
+		t.Errorf("unexpected step line: %s", upgrade)
+	}
+
+	// the spec failed before the upgrade
+	steps = clusterLifecycle(timeline, at(16))
+	if !steps[0].Failed || steps[1].Failed {
+		t.Errorf("the failure must be attributed to the provisioning: %v", steps)
+	}
+	// the spec did not fail
+	for _, step := range clusterLifecycle(timeline, time.Time{}) {
+		if step.Failed {
+			t.Errorf("no step must be failed: %v", step)
+		}
+	}
+}
+
+func TestRecordClusterCall(t *testing.T) {
+	transitionHistory.recording = true
+	transitionHistory.last = map[string]ClusterTransition{}
+	transitionHistory.timeline = nil
+	defer func() { transitionHistory.recording = false }()
+
+	cluster := &management.Cluster{Name: "gke-1", State: "active"}
+	cluster.ID = "c-1"
+	func() {
+		recordClusterCall(cluster, callerName(1), time.Now(), errors.New("conflict"))
+	}()
+
+	timeline := transitionHistory.timeline
+	if len(timeline) != 2 {
+		t.Fatalf("got %d entries, want the call and the state of the cluster: %v", len(timeline), timeline)
+	}
+	if timeline[0].Call != "helpers.TestRecordClusterCall" || timeline[0].Error != "conflict" || timeline[1].State != "active" {
+		t.Errorf("unexpected timeline: %v", timeline)
+	}
+	// the call is not a transition of the cluster
+	if transitions := ClusterTransitionsOf(cluster.ID, time.Time{}); len(transitions) != 1 {
+		t.Errorf("got %d transitions, want 1: %v", len(transitions), transitions)
+	}
+}
@@ -0,0 +1,151 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+	"github.com/rancher-sandbox/ele-testhelpers/rancher"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+)
+
+const (
+	// localClusterK3d and localClusterKind are the RANCHER_INSTALL_BACKEND installing rancher with helm on a local k3d or kind cluster
+	localClusterK3d  = "k3d"
+	localClusterKind = "kind"
+	// localClusterName is the name of the k3d or kind cluster, see make clean-local-cluster
+	localClusterName = "hosted-providers-rancher"
+	// kindIngressManifest is the ingress-nginx controller of the kind clusters, the ingress of rancher being served by it; k3d clusters have traefik
+	kindIngressManifest = "https://raw.githubusercontent.com/kubernetes/ingress-nginx/controller-v1.11.2/deploy/static/provider/kind/deploy.yaml"
+)
+
+// kindConfig is the config of the kind cluster: a single node, labelled for the ingress-nginx controller, with the http(s) ports published on the host
+const kindConfig = `kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+nodes:
+- role: control-plane
+  kubeadmConfigPatches:
+  - |
+    kind: InitConfiguration
+    nodeRegistration:
+      kubeletExtraArgs:
+        node-labels: "ingress-ready=true"
+  extraPortMappings:
+  - containerPort: 80
+    hostPort: 80
+    protocol: TCP
+  - containerPort: 443
+    hostPort: 443
+    protocol: TCP
+`
+
+// IsLocalClusterBackend returns true if rancher must be installed with helm on a local k3d or kind cluster instead of k3s (RANCHER_INSTALL_BACKEND=k3d or kind);
+// it is set up in minutes, for e.g. to iterate on the chart support suites, but it can not be wiped like k3s by the backup/restore suites
+func IsLocalClusterBackend() bool {
+	return RancherInstallBackend == localClusterK3d || RancherInstallBackend == localClusterKind
+}
+
+// k3dImage returns the k3s image of the k3s version, for e.g. rancher/k3s:v1.30.4-k3s1 for v1.30.4+k3s1; the default image of k3d if empty
+func k3dImage(k3sVersion string) string {
+	if k3sVersion == "" {
+		return ""
+	}
+	return "rancher/k3s:" + strings.ReplaceAll(k3sVersion, "+", "-")
+}
+
+/*
+localClusterCreateArgs returns the arguments of the CLI of the backend creating the local cluster, whose http(s) ports are published on the host
+so that rancher is reached on RANCHER_HOSTNAME; the kubeconfig of the user is left untouched.
+  - @param backend k3d or kind
+  - @param image Node image, the default image of the backend if empty
+  - @param kindConfigPath Path of the kindConfig, only used with kind
+  - @returns The arguments of k3d or kind
+*/
+func localClusterCreateArgs(backend, image, kindConfigPath string) []string {
+	var args []string
+	switch backend {
+	case localClusterK3d:
+		args = []string{"cluster", "create", localClusterName, "--servers", "1", "--agents", "0",
+			"-p", "80:80@loadbalancer", "-p", "443:443@loadbalancer",
+			"--wait", "--timeout", "5m", "--kubeconfig-update-default=false", "--kubeconfig-switch-context=false"}
+	case localClusterKind:
+		args = []string{"create", "cluster", "--name", localClusterName, "--config", kindConfigPath, "--wait", "5m"}
+	}
+	if image != "" {
+		args = append(args, "--image", image)
+	}
+	return args
+}
+
+// localClusterKubeconfigArgs returns the arguments of the CLI of the backend printing the kubeconfig of the local cluster
+func localClusterKubeconfigArgs(backend string) []string {
+	if backend == localClusterKind {
+		return []string{"get", "kubeconfig", "--name", localClusterName}
+	}
+	return []string{"kubeconfig", "get", localClusterName}
+}
+
+/*
+InstallLocalCluster creates the k3d or kind cluster of RANCHER_INSTALL_BACKEND, in place of InstallK3S, and writes its kubeconfig to KUBECONFIG;
+the ingress controller of the cluster (traefik with k3d, ingress-nginx with kind) serves rancher on the http(s) ports of the host.
+  - @param k kubectl of the cluster, using KUBECONFIG
+  - @param k3sVersion k3s version of the k3d node image, for e.g. v1.30.4+k3s1; LOCAL_CLUSTER_IMAGE takes precedence
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func InstallLocalCluster(k *kubectl.Kubectl, k3sVersion string) {
+	backend := RancherInstallBackend
+	cli := extcli.K3d
+	image := runConfig.LocalClusterImage
+	if backend == localClusterKind {
+		cli = extcli.Kind
+	} else if image == "" {
+		image = k3dImage(k3sVersion)
+	}
+	Expect(Kubeconfig).ToNot(BeEmpty(), "KUBECONFIG is required to write the kubeconfig of the %s cluster", backend)
+
+	By(fmt.Sprintf("Creating the %s cluster %s", backend, localClusterName), func() {
+		var kindConfigPath string
+		if backend == localClusterKind {
+			kindConfigPath = filepath.Join(GinkgoT().TempDir(), "kind-config.yaml")
+			Expect(os.WriteFile(kindConfigPath, []byte(kindConfig), 0o644)).To(Succeed())
+		}
+		out, err := cli.WithTimeout(10 * time.Minute).Output(localClusterCreateArgs(backend, image, kindConfigPath)...)
+		GinkgoWriter.Println(out)
+		Expect(err).To(Not(HaveOccurred()), "Failed to create the %s cluster", backend)
+	})
+
+	By(fmt.Sprintf("Writing the kubeconfig of the %s cluster to %s", backend, Kubeconfig), func() {
+		kubeconfig, err := cli.Output(localClusterKubeconfigArgs(backend)...)
+		Expect(err).To(Not(HaveOccurred()))
+		Expect(os.MkdirAll(filepath.Dir(Kubeconfig), 0o755)).To(Succeed())
+		Expect(os.WriteFile(Kubeconfig, []byte(kubeconfig), 0o600)).To(Succeed())
+	})
+
+	checkList := [][]string{
+		{"kube-system", "k8s-app=kube-dns"},
+		{"kube-system", "app.kubernetes.io/name=traefik"},
+	}
+	if backend == localClusterKind {
+		By("Installing the ingress-nginx controller", func() {
+			out, err := extcli.Kubectl.WithRetries(3, 10*time.Second).Output("apply", "-f", kindIngressManifest)
+			GinkgoWriter.Println(out)
+			Expect(err).To(Not(HaveOccurred()))
+		})
+		checkList = [][]string{
+			{"kube-system", "k8s-app=kube-dns"},
+			{"ingress-nginx", "app.kubernetes.io/component=controller"},
+		}
+	}
+
+	By(fmt.Sprintf("Waiting for the %s cluster to be started", backend), func() {
+		EventuallyWithBackoff(func() error {
+			return rancher.CheckPod(k, checkList)
+		}, tools.SetTimeout(4*time.Minute), 30*time.Second).Should(BeNil(), "The pods of the %s cluster are not running", backend)
+	})
+}
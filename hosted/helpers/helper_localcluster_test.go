@@ -0,0 +1,58 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLocalClusterArgs(t *testing.T) {
+	if image := k3dImage("v1.30.4+k3s1"); image != "rancher/k3s:v1.30.4-k3s1" {
+		t.Errorf("k3dImage() = %s", image)
+	}
+	if image := k3dImage(""); image != "" {
+		t.Errorf("k3dImage() = %s, want the default image of k3d", image)
+	}
+
+	for _, tc := range []struct {
+		backend, image string
+		create         []string
+		kubeconfig     []string
+	}{
+		{backend: localClusterK3d, image: "rancher/k3s:v1.30.4-k3s1",
+			create: []string{"cluster", "create", localClusterName, "--servers", "1", "--agents", "0", "-p", "80:80@loadbalancer", "-p", "443:443@loadbalancer",
+				"--wait", "--timeout", "5m", "--kubeconfig-update-default=false", "--kubeconfig-switch-context=false", "--image", "rancher/k3s:v1.30.4-k3s1"},
+			kubeconfig: []string{"kubeconfig", "get", localClusterName}},
+		{backend: localClusterKind,
+			create:     []string{"create", "cluster", "--name", localClusterName, "--config", "kind-config.yaml", "--wait", "5m"},
+			kubeconfig: []string{"get", "kubeconfig", "--name", localClusterName}},
+	} {
+		if args := localClusterCreateArgs(tc.backend, tc.image, "kind-config.yaml"); !reflect.DeepEqual(args, tc.create) {
+			t.Errorf("localClusterCreateArgs(%s) = %v, want %v", tc.backend, args, tc.create)
+		}
+		if args := localClusterKubeconfigArgs(tc.backend); !reflect.DeepEqual(args, tc.kubeconfig) {
+			t.Errorf("localClusterKubeconfigArgs(%s) = %v, want %v", tc.backend, args, tc.kubeconfig)
+		}
+	}
+}
+
+func TestValidateRancherInstallBackend(t *testing.T) {
+	for _, tc := range []struct {
+		backend  string
+		suite    Suite
+		problems int
+	}{
+		{backend: "", suite: SuiteBackupRestore},
+		{backend: localClusterK3d, suite: SuiteChaos},
+		{backend: localClusterKind, suite: SuiteCommon},
+		{backend: "minikube", suite: SuiteCommon, problems: 1},
+		{backend: localClusterK3d, suite: SuiteBackupRestore, problems: 1},
+		{backend: localClusterKind, suite: SuiteBackupRestore, problems: 1},
+	} {
+		config := &RunConfig{Provider: "eks", RancherHostname: "1.2.3.4.sslip.io", RancherPassword: "password", Kubeconfig: "k3s.yaml", K3sVersion: "v1.30.4+k3s1",
+			RancherVersion: "latest/2.9.3", ChartDowngradeVersions: 1, ScaleNodePools: 2, ScaleNodesPerPool: 1, PollBackoffFactor: 1, PollMaxInterval: 1,
+			RancherInstallBackend: tc.backend}
+		if problems := config.Validate(tc.suite); len(problems) != tc.problems {
+			t.Errorf("Validate(%s) with RANCHER_INSTALL_BACKEND %q = %v, want %d problems", tc.suite, tc.backend, problems, tc.problems)
+		}
+	}
+}
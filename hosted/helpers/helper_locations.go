@@ -0,0 +1,72 @@
+package helpers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// gkeZoneRegex matches the GKE zones, for e.g. asia-south2-c
+var gkeZoneRegex = regexp.MustCompile(`^[a-z]+-[a-z]+\d+-[a-z]$`)
+
+// GKELocation is a location configuration of a GKE cluster of the location matrix
+type GKELocation struct {
+	// Description of the configuration, for e.g. "zonal cluster in asia-south2-c"
+	Description string
+	// Zone of a zonal cluster, empty for a regional cluster
+	Zone string
+	// Region of a regional cluster, empty for a zonal cluster
+	Region string
+	// Locations are the zones of the nodes; the zone of the cluster is used if empty, or the zones chosen by GKE for a regional cluster
+	Locations []string
+}
+
+// IsGKEZone returns true if the GKE location is a zone, for e.g. asia-south2-c, rather than a region, for e.g. asia-south2
+func IsGKEZone(location string) bool {
+	return gkeZoneRegex.MatchString(location)
+}
+
+// GKERegionOfZone returns the region of a GKE zone, for e.g. asia-south2 for asia-south2-c
+func GKERegionOfZone(zone string) string {
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		return zone[:i]
+	}
+	return zone
+}
+
+// GetEKSMatrixRegions returns the regions of the EKS location matrix: EKS_MATRIX_REGIONS, or the EKS region if it is not set
+func GetEKSMatrixRegions() []string {
+	if len(runConfig.EKSMatrixRegions) > 0 {
+		return runConfig.EKSMatrixRegions
+	}
+	return []string{GetEKSRegion()}
+}
+
+// GetGKEMatrixZones returns the zones of the GKE location matrix: GKE_MATRIX_ZONES, or the GKE zone if it is not set
+func GetGKEMatrixZones() []string {
+	if len(runConfig.GKEMatrixZones) > 0 {
+		return runConfig.GKEMatrixZones
+	}
+	return []string{GetGKEZone()}
+}
+
+// GKELocationMatrix returns the location configurations of the GKE location matrix for zones of the same region: a zonal cluster in the first zone,
+// a regional cluster in the region of the zones and, if there are several zones, a zonal cluster in the first zone with nodes in all the zones
+func GKELocationMatrix(zones []string) []GKELocation {
+	if len(zones) == 0 {
+		return nil
+	}
+	region := GKERegionOfZone(zones[0])
+	locations := []GKELocation{
+		{Description: fmt.Sprintf("zonal cluster in %s", zones[0]), Zone: zones[0]},
+		{Description: fmt.Sprintf("regional cluster in %s", region), Region: region},
+	}
+	if len(zones) > 1 {
+		locations = append(locations, GKELocation{
+			Description: fmt.Sprintf("multi-zone cluster in %s", strings.Join(zones, ",")),
+			Zone:        zones[0],
+			Locations:   zones,
+		})
+	}
+	return locations
+}
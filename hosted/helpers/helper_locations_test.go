@@ -0,0 +1,54 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGKERegionOfZone(t *testing.T) {
+	for zone, want := range map[string]string{"asia-south2-c": "asia-south2", "us-central1-a": "us-central1", "us-central1": "us"} {
+		if got := GKERegionOfZone(zone); got != want {
+			t.Errorf("GKERegionOfZone(%s) = %s, want %s", zone, got, want)
+		}
+	}
+}
+
+func TestGKELocationMatrix(t *testing.T) {
+	if got := GKELocationMatrix(nil); got != nil {
+		t.Errorf("GKELocationMatrix(nil) = %v, want nil", got)
+	}
+
+	got := GKELocationMatrix([]string{"asia-south2-c"})
+	want := []GKELocation{
+		{Description: "zonal cluster in asia-south2-c", Zone: "asia-south2-c"},
+		{Description: "regional cluster in asia-south2", Region: "asia-south2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GKELocationMatrix(asia-south2-c) = %v, want %v", got, want)
+	}
+
+	zones := []string{"asia-south2-c", "asia-south2-a"}
+	got = GKELocationMatrix(zones)
+	want = append(want, GKELocation{Description: "multi-zone cluster in asia-south2-c,asia-south2-a", Zone: "asia-south2-c", Locations: zones})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GKELocationMatrix(%v) = %v, want %v", zones, got, want)
+	}
+}
+
+func TestValidateGKEMatrixZones(t *testing.T) {
+	for _, tc := range []struct {
+		zones    []string
+		problems int
+	}{
+		{zones: nil, problems: 0},
+		{zones: []string{"asia-south2-c", "asia-south2-a"}, problems: 0},
+		{zones: []string{"asia-south2-c", "us-central1-a"}, problems: 1},
+		{zones: []string{"asia-south2"}, problems: 1},
+	} {
+		config := &RunConfig{Provider: "gke", RancherHostname: "1.2.3.4.sslip.io", RancherPassword: "password", GKEProjectID: "project",
+			ChartDowngradeVersions: 1, ScaleNodePools: 2, ScaleNodesPerPool: 1, PollBackoffFactor: 1, PollMaxInterval: 1, GKEMatrixZones: tc.zones}
+		if problems := config.Validate(SuiteCommon); len(problems) != tc.problems {
+			t.Errorf("Validate() with GKE_MATRIX_ZONES %v = %v, want %d problems", tc.zones, problems, tc.problems)
+		}
+	}
+}
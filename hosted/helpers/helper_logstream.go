@@ -0,0 +1,54 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+)
+
+// StreamOperatorLogs follows the logs of the provider operator pods in cattle-system for the duration of the current spec
+// and writes them with timestamps to operator-stream.log in the spec artifact directory.
+// Operator pods replaced during the spec (for e.g. by a chart upgrade) are followed as well.
+// It must be called from a setup node (BeforeEach, JustBeforeEach or It); streaming is stopped by DeferCleanup once the spec is done.
+func StreamOperatorLogs() {
+	dir, err := ArtifactDir(ginkgo.CurrentSpecReport())
+	if err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to create the artifact directory, operator logs will not be streamed: %v", err))
+		return
+	}
+	logFile, err := os.Create(filepath.Join(dir, "operator-stream.log"))
+	if err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to create the operator log file, operator logs will not be streamed: %v", err))
+		return
+	}
+
+	streamCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		since := time.Now()
+		for streamCtx.Err() == nil {
+			args := []string{"logs", "--namespace", CattleSystemNS, "-l", operatorSelector(Provider), "--follow", "--timestamps", "--prefix", "--ignore-errors", "--since-time", since.Format(time.RFC3339)}
+			since = time.Now()
+			// kubectl complains on stderr as long as no operator pod exists, for e.g. before rancher is installed; it follows until the spec is done
+			_, _ = extcli.Kubectl.WithStdout(logFile).WithTimeout(0).RunContext(streamCtx, upstreamKubectlArgs(args...)...)
+
+			// kubectl stops following once the pods it follows are gone; wait for the new ones to come up
+			select {
+			case <-streamCtx.Done():
+			case <-time.After(10 * time.Second):
+			}
+		}
+	}()
+
+	ginkgo.DeferCleanup(func() {
+		cancel()
+		<-done
+		_ = logFile.Close()
+	})
+}
@@ -0,0 +1,166 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// Resource kinds recorded in the resource manifest
+const (
+	ResourceRancherCluster  = "rancher-cluster"
+	ResourceCloudCluster    = "cloud-cluster"
+	ResourceCloudCredential = "cloud-credential"
+)
+
+// Resource is a cloud or Rancher resource created by the helpers during the run
+type Resource struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	ID       string `json:"id,omitempty"`
+	Provider string `json:"provider"`
+	Region   string `json:"region,omitempty"`
+	// KubernetesVersion is the version the cluster was created with
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+	// NodePools are the instance types and node counts the cluster was created with, used to estimate the cost of the run
+	NodePools []NodePoolSize    `json:"nodePools,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	Spec      string            `json:"spec,omitempty"`
+	// CorrelationID is the correlation ID of the spec execution which created the resource, see CorrelationID
+	CorrelationID string     `json:"correlationID,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	DeletedAt     *time.Time `json:"deletedAt,omitempty"`
+}
+
+// ResourceManifest lists the resources a run has touched; one manifest is written per parallel process
+type ResourceManifest struct {
+	RunID           string      `json:"runID"`
+	RancherHostname string      `json:"rancherHostname"`
+	Provider        string      `json:"provider"`
+	Process         int         `json:"process"`
+	Resources       []*Resource `json:"resources"`
+}
+
+var resourceManifest = struct {
+	sync.Mutex
+	ResourceManifest
+}{}
+
+// resourceManifestPath returns the path of the manifest of the current process
+func resourceManifestPath() string {
+	return filepath.Join(ArtifactsDir, fmt.Sprintf("resource-manifest-p%d.json", ginkgo.GinkgoParallelProcess()))
+}
+
+// writeResourceManifest writes the manifest; it must be called with the manifest locked.
+// The manifest is rewritten on every change so that it is complete even if the suite is interrupted.
+func writeResourceManifest() {
+	resourceManifest.RunID = runConfig.RunID
+	resourceManifest.RancherHostname = RancherHostname
+	resourceManifest.Provider = Provider
+	resourceManifest.Process = ginkgo.GinkgoParallelProcess()
+
+	content, err := json.MarshalIndent(resourceManifest.ResourceManifest, "", "  ")
+	if err == nil {
+		if err = os.MkdirAll(ArtifactsDir, 0o755); err == nil {
+			err = os.WriteFile(resourceManifestPath(), content, 0o644)
+		}
+	}
+	if err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to write the resource manifest: %v", err))
+	}
+}
+
+// runResourceManifests returns the resource manifests of the parallel processes of the current run; the manifests left in ArtifactsDir by the other
+// runs, for e.g. by the processes of an earlier run with more of them, or with another Rancher, are left out
+func runResourceManifests() []ResourceManifest {
+	var manifests []ResourceManifest
+	for _, manifest := range readArtifacts[ResourceManifest]("resource-manifest-p*.json") {
+		if manifest.RunID == runConfig.RunID && manifest.RancherHostname == RancherHostname {
+			manifests = append(manifests, manifest)
+		}
+	}
+	return manifests
+}
+
+// TrackResource records a resource created by the helpers in the resource manifest;
+// the provider and the spec creating it are filled in if empty.
+func TrackResource(resource Resource) {
+	if resource.Provider == "" {
+		resource.Provider = Provider
+	}
+	if resource.Spec == "" {
+		resource.Spec = ginkgo.CurrentSpecReport().FullText()
+	}
+	if resource.CorrelationID == "" {
+		resource.CorrelationID = CorrelationID()
+	}
+	resource.CreatedAt = time.Now().UTC()
+
+	resourceManifest.Lock()
+	defer resourceManifest.Unlock()
+	resourceManifest.Resources = append(resourceManifest.Resources, &resource)
+	writeResourceManifest()
+}
+
+// MarkResourceDeleted records the deletion of the resource of the given kind and name in the resource manifest
+func MarkResourceDeleted(kind, name string) {
+	resourceManifest.Lock()
+	defer resourceManifest.Unlock()
+
+	deletedAt := time.Now().UTC()
+	for _, resource := range resourceManifest.Resources {
+		if resource.Kind == kind && resource.Name == name && resource.DeletedAt == nil {
+			resource.DeletedAt = &deletedAt
+		}
+	}
+	writeResourceManifest()
+}
+
+// TrackRancherCluster records the cluster created on Rancher in the resource manifest if err is nil,
+// times the operation (OperationProvision or OperationImport) until the cluster is ready and records the transitions of the cluster
+// (see RecordClusterTransitions); cluster and err are returned unchanged
+func TrackRancherCluster(operation string, cluster *management.Cluster, err error, region string, tags map[string]string) (*management.Cluster, error) {
+	if err == nil && cluster != nil {
+		TrackResource(Resource{Kind: ResourceRancherCluster, Name: cluster.Name, ID: cluster.ID, Provider: clusterProvider(cluster), Region: region, Tags: tags, KubernetesVersion: clusterKubernetesVersion(cluster), NodePools: clusterNodePools(cluster)})
+		TimeUntilClusterReady(operation, cluster)
+		watchClusterTransitions(operation, cluster)
+	}
+	return cluster, err
+}
+
+// clusterProvider returns the provider of the cluster according to its config, or the current provider if it has none;
+// the multi-provider suites rely on it to attribute the resources and metrics to the right provider
+func clusterProvider(cluster *management.Cluster) string {
+	switch {
+	case cluster.EKSConfig != nil:
+		return "eks"
+	case cluster.GKEConfig != nil:
+		return "gke"
+	case cluster.AKSConfig != nil:
+		return "aks"
+	}
+	return Provider
+}
+
+// clusterKubernetesVersion returns the kubernetes version of the cluster config, if any
+func clusterKubernetesVersion(cluster *management.Cluster) string {
+	var version *string
+	switch {
+	case cluster.EKSConfig != nil:
+		version = cluster.EKSConfig.KubernetesVersion
+	case cluster.GKEConfig != nil:
+		version = cluster.GKEConfig.KubernetesVersion
+	case cluster.AKSConfig != nil:
+		version = cluster.AKSConfig.KubernetesVersion
+	}
+	if version == nil {
+		return ""
+	}
+	return *version
+}
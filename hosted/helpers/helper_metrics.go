@@ -0,0 +1,306 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// Operations whose duration is recorded in the operation metrics
+const (
+	OperationProvision      = "provision"
+	OperationImport         = "import"
+	OperationUpgrade        = "upgrade"
+	OperationNodeUpgrade    = "node-upgrade"
+	OperationScale          = "scale"
+	OperationAddNodePool    = "add-nodepool"
+	OperationDeleteNodePool = "delete-nodepool"
+	// OperationCLIProvision and OperationCLIDelete are the creation and deletion of a cluster with the provider CLI
+	OperationCLIProvision = "cli-provision"
+	OperationCLIDelete    = "cli-delete"
+	// OperationChartInstall is the installation of a chart on a downstream cluster, for e.g. rancher-monitoring
+	OperationChartInstall = "chart-install"
+	// OperationNodesReady is the time until the nodes of a cluster are all registered and active in Rancher after a change of its node count
+	OperationNodesReady = "nodes-ready"
+	// OperationNodePoolBatch is the time until several nodegroup/nodepool changes applied in a single cluster update are all reconciled
+	OperationNodePoolBatch = "nodepool-batch"
+)
+
+// OperationMetric is the duration of an operation performed on a cluster
+type OperationMetric struct {
+	Operation      string    `json:"operation"`
+	Provider       string    `json:"provider"`
+	Cluster        string    `json:"cluster"`
+	RancherVersion string    `json:"rancherVersion"`
+	Spec           string    `json:"spec"`
+	Start          time.Time `json:"start"`
+	Seconds        float64   `json:"seconds"`
+	Success        bool      `json:"success"`
+	// FromVersion and ToVersion are the kubernetes versions of an upgrade
+	FromVersion string `json:"fromVersion,omitempty"`
+	ToVersion   string `json:"toVersion,omitempty"`
+}
+
+var (
+	operationMetrics = struct {
+		sync.Mutex
+		metrics []OperationMetric
+	}{}
+
+	// pendingOperations holds the start of the operations completing when the cluster is ready, by cluster ID
+	pendingOperations = struct {
+		sync.Mutex
+		starts map[string]OperationMetric
+	}{starts: map[string]OperationMetric{}}
+)
+
+// operationMetricsPath returns the path of the metrics file of the current process
+func operationMetricsPath() string {
+	return filepath.Join(ArtifactsDir, fmt.Sprintf("operation-metrics-p%d.json", ginkgo.GinkgoParallelProcess()))
+}
+
+// newOperationMetric returns the metric of an operation starting now
+func newOperationMetric(operation, clusterName string) OperationMetric {
+	return OperationMetric{
+		Operation:      operation,
+		Provider:       Provider,
+		Cluster:        clusterName,
+		RancherVersion: RancherFullVersion,
+		Spec:           ginkgo.CurrentSpecReport().FullText(),
+		Start:          time.Now().UTC(),
+	}
+}
+
+// recordOperationMetric records the metric, rewrites the metrics file and pushes the metrics to the pushgateway if configured;
+// failing to write or push the metrics must not fail the spec, it is only logged.
+func recordOperationMetric(metric OperationMetric, success bool) {
+	metric.Seconds = time.Since(metric.Start).Seconds()
+	metric.Success = success
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Operation %s on cluster %s took %.0fs (success: %t)", metric.Operation, metric.Cluster, metric.Seconds, success))
+
+	operationMetrics.Lock()
+	defer operationMetrics.Unlock()
+	operationMetrics.metrics = append(operationMetrics.metrics, metric)
+
+	content, err := json.MarshalIndent(operationMetrics.metrics, "", "  ")
+	if err == nil {
+		if err = os.MkdirAll(ArtifactsDir, 0o755); err == nil {
+			err = os.WriteFile(operationMetricsPath(), content, 0o644)
+		}
+	}
+	if err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to write the operation metrics: %v", err))
+	}
+
+	if runConfig.PushgatewayURL != "" {
+		if err = pushOperationMetrics(operationMetrics.metrics); err != nil {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to push the operation metrics: %v", err))
+		}
+	}
+}
+
+// RecordOperation records the duration of an operation started at start; err is the outcome of the operation
+func RecordOperation(operation, clusterName string, start time.Time, err error) {
+	metric := newOperationMetric(operation, clusterName)
+	metric.Start = start.UTC()
+	recordOperationMetric(metric, err == nil)
+}
+
+// TimeOperation starts timing the operation on the cluster and returns the function recording it, which must be deferred, for e.g.
+// defer helpers.TimeOperation(helpers.OperationScale, cluster, wait)()
+// The operation is successful unless the helper fails through Ginkgo. Nothing is recorded if measure is false,
+// for e.g. when the helper does not wait for the operation to complete.
+func TimeOperation(operation string, cluster *management.Cluster, measure bool) func() {
+	if !measure {
+		return func() {}
+	}
+	metric := newOperationMetric(operation, cluster.Name)
+	metric.Provider = clusterProvider(cluster)
+	return deferredOperationMetric(metric)
+}
+
+// TimeUpgrade is TimeOperation for the upgrades of the control plane (OperationUpgrade) or of the nodes (OperationNodeUpgrade) of the cluster
+// to the kubernetes version; the version transition is recorded along with the duration, for e.g.
+// defer helpers.TimeUpgrade(helpers.OperationNodeUpgrade, cluster, upgradeToVersion, wait)()
+func TimeUpgrade(operation string, cluster *management.Cluster, version string, measure bool) func() {
+	if !measure {
+		return func() {}
+	}
+	metric := newOperationMetric(operation, cluster.Name)
+	metric.Provider = clusterProvider(cluster)
+	metric.FromVersion, metric.ToVersion = upgradeFromVersion(operation, cluster), version
+	return deferredOperationMetric(metric)
+}
+
+// upgradeFromVersion returns the kubernetes version the cluster is upgraded from: the version of its first nodegroup/nodepool for a node upgrade,
+// since the control plane is upgraded first, and the version of the control plane otherwise
+func upgradeFromVersion(operation string, cluster *management.Cluster) string {
+	controlPlane, nodePools := upstreamVersions(cluster)
+	if operation == OperationNodeUpgrade && len(nodePools) > 0 && nodePools[0] != "" {
+		return nodePools[0]
+	}
+	if controlPlane == "" {
+		return clusterKubernetesVersion(cluster)
+	}
+	return controlPlane
+}
+
+// deferredOperationMetric returns the function recording the metric, successful unless the caller fails through Ginkgo
+func deferredOperationMetric(metric OperationMetric) func() {
+	return func() {
+		r := recover()
+		recordOperationMetric(metric, r == nil)
+		if r != nil {
+			panic(r)
+		}
+	}
+}
+
+// TimeUntilClusterReady starts timing an operation that completes when the cluster is ready, for e.g. provisioning or import;
+// it is recorded by the next WaitUntilClusterIsReady on the cluster.
+func TimeUntilClusterReady(operation string, cluster *management.Cluster) {
+	pendingOperations.Lock()
+	defer pendingOperations.Unlock()
+	metric := newOperationMetric(operation, cluster.Name)
+	metric.Provider = clusterProvider(cluster)
+	pendingOperations.starts[cluster.ID] = metric
+}
+
+// recordClusterReady records the pending operation of the cluster, if any
+func recordClusterReady(clusterID string, err error) {
+	pendingOperations.Lock()
+	metric, ok := pendingOperations.starts[clusterID]
+	delete(pendingOperations.starts, clusterID)
+	pendingOperations.Unlock()
+
+	if ok {
+		recordOperationMetric(metric, err == nil)
+	}
+}
+
+// pushOperationMetrics replaces the metrics of the current process on the Prometheus pushgateway;
+// the durations are aggregated per provider, operation and rancher version.
+func pushOperationMetrics(metrics []OperationMetric) error {
+	var body bytes.Buffer
+	writeOperationMetrics(&body, metrics)
+	return pushMetrics(http.MethodPut, fmt.Sprintf("process/%d", ginkgo.GinkgoParallelProcess()), &body)
+}
+
+// writeOperationMetrics writes the durations of the operations aggregated per provider, operation and rancher version in the Prometheus text format
+func writeOperationMetrics(body *bytes.Buffer, metrics []OperationMetric) {
+	type series struct {
+		provider, operation, rancherVersion string
+	}
+	type aggregate struct {
+		count, failures int
+		sum, max        float64
+	}
+	aggregates := map[series]*aggregate{}
+	for _, metric := range metrics {
+		key := series{metric.Provider, metric.Operation, metric.RancherVersion}
+		if aggregates[key] == nil {
+			aggregates[key] = &aggregate{}
+		}
+		a := aggregates[key]
+		if !metric.Success {
+			a.failures++
+			continue
+		}
+		a.count++
+		a.sum += metric.Seconds
+		a.max = max(a.max, metric.Seconds)
+	}
+	keys := make([]series, 0, len(aggregates))
+	for key := range aggregates {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+
+	for _, name := range []string{"sum", "count", "max", "failures"} {
+		fmt.Fprintf(body, "# TYPE hosted_providers_e2e_operation_duration_seconds_%s gauge\n", name)
+		for _, key := range keys {
+			a := aggregates[key]
+			value := map[string]float64{"sum": a.sum, "count": float64(a.count), "max": a.max, "failures": float64(a.failures)}[name]
+			fmt.Fprintf(body, "hosted_providers_e2e_operation_duration_seconds_%s{provider=%q,operation=%q,rancher_version=%q} %g\n",
+				name, key.provider, key.operation, key.rancherVersion, value)
+		}
+	}
+}
+
+// pushSuiteMetrics pushes the metrics of the suite to the Prometheus pushgateway, grouped by run ID and suite: the duration and outcome of the suite,
+// its number of specs per state and the operation metrics of all its parallel processes, which replace the ones pushed by every process during the run.
+func pushSuiteMetrics(summary SuiteSummary, parallelTotal int) error {
+	var metrics []OperationMetric
+	for _, processMetrics := range readArtifacts[[]OperationMetric]("operation-metrics-p*.json") {
+		metrics = append(metrics, processMetrics...)
+	}
+	var body bytes.Buffer
+	writeOperationMetrics(&body, metrics)
+
+	labels := fmt.Sprintf("provider=%q,suite=%q,rancher_version=%q", summary.Provider, summary.Suite, summary.RancherVersion)
+	succeeded := 0
+	if summary.Succeeded {
+		succeeded = 1
+	}
+	fmt.Fprintf(&body, "# TYPE hosted_providers_e2e_suite_duration_seconds gauge\n")
+	fmt.Fprintf(&body, "hosted_providers_e2e_suite_duration_seconds{%s} %g\n", labels, summary.Seconds)
+	fmt.Fprintf(&body, "# TYPE hosted_providers_e2e_suite_succeeded gauge\n")
+	fmt.Fprintf(&body, "hosted_providers_e2e_suite_succeeded{%s} %d\n", labels, succeeded)
+	fmt.Fprintf(&body, "# TYPE hosted_providers_e2e_suite_end_timestamp_seconds gauge\n")
+	fmt.Fprintf(&body, "hosted_providers_e2e_suite_end_timestamp_seconds{%s} %d\n", labels, summary.EndTime.Unix())
+	states := make([]string, 0, len(summary.Counts))
+	for state := range summary.Counts {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+	fmt.Fprintf(&body, "# TYPE hosted_providers_e2e_suite_specs gauge\n")
+	for _, state := range states {
+		fmt.Fprintf(&body, "hosted_providers_e2e_suite_specs{%s,state=%q} %d\n", labels, state, summary.Counts[state])
+	}
+
+	if err := pushMetrics(http.MethodPut, "suite/"+url.PathEscape(summary.Suite), &body); err != nil {
+		return err
+	}
+	// the operation metrics of the processes are now part of the suite group
+	for process := 1; process <= parallelTotal; process++ {
+		if err := pushMetrics(http.MethodDelete, fmt.Sprintf("process/%d", process), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pushMetrics sends a request for a group of the run on the Prometheus pushgateway, for e.g. a PUT replacing the metrics of process/1
+func pushMetrics(method, group string, body io.Reader) error {
+	pushURL := fmt.Sprintf("%s/metrics/job/hosted-providers-e2e/run_id/%s/%s", runConfig.PushgatewayURL, url.PathEscape(sanitizeName(runConfig.RunID)), group)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, pushURL, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("pushgateway returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+func TestPushOperationMetrics(t *testing.T) {
+	defer func(url, runID string) { runConfig.PushgatewayURL, runConfig.RunID = url, runID }(runConfig.PushgatewayURL, runConfig.RunID)
+
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := io.ReadAll(r.Body)
+		method, path, body = r.Method, r.URL.Path, string(content)
+	}))
+	defer server.Close()
+	runConfig.PushgatewayURL, runConfig.RunID = server.URL, "Run 42"
+
+	metrics := []OperationMetric{
+		{Operation: OperationScale, Provider: "eks", RancherVersion: "2.9.0", Seconds: 60, Success: true},
+		{Operation: OperationScale, Provider: "eks", RancherVersion: "2.9.0", Seconds: 120, Success: true},
+		{Operation: OperationScale, Provider: "eks", RancherVersion: "2.9.0", Seconds: 900, Success: false},
+	}
+	if err := pushOperationMetrics(metrics); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if method != http.MethodPut || path != "/metrics/job/hosted-providers-e2e/run_id/run-42/process/1" {
+		t.Errorf("unexpected request: %s %s", method, path)
+	}
+	labels := `{provider="eks",operation="scale",rancher_version="2.9.0"}`
+	for _, want := range []string{
+		"hosted_providers_e2e_operation_duration_seconds_sum" + labels + " 180\n",
+		"hosted_providers_e2e_operation_duration_seconds_count" + labels + " 2\n",
+		"hosted_providers_e2e_operation_duration_seconds_max" + labels + " 120\n",
+		"hosted_providers_e2e_operation_duration_seconds_failures" + labels + " 1\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("%q not found in:\n%s", want, body)
+		}
+	}
+}
+
+func TestPushSuiteMetrics(t *testing.T) {
+	defer func(url, runID, dir string) {
+		runConfig.PushgatewayURL, runConfig.RunID, ArtifactsDir = url, runID, dir
+	}(runConfig.PushgatewayURL, runConfig.RunID, ArtifactsDir)
+
+	var requests []string
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPut {
+			content, _ := io.ReadAll(r.Body)
+			body = string(content)
+		}
+	}))
+	defer server.Close()
+	runConfig.PushgatewayURL, runConfig.RunID, ArtifactsDir = server.URL, "42", t.TempDir()
+
+	for process, seconds := range []float64{60, 120} {
+		content, _ := json.Marshal([]OperationMetric{{Operation: OperationImport, Provider: "gke", RancherVersion: "2.10.3", Seconds: seconds, Success: true}})
+		if err := os.WriteFile(filepath.Join(ArtifactsDir, fmt.Sprintf("operation-metrics-p%d.json", process+1)), content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	summary := SuiteSummary{
+		Suite:          "gke-p0",
+		Provider:       "gke",
+		RancherVersion: "2.10.3",
+		EndTime:        time.Unix(1700000000, 0),
+		Seconds:        1800,
+		Counts:         map[string]int{"passed": 3, "failed": 1},
+	}
+	if err := pushSuiteMetrics(summary, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"PUT /metrics/job/hosted-providers-e2e/run_id/42/suite/gke-p0",
+		"DELETE /metrics/job/hosted-providers-e2e/run_id/42/process/1",
+		"DELETE /metrics/job/hosted-providers-e2e/run_id/42/process/2",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("unexpected requests: %v", requests)
+	}
+	labels := `{provider="gke",suite="gke-p0",rancher_version="2.10.3"`
+	for _, want := range []string{
+		`hosted_providers_e2e_operation_duration_seconds_count{provider="gke",operation="import",rancher_version="2.10.3"} 2` + "\n",
+		"hosted_providers_e2e_suite_duration_seconds" + labels + "} 1800\n",
+		"hosted_providers_e2e_suite_succeeded" + labels + "} 0\n",
+		"hosted_providers_e2e_suite_end_timestamp_seconds" + labels + "} 1700000000\n",
+		"hosted_providers_e2e_suite_specs" + labels + `,state="failed"} 1` + "\n",
+		"hosted_providers_e2e_suite_specs" + labels + `,state="passed"} 3` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("%q not found in:\n%s", want, body)
+		}
+	}
+}
+
+func TestUpgradeFromVersion(t *testing.T) {
+	controlPlane, nodes := "1.31", "1.30"
+	cluster := &management.Cluster{
+		EKSConfig: &management.EKSClusterConfigSpec{KubernetesVersion: &controlPlane},
+		EKSStatus: &management.EKSStatus{UpstreamSpec: &management.EKSClusterConfigSpec{
+			KubernetesVersion: &controlPlane,
+			NodeGroups:        &[]management.NodeGroup{{Version: &nodes}},
+		}},
+	}
+	if version := upgradeFromVersion(OperationNodeUpgrade, cluster); version != "1.30" {
+		t.Errorf("upgradeFromVersion() of a node upgrade = %q, want 1.30", version)
+	}
+	if version := upgradeFromVersion(OperationUpgrade, cluster); version != "1.31" {
+		t.Errorf("upgradeFromVersion() of a control plane upgrade = %q, want 1.31", version)
+	}
+	cluster.EKSStatus = nil
+	if version := upgradeFromVersion(OperationNodeUpgrade, cluster); version != "1.31" {
+		t.Errorf("upgradeFromVersion() without UpstreamSpec = %q, want the config version 1.31", version)
+	}
+}
@@ -0,0 +1,136 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/rancher/tests/v2/actions/charts"
+	"github.com/rancher/rancher/tests/v2/actions/projects"
+	"github.com/rancher/shepherd/clients/rancher"
+	"github.com/rancher/shepherd/clients/rancher/catalog"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	extcharts "github.com/rancher/shepherd/extensions/charts"
+	shepherdclusters "github.com/rancher/shepherd/extensions/clusters"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultMonitoringJobs are the Prometheus jobs expected to be scraped on every hosted cluster; the control plane components other than
+// the API server are managed by the cloud provider and can not be scraped.
+var DefaultMonitoringJobs = []string{"apiserver", "kubelet", "node-exporter", "kube-state-metrics"}
+
+// prometheusTargetsPath is the path of the Prometheus targets API, proxied by the kube API server of the downstream cluster
+var prometheusTargetsPath = fmt.Sprintf("/api/v1/namespaces/%s/services/http:rancher-monitoring-prometheus:9090/proxy/api/v1/targets", charts.RancherMonitoringNamespace)
+
+// prometheusTargets is the part of the Prometheus targets API response needed to check the targets
+type prometheusTargets struct {
+	Data struct {
+		ActiveTargets []struct {
+			Labels    map[string]string `json:"labels"`
+			Health    string            `json:"health"`
+			LastError string            `json:"lastError"`
+		} `json:"activeTargets"`
+	} `json:"data"`
+}
+
+// downMonitoringJobs returns the problems of the jobs in the Prometheus targets API response, for e.g. a job without active target
+// or a target that is down; an empty list means all the jobs are up.
+func downMonitoringJobs(content []byte, jobs []string) ([]string, error) {
+	var targets prometheusTargets
+	if err := json.Unmarshal(content, &targets); err != nil {
+		return nil, err
+	}
+
+	active := map[string]int{}
+	var problems []string
+	for _, target := range targets.Data.ActiveTargets {
+		job := target.Labels["job"]
+		active[job]++
+		if target.Health != "up" {
+			problems = append(problems, fmt.Sprintf("%s target %s is %s: %s", job, target.Labels["instance"], target.Health, target.LastError))
+		}
+	}
+	for _, job := range jobs {
+		if active[job] == 0 {
+			problems = append(problems, fmt.Sprintf("%s has no active target", job))
+		}
+	}
+	sort.Strings(problems)
+	return problems, nil
+}
+
+/*
+Install the rancher-monitoring chart on a downstream cluster through the Rancher catalog API, in the System project, and wait for its workloads
+to be ready; the chart is uninstalled when the client session is cleaned up.
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @param version Version of the chart; the latest version of the Rancher chart repository is used if empty
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func InstallRancherMonitoring(client *rancher.Client, cluster *management.Cluster, version string) {
+	status, err := extcharts.GetChartStatus(client, cluster.ID, charts.RancherMonitoringNamespace, charts.RancherMonitoringName)
+	Expect(err).To(BeNil())
+	if status.IsAlreadyInstalled {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("%s is already installed on cluster %s", charts.RancherMonitoringName, cluster.Name))
+		return
+	}
+
+	catalogClient, err := client.GetClusterCatalogClient(cluster.ID)
+	Expect(err).To(BeNil())
+	if version == "" {
+		version, err = catalogClient.GetLatestChartVersion(charts.RancherMonitoringName, catalog.RancherChartRepo)
+		Expect(err).To(BeNil())
+	}
+
+	clusterMeta, err := shepherdclusters.NewClusterMeta(client, cluster.Name)
+	Expect(err).To(BeNil())
+	systemProject, err := projects.GetProjectByName(client, cluster.ID, "System")
+	Expect(err).To(BeNil())
+
+	ginkgo.By(fmt.Sprintf("Installing %s %s on cluster %s", charts.RancherMonitoringName, version, cluster.Name), func() {
+		defer TimeOperation(OperationChartInstall, cluster, true)()
+
+		err = charts.InstallRancherMonitoringChart(client, &charts.InstallOptions{
+			Cluster:   clusterMeta,
+			Version:   version,
+			ProjectID: systemProject.ID,
+		}, &charts.RancherMonitoringOpts{})
+		Expect(err).To(BeNil())
+
+		Expect(extcharts.WatchAndWaitDeployments(client, cluster.ID, charts.RancherMonitoringNamespace, metav1.ListOptions{})).To(Succeed())
+		Expect(extcharts.WatchAndWaitDaemonSets(client, cluster.ID, charts.RancherMonitoringNamespace, metav1.ListOptions{})).To(Succeed())
+		Expect(extcharts.WatchAndWaitStatefulSets(client, cluster.ID, charts.RancherMonitoringNamespace, metav1.ListOptions{})).To(Succeed())
+	})
+}
+
+/*
+Check that Prometheus scrapes the downstream cluster: every job must have at least one active target, and all the targets must be up
+  - @param client Rancher client
+  - @param cluster Downstream cluster on which rancher-monitoring is installed
+  - @param jobs Prometheus jobs to check; DefaultMonitoringJobs are checked if none is given
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckMonitoringTargets(client *rancher.Client, cluster *management.Cluster, jobs ...string) {
+	if len(jobs) == 0 {
+		jobs = DefaultMonitoringJobs
+	}
+	catalogClient, err := client.GetClusterCatalogClient(cluster.ID)
+	Expect(err).To(BeNil())
+
+	ginkgo.By(fmt.Sprintf("Checking the Prometheus targets of cluster %s", cluster.Name), func() {
+		EventuallyWithBackoff(func() (string, error) {
+			content, err := catalogClient.RESTClient().Get().AbsPath(prometheusTargetsPath).Do(context.Background()).Raw()
+			if err != nil {
+				return "", err
+			}
+			problems, err := downMonitoringJobs(content, jobs)
+			return strings.Join(problems, "\n"), err
+		}, tools.SetTimeout(10*time.Minute), 15*time.Second).Should(BeEmpty(), "Prometheus targets are not up")
+	})
+}
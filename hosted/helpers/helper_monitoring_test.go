@@ -0,0 +1,33 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDownMonitoringJobs(t *testing.T) {
+	content := []byte(`{"status":"success","data":{"activeTargets":[
+		{"labels":{"job":"apiserver","instance":"10.0.0.1:443"},"health":"up","lastError":""},
+		{"labels":{"job":"kubelet","instance":"10.0.1.5:10250"},"health":"up","lastError":""},
+		{"labels":{"job":"kubelet","instance":"10.0.1.6:10250"},"health":"down","lastError":"context deadline exceeded"}
+	]}}`)
+
+	problems, err := downMonitoringJobs(content, []string{"apiserver", "kubelet", "node-exporter"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"kubelet target 10.0.1.6:10250 is down: context deadline exceeded",
+		"node-exporter has no active target",
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("got %q, want %q", problems, want)
+	}
+
+	if problems, err = downMonitoringJobs(content, []string{"apiserver"}); err != nil || len(problems) != 1 {
+		t.Errorf("got %q, %v; want only the down kubelet target", problems, err)
+	}
+	if _, err = downMonitoringJobs([]byte("<html>"), nil); err == nil {
+		t.Error("expected an error for an invalid response")
+	}
+}
@@ -0,0 +1,112 @@
+package helpers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+)
+
+// namingPolicy holds the naming limits of a provider that the generated names and metadata labels must comply with
+type namingPolicy struct {
+	// maxNameLength is the maximum length of a cluster name
+	maxNameLength int
+	// maxLabelLength is the maximum length of a label/tag value
+	maxLabelLength int
+	// invalidLabelChars matches the characters not allowed in a label/tag value; nil if all the characters are allowed
+	invalidLabelChars *regexp.Regexp
+	// lowercaseLabels is true if the label/tag values must be lowercase
+	lowercaseLabels bool
+}
+
+var (
+	namingPolicies = map[string]namingPolicy{
+		// the DNS prefix of the cluster is <name>-dns and can not exceed 54 characters
+		"aks": {maxNameLength: 50, maxLabelLength: 256},
+		// the cluster name is also used as the value of the metadata labels, limited to 63 characters
+		"eks": {maxNameLength: 63, maxLabelLength: 256, invalidLabelChars: regexp.MustCompile(`[^\p{L}\p{Z}\p{N}_.:/=+\-@]`)},
+		"gke": {maxNameLength: 40, maxLabelLength: 63, invalidLabelChars: regexp.MustCompile(`[^a-z0-9_-]`), lowercaseLabels: true},
+	}
+	// defaultNamingPolicy is the strictest policy, used if the provider is unknown
+	defaultNamingPolicy = namingPolicies["gke"]
+
+	invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+const (
+	// maxRunIDNameLength is the maximum number of characters of the run ID included in the generated names
+	maxRunIDNameLength = 12
+	// randomNameLength is the length of the random suffix of the generated names, the same as namegen.AppendRandomString
+	randomNameLength = 5
+)
+
+// currentNamingPolicy returns the naming policy of the provider under test
+func currentNamingPolicy() namingPolicy {
+	if policy, ok := namingPolicies[Provider]; ok {
+		return policy
+	}
+	return defaultNamingPolicy
+}
+
+// sanitizeName lowercases the value and replaces the characters not allowed in a cluster name with a hyphen
+func sanitizeName(value string) string {
+	return strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(value), "-"), "-")
+}
+
+// sanitizeLabelValue makes the value comply with the label/tag policy of the provider;
+// if it is too long, the end of the value is kept since it is usually the most specific part, for e.g. the run number of a pipeline URL.
+func sanitizeLabelValue(value string) string {
+	policy := currentNamingPolicy()
+	if policy.lowercaseLabels {
+		value = strings.ToLower(value)
+	}
+	if policy.invalidLabelChars != nil {
+		value = policy.invalidLabelChars.ReplaceAllString(value, "-")
+	}
+	if len(value) > policy.maxLabelLength {
+		value = strings.TrimLeft(value[len(value)-policy.maxLabelLength:], "-_")
+	}
+	return value
+}
+
+// GenerateClusterName returns a random cluster name starting with the base, usually ClusterNamePrefix;
+// it contains the run ID so that leaked clusters can be traced to the run that created them, and complies with the naming policy of the provider.
+// The base is truncated if the name would be too long, the run ID and the random suffix are always kept; for e.g. auto-eks-hp-ci-12345678901-abcde.
+// When the suite runs with `ginkgo -p`, the name also contains the parallel process, for e.g. auto-eks-hp-ci-12345678901-p2-abcde,
+// so that the names generated by the processes can not collide.
+func GenerateClusterName(base string) string {
+	process := 0
+	if suiteConfig, _ := ginkgo.GinkgoConfiguration(); suiteConfig.ParallelTotal > 1 {
+		process = ginkgo.GinkgoParallelProcess()
+	}
+	return generateClusterName(base, process)
+}
+
+// generateClusterName returns the cluster name of GenerateClusterName for the parallel process; 0 if the suite does not run in parallel
+func generateClusterName(base string, process int) string {
+	runID := sanitizeName(runConfig.RunID)
+	if len(runID) > maxRunIDNameLength {
+		runID = strings.TrimLeft(runID[len(runID)-maxRunIDNameLength:], "-")
+	}
+
+	suffix := namegen.RandStringLower(randomNameLength)
+	if process > 0 {
+		suffix = fmt.Sprintf("p%d-%s", process, suffix)
+	}
+	if runID != "" {
+		suffix = runID + "-" + suffix
+	}
+
+	// namegen.AppendRandomString names start with auto-, which is relied upon by the cleanup jobs
+	const prefix = "auto-"
+	base = sanitizeName(base)
+	if maxBase := currentNamingPolicy().maxNameLength - len(prefix) - len(suffix) - 1; len(base) > maxBase {
+		base = strings.TrimRight(base[:max(maxBase, 0)], "-")
+	}
+	if base == "" {
+		return prefix + suffix
+	}
+	return fmt.Sprintf("%s%s-%s", prefix, base, suffix)
+}
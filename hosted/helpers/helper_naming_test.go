@@ -0,0 +1,55 @@
+package helpers
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestGenerateClusterName(t *testing.T) {
+	defer func(provider, runID string) { Provider, runConfig.RunID = provider, runID }(Provider, runConfig.RunID)
+
+	valid := regexp.MustCompile(`^auto-[a-z0-9-]*[a-z0-9]$`)
+	for provider, policy := range namingPolicies {
+		for _, tt := range []struct {
+			base, runID string
+		}{
+			{base: provider + "-hp-ci", runID: "12345678901"},
+			{base: provider + "-a-very-long-user_name.with.dots-hp-ci", runID: "12345678901"},
+			{base: provider + "-hp-ci", runID: "Local Run/42"},
+			{base: provider + "-hp-ci", runID: ""},
+		} {
+			Provider, runConfig.RunID = provider, tt.runID
+			name := GenerateClusterName(tt.base)
+			if len(name) > policy.maxNameLength {
+				t.Errorf("%s: %q is longer than %d characters", provider, name, policy.maxNameLength)
+			}
+			if !valid.MatchString(name) {
+				t.Errorf("%s: %q is not a valid name", provider, name)
+			}
+			if runID := sanitizeName(tt.runID); !strings.Contains(name, runID) {
+				t.Errorf("%s: %q does not contain the run ID %q", provider, name, runID)
+			}
+			if name := generateClusterName(tt.base, 12); len(name) > policy.maxNameLength || !valid.MatchString(name) || !strings.Contains(name, "-p12-") {
+				t.Errorf("%s: %q is not a valid name of the parallel process 12", provider, name)
+			}
+		}
+	}
+}
+
+func TestSanitizeLabelValue(t *testing.T) {
+	defer func(provider string) { Provider = provider }(Provider)
+
+	url := "https://github.com/rancher/hosted-providers-e2e/actions/runs/12345678901"
+	Provider = "gke"
+	if got := sanitizeLabelValue(url); len(got) > 63 || !strings.HasSuffix(got, "runs-12345678901") || strings.ContainsAny(got, ":/.") {
+		t.Errorf("gke: unexpected value %q", got)
+	}
+	Provider = "eks"
+	if got := sanitizeLabelValue(url); got != url {
+		t.Errorf("eks: got %q, want %q", got, url)
+	}
+	if got := sanitizeLabelValue("owner<name>"); got != "owner-name-" {
+		t.Errorf("eks: got %q, want %q", got, "owner-name-")
+	}
+}
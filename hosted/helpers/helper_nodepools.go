@@ -0,0 +1,48 @@
+package helpers
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// MissingNodePools returns the nodegroups/nodepools of want which are not in names, in the order of want
+func MissingNodePools(want, names []string) []string {
+	var missing []string
+	for _, name := range want {
+		if !slices.Contains(names, name) {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+/*
+WaitForAddedNodePools waits until the nodegroups/nodepools added to a cluster are in its UpstreamSpec: only the added ones are looked up in the cached
+cluster (see WatchedCluster) rather than every nodegroup/nodepool being compared, then the total count is checked once, so that the validation
+of the clusters with many nodegroups/nodepools stays short. The polling follows the profile of OperationAddNodePool.
+  - @param client Rancher client
+  - @param clusterID ID of the cluster
+  - @param added Names of the added nodegroups/nodepools
+  - @param total Number of nodegroups/nodepools the cluster must have once they are added
+  - @param upstreamNames Returns the names of the nodegroups/nodepools of the UpstreamSpec of the cluster
+  - @param timeout Timeout of the wait
+  - @returns The last fetched cluster and the error of the wait
+*/
+func WaitForAddedNodePools(client *rancher.Client, clusterID string, added []string, total int, upstreamNames func(*management.Cluster) []string,
+	timeout time.Duration) (*management.Cluster, error) {
+	cluster, err := waitForUpstreamField(client, clusterID, func(cluster *management.Cluster) any {
+		return MissingNodePools(added, upstreamNames(cluster))
+	}, BeEmpty(), timeout, NewOperationBackoff(OperationAddNodePool))
+	if err != nil {
+		return cluster, err
+	}
+	if count := len(upstreamNames(cluster)); count != total {
+		return cluster, fmt.Errorf("cluster %s has %d nodegroups/nodepools once %v are added, want %d", clusterID, count, added, total)
+	}
+	return cluster, nil
+}
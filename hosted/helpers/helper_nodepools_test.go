@@ -0,0 +1,21 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMissingNodePools(t *testing.T) {
+	for _, tc := range []struct {
+		want, names, missing []string
+	}{
+		{[]string{"np-abc12", "np-def34"}, []string{"np-def34", "ranchernodes", "np-abc12"}, nil},
+		{[]string{"np-abc12", "np-def34"}, []string{"ranchernodes", "np-def34"}, []string{"np-abc12"}},
+		{[]string{"np-abc12"}, nil, []string{"np-abc12"}},
+		{nil, []string{"ranchernodes"}, nil},
+	} {
+		if got := MissingNodePools(tc.want, tc.names); !reflect.DeepEqual(got, tc.missing) {
+			t.Errorf("MissingNodePools(%v, %v) = %v, want %v", tc.want, tc.names, got, tc.missing)
+		}
+	}
+}
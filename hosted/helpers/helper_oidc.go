@@ -0,0 +1,524 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+	"github.com/rancher/shepherd/extensions/cloudcredentials"
+	"github.com/rancher/shepherd/pkg/config"
+)
+
+const (
+	// cloudCredentialSourceStatic and cloudCredentialSourceOIDC are the CLOUD_CREDENTIAL_SOURCE: the static keys of the env, or the keys minted from the OIDC token of the CI
+	cloudCredentialSourceStatic = "static"
+	cloudCredentialSourceOIDC   = "oidc"
+	// mintedSecretTTL is how long the minted keys are valid, in case they can not be revoked at the end of the suite: the Azure client secrets
+	// expire after it, the AWS access keys and GCP service account keys older than it are deleted by the next run, see deleteExpiredKeys
+	mintedSecretTTL = 24 * time.Hour
+	// maxAWSAccessKeys is the number of access keys an IAM user can have
+	maxAWSAccessKeys = 2
+)
+
+// IsOIDCCredentials returns true if the credentials of the providers are minted from the OIDC token of the CI (CLOUD_CREDENTIAL_SOURCE=oidc); see AcquireCloudCredentials
+func IsOIDCCredentials() bool {
+	return runConfig.CloudCredentialSource == cloudCredentialSourceOIDC
+}
+
+// oidcCLI is the CLI minting the credentials of each provider
+var oidcCLI = map[string]string{
+	"eks": "aws",
+	"gke": "gcloud",
+	"aks": "az",
+}
+
+// oidcAudience returns the audience of the OIDC token exchanged by the cloud provider
+func oidcAudience(config *RunConfig, provider string) string {
+	switch provider {
+	case "eks":
+		return "sts.amazonaws.com"
+	case "gke":
+		return "//iam.googleapis.com/" + config.GCPWorkloadIdentityProvider
+	case "aks":
+		return "api://AzureADTokenExchange"
+	}
+	return ""
+}
+
+// oidcProblems returns the missing settings to mint the credentials of the provider from the OIDC token, in place of the static keys checked by checkProviderCredentials
+func oidcProblems(config *RunConfig, provider string) (problems []string) {
+	if config.OIDCTokenFile == "" && (config.OIDCRequestURL == "" || config.OIDCRequestToken == "") {
+		problems = append(problems, "OIDC_TOKEN_FILE is not set, and neither is the ID token of GitHub Actions (ACTIONS_ID_TOKEN_REQUEST_URL and ACTIONS_ID_TOKEN_REQUEST_TOKEN, "+
+			"with the id-token: write permission); it is required with CLOUD_CREDENTIAL_SOURCE=oidc")
+	}
+	required := func(env, value string) {
+		if value == "" {
+			problems = append(problems, fmt.Sprintf("%s is not set; it is required to mint the %s credentials with CLOUD_CREDENTIAL_SOURCE=oidc", env, provider))
+		}
+	}
+	if cli := oidcCLI[provider]; cli != "" {
+		if _, err := exec.LookPath(cli); err != nil {
+			problems = append(problems, fmt.Sprintf("%s is not installed or not in PATH; it is required to mint the %s credentials with CLOUD_CREDENTIAL_SOURCE=oidc", cli, provider))
+		}
+	}
+	switch provider {
+	case "eks":
+		required("AWS_ROLE_ARN", config.AWSRoleARN)
+		required("AWS_IAM_USER", config.AWSIAMUser)
+	case "gke":
+		required("GCP_WORKLOAD_IDENTITY_PROVIDER", config.GCPWorkloadIdentityProvider)
+		required("GCP_SERVICE_ACCOUNT", config.GCPServiceAccount)
+	case "aks":
+		required("AKS_CLIENT_ID", os.Getenv("AKS_CLIENT_ID"))
+		required("AKS_SUBSCRIPTION_ID", os.Getenv("AKS_SUBSCRIPTION_ID"))
+		required("AKS_TENANT_ID", config.AKSTenantID)
+	}
+	return
+}
+
+// oidcToken returns the OIDC token of the CI for the audience: the content of OIDC_TOKEN_FILE, or the ID token of the GitHub Actions job
+func oidcToken(config *RunConfig, audience string) (string, error) {
+	if config.OIDCTokenFile != "" {
+		content, err := os.ReadFile(config.OIDCTokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the OIDC token: %w", err)
+		}
+		return strings.TrimSpace(string(content)), nil
+	}
+
+	requestURL, err := url.Parse(config.OIDCRequestURL)
+	if err != nil {
+		return "", fmt.Errorf("ACTIONS_ID_TOKEN_REQUEST_URL is not valid: %w", err)
+	}
+	query := requestURL.Query()
+	query.Set("audience", audience)
+	requestURL.RawQuery = query.Encode()
+	request, err := http.NewRequest(http.MethodGet, requestURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Authorization", "Bearer "+config.OIDCRequestToken)
+	response, err := (&http.Client{Timeout: 30 * time.Second}).Do(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to request the OIDC token: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to request the OIDC token: %s", response.Status)
+	}
+	var token struct {
+		Value string `json:"value"`
+	}
+	if err = json.NewDecoder(response.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode the OIDC token: %w", err)
+	}
+	if token.Value == "" {
+		return "", errors.New("the OIDC token is empty")
+	}
+	return token.Value, nil
+}
+
+// writeSecretFile writes a secret to a file of the directory only readable by the user, for the CLIs reading it from a file
+func writeSecretFile(dir, name, secret string) (string, error) {
+	path := filepath.Join(dir, name)
+	return path, os.WriteFile(path, []byte(secret), 0o600)
+}
+
+// mintedCredential is a credential of a provider minted from the OIDC token: the env vars of the static credential it replaces,
+// and its revocation, done with a new OIDC token since the one it was minted with may have expired
+type mintedCredential struct {
+	env    map[string]string
+	revoke func(token string) error
+}
+
+// parseAWSAccessKey returns the ID and the secret of the access key created by aws iam create-access-key
+func parseAWSAccessKey(output string) (string, string, error) {
+	var key struct {
+		AccessKey struct {
+			AccessKeyID     string `json:"AccessKeyId"`
+			SecretAccessKey string `json:"SecretAccessKey"`
+		} `json:"AccessKey"`
+	}
+	if err := json.Unmarshal([]byte(output), &key); err != nil {
+		return "", "", fmt.Errorf("failed to decode the access key: %w", err)
+	}
+	if key.AccessKey.AccessKeyID == "" || key.AccessKey.SecretAccessKey == "" {
+		return "", "", errors.New("the access key is empty")
+	}
+	return key.AccessKey.AccessKeyID, key.AccessKey.SecretAccessKey, nil
+}
+
+// mintedKey is a key of the dedicated identity, listed to delete the ones left by the runs which could not revoke them
+type mintedKey struct {
+	ID      string
+	Created time.Time
+}
+
+// expiredKeyIDs returns the IDs of the keys created more than mintedSecretTTL before now
+func expiredKeyIDs(keys []mintedKey, now time.Time) (ids []string) {
+	for _, key := range keys {
+		if now.Sub(key.Created) > mintedSecretTTL {
+			ids = append(ids, key.ID)
+		}
+	}
+	return
+}
+
+// parseAWSAccessKeys returns the access keys listed by aws iam list-access-keys
+func parseAWSAccessKeys(output string) ([]mintedKey, error) {
+	var list struct {
+		AccessKeyMetadata []struct {
+			AccessKeyID string    `json:"AccessKeyId"`
+			CreateDate  time.Time `json:"CreateDate"`
+		} `json:"AccessKeyMetadata"`
+	}
+	if err := json.Unmarshal([]byte(output), &list); err != nil {
+		return nil, fmt.Errorf("failed to decode the access keys: %w", err)
+	}
+	keys := []mintedKey{}
+	for _, key := range list.AccessKeyMetadata {
+		keys = append(keys, mintedKey{ID: key.AccessKeyID, Created: key.CreateDate})
+	}
+	return keys, nil
+}
+
+// parseGCPKeys returns the user managed keys listed by gcloud iam service-accounts keys list, the ID being the last segment of their name
+func parseGCPKeys(output string) ([]mintedKey, error) {
+	var list []struct {
+		Name           string    `json:"name"`
+		ValidAfterTime time.Time `json:"validAfterTime"`
+	}
+	if err := json.Unmarshal([]byte(output), &list); err != nil {
+		return nil, fmt.Errorf("failed to decode the service account keys: %w", err)
+	}
+	keys := []mintedKey{}
+	for _, key := range list {
+		keys = append(keys, mintedKey{ID: key.Name[strings.LastIndex(key.Name, "/")+1:], Created: key.ValidAfterTime})
+	}
+	return keys, nil
+}
+
+/*
+deleteExpiredKeys deletes the keys of the dedicated identity older than mintedSecretTTL, left by the runs killed before revoking them:
+the Rancher cloud credentials only hold static keys (no AWS session token, a GCP service account key), which do not expire by themselves.
+  - @param provider Provider of the keys, for e.g. eks
+  - @param keys Keys of the identity
+  - @param remove Function deleting a key by its ID
+  - @returns The keys left, or an error if one can not be deleted
+*/
+func deleteExpiredKeys(provider string, keys []mintedKey, remove func(id string) error) ([]mintedKey, error) {
+	expired := expiredKeyIDs(keys, time.Now())
+	for _, id := range expired {
+		if err := remove(id); err != nil {
+			return nil, fmt.Errorf("failed to delete the expired %s key %s: %w", provider, id, err)
+		}
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Deleted the expired %s key %s, left by an earlier run", provider, id))
+	}
+	left := []mintedKey{}
+	for _, key := range keys {
+		if !slices.Contains(expired, key.ID) {
+			left = append(left, key)
+		}
+	}
+	return left, nil
+}
+
+// mintAWSCredential creates an access key of AWS_IAM_USER with the role assumed with the OIDC token (AWS_ROLE_ARN); it is deleted once revoked,
+// or by the next run once expired. A static key is minted since the Amazon cloud credentials of Rancher can not hold the session token of the role
+func mintAWSCredential(config *RunConfig, token, dir string) (*mintedCredential, error) {
+	awsCLI := func(token string) (*extcli.CLI, error) {
+		tokenFile, err := writeSecretFile(dir, "aws-token", token)
+		if err != nil {
+			return nil, err
+		}
+		// the CLI assumes the role with the token file, the static keys of the env are left out
+		return extcli.AWS.WithEnv("AWS_ROLE_ARN="+config.AWSRoleARN, "AWS_WEB_IDENTITY_TOKEN_FILE="+tokenFile,
+			"AWS_ROLE_SESSION_NAME=hosted-providers-e2e-"+config.RunID, "AWS_ACCESS_KEY_ID=", "AWS_SECRET_ACCESS_KEY=", "AWS_SESSION_TOKEN="), nil
+	}
+
+	cli, err := awsCLI(token)
+	if err != nil {
+		return nil, err
+	}
+	out, err := cli.Output("iam", "list-access-keys", "--user-name", config.AWSIAMUser, "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	keys, err := parseAWSAccessKeys(out)
+	if err != nil {
+		return nil, err
+	}
+	if keys, err = deleteExpiredKeys("eks", keys, func(id string) error {
+		_, err := cli.Run("iam", "delete-access-key", "--user-name", config.AWSIAMUser, "--access-key-id", id)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if len(keys) >= maxAWSAccessKeys {
+		return nil, fmt.Errorf("the IAM user %s already has %d access keys, used by concurrent runs or left by killed ones: "+
+			"the keys older than %s are deleted by the next run, delete the others with aws iam delete-access-key if they are unused", config.AWSIAMUser, len(keys), mintedSecretTTL)
+	}
+	out, err = cli.Sensitive().Output("iam", "create-access-key", "--user-name", config.AWSIAMUser, "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	keyID, secret, err := parseAWSAccessKey(out)
+	if err != nil {
+		return nil, err
+	}
+	return &mintedCredential{
+		env: map[string]string{"AWS_ACCESS_KEY_ID": keyID, "AWS_SECRET_ACCESS_KEY": secret},
+		revoke: func(token string) error {
+			cli, err := awsCLI(token)
+			if err != nil {
+				return err
+			}
+			_, err = cli.Run("iam", "delete-access-key", "--user-name", config.AWSIAMUser, "--access-key-id", keyID)
+			return err
+		},
+	}, nil
+}
+
+// parseGCPKeyID returns the ID of the service account key created by gcloud iam service-accounts keys create
+func parseGCPKeyID(key []byte) (string, error) {
+	var serviceAccount struct {
+		Type         string `json:"type"`
+		PrivateKeyID string `json:"private_key_id"`
+	}
+	if err := json.Unmarshal(key, &serviceAccount); err != nil {
+		return "", fmt.Errorf("failed to decode the service account key: %w", err)
+	}
+	if serviceAccount.Type != "service_account" || serviceAccount.PrivateKeyID == "" {
+		return "", errors.New("the service account key has no ID")
+	}
+	return serviceAccount.PrivateKeyID, nil
+}
+
+// mintGCPCredential creates a key of GCP_SERVICE_ACCOUNT, impersonated through the workload identity provider with the OIDC token; it is deleted once revoked,
+// or by the next run once expired. A key is minted since the Google cloud credentials of Rancher can not hold an access token
+func mintGCPCredential(config *RunConfig, token, dir string) (*mintedCredential, error) {
+	gcloudCLI := func(token string) (*extcli.CLI, error) {
+		tokenFile, err := writeSecretFile(dir, "gcp-token", token)
+		if err != nil {
+			return nil, err
+		}
+		credentialConfig := filepath.Join(dir, "gcp-credential-config.json")
+		if _, err = extcli.Gcloud.Run("iam", "workload-identity-pools", "create-cred-config", config.GCPWorkloadIdentityProvider,
+			"--service-account="+config.GCPServiceAccount, "--credential-source-file="+tokenFile, "--output-file="+credentialConfig); err != nil {
+			return nil, err
+		}
+		// the account of the gcloud config is left untouched
+		return extcli.Gcloud.WithEnv("CLOUDSDK_AUTH_CREDENTIAL_FILE_OVERRIDE=" + credentialConfig), nil
+	}
+
+	cli, err := gcloudCLI(token)
+	if err != nil {
+		return nil, err
+	}
+	out, err := cli.Output("iam", "service-accounts", "keys", "list", "--iam-account="+config.GCPServiceAccount, "--managed-by=user", "--format=json")
+	if err != nil {
+		return nil, err
+	}
+	keys, err := parseGCPKeys(out)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = deleteExpiredKeys("gke", keys, func(id string) error {
+		_, err := cli.Run("iam", "service-accounts", "keys", "delete", id, "--iam-account="+config.GCPServiceAccount, "--quiet")
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	keyFile := filepath.Join(dir, "gcp-key.json")
+	if _, err = cli.Run("iam", "service-accounts", "keys", "create", keyFile, "--iam-account="+config.GCPServiceAccount); err != nil {
+		return nil, err
+	}
+	key, err := os.ReadFile(keyFile)
+	os.Remove(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	keyID, err := parseGCPKeyID(key)
+	if err != nil {
+		return nil, err
+	}
+	return &mintedCredential{
+		env: map[string]string{"GCP_CREDENTIALS": string(key)},
+		revoke: func(token string) error {
+			cli, err := gcloudCLI(token)
+			if err != nil {
+				return err
+			}
+			_, err = cli.Run("iam", "service-accounts", "keys", "delete", keyID, "--iam-account="+config.GCPServiceAccount, "--quiet")
+			return err
+		},
+	}, nil
+}
+
+// parseAzureSecret returns the client secret created by az ad app credential reset
+func parseAzureSecret(output string) (string, error) {
+	var credential struct {
+		Password string `json:"password"`
+	}
+	if err := json.Unmarshal([]byte(output), &credential); err != nil {
+		return "", fmt.Errorf("failed to decode the client secret: %w", err)
+	}
+	if credential.Password == "" {
+		return "", errors.New("the client secret is empty")
+	}
+	return credential.Password, nil
+}
+
+// mintAzureCredential adds a client secret to the AKS_CLIENT_ID application, logged in with its federated credential and the OIDC token;
+// the secret expires after mintedSecretTTL, and is deleted once revoked
+func mintAzureCredential(config *RunConfig, token, dir string) (*mintedCredential, error) {
+	clientID := os.Getenv("AKS_CLIENT_ID")
+	secretName := "hosted-providers-e2e-" + config.RunID
+	azCLI := func(token string) (*extcli.CLI, error) {
+		// the login is kept in a config dir of its own, the one of the user is left untouched
+		cli := extcli.Az.WithEnv("AZURE_CONFIG_DIR=" + filepath.Join(dir, "azure"))
+		_, err := cli.Sensitive().Run("login", "--service-principal", "--username", clientID, "--tenant", config.AKSTenantID, "--federated-token", token, "--allow-no-subscriptions")
+		return cli, err
+	}
+
+	cli, err := azCLI(token)
+	if err != nil {
+		return nil, err
+	}
+	out, err := cli.Sensitive().Output("ad", "app", "credential", "reset", "--id", clientID, "--append", "--display-name", secretName,
+		"--end-date", time.Now().Add(mintedSecretTTL).UTC().Format(time.RFC3339), "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	secret, err := parseAzureSecret(out)
+	if err != nil {
+		return nil, err
+	}
+	return &mintedCredential{
+		env: map[string]string{"AKS_CLIENT_SECRET": secret},
+		revoke: func(token string) error {
+			cli, err := azCLI(token)
+			if err != nil {
+				return err
+			}
+			keyIDs, err := cli.Output("ad", "app", "credential", "list", "--id", clientID, "--query", fmt.Sprintf("[?displayName=='%s'].keyId", secretName), "--output", "tsv")
+			if err != nil {
+				return err
+			}
+			for _, keyID := range strings.Fields(keyIDs) {
+				if _, err = cli.Run("ad", "app", "credential", "delete", "--id", clientID, "--key-id", keyID); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}, nil
+}
+
+// mintCloudCredential mints the credential of the provider from the OIDC token, see AcquireCloudCredentials
+func mintCloudCredential(config *RunConfig, provider, token, dir string) (*mintedCredential, error) {
+	switch provider {
+	case "eks":
+		return mintAWSCredential(config, token, dir)
+	case "gke":
+		return mintGCPCredential(config, token, dir)
+	case "aks":
+		return mintAzureCredential(config, token, dir)
+	}
+	return nil, fmt.Errorf("unsupported provider %q", provider)
+}
+
+/*
+AcquireCloudCredentials mints the credentials of the providers with CLOUD_CREDENTIAL_SOURCE=oidc, instead of the long-lived static keys: the OIDC token
+of the CI (the ID token of the GitHub Actions job, or OIDC_TOKEN_FILE) is exchanged with the cloud provider, through a role, a workload identity provider
+or a federated credential, for a key of a dedicated identity (an access key of AWS_IAM_USER, a key of GCP_SERVICE_ACCOUNT, or a client secret of the
+AKS_CLIENT_ID application). The keys are set as the static credentials of the env, written to the cattle config by the suite setup, from which the Rancher
+cloud credentials are created, for e.g. with CreateCloudCredentials; they are revoked by RevokeCloudCredentials once all the parallel processes are done. The Rancher cloud
+credentials can not hold session tokens or access tokens, so the AWS and GCP keys do not expire by themselves: the ones older than mintedSecretTTL, left by
+the runs killed before revoking them, are deleted before minting new ones.
+It is called by the SynchronizedBeforeSuite of the suites, on the first parallel process; nothing is done with the static keys.
+  - @param providers Providers of the suite
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func AcquireCloudCredentials(providers ...string) {
+	if !IsOIDCCredentials() {
+		return
+	}
+	dir, err := os.MkdirTemp("", "hosted-providers-credentials-")
+	Expect(err).To(BeNil())
+	// added first to be removed once all the credentials are revoked
+	credentialCleanups = append(credentialCleanups, func() { os.RemoveAll(dir) })
+
+	for _, provider := range providers {
+		ginkgo.By(fmt.Sprintf("Minting the %s credentials with the OIDC token", provider), func() {
+			audience := oidcAudience(runConfig, provider)
+			token, err := oidcToken(runConfig, audience)
+			Expect(err).To(BeNil())
+			minted, err := mintCloudCredential(runConfig, provider, token, dir)
+			Expect(err).To(BeNil(), "Failed to mint the %s credentials", provider)
+			for key, value := range minted.env {
+				Expect(os.Setenv(key, value)).To(Succeed())
+			}
+
+			credentialCleanups = append(credentialCleanups, func() {
+				token, err := oidcToken(runConfig, audience)
+				if err == nil {
+					err = minted.revoke(token)
+				}
+				if err != nil {
+					ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to revoke the %s credentials minted for the suite, revoke them manually: %v", provider, err))
+					return
+				}
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Revoked the %s credentials minted for the suite", provider))
+			})
+		})
+	}
+}
+
+// credentialCleanups revokes the credentials minted by AcquireCloudCredentials and removes their files, see RevokeCloudCredentials
+var credentialCleanups []func()
+
+// RevokeCloudCredentials revokes the credentials minted by AcquireCloudCredentials, in the reverse order, on the first parallel process;
+// it is called by CommonSynchronizedAfterSuite once the leaked clusters are deleted, and nothing is done with the static keys
+func RevokeCloudCredentials() {
+	for i := len(credentialCleanups) - 1; i >= 0; i-- {
+		credentialCleanups[i]()
+	}
+	credentialCleanups = nil
+}
+
+// exportMintedCredentials sets the static credentials of the env to the ones minted by AcquireCloudCredentials on the first parallel process,
+// read from the cattle config file, so that the provider CLIs of every process use them; nothing is done with the static keys
+func exportMintedCredentials(providers ...string) {
+	if !IsOIDCCredentials() {
+		return
+	}
+	for _, provider := range providers {
+		switch provider {
+		case "eks":
+			credentialConfig := new(cloudcredentials.AmazonEC2CredentialConfig)
+			config.LoadConfig(cloudcredentials.AmazonEC2CredentialConfigurationFileKey, credentialConfig)
+			os.Setenv("AWS_ACCESS_KEY_ID", credentialConfig.AccessKey)
+			os.Setenv("AWS_SECRET_ACCESS_KEY", credentialConfig.SecretKey)
+		case "gke":
+			credentialConfig := new(cloudcredentials.GoogleCredentialConfig)
+			config.LoadConfig(cloudcredentials.GoogleCredentialConfigurationFileKey, credentialConfig)
+			os.Setenv("GCP_CREDENTIALS", credentialConfig.AuthEncodedJSON)
+		case "aks":
+			credentialConfig := new(cloudcredentials.AzureCredentialConfig)
+			config.LoadConfig(cloudcredentials.AzureCredentialConfigurationFileKey, credentialConfig)
+			os.Setenv("AKS_CLIENT_SECRET", credentialConfig.ClientSecret)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package helpers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestOIDCToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer request-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"value":"token-for-` + r.URL.Query().Get("audience") + `"}`))
+	}))
+	defer server.Close()
+
+	config := &RunConfig{OIDCRequestURL: server.URL + "/token?api-version=2.0", OIDCRequestToken: "request-token"}
+	if token, err := oidcToken(config, "sts.amazonaws.com"); err != nil || token != "token-for-sts.amazonaws.com" {
+		t.Errorf("got %q, %v", token, err)
+	}
+	config.OIDCRequestToken = "expired"
+	if _, err := oidcToken(config, "sts.amazonaws.com"); err == nil {
+		t.Error("expected an error with an unauthorized request")
+	}
+
+	config.OIDCTokenFile = filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(config.OIDCTokenFile, []byte("file-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if token, err := oidcToken(config, "sts.amazonaws.com"); err != nil || token != "file-token" {
+		t.Errorf("got %q, %v", token, err)
+	}
+}
+
+func TestOIDCProblems(t *testing.T) {
+	t.Setenv("PATH", "")
+	t.Setenv("AKS_CLIENT_ID", "")
+	t.Setenv("AKS_SUBSCRIPTION_ID", "")
+	for _, tc := range []struct {
+		provider string
+		config   RunConfig
+		problems int
+	}{
+		{provider: "eks", config: RunConfig{OIDCTokenFile: "/var/run/token", AWSRoleARN: "arn:aws:iam::123456789012:role/ci", AWSIAMUser: "ci"}, problems: 1},
+		{provider: "eks", config: RunConfig{OIDCRequestURL: "https://token.actions.githubusercontent.com"}, problems: 4},
+		{provider: "gke", config: RunConfig{OIDCTokenFile: "/var/run/token", GCPServiceAccount: "ci@project.iam.gserviceaccount.com"}, problems: 2},
+		{provider: "aks", config: RunConfig{OIDCTokenFile: "/var/run/token", AKSTenantID: "tenant"}, problems: 3},
+	} {
+		if problems := oidcProblems(&tc.config, tc.provider); len(problems) != tc.problems {
+			t.Errorf("%s: got %q, want %d problems", tc.provider, problems, tc.problems)
+		}
+	}
+}
+
+func TestParseMintedCredentials(t *testing.T) {
+	if keyID, secret, err := parseAWSAccessKey(`{"AccessKey": {"UserName": "ci", "AccessKeyId": "AKIAEXAMPLE", "Status": "Active", "SecretAccessKey": "secret"}}`); err != nil || keyID != "AKIAEXAMPLE" || secret != "secret" {
+		t.Errorf("got %q, %q, %v", keyID, secret, err)
+	}
+	if _, _, err := parseAWSAccessKey(`{}`); err == nil {
+		t.Error("expected an error with an empty access key")
+	}
+	if keyID, err := parseGCPKeyID([]byte(`{"type": "service_account", "private_key_id": "abc123", "private_key": "key"}`)); err != nil || keyID != "abc123" {
+		t.Errorf("got %q, %v", keyID, err)
+	}
+	if _, err := parseGCPKeyID([]byte(`{"type": "external_account"}`)); err == nil {
+		t.Error("expected an error with a key of another type")
+	}
+	if secret, err := parseAzureSecret(`{"appId": "app", "password": "secret", "tenant": "tenant"}`); err != nil || secret != "secret" {
+		t.Errorf("got %q, %v", secret, err)
+	}
+	if _, err := parseAzureSecret(`not json`); err == nil {
+		t.Error("expected an error with an invalid output")
+	}
+}
+
+func TestExpiredKeys(t *testing.T) {
+	awsKeys, err := parseAWSAccessKeys(`{"AccessKeyMetadata": [
+		{"UserName": "ci", "AccessKeyId": "AKIAOLD", "Status": "Active", "CreateDate": "2026-10-14T08:00:00+00:00"},
+		{"UserName": "ci", "AccessKeyId": "AKIANEW", "Status": "Active", "CreateDate": "2026-10-16T07:00:00+00:00"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcpKeys, err := parseGCPKeys(`[
+		{"name": "projects/p/serviceAccounts/ci@p.iam.gserviceaccount.com/keys/old", "keyType": "USER_MANAGED", "validAfterTime": "2026-10-15T07:59:59Z"},
+		{"name": "projects/p/serviceAccounts/ci@p.iam.gserviceaccount.com/keys/new", "keyType": "USER_MANAGED", "validAfterTime": "2026-10-15T09:00:00Z"}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	if ids := expiredKeyIDs(awsKeys, now); !slices.Equal(ids, []string{"AKIAOLD"}) {
+		t.Errorf("got expired AWS keys %q", ids)
+	}
+	if ids := expiredKeyIDs(gcpKeys, now); !slices.Equal(ids, []string{"old"}) {
+		t.Errorf("got expired GCP keys %q", ids)
+	}
+	if _, err = parseGCPKeys(`{}`); err == nil {
+		t.Error("expected an error with an invalid output")
+	}
+}
+
+func TestRevokeCloudCredentials(t *testing.T) {
+	var revoked []string
+	credentialCleanups = []func(){
+		func() { revoked = append(revoked, "dir") },
+		func() { revoked = append(revoked, "eks") },
+		func() { revoked = append(revoked, "gke") },
+	}
+	RevokeCloudCredentials()
+	if !slices.Equal(revoked, []string{"gke", "eks", "dir"}) {
+		t.Errorf("got revoked %q", revoked)
+	}
+	RevokeCloudCredentials()
+	if len(revoked) != 3 {
+		t.Errorf("got credentials revoked twice: %q", revoked)
+	}
+}
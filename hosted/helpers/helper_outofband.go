@@ -0,0 +1,77 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// notFoundErrorKeywords are the words of the errors returned by the cloud providers for a cluster that does not exist anymore,
+// for e.g. "ResourceNotFoundException: No cluster found" on AWS, "Error 404: Not found" on GCP or "ResourceGroupNotFound" on Azure
+var notFoundErrorKeywords = []string{"not found", "notfound", "could not be found", "does not exist"}
+
+// isClusterNotFoundError returns true if the message is a cloud error about a cluster that does not exist
+func isClusterNotFoundError(message string) bool {
+	message = strings.ToLower(message)
+	for _, keyword := range notFoundErrorKeywords {
+		if strings.Contains(message, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Check that Rancher reports a cluster deleted directly on the cloud provider, out of band: the cluster must be in error,
+with the not found error of the cloud provider as TransitioningMessage, once the operator refreshed it.
+  - @param client Rancher client
+  - @param cluster Downstream cluster deleted on the cloud provider
+  - @returns The up-to-date cluster; the function will fail through Ginkgo in case of issue
+*/
+func CheckClusterDeletedOutOfBand(client *rancher.Client, cluster *management.Cluster) *management.Cluster {
+	// the cluster stays in error for the rest of the spec
+	AllowClusterErrors()
+	ginkgo.By(fmt.Sprintf("Waiting for cluster %s to report its deletion on the cloud provider", cluster.Name), func() {
+		start := time.Now()
+		EventuallyWithBackoff(func() (string, error) {
+			var err error
+			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			if err != nil {
+				return "", err
+			}
+			ObserveCluster(cluster)
+			if cluster.Transitioning != "error" {
+				return "", nil
+			}
+			return cluster.TransitioningMessage, nil
+		}, tools.SetTimeout(30*time.Minute), 15*time.Second).Should(Satisfy(isClusterNotFoundError), fmt.Sprintf("Cluster %s did not report its deletion", cluster.Name))
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Cluster %s reported its deletion after %s: %s", cluster.Name, time.Since(start).Round(time.Second), cluster.TransitioningMessage))
+	})
+	return cluster
+}
+
+/*
+Delete from Rancher a cluster already deleted on the cloud provider, and check that the deletion does not hang on the missing cloud resources
+  - @param client Rancher client
+  - @param cluster Downstream cluster deleted on the cloud provider
+  - @param deleteCluster Provider function deleting the cluster from Rancher, for e.g. DeleteEKSHostCluster
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func DeleteClusterDeletedOutOfBand(client *rancher.Client, cluster *management.Cluster, deleteCluster func(*management.Cluster, *rancher.Client) error) {
+	ginkgo.By(fmt.Sprintf("Deleting cluster %s from Rancher", cluster.Name), func() {
+		start := time.Now()
+		Expect(deleteCluster(cluster, client)).To(Succeed())
+		// there is nothing left to delete on the cloud provider, the finalizers of the cluster must be done in a few minutes
+		EventuallyWithBackoff(func() bool {
+			_, err := client.Management.Cluster.ByID(cluster.ID)
+			return err != nil && strings.Contains(err.Error(), "not found")
+		}, tools.SetTimeout(10*time.Minute), 5*time.Second).Should(BeTrue(), fmt.Sprintf("The deletion of cluster %s hangs", cluster.Name))
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Cluster %s was removed from Rancher after %s", cluster.Name, time.Since(start).Round(time.Second)))
+	})
+}
@@ -0,0 +1,20 @@
+package helpers
+
+import "testing"
+
+func TestIsClusterNotFoundError(t *testing.T) {
+	for _, tc := range []struct {
+		message string
+		want    bool
+	}{
+		{message: "", want: false},
+		{message: "waiting for the cluster agent to connect", want: false},
+		{message: "ResourceNotFoundException: No cluster found for name: auto-eks-hp-ci-abcde.", want: true},
+		{message: "googleapi: Error 404: Not found: projects/p/zones/z/clusters/auto-gke-hp-ci-abcde.", want: true},
+		{message: "Code=\"ResourceGroupNotFound\" Message=\"Resource group 'auto-aks-hp-ci-abcde' could not be found.\"", want: true},
+	} {
+		if got := isClusterNotFoundError(tc.message); got != tc.want {
+			t.Errorf("isClusterNotFoundError(%q) = %t, want %t", tc.message, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	minorVersionRegex = regexp.MustCompile(`^\d+\.\d+$`)
+	uuidRegex         = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// providerCLI maps the provider to the CLI tools used by the helpers of its import and sync tests
+var providerCLI = map[string][]string{
+	"eks": {"eksctl", "aws"},
+	"gke": {"gcloud"},
+	"aks": {"az"},
+}
+
+// MultiProviders are the providers whose clusters are provisioned side by side by the multi-provider suites
+var MultiProviders = []string{"eks", "gke", "aks"}
+
+// PreflightChecks validates the test environment before any call is made to Rancher or the cloud provider;
+// it collects every problem found and fails once with all of them, instead of failing later with an opaque error deep in the helpers.
+func PreflightChecks() {
+	preflightChecks(SuiteCommon, Provider)
+}
+
+// MultiProviderPreflightChecks validates the test environment of the multi-provider suites, which need the settings of all the MultiProviders
+func MultiProviderPreflightChecks() {
+	preflightChecks(SuiteMultiProvider, MultiProviders...)
+}
+
+// preflightChecks fails with the problems of PreflightProblems for the current run config
+func preflightChecks(suite Suite, providers ...string) {
+	ginkgo.GinkgoLogr.Info("Running preflight checks ...")
+
+	problems := PreflightProblems(runConfig, suite, providers...)
+	Expect(problems).To(BeEmpty(), "Preflight checks failed:\n - "+strings.Join(problems, "\n - "))
+}
+
+/*
+PreflightProblems returns the problems found by the preflight checks in the run config for the suite, and in the cattle config, credentials
+and CLI tools of the providers; unlike PreflightChecks, it does not need a running spec, for e.g. to validate a run before starting ginkgo.
+  - @param config Run config, for e.g. LoadRunConfig once the env is set
+  - @param suite Suite the run config is validated for
+  - @param providers Providers of the suite, for e.g. MultiProviders
+  - @returns The problems found, none if the environment is valid
+*/
+func PreflightProblems(config *RunConfig, suite Suite, providers ...string) (problems []string) {
+	problems = append(problems, config.Validate(suite)...)
+	problems = append(problems, checkCattleConfig(config, providers...)...)
+	problems = append(problems, checkArtifactsBucket(config.ArtifactsBucket)...)
+	for _, provider := range providers {
+		if config.CloudCredentialSource == cloudCredentialSourceOIDC {
+			problems = append(problems, oidcProblems(config, provider)...)
+		} else if credentialProblems := checkProviderCredentials(provider); len(credentialProblems) > 0 {
+			problems = append(problems, credentialProblems...)
+		} else {
+			// the minted credentials are only checked once minted, by the calls of the suite
+			problems = append(problems, checkProviderAuth(config, provider)...)
+		}
+		problems = append(problems, checkProviderCLI(provider, config.IsImport, config.DryRun)...)
+	}
+	if config.ProvisioningBackend == provisioningBackendTerraform {
+		if _, err := exec.LookPath(extcli.Terraform.Name()); err != nil {
+			problems = append(problems, "terraform is not installed or not in PATH; it is required with PROVISIONING_BACKEND=terraform")
+		}
+	}
+	return
+}
+
+// checkCattleConfig validates that CATTLE_TEST_CONFIG points to a readable config containing the sections needed by the providers
+func checkCattleConfig(config *RunConfig, providers ...string) (problems []string) {
+	configPath := config.CattleConfigPath
+	if configPath == "" {
+		return []string{"CATTLE_TEST_CONFIG is not set; export the path of the config file, for e.g. cattle-config-provisioning.yaml"}
+	}
+	content, err := os.ReadFile(configPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		// the config is generated at the start of the suite, see GenerateCattleConfig
+		content, err = yaml.Marshal(NewCattleConfig())
+	}
+	if err != nil {
+		return []string{fmt.Sprintf("CATTLE_TEST_CONFIG %q can not be read: %v", configPath, err)}
+	}
+	cattleConfig := map[string]interface{}{}
+	if err = yaml.Unmarshal(content, &cattleConfig); err != nil {
+		return []string{fmt.Sprintf("CATTLE_TEST_CONFIG %q is not a valid YAML file: %v", configPath, err)}
+	}
+
+	if _, ok := cattleConfig["rancher"]; !ok {
+		problems = append(problems, fmt.Sprintf("CATTLE_TEST_CONFIG %q has no rancher section", configPath))
+	}
+	for _, provider := range providers {
+		if _, ok := providerCLI[provider]; !ok {
+			continue
+		}
+		section := provider + "ClusterConfig"
+		if clusterConfig, ok := cattleConfig[section].(map[string]interface{}); !ok {
+			problems = append(problems, fmt.Sprintf("CATTLE_TEST_CONFIG %q has no %s section", configPath, section))
+		} else if !config.IsImport {
+			// node pools are only needed to provision a cluster, imported clusters are created with the provider CLI
+			pools := "nodePools"
+			if provider == "eks" {
+				pools = "nodeGroups"
+			}
+			if nodePools, ok := clusterConfig[pools].([]interface{}); !ok || len(nodePools) == 0 {
+				problems = append(problems, fmt.Sprintf("CATTLE_TEST_CONFIG %q has no %s.%s; at least one is needed to provision a cluster", configPath, section, pools))
+			}
+		}
+	}
+	return
+}
+
+// checkProviderCredentials validates that the provider credentials are set and well-formed
+func checkProviderCredentials(provider string) (problems []string) {
+	missing := func(envs ...string) {
+		for _, env := range envs {
+			if os.Getenv(env) == "" {
+				problems = append(problems, fmt.Sprintf("%s is not set; it is required to run the %s tests", env, provider))
+			}
+		}
+	}
+
+	switch provider {
+	case "eks":
+		missing("AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY")
+	case "gke":
+		missing("GCP_CREDENTIALS")
+		if credentials := os.Getenv("GCP_CREDENTIALS"); credentials != "" {
+			serviceAccount := struct {
+				Type       string `json:"type"`
+				ProjectID  string `json:"project_id"`
+				PrivateKey string `json:"private_key"`
+			}{}
+			if err := json.Unmarshal([]byte(credentials), &serviceAccount); err != nil {
+				problems = append(problems, fmt.Sprintf("GCP_CREDENTIALS is not a valid service account JSON key: %v", err))
+			} else if serviceAccount.Type != "service_account" || serviceAccount.PrivateKey == "" {
+				problems = append(problems, "GCP_CREDENTIALS must be the JSON private key of a service account")
+			} else if projectID := GetGKEProjectID(); projectID != "" && serviceAccount.ProjectID != projectID {
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Preflight: service account belongs to project %s, but GKE_PROJECT_ID is %s", serviceAccount.ProjectID, projectID))
+			}
+		}
+	case "aks":
+		missing("AKS_CLIENT_ID", "AKS_CLIENT_SECRET", "AKS_SUBSCRIPTION_ID")
+		for _, env := range []string{"AKS_CLIENT_ID", "AKS_SUBSCRIPTION_ID"} {
+			if value := os.Getenv(env); value != "" && !uuidRegex.MatchString(value) {
+				problems = append(problems, fmt.Sprintf("%s is not a valid Azure ID; a UUID is expected", env))
+			}
+		}
+	}
+	return
+}
+
+// providerAuthTimeout is the timeout of the authenticated call checking the credentials of a provider
+const providerAuthTimeout = time.Minute
+
+/*
+checkProviderAuth validates that the provider accepts the credentials of the env with a cheap authenticated call, so that revoked or expired keys
+are reported before any cluster is created; the call is skipped if the CLI of the provider is missing, which checkProviderCLI reports.
+The calls do not change the config of the CLIs: the GCP key and the Azure login are kept in a temporary directory.
+  - @param config Run config, for e.g. AKSTenantID to log in to Azure
+  - @param provider Provider of the credentials
+  - @returns The problem found, none if the credentials are accepted
+*/
+func checkProviderAuth(config *RunConfig, provider string) (problems []string) {
+	cli := oidcCLI[provider]
+	if _, err := exec.LookPath(cli); err != nil {
+		return
+	}
+	dir, err := os.MkdirTemp("", "hosted-providers-preflight-")
+	if err != nil {
+		return []string{fmt.Sprintf("failed to create the directory to check the %s credentials: %v", provider, err)}
+	}
+	defer os.RemoveAll(dir)
+
+	switch provider {
+	case "eks":
+		if _, err = extcli.AWS.WithTimeout(providerAuthTimeout).Run("sts", "get-caller-identity", "--region", GetEKSRegion(), "--output", "json"); err != nil {
+			problems = append(problems, fmt.Sprintf("AWS rejects AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY (aws sts get-caller-identity failed): %v; "+
+				"check that the access key is active and was not deleted", err))
+		}
+	case "gke":
+		keyFile, err := writeSecretFile(dir, "gcp-key.json", os.Getenv("GCP_CREDENTIALS"))
+		if err == nil {
+			// the key is used instead of the account of the gcloud config, which is left untouched
+			_, err = extcli.Gcloud.WithEnv("CLOUDSDK_AUTH_CREDENTIAL_FILE_OVERRIDE="+keyFile).WithTimeout(providerAuthTimeout).Sensitive().Run("auth", "print-access-token")
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("Google Cloud rejects GCP_CREDENTIALS (gcloud auth print-access-token failed): %v; "+
+				"check that the service account key is enabled and was not deleted", err))
+		}
+	case "aks":
+		if config.AKSTenantID == "" {
+			ginkgo.GinkgoLogr.Info("Preflight: AKS_TENANT_ID is not set, the Azure credentials can not be checked with az login")
+			return
+		}
+		az := extcli.Az.WithEnv("AZURE_CONFIG_DIR=" + filepath.Join(dir, "azure")).WithTimeout(providerAuthTimeout)
+		_, err = az.Sensitive().Run("login", "--service-principal", "--username", os.Getenv("AKS_CLIENT_ID"), "--password", os.Getenv("AKS_CLIENT_SECRET"),
+			"--tenant", config.AKSTenantID, "--allow-no-subscriptions")
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("Azure rejects AKS_CLIENT_ID and AKS_CLIENT_SECRET in the tenant AKS_TENANT_ID (az login failed): %v; "+
+				"check that the client secret has not expired", err))
+		} else if _, err = az.Run("account", "show", "--subscription", os.Getenv("AKS_SUBSCRIPTION_ID"), "--output", "json"); err != nil {
+			problems = append(problems, fmt.Sprintf("the application AKS_CLIENT_ID has no access to the subscription AKS_SUBSCRIPTION_ID (az account show failed): %v; "+
+				"assign it a role on the subscription", err))
+		}
+	}
+	return
+}
+
+// checkProviderCLI validates that the CLI tools of the provider are present;
+// import tests can not run without them, and neither can DRY_RUN check the instance types (eksctl aside); other tests only need them for a few specs, so a warning is logged instead.
+func checkProviderCLI(provider string, isImport, dryRun bool) (problems []string) {
+	for _, cli := range providerCLI[provider] {
+		if _, err := exec.LookPath(cli); err != nil {
+			if isImport {
+				problems = append(problems, fmt.Sprintf("%s is not installed or not in PATH; it is required to run the %s import tests", cli, provider))
+			} else if dryRun && cli != "eksctl" {
+				problems = append(problems, fmt.Sprintf("%s is not installed or not in PATH; it is required to check the instance types of the %s clusters with DRY_RUN", cli, provider))
+			} else {
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Preflight: %s is not installed or not in PATH; specs using it will fail", cli))
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,55 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeCLI installs a script named after the CLI in a directory of its own, set as PATH
+func fakeCLI(t *testing.T, name, script string) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+":/bin:/usr/bin")
+}
+
+func TestCheckProviderAuth(t *testing.T) {
+	fakeCLI(t, "aws", `[ "$AWS_ACCESS_KEY_ID" = valid ] && exit 0; echo "An error occurred (InvalidClientTokenId) when calling the GetCallerIdentity operation" >&2; exit 254`)
+	t.Setenv("AWS_ACCESS_KEY_ID", "valid")
+	if problems := checkProviderAuth(&RunConfig{}, "eks"); len(problems) != 0 {
+		t.Errorf("got %q with valid credentials", problems)
+	}
+	t.Setenv("AWS_ACCESS_KEY_ID", "revoked")
+	if problems := checkProviderAuth(&RunConfig{}, "eks"); len(problems) != 1 || !strings.Contains(problems[0], "InvalidClientTokenId") {
+		t.Errorf("got %q with revoked credentials", problems)
+	}
+
+	// the key is passed to gcloud in a file, and the token printed is not reported
+	fakeCLI(t, "gcloud", `grep -q '"private_key"' "$CLOUDSDK_AUTH_CREDENTIAL_FILE_OVERRIDE" && echo ya29.token && exit 0; echo "ERROR: invalid_grant" >&2; exit 1`)
+	t.Setenv("GCP_CREDENTIALS", `{"type": "service_account", "private_key": "key"}`)
+	if problems := checkProviderAuth(&RunConfig{}, "gke"); len(problems) != 0 {
+		t.Errorf("got %q with valid credentials", problems)
+	}
+	t.Setenv("GCP_CREDENTIALS", `{"type": "service_account"}`)
+	if problems := checkProviderAuth(&RunConfig{}, "gke"); len(problems) != 1 || !strings.Contains(problems[0], "invalid_grant") {
+		t.Errorf("got %q with a deleted key", problems)
+	}
+
+	// Azure is only checked with AKS_TENANT_ID; az account show fails on a subscription the application has no access to
+	fakeCLI(t, "az", `[ "$1" = login ] && exit 0; echo "ERROR: Subscription 'sub' not found" >&2; exit 1`)
+	if problems := checkProviderAuth(&RunConfig{}, "aks"); len(problems) != 0 {
+		t.Errorf("got %q without AKS_TENANT_ID", problems)
+	}
+	if problems := checkProviderAuth(&RunConfig{AKSTenantID: "tenant"}, "aks"); len(problems) != 1 || !strings.Contains(problems[0], "AKS_SUBSCRIPTION_ID") {
+		t.Errorf("got %q without access to the subscription", problems)
+	}
+
+	// a missing CLI is reported by checkProviderCLI
+	t.Setenv("PATH", t.TempDir())
+	if problems := checkProviderAuth(&RunConfig{}, "eks"); len(problems) != 0 {
+		t.Errorf("got %q without the CLI", problems)
+	}
+}
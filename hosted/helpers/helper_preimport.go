@@ -0,0 +1,172 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+)
+
+// Workloads deployed on a cluster before it is imported into Rancher, from the manifests of the assets directory
+const (
+	preImportCRDManifest       = "../../helpers/assets/preimport-crd.yaml"
+	preImportWorkloadsManifest = "../../helpers/assets/preimport-workloads.yaml"
+	preImportCRD               = "widgets.e2e.hosted-providers.cattle.io"
+	preImportNamespace         = "hp-preimport"
+	preImportApp               = "hp-preimport-app"
+)
+
+// preImportObject is an object of the pre-import manifests, read with the dynamic client
+type preImportObject struct {
+	resource schema.GroupVersionResource
+	name     string
+}
+
+// preImportObjects are the objects of the pre-import manifests; the CRD is the only cluster-scoped one
+var preImportObjects = []preImportObject{
+	{resource: crdResource, name: preImportCRD},
+	{resource: appsv1.SchemeGroupVersion.WithResource("deployments"), name: preImportApp},
+	{resource: policyv1.SchemeGroupVersion.WithResource("poddisruptionbudgets"), name: preImportApp},
+	{resource: schema.GroupVersionResource{Group: "e2e.hosted-providers.cattle.io", Version: "v1", Resource: "widgets"}, name: "hp-preimport-widget"},
+}
+
+// preImportFingerprints returns the fingerprint of every object, for e.g. "Deployment/hp-preimport-app" => "uid=...,generation=1";
+// the UID changes if the object is recreated and the generation if its spec is modified, while the status updates are ignored.
+func preImportFingerprints(objects []*unstructured.Unstructured) map[string]string {
+	fingerprints := map[string]string{}
+	for _, object := range objects {
+		fingerprints[object.GetKind()+"/"+object.GetName()] = fmt.Sprintf("uid=%s,generation=%d", object.GetUID(), object.GetGeneration())
+	}
+	return fingerprints
+}
+
+// downstreamKubectl returns the kubectl CLI using the kubeconfig of a cluster created with the provider CLI, written on first use; see DownstreamKubeconfigPath
+func downstreamKubectl(clusterName string) *extcli.CLI {
+	kubeconfig, err := DownstreamKubeconfigPath(clusterName)
+	Expect(err).To(BeNil())
+	return extcli.Kubectl.WithEnv("KUBECONFIG=" + kubeconfig)
+}
+
+// getPreImportFingerprints returns the fingerprints of the pre-import objects of a cluster, read with the kubeconfig of the provider CLI
+func getPreImportFingerprints(clusterName string) (map[string]string, error) {
+	clients, err := ProviderDownstreamClients(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	var objects []*unstructured.Unstructured
+	for _, object := range preImportObjects {
+		var resource dynamic.ResourceInterface = clients.Dynamic.Resource(object.resource)
+		if object.resource != crdResource {
+			resource = clients.Dynamic.Resource(object.resource).Namespace(preImportNamespace)
+		}
+		found, err := resource.Get(context.Background(), object.name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, found)
+	}
+	return preImportFingerprints(objects), nil
+}
+
+/*
+Deploy workloads on a cluster created with the provider CLI, before it is imported into Rancher: a CRD and one of its custom resources,
+a deployment and its pod disruption budget; the cluster is reached with the kubeconfig written by the provider CLI, the manifests being applied
+with kubectl and the resources waited for with client-go.
+  - @param clusterName Name of the cluster
+  - @returns The fingerprints of the deployed resources, to be given to CheckPreImportWorkloads once the cluster is imported
+*/
+func DeployPreImportWorkloads(clusterName string) map[string]string {
+	kubectl := downstreamKubectl(clusterName)
+	clients, err := ProviderDownstreamClients(clusterName)
+	Expect(err).To(BeNil())
+
+	var fingerprints map[string]string
+	ginkgo.By(fmt.Sprintf("Deploying the pre-import workloads on cluster %s", clusterName), func() {
+		_, err := kubectl.Run("apply", "-f", preImportCRDManifest)
+		Expect(err).To(BeNil())
+		// the custom resource can only be created once the CRD is established
+		Expect(clients.WaitForCRD(context.Background(), preImportCRD, 2*time.Minute)).To(Succeed(), "CRD %s is not established", preImportCRD)
+		_, err = kubectl.Run("apply", "-f", preImportWorkloadsManifest)
+		Expect(err).To(BeNil())
+		Expect(clients.WaitForRollout(context.Background(), preImportNamespace, preImportApp, 5*time.Minute)).To(Succeed(), "Deployment %s is not rolled out", preImportApp)
+
+		fingerprints, err = getPreImportFingerprints(clusterName)
+		Expect(err).To(BeNil())
+		Expect(fingerprints).To(HaveLen(len(preImportObjects)))
+	})
+	return fingerprints
+}
+
+/*
+Check that the workloads deployed by DeployPreImportWorkloads are left untouched by the import of the cluster into Rancher,
+then that they can be managed through Rancher: the deployment is scaled through the Rancher proxy and its pod disruption budget must follow.
+  - @param client Rancher client
+  - @param cluster Imported cluster
+  - @param fingerprints Fingerprints returned by DeployPreImportWorkloads
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckPreImportWorkloads(client *rancher.Client, cluster *management.Cluster, fingerprints map[string]string) {
+	ginkgo.By(fmt.Sprintf("Checking the pre-import workloads are untouched on cluster %s", cluster.Name), func() {
+		current, err := getPreImportFingerprints(cluster.Name)
+		Expect(err).To(BeNil())
+		Expect(current).To(Equal(fingerprints), "The pre-import resources were recreated or modified by the import")
+	})
+
+	steveClient, err := client.Steve.ProxyDownstream(cluster.ID)
+	Expect(err).To(BeNil())
+	deploymentID := preImportNamespace + "/" + preImportApp
+
+	ginkgo.By(fmt.Sprintf("Checking the pre-import workloads are readable through Rancher on cluster %s", cluster.Name), func() {
+		_, err := steveClient.SteveType("e2e.hosted-providers.cattle.io.widget").ByID(preImportNamespace + "/hp-preimport-widget")
+		Expect(err).To(BeNil())
+	})
+
+	ginkgo.By(fmt.Sprintf("Scaling deployment %s through Rancher on cluster %s", deploymentID, cluster.Name), func() {
+		object, err := steveClient.SteveType("apps.deployment").ByID(deploymentID)
+		Expect(err).To(BeNil())
+		deployment := &appsv1.Deployment{}
+		Expect(steveV1.ConvertToK8sType(object.JSONResp, deployment)).To(Succeed())
+		replicas := *deployment.Spec.Replicas + 1
+		deployment.Spec.Replicas = &replicas
+		_, err = steveClient.SteveType("apps.deployment").Update(object, deployment)
+		Expect(err).To(BeNil())
+
+		EventuallyWithBackoff(func() (int32, error) {
+			object, err := steveClient.SteveType("apps.deployment").ByID(deploymentID)
+			if err != nil {
+				return 0, err
+			}
+			deployment := &appsv1.Deployment{}
+			if err = steveV1.ConvertToK8sType(object.JSONResp, deployment); err != nil {
+				return 0, err
+			}
+			return deployment.Status.ReadyReplicas, nil
+		}, tools.SetTimeout(5*time.Minute), 10*time.Second).Should(Equal(replicas), fmt.Sprintf("Deployment %s was not scaled", deploymentID))
+
+		EventuallyWithBackoff(func() (int32, error) {
+			object, err := steveClient.SteveType("policy.poddisruptionbudget").ByID(deploymentID)
+			if err != nil {
+				return 0, err
+			}
+			pdb := &policyv1.PodDisruptionBudget{}
+			if err = steveV1.ConvertToK8sType(object.JSONResp, pdb); err != nil {
+				return 0, err
+			}
+			return pdb.Status.CurrentHealthy, nil
+		}, tools.SetTimeout(2*time.Minute), 10*time.Second).Should(Equal(replicas), fmt.Sprintf("Pod disruption budget %s does not cover the new pods", deploymentID))
+	})
+}
@@ -0,0 +1,26 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPreImportFingerprints(t *testing.T) {
+	deployment := &unstructured.Unstructured{Object: map[string]any{"kind": "Deployment",
+		"metadata": map[string]any{"name": "hp-preimport-app", "uid": "1234", "generation": int64(2)}, "status": map[string]any{"readyReplicas": int64(1)}}}
+	crd := &unstructured.Unstructured{Object: map[string]any{"kind": "CustomResourceDefinition",
+		"metadata": map[string]any{"name": "widgets.e2e.hosted-providers.cattle.io", "uid": "5678", "generation": int64(1)}}}
+	got := preImportFingerprints([]*unstructured.Unstructured{deployment, crd})
+	want := map[string]string{
+		"Deployment/hp-preimport-app":                                     "uid=1234,generation=2",
+		"CustomResourceDefinition/widgets.e2e.hosted-providers.cattle.io": "uid=5678,generation=1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("preImportFingerprints() = %v, want %v", got, want)
+	}
+	if len(preImportObjects) != 4 {
+		t.Errorf("got %d pre-import objects, want the 4 objects of the manifests", len(preImportObjects))
+	}
+}
@@ -0,0 +1,125 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	ginkgotypes "github.com/onsi/ginkgo/v2/types"
+	"github.com/rancher/norman/types"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// preProvisionedClusters are the IDs of the clusters of the process injected with PREPROVISIONED_CLUSTERS, which the specs must not delete
+var preProvisionedClusters = map[string]bool{}
+
+// parsePreProvisionedClusters parses PREPROVISIONED_CLUSTERS, for e.g. c-m-abc12,74=c-m-def34: the clusters by Qase case ID,
+// and the cluster of the specs whose cases have none under the case ID 0
+func parsePreProvisionedClusters(values []string) (map[int64]string, error) {
+	clusters := map[int64]string{}
+	for _, value := range values {
+		caseID, cluster := int64(0), value
+		if before, after, found := strings.Cut(value, "="); found {
+			id, err := strconv.ParseInt(strings.TrimSpace(before), 10, 64)
+			if err != nil || id <= 0 {
+				return nil, fmt.Errorf("%q is not a valid Qase case ID in %q", before, value)
+			}
+			caseID, cluster = id, strings.TrimSpace(after)
+		}
+		if cluster == "" {
+			return nil, fmt.Errorf("no cluster in %q", value)
+		}
+		if _, found := clusters[caseID]; found {
+			return nil, fmt.Errorf("several clusters are given for the case %d", caseID)
+		}
+		clusters[caseID] = cluster
+	}
+	return clusters, nil
+}
+
+// preProvisionedClusterOf returns the pre-provisioned cluster of a spec covering the given Qase cases: the one of its first case which has one,
+// or the cluster given for all the specs; empty if the spec provisions its cluster
+func preProvisionedClusterOf(caseIDs []int64, clusters map[int64]string) string {
+	for _, id := range caseIDs {
+		if cluster, found := clusters[id]; found {
+			return cluster
+		}
+	}
+	return clusters[0]
+}
+
+// specPreProvisionedCluster returns the pre-provisioned cluster of the current spec, see preProvisionedClusterOf
+func specPreProvisionedCluster() string {
+	// the clusters are checked by Validate
+	clusters, _ := parsePreProvisionedClusters(runConfig.PreProvisionedClusters)
+	caseIDs, _ := qaseCaseIDs(ginkgo.CurrentSpecReport().Labels())
+	return preProvisionedClusterOf(caseIDs, clusters)
+}
+
+// findCluster returns the Rancher cluster with the given ID, or else with the given name; nil if there is none
+func findCluster(client *rancher.Client, idOrName string) (*management.Cluster, error) {
+	if cluster, err := client.Management.Cluster.ByID(idOrName); err == nil {
+		return cluster, nil
+	}
+	collection, err := client.Management.Cluster.List(&types.ListOpts{Filters: map[string]interface{}{"name": idOrName}})
+	if err != nil {
+		return nil, err
+	}
+	if len(collection.Data) == 0 {
+		return nil, nil
+	}
+	return &collection.Data[0], nil
+}
+
+/*
+PreProvisionedCluster returns the cluster of the current spec: the existing cluster given for the spec by PREPROVISIONED_CLUSTERS if there is one,
+for e.g. to iterate on the checks of a spec or to run a failed spec again against the cluster it left behind, or else the cluster it provisions.
+The pre-provisioned cluster is given by its Rancher ID or name; for the import suites, it can also be the name of a cloud cluster which is not imported
+yet, the provision function then imports it without creating it (see PreProvisionedCloudCluster). The pre-provisioned clusters must not be deleted
+by the spec, see IsPreProvisionedCluster.
+  - @param client Rancher client
+  - @param provision Provisions the cluster and waits until it is ready
+  - @returns The ready cluster and the error of its provisioning or lookup
+*/
+func PreProvisionedCluster(client *rancher.Client, provision func() (*management.Cluster, error)) (*management.Cluster, error) {
+	name := specPreProvisionedCluster()
+	if name == "" {
+		return provision()
+	}
+
+	cluster, err := findCluster(client, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the pre-provisioned cluster %s: %w", name, err)
+	}
+	if cluster == nil {
+		if !IsImport {
+			return nil, fmt.Errorf("the pre-provisioned cluster %s is not found in Rancher", name)
+		}
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Importing the pre-provisioned cloud cluster %s", name))
+		cluster, err = provision()
+	} else {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Using the pre-provisioned cluster %s (%s)", cluster.Name, cluster.ID))
+		cluster, err = WaitUntilClusterIsReady(cluster, client)
+	}
+	if cluster != nil {
+		preProvisionedClusters[cluster.ID] = true
+	}
+	return cluster, err
+}
+
+// PreProvisionedCloudCluster returns the name of the pre-provisioned cloud cluster the provision function of an import suite must import
+// instead of creating a cluster, see PreProvisionedCluster; empty if the spec creates its cluster
+func PreProvisionedCloudCluster() string {
+	return specPreProvisionedCluster()
+}
+
+// IsPreProvisionedCluster returns true if the cluster was given by PREPROVISIONED_CLUSTERS, or if the current spec is given one, so that
+// its cluster is kept even if the spec failed to look it up or to import it; the spec must neither delete it nor its cloud cluster
+func IsPreProvisionedCluster(cluster *management.Cluster) bool {
+	if cluster != nil && preProvisionedClusters[cluster.ID] {
+		return true
+	}
+	return ginkgo.CurrentSpecReport().LeafNodeType != ginkgotypes.NodeTypeInvalid && specPreProvisionedCluster() != ""
+}
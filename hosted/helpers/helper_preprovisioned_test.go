@@ -0,0 +1,35 @@
+package helpers
+
+import "testing"
+
+func TestPreProvisionedClusters(t *testing.T) {
+	clusters, err := parsePreProvisionedClusters([]string{"c-m-abc12", "74=c-m-def34", " 71 = my-eks-cluster"})
+	if err != nil {
+		t.Fatalf("parsePreProvisionedClusters() = %v", err)
+	}
+	for _, tc := range []struct {
+		caseIDs []int64
+		want    string
+	}{
+		{[]int64{74}, "c-m-def34"},
+		{[]int64{12, 71, 74}, "my-eks-cluster"},
+		{[]int64{12}, "c-m-abc12"},
+		{nil, "c-m-abc12"},
+	} {
+		if got := preProvisionedClusterOf(tc.caseIDs, clusters); got != tc.want {
+			t.Errorf("preProvisionedClusterOf(%v) = %q, want %q", tc.caseIDs, got, tc.want)
+		}
+	}
+
+	// without a cluster for all the specs, the other specs provision theirs
+	clusters, _ = parsePreProvisionedClusters([]string{"74=c-m-def34"})
+	if got := preProvisionedClusterOf([]int64{71}, clusters); got != "" {
+		t.Errorf("preProvisionedClusterOf() = %q, want no cluster", got)
+	}
+
+	for _, invalid := range [][]string{{"abc=c-m-def34"}, {"0=c-m-def34"}, {"74="}, {"c-m-abc12", "c-m-def34"}, {"74=a", "74=b"}} {
+		if _, err := parsePreProvisionedClusters(invalid); err == nil {
+			t.Errorf("parsePreProvisionedClusters(%v) must fail", invalid)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClosedAuthorizedCIDR is a documentation range (TEST-NET-1) no client connects from; authorizing only this range closes the public endpoint of the API server
+const ClosedAuthorizedCIDR = "192.0.2.0/32"
+
+// apiServerAddress returns the host:port of the API server of a cluster from its endpoint, for e.g. https://ABCD.gr7.ap-south-1.eks.amazonaws.com => ABCD.gr7.ap-south-1.eks.amazonaws.com:443;
+// the endpoint may be a bare host or IP, as reported by GKE.
+func apiServerAddress(endpoint string) (string, error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("endpoint %q has no host", endpoint)
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
+
+/*
+Check that the API server of a downstream cluster can not be reached from the machine running the tests, which is on the network of Rancher;
+Rancher can then only manage the cluster through the tunnel of the cluster agent. The access changes may take a while to be applied by the provider.
+  - @param cluster Downstream cluster
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckAPIServerNotReachable(cluster *management.Cluster) {
+	ginkgo.By(fmt.Sprintf("Checking the API server of cluster %s is not reachable", cluster.Name), func() {
+		Expect(cluster.APIEndpoint).ToNot(BeEmpty(), fmt.Sprintf("Cluster %s has no API endpoint", cluster.Name))
+		address, err := apiServerAddress(cluster.APIEndpoint)
+		Expect(err).To(BeNil())
+
+		EventuallyWithBackoff(func() error {
+			conn, err := net.DialTimeout("tcp", address, 10*time.Second)
+			if err != nil {
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("API server %s is not reachable: %v", address, err))
+				return nil
+			}
+			_ = conn.Close()
+			return fmt.Errorf("API server %s is reachable", address)
+		}, tools.SetTimeout(15*time.Minute), 30*time.Second).Should(Succeed(), fmt.Sprintf("The API server of cluster %s is still publicly reachable", cluster.Name))
+	})
+}
+
+/*
+Reach a downstream cluster with the kubeconfig generated by Rancher, as the kubectl shell of the Rancher UI does, with client-go
+(see RancherDownstreamClients); the requests go through the Rancher proxy and the tunnel of the cluster agent, never to the API server of the cluster.
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckKubectlThroughRancher(client *rancher.Client, cluster *management.Cluster) {
+	ginkgo.By(fmt.Sprintf("Reaching cluster %s through Rancher", cluster.Name), func() {
+		clients, err := RancherDownstreamClients(client, cluster)
+		Expect(err).To(BeNil())
+		Expect(clients.Config.Host).To(HavePrefix("https://"+RancherHostname), "The kubeconfig does not go through Rancher")
+		ctx := context.Background()
+
+		nodes, err := clients.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		Expect(err).To(BeNil())
+		Expect(nodes.Items).ToNot(BeEmpty())
+
+		// the logs are streamed from the kubelet, through the API server
+		agents, err := clients.Clientset.CoreV1().Pods(CattleSystemNS).List(ctx, metav1.ListOptions{LabelSelector: "app=" + clusterAgentDeployment})
+		Expect(err).To(BeNil())
+		Expect(agents.Items).ToNot(BeEmpty())
+		tail := int64(1)
+		_, err = clients.Clientset.CoreV1().Pods(CattleSystemNS).GetLogs(agents.Items[0].Name, &corev1.PodLogOptions{TailLines: &tail}).DoRaw(ctx)
+		Expect(err).To(BeNil())
+
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namegen.AppendRandomString("hp-tunnel")}}
+		_, err = clients.Clientset.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+		Expect(err).To(BeNil())
+		Expect(clients.Clientset.CoreV1().Namespaces().Delete(ctx, namespace.Name, metav1.DeleteOptions{})).To(Succeed())
+	})
+}
@@ -0,0 +1,19 @@
+package helpers
+
+import "testing"
+
+func TestAPIServerAddress(t *testing.T) {
+	for endpoint, want := range map[string]string{
+		"https://ABCD.gr7.ap-south-1.eks.amazonaws.com": "ABCD.gr7.ap-south-1.eks.amazonaws.com:443",
+		"34.100.1.2": "34.100.1.2:443",
+		"https://hp-dns-1234.hcp.centralindia.azmk8s.io:443": "hp-dns-1234.hcp.centralindia.azmk8s.io:443",
+		"https://[2600:1f18::1]:6443":                        "[2600:1f18::1]:6443",
+	} {
+		if got, err := apiServerAddress(endpoint); err != nil || got != want {
+			t.Errorf("apiServerAddress(%s) = %s, %v, want %s", endpoint, got, err, want)
+		}
+	}
+	if _, err := apiServerAddress("https://"); err == nil {
+		t.Error("apiServerAddress(https://) should fail")
+	}
+}
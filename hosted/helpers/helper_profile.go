@@ -0,0 +1,314 @@
+package helpers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/rancher/shepherd/clients/rancher"
+)
+
+// Values of PROFILE: profileWall samples the wall-clock time of the helpers and times the Rancher API calls, profilePprof also records a CPU pprof
+const (
+	profileWall  = "wall"
+	profilePprof = "pprof"
+)
+
+const (
+	// profileSampleInterval is how often the stacks of the specs are sampled; every sample accounts for that much wall-clock time
+	profileSampleInterval = time.Second
+	// profileFlushInterval is how often the profile is written while the suite runs, so that it is kept if the suite is killed
+	profileFlushInterval = time.Minute
+	// modulePrefix is the prefix of the functions of the repository, the only frames kept in the sampled stacks
+	modulePrefix = "github.com/rancher/hosted-providers-e2e/"
+	// specFramePrefix is the frame of the goroutines running the nodes of the specs, the only goroutines sampled
+	specFramePrefix = "github.com/onsi/ginkgo/v2/internal.(*Suite).runNode"
+)
+
+// apiCallStats are the durations of the Rancher API calls to an endpoint
+type apiCallStats struct {
+	Calls int
+	Total time.Duration
+	Max   time.Duration
+}
+
+// helperProfile is the profile of the process, see StartProfiling
+var helperProfile = struct {
+	sync.Mutex
+	// samples are the numbers of samples of the folded stacks, <spec>;<caller>;...;<callee>
+	samples  map[string]int
+	apiCalls map[string]*apiCallStats
+}{samples: map[string]int{}, apiCalls: map[string]*apiCallStats{}}
+
+// profiling returns true if the helpers are profiled, see PROFILE
+func profiling() bool {
+	return runConfig.Profile != ""
+}
+
+// profilePath returns the path of a profile file of the current process
+func profilePath(extension string) string {
+	return filepath.Join(ArtifactsDir, fmt.Sprintf("profile-p%d.%s", ginkgo.GinkgoParallelProcess(), extension))
+}
+
+// sampledStacks returns the stacks of the goroutines running the specs in the goroutine dump, from the caller to the callee;
+// only the functions of the repository are kept, without their module prefix, for e.g. hosted/helpers.WaitUntilClusterIsReady
+func sampledStacks(dump []byte) [][]string {
+	var stacks [][]string
+	for _, goroutine := range bytes.Split(dump, []byte("\n\n")) {
+		var frames []string
+		spec := false
+		scanner := bufio.NewScanner(bytes.NewReader(goroutine))
+		for scanner.Scan() {
+			line := scanner.Text()
+			if strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "goroutine ") || strings.HasPrefix(line, "created by ") {
+				continue
+			}
+			if strings.HasPrefix(line, specFramePrefix) {
+				spec = true
+			}
+			if function, found := strings.CutPrefix(line, modulePrefix); found {
+				// the arguments of the frame are dropped, for e.g. (0xc000123456, {0x1, 0x2})
+				if i := strings.LastIndex(function, "("); i > 0 {
+					function = function[:i]
+				}
+				frames = append([]string{function}, frames...)
+			}
+		}
+		if spec && len(frames) > 0 {
+			stacks = append(stacks, frames)
+		}
+	}
+	return stacks
+}
+
+// foldedStack returns the folded stack of the spec, as read by flamegraph.pl or speedscope; the separators of the folded format are removed from the spec text
+func foldedStack(spec string, frames []string) string {
+	if spec == "" {
+		spec = "suite"
+	}
+	spec = strings.NewReplacer(";", ",", " ", "_").Replace(spec)
+	return strings.Join(append([]string{spec}, frames...), ";")
+}
+
+// sampleSpecs samples the stacks of the running specs once
+func sampleSpecs() {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	spec := ginkgo.CurrentSpecReport().FullText()
+
+	helperProfile.Lock()
+	defer helperProfile.Unlock()
+	for _, frames := range sampledStacks(buf) {
+		helperProfile.samples[foldedStack(spec, frames)]++
+	}
+}
+
+// apiEndpoint returns the endpoint of a Rancher API call, without the IDs of the resources, for e.g. GET /v3/clusters/{id}?action=generateKubeconfig
+func apiEndpoint(method string, u *url.URL) string {
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) > 2 {
+		segments = append(segments[:2], "{id}")
+	}
+	endpoint := method + " /" + strings.Join(segments, "/")
+	if action := u.Query().Get("action"); action != "" {
+		endpoint += "?action=" + action
+	}
+	return endpoint
+}
+
+// recordAPICall records the duration of a Rancher API call
+func recordAPICall(endpoint string, duration time.Duration) {
+	helperProfile.Lock()
+	defer helperProfile.Unlock()
+	stats, found := helperProfile.apiCalls[endpoint]
+	if !found {
+		stats = &apiCallStats{}
+		helperProfile.apiCalls[endpoint] = stats
+	}
+	stats.Calls++
+	stats.Total += duration
+	stats.Max = max(stats.Max, duration)
+}
+
+// profiledTransport times the Rancher API calls of a client
+type profiledTransport struct {
+	base http.RoundTripper
+}
+
+func (t profiledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	recordAPICall(apiEndpoint(req.Method, req.URL), time.Since(start))
+	return resp, err
+}
+
+// ProfileClient times the Rancher API calls of the client with PROFILE; it is called for the clients created by the helpers, for e.g. the admin client
+func ProfileClient(client *rancher.Client) {
+	if !profiling() {
+		return
+	}
+	for _, httpClient := range []*http.Client{client.Management.Ops.Client, client.Steve.Ops.Client} {
+		if _, profiled := httpClient.Transport.(profiledTransport); profiled {
+			continue
+		}
+		base := httpClient.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		httpClient.Transport = profiledTransport{base: base}
+	}
+}
+
+// writeProfileSummary writes the summary of the samples and API calls: the wall-clock time spent in each function of the repository, including its callees,
+// and the time of the API calls by endpoint, both sorted by time
+func writeProfileSummary(w *bufio.Writer, samples map[string]int, apiCalls map[string]*apiCallStats, interval time.Duration) {
+	cumulative := map[string]int{}
+	total := 0
+	for stack, count := range samples {
+		total += count
+		seen := map[string]bool{}
+		// the first frame is the spec
+		for _, function := range strings.Split(stack, ";")[1:] {
+			// the recursive functions are only counted once per stack
+			if !seen[function] {
+				cumulative[function] += count
+				seen[function] = true
+			}
+		}
+	}
+	functions := make([]string, 0, len(cumulative))
+	for function := range cumulative {
+		functions = append(functions, function)
+	}
+	sort.Slice(functions, func(i, j int) bool {
+		if cumulative[functions[i]] != cumulative[functions[j]] {
+			return cumulative[functions[i]] > cumulative[functions[j]]
+		}
+		return functions[i] < functions[j]
+	})
+
+	fmt.Fprintf(w, "Wall-clock time of the specs: %s, sampled every %s\n\n", time.Duration(total)*interval, interval)
+	fmt.Fprintf(w, "%12s %7s  %s\n", "time", "share", "function (with its callees)")
+	for _, function := range functions {
+		fmt.Fprintf(w, "%12s %6.1f%%  %s\n", time.Duration(cumulative[function])*interval, 100*float64(cumulative[function])/float64(total), function)
+	}
+
+	endpoints := make([]string, 0, len(apiCalls))
+	for endpoint := range apiCalls {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if apiCalls[endpoints[i]].Total != apiCalls[endpoints[j]].Total {
+			return apiCalls[endpoints[i]].Total > apiCalls[endpoints[j]].Total
+		}
+		return endpoints[i] < endpoints[j]
+	})
+	fmt.Fprintf(w, "\nRancher API calls\n\n%12s %7s %12s %12s  %s\n", "time", "calls", "mean", "max", "endpoint")
+	for _, endpoint := range endpoints {
+		stats := apiCalls[endpoint]
+		fmt.Fprintf(w, "%12s %7d %12s %12s  %s\n", stats.Total.Round(time.Millisecond), stats.Calls,
+			(stats.Total / time.Duration(stats.Calls)).Round(time.Millisecond), stats.Max.Round(time.Millisecond), endpoint)
+	}
+}
+
+// writeProfile writes the folded stacks and the summary of the profile to the artifacts directory
+func writeProfile() error {
+	helperProfile.Lock()
+	defer helperProfile.Unlock()
+	if err := os.MkdirAll(ArtifactsDir, 0o755); err != nil {
+		return err
+	}
+
+	stacks := make([]string, 0, len(helperProfile.samples))
+	for stack, count := range helperProfile.samples {
+		stacks = append(stacks, fmt.Sprintf("%s %d", stack, count))
+	}
+	sort.Strings(stacks)
+	if err := os.WriteFile(profilePath("folded"), []byte(strings.Join(stacks, "\n")+"\n"), 0o644); err != nil {
+		return err
+	}
+
+	var summary bytes.Buffer
+	w := bufio.NewWriter(&summary)
+	writeProfileSummary(w, helperProfile.samples, helperProfile.apiCalls, profileSampleInterval)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return os.WriteFile(profilePath("txt"), summary.Bytes(), 0o644)
+}
+
+/*
+StartProfiling profiles the suite with PROFILE: the stacks of the running specs are sampled every second, so that the wall-clock time spent in every function
+of the repository, the helpers and the spec bodies, is recorded along with the Rancher API calls of the clients (see ProfileClient). Once the suite ends,
+and every minute meanwhile, the profile is written to the artifacts directory, as profile-p<process>.folded, the folded stacks to render as a flame graph
+(for e.g. with flamegraph.pl or speedscope), and profile-p<process>.txt, the time of the functions and of the API calls sorted by time.
+With PROFILE=pprof, a CPU profile of the test binary is also written to profile-p<process>.pprof. It is called by SuiteConfig.
+  - @param t Go test of the suite, the profile is written once it ends
+  - @returns Nothing, failing to profile the suite is only logged
+*/
+func StartProfiling(t testing.TB) {
+	if !profiling() {
+		return
+	}
+	if runConfig.Profile == profilePprof {
+		if err := os.MkdirAll(ArtifactsDir, 0o755); err != nil {
+			t.Logf("Failed to create the artifacts directory, the CPU profile is not recorded: %v", err)
+		} else if cpuProfile, err := os.Create(profilePath("pprof")); err != nil {
+			t.Logf("Failed to create the CPU profile: %v", err)
+		} else if err = pprof.StartCPUProfile(cpuProfile); err != nil {
+			cpuProfile.Close()
+			t.Logf("Failed to start the CPU profile: %v", err)
+		} else {
+			t.Cleanup(func() {
+				pprof.StopCPUProfile()
+				cpuProfile.Close()
+			})
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(profileSampleInterval)
+		defer ticker.Stop()
+		lastFlush := time.Now()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				sampleSpecs()
+				if time.Since(lastFlush) >= profileFlushInterval {
+					if err := writeProfile(); err != nil {
+						ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to write the profile: %v", err))
+					}
+					lastFlush = time.Now()
+				}
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		close(done)
+		if err := writeProfile(); err != nil {
+			t.Logf("Failed to write the profile: %v", err)
+		}
+	})
+}
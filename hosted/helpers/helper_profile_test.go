@@ -0,0 +1,108 @@
+package helpers
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const goroutineDump = `goroutine 42 [sleep]:
+time.Sleep(0x2540be400)
+	/usr/local/go/src/runtime/time.go:195 +0x125
+github.com/rancher/hosted-providers-e2e/hosted/helpers.(*clusterWatcher).read(0xc000123456)
+	/root/module/hosted/helpers/helper_clusterwatcher.go:75 +0x2a
+github.com/rancher/hosted-providers-e2e/hosted/helpers.WaitUntilClusterIsReady({0xc0001, 0x2}, 0xc0002)
+	/root/module/hosted/helpers/helper_common.go:260 +0x1b
+github.com/rancher/hosted-providers-e2e/hosted/eks/p0_test.init.func3.1()
+	/root/module/hosted/eks/p0/p0_provisioning_test.go:90 +0x3c
+github.com/onsi/ginkgo/v2/internal.(*Suite).runNode.func3()
+	/root/go/pkg/mod/github.com/onsi/ginkgo/v2/internal/suite.go:942 +0x1b2
+created by github.com/onsi/ginkgo/v2/internal.(*Suite).runNode in goroutine 7
+	/root/go/pkg/mod/github.com/onsi/ginkgo/v2/internal/suite.go:906 +0xba6
+
+goroutine 51 [select]:
+github.com/rancher/hosted-providers-e2e/hosted/helpers.(*clusterWatcher).poll(0xc000123456, {0xc0003, 0x9})
+	/root/module/hosted/helpers/helper_clusterwatcher.go:98 +0x11
+created by github.com/rancher/hosted-providers-e2e/hosted/helpers.WatchedCluster in goroutine 42
+	/root/module/hosted/helpers/helper_clusterwatcher.go:135 +0x22
+`
+
+func TestSampledStacks(t *testing.T) {
+	got := sampledStacks([]byte(goroutineDump))
+	want := [][]string{{"hosted/eks/p0_test.init.func3.1", "hosted/helpers.WaitUntilClusterIsReady", "hosted/helpers.(*clusterWatcher).read"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want only the stack of the spec %v", got, want)
+	}
+	if stack := foldedStack("P0 Provisioning; eks", want[0]); !strings.HasPrefix(stack, "P0_Provisioning,_eks;hosted/eks/p0_test.init.func3.1;") {
+		t.Errorf("got folded stack %q", stack)
+	}
+}
+
+func TestAPIEndpoint(t *testing.T) {
+	tests := []struct {
+		method, rawURL, want string
+	}{
+		{method: "GET", rawURL: "https://rancher.example.com/v3/clusters/c-m-abc12", want: "GET /v3/clusters/{id}"},
+		{method: "POST", rawURL: "https://rancher.example.com/v3/clusters/c-m-abc12?action=generateKubeconfig", want: "POST /v3/clusters/{id}?action=generateKubeconfig"},
+		{method: "GET", rawURL: "https://rancher.example.com/v3/settings", want: "GET /v3/settings"},
+		{method: "GET", rawURL: "https://rancher.example.com/v1/management.cattle.io.clusters/fleet-default/c-m-abc12", want: "GET /v1/management.cattle.io.clusters/{id}"},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse(tt.rawURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := apiEndpoint(tt.method, u); got != tt.want {
+			t.Errorf("apiEndpoint(%s %s) = %q, want %q", tt.method, tt.rawURL, got, tt.want)
+		}
+	}
+}
+
+func TestProfiledTransport(t *testing.T) {
+	defer func() { helperProfile.apiCalls = map[string]*apiCallStats{} }()
+	helperProfile.apiCalls = map[string]*apiCallStats{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	client := &http.Client{Transport: profiledTransport{base: http.DefaultTransport}}
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL + "/v3/clusters/c-m-abc12")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if stats := helperProfile.apiCalls["GET /v3/clusters/{id}"]; stats == nil || stats.Calls != 3 {
+		t.Errorf("got %+v, want the 3 calls to be recorded", stats)
+	}
+}
+
+func TestWriteProfileSummary(t *testing.T) {
+	samples := map[string]int{
+		"spec_A;hosted/helpers.WaitUntilClusterIsReady;hosted/helpers.WatchedCluster": 90,
+		"spec_A;hosted/helpers.ScaleNodeGroup":                                        10,
+	}
+	apiCalls := map[string]*apiCallStats{"GET /v3/clusters/{id}": {Calls: 4, Total: 2 * time.Second, Max: time.Second}}
+	var out bytes.Buffer
+	w := bufio.NewWriter(&out)
+	writeProfileSummary(w, samples, apiCalls, time.Second)
+	w.Flush()
+
+	summary := out.String()
+	for _, line := range []string{"Wall-clock time of the specs: 1m40s", "1m30s   90.0%  hosted/helpers.WaitUntilClusterIsReady", "10s   10.0%  hosted/helpers.ScaleNodeGroup",
+		"2s       4        500ms           1s  GET /v3/clusters/{id}"} {
+		if !strings.Contains(summary, line) {
+			t.Errorf("the summary does not contain %q:\n%s", line, summary)
+		}
+	}
+	if strings.Index(summary, "WaitUntilClusterIsReady") > strings.Index(summary, "ScaleNodeGroup") {
+		t.Errorf("the functions must be sorted by time:\n%s", summary)
+	}
+}
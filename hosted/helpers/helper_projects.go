@@ -0,0 +1,129 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/rancher/tests/v2/actions/projects"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/users"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Project role templates that can be bound to a user with AddProjectMember
+const (
+	ProjectOwnerRole    = "project-owner"
+	ProjectMemberRole   = "project-member"
+	ProjectReadOnlyRole = "read-only"
+)
+
+// configMapSteveType is the Steve type of the config maps, used to check the write access to a namespace
+const configMapSteveType = "configmap"
+
+/*
+Create a Rancher project with a namespace on a downstream cluster; the project and its namespace are deleted once the spec is done
+  - @param client Rancher admin client
+  - @param cluster Downstream cluster
+  - @returns The project and its namespace; the function will fail through Ginkgo in case of issue
+*/
+func CreateProjectAndNamespace(client *rancher.Client, cluster *management.Cluster) (project *management.Project, namespace *corev1.Namespace) {
+	ginkgo.By(fmt.Sprintf("Creating a project and a namespace on cluster %s", cluster.Name), func() {
+		var err error
+		project, namespace, err = projects.CreateProjectAndNamespace(client, cluster.ID)
+		Expect(err).To(BeNil())
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Created project %s with namespace %s", project.ID, namespace.Name))
+	})
+	ginkgo.DeferCleanup(DeleteProject, client, project)
+	return project, namespace
+}
+
+/*
+Delete a Rancher project; Rancher deletes the namespaces of the project along with it
+  - @param client Rancher admin client
+  - @param project Project to delete
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func DeleteProject(client *rancher.Client, project *management.Project) {
+	current, err := client.Management.Project.ByID(project.ID)
+	if err != nil && strings.Contains(err.Error(), "not found") {
+		return
+	}
+	Expect(err).To(BeNil())
+	Expect(client.Management.Project.Delete(current)).To(Succeed())
+}
+
+/*
+Bind a project role to the user of a client, for e.g. the standard user client, and wait for the role to be rolled out on the downstream cluster
+  - @param client Rancher admin client
+  - @param project Project
+  - @param userClient Client of the user to add to the project
+  - @param role Project role template, for e.g. ProjectMemberRole
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func AddProjectMember(client *rancher.Client, project *management.Project, userClient *rancher.Client, role string) {
+	user, err := client.Management.User.ByID(userClient.UserID)
+	Expect(err).To(BeNil())
+
+	ginkgo.By(fmt.Sprintf("Binding role %s of project %s to user %s", role, project.ID, user.Username), func() {
+		Expect(users.AddProjectMember(client, project, user, role, nil)).To(Succeed())
+	})
+}
+
+// isForbidden returns true if the error is a permission error of the Rancher or Kubernetes API
+func isForbidden(err error) bool {
+	return err != nil && (strings.Contains(err.Error(), "403") || strings.Contains(strings.ToLower(err.Error()), "forbidden"))
+}
+
+/*
+Check the access of a user to a namespace of a downstream cluster: the namespace must be listed if canRead is true, and a config map can be
+created in it if canWrite is true; the opposite is checked otherwise, so that the enforcement of the project roles is verified both ways.
+  - @param userClient Client of the user, for e.g. the standard user client
+  - @param clusterID ID of the downstream cluster
+  - @param namespace Name of the namespace
+  - @param canRead Whether the user is expected to see the namespace
+  - @param canWrite Whether the user is expected to create resources in the namespace
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckNamespaceAccess(userClient *rancher.Client, clusterID, namespace string, canRead, canWrite bool) {
+	ginkgo.By(fmt.Sprintf("Checking the access to namespace %s: read=%t write=%t", namespace, canRead, canWrite), func() {
+		// the permissions of a new role binding take a while to be effective on the downstream cluster
+		EventuallyWithBackoff(func() error {
+			steveClient, err := userClient.Steve.ProxyDownstream(clusterID)
+			if err != nil {
+				if !canRead && isForbidden(err) {
+					return nil
+				}
+				return err
+			}
+
+			_, err = steveClient.SteveType("namespace").ByID(namespace)
+			if canRead && err != nil {
+				return fmt.Errorf("namespace %s can not be read: %w", namespace, err)
+			}
+			if !canRead && !isForbidden(err) && !strings.Contains(fmt.Sprint(err), "not found") {
+				return fmt.Errorf("namespace %s can be read: %v", namespace, err)
+			}
+
+			configMap, err := steveClient.SteveType(configMapSteveType).Create(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: namegen.AppendRandomString("hp-access"), Namespace: namespace},
+			})
+			if err == nil {
+				_ = steveClient.SteveType(configMapSteveType).Delete(configMap)
+			}
+			if canWrite && err != nil {
+				return fmt.Errorf("namespace %s can not be written: %w", namespace, err)
+			}
+			if !canWrite && !isForbidden(err) {
+				return fmt.Errorf("namespace %s can be written: %v", namespace, err)
+			}
+			return nil
+		}, tools.SetTimeout(3*time.Minute), 5*time.Second).Should(Succeed())
+	})
+}
@@ -0,0 +1,19 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsForbidden(t *testing.T) {
+	for err, want := range map[error]bool{
+		nil: false,
+		errors.New(`configmaps is forbidden: User "u-abcde" cannot create resource "configmaps"`): true,
+		errors.New("response 403 (Forbidden)"):                                                    true,
+		errors.New(`namespaces "testns-abcde" not found`):                                         false,
+	} {
+		if got := isForbidden(err); got != want {
+			t.Errorf("%v: got %t, want %t", err, got, want)
+		}
+	}
+}
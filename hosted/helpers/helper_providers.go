@@ -0,0 +1,160 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+/*
+HostedProvider is a hosted provider of Rancher, implemented by the helper package of the provider (hosted/<provider>/helper) with its own helpers
+and registered with RegisterProvider from an init function of the package. The suites shared by the providers, for e.g. the multi-provider suites,
+and the janitor (see CleanupLeakedClusters) use the registered providers instead of a copy of their code per provider; the specs of a provider still
+use its helper package directly. A new hosted provider of Rancher is added by implementing HostedProvider in its helper package; the operations wait
+for the cluster to be updated and check its config, and the default location of the provider is used, for e.g. GetEKSRegion.
+*/
+type HostedProvider interface {
+	// Name returns the PROVIDER of the provider, for e.g. eks
+	Name() string
+	// Location returns the location the clusters are created in, for e.g. the region of GetEKSRegion
+	Location() string
+	// K8sVersion returns the kubernetes version to create a cluster with; a version that can be upgraded is returned if forUpgrade is true
+	K8sVersion(client *rancher.Client, cloudCredID string, forUpgrade bool) (string, error)
+	// AvailableVersions returns the kubernetes versions the cluster can be upgraded to
+	AvailableVersions(client *rancher.Client, cluster *management.Cluster) ([]string, error)
+	// CreateCluster provisions a cluster with the hosted config of the cattle config file
+	CreateCluster(client *rancher.Client, clusterName, cloudCredID, k8sVersion string) (*management.Cluster, error)
+	// ImportCluster imports a cluster created on the cloud provider, for e.g. with OutOfBandClient.CreateCluster
+	ImportCluster(client *rancher.Client, clusterName, cloudCredID string) (*management.Cluster, error)
+	// UpgradeCluster upgrades the kubernetes version of the control plane
+	UpgradeCluster(cluster *management.Cluster, client *rancher.Client, k8sVersion string) (*management.Cluster, error)
+	// UpdateCluster updates the cluster with updateFunc, applied to a copy of its config
+	UpdateCluster(cluster *management.Cluster, client *rancher.Client, updateFunc func(*management.Cluster)) (*management.Cluster, error)
+	// AddNodePool adds a nodepool (nodegroup on EKS) to the cluster
+	AddNodePool(cluster *management.Cluster, client *rancher.Client) (*management.Cluster, error)
+	// DeleteNodePool deletes the last nodepool of the cluster
+	DeleteNodePool(cluster *management.Cluster, client *rancher.Client) (*management.Cluster, error)
+	// ScaleNodePools scales all the nodepools of the cluster to nodeCount nodes
+	ScaleNodePools(cluster *management.Cluster, client *rancher.Client, nodeCount int64) (*management.Cluster, error)
+	// DeleteCluster deletes the cluster from Rancher
+	DeleteCluster(cluster *management.Cluster, client *rancher.Client) error
+	// OutOfBand returns the client managing the clusters directly on the cloud provider, out of band of Rancher
+	OutOfBand() OutOfBandClient
+}
+
+// OutOfBandClient manages the clusters of a provider directly on the cloud provider with its CLI, for e.g. the clusters of the import tests
+type OutOfBandClient interface {
+	// CreateCluster creates a cluster of one node in the default location of the provider, with the common metadata labels
+	CreateCluster(clusterName, k8sVersion string) error
+	// ClusterExists returns true if the cluster exists in the location (region, zone or location of the provider)
+	ClusterExists(clusterName, location string) (bool, error)
+	// DeleteCluster deletes the cluster of the location along with its cloud resources
+	DeleteCluster(clusterName, location string) error
+	// UpdateTags adds or updates the tags (labels on GKE) of the cluster of the location, the other tags are kept
+	UpdateTags(clusterName, location string, tags map[string]string) error
+}
+
+var hostedProviders = struct {
+	sync.Mutex
+	providers map[string]HostedProvider
+}{providers: map[string]HostedProvider{}}
+
+// RegisterProvider registers a hosted provider by its name; it must be called from an init function of the helper package of the provider,
+// and panics if the provider is already registered
+func RegisterProvider(provider HostedProvider) {
+	hostedProviders.Lock()
+	defer hostedProviders.Unlock()
+
+	if _, ok := hostedProviders.providers[provider.Name()]; ok {
+		panic(fmt.Sprintf("provider %s is already registered", provider.Name()))
+	}
+	hostedProviders.providers[provider.Name()] = provider
+}
+
+// LookupProvider returns the registered hosted provider; the helper package of the provider must be imported by the suite for it to be registered
+func LookupProvider(name string) (HostedProvider, error) {
+	hostedProviders.Lock()
+	defer hostedProviders.Unlock()
+
+	provider, ok := hostedProviders.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("provider %q is not registered; registered providers: %s", name, strings.Join(registeredProviders(), ", "))
+	}
+	return provider, nil
+}
+
+// RegisteredProviders returns the names of the registered hosted providers, sorted
+func RegisteredProviders() []string {
+	hostedProviders.Lock()
+	defer hostedProviders.Unlock()
+
+	return registeredProviders()
+}
+
+// registeredProviders returns the names of the registered providers; it must be called with the providers locked
+func registeredProviders() []string {
+	names := make([]string, 0, len(hostedProviders.providers))
+	for name := range hostedProviders.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// leakedClusters returns the clusters created on the cloud providers by the helpers and not deleted, once per provider, name and location
+func leakedClusters(resources []*Resource) []*Resource {
+	var leaked []*Resource
+	seen := map[string]bool{}
+	for _, resource := range resources {
+		key := strings.Join([]string{resource.Provider, resource.Region, resource.Name}, "/")
+		if resource.Kind != ResourceCloudCluster || resource.DeletedAt != nil || seen[key] {
+			continue
+		}
+		seen[key] = true
+		leaked = append(leaked, resource)
+	}
+	return leaked
+}
+
+/*
+CleanupLeakedClusters is the janitor of the run: the clusters created on the cloud providers by the helpers (for e.g. the clusters of the import tests)
+and not deleted by the specs, as recorded in the resource manifests of all the parallel processes of the run, are deleted with the out-of-band client of their provider.
+Nothing is done if the cleanup of the clusters is disabled (DOWNSTREAM_CLUSTER_CLEANUP=false). It runs once all the parallel processes are done,
+from CommonSynchronizedAfterSuite; a cluster of a provider not registered by the suite is left as it is,
+and so are the clusters of the manifests of the other runs (RUN_ID) or Rancher servers, for e.g. kept by a run with DOWNSTREAM_CLUSTER_CLEANUP=false.
+  - @returns Nothing, the failures are logged since the suite is already done
+*/
+func CleanupLeakedClusters() {
+	if !clusterCleanup {
+		return
+	}
+	var resources []*Resource
+	for _, manifest := range runResourceManifests() {
+		resources = append(resources, manifest.Resources...)
+	}
+
+	for _, resource := range leakedClusters(resources) {
+		provider, err := LookupProvider(resource.Provider)
+		if err != nil {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Janitor: leaving the leaked cluster %s: %v", resource.Name, err))
+			continue
+		}
+		exists, err := provider.OutOfBand().ClusterExists(resource.Name, resource.Region)
+		if err != nil && !isClusterNotFoundError(err.Error()) {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Janitor: failed to check the leaked cluster %s: %v", resource.Name, err))
+			continue
+		}
+		if !exists {
+			continue
+		}
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Janitor: deleting the %s cluster %s leaked by %q", resource.Provider, resource.Name, resource.Spec))
+		if err = provider.OutOfBand().DeleteCluster(resource.Name, resource.Region); err != nil {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Janitor: failed to delete the leaked cluster %s, delete it manually: %v", resource.Name, err))
+		}
+	}
+}
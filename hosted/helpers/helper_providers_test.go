@@ -0,0 +1,63 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testProvider is a HostedProvider with a name only
+type testProvider struct {
+	HostedProvider
+	name string
+}
+
+func (p testProvider) Name() string {
+	return p.name
+}
+
+func TestRegisterProvider(t *testing.T) {
+	RegisterProvider(testProvider{name: "test-provider"})
+	defer func() {
+		hostedProviders.Lock()
+		delete(hostedProviders.providers, "test-provider")
+		hostedProviders.Unlock()
+	}()
+
+	provider, err := LookupProvider("test-provider")
+	if err != nil || provider.Name() != "test-provider" {
+		t.Fatalf("got %v, %v", provider, err)
+	}
+	if _, err = LookupProvider("unknown"); err == nil {
+		t.Error("expected an error with a provider not registered")
+	}
+	if providers := RegisteredProviders(); !reflect.DeepEqual(providers, []string{"test-provider"}) {
+		t.Errorf("got %v", providers)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic with a provider registered twice")
+		}
+	}()
+	RegisterProvider(testProvider{name: "test-provider"})
+}
+
+func TestLeakedClusters(t *testing.T) {
+	deletedAt := time.Now()
+	resources := []*Resource{
+		{Kind: ResourceCloudCluster, Name: "auto-eks-hp-ci-1-abcde", Provider: "eks", Region: "ap-south-1"},
+		{Kind: ResourceCloudCluster, Name: "auto-eks-hp-ci-1-fghij", Provider: "eks", Region: "ap-south-1", DeletedAt: &deletedAt},
+		{Kind: ResourceRancherCluster, Name: "auto-eks-hp-ci-1-klmno", Provider: "eks", Region: "ap-south-1"},
+		// recorded again by another parallel process
+		{Kind: ResourceCloudCluster, Name: "auto-eks-hp-ci-1-abcde", Provider: "eks", Region: "ap-south-1"},
+		{Kind: ResourceCloudCluster, Name: "auto-gke-hp-ci-1-pqrst", Provider: "gke", Region: "asia-south2-c"},
+	}
+	var names []string
+	for _, resource := range leakedClusters(resources) {
+		names = append(names, resource.Name)
+	}
+	if want := []string{"auto-eks-hp-ci-1-abcde", "auto-gke-hp-ci-1-pqrst"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+}
@@ -0,0 +1,157 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	shepherdclusters "github.com/rancher/shepherd/extensions/clusters"
+)
+
+// provisioningClusterNamespace is the namespace of the provisioning.cattle.io clusters Rancher creates for the management clusters
+const provisioningClusterNamespace = "fleet-default"
+
+// provisioningClusterProblems returns the differences between the provisioning.cattle.io status of a cluster and its management cluster,
+// for e.g. a provisioning cluster that is not ready while the management cluster is active, or a condition of the management cluster
+// copied with another status; an empty list means they agree.
+func provisioningClusterProblems(cluster *management.Cluster, status *provv1.ClusterStatus) []string {
+	var problems []string
+	if status.ClusterName != cluster.ID {
+		problems = append(problems, fmt.Sprintf("status.clusterName is %q instead of %q", status.ClusterName, cluster.ID))
+	}
+	if cluster.State == activeState {
+		if !status.Ready {
+			problems = append(problems, "status.ready is false while the management cluster is active")
+		}
+		if !status.AgentDeployed {
+			problems = append(problems, "status.agentDeployed is false while the management cluster is active")
+		}
+		ready := false
+		for _, condition := range status.Conditions {
+			ready = ready || (condition.Type == "Ready" && condition.Status == "True")
+		}
+		if !ready {
+			problems = append(problems, "the Ready condition is not True while the management cluster is active")
+		}
+
+		// the conditions of the management cluster are copied to the provisioning cluster, they only differ until the copy once the cluster is settled
+		provConditions := map[string]string{}
+		for _, condition := range status.Conditions {
+			provConditions[condition.Type] = string(condition.Status)
+		}
+		for _, condition := range cluster.Conditions {
+			if provStatus, found := provConditions[condition.Type]; found && provStatus != condition.Status {
+				problems = append(problems, fmt.Sprintf("the %s condition is %s instead of %s", condition.Type, provStatus, condition.Status))
+			}
+		}
+	}
+	return problems
+}
+
+// provisioningStateProblems returns why the state of the provisioning cluster, the one the UI shows in the cluster list, does not agree with
+// an active management cluster, for e.g. an error state; the state is not checked while the management cluster is not active.
+func provisioningStateProblems(cluster *management.Cluster, state *steveV1.State) []string {
+	if cluster.State != activeState {
+		return nil
+	}
+	switch {
+	case state == nil:
+		return []string{"metadata.state is not set while the management cluster is active"}
+	case state.Error:
+		return []string{fmt.Sprintf("metadata.state is in error while the management cluster is active: %s", state.Message)}
+	case state.Name != activeState:
+		return []string{fmt.Sprintf("metadata.state is %s instead of %s", state.Name, activeState)}
+	}
+	return nil
+}
+
+/*
+Get the provisioning.cattle.io cluster of a hosted cluster, which Rancher creates for every management cluster and through which the UI lists,
+reads and deletes them; the v1 spec has no hosted provider config, so the hosted clusters are still created through the management API.
+  - @param client Rancher client
+  - @param cluster Management cluster
+  - @returns The provisioning cluster, or an error if it is not found
+*/
+func GetProvisioningCluster(client *rancher.Client, cluster *management.Cluster) (*steveV1.SteveAPIObject, error) {
+	collection, err := client.Steve.SteveType(shepherdclusters.ProvisioningSteveResourceType).NamespacedSteveClient(provisioningClusterNamespace).List(nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := range collection.Data {
+		status := &provv1.ClusterStatus{}
+		if err := steveV1.ConvertToK8sType(collection.Data[i].Status, status); err != nil {
+			return nil, err
+		}
+		if status.ClusterName == cluster.ID {
+			return &collection.Data[i], nil
+		}
+	}
+	return nil, fmt.Errorf("provisioning cluster of cluster %s not found", cluster.ID)
+}
+
+/*
+Check that a hosted cluster is listed and read through the provisioning.cattle.io API, and that its status, conditions and state there agree
+with the management cluster; the management API is the one the clusters are created through, the provisioning API the one the UI reads them from.
+It is part of ClusterIsReadyChecks.
+  - @param client Rancher client
+  - @param cluster Management cluster
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckProvisioningCluster(client *rancher.Client, cluster *management.Cluster) {
+	ginkgo.By(fmt.Sprintf("Checking the provisioning cluster of cluster %s", cluster.Name), func() {
+		// the status of the provisioning cluster is synced from the management cluster, with a delay
+		EventuallyWithBackoff(func() (string, error) {
+			current, err := client.Management.Cluster.ByID(cluster.ID)
+			if err != nil {
+				return "", err
+			}
+			provCluster, err := GetProvisioningCluster(client, current)
+			if err != nil {
+				return "", err
+			}
+			status := &provv1.ClusterStatus{}
+			if err = steveV1.ConvertToK8sType(provCluster.Status, status); err != nil {
+				return "", err
+			}
+			problems := append(provisioningClusterProblems(current, status), provisioningStateProblems(current, provCluster.State)...)
+			return strings.Join(problems, "\n"), nil
+		}, tools.SetTimeout(5*time.Minute), 10*time.Second).Should(BeEmpty(), fmt.Sprintf("The provisioning cluster of cluster %s does not agree with it", cluster.Name))
+	})
+}
+
+// DeleteThroughProvisioningAPI deletes the provisioning.cattle.io cluster of a hosted cluster, as the UI does; Rancher then deletes the management cluster
+func DeleteThroughProvisioningAPI(cluster *management.Cluster, client *rancher.Client) error {
+	provCluster, err := GetProvisioningCluster(client, cluster)
+	if err != nil {
+		return err
+	}
+	if err = client.Steve.SteveType(shepherdclusters.ProvisioningSteveResourceType).Delete(provCluster); err != nil {
+		return err
+	}
+	MarkResourceDeleted(ResourceRancherCluster, cluster.Name)
+	return nil
+}
+
+/*
+Delete a hosted cluster through the provisioning.cattle.io API, as the UI does, and wait for the management cluster to be removed
+and for the cluster to be deleted on the cloud provider
+  - @param client Rancher client
+  - @param cluster Management cluster
+  - @param existsOnCloud Returns whether the cluster still exists on the cloud provider, for e.g. with ClusterExistsOnAWS
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func DeleteProvisioningCluster(client *rancher.Client, cluster *management.Cluster, existsOnCloud func() (bool, error)) {
+	ginkgo.By(fmt.Sprintf("Deleting cluster %s through the provisioning API", cluster.Name), func() {
+		Expect(DeleteThroughProvisioningAPI(cluster, client)).To(Succeed())
+		WaitUntilClusterIsRemoved(client, cluster.ID)
+
+		EventuallyWithBackoff(existsOnCloud, tools.SetTimeout(30*time.Minute), 30*time.Second).Should(BeFalse(), fmt.Sprintf("Cluster %s still exists on the cloud provider", cluster.Name))
+	})
+}
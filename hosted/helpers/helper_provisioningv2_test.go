@@ -0,0 +1,65 @@
+package helpers
+
+import (
+	"encoding/json"
+	"testing"
+
+	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+)
+
+func TestProvisioningClusterProblems(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		state      string
+		conditions []management.ClusterCondition
+		status     string
+		problems   int
+	}{
+		{name: "ready", state: "active", problems: 0,
+			status: `{"clusterName":"c-abcde","ready":true,"agentDeployed":true,"conditions":[{"type":"Ready","status":"True"}]}`},
+		{name: "provisioning", state: "provisioning", problems: 0,
+			status: `{"clusterName":"c-abcde","conditions":[{"type":"Ready","status":"False"}]}`},
+		{name: "not ready while active", state: "active", problems: 3,
+			status: `{"clusterName":"c-abcde","conditions":[{"type":"Ready","status":"False"}]}`},
+		{name: "other cluster", state: "provisioning", problems: 1,
+			status: `{"clusterName":"c-fghij"}`},
+		{name: "diverging condition", state: "active", problems: 1,
+			conditions: []management.ClusterCondition{{Type: "Ready", Status: "True"}, {Type: "Updated", Status: "True"}, {Type: "Connected", Status: "True"}},
+			status:     `{"clusterName":"c-abcde","ready":true,"agentDeployed":true,"conditions":[{"type":"Ready","status":"True"},{"type":"Updated","status":"False"}]}`},
+		{name: "diverging condition while updating", state: "updating", problems: 0,
+			conditions: []management.ClusterCondition{{Type: "Updated", Status: "Unknown"}},
+			status:     `{"clusterName":"c-abcde","conditions":[{"type":"Updated","status":"True"}]}`},
+	} {
+		status := &provv1.ClusterStatus{}
+		if err := json.Unmarshal([]byte(tc.status), status); err != nil {
+			t.Fatal(err)
+		}
+		cluster := &management.Cluster{State: tc.state, Conditions: tc.conditions}
+		cluster.ID = "c-abcde"
+		if problems := provisioningClusterProblems(cluster, status); len(problems) != tc.problems {
+			t.Errorf("%s: provisioningClusterProblems() = %v, want %d problems", tc.name, problems, tc.problems)
+		}
+	}
+}
+
+func TestProvisioningStateProblems(t *testing.T) {
+	active := &management.Cluster{State: "active"}
+	for _, tc := range []struct {
+		name     string
+		cluster  *management.Cluster
+		state    *steveV1.State
+		problems int
+	}{
+		{name: "active", cluster: active, state: &steveV1.State{Name: "active"}},
+		{name: "no state", cluster: active, problems: 1},
+		{name: "error", cluster: active, state: &steveV1.State{Name: "active", Error: true, Message: "cluster health check failed"}, problems: 1},
+		{name: "updating", cluster: active, state: &steveV1.State{Name: "updating", Transitioning: true}, problems: 1},
+		{name: "provisioning", cluster: &management.Cluster{State: "provisioning"}, state: &steveV1.State{Name: "provisioning", Transitioning: true}},
+	} {
+		if problems := provisioningStateProblems(tc.cluster, tc.state); len(problems) != tc.problems {
+			t.Errorf("%s: provisioningStateProblems() = %v, want %d problems", tc.name, problems, tc.problems)
+		}
+	}
+}
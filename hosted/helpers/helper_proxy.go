@@ -0,0 +1,76 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+)
+
+// squidContainer is the name of the local squid proxy container started by InstallK3S
+const squidContainer = "squid_proxy"
+
+// IsRancherBehindProxy returns true if rancher has been installed behind the proxy (RANCHER_BEHIND_PROXY=enabled)
+func IsRancherBehindProxy() bool {
+	return RancherBehindProxy == "enabled"
+}
+
+// getDeploymentEnv returns the env vars of the first container of a deployment in cattle-system
+func getDeploymentEnv(deployment string) (map[string]string, error) {
+	out, err := kubectl.RunWithoutErr("get", "deployment", deployment, "--namespace", CattleSystemNS,
+		"-o", `jsonpath={range .spec.template.spec.containers[0].env[*]}{.name}={.value}{"\n"}{end}`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the env of deployment %s: %v: %s", deployment, err, out)
+	}
+	env := map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		if name, value, found := strings.Cut(line, "="); found {
+			env[name] = value
+		}
+	}
+	return env, nil
+}
+
+// CheckProxyConfiguration checks that rancher and the provider operator are configured with the proxy (PROXY_HOST) and the no proxy list (RANCHER_NO_PROXY)
+func CheckProxyConfiguration() {
+	for _, deployment := range []string{"rancher", fmt.Sprintf("%s-config-operator", Provider)} {
+		ginkgo.By(fmt.Sprintf("checking the proxy configuration of deployment %s", deployment), func() {
+			EventuallyWithBackoff(func() (map[string]string, error) {
+				return getDeploymentEnv(deployment)
+			}, tools.SetTimeout(5*time.Minute), 10*time.Second).Should(SatisfyAll(
+				HaveKeyWithValue("HTTP_PROXY", "http://"+ProxyHost),
+				HaveKeyWithValue("HTTPS_PROXY", "http://"+ProxyHost),
+				HaveKeyWithValue("NO_PROXY", NoProxy),
+			))
+		})
+	}
+}
+
+// providerAPIHost returns the host of the cloud provider API called by the provider operator
+func providerAPIHost() string {
+	switch Provider {
+	case "eks":
+		return fmt.Sprintf("eks.%s.amazonaws.com", GetEKSRegion())
+	case "gke":
+		return "container.googleapis.com"
+	case "aks":
+		return "management.azure.com"
+	}
+	return ""
+}
+
+// CheckOperatorTrafficThroughProxy checks that the calls of the provider operator to the cloud provider API go through the local squid proxy,
+// by looking for the provider API host in the proxy access log
+func CheckOperatorTrafficThroughProxy() {
+	apiHost := providerAPIHost()
+	Expect(apiHost).ToNot(BeEmpty(), "unsupported provider %q", Provider)
+
+	EventuallyWithBackoff(func() (string, error) {
+		return extcli.Docker.Output("exec", squidContainer, "cat", "/var/log/squid/access.log")
+	}, tools.SetTimeout(5*time.Minute), 30*time.Second).Should(ContainSubstring(apiHost), "%s has not been reached through the proxy", apiHost)
+}
@@ -0,0 +1,174 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	shepherdclusters "github.com/rancher/shepherd/extensions/clusters"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Pod Security Admission configuration templates shipped with Rancher
+const (
+	RancherPrivilegedPSACT = "rancher-privileged"
+	RancherRestrictedPSACT = "rancher-restricted"
+)
+
+const (
+	// psaLabelPrefix is the prefix of the namespace labels read by the Pod Security Admission controller of kubernetes
+	psaLabelPrefix = "pod-security.kubernetes.io/"
+	// psaLatestVersion is the policy version used when the template does not set one
+	psaLatestVersion = "latest"
+	// psaViolationMessage is part of the error returned by the kube API server when a pod is rejected by Pod Security Admission
+	psaViolationMessage = "violates PodSecurity"
+	// podSteveType is the Steve type of the pods
+	podSteveType = "pod"
+)
+
+// psaNamespaceLabels returns the Pod Security Admission labels enforcing the defaults of the template on a namespace,
+// for e.g. pod-security.kubernetes.io/enforce=restricted; the modes without level are left to the defaults of the API server.
+func psaNamespaceLabels(template *v3.PodSecurityAdmissionConfigurationTemplate) map[string]string {
+	defaults := template.Configuration.Defaults
+	labels := map[string]string{}
+	for _, mode := range []struct{ name, level, version string }{
+		{"enforce", defaults.Enforce, defaults.EnforceVersion},
+		{"audit", defaults.Audit, defaults.AuditVersion},
+		{"warn", defaults.Warn, defaults.WarnVersion},
+	} {
+		if mode.level == "" {
+			continue
+		}
+		if mode.version == "" {
+			mode.version = psaLatestVersion
+		}
+		labels[psaLabelPrefix+mode.name] = mode.level
+		labels[psaLabelPrefix+mode.name+"-version"] = mode.version
+	}
+	return labels
+}
+
+// newPrivilegedPod returns a pod violating every Pod Security Standard but privileged: a privileged container running as root
+func newPrivilegedPod(namespace string) *corev1.Pod {
+	privileged := true
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: namegen.AppendRandomString("hp-psa"), Namespace: namespace},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:            "nginx",
+				Image:           "nginx",
+				SecurityContext: &corev1.SecurityContext{Privileged: &privileged},
+			}},
+		},
+	}
+}
+
+/*
+Get a Pod Security Admission configuration template of Rancher
+  - @param client Rancher client
+  - @param name Name of the template, for e.g. RancherRestrictedPSACT
+  - @returns The template; the function will fail through Ginkgo in case of issue
+*/
+func GetPSACT(client *rancher.Client, name string) *v3.PodSecurityAdmissionConfigurationTemplate {
+	object, err := client.Steve.SteveType(shepherdclusters.PodSecurityAdmissionSteveResoureType).ByID(name)
+	Expect(err).To(BeNil())
+	template := &v3.PodSecurityAdmissionConfigurationTemplate{}
+	Expect(steveV1.ConvertToK8sType(object.JSONResp, template)).To(Succeed())
+	return template
+}
+
+/*
+Apply a Pod Security Admission configuration template of Rancher to a new namespace of a downstream cluster; the namespace is deleted once the spec is done.
+Rancher only sets the admission configuration of the API server of the RKE2/K3s clusters, the one of the hosted clusters is managed by the cloud
+provider, so the defaults of the template are applied with the Pod Security Admission labels of the namespace instead.
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @param name Name of the template, for e.g. RancherRestrictedPSACT
+  - @returns The name of the namespace; the function will fail through Ginkgo in case of issue
+*/
+func CreatePSANamespace(client *rancher.Client, cluster *management.Cluster, name string) string {
+	template := GetPSACT(client, name)
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: namegen.AppendRandomString("hp-psa"), Labels: psaNamespaceLabels(template)},
+	}
+
+	steveClient, err := client.Steve.ProxyDownstream(cluster.ID)
+	Expect(err).To(BeNil())
+	ginkgo.By(fmt.Sprintf("Creating namespace %s with the defaults of template %s on cluster %s", namespace.Name, name, cluster.Name), func() {
+		_, err = steveClient.SteveType("namespace").Create(namespace)
+		Expect(err).To(BeNil())
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Created namespace %s with labels %v", namespace.Name, namespace.Labels))
+	})
+	ginkgo.DeferCleanup(deletePSANamespace, steveClient, namespace.Name)
+	return namespace.Name
+}
+
+// deletePSANamespace deletes a namespace of a downstream cluster, if it still exists
+func deletePSANamespace(steveClient *steveV1.Client, namespace string) {
+	current, err := steveClient.SteveType("namespace").ByID(namespace)
+	if err != nil && strings.Contains(err.Error(), "not found") {
+		return
+	}
+	Expect(err).To(BeNil())
+	Expect(steveClient.SteveType("namespace").Delete(current)).To(Succeed())
+}
+
+/*
+Check that Pod Security Admission is enforced on a namespace of a downstream cluster: a privileged pod must be rejected with a PodSecurity violation
+if allowed is false, and be admitted otherwise; an admitted pod is deleted right away.
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @param namespace Namespace, for e.g. from CreatePSANamespace
+  - @param allowed Whether privileged pods are expected to be admitted in the namespace
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckPrivilegedPodAdmission(client *rancher.Client, cluster *management.Cluster, namespace string, allowed bool) {
+	steveClient, err := client.Steve.ProxyDownstream(cluster.ID)
+	Expect(err).To(BeNil())
+
+	ginkgo.By(fmt.Sprintf("Checking the admission of a privileged pod in namespace %s: allowed=%t", namespace, allowed), func() {
+		// the service account of a new namespace must exist for a pod to be admitted
+		EventuallyWithBackoff(func() error {
+			pod, err := steveClient.SteveType(podSteveType).Create(newPrivilegedPod(namespace))
+			if err == nil {
+				_ = steveClient.SteveType(podSteveType).Delete(pod)
+				if !allowed {
+					return StopTrying(fmt.Sprintf("Privileged pod %s was admitted in namespace %s", pod.Name, namespace))
+				}
+				return nil
+			}
+			if strings.Contains(err.Error(), psaViolationMessage) {
+				if allowed {
+					return StopTrying(fmt.Sprintf("Privileged pod was rejected in namespace %s", namespace)).Wrap(err)
+				}
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Privileged pod was rejected in namespace %s as expected: %v", namespace, err))
+				return nil
+			}
+			return err
+		}, tools.SetTimeout(2*time.Minute), 5*time.Second).Should(Succeed())
+	})
+}
+
+/*
+Check the enforcement of the Pod Security Admission configuration templates of Rancher on a downstream cluster: a privileged pod must be rejected
+in a namespace with the defaults of RancherRestrictedPSACT, and admitted in a namespace with the defaults of RancherPrivilegedPSACT
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckPSADefaults(client *rancher.Client, cluster *management.Cluster) {
+	restricted := CreatePSANamespace(client, cluster, RancherRestrictedPSACT)
+	CheckPrivilegedPodAdmission(client, cluster, restricted, false)
+
+	privileged := CreatePSANamespace(client, cluster, RancherPrivilegedPSACT)
+	CheckPrivilegedPodAdmission(client, cluster, privileged, true)
+}
@@ -0,0 +1,38 @@
+package helpers
+
+import (
+	"maps"
+	"testing"
+
+	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+)
+
+func TestPSANamespaceLabels(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		defaults v3.PodSecurityAdmissionConfigurationTemplateDefaults
+		want     map[string]string
+	}{
+		{name: "empty", want: map[string]string{}},
+		{
+			name:     "restricted",
+			defaults: v3.PodSecurityAdmissionConfigurationTemplateDefaults{Enforce: "restricted", Audit: "restricted", Warn: "restricted"},
+			want: map[string]string{
+				"pod-security.kubernetes.io/enforce": "restricted", "pod-security.kubernetes.io/enforce-version": "latest",
+				"pod-security.kubernetes.io/audit": "restricted", "pod-security.kubernetes.io/audit-version": "latest",
+				"pod-security.kubernetes.io/warn": "restricted", "pod-security.kubernetes.io/warn-version": "latest",
+			},
+		},
+		{
+			name:     "enforce only with version",
+			defaults: v3.PodSecurityAdmissionConfigurationTemplateDefaults{Enforce: "baseline", EnforceVersion: "v1.30"},
+			want:     map[string]string{"pod-security.kubernetes.io/enforce": "baseline", "pod-security.kubernetes.io/enforce-version": "v1.30"},
+		},
+	} {
+		template := &v3.PodSecurityAdmissionConfigurationTemplate{}
+		template.Configuration.Defaults = tc.defaults
+		if got := psaNamespaceLabels(template); !maps.Equal(got, tc.want) {
+			t.Errorf("%s: psaNamespaceLabels() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
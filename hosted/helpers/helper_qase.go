@@ -0,0 +1,622 @@
+package helpers
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antihax/optional"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+	qase "go.qase.io/client"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/qasecases"
+)
+
+// qaseAutoRun is the QASE_RUN_ID value creating the Qase run of the test run, or reusing the one created by a previous suite of the same run
+const qaseAutoRun = "auto"
+
+// qaseMaxAttachmentSize is the size limit of a Qase attachment; bigger attachments are skipped
+const qaseMaxAttachmentSize = 32 << 20
+
+// qaseBulkSize is the maximum number of spec results submitted to Qase per request
+const qaseBulkSize = 100
+
+// qaseAttempts is the number of attempts of the Qase API requests, and qaseRetryInterval the initial wait between them
+const (
+	qaseAttempts      = 5
+	qaseRetryInterval = 10 * time.Second
+)
+
+// qaseRunsPageSize is the number of runs fetched per request when looking for the Qase run of the test run
+const qaseRunsPageSize = 100
+
+var (
+	qaseRunOnce sync.Once
+	qaseRunID   int32
+	qaseRunErr  error
+)
+
+// Title returns the title of the Qase run; the suites of a test run share the same title, and thus the same Qase run
+func (m *RunMetadata) Title() string {
+	return fmt.Sprintf("%s - rancher %s - run %s", strings.Join(m.Providers, ","), m.RancherVersion, m.RunID)
+}
+
+// Description returns the description of the Qase run, one line per metadata
+func (m *RunMetadata) Description() string {
+	lines := []string{"Ginkgo automated run of the hosted providers e2e tests"}
+	add := func(name, value string) {
+		if value != "" {
+			lines = append(lines, fmt.Sprintf("%s: %s", name, value))
+		}
+	}
+	add("Providers", strings.Join(m.Providers, ", "))
+	add("Locations", joinByProvider(m.Locations))
+	add("Rancher version", m.RancherVersion)
+	add("Rancher server version", m.RancherServerVersion)
+	add("Operator chart versions", joinByProvider(m.OperatorChartVersions))
+	add("Upstream k8s version", m.UpstreamK8sVersion)
+	add("Downstream k8s version", m.DownstreamK8sVersion)
+	add("CLI versions", joinByProvider(m.CLIVersions))
+	add("Git SHA", m.GitSHA)
+	add("Run ID", m.RunID)
+	add("Pipeline", m.PipelineURL)
+	return strings.Join(lines, "\n")
+}
+
+// Tags returns the tags of the Qase run, to filter the runs by provider and version
+func (m *RunMetadata) Tags() []string {
+	tags := append([]string{}, m.Providers...)
+	if m.RancherVersion != "" {
+		tags = append(tags, "rancher-"+m.RancherVersion)
+	}
+	if m.DownstreamK8sVersion != "" {
+		tags = append(tags, "k8s-"+m.DownstreamK8sVersion)
+	}
+	return tags
+}
+
+// joinByProvider returns the values as provider=value pairs (or cli=version for the CLI versions), sorted by key
+func joinByProvider(values map[string]string) string {
+	var pairs []string
+	for provider, value := range values {
+		if value != "" {
+			pairs = append(pairs, fmt.Sprintf("%s=%s", provider, value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+// newQaseClient returns a client of the Qase API authenticated with QASE_API_TOKEN
+func newQaseClient() *qase.APIClient {
+	cfg := qase.NewConfiguration()
+	cfg.AddDefaultHeader("Token", runConfig.QaseAPIToken)
+	return qase.NewAPIClient(cfg)
+}
+
+// findQaseRun returns the ID of the active Qase run with the given title, or 0 if there is none
+func findQaseRun(client *qase.APIClient, title string) (int32, error) {
+	for offset := int32(0); ; offset += qaseRunsPageSize {
+		runs, _, err := client.RunsApi.GetRuns(context.TODO(), runConfig.QaseProjectCode, &qase.RunsApiGetRunsOpts{
+			Limit:         optional.NewInt32(qaseRunsPageSize),
+			Offset:        optional.NewInt32(offset),
+			FiltersStatus: optional.NewString("active"),
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to list the Qase runs: %w", err)
+		}
+		if runs.Result == nil {
+			return 0, nil
+		}
+		for _, run := range runs.Result.Entities {
+			if run.Title == title {
+				return int32(run.Id), nil
+			}
+		}
+		if len(runs.Result.Entities) < qaseRunsPageSize {
+			return 0, nil
+		}
+	}
+}
+
+// createQaseRun creates the Qase run of the test run, annotated with its metadata
+func createQaseRun(client *qase.APIClient, metadata *RunMetadata) (int32, error) {
+	response, _, err := client.RunsApi.CreateRun(context.TODO(), qase.RunCreate{
+		Title:         metadata.Title(),
+		Description:   metadata.Description(),
+		IsAutotest:    true,
+		EnvironmentId: int64(runConfig.QaseEnvironmentID),
+		Tags:          metadata.Tags(),
+	}, runConfig.QaseProjectCode)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create the Qase run: %w", err)
+	}
+	if response.Result == nil {
+		return 0, fmt.Errorf("no ID returned for the Qase run %q", metadata.Title())
+	}
+	return int32(response.Result.Id), nil
+}
+
+// resolveQaseRun returns the ID of the Qase run of the test run, created if no suite of the run created it yet
+func resolveQaseRun() (int32, error) {
+	metadata := runMetadata
+	if metadata == nil {
+		metadata = newRunMetadata([]string{Provider})
+	}
+	client := newQaseClient()
+
+	id, err := findQaseRun(client, metadata.Title())
+	if err != nil || id > 0 {
+		return id, err
+	}
+	if id, err = createQaseRun(client, metadata); err != nil {
+		return 0, err
+	}
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Created the Qase run %d %q", id, metadata.Title()))
+	// the ID is needed to publish or complete the run once all the suites are done, for e.g. with hosted/helpers/qase
+	if err = os.MkdirAll(ArtifactsDir, 0o755); err == nil {
+		err = os.WriteFile(filepath.Join(ArtifactsDir, "qase-run-id"), []byte(strconv.Itoa(int(id))), 0o644)
+	}
+	if err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to write the Qase run ID: %v", err))
+	}
+	return id, nil
+}
+
+// currentQaseRun returns the ID of the Qase run the results are reported to, resolved once per process
+func currentQaseRun() (int32, error) {
+	qaseRunOnce.Do(func() {
+		if runConfig.QaseRunID == qaseAutoRun {
+			qaseRunID, qaseRunErr = resolveQaseRun()
+			return
+		}
+		id, err := strconv.ParseInt(runConfig.QaseRunID, 10, 32)
+		qaseRunID, qaseRunErr = int32(id), err
+	})
+	return qaseRunID, qaseRunErr
+}
+
+// qaseResultStatus returns the Qase status of the result of a spec in the given state; a skipped spec, which did not run, is skipped,
+// while a pending spec, which is never executed until it is enabled in the code, is blocked
+func qaseResultStatus(state types.SpecState) string {
+	switch {
+	case state.Is(types.SpecStateFailureStates):
+		return "failed"
+	case state == types.SpecStatePassed:
+		return "passed"
+	case state == types.SpecStatePending:
+		return "blocked"
+	case state == types.SpecStateSkipped:
+		return "skipped"
+	}
+	return "invalid"
+}
+
+// qaseResultComment returns the comment of the Qase result of a spec: the failure message of a failed spec, and why a spec did not run,
+// so that the specs skipped while running, for e.g. by SkipUpgradeTests, can be told apart from the pending specs which are never executed.
+// With FLAKE_ATTEMPTS, the specs passing on retry are flagged as such, and the failed ones state they failed on every attempt.
+// The known issues matched by a failure (see annotateKnownIssues) are stated first.
+func qaseResultComment(report ginkgo.SpecReport) string {
+	if knownIssues := specKnownIssues(report); report.Failed() && len(knownIssues) > 0 {
+		return "Known issue: " + strings.Join(knownIssues, ", ") + "\n" + qaseFailureComment(report)
+	}
+	switch {
+	case report.Failed():
+		return qaseFailureComment(report)
+	case passedOnRetry(report):
+		return fmt.Sprintf("Passed on retry, at attempt %d of %d: flaky", report.NumAttempts, report.MaxFlakeAttempts)
+	case report.State == types.SpecStateSkipped:
+		return fmt.Sprintf("Not run, skipped at %s: %s", report.Failure.Location, report.Failure.Message)
+	case report.State == types.SpecStatePending:
+		return fmt.Sprintf("Never executed, the spec is pending at %s", report.LeafNodeLocation)
+	}
+	return ""
+}
+
+// qaseFailureComment returns the failure message of a failed spec, stating whether it failed on every attempt with FLAKE_ATTEMPTS
+func qaseFailureComment(report ginkgo.SpecReport) string {
+	comment := report.Failure.Message
+	if report.NumAttempts > 1 {
+		comment = fmt.Sprintf("Failed on all the %d attempts: %s", report.NumAttempts, report.Failure.Message)
+	}
+	if id := specCorrelationID(report); id != "" {
+		comment += "\nCorrelation ID: " + id
+	}
+	return comment
+}
+
+// writeSpecLog writes the output of a spec captured by Ginkgo, along with its failure, to spec.log in the given directory
+func writeSpecLog(report ginkgo.SpecReport, dir string) (string, error) {
+	var content strings.Builder
+	fmt.Fprintf(&content, "%s\n%s after %s\n\n", report.FullText(), report.State, report.RunTime.Round(time.Second))
+	content.WriteString(report.CapturedGinkgoWriterOutput)
+	content.WriteString(report.CapturedStdOutErr)
+	if report.Failed() {
+		fmt.Fprintf(&content, "\n%s\n%s\n", report.Failure.Message, report.Failure.Location.String())
+	}
+	path := filepath.Join(dir, "spec.log")
+	return path, os.WriteFile(path, []byte(content.String()), 0o644)
+}
+
+// archiveSupportBundle archives the artifact directory of a failed spec, support bundle included, to support-bundle.tar.gz in the directory;
+// the kubeconfigs of the downstream clusters are left out, since the Qase results may be published.
+func archiveSupportBundle(dir string) (string, error) {
+	path := filepath.Join(dir, "support-bundle.tar.gz")
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	err = filepath.Walk(dir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() || filePath == path || strings.HasSuffix(filePath, ".kubeconfig") {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		if header.Name, err = filepath.Rel(dir, filePath); err != nil {
+			return err
+		}
+		if err = tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		content, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer content.Close()
+		// the operator logs are still being streamed, only the size read by the header is archived
+		_, err = io.CopyN(tarWriter, content, header.Size)
+		return err
+	})
+	if err == nil {
+		err = tarWriter.Close()
+	}
+	if err == nil {
+		err = gzipWriter.Close()
+	}
+	return path, err
+}
+
+// uploadQaseAttachment uploads a file to the Qase project and returns its hash; the upload API of the Qase client does not send the file content.
+func uploadQaseAttachment(cfg *qase.Configuration, path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filepath.Base(path))
+	if err != nil {
+		return "", err
+	}
+	if _, err = io.Copy(part, file); err != nil {
+		return "", err
+	}
+	if err = writer.Close(); err != nil {
+		return "", err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/attachment/%s", cfg.BasePath, runConfig.QaseProjectCode), &body)
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	request.Header.Set("Token", runConfig.QaseAPIToken)
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	var uploads qase.AttachmentUploadsResponse
+	if err = json.NewDecoder(response.Body).Decode(&uploads); err != nil {
+		return "", fmt.Errorf("failed to decode the response of the upload, status %s: %w", response.Status, err)
+	}
+	if response.StatusCode != http.StatusOK || !uploads.Status || len(uploads.Result) == 0 {
+		return "", fmt.Errorf("upload failed with status %s", response.Status)
+	}
+	return uploads.Result[0].Hash, nil
+}
+
+// qaseAttachmentFiles writes the log of a spec, and the archive of its support bundle if it failed, and returns their paths;
+// the files that can not be written, or are too big to be attached, are logged and left out.
+func qaseAttachmentFiles(report ginkgo.SpecReport) []string {
+	dir, err := ArtifactDir(report)
+	if err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to create the artifact directory, no Qase attachment: %v", err))
+		return nil
+	}
+	var paths []string
+	path, err := writeSpecLog(report, dir)
+	if err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to write the spec log: %v", err))
+	} else {
+		paths = append(paths, path)
+	}
+	if report.Failed() {
+		if path, err = archiveSupportBundle(dir); err != nil {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to archive the support bundle: %v", err))
+		} else {
+			paths = append(paths, path)
+		}
+	}
+
+	var attachments []string
+	for _, path := range paths {
+		if info, err := os.Stat(path); err != nil || info.Size() > qaseMaxAttachmentSize {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Skipping the Qase attachment %s, bigger than %d bytes or not readable: %v", path, qaseMaxAttachmentSize, err))
+			continue
+		}
+		attachments = append(attachments, path)
+	}
+	return attachments
+}
+
+// qaseLabelPrefix is the prefix of the labels holding the Qase case IDs of a spec, for e.g. Label("qase:131")
+const qaseLabelPrefix = "qase:"
+
+// QaseLabel returns the labels of the Qase cases covered by a spec, It("...", helpers.QaseLabel(qasecases.EKSP1...), func() { ... }); the case IDs
+// are the generated constants of qasecases, so that a case retired from Qase fails to compile, rather than literal qase:<ID> labels.
+func QaseLabel(ids ...qasecases.CaseID) ginkgo.Labels {
+	caseIDs := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		caseIDs = append(caseIDs, int64(id))
+	}
+	return qaseLabels(caseIDs)
+}
+
+// qaseLabels returns the qase:<ID> labels of the case IDs, for e.g. the ones of a Qase test plan
+func qaseLabels(ids []int64) ginkgo.Labels {
+	labels := ginkgo.Labels{}
+	for _, id := range ids {
+		labels = append(labels, fmt.Sprintf("%s%d", qaseLabelPrefix, id))
+	}
+	return labels
+}
+
+// qaseCaseIDs returns the Qase case IDs of the qase:<ID> labels of a spec, in order and without duplicates, along with the labels whose ID is not valid
+func qaseCaseIDs(labels []string) (ids []int64, invalid []string) {
+	seen := map[int64]bool{}
+	for _, label := range labels {
+		value, found := strings.CutPrefix(label, qaseLabelPrefix)
+		if !found {
+			continue
+		}
+		id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || id <= 0 {
+			invalid = append(invalid, label)
+			continue
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, invalid
+}
+
+// QaseResult is the result of a spec for its Qase cases, buffered by the parallel process until the end of the suite; see SubmitQaseResults
+type QaseResult struct {
+	Spec       string  `json:"spec"`
+	CaseIDs    []int64 `json:"caseIDs"`
+	Status     string  `json:"status"`
+	TimeMs     int64   `json:"timeMs"`
+	Comment    string  `json:"comment,omitempty"`
+	Stacktrace string  `json:"stacktrace,omitempty"`
+	// Attachments are the paths of the files attached to the results, uploaded along with them
+	Attachments []string `json:"attachments,omitempty"`
+	// Signature is the failure signature of a failed spec a Qase defect is linked to with QASE_CREATE_DEFECTS, see linkQaseDefects
+	Signature string `json:"signature,omitempty"`
+	DefectID  int64  `json:"defectID,omitempty"`
+}
+
+// qaseResults are the results buffered by the current process
+var qaseResults []QaseResult
+
+// qaseResultsPath returns the path of the file the results of the current process are buffered in
+func qaseResultsPath() string {
+	return filepath.Join(ArtifactsDir, fmt.Sprintf("qase-results-p%d.json", ginkgo.GinkgoParallelProcess()))
+}
+
+// writeQaseResults writes the results to the given file, or removes it if there is none left
+func writeQaseResults(path string, results []QaseResult) error {
+	if len(results) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	content, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o644)
+}
+
+/*
+Buffer the result of a spec for the Qase cases of its labels, for e.g. It("...", Label("qase:131"), ...); a spec covering several cases has
+several labels, for e.g. Label("qase:240", "qase:241"). The log of the spec, and the archive of its artifact directory with the support bundle
+if it failed, are attached to the results; the specs skipped with Skip and the pending specs are reported with why they did not run. The results are submitted at the end of the suite by SubmitQaseResults. It must be called from the
+ReportAfterEach node of every suite:
+var _ = ReportAfterEach(func(report SpecReport) { helpers.ReportToQase(report) })
+Nothing is reported if QASE_RUN_ID is not set, the spec has no Qase label or it is left out by the filters; a failure to buffer the result
+is logged and does not fail the spec.
+  - @param report Report of the spec
+  - @returns Nothing
+*/
+func ReportToQase(report ginkgo.SpecReport) {
+	caseIDs, invalid := qaseCaseIDs(report.Labels())
+	if len(invalid) > 0 {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Ignoring the invalid Qase labels %v of spec %q; the expected format is qase:<case ID>", invalid, report.FullText()))
+	}
+	// the specs left out by the focus and label filters are skipped without a reason, they are not reported
+	skippedByFilter := report.State == types.SpecStateSkipped && report.Failure.IsZero()
+	if runConfig.QaseRunID == "" || len(caseIDs) == 0 || skippedByFilter {
+		return
+	}
+
+	result := QaseResult{
+		Spec:    report.FullText(),
+		CaseIDs: caseIDs,
+		Status:  qaseResultStatus(report.State),
+		TimeMs:  report.RunTime.Milliseconds(),
+		Comment: qaseResultComment(report),
+	}
+	if report.Failed() {
+		result.Stacktrace = report.Failure.Location.FullStackTrace
+		// the failures of a known issue are already triaged
+		if runConfig.QaseCreateDefects && len(specKnownIssues(report)) == 0 {
+			result.Signature = failureSignature(report.Failure.Location.String(), report.Failure.Message)
+		}
+	}
+	// the specs which did not run have nothing to attach
+	if report.State != types.SpecStateSkipped && report.State != types.SpecStatePending {
+		result.Attachments = qaseAttachmentFiles(report)
+	}
+	if qaseResults == nil {
+		// keep the results a previous suite using the same artifacts directory could not submit
+		for _, previous := range readArtifacts[[]QaseResult](filepath.Base(qaseResultsPath())) {
+			qaseResults = append(qaseResults, previous...)
+		}
+	}
+	qaseResults = append(qaseResults, result)
+	if err := writeQaseResults(qaseResultsPath(), qaseResults); err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to buffer the Qase result of spec %q: %v", report.FullText(), err))
+	}
+}
+
+// retryQase calls f until it succeeds, up to qaseAttempts times, waiting for an exponential backoff between the attempts
+func retryQase(operation string, f func() error) (err error) {
+	backoff := NewBackoff(qaseRetryInterval)
+	for attempt := 1; ; attempt++ {
+		if err = f(); err == nil || attempt == qaseAttempts {
+			return err
+		}
+		wait := backoff.Next()
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to %s, attempt %d/%d, retrying in %s: %v", operation, attempt, qaseAttempts, wait.Round(time.Second), err))
+		time.Sleep(wait)
+	}
+}
+
+// newQaseResultsBulk uploads the attachments of the results and returns the bulk of Qase results, one per case of every result;
+// the attachments that can not be uploaded are logged and left out.
+func newQaseResultsBulk(cfg *qase.Configuration, results []QaseResult) qase.ResultCreateBulk {
+	bulk := qase.ResultCreateBulk{}
+	for _, result := range results {
+		var hashes []string
+		for _, path := range result.Attachments {
+			var hash string
+			err := retryQase(fmt.Sprintf("upload the Qase attachment %s", path), func() (err error) {
+				hash, err = uploadQaseAttachment(cfg, path)
+				return err
+			})
+			if err != nil {
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to upload the Qase attachment %s: %v", path, err))
+				continue
+			}
+			hashes = append(hashes, hash)
+		}
+		for _, caseID := range result.CaseIDs {
+			bulk.Results = append(bulk.Results, qase.ResultCreate{
+				CaseId:      caseID,
+				Status:      result.Status,
+				TimeMs:      result.TimeMs,
+				Comment:     result.Comment,
+				Stacktrace:  result.Stacktrace,
+				Attachments: hashes,
+			})
+		}
+	}
+	return bulk
+}
+
+/*
+Submit the Qase results buffered by all the parallel processes (see ReportToQase) to the Qase run of QASE_RUN_ID, in bulks of qaseBulkSize
+results retried with an exponential backoff; when QASE_RUN_ID is auto, the run is created with the environment of the run (see RecordRunMetadata),
+or reused if a previous suite of the same run created it. It must be called from the ReportAfterSuite node of every suite, which runs once all
+the processes are done:
+var _ = ReportAfterSuite("Reports", func(report Report) { helpers.SubmitQaseResults() })
+With QASE_CREATE_DEFECTS, the new failures are linked to a Qase defect, deduplicated by failure signature, see linkQaseDefects.
+The results that can not be submitted are kept in the artifacts directory, and submitted along with the results of the next suite using it.
+Failing to submit the results is logged and does not fail the suite.
+  - @returns Nothing
+*/
+func SubmitQaseResults() {
+	if runConfig.QaseRunID == "" {
+		return
+	}
+	paths, _ := filepath.Glob(filepath.Join(ArtifactsDir, "qase-results-p*.json"))
+	if len(paths) == 0 {
+		return
+	}
+	var runID int32
+	err := retryQase("get the Qase run", func() (err error) {
+		runID, err = currentQaseRun()
+		if err != nil {
+			// a failed resolution is not cached, so that it can be retried
+			qaseRunOnce = sync.Once{}
+		}
+		return err
+	})
+	if err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to get the Qase run, the results are kept in %s: %v", ArtifactsDir, err))
+		return
+	}
+
+	cfg := qase.NewConfiguration()
+	cfg.AddDefaultHeader("Token", runConfig.QaseAPIToken)
+	client := qase.NewAPIClient(cfg)
+	for _, path := range paths {
+		results := readArtifacts[[]QaseResult](filepath.Base(path))
+		if len(results) == 0 {
+			continue
+		}
+		pending := results[0]
+		for len(pending) > 0 {
+			batch := pending[:min(qaseBulkSize, len(pending))]
+			linkQaseDefects(client, batch)
+			bulk := newQaseResultsBulk(cfg, batch)
+			err = retryQase(fmt.Sprintf("submit %d Qase results", len(bulk.Results)), func() error {
+				_, _, err := client.ResultsApi.CreateResultBulk(context.TODO(), bulk, runConfig.QaseProjectCode, runID)
+				return err
+			})
+			if err != nil {
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to submit the Qase results, %d results are kept in %s: %v", len(pending), path, err))
+				break
+			}
+			ginkgo.GinkgoWriter.Printf("%d Qase results submitted to run ID %d on project %s\n", len(bulk.Results), runID, runConfig.QaseProjectCode)
+			pending = pending[len(batch):]
+		}
+		if err = writeQaseResults(path, pending); err != nil {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to update %s: %v", path, err))
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package helpers
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2/types"
+	qase "go.qase.io/client"
+)
+
+func TestQaseRunMetadata(t *testing.T) {
+	metadata := &RunMetadata{
+		Providers:             []string{"gke", "eks"},
+		Locations:             map[string]string{"gke": "asia-south2-c", "eks": "ap-south-1"},
+		RancherVersion:        "latest/2.10.3",
+		RancherServerVersion:  "v2.10.3",
+		OperatorChartVersions: map[string]string{"eks": "105.1.0+up1.6.1", "gke": ""},
+		DownstreamK8sVersion:  "1.31",
+		RunID:                 "12345",
+	}
+	if title := metadata.Title(); title != "gke,eks - rancher latest/2.10.3 - run 12345" {
+		t.Errorf("Title() = %q", title)
+	}
+
+	description := metadata.Description()
+	for _, line := range []string{
+		"Locations: eks=ap-south-1, gke=asia-south2-c",
+		"Rancher server version: v2.10.3",
+		"Operator chart versions: eks=105.1.0+up1.6.1",
+		"Downstream k8s version: 1.31",
+	} {
+		if !strings.Contains(description, line+"\n") {
+			t.Errorf("Description() = %q, want the line %q", description, line)
+		}
+	}
+	if strings.Contains(description, "Upstream k8s version") || strings.Contains(description, "Pipeline") {
+		t.Errorf("Description() = %q, want the empty metadata left out", description)
+	}
+
+	if tags := metadata.Tags(); !reflect.DeepEqual(tags, []string{"gke", "eks", "rancher-latest/2.10.3", "k8s-1.31"}) {
+		t.Errorf("Tags() = %v", tags)
+	}
+}
+
+func TestQaseResultStatus(t *testing.T) {
+	for state, expected := range map[types.SpecState]string{
+		types.SpecStatePassed:      "passed",
+		types.SpecStateFailed:      "failed",
+		types.SpecStatePanicked:    "failed",
+		types.SpecStateTimedout:    "failed",
+		types.SpecStatePending:     "blocked",
+		types.SpecStateSkipped:     "skipped",
+		types.SpecStateInvalid:     "invalid",
+		types.SpecStateInterrupted: "failed",
+	} {
+		if status := qaseResultStatus(state); status != expected {
+			t.Errorf("qaseResultStatus(%s) = %s, want %s", state, status, expected)
+		}
+	}
+}
+
+func TestValidateQaseRunID(t *testing.T) {
+	for runID, valid := range map[string]bool{"": true, "auto": true, "42": true, "0": false, "latest": false} {
+		c := &RunConfig{QaseRunID: runID, QaseAPIToken: "token", QaseProjectCode: "HP"}
+		problems := strings.Join(c.Validate(SuiteCommon), "\n")
+		if strings.Contains(problems, "QASE_RUN_ID") == valid {
+			t.Errorf("Validate() with QASE_RUN_ID %q = %q, want valid=%t", runID, problems, valid)
+		}
+	}
+	c := &RunConfig{QaseRunID: "auto"}
+	if problems := strings.Join(c.Validate(SuiteCommon), "\n"); !strings.Contains(problems, "QASE_API_TOKEN") {
+		t.Errorf("Validate() without QASE_API_TOKEN = %q, want a problem", problems)
+	}
+}
+
+func TestArchiveSupportBundle(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range []string{"rancher.log", "failure.txt", "eks-hp-ci-abcde.kubeconfig", "spec.log"} {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(file), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path, err := archiveSupportBundle(dir)
+	if err != nil {
+		t.Fatalf("archiveSupportBundle() = %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tarReader := tar.NewReader(gzipReader)
+	var names []string
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(tarReader)
+		if string(content) != header.Name {
+			t.Errorf("content of %s = %q", header.Name, content)
+		}
+		names = append(names, header.Name)
+	}
+	sort.Strings(names)
+	if !reflect.DeepEqual(names, []string{"failure.txt", "rancher.log", "spec.log"}) {
+		t.Errorf("archiveSupportBundle() archived %v, want the kubeconfig and the archive left out", names)
+	}
+}
+
+func TestUploadQaseAttachment(t *testing.T) {
+	defer func(code, token string) { runConfig.QaseProjectCode, runConfig.QaseAPIToken = code, token }(runConfig.QaseProjectCode, runConfig.QaseAPIToken)
+	runConfig.QaseProjectCode, runConfig.QaseAPIToken = "HP", "secret"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		if r.URL.Path != "/attachment/HP" || r.Header.Get("Token") != "secret" || err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		content, _ := io.ReadAll(file)
+		if header.Filename != "spec.log" || string(content) != "spec output" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status": true, "result": [{"hash": "6a1b2c", "filename": "spec.log"}]}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "spec.log")
+	if err := os.WriteFile(path, []byte("spec output"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := uploadQaseAttachment(&qase.Configuration{BasePath: server.URL}, path)
+	if err != nil || hash != "6a1b2c" {
+		t.Errorf("uploadQaseAttachment() = %q, %v, want 6a1b2c", hash, err)
+	}
+
+	runConfig.QaseAPIToken = "revoked"
+	if _, err = uploadQaseAttachment(&qase.Configuration{BasePath: server.URL}, path); err == nil {
+		t.Errorf("uploadQaseAttachment() with a rejected token returned no error")
+	}
+}
+
+func TestQaseCaseIDs(t *testing.T) {
+	labels := append([]string{"p1", "qase:240", "qase: 241", "qase:abc", "qase:0", "qase:240"}, QaseLabel(102, 103)...)
+	ids, invalid := qaseCaseIDs(labels)
+	if !reflect.DeepEqual(ids, []int64{240, 241, 102, 103}) {
+		t.Errorf("qaseCaseIDs() = %v, want [240 241 102 103]", ids)
+	}
+	if !reflect.DeepEqual(invalid, []string{"qase:abc", "qase:0"}) {
+		t.Errorf("qaseCaseIDs() invalid = %v, want [qase:abc qase:0]", invalid)
+	}
+	if ids, invalid = qaseCaseIDs([]string{"p0", "import"}); ids != nil || invalid != nil {
+		t.Errorf("qaseCaseIDs() without Qase label = %v, %v, want none", ids, invalid)
+	}
+}
+
+// TestSpecsUseQaseCases checks that the specs reference their Qase cases with the generated constants of qasecases, see QaseLabel
+func TestSpecsUseQaseCases(t *testing.T) {
+	literal := regexp.MustCompile(`"qase:\d+"`)
+	err := filepath.WalkDir("..", func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || !strings.HasSuffix(path, "_test.go") || strings.HasPrefix(path, filepath.Join("..", "helpers")) {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, label := range literal.FindAllString(string(content), -1) {
+			t.Errorf("%s: label %s must be helpers.QaseLabel(qasecases.<case>)", path, label)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReportToQaseBuffersResults(t *testing.T) {
+	defer func(dir, runID string, results []QaseResult) {
+		ArtifactsDir, runConfig.QaseRunID, qaseResults = dir, runID, results
+	}(ArtifactsDir, runConfig.QaseRunID, qaseResults)
+	ArtifactsDir, runConfig.QaseRunID, qaseResults = t.TempDir(), "42", nil
+
+	// results left by a previous suite
+	if err := writeQaseResults(qaseResultsPath(), []QaseResult{{Spec: "previous", CaseIDs: []int64{1}, Status: "passed"}}); err != nil {
+		t.Fatal(err)
+	}
+	ReportToQase(types.SpecReport{LeafNodeText: "filtered", LeafNodeLabels: []string{"qase:2"}, State: types.SpecStateSkipped})
+	ReportToQase(types.SpecReport{LeafNodeText: "no case", State: types.SpecStatePassed})
+	ReportToQase(types.SpecReport{LeafNodeText: "pending", LeafNodeLabels: []string{"qase:5"}, State: types.SpecStatePending})
+	ReportToQase(types.SpecReport{
+		LeafNodeText:   "failed",
+		LeafNodeLabels: []string{"qase:3", "qase:4"},
+		State:          types.SpecStateFailed,
+		Failure:        types.Failure{Message: "boom"},
+	})
+
+	results := readArtifacts[[]QaseResult](filepath.Base(qaseResultsPath()))
+	if len(results) != 1 || len(results[0]) != 3 {
+		t.Fatalf("ReportToQase() buffered %+v, want the previous, pending and failed results", results)
+	}
+	if pending := results[0][1]; pending.Status != "blocked" || len(pending.Attachments) != 0 {
+		t.Errorf("ReportToQase() buffered %+v, want a blocked result without attachment", pending)
+	}
+	failed := results[0][2]
+	if failed.Spec != "failed" || !reflect.DeepEqual(failed.CaseIDs, []int64{3, 4}) || failed.Status != "failed" || failed.Comment != "boom" {
+		t.Errorf("ReportToQase() buffered %+v", failed)
+	}
+	var attachments []string
+	for _, path := range failed.Attachments {
+		attachments = append(attachments, filepath.Base(path))
+	}
+	if !reflect.DeepEqual(attachments, []string{"spec.log", "support-bundle.tar.gz"}) {
+		t.Errorf("ReportToQase() attachments = %v, want the spec log and the support bundle", attachments)
+	}
+
+	if err := writeQaseResults(qaseResultsPath(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(qaseResultsPath()); !os.IsNotExist(err) {
+		t.Errorf("writeQaseResults() without results kept the file: %v", err)
+	}
+}
+
+func TestNewQaseResultsBulk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status": true, "result": [{"hash": "6a1b2c"}]}`))
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "spec.log")
+	if err := os.WriteFile(path, []byte("spec output"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	bulk := newQaseResultsBulk(&qase.Configuration{BasePath: server.URL}, []QaseResult{
+		{CaseIDs: []int64{240, 241}, Status: "failed", TimeMs: 1000, Comment: "boom", Attachments: []string{path}},
+		{CaseIDs: []int64{131}, Status: "passed"},
+	})
+	expected := []qase.ResultCreate{
+		{CaseId: 240, Status: "failed", TimeMs: 1000, Comment: "boom", Attachments: []string{"6a1b2c"}},
+		{CaseId: 241, Status: "failed", TimeMs: 1000, Comment: "boom", Attachments: []string{"6a1b2c"}},
+		{CaseId: 131, Status: "passed"},
+	}
+	if !reflect.DeepEqual(bulk.Results, expected) {
+		t.Errorf("newQaseResultsBulk() = %+v, want %+v", bulk.Results, expected)
+	}
+}
+
+func TestQaseResultComment(t *testing.T) {
+	location := types.CodeLocation{FileName: "p1_import_test.go", LineNumber: 77}
+	for _, test := range []struct {
+		report   types.SpecReport
+		expected string
+	}{
+		{types.SpecReport{State: types.SpecStatePassed}, ""},
+		{types.SpecReport{State: types.SpecStatePassed, NumAttempts: 2, MaxFlakeAttempts: 3}, "Passed on retry, at attempt 2 of 3: flaky"},
+		{types.SpecReport{State: types.SpecStateFailed, NumAttempts: 3, MaxFlakeAttempts: 3, Failure: types.Failure{Message: "boom"}}, "Failed on all the 3 attempts: boom"},
+		{types.SpecReport{State: types.SpecStateFailed, Failure: types.Failure{Message: "boom", Location: location}}, "boom"},
+		{
+			types.SpecReport{State: types.SpecStateSkipped, Failure: types.Failure{Message: SkipUpgradeTestsLog, Location: location}},
+			"Not run, skipped at p1_import_test.go:77: " + SkipUpgradeTestsLog,
+		},
+		{types.SpecReport{State: types.SpecStatePending, LeafNodeLocation: location}, "Never executed, the spec is pending at p1_import_test.go:77"},
+		{
+			types.SpecReport{State: types.SpecStateFailed, Failure: types.Failure{Message: "boom"}, ReportEntries: types.ReportEntries{
+				{Name: knownIssuesEntry, Value: types.WrapEntryValue("matches eks-operator#752 (https://github.com/rancher/eks-operator/issues/752)")},
+			}},
+			"Known issue: matches eks-operator#752 (https://github.com/rancher/eks-operator/issues/752)\nboom",
+		},
+		{
+			types.SpecReport{State: types.SpecStateFailed, Failure: types.Failure{Message: "boom"}, ReportEntries: types.ReportEntries{
+				{Name: correlationIDEntry, Value: types.WrapEntryValue("p2-3f2a9c0b1d4e")},
+			}},
+			"boom\nCorrelation ID: p2-3f2a9c0b1d4e",
+		},
+	} {
+		if comment := qaseResultComment(test.report); comment != test.expected {
+			t.Errorf("qaseResultComment() of a %s spec = %q, want %q", test.report.State, comment, test.expected)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package helpers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/antihax/optional"
+	"github.com/onsi/ginkgo/v2"
+	qase "go.qase.io/client"
+)
+
+// qaseDefectsPageSize is the number of open defects listed per request when looking for the defect of a failure signature
+const qaseDefectsPageSize = 100
+
+// qaseDefectSignaturePrefix prefixes the failure signature at the end of the title of the defects, for e.g. [e2e:3f2a9c0b1d4e]
+const qaseDefectSignaturePrefix = "e2e:"
+
+var (
+	// volatileFailureText matches the parts of a failure message changing from a run to another: the generated names, IDs, durations and addresses
+	volatileFailureText = regexp.MustCompile(`[0-9a-f]{8,}|\d+`)
+	// blankFailureText matches the sequences of blanks of a failure message
+	blankFailureText = regexp.MustCompile(`\s+`)
+)
+
+// failureSignature returns the signature of a failure, so that the same failure of different runs gets the same Qase defect:
+// the hash of its location and of its message, without the numbers and hexadecimal IDs
+func failureSignature(location, message string) string {
+	normalized := volatileFailureText.ReplaceAllString(strings.ToLower(message), "#")
+	normalized = strings.TrimSpace(blankFailureText.ReplaceAllString(normalized, " "))
+	sum := sha256.Sum256([]byte(location + "\n" + normalized))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// qaseDefectTitle returns the title of the defect of a failed spec, ending with its failure signature
+func qaseDefectTitle(spec, signature string) string {
+	if len(spec) > 200 {
+		spec = spec[:200]
+	}
+	return fmt.Sprintf("%s [%s%s]", spec, qaseDefectSignaturePrefix, signature)
+}
+
+// defectSignature returns the failure signature of the title of a defect created by the e2e tests, or an empty string
+func defectSignature(title string) string {
+	start := strings.LastIndex(title, "["+qaseDefectSignaturePrefix)
+	if start < 0 || !strings.HasSuffix(title, "]") {
+		return ""
+	}
+	return title[start+len(qaseDefectSignaturePrefix)+1 : len(title)-1]
+}
+
+// openQaseDefects returns the IDs of the open defects of QASE_PROJECT_CODE created by the e2e tests, by failure signature
+func openQaseDefects(client *qase.APIClient) (map[string]int64, error) {
+	defects := map[string]int64{}
+	for offset := int32(0); ; offset += qaseDefectsPageSize {
+		response, _, err := client.DefectsApi.GetDefects(context.TODO(), runConfig.QaseProjectCode, &qase.DefectsApiGetDefectsOpts{
+			Limit:         optional.NewInt32(qaseDefectsPageSize),
+			Offset:        optional.NewInt32(offset),
+			FiltersStatus: optional.NewString("open"),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the Qase defects: %w", err)
+		}
+		if response.Result == nil {
+			return defects, nil
+		}
+		for _, defect := range response.Result.Entities {
+			if signature := defectSignature(defect.Title); signature != "" {
+				defects[signature] = defect.Id
+			}
+		}
+		if len(response.Result.Entities) < qaseDefectsPageSize {
+			return defects, nil
+		}
+	}
+}
+
+// createQaseDefect opens the defect of a failed result
+func createQaseDefect(client *qase.APIClient, result QaseResult) (int64, error) {
+	response, _, err := client.DefectsApi.CreateDefect(context.TODO(), qase.DefectCreate{
+		Title: qaseDefectTitle(result.Spec, result.Signature),
+		ActualResult: fmt.Sprintf("%s\n\nSpec: %s\nCases: %v\nRun: %s %s", result.Comment, result.Spec, result.CaseIDs,
+			runConfig.RunID, runConfig.PipelineURL),
+		Tags: []string{"hosted-providers-e2e"},
+	}, runConfig.QaseProjectCode)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create the Qase defect: %w", err)
+	}
+	if response.Result == nil {
+		return 0, fmt.Errorf("no ID returned for the Qase defect of %q", result.Spec)
+	}
+	return response.Result.Id, nil
+}
+
+// linkQaseDefects sets the defect of every failed result with a signature, see ReportToQase: the open defect with the same failure signature
+// if there is one, a new defect otherwise; the defect is referenced in the comment of the result. The results keep their defect,
+// so that the results submitted again by the next suite do not open another one. Failing to list or create the defects is logged,
+// the results are submitted without defect.
+func linkQaseDefects(client *qase.APIClient, results []QaseResult) {
+	var defects map[string]int64
+	for i := range results {
+		result := &results[i]
+		if result.Signature == "" || result.DefectID != 0 {
+			continue
+		}
+		if defects == nil {
+			err := retryQase("list the open Qase defects", func() (err error) {
+				defects, err = openQaseDefects(client)
+				return err
+			})
+			if err != nil {
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to list the open Qase defects, the results are submitted without defect: %v", err))
+				return
+			}
+		}
+
+		id, found := defects[result.Signature]
+		if !found {
+			err := retryQase(fmt.Sprintf("create the Qase defect of %q", result.Spec), func() (err error) {
+				id, err = createQaseDefect(client, *result)
+				return err
+			})
+			if err != nil {
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to create the Qase defect of %q: %v", result.Spec, err))
+				continue
+			}
+			defects[result.Signature] = id
+			ginkgo.GinkgoWriter.Printf("Qase defect %d opened for the failure %s of %q\n", id, result.Signature, result.Spec)
+		}
+		result.DefectID = id
+		result.Comment += fmt.Sprintf("\nQase defect: %d (failure signature %s)", id, result.Signature)
+	}
+}
@@ -0,0 +1,85 @@
+package helpers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	qase "go.qase.io/client"
+)
+
+func TestFailureSignature(t *testing.T) {
+	location := "p1_provisioning_test.go:340"
+	signature := failureSignature(location, "Timed out after 600.001s.\nCluster auto-eks-hp-ci-12345 c-m-4b2f9d1a is not ready")
+	// the generated names, IDs and durations of another run do not change the signature
+	if other := failureSignature(location, "Timed out after 600.002s.\n  Cluster auto-eks-hp-ci-67890 c-m-9e8d7c6b is not ready"); other != signature {
+		t.Errorf("got signatures %s and %s for the same failure", signature, other)
+	}
+	if other := failureSignature(location, "Expected <bool>: false to be true"); other == signature {
+		t.Error("different failures must have different signatures")
+	}
+	if other := failureSignature("p1_provisioning_test.go:484", "Timed out after 600.001s.\nCluster auto-eks-hp-ci-12345 c-m-4b2f9d1a is not ready"); other == signature {
+		t.Error("failures at different locations must have different signatures")
+	}
+
+	title := qaseDefectTitle("P1Provisioning should upgrade", signature)
+	if got := defectSignature(title); got != signature {
+		t.Errorf("defectSignature(%q) = %q, want %q", title, got, signature)
+	}
+	if got := defectSignature("Defect opened manually"); got != "" {
+		t.Errorf("defectSignature() of a manual defect = %q", got)
+	}
+}
+
+func TestLinkQaseDefects(t *testing.T) {
+	defer func(code string) { runConfig.QaseProjectCode = code }(runConfig.QaseProjectCode)
+	runConfig.QaseProjectCode = "HP"
+
+	var created []qase.DefectCreate
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/defect/HP":
+			if r.URL.Query().Get("filters[status]") != "open" {
+				t.Errorf("only the open defects must be listed: %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{"status": true, "result": {"entities": [
+				{"id": 3, "title": "P0 should provision [e2e:aaaaaaaaaaaa]"},
+				{"id": 4, "title": "Defect opened manually"}
+			]}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/defect/HP":
+			var defect qase.DefectCreate
+			_ = json.NewDecoder(r.Body).Decode(&defect)
+			created = append(created, defect)
+			_, _ = w.Write([]byte(`{"status": true, "result": {"id": 7}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	results := []QaseResult{
+		{Spec: "P0 should provision", Status: "failed", Comment: "boom", Signature: "aaaaaaaaaaaa"},
+		{Spec: "P1 should upgrade", Status: "failed", Comment: "timed out", Signature: "bbbbbbbbbbbb"},
+		// the same failure in another spec is linked to the same defect
+		{Spec: "P1 should upgrade nodes", Status: "failed", Comment: "timed out", Signature: "bbbbbbbbbbbb"},
+		{Spec: "P1 should scale", Status: "passed"},
+		// already linked by a previous submission
+		{Spec: "P1 should delete", Status: "failed", Comment: "gone\nQase defect: 5 (failure signature cccccccccccc)", Signature: "cccccccccccc", DefectID: 5},
+	}
+	linkQaseDefects(qase.NewAPIClient(&qase.Configuration{BasePath: server.URL}), results)
+
+	if len(created) != 1 || created[0].Title != "P1 should upgrade [e2e:bbbbbbbbbbbb]" || !strings.HasPrefix(created[0].ActualResult, "timed out") {
+		t.Fatalf("one defect must be created for the new failure: %+v", created)
+	}
+	for i, want := range []int64{3, 7, 7, 0, 5} {
+		if results[i].DefectID != want {
+			t.Errorf("result %q got defect %d, want %d", results[i].Spec, results[i].DefectID, want)
+		}
+	}
+	if results[1].Comment != "timed out\nQase defect: 7 (failure signature bbbbbbbbbbbb)" || strings.Count(results[4].Comment, "Qase defect") != 1 {
+		t.Errorf("unexpected comments %q and %q", results[1].Comment, results[4].Comment)
+	}
+}
@@ -0,0 +1,75 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+	qase "go.qase.io/client"
+)
+
+// qasePlanCaseIDs returns the IDs of the cases of the Qase test plan
+func qasePlanCaseIDs(client *qase.APIClient, planID int32) ([]int64, error) {
+	var plan qase.PlanResponse
+	err := retryQase(fmt.Sprintf("get the Qase test plan %d", planID), func() (err error) {
+		plan, _, err = client.PlansApi.GetPlan(context.TODO(), runConfig.QaseProjectCode, planID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the Qase test plan %d: %w", planID, err)
+	}
+	if plan.Result == nil || len(plan.Result.Cases) == 0 {
+		return nil, fmt.Errorf("the Qase test plan %d has no case", planID)
+	}
+	caseIDs := make([]int64, 0, len(plan.Result.Cases))
+	for _, planCase := range plan.Result.Cases {
+		caseIDs = append(caseIDs, planCase.CaseId)
+	}
+	return caseIDs, nil
+}
+
+// qasePlanLabelFilter returns the Ginkgo label filter selecting the specs with a qase:<ID> label of the cases, within the specs selected
+// by the label filter of the run, if any
+func qasePlanLabelFilter(caseIDs []int64, labelFilter string) string {
+	filter := "(" + strings.Join(qaseLabels(caseIDs), " || ") + ")"
+	if strings.TrimSpace(labelFilter) != "" {
+		filter = "(" + labelFilter + ") && " + filter
+	}
+	return filter
+}
+
+/*
+Return the Ginkgo suite config of the run, to be passed to RunSpecs; with QASE_TEST_PLAN_ID, only the specs covering a case of the Qase test plan,
+according to their qase:<ID> labels, are run, so that the regression runs can be driven from Qase:
+
+	RunSpecs(t, "P1 Suite", helpers.SuiteConfig(t))
+
+The other specs are left out like with --label-filter, they are neither run nor reported to Qase.
+With GRAFANA_URL, the start of the suite is annotated on Grafana, see annotateSuiteStart. The specs are skipped once the suite time budget
+is nearly exhausted, see SkipOverBudget. With PROFILE, the suite is profiled, see StartProfiling.
+  - @param t Go test of the suite
+  - @returns The suite config from the ginkgo flags, with the label filter of the plan; the test fails if the plan can not be fetched
+*/
+func SuiteConfig(t testing.TB) types.SuiteConfig {
+	suiteConfig, _ := ginkgo.GinkgoConfiguration()
+	if runConfig.GrafanaURL != "" && suiteConfig.ParallelProcess == 1 {
+		if err := annotateSuiteStart(); err != nil {
+			t.Logf("Failed to annotate the start of the suite on Grafana: %v", err)
+		}
+	}
+	startTimeBudget(suiteConfig)
+	StartProfiling(t)
+	if runConfig.QaseTestPlanID == 0 {
+		return suiteConfig
+	}
+	caseIDs, err := qasePlanCaseIDs(newQaseClient(), int32(runConfig.QaseTestPlanID))
+	if err != nil {
+		t.Fatalf("QASE_TEST_PLAN_ID: %v", err)
+	}
+	suiteConfig.LabelFilter = qasePlanLabelFilter(caseIDs, suiteConfig.LabelFilter)
+	t.Logf("Running the %d cases of the Qase test plan %d, with the label filter %s", len(caseIDs), runConfig.QaseTestPlanID, suiteConfig.LabelFilter)
+	return suiteConfig
+}
@@ -0,0 +1,61 @@
+package helpers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2/types"
+	qase "go.qase.io/client"
+)
+
+func TestQasePlanCaseIDs(t *testing.T) {
+	defer func(code string) { runConfig.QaseProjectCode = code }(runConfig.QaseProjectCode)
+	runConfig.QaseProjectCode = "HP"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/plan/HP/7":
+			_, _ = w.Write([]byte(`{"status": true, "result": {"id": 7, "cases": [{"case_id": 131}, {"case_id": 240, "assignee_id": 3}]}}`))
+		case "/plan/HP/8":
+			_, _ = w.Write([]byte(`{"status": true, "result": {"id": 8, "cases": []}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	cfg := qase.NewConfiguration()
+	cfg.BasePath = server.URL
+	client := qase.NewAPIClient(cfg)
+
+	caseIDs, err := qasePlanCaseIDs(client, 7)
+	if err != nil || len(caseIDs) != 2 || caseIDs[0] != 131 || caseIDs[1] != 240 {
+		t.Errorf("qasePlanCaseIDs() = %v, %v, want [131 240]", caseIDs, err)
+	}
+	if _, err = qasePlanCaseIDs(client, 8); err == nil {
+		t.Errorf("qasePlanCaseIDs() of an empty plan returned no error")
+	}
+}
+
+func TestQasePlanLabelFilter(t *testing.T) {
+	filter := qasePlanLabelFilter([]int64{131, 240}, "")
+	if filter != "(qase:131 || qase:240)" {
+		t.Errorf("qasePlanLabelFilter() = %q", filter)
+	}
+	filter = qasePlanLabelFilter([]int64{131, 240}, "!upgrade")
+	if filter != "(!upgrade) && (qase:131 || qase:240)" {
+		t.Errorf("qasePlanLabelFilter() with a label filter = %q", filter)
+	}
+
+	matches, err := types.ParseLabelFilter(filter)
+	if err != nil {
+		t.Fatalf("ParseLabelFilter(%q) = %v", filter, err)
+	}
+	for labels, expected := range map[string]bool{"qase:131": true, "qase:240,p1": true, "qase:240,upgrade": false, "qase:13": false, "p1": false} {
+		if matches(strings.Split(labels, ",")) != expected {
+			t.Errorf("label filter %q on %s, want %t", filter, labels, expected)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// QuotaExceededNodeCount is a node count no test account has the quota for, used to provision a cluster beyond the account quota
+const QuotaExceededNodeCount = 1000
+
+// quotaErrorKeywords are the words of the errors returned by the cloud providers when a request exceeds a quota or a limit of the account,
+// for e.g. "Insufficient regional quota to satisfy request" on GCP or "exceeding approved standardDSv2Family Cores quota" on Azure
+var quotaErrorKeywords = []string{"quota", "limit", "exceed", "insufficient", "maximum"}
+
+// isQuotaError returns true if the message is a cloud error about an exceeded quota or limit
+func isQuotaError(message string) bool {
+	message = strings.ToLower(message)
+	for _, keyword := range quotaErrorKeywords {
+		if strings.Contains(message, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Wait for Rancher to surface the error returned by the cloud provider for a request exceeding the account quota, for e.g. a nodegroup/nodepool
+with QuotaExceededNodeCount nodes; the cluster must report it as an error in its TransitioningMessage within the given time.
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @param timeout Time the error must be surfaced within, which includes the provisioning of the control plane if the error is about the nodes
+  - @returns The cluster reporting the error; the function will fail through Ginkgo in case of issue
+*/
+func WaitForQuotaError(client *rancher.Client, cluster *management.Cluster, timeout time.Duration) *management.Cluster {
+	// the cluster stays in error for the rest of the spec
+	AllowClusterErrors()
+	ginkgo.By(fmt.Sprintf("Waiting for cluster %s to report the quota error", cluster.Name), func() {
+		start := time.Now()
+		EventuallyWithBackoff(func() (string, error) {
+			var err error
+			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			if err != nil {
+				return "", err
+			}
+			ObserveCluster(cluster)
+			if cluster.Transitioning != "error" {
+				return "", nil
+			}
+			return cluster.TransitioningMessage, nil
+		}, tools.SetTimeout(timeout), 15*time.Second).Should(Satisfy(isQuotaError), fmt.Sprintf("Cluster %s did not report a quota error", cluster.Name))
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Cluster %s reported the quota error after %s: %s", cluster.Name, time.Since(start).Round(time.Second), cluster.TransitioningMessage))
+	})
+	return cluster
+}
+
+/*
+Make sure a cluster that failed to be provisioned does not leave any resource behind, whether the cluster cleanup is enabled or not:
+the cluster is deleted from Rancher if it still exists, and the cloud cluster, partially created by the operator, must be deleted along with it.
+It is meant to be registered with DeferCleanup right after the creation of the cluster.
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @param deleteCluster Provider function deleting the cluster from Rancher, for e.g. DeleteEKSHostCluster
+  - @param existsOnCloud Provider function checking whether the cloud cluster still exists, for e.g. a wrapper of ClusterExistsOnAWS
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CleanupFailedCluster(client *rancher.Client, cluster *management.Cluster, deleteCluster func(*management.Cluster, *rancher.Client) error, existsOnCloud func() (bool, error)) {
+	ginkgo.By(fmt.Sprintf("Cleaning up the resources of cluster %s", cluster.Name), func() {
+		current, err := client.Management.Cluster.ByID(cluster.ID)
+		if err == nil {
+			Expect(deleteCluster(current, client)).To(Succeed())
+		} else {
+			Expect(err.Error()).To(ContainSubstring("not found"))
+		}
+		WaitUntilClusterIsRemoved(client, cluster.ID)
+
+		EventuallyWithBackoff(existsOnCloud, tools.SetTimeout(30*time.Minute), 30*time.Second).Should(BeFalse(), fmt.Sprintf("Cluster %s still exists on the cloud provider", cluster.Name))
+	})
+}
@@ -0,0 +1,20 @@
+package helpers
+
+import "testing"
+
+func TestIsQuotaError(t *testing.T) {
+	for _, tc := range []struct {
+		message string
+		want    bool
+	}{
+		{message: "", want: false},
+		{message: "Cluster must have at least one managed nodegroup or one self-managed node", want: false},
+		{message: `googleapi: Error 403: Insufficient regional quota to satisfy request: resource "CPUS"`, want: true},
+		{message: "Operation could not be completed as it results in exceeding approved standardDSv2Family Cores quota", want: true},
+		{message: "ResourceLimitExceeded: You have requested more vCPU capacity than your current vCPU limit", want: true},
+	} {
+		if got := isQuotaError(tc.message); got != tc.want {
+			t.Errorf("isQuotaError(%q) = %t, want %t", tc.message, got, tc.want)
+		}
+	}
+}
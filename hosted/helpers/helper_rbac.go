@@ -0,0 +1,68 @@
+package helpers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/users"
+)
+
+// Cluster role templates that can be bound to a user with AddClusterMember; the creator of a cluster is bound to ClusterOwnerRole by Rancher
+const (
+	ClusterOwnerRole  = "cluster-owner"
+	ClusterMemberRole = "cluster-member"
+)
+
+/*
+Bind a cluster role to the user of a client, for e.g. a client created with NewStdUserClient
+  - @param client Rancher admin client
+  - @param cluster Downstream cluster
+  - @param userClient Client of the user to add to the cluster
+  - @param role Cluster role template, for e.g. ClusterMemberRole
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func AddClusterMember(client *rancher.Client, cluster *management.Cluster, userClient *rancher.Client, role string) {
+	user, err := client.Management.User.ByID(userClient.UserID)
+	Expect(err).To(BeNil())
+
+	ginkgo.By(fmt.Sprintf("Binding role %s of cluster %s to user %s", role, cluster.Name, user.Username), func() {
+		Expect(users.AddClusterRoleToUser(client, cluster, user, role, nil)).To(Succeed())
+	})
+}
+
+/*
+Check that a user can see a cluster but can not change it: every update and the deletion of the cluster must be denied by Rancher.
+It is meant to check the users bound to ClusterMemberRole.
+  - @param client Rancher admin client, used to get a copy of the cluster for every update so that the caller's cluster is left unchanged
+  - @param userClient Client of the user
+  - @param clusterID ID of the downstream cluster
+  - @param updates Changes to the cluster that must be denied, for e.g. a nodepool scaling or a kubernetes version upgrade
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckClusterUpdateDenied(client, userClient *rancher.Client, clusterID string, updates ...func(cluster *management.Cluster)) {
+	ginkgo.By(fmt.Sprintf("Checking that cluster %s can be read but not changed", clusterID), func() {
+		// the permissions of a new role binding take a while to be effective
+		EventuallyWithBackoff(func() error {
+			_, err := userClient.Management.Cluster.ByID(clusterID)
+			return err
+		}, tools.SetTimeout(3*time.Minute), 5*time.Second).Should(Succeed(), fmt.Sprintf("Cluster %s can not be read", clusterID))
+
+		for i, update := range updates {
+			cluster, err := client.Management.Cluster.ByID(clusterID)
+			Expect(err).To(BeNil())
+			update(cluster)
+			_, err = UpdateRancherCluster(userClient, cluster, cluster)
+			Expect(isForbidden(err)).To(BeTrue(), fmt.Sprintf("Update %d of cluster %s was not denied: %v", i, clusterID, err))
+		}
+
+		cluster, err := client.Management.Cluster.ByID(clusterID)
+		Expect(err).To(BeNil())
+		err = userClient.Management.Cluster.Delete(cluster)
+		Expect(isForbidden(err)).To(BeTrue(), fmt.Sprintf("Deletion of cluster %s was not denied: %v", clusterID, err))
+	})
+}
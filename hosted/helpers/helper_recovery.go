@@ -0,0 +1,213 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+	"github.com/rancher/norman/types"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// ClusterSnapshot holds the state of a hosted cluster that must survive a Rancher backup/restore
+type ClusterSnapshot struct {
+	ID           string
+	Name         string
+	Config       interface{}
+	UpstreamSpec interface{}
+	// KubernetesVersion and NodeNames identify the cloud cluster, they change if it is recreated
+	KubernetesVersion string
+	NodeNames         []string
+}
+
+// providerSpecs returns the provider config and the upstream spec of the cluster, whatever its provider
+func providerSpecs(cluster *management.Cluster) (config, upstreamSpec interface{}) {
+	switch {
+	case cluster.EKSConfig != nil:
+		if cluster.EKSStatus != nil {
+			upstreamSpec = cluster.EKSStatus.UpstreamSpec
+		}
+		return cluster.EKSConfig, upstreamSpec
+	case cluster.GKEConfig != nil:
+		if cluster.GKEStatus != nil {
+			upstreamSpec = cluster.GKEStatus.UpstreamSpec
+		}
+		return cluster.GKEConfig, upstreamSpec
+	case cluster.AKSConfig != nil:
+		if cluster.AKSStatus != nil {
+			upstreamSpec = cluster.AKSStatus.UpstreamSpec
+		}
+		return cluster.AKSConfig, upstreamSpec
+	}
+	return nil, nil
+}
+
+// SnapshotHostedCluster records the provider config and the upstream spec of the cluster;
+// it is meant to be taken right before the backup so that it can be compared with the restored cluster.
+func SnapshotHostedCluster(client *rancher.Client, clusterID string) (*ClusterSnapshot, error) {
+	cluster, err := client.Management.Cluster.ByID(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	nodeNames, err := clusterNodeNames(client, cluster.ID)
+	if err != nil {
+		return nil, err
+	}
+	config, upstreamSpec := providerSpecs(cluster)
+	snapshot := &ClusterSnapshot{
+		ID:           cluster.ID,
+		Name:         cluster.Name,
+		Config:       config,
+		UpstreamSpec: upstreamSpec,
+		NodeNames:    nodeNames,
+	}
+	if cluster.Version != nil {
+		snapshot.KubernetesVersion = cluster.Version.GitVersion
+	}
+	return snapshot, nil
+}
+
+// clusterNodeNames returns the sorted names of the nodes of the cluster
+func clusterNodeNames(client *rancher.Client, clusterID string) ([]string, error) {
+	nodes, err := client.Management.Node.List(&types.ListOpts{Filters: map[string]interface{}{"clusterId": clusterID}})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, node := range nodes.Data {
+		names = append(names, node.NodeName)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+/*
+Wipe the Rancher installation by uninstalling and reinstalling k3s
+  - @param k kubectl structure
+  - @param k3sVersion k3s version to install
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func WipeRancher(k *kubectl.Kubectl, k3sVersion string) {
+	ginkgo.By("Uninstalling k3s", func() {
+		_, err := extcli.New("k3s-uninstall.sh").WithTimeout(10 * time.Minute).Run()
+		Expect(err).To(Not(HaveOccurred()))
+	})
+
+	ginkgo.By("Getting k3s ready", func() {
+		InstallK3S(k, k3sVersion, "none", "none")
+	})
+}
+
+/*
+Restore Rancher from a backup file on a wiped cluster and reinstall Rancher on top of it
+  - @param k kubectl structure
+  - @param restoreResourceName Restore resource name
+  - @param backupFile backup file returned by ExecuteBackup
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func RestoreRancher(k *kubectl.Kubectl, restoreResourceName, backupFile string) {
+	ginkgo.By("Performing a restore", func() {
+		ExecuteRestore(k, restoreResourceName, backupFile)
+	})
+
+	installRancherOnWipedCluster(k)
+}
+
+// installRancherOnWipedCluster installs cert-manager and Rancher on the upstream cluster wiped by WipeRancher
+func installRancherOnWipedCluster(k *kubectl.Kubectl) {
+	ginkgo.By("Performing post migration installations: Installing CertManager", func() {
+		InstallCertManager(k, RancherBehindProxy, "none")
+	})
+
+	ginkgo.By("Performing post migration installations: Installing Rancher Manager", func() {
+		rancherChannel, rancherVersion, rancherHeadVersion := GetRancherVersions(RancherFullVersion)
+		InstallRancherManager(k, RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, RancherBehindProxy, "none")
+	})
+
+	ginkgo.By("Performing post migration installations: Checking Rancher Deployments", func() {
+		CheckRancherDeployments(k)
+	})
+}
+
+// CheckHostedClusterRecovered checks that the restored cluster is ready again
+// and that its provider config and upstream spec are the same as the ones recorded before the backup.
+// It returns the up-to-date cluster.
+func CheckHostedClusterRecovered(client *rancher.Client, snapshot *ClusterSnapshot) *management.Cluster {
+	var cluster *management.Cluster
+
+	ginkgo.By(fmt.Sprintf("checking cluster %s has been restored", snapshot.Name), func() {
+		EventuallyWithBackoff(func() error {
+			var err error
+			cluster, err = client.Management.Cluster.ByID(snapshot.ID)
+			return err
+		}, Timeout, 10*time.Second).Should(BeNil())
+		Expect(cluster.Name).To(Equal(snapshot.Name))
+	})
+
+	ginkgo.By("waiting for the restored cluster to be ready", func() {
+		var err error
+		cluster, err = WaitUntilClusterIsReady(cluster, client)
+		Expect(err).To(BeNil())
+	})
+
+	ginkgo.By("checking the provider config and the upstream spec are unchanged", func() {
+		// Fetch the cluster again since WaitUntilClusterIsReady overwrites the config of imported clusters
+		restored, err := client.Management.Cluster.ByID(snapshot.ID)
+		Expect(err).To(BeNil())
+		config, upstreamSpec := providerSpecs(restored)
+		Expect(config).To(Equal(snapshot.Config))
+		Expect(upstreamSpec).To(Equal(snapshot.UpstreamSpec))
+	})
+
+	return cluster
+}
+
+/*
+Reinstall Rancher from scratch on a wiped upstream cluster, with the same version and hostname but without restoring any backup:
+the clusters, cloud credentials and tokens of the previous installation are lost, while the cloud clusters keep running.
+  - @param k kubectl structure
+  - @param k3sVersion k3s version to install
+  - @returns The context of the new installation, with a new admin client and cloud credential; the function will fail through Ginkgo in case of issue
+*/
+func ReinstallRancher(k *kubectl.Kubectl, k3sVersion string) RancherContext {
+	ginkgo.By("Reinstalling Rancher: Wiping Rancher", func() {
+		WipeRancher(k, k3sVersion)
+	})
+	installRancherOnWipedCluster(k)
+	WaitUntilRancherIsUp(RancherHostname)
+
+	// the admin token of the cattle config file belongs to the previous installation
+	setupRancher()
+	updateCredentialsConfig(Provider)
+	return CommonBeforeSuite()
+}
+
+// CheckHostedClusterAdopted checks that the cluster imported by a new Rancher installation is ready and manages the same cloud cluster
+// as the snapshot taken before the reinstallation, i.e. that no cloud resource was recreated: the kubernetes version and the nodes must be the same.
+// It returns the up-to-date cluster, with the upstream spec as provider config so that it can be updated.
+func CheckHostedClusterAdopted(client *rancher.Client, cluster *management.Cluster, snapshot *ClusterSnapshot) *management.Cluster {
+	ginkgo.By(fmt.Sprintf("waiting for the adopted cluster %s to be ready", cluster.Name), func() {
+		var err error
+		cluster, err = WaitUntilClusterIsReady(cluster, client)
+		Expect(err).To(BeNil())
+		useUpstreamSpec(cluster)
+	})
+
+	ginkgo.By("checking the cloud cluster has not been recreated", func() {
+		Expect(cluster.Name).To(Equal(snapshot.Name))
+		Expect(cluster.Version).ToNot(BeNil())
+		Expect(cluster.Version.GitVersion).To(Equal(snapshot.KubernetesVersion))
+		// the nodes register with the new installation one after the other
+		EventuallyWithBackoff(func() ([]string, error) {
+			return clusterNodeNames(client, cluster.ID)
+		}, tools.SetTimeout(10*time.Minute), 10*time.Second).Should(Equal(snapshot.NodeNames))
+	})
+
+	return cluster
+}
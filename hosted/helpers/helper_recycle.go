@@ -0,0 +1,167 @@
+package helpers
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// OperationRecycle is the operation handing the recycled cluster to a spec, in the cluster timeline; see RecycleCluster
+const OperationRecycle = "recycle"
+
+// recycleResetTimeout is how long the recycled cluster is given to get back to its provisioned config before it is replaced
+const recycleResetTimeout = 20 * time.Minute
+
+// recycledCluster is the cluster shared by the specs of the process when RECYCLE_CLUSTER is true
+type recycledCluster struct {
+	client *rancher.Client
+	// snapshot is the provider config and the upstream spec of the cluster once provisioned, which it is reset to between the specs
+	snapshot      *ClusterSnapshot
+	deleteCluster func(*management.Cluster) error
+	// dirty is true once a spec using the cluster failed: the cluster may be left in any state, it is replaced for the next spec
+	dirty bool
+	specs int
+}
+
+// recycled is the recycled cluster of the process; the specs of a process run one at a time
+var recycled *recycledCluster
+
+// the snapshot and the reset of the recycled cluster, and the cleanup of the spec using it, replaced by the tests
+var (
+	snapshotRecycledCluster = SnapshotHostedCluster
+	resetRecycled           = resetRecycledCluster
+	deferRecycledCleanup    = ginkgo.DeferCleanup
+)
+
+/*
+RecycleCluster returns a ready cluster for a spec which only needs an active cluster and does not destroy it. If RECYCLE_CLUSTER is true,
+the first spec of the process provisions the cluster and the next ones reuse it, its provider config being reset to the one it was provisioned with
+(see SnapshotHostedCluster) before every spec; the cluster is replaced if a spec using it failed or if it can not be reset. Otherwise, a new cluster
+is provisioned for every spec, as without the helper. The recycled cluster must not be deleted by the spec (see IsRecycledCluster),
+it is deleted by DeleteRecycledCluster at the end of the suite on every process,
+for e.g. var _ = SynchronizedAfterSuite(helpers.DeleteRecycledCluster, helpers.CommonSynchronizedAfterSuite).
+  - @param client Rancher client
+  - @param provision Provisions the cluster and waits until it is ready
+  - @param deleteCluster Deletes the cluster, when it is replaced or at the end of the suite
+  - @returns The ready cluster and the error of its provisioning or reset
+*/
+func RecycleCluster(client *rancher.Client, provision func() (*management.Cluster, error), deleteCluster func(*management.Cluster) error) (*management.Cluster, error) {
+	if !runConfig.RecycleCluster {
+		return provision()
+	}
+
+	if recycled != nil && recycled.dirty {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("A spec failed on the recycled cluster %s, replacing it", recycled.snapshot.Name))
+		discardRecycledCluster()
+	}
+	if recycled != nil {
+		cluster, err := resetRecycled(client, recycled.snapshot)
+		if err == nil {
+			return useRecycledCluster(cluster), nil
+		}
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to reset the recycled cluster %s, replacing it: %v", recycled.snapshot.Name, err))
+		discardRecycledCluster()
+	}
+
+	cluster, err := provision()
+	if err != nil {
+		return cluster, err
+	}
+	snapshot, err := snapshotRecycledCluster(client, cluster.ID)
+	if err != nil {
+		// the cluster is not recycled, the spec deletes it
+		return cluster, err
+	}
+	recycled = &recycledCluster{client: client, snapshot: snapshot, deleteCluster: deleteCluster}
+	return useRecycledCluster(cluster), nil
+}
+
+// useRecycledCluster hands the recycled cluster to the current spec: its transitions are recorded, and it is marked dirty if the spec fails
+func useRecycledCluster(cluster *management.Cluster) *management.Cluster {
+	recycled.specs++
+	if recycled.specs > 1 {
+		watchClusterTransitions(OperationRecycle, cluster)
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Recycling cluster %s for the spec %d", cluster.Name, recycled.specs))
+	}
+	current := recycled
+	deferRecycledCleanup(func() {
+		if ginkgo.CurrentSpecReport().Failed() {
+			current.dirty = true
+		}
+	})
+	return cluster
+}
+
+// resetRecycledCluster sets the provider config of the cluster back to the one of the snapshot if a spec changed it,
+// and waits until the upstream spec is the one of the snapshot again and the cluster is ready
+func resetRecycledCluster(client *rancher.Client, snapshot *ClusterSnapshot) (*management.Cluster, error) {
+	cluster, err := client.Management.Cluster.ByID(snapshot.ID)
+	if err != nil {
+		return nil, err
+	}
+	if config, _ := providerSpecs(cluster); reflect.DeepEqual(config, snapshot.Config) {
+		return WaitUntilClusterIsReady(cluster, client)
+	}
+
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Resetting the config of the recycled cluster %s", cluster.Name))
+	cluster, err = UpdateRancherCluster(client, cluster, recycledClusterReset(cluster.Name, snapshot.Config))
+	if err != nil {
+		return nil, err
+	}
+	upstreamSpec := func(cluster *management.Cluster) any {
+		_, upstreamSpec := providerSpecs(cluster)
+		return upstreamSpec
+	}
+	if cluster, err = WaitForUpstreamField(client, snapshot.ID, upstreamSpec, snapshot.UpstreamSpec, recycleResetTimeout); err != nil {
+		return nil, err
+	}
+	return WaitUntilClusterIsReady(cluster, client)
+}
+
+// recycledClusterReset returns the update setting the provider config of the cluster back to the given one
+func recycledClusterReset(name string, config interface{}) *management.Cluster {
+	update := &management.Cluster{Name: name}
+	switch config := config.(type) {
+	case *management.EKSClusterConfigSpec:
+		update.EKSConfig = config
+	case *management.GKEClusterConfigSpec:
+		update.GKEConfig = config
+	case *management.AKSClusterConfigSpec:
+		update.AKSConfig = config
+	}
+	return update
+}
+
+// IsRecycledCluster returns true if the cluster is the recycled cluster of the process, which the spec must not delete; see RecycleCluster
+func IsRecycledCluster(cluster *management.Cluster) bool {
+	return cluster != nil && recycled != nil && recycled.snapshot.ID == cluster.ID
+}
+
+// discardRecycledCluster deletes the recycled cluster, unless DOWNSTREAM_CLUSTER_CLEANUP is false; the deletion errors are logged
+func discardRecycledCluster() {
+	current := recycled
+	recycled = nil
+	if !clusterCleanup {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Skipping the deletion of the recycled cluster %s", current.snapshot.Name))
+		return
+	}
+	cluster, err := current.client.Management.Cluster.ByID(current.snapshot.ID)
+	if err == nil {
+		err = current.deleteCluster(cluster)
+	}
+	if err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to delete the recycled cluster %s: %v", current.snapshot.Name, err))
+	}
+}
+
+// DeleteRecycledCluster deletes the recycled cluster of the process, if any; it must be called on every process at the end of the suites using RecycleCluster, see RecycleCluster
+func DeleteRecycledCluster() {
+	if recycled != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Deleting the recycled cluster %s, used by %d specs", recycled.snapshot.Name, recycled.specs))
+		discardRecycledCluster()
+	}
+}
@@ -0,0 +1,107 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+func TestRecycleClusterDisabled(t *testing.T) {
+	defer func(recycle bool) { runConfig.RecycleCluster = recycle }(runConfig.RecycleCluster)
+	runConfig.RecycleCluster = false
+
+	provisioned := 0
+	provision := func() (*management.Cluster, error) {
+		provisioned++
+		cluster := &management.Cluster{Name: "eks-1"}
+		cluster.ID = "c-1"
+		return cluster, nil
+	}
+	for i := 0; i < 2; i++ {
+		cluster, err := RecycleCluster(nil, provision, nil)
+		if err != nil || cluster.ID != "c-1" {
+			t.Fatalf("RecycleCluster() = %v, %v", cluster, err)
+		}
+		if IsRecycledCluster(cluster) {
+			t.Errorf("the cluster must not be recycled without RECYCLE_CLUSTER")
+		}
+	}
+	if provisioned != 2 {
+		t.Errorf("got %d clusters provisioned, want one per spec", provisioned)
+	}
+}
+
+func TestRecycleClusterEnabled(t *testing.T) {
+	defer func(recycle, cleanup bool) {
+		runConfig.RecycleCluster, clusterCleanup = recycle, cleanup
+		recycled, snapshotRecycledCluster, resetRecycled, deferRecycledCleanup = nil, SnapshotHostedCluster, resetRecycledCluster, ginkgo.DeferCleanup
+	}(runConfig.RecycleCluster, clusterCleanup)
+	runConfig.RecycleCluster, clusterCleanup = true, false
+
+	provisioned := 0
+	provision := func() (*management.Cluster, error) {
+		provisioned++
+		cluster := &management.Cluster{Name: fmt.Sprintf("eks-%d", provisioned)}
+		cluster.ID = fmt.Sprintf("c-%d", provisioned)
+		return cluster, nil
+	}
+	snapshotRecycledCluster = func(_ *rancher.Client, clusterID string) (*ClusterSnapshot, error) {
+		return &ClusterSnapshot{ID: clusterID, Name: "eks-" + clusterID[len("c-"):]}, nil
+	}
+	var resetErr error
+	resetRecycled = func(_ *rancher.Client, snapshot *ClusterSnapshot) (*management.Cluster, error) {
+		cluster := &management.Cluster{Name: snapshot.Name}
+		cluster.ID = snapshot.ID
+		return cluster, resetErr
+	}
+	deferRecycledCleanup = func(args ...any) {}
+
+	recycle := func(want string) {
+		t.Helper()
+		cluster, err := RecycleCluster(nil, provision, nil)
+		if err != nil || cluster.Name != want {
+			t.Fatalf("RecycleCluster() = %v, %v, want %s", cluster, err, want)
+		}
+		if !IsRecycledCluster(cluster) {
+			t.Errorf("%s must be the recycled cluster", cluster.Name)
+		}
+	}
+	// the first spec provisions the cluster, the next one resets it
+	recycle("eks-1")
+	recycle("eks-1")
+	if provisioned != 1 || recycled.specs != 2 {
+		t.Errorf("got %d clusters provisioned for %d specs, want one for 2", provisioned, recycled.specs)
+	}
+	// a spec failed on the cluster
+	recycled.dirty = true
+	recycle("eks-2")
+	// the cluster can not be reset
+	resetErr = errors.New("timed out")
+	recycle("eks-3")
+	if provisioned != 3 {
+		t.Errorf("got %d clusters provisioned, want 3", provisioned)
+	}
+}
+
+func TestRecycledClusterReset(t *testing.T) {
+	eksConfig := &management.EKSClusterConfigSpec{DisplayName: "eks-1"}
+	if update := recycledClusterReset("eks-1", eksConfig); update.Name != "eks-1" || update.EKSConfig != eksConfig || update.GKEConfig != nil || update.AKSConfig != nil {
+		t.Errorf("unexpected EKS reset: %+v", update)
+	}
+	aksConfig := &management.AKSClusterConfigSpec{ClusterName: "aks-1"}
+	if update := recycledClusterReset("aks-1", aksConfig); update.AKSConfig != aksConfig || update.EKSConfig != nil {
+		t.Errorf("unexpected AKS reset: %+v", update)
+	}
+
+	defer func() { recycled = nil }()
+	recycled = &recycledCluster{snapshot: &ClusterSnapshot{ID: "c-1", Name: "eks-1"}}
+	cluster := &management.Cluster{}
+	cluster.ID = "c-1"
+	if !IsRecycledCluster(cluster) || IsRecycledCluster(nil) {
+		t.Errorf("only c-1 is the recycled cluster")
+	}
+}
@@ -0,0 +1,216 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// SuiteSummary is a condensed report of a suite run, meant to be consumed by the CI systems and dashboards
+type SuiteSummary struct {
+	Suite           string         `json:"suite"`
+	Provider        string         `json:"provider"`
+	RancherHostname string         `json:"rancherHostname"`
+	RancherVersion  string         `json:"rancherVersion"`
+	RunID           string         `json:"runID"`
+	PipelineURL     string         `json:"pipelineURL,omitempty"`
+	StartTime       time.Time      `json:"startTime"`
+	EndTime         time.Time      `json:"endTime"`
+	Seconds         float64        `json:"seconds"`
+	Succeeded       bool           `json:"succeeded"`
+	Counts          map[string]int `json:"counts"`
+	// PassedOnRetry is the number of flaky specs, which passed after failing with FLAKE_ATTEMPTS
+	PassedOnRetry int `json:"passedOnRetry"`
+	// Cost is the estimated spend of the clusters of the suite
+	Cost CostEstimate `json:"cost"`
+	// Metadata is the environment of the run recorded at the start of the suite, see RecordRunMetadata
+	Metadata *RunMetadata  `json:"metadata,omitempty"`
+	Specs    []SpecSummary `json:"specs"`
+}
+
+// SpecSummary is the outcome of a spec, with the clusters it created and the operations it timed
+type SpecSummary struct {
+	Name     string   `json:"name"`
+	Labels   []string `json:"labels,omitempty"`
+	State    string   `json:"state"`
+	Seconds  float64  `json:"seconds"`
+	Attempts int      `json:"attempts"`
+	// CorrelationID is the correlation ID of the last execution of the spec, set on the resources it created, see CorrelationID
+	CorrelationID string `json:"correlationID,omitempty"`
+	// PassedOnRetry is true if the spec passed after failing, see passedOnRetry
+	PassedOnRetry bool   `json:"passedOnRetry,omitempty"`
+	Failure       string `json:"failure,omitempty"`
+	Location      string `json:"location,omitempty"`
+	// Transitions is the timeline of the cluster transitions of a failed spec, see RecordClusterTransitions
+	Transitions []string `json:"transitions,omitempty"`
+	// Lifecycle is the sequence of the cluster calls of a failed spec with the transitions they resulted in, see clusterLifecycle
+	Lifecycle []LifecycleStep `json:"lifecycle,omitempty"`
+	// KnownIssues are the known issues matched by the failure, see annotateKnownIssues
+	KnownIssues []string          `json:"knownIssues,omitempty"`
+	Clusters    []Resource        `json:"clusters,omitempty"`
+	Operations  []OperationMetric `json:"operations,omitempty"`
+}
+
+// SuiteReportName returns the name of the suite used for its report files, for e.g. eks-p1 or eks-k8s-chart-support-upgrade;
+// the description of the suite is used for the suites outside of hosted/.
+func SuiteReportName(report ginkgo.Report) string {
+	if _, suite, found := strings.Cut(filepath.ToSlash(report.SuitePath), "/hosted/"); found {
+		return sanitizeName(suite)
+	}
+	return sanitizeName(report.SuiteDescription)
+}
+
+// GenerateSuiteReports writes the Ginkgo JSON and JUnit reports and the summary of the suite to ArtifactsDir,
+// as <suite>-report.json, <suite>-junit.xml and <suite>-summary.json, along with the HTML report of the run (see GenerateRunReport), pushes the suite metrics to the pushgateway and annotates the suite on Grafana if configured; it must be called from the ReportAfterSuite node of every suite:
+// var _ = ReportAfterSuite("Reports", func(report Report) { helpers.GenerateSuiteReports(report) })
+// Since ReportAfterSuite runs once all the parallel processes are done, the report covers all of them.
+func GenerateSuiteReports(report ginkgo.Report) {
+	name := SuiteReportName(report)
+	if err := os.MkdirAll(ArtifactsDir, 0o755); err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to create the artifacts directory: %v", err))
+		return
+	}
+
+	if err := reporters.GenerateJSONReport(report, filepath.Join(ArtifactsDir, name+"-report.json")); err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to write the JSON report: %v", err))
+	}
+	if err := reporters.GenerateJUnitReport(report, filepath.Join(ArtifactsDir, name+"-junit.xml")); err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to write the JUnit report: %v", err))
+	}
+
+	summary := newSuiteSummary(name, report)
+	content, err := json.MarshalIndent(summary, "", "  ")
+	if err == nil {
+		err = os.WriteFile(filepath.Join(ArtifactsDir, name+"-summary.json"), content, 0o644)
+	}
+	if err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to write the suite summary: %v", err))
+	}
+	if err = GenerateRunReport(); err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to write the run report: %v", err))
+	}
+
+	if runConfig.GrafanaURL != "" {
+		if err = annotateSuiteEnd(summary); err != nil {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to annotate the suite on Grafana: %v", err))
+		}
+	}
+	if runConfig.PushgatewayURL != "" {
+		if err = pushSuiteMetrics(summary, report.SuiteConfig.ParallelTotal); err != nil {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to push the suite metrics: %v", err))
+		}
+	}
+}
+
+// newSuiteSummary builds the summary of the suite; the clusters and operations of the specs are read from the resource manifests
+// and the operation metrics written by all the parallel processes.
+func newSuiteSummary(name string, report ginkgo.Report) SuiteSummary {
+	clusters := map[string][]Resource{}
+	var resources []*Resource
+	for _, manifest := range runResourceManifests() {
+		resources = append(resources, manifest.Resources...)
+		for _, resource := range manifest.Resources {
+			if resource.Kind == ResourceRancherCluster {
+				clusters[resource.Spec] = append(clusters[resource.Spec], *resource)
+			}
+		}
+	}
+	operations := map[string][]OperationMetric{}
+	for _, metrics := range readArtifacts[[]OperationMetric]("operation-metrics-p*.json") {
+		for _, metric := range metrics {
+			operations[metric.Spec] = append(operations[metric.Spec], metric)
+		}
+	}
+
+	summary := SuiteSummary{
+		Suite:           name,
+		Provider:        Provider,
+		RancherHostname: RancherHostname,
+		RancherVersion:  RancherFullVersion,
+		RunID:           runConfig.RunID,
+		PipelineURL:     runConfig.PipelineURL,
+		StartTime:       report.StartTime.UTC(),
+		EndTime:         report.EndTime.UTC(),
+		Seconds:         report.RunTime.Seconds(),
+		Succeeded:       report.SuiteSucceeded,
+		Counts:          map[string]int{},
+	}
+	if metadata := readArtifacts[RunMetadata]("run-metadata.json"); len(metadata) > 0 {
+		summary.Metadata = &metadata[0]
+	}
+	prices, err := parseCostPrices(runConfig.CostPrices)
+	if err != nil {
+		prices = defaultCostPrices
+	}
+	summary.Cost = estimateCost(resources, report.EndTime, prices)
+	for _, spec := range report.SpecReports {
+		// the suite level nodes (BeforeSuite, AfterSuite, ...) are only reported if they failed
+		if spec.LeafNodeType != types.NodeTypeIt && !spec.Failed() {
+			continue
+		}
+		summary.Counts[spec.State.String()]++
+		if passedOnRetry(spec) {
+			summary.PassedOnRetry++
+		}
+
+		specSummary := SpecSummary{
+			Name:          spec.FullText(),
+			Labels:        spec.Labels(),
+			State:         spec.State.String(),
+			Seconds:       spec.RunTime.Seconds(),
+			Attempts:      spec.NumAttempts,
+			CorrelationID: specCorrelationID(spec),
+			PassedOnRetry: passedOnRetry(spec),
+			Clusters:      clusters[spec.FullText()],
+			Operations:    operations[spec.FullText()],
+		}
+		if spec.LeafNodeType != types.NodeTypeIt {
+			specSummary.Name = spec.LeafNodeType.String()
+		}
+		if spec.Failed() {
+			specSummary.Failure = spec.Failure.Message
+			specSummary.Location = spec.Failure.Location.String()
+			for _, entry := range spec.ReportEntries {
+				if entry.Name == clusterTransitionsEntry {
+					specSummary.Transitions = strings.Split(entry.StringRepresentation(), "\n")
+				}
+			}
+			specSummary.Lifecycle = specLifecycle(spec)
+			specSummary.KnownIssues = specKnownIssues(spec)
+		}
+		summary.Specs = append(summary.Specs, specSummary)
+	}
+	return summary
+}
+
+// passedOnRetry returns true if the spec passed after failing on the previous attempts, when retried with FLAKE_ATTEMPTS;
+// it tells the flaky specs apart from the ones passing at once and from the hard failures
+func passedOnRetry(spec types.SpecReport) bool {
+	return spec.State == types.SpecStatePassed && spec.NumAttempts > 1
+}
+
+// readArtifacts decodes the JSON artifacts matching the pattern in ArtifactsDir; the files that can not be read are logged and skipped
+func readArtifacts[T any](pattern string) []T {
+	paths, _ := filepath.Glob(filepath.Join(ArtifactsDir, pattern))
+	var artifacts []T
+	for _, path := range paths {
+		var artifact T
+		content, err := os.ReadFile(path)
+		if err == nil {
+			err = json.Unmarshal(content, &artifact)
+		}
+		if err != nil {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to read %s: %v", path, err))
+			continue
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts
+}
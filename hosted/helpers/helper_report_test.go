@@ -0,0 +1,104 @@
+package helpers
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+func TestSuiteReportName(t *testing.T) {
+	for path, want := range map[string]string{
+		"/src/hosted-providers-e2e/hosted/eks/p1":                        "eks-p1",
+		"/src/hosted-providers-e2e/hosted/gke/k8s_chart_support/upgrade": "gke-k8s-chart-support-upgrade",
+		"/src/hosted-providers-e2e":                                      "hosted-provider-rancher-environment-setup",
+	} {
+		report := ginkgo.Report{SuitePath: path, SuiteDescription: "Hosted Provider Rancher Environment Setup"}
+		if got := SuiteReportName(report); got != want {
+			t.Errorf("%s: got %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestGenerateSuiteReports(t *testing.T) {
+	defer func(dir string) { ArtifactsDir = dir }(ArtifactsDir)
+	ArtifactsDir = t.TempDir()
+
+	passed := types.SpecReport{
+		ContainerHierarchyTexts: []string{"P1Provisioning"},
+		LeafNodeType:            types.NodeTypeIt,
+		LeafNodeText:            "should scale",
+		State:                   types.SpecStatePassed,
+		RunTime:                 90 * time.Second,
+		NumAttempts:             2,
+	}
+	failed := types.SpecReport{
+		ContainerHierarchyTexts: []string{"P1Provisioning"},
+		LeafNodeType:            types.NodeTypeIt,
+		LeafNodeText:            "should upgrade",
+		State:                   types.SpecStateFailed,
+		NumAttempts:             1,
+		Failure:                 types.Failure{Message: "timed out"},
+		ReportEntries:           types.ReportEntries{{Name: clusterTransitionsEntry, Value: types.WrapEntryValue("first\nsecond")}},
+	}
+	beforeSuite := types.SpecReport{LeafNodeType: types.NodeTypeSynchronizedBeforeSuite, State: types.SpecStatePassed}
+	report := ginkgo.Report{
+		SuitePath:      "/src/hosted/eks/p1",
+		SuiteSucceeded: false,
+		SpecReports:    types.SpecReports{passed, failed, beforeSuite},
+	}
+
+	writeJSON := func(name string, value any) {
+		content, _ := json.Marshal(value)
+		if err := os.WriteFile(filepath.Join(ArtifactsDir, name), content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the manifest left by a process of an earlier run is not part of the report
+	writeJSON("resource-manifest-p3.json", ResourceManifest{RunID: "earlier", RancherHostname: RancherHostname, Resources: []*Resource{
+		{Kind: ResourceRancherCluster, Name: "auto-eks-hp-ci-3-fghij", ID: "c-fghij", Spec: passed.FullText()},
+	}})
+	writeJSON("resource-manifest-p1.json", ResourceManifest{RunID: runConfig.RunID, RancherHostname: RancherHostname, Resources: []*Resource{
+		{Kind: ResourceRancherCluster, Name: "auto-eks-hp-ci-1-abcde", ID: "c-abcde", KubernetesVersion: "1.30", Spec: passed.FullText()},
+		{Kind: ResourceCloudCredential, Name: "cc-abcde", Spec: passed.FullText()},
+	}})
+	writeJSON("operation-metrics-p2.json", []OperationMetric{{Operation: OperationScale, Seconds: 42, Success: true, Spec: passed.FullText()}})
+
+	GenerateSuiteReports(report)
+
+	for _, name := range []string{"eks-p1-report.json", "eks-p1-junit.xml"} {
+		if _, err := os.Stat(filepath.Join(ArtifactsDir, name)); err != nil {
+			t.Errorf("%s was not written: %v", name, err)
+		}
+	}
+
+	var summary SuiteSummary
+	content, err := os.ReadFile(filepath.Join(ArtifactsDir, "eks-p1-summary.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(content, &summary); err != nil {
+		t.Fatal(err)
+	}
+	if len(summary.Specs) != 2 || summary.Counts["passed"] != 1 || summary.Counts["failed"] != 1 {
+		t.Fatalf("unexpected specs in the summary: %s", content)
+	}
+	scale := summary.Specs[0]
+	if summary.PassedOnRetry != 1 || !scale.PassedOnRetry || summary.Specs[1].PassedOnRetry {
+		t.Errorf("unexpected specs passed on retry: %s", content)
+	}
+	if len(scale.Clusters) != 1 || scale.Clusters[0].ID != "c-abcde" || scale.Clusters[0].KubernetesVersion != "1.30" {
+		t.Errorf("unexpected clusters: %+v", scale.Clusters)
+	}
+	if len(scale.Operations) != 1 || scale.Operations[0].Seconds != 42 {
+		t.Errorf("unexpected operations: %+v", scale.Operations)
+	}
+	if upgrade := summary.Specs[1]; upgrade.Failure != "timed out" || len(upgrade.Clusters) != 0 || !reflect.DeepEqual(upgrade.Transitions, []string{"first", "second"}) {
+		t.Errorf("unexpected failed spec: %+v", upgrade)
+	}
+}
@@ -0,0 +1,651 @@
+package helpers
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+// Suite identifies a group of test suites sharing the same run config requirements
+type Suite string
+
+const (
+	// SuiteCommon is used by the suites that only need the common settings, for e.g. P0, P1 and support matrix
+	SuiteCommon Suite = "common"
+	// SuiteUpgrade is used by the suites that upgrade Rancher, for e.g. k8s chart support upgrade
+	SuiteUpgrade Suite = "upgrade"
+	// SuiteBackupRestore is used by the suites that reinstall the upstream cluster
+	SuiteBackupRestore Suite = "backup-restore"
+	// SuiteMultiProvider is used by the suites provisioning clusters of all the providers from the same Rancher; PROVIDER is not used
+	SuiteMultiProvider Suite = "multi-provider"
+	// SuiteMultiProviderBackupRestore is used by the multi-provider suites reinstalling the upstream cluster, for e.g. the disaster recovery
+	SuiteMultiProviderBackupRestore Suite = "multi-provider-backup-restore"
+	// SuiteChaos is used by the suites disrupting the upstream cluster, for e.g. by killing the operator pods or deactivating the kontainer drivers
+	SuiteChaos Suite = "chaos"
+	// SuiteAirgap is used by the suites that only make sense against an airgapped rancher, for e.g. the airgap k8s chart support
+	SuiteAirgap Suite = "airgap"
+	// SuiteSoak is used by the suites keeping a cluster under management for hours, for e.g. the drift detection soak
+	SuiteSoak Suite = "soak"
+	// SuiteChurn is used by the suites importing and removing clusters in a loop while watching the upstream cluster, for e.g. the registration churn
+	SuiteChurn Suite = "churn"
+	// SuiteCompatibility is used by the suites installing the Rancher versions and operator chart versions of the compatibility matrix
+	SuiteCompatibility Suite = "compatibility"
+)
+
+// multiProvider returns true for the suites provisioning clusters of all the providers
+func (s Suite) multiProvider() bool {
+	return s == SuiteMultiProvider || s == SuiteMultiProviderBackupRestore
+}
+
+// RunConfig holds the settings of a test run; it is loaded once from the environment and validated before the suites start.
+// New settings must be added here rather than read from the environment in the helpers.
+type RunConfig struct {
+	// Common settings
+	Provider         string
+	RancherHostname  string
+	RancherPassword  string
+	RancherVersion   string
+	CattleConfigPath string
+	ClusterCleanup   bool
+	IsImport         bool
+	ArtifactsDir     string
+	// RecycleCluster shares a cluster between the non-destructive specs of a process instead of provisioning one per spec; see RecycleCluster
+	RecycleCluster bool
+	// PreProvisionedClusters are the existing clusters the specs run against instead of provisioning one, for e.g. 74=c-m-abc12; see PreProvisionedCluster
+	PreProvisionedClusters []string
+	// DryRun only validates the configs of the clusters against the Rancher schema and the cloud provider, without creating them; see DryRunCluster
+	DryRun bool
+
+	// Traceability settings, added to the generated names and to the metadata labels of the clusters
+	RunID       string
+	PipelineURL string
+	Owner       string
+
+	// FeatureFlags are the Rancher feature flags set before the suites start, for e.g. "aggregated-roletemplates=true"; see ParseFeatureFlags
+	FeatureFlags string
+
+	// PushgatewayURL is the URL of the Prometheus pushgateway the operation metrics are pushed to; they are only written to the artifacts if empty
+	PushgatewayURL string
+
+	// GrafanaURL is the URL of the Grafana the start and the end of the suites are annotated on, with the GrafanaAPIToken service account token;
+	// see annotateSuiteStart
+	GrafanaURL      string
+	GrafanaAPIToken string
+
+	// Downstream cluster settings
+	DownstreamK8sMinorVersion string
+
+	// Rancher installation settings
+	Kubeconfig            string
+	K3sVersion            string
+	RancherBehindProxy    string
+	ProxyHost             string
+	NoProxy               string
+	PrivateRegistry       string
+	RancherCA             string
+	RancherHA             string
+	RancherInstallBackend string
+	K3SServerIP           string
+	// LocalClusterImage is the node image of the k3d or kind cluster of RancherInstallBackend, for e.g. kindest/node:v1.30.4;
+	// the k3s image of K3sVersion with k3d, the default image of kind if empty. See InstallLocalCluster
+	LocalClusterImage string
+
+	// Chart sources: the helm repository of the Rancher chart of the prime channels, the registry of the prime images,
+	// and the helm repository the operator charts are installed, upgraded and downgraded from
+	PrimeChartRepoURL     string
+	PrimeRegistry         string
+	OperatorChartsRepoURL string
+	// HelmChartCacheDir is the directory the chart archives installed by the suites are cached in, shared by the suites of the user; see CachedChart
+	HelmChartCacheDir string
+
+	// Upgrade suites settings
+	RancherUpgradeVersion   string
+	K8sUpgradedMinorVersion string
+
+	// ChartDowngradeVersions is the number of previous operator chart versions the k8s chart support suites downgrade to, one cycle per version
+	ChartDowngradeVersions int
+
+	// Provider settings; empty values mean the value of the cattle config file is used
+	EKSRegion    string
+	GKEProjectID string
+	GKEZone      string
+	GKERegion    string
+	AKSRegion    string
+
+	// Cloud credentials settings: with CloudCredentialSource oidc, the credentials of the providers are minted at the start of the suites from the OIDC
+	// token of the CI, read from OIDCTokenFile or requested to the OIDC endpoint of GitHub Actions, instead of the static keys; see AcquireCloudCredentials
+	CloudCredentialSource string
+	OIDCTokenFile         string
+	OIDCRequestURL        string
+	OIDCRequestToken      string
+	// AWSRoleARN is the role assumed with the OIDC token, allowed to create the access keys of AWSIAMUser
+	AWSRoleARN string
+	AWSIAMUser string
+	// GCPWorkloadIdentityProvider is the provider of the workload identity pool trusting the OIDC token, for e.g.
+	// projects/123456/locations/global/workloadIdentityPools/ci/providers/github, impersonating GCPServiceAccount
+	GCPWorkloadIdentityProvider string
+	GCPServiceAccount           string
+	// AKSTenantID is the tenant of the AKS_CLIENT_ID application, whose federated credential trusts the OIDC token
+	AKSTenantID string
+
+	// SecretsProvider is the secret manager the secrets of SecretsPath are fetched from and exported to the env, env (default), vault or aws-secrets-manager; see LoadSecrets
+	SecretsProvider string
+	SecretsPath     string
+
+	// ScenariosDir is the directory of the YAML scenarios of the Scenario suite; the scenarios of hosted/helpers/assets/scenarios if empty
+	ScenariosDir string
+
+	// Location matrix suites settings: the EKS regions and the GKE zones of a single region the clusters are provisioned in;
+	// empty values mean only the EKS region and the GKE zone are used
+	EKSMatrixRegions []string
+	GKEMatrixZones   []string
+
+	// Scale suites settings: number of nodepools of the cluster and number of nodes of every nodepool once scaled up
+	ScaleNodePools    int
+	ScaleNodesPerPool int
+
+	// Soak suites settings: how long the cluster is kept under management, and how often it is changed out-of-band
+	SoakDuration time.Duration
+	SoakInterval time.Duration
+
+	// ChurnCycles is the number of import/removal cycles of the churn suites
+	ChurnCycles int
+
+	// CompatibilityMatrix is the path of the compatibility matrix of the operator charts, from the directory of the suites; see LoadCompatibilityMatrix
+	CompatibilityMatrix string
+
+	// Qase settings: the results of the specs are reported to the QASE_RUN_ID run of the QASE_PROJECT_CODE project, created by the suites
+	// if it is auto; nothing is reported if it is empty. See ReportToQase.
+	QaseAPIToken      string
+	QaseProjectCode   string
+	QaseRunID         string
+	QaseEnvironmentID int
+	// QaseTestPlanID restricts the specs run to the cases of the Qase test plan, if set; see SuiteConfig
+	QaseTestPlanID int
+	// QaseCreateDefects opens a Qase defect for the new failures, deduplicated by failure signature; see linkQaseDefects
+	QaseCreateDefects bool
+
+	// ArtifactsBucket is the S3 or GCS bucket URL the artifacts of the failed specs are uploaded to; see UploadArtifactsOnFailure
+	ArtifactsBucket string
+
+	// CostPrices override the prices of the cost estimate, for e.g. t3.large=0.0832; see estimateCost
+	CostPrices []string
+
+	// Polling settings of EventuallyWithBackoff; a factor of 1 polls at a fixed interval
+	PollBackoffFactor float64
+	PollMaxInterval   time.Duration
+	PollJitter        float64
+	// PollProfiles override the polling profiles of the operations, for e.g. upgrade=30s:3m; see EventuallyForOperation
+	PollProfiles []string
+
+	// FailFastAfter is how long a cluster can stay in error before the waits abort, instead of waiting out their timeout; 0, the default, disables
+	// the fail-fast.
+	// The cloud errors no retry can fix abort them at once. See WaitClusterToBeUpgraded
+	FailFastAfter time.Duration
+
+	// Time budget of the suite, see SkipOverBudget: SuiteDeadline is the RFC3339 time the suite must be done by, for e.g. the deadline of the CI job,
+	// and SuiteTimeBudget the time it is given from its start; CleanupReserve is kept for the cleanup before the deadline,
+	// SpecTimeEstimate is the time a spec needs to start once the budget is nearly exhausted
+	SuiteDeadline    string
+	SuiteTimeBudget  time.Duration
+	CleanupReserve   time.Duration
+	SpecTimeEstimate time.Duration
+
+	// ProvisioningBackend creates the hosted clusters with the rancher2 Terraform provider if it is terraform, with the Rancher API otherwise;
+	// TerraformProviderVersion is the version constraint of the provider. See CreateClusterWithTerraform
+	ProvisioningBackend      string
+	TerraformProviderVersion string
+
+	// Profile is the profiling mode of the suites, empty if they are not profiled; see StartProfiling
+	Profile string
+
+	// VersionCatalogTTL is how long the version queries cached by the version catalog are reused, for the whole process if 0; see CachedVersions
+	VersionCatalogTTL time.Duration
+}
+
+// runConfig is the config of the current run
+var runConfig = LoadRunConfig()
+
+// CurrentRunConfig returns the config of the current run
+func CurrentRunConfig() *RunConfig {
+	return runConfig
+}
+
+// envOrDefault returns the value of the env variable, or defaultValue if it is empty
+func envOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// envFloat returns the value of the env variable as a float, defaultValue if it is empty, or -1 if it is not a number so that Validate reports it
+func envFloat(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return -1
+	}
+	return f
+}
+
+// envInt returns the value of the env variable as an int, defaultValue if it is empty, or -1 if it is not a number so that Validate reports it
+func envInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return -1
+	}
+	return i
+}
+
+// envDuration returns the value of the env variable as a duration, defaultValue if it is empty, or -1 if it is not a duration so that Validate reports it
+func envDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return -1
+	}
+	return d
+}
+
+// envList returns the comma separated values of the env variable, or nil if it is empty
+func envList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// defaultRunID returns the ID of the GitHub Actions run, or an ID shared by the parallel processes of a local run
+func defaultRunID() string {
+	if runID := os.Getenv("GITHUB_RUN_ID"); runID != "" {
+		return runID
+	}
+	return fmt.Sprintf("local%d", os.Getppid())
+}
+
+// defaultPipelineURL returns the URL of the GitHub Actions run, if any
+func defaultPipelineURL() string {
+	if os.Getenv("GITHUB_RUN_ID") == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/actions/runs/%s", os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_RUN_ID"))
+}
+
+// defaultOwner returns the owner of the resources of a run; the current user for local runs
+func defaultOwner() string {
+	username := "unknown"
+	if u, err := user.Current(); err == nil {
+		username = u.Username
+	}
+	return "hosted-providers-qa-ci-" + username
+}
+
+// LoadRunConfig reads the run config from the environment
+func LoadRunConfig() *RunConfig {
+	clusterCleanup, _ := strconv.ParseBool(os.Getenv("DOWNSTREAM_CLUSTER_CLEANUP"))
+	qaseCreateDefects, _ := strconv.ParseBool(os.Getenv("QASE_CREATE_DEFECTS"))
+	recycleCluster, _ := strconv.ParseBool(os.Getenv("RECYCLE_CLUSTER"))
+	dryRun, _ := strconv.ParseBool(os.Getenv("DRY_RUN"))
+	cattleConfigPath := os.Getenv("CATTLE_TEST_CONFIG")
+
+	return &RunConfig{
+		Provider:         os.Getenv("PROVIDER"),
+		RancherHostname:  os.Getenv("RANCHER_HOSTNAME"),
+		RancherPassword:  os.Getenv("RANCHER_PASSWORD"),
+		RancherVersion:   os.Getenv("RANCHER_VERSION"),
+		CattleConfigPath: cattleConfigPath,
+		ClusterCleanup:   clusterCleanup,
+		IsImport:         strings.Contains(cattleConfigPath, "import"),
+		ArtifactsDir:     envOrDefault("ARTIFACTS_DIR", "artifacts"),
+		RecycleCluster:   recycleCluster,
+		DryRun:           dryRun,
+
+		PreProvisionedClusters: envList("PREPROVISIONED_CLUSTERS"),
+
+		RunID:       envOrDefault("RUN_ID", defaultRunID()),
+		PipelineURL: envOrDefault("PIPELINE_URL", defaultPipelineURL()),
+		Owner:       envOrDefault("OWNER", defaultOwner()),
+
+		FeatureFlags: os.Getenv("FEATURE_FLAGS"),
+
+		PushgatewayURL: strings.TrimSuffix(os.Getenv("PUSHGATEWAY_URL"), "/"),
+
+		GrafanaURL:      strings.TrimSuffix(os.Getenv("GRAFANA_URL"), "/"),
+		GrafanaAPIToken: os.Getenv("GRAFANA_API_TOKEN"),
+
+		DownstreamK8sMinorVersion: os.Getenv("DOWNSTREAM_K8S_MINOR_VERSION"),
+
+		Kubeconfig:         os.Getenv("KUBECONFIG"),
+		K3sVersion:         os.Getenv("INSTALL_K3S_VERSION"),
+		RancherBehindProxy: os.Getenv("RANCHER_BEHIND_PROXY"),
+		ProxyHost:          strings.TrimPrefix(strings.TrimPrefix(envOrDefault("PROXY_HOST", "172.17.0.1:3128"), "http://"), "https://"),
+		NoProxy:            envOrDefault("RANCHER_NO_PROXY", "127.0.0.0/8,10.0.0.0/8,cattle-system.svc,172.16.0.0/12,192.168.0.0/16,.svc,.cluster.local"),
+		PrivateRegistry:    os.Getenv("PRIVATE_REGISTRY"),
+		RancherCA:          os.Getenv("RANCHER_CA"),
+		RancherHA:          os.Getenv("RANCHER_HA"),
+		// either helm on k3s (default), docker, or helm on a local k3d or kind cluster
+		RancherInstallBackend: os.Getenv("RANCHER_INSTALL_BACKEND"),
+		LocalClusterImage:     os.Getenv("LOCAL_CLUSTER_IMAGE"),
+		K3SServerIP:           envOrDefault("K3S_SERVER_IP", "172.17.0.1"),
+
+		PrimeChartRepoURL:     os.Getenv("PRIME_CHART_REPO_URL"),
+		PrimeRegistry:         envOrDefault("PRIME_REGISTRY", defaultPrimeRegistry),
+		OperatorChartsRepoURL: envOrDefault("OPERATOR_CHARTS_REPO_URL", defaultOperatorChartsRepoURL),
+		HelmChartCacheDir:     envOrDefault("HELM_CHART_CACHE_DIR", defaultHelmChartCacheDir()),
+
+		RancherUpgradeVersion:   os.Getenv("RANCHER_UPGRADE_VERSION"),
+		K8sUpgradedMinorVersion: os.Getenv("K8S_UPGRADE_MINOR_VERSION"),
+
+		ChartDowngradeVersions: envInt("CHART_DOWNGRADE_VERSIONS", 1),
+
+		EKSRegion:    os.Getenv("EKS_REGION"),
+		GKEProjectID: os.Getenv("GKE_PROJECT_ID"),
+		GKEZone:      os.Getenv("GKE_ZONE"),
+		GKERegion:    os.Getenv("GKE_REGION"),
+		AKSRegion:    os.Getenv("AKS_REGION"),
+
+		CloudCredentialSource:       os.Getenv("CLOUD_CREDENTIAL_SOURCE"),
+		OIDCTokenFile:               os.Getenv("OIDC_TOKEN_FILE"),
+		OIDCRequestURL:              os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL"),
+		OIDCRequestToken:            os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN"),
+		AWSRoleARN:                  os.Getenv("AWS_ROLE_ARN"),
+		AWSIAMUser:                  os.Getenv("AWS_IAM_USER"),
+		GCPWorkloadIdentityProvider: os.Getenv("GCP_WORKLOAD_IDENTITY_PROVIDER"),
+		GCPServiceAccount:           os.Getenv("GCP_SERVICE_ACCOUNT"),
+		AKSTenantID:                 os.Getenv("AKS_TENANT_ID"),
+
+		SecretsProvider: os.Getenv("SECRETS_PROVIDER"),
+		SecretsPath:     os.Getenv("SECRETS_PATH"),
+
+		ScenariosDir: os.Getenv("SCENARIOS_DIR"),
+
+		EKSMatrixRegions: envList("EKS_MATRIX_REGIONS"),
+		GKEMatrixZones:   envList("GKE_MATRIX_ZONES"),
+
+		ScaleNodePools:    envInt("SCALE_NODEPOOLS", 10),
+		ScaleNodesPerPool: envInt("SCALE_NODES_PER_POOL", 3),
+
+		SoakDuration: envDuration("SOAK_DURATION", 4*time.Hour),
+		SoakInterval: envDuration("SOAK_INTERVAL", 30*time.Minute),
+
+		ChurnCycles: envInt("CHURN_CYCLES", 24),
+
+		CompatibilityMatrix: envOrDefault("COMPATIBILITY_MATRIX", "../../helpers/assets/compatibility-matrix.yaml"),
+
+		QaseAPIToken:      os.Getenv("QASE_API_TOKEN"),
+		QaseProjectCode:   os.Getenv("QASE_PROJECT_CODE"),
+		QaseRunID:         os.Getenv("QASE_RUN_ID"),
+		QaseEnvironmentID: envInt("QASE_ENVIRONMENT_ID", 0),
+		QaseTestPlanID:    envInt("QASE_TEST_PLAN_ID", 0),
+		QaseCreateDefects: qaseCreateDefects,
+
+		ArtifactsBucket: os.Getenv("ARTIFACTS_BUCKET"),
+
+		CostPrices: envList("COST_PRICES"),
+
+		PollBackoffFactor: envFloat("POLL_BACKOFF_FACTOR", 1.5),
+		PollMaxInterval:   envDuration("POLL_MAX_INTERVAL", time.Minute),
+		PollJitter:        envFloat("POLL_JITTER", 0.2),
+		PollProfiles:      envList("POLL_PROFILES"),
+
+		FailFastAfter: envDuration("FAIL_FAST_AFTER", 0),
+
+		SuiteDeadline:    os.Getenv("SUITE_DEADLINE"),
+		SuiteTimeBudget:  envDuration("SUITE_TIME_BUDGET", 0),
+		CleanupReserve:   envDuration("CLEANUP_RESERVE", 20*time.Minute),
+		SpecTimeEstimate: envDuration("SPEC_TIME_ESTIMATE", 30*time.Minute),
+
+		VersionCatalogTTL: envDuration("VERSION_CATALOG_TTL", 0),
+
+		Profile: os.Getenv("PROFILE"),
+
+		ProvisioningBackend:      os.Getenv("PROVISIONING_BACKEND"),
+		TerraformProviderVersion: envOrDefault("TERRAFORM_RANCHER2_VERSION", ">= 4.0.0"),
+	}
+}
+
+// SkipUpgradeTests returns true if the k8s upgrade tests can not run with the Rancher version under test,
+// since only one minor k8s version is supported by v2.8
+func (c *RunConfig) SkipUpgradeTests() bool {
+	return strings.Contains(c.RancherVersion, "2.8")
+}
+
+// SkipTest returns true for the features not available on v2.8 and v2.9
+func (c *RunConfig) SkipTest() bool {
+	return strings.Contains(c.RancherVersion, "2.8") || strings.Contains(c.RancherVersion, "2.9")
+}
+
+// Validate returns the problems found in the run config for the given suite;
+// the common and provider settings are always validated, suite settings only for the matching suite.
+func (c *RunConfig) Validate(suite Suite) (problems []string) {
+	if c.RancherHostname == "" {
+		problems = append(problems, "RANCHER_HOSTNAME is not set; export the public DNS of rancher, for e.g. 1.2.3.4.sslip.io")
+	} else if strings.Contains(c.RancherHostname, "://") {
+		problems = append(problems, fmt.Sprintf("RANCHER_HOSTNAME %q must not contain the scheme; for e.g. use 1.2.3.4.sslip.io instead of https://1.2.3.4.sslip.io", c.RancherHostname))
+	}
+	if c.RancherPassword == "" {
+		problems = append(problems, "RANCHER_PASSWORD is not set; export the password of the rancher admin user")
+	}
+	if _, ok := providerCLI[c.Provider]; !ok && (!suite.multiProvider() || c.Provider != "") {
+		problems = append(problems, fmt.Sprintf("PROVIDER %q is not supported; acceptable values are eks, gke and aks", c.Provider))
+	}
+
+	if IsK8sVersionConstraint(c.DownstreamK8sMinorVersion) {
+		if _, err := parseK8sVersionConstraint(c.DownstreamK8sMinorVersion); err != nil {
+			problems = append(problems, fmt.Sprintf("DOWNSTREAM_K8S_MINOR_VERSION %q is not valid; either a X.Y version or a semver constraint is expected, for e.g. 1.30, <=1.29 or 1.28.x", c.DownstreamK8sMinorVersion))
+		}
+	}
+	if c.K8sUpgradedMinorVersion != "" && !minorVersionRegex.MatchString(c.K8sUpgradedMinorVersion) {
+		problems = append(problems, fmt.Sprintf("K8S_UPGRADE_MINOR_VERSION %q is not valid; only the X.Y version is expected, for e.g. 1.30", c.K8sUpgradedMinorVersion))
+	}
+	for _, env := range [][2]string{
+		{"RANCHER_VERSION", c.RancherVersion},
+		{"RANCHER_UPGRADE_VERSION", c.RancherUpgradeVersion},
+	} {
+		if env[1] != "" && !strings.Contains(env[1], "/") {
+			problems = append(problems, fmt.Sprintf("%s %q is not valid; the expected format is channel/version, for e.g. latest/2.9.0 or latest/devel/2.9", env[0], env[1]))
+		}
+	}
+
+	for _, env := range [][2]string{
+		{"PRIME_CHART_REPO_URL", c.PrimeChartRepoURL},
+		{"OPERATOR_CHARTS_REPO_URL", c.OperatorChartsRepoURL},
+	} {
+		if env[1] != "" && !isChartRepoURL(env[1]) {
+			problems = append(problems, fmt.Sprintf("%s %q is not valid; an http(s) URL is expected, for e.g. https://charts.rancher.io", env[0], env[1]))
+		}
+	}
+	if c.HelmChartCacheDir != "" && c.HelmChartCacheDir != helmChartCacheOff && !filepath.IsAbs(c.HelmChartCacheDir) {
+		problems = append(problems, fmt.Sprintf("HELM_CHART_CACHE_DIR %q is not valid; an absolute path is expected so that the suites share it, or %s to disable the cache", c.HelmChartCacheDir, helmChartCacheOff))
+	}
+	if strings.Contains(c.PrimeRegistry, "/") {
+		problems = append(problems, fmt.Sprintf("PRIME_REGISTRY %q is not valid; only the registry host[:port] is expected, for e.g. registry.rancher.com", c.PrimeRegistry))
+	}
+
+	if _, err := ParseFeatureFlags(c.FeatureFlags); err != nil {
+		problems = append(problems, fmt.Sprintf("FEATURE_FLAGS is not valid: %v", err))
+	}
+
+	if c.ChartDowngradeVersions < 1 {
+		problems = append(problems, "CHART_DOWNGRADE_VERSIONS is not valid; a positive number is expected, for e.g. 3")
+	}
+
+	if c.ScaleNodePools < 2 {
+		problems = append(problems, "SCALE_NODEPOOLS is not valid; a number greater than or equal to 2 is expected, for e.g. 10")
+	}
+	if c.ScaleNodesPerPool < 1 {
+		problems = append(problems, "SCALE_NODES_PER_POOL is not valid; a positive number is expected, for e.g. 3")
+	}
+
+	for _, zone := range c.GKEMatrixZones {
+		if !gkeZoneRegex.MatchString(zone) {
+			problems = append(problems, fmt.Sprintf("GKE_MATRIX_ZONES %q is not valid; zones are expected, for e.g. asia-south2-a,asia-south2-c", zone))
+		} else if GKERegionOfZone(zone) != GKERegionOfZone(c.GKEMatrixZones[0]) {
+			problems = append(problems, fmt.Sprintf("GKE_MATRIX_ZONES %q is not valid; the zones must be in the same region", zone))
+		}
+	}
+
+	if c.QaseRunID != "" {
+		if id, err := strconv.ParseInt(c.QaseRunID, 10, 32); c.QaseRunID != qaseAutoRun && (err != nil || id <= 0) {
+			problems = append(problems, fmt.Sprintf("QASE_RUN_ID %q is not valid; either the ID of an existing run or %s is expected", c.QaseRunID, qaseAutoRun))
+		}
+		if c.QaseAPIToken == "" || c.QaseProjectCode == "" {
+			problems = append(problems, "QASE_API_TOKEN and QASE_PROJECT_CODE must be set to report the results to Qase")
+		}
+	}
+	if c.QaseEnvironmentID < 0 {
+		problems = append(problems, "QASE_ENVIRONMENT_ID is not valid; the ID of a Qase environment is expected")
+	}
+	if c.QaseTestPlanID < 0 || int64(c.QaseTestPlanID) > math.MaxInt32 {
+		problems = append(problems, "QASE_TEST_PLAN_ID is not valid; the ID of a Qase test plan is expected")
+	} else if c.QaseTestPlanID > 0 && (c.QaseAPIToken == "" || c.QaseProjectCode == "") {
+		problems = append(problems, "QASE_API_TOKEN and QASE_PROJECT_CODE must be set to run the cases of a Qase test plan")
+	}
+
+	if c.GrafanaURL != "" && c.GrafanaAPIToken == "" {
+		problems = append(problems, "GRAFANA_API_TOKEN must be set to annotate the suites on GRAFANA_URL")
+	}
+
+	if _, err := parseCostPrices(c.CostPrices); err != nil {
+		problems = append(problems, fmt.Sprintf("COST_PRICES is not valid: %v", err))
+	}
+
+	if c.PollBackoffFactor < 1 {
+		problems = append(problems, "POLL_BACKOFF_FACTOR is not valid; a number greater than or equal to 1 is expected, for e.g. 1.5")
+	}
+	if c.PollMaxInterval <= 0 {
+		problems = append(problems, "POLL_MAX_INTERVAL is not valid; a positive duration is expected, for e.g. 1m")
+	}
+	if c.PollJitter < 0 || c.PollJitter >= 1 {
+		problems = append(problems, "POLL_JITTER is not valid; a number between 0 and 1 is expected, for e.g. 0.2")
+	}
+	if _, err := parsePreProvisionedClusters(c.PreProvisionedClusters); err != nil {
+		problems = append(problems, fmt.Sprintf("PREPROVISIONED_CLUSTERS is not valid: %v", err))
+	}
+
+	if _, err := parsePollProfiles(c.PollProfiles); err != nil {
+		problems = append(problems, fmt.Sprintf("POLL_PROFILES is not valid: %v", err))
+	}
+	if c.FailFastAfter < 0 {
+		problems = append(problems, "FAIL_FAST_AFTER is not valid; a duration is expected, for e.g. 10m, or 0 to always wait out the timeouts")
+	}
+	if _, err := parseSuiteDeadline(c.SuiteDeadline); err != nil {
+		problems = append(problems, fmt.Sprintf("SUITE_DEADLINE %q is not valid; an RFC3339 time is expected, for e.g. 2024-06-01T18:00:00Z", c.SuiteDeadline))
+	}
+	if c.SuiteTimeBudget < 0 {
+		problems = append(problems, "SUITE_TIME_BUDGET is not valid; a duration is expected, for e.g. 2h30m, or 0 for no budget but the ginkgo --timeout")
+	}
+	if c.CleanupReserve < 0 {
+		problems = append(problems, "CLEANUP_RESERVE is not valid; a duration is expected, for e.g. 30m")
+	}
+	if c.SpecTimeEstimate < 0 {
+		problems = append(problems, "SPEC_TIME_ESTIMATE is not valid; a duration is expected, for e.g. 45m")
+	}
+	if c.VersionCatalogTTL < 0 {
+		problems = append(problems, "VERSION_CATALOG_TTL is not valid; a duration is expected, for e.g. 2h, or 0 to never refresh the cached versions")
+	}
+	if c.ProvisioningBackend != "" && c.ProvisioningBackend != "api" && c.ProvisioningBackend != provisioningBackendTerraform {
+		problems = append(problems, fmt.Sprintf("PROVISIONING_BACKEND %q is not valid; acceptable values are api and %s", c.ProvisioningBackend, provisioningBackendTerraform))
+	}
+	if c.CloudCredentialSource != "" && c.CloudCredentialSource != cloudCredentialSourceStatic && c.CloudCredentialSource != cloudCredentialSourceOIDC {
+		problems = append(problems, fmt.Sprintf("CLOUD_CREDENTIAL_SOURCE %q is not valid; acceptable values are %s and %s", c.CloudCredentialSource, cloudCredentialSourceStatic, cloudCredentialSourceOIDC))
+	}
+	switch c.RancherInstallBackend {
+	case "", rancherBackendDocker, localClusterK3d, localClusterKind:
+	default:
+		problems = append(problems, fmt.Sprintf("RANCHER_INSTALL_BACKEND %q is not valid; acceptable values are %s, %s and %s, or empty to install rancher on k3s",
+			c.RancherInstallBackend, rancherBackendDocker, localClusterK3d, localClusterKind))
+	}
+	if c.Profile != "" && c.Profile != profileWall && c.Profile != profilePprof {
+		problems = append(problems, fmt.Sprintf("PROFILE %q is not valid; acceptable values are %s and %s", c.Profile, profileWall, profilePprof))
+	}
+
+	if (c.Provider == "gke" || suite.multiProvider()) && c.GKEProjectID == "" {
+		problems = append(problems, "GKE_PROJECT_ID is not set; it is required to run the gke tests")
+	}
+
+	required := func(env, value string) {
+		if value == "" {
+			problems = append(problems, fmt.Sprintf("%s is not set; it is required to run the %s suites", env, suite))
+		}
+	}
+	switch suite {
+	case SuiteUpgrade:
+		required("KUBECONFIG", c.Kubeconfig)
+		required("RANCHER_VERSION", c.RancherVersion)
+		required("RANCHER_UPGRADE_VERSION", c.RancherUpgradeVersion)
+		required("K8S_UPGRADE_MINOR_VERSION", c.K8sUpgradedMinorVersion)
+		if strings.Contains(c.RancherVersion, "devel") {
+			problems = append(problems, fmt.Sprintf("RANCHER_VERSION %q must be a released version to run the %s suites", c.RancherVersion, suite))
+		}
+	case SuiteBackupRestore, SuiteMultiProviderBackupRestore:
+		required("KUBECONFIG", c.Kubeconfig)
+		required("INSTALL_K3S_VERSION", c.K3sVersion)
+		if c.RancherInstallBackend == localClusterK3d || c.RancherInstallBackend == localClusterKind {
+			// the upstream k3s is wiped and installed again, see WipeRancher
+			problems = append(problems, fmt.Sprintf("RANCHER_INSTALL_BACKEND %s is not supported by the %s suites, which reinstall k3s", c.RancherInstallBackend, suite))
+		}
+	case SuiteChaos:
+		required("KUBECONFIG", c.Kubeconfig)
+	case SuiteAirgap:
+		required("KUBECONFIG", c.Kubeconfig)
+		required("PRIVATE_REGISTRY", c.PrivateRegistry)
+	case SuiteSoak:
+		// the soak must end before the 24h timeout of the make target, provisioning and deletion of the cluster included
+		if c.SoakDuration <= 0 || c.SoakDuration > maxSoakDuration {
+			problems = append(problems, fmt.Sprintf("SOAK_DURATION is not valid; a positive duration up to %s is expected, for e.g. 4h", maxSoakDuration))
+		}
+		if c.SoakInterval < soakSettleTime || (c.SoakDuration > 0 && c.SoakInterval > c.SoakDuration) {
+			problems = append(problems, fmt.Sprintf("SOAK_INTERVAL is not valid; a duration between %s and SOAK_DURATION is expected, for e.g. 30m", soakSettleTime))
+		}
+	case SuiteChurn:
+		// the usage of the upstream pods and its namespaces and secrets are read with kubectl
+		required("KUBECONFIG", c.Kubeconfig)
+		// both an active and a registering cluster must be removed
+		if c.ChurnCycles < 2 {
+			problems = append(problems, "CHURN_CYCLES is not valid; a number greater than or equal to 2 is expected, for e.g. 24")
+		}
+	case SuiteCompatibility:
+		// Rancher is installed back to RANCHER_VERSION once a matrix entry is done
+		required("KUBECONFIG", c.Kubeconfig)
+		required("RANCHER_VERSION", c.RancherVersion)
+		matrix, err := LoadCompatibilityMatrix(c.CompatibilityMatrix)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("COMPATIBILITY_MATRIX is not valid: %v", err))
+		}
+		for _, entry := range matrix {
+			if rancherDowngrade(entry.Rancher, c.RancherVersion) {
+				problems = append(problems, fmt.Sprintf("COMPATIBILITY_MATRIX is not valid: rancher %s is lower than RANCHER_VERSION %s, and rancher can not be downgraded", entry.Rancher, c.RancherVersion))
+			}
+		}
+	}
+	return
+}
+
+// ValidateRunConfig fails if the run config is not valid for the given suite; the secrets are loaded first since some settings can be secrets
+func ValidateRunConfig(suite Suite) {
+	setupSecrets(nil)
+	problems := runConfig.Validate(suite)
+	Expect(problems).To(BeEmpty(), fmt.Sprintf("Invalid run config for the %s suites:\n - %s", suite, strings.Join(problems, "\n - ")))
+}
@@ -0,0 +1,184 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/rancher/shepherd/clients/rancher"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+)
+
+// runMetadataEntry is the name of the report entry holding the metadata of the run, in the JSON report of the suite
+const runMetadataEntry = "Run metadata"
+
+// RunMetadata is the environment of a test run, written to run-metadata.json so that the run can be reproduced,
+// and added to the title, description and tags of its Qase run and to the suite summaries
+type RunMetadata struct {
+	// Providers are the providers under test, for e.g. eks; all the MultiProviders for the multi-provider suites
+	Providers []string `json:"providers"`
+	// Locations are the regions/zones the clusters are provisioned in, by provider
+	Locations            map[string]string `json:"locations"`
+	RancherVersion       string            `json:"rancherVersion"`
+	RancherServerVersion string            `json:"rancherServerVersion,omitempty"`
+	// OperatorChartVersions are the versions of the operator charts installed by Rancher, by provider
+	OperatorChartVersions map[string]string `json:"operatorChartVersions"`
+	UpstreamK8sVersion    string            `json:"upstreamK8sVersion,omitempty"`
+	DownstreamK8sVersion  string            `json:"downstreamK8sVersion,omitempty"`
+	// CLIVersions are the versions of the provider CLIs, kubectl and helm used by the helpers, by CLI
+	CLIVersions map[string]string `json:"cliVersions,omitempty"`
+	// GitSHA is the commit of the e2e tests
+	GitSHA      string    `json:"gitSHA,omitempty"`
+	RunID       string    `json:"runID"`
+	PipelineURL string    `json:"pipelineURL,omitempty"`
+	RecordedAt  time.Time `json:"recordedAt"`
+}
+
+// providerLocation returns the region/zone the clusters of the provider are provisioned in
+func providerLocation(provider string) string {
+	switch provider {
+	case "eks":
+		return GetEKSRegion()
+	case "gke":
+		return GetGKEZone()
+	case "aks":
+		return GetAKSLocation()
+	}
+	return ""
+}
+
+// newRunMetadata returns the metadata of the run known from the run config; the versions read from Rancher are left empty
+func newRunMetadata(providers []string) *RunMetadata {
+	metadata := &RunMetadata{
+		Providers:             providers,
+		Locations:             map[string]string{},
+		RancherVersion:        runConfig.RancherVersion,
+		OperatorChartVersions: map[string]string{},
+		DownstreamK8sVersion:  runConfig.DownstreamK8sMinorVersion,
+		RunID:                 runConfig.RunID,
+		PipelineURL:           runConfig.PipelineURL,
+		RecordedAt:            time.Now().UTC(),
+	}
+	for _, provider := range providers {
+		metadata.Locations[provider] = providerLocation(provider)
+	}
+	return metadata
+}
+
+// operatorChartVersion returns the version of the operator chart of the provider installed by Rancher, read from its catalog app
+func operatorChartVersion(client *rancher.Client, provider string) (string, error) {
+	app, err := client.Steve.SteveType("catalog.cattle.io.app").ByID(fmt.Sprintf("%s/rancher-%s-operator", CattleSystemNS, provider))
+	if err != nil {
+		return "", err
+	}
+	var spec struct {
+		Chart struct {
+			Metadata struct {
+				Version string `json:"version"`
+			} `json:"metadata"`
+		} `json:"chart"`
+	}
+	if err = steveV1.ConvertToK8sType(app.Spec, &spec); err != nil {
+		return "", err
+	}
+	return spec.Chart.Metadata.Version, nil
+}
+
+// cliVersionArgs are the arguments printing the version of the CLIs used by the helpers, on the first line of their output
+var cliVersionArgs = map[*extcli.CLI][]string{
+	extcli.Kubectl: {"version", "--client"},
+	extcli.Helm:    {"version", "--short"},
+	extcli.Eksctl:  {"version"},
+	extcli.AWS:     {"--version"},
+	extcli.Gcloud:  {"--version"},
+	extcli.Az:      {"version", "--query", `"azure-cli"`, "--output", "tsv"},
+}
+
+// cliVersions returns the versions of the CLIs which are installed, by CLI
+func cliVersions() map[string]string {
+	versions := map[string]string{}
+	for cli, args := range cliVersionArgs {
+		out, err := cli.Output(args...)
+		if err != nil {
+			continue
+		}
+		if line, _, _ := strings.Cut(strings.TrimSpace(out), "\n"); line != "" {
+			versions[cli.Name()] = strings.TrimSpace(line)
+		}
+	}
+	return versions
+}
+
+// gitSHA returns the commit of the e2e tests: GITHUB_SHA on GitHub Actions, HEAD of the working tree otherwise
+func gitSHA() string {
+	if sha := os.Getenv("GITHUB_SHA"); sha != "" {
+		return sha
+	}
+	out, err := extcli.New("git").Output("rev-parse", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// writeRunMetadata writes the metadata to run-metadata.json in ArtifactsDir
+func writeRunMetadata(metadata *RunMetadata) error {
+	content, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(ArtifactsDir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(ArtifactsDir, "run-metadata.json"), content, 0o644)
+}
+
+/*
+Record the environment of the run: the versions of Rancher, of the operator charts, of the CLIs and of kubernetes, the locations and the git SHA
+of the tests. The first parallel process writes it to run-metadata.json in ArtifactsDir at the start of every suite, it is added to the JSON report,
+to the suite summary (see GenerateSuiteReports) and to the Qase run created when QASE_RUN_ID is auto (see ReportToQase).
+It is done by CommonBeforeSuite, ParallelBeforeSuite and MultiProviderParallelBeforeSuite, the versions that can not be read are logged and left out.
+  - @param client Rancher client
+  - @param providers Providers under test, for e.g. Provider
+  - @returns Nothing
+*/
+func RecordRunMetadata(client *rancher.Client, providers ...string) {
+	metadata := newRunMetadata(providers)
+
+	var err error
+	if metadata.RancherServerVersion, err = GetRancherServerVersion(client); err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to get the rancher server version for the run metadata: %v", err))
+	}
+	if local, err := client.Management.Cluster.ByID("local"); err != nil || local.Version == nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to get the upstream k8s version for the run metadata: %v", err))
+	} else {
+		metadata.UpstreamK8sVersion = local.Version.GitVersion
+	}
+	for _, provider := range providers {
+		version, err := operatorChartVersion(client, provider)
+		if err != nil {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to get the %s operator chart version for the run metadata: %v", provider, err))
+			continue
+		}
+		metadata.OperatorChartVersions[provider] = version
+	}
+	metadata.CLIVersions = cliVersions()
+	metadata.GitSHA = gitSHA()
+	runMetadata = metadata
+
+	ginkgo.AddReportEntry(runMetadataEntry, metadata, ginkgo.ReportEntryVisibilityNever)
+	if ginkgo.GinkgoParallelProcess() == 1 {
+		if err = writeRunMetadata(metadata); err != nil {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to write the run metadata: %v", err))
+		}
+	}
+}
+
+// runMetadata is the metadata of the run recorded by RecordRunMetadata, if any
+var runMetadata *RunMetadata
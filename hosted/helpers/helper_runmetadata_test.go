@@ -0,0 +1,31 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteRunMetadata(t *testing.T) {
+	defer func(dir string) { ArtifactsDir = dir }(ArtifactsDir)
+	ArtifactsDir = t.TempDir()
+
+	metadata := newRunMetadata([]string{"eks"})
+	metadata.OperatorChartVersions["eks"] = "105.0.0"
+	metadata.CLIVersions = map[string]string{"eksctl": "0.190.0"}
+	metadata.GitSHA = "0a1b2c3"
+	if err := writeRunMetadata(metadata); err != nil {
+		t.Fatalf("writeRunMetadata() = %v", err)
+	}
+
+	read := readArtifacts[RunMetadata]("run-metadata.json")
+	if len(read) != 1 {
+		t.Fatalf("got %d run metadata, want 1", len(read))
+	}
+	if read[0].OperatorChartVersions["eks"] != "105.0.0" || read[0].CLIVersions["eksctl"] != "0.190.0" || read[0].GitSHA != "0a1b2c3" ||
+		len(read[0].Providers) != 1 || !read[0].RecordedAt.Equal(metadata.RecordedAt) {
+		t.Errorf("unexpected run metadata: %+v", read[0])
+	}
+	if description := read[0].Description(); !strings.Contains(description, "Git SHA: 0a1b2c3") || !strings.Contains(description, "CLI versions: eksctl=0.190.0") {
+		t.Errorf("unexpected description: %s", description)
+	}
+}
@@ -0,0 +1,87 @@
+package helpers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/norman/types"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// activeNodeCount returns the number of management nodes of the cluster, and how many of them are active
+func activeNodeCount(client *rancher.Client, clusterID string) (total, active int, err error) {
+	nodes, err := client.Management.Node.List(&types.ListOpts{Filters: map[string]interface{}{"clusterId": clusterID}})
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, node := range nodes.Data {
+		if node.State == "active" {
+			active++
+		}
+	}
+	return len(nodes.Data), active, nil
+}
+
+/*
+Wait until the cluster has exactly count nodes in Rancher, all of them active; the time since start is recorded as OperationNodesReady,
+so that start can be set before the node count is changed to measure the whole reconcile latency, for e.g.
+start := time.Now(); cluster, err = helper.ScaleNodeGroup(...); helpers.WaitUntilNodeCount(client, cluster, count, start)
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @param count Expected number of nodes
+  - @param start Time the node count was changed
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func WaitUntilNodeCount(client *rancher.Client, cluster *management.Cluster, count int, start time.Time) {
+	metric := newOperationMetric(OperationNodesReady, cluster.Name)
+	metric.Start = start.UTC()
+	defer func() {
+		r := recover()
+		recordOperationMetric(metric, r == nil)
+		if r != nil {
+			panic(r)
+		}
+	}()
+
+	ginkgo.By(fmt.Sprintf("Waiting for cluster %s to have %d active nodes", cluster.Name, count), func() {
+		EventuallyWithBackoff(func() (string, error) {
+			total, active, err := activeNodeCount(client, cluster.ID)
+			return fmt.Sprintf("%d/%d", active, total), err
+		}, tools.SetTimeout(Timeout), 30*time.Second).Should(Equal(fmt.Sprintf("%d/%d", count, count)), "active/total nodes")
+	})
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Cluster %s has %d active nodes after %s", cluster.Name, count, time.Since(start).Round(time.Second)))
+}
+
+// clusterCondition returns the condition of the cluster with the given type, or nil if the cluster does not have it
+func clusterCondition(cluster *management.Cluster, conditionType string) *management.ClusterCondition {
+	for i := range cluster.Conditions {
+		if cluster.Conditions[i].Type == conditionType {
+			return &cluster.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// clusterAgentDisconnected returns true if Rancher reports that the agent of the cluster is not connected, for e.g. since it has no node to run on
+func clusterAgentDisconnected(cluster *management.Cluster) bool {
+	ready := clusterCondition(cluster, "Ready")
+	return ready != nil && ready.Status == "False" && ready.Reason == "Disconnected"
+}
+
+/*
+Check that Rancher gracefully handles a cluster left without node, for e.g. once all its nodegroups/nodepools are scaled to zero:
+the nodes must be removed from Rancher and the cluster agent, which can not run anymore, must be reported as disconnected,
+while the cluster is kept and remains provisioned so that it can be scaled up again.
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @param start Time the node count was changed
+  - @returns The up-to-date cluster; the function will fail through Ginkgo in case of issue
+*/
+func CheckZeroNodeCluster(client *rancher.Client, cluster *management.Cluster, start time.Time) *management.Cluster {
+	WaitUntilNodeCount(client, cluster, 0, start)
+	return CheckClusterHibernated(client, cluster)
+}
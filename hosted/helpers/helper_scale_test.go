@@ -0,0 +1,23 @@
+package helpers
+
+import (
+	"testing"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+func TestClusterAgentDisconnected(t *testing.T) {
+	for _, tc := range []struct {
+		conditions []management.ClusterCondition
+		want       bool
+	}{
+		{conditions: nil, want: false},
+		{conditions: []management.ClusterCondition{{Type: "Ready", Status: "True"}}, want: false},
+		{conditions: []management.ClusterCondition{{Type: "Ready", Status: "False", Reason: "Provisioning"}}, want: false},
+		{conditions: []management.ClusterCondition{{Type: "Provisioned", Status: "True"}, {Type: "Ready", Status: "False", Reason: "Disconnected"}}, want: true},
+	} {
+		if got := clusterAgentDisconnected(&management.Cluster{Conditions: tc.conditions}); got != tc.want {
+			t.Errorf("clusterAgentDisconnected(%v) = %t, want %t", tc.conditions, got, tc.want)
+		}
+	}
+}
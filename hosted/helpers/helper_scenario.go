@@ -0,0 +1,391 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"sigs.k8s.io/yaml"
+)
+
+// Actions of the steps of a scenario
+const (
+	// ScenarioCreate provisions the cluster with the hosted config of the cattle config file
+	ScenarioCreate = "create"
+	// ScenarioImport creates the cluster on the cloud provider and imports it
+	ScenarioImport = "import"
+	// ScenarioUpgradeControlPlane upgrades the kubernetes version of the control plane
+	ScenarioUpgradeControlPlane = "upgrade-control-plane"
+	// ScenarioAddNodePool adds a nodepool (nodegroup on EKS)
+	ScenarioAddNodePool = "add-node-pool"
+	// ScenarioDeleteNodePool deletes the last nodepool
+	ScenarioDeleteNodePool = "delete-node-pool"
+	// ScenarioScale scales all the nodepools to the nodes of the step
+	ScenarioScale = "scale"
+	// ScenarioOutOfBandTags updates the tags of the cluster directly on the cloud provider
+	ScenarioOutOfBandTags = "out-of-band-tags"
+	// ScenarioDelete deletes the cluster from Rancher, and from the cloud provider if it was imported
+	ScenarioDelete = "delete"
+)
+
+// scenarioOperations are the operations whose polling profile is used to check the expectation of the steps, see EventuallyForOperation;
+// the other steps use the default profile
+var scenarioOperations = map[string]string{
+	ScenarioCreate:              OperationProvision,
+	ScenarioImport:              OperationImport,
+	ScenarioUpgradeControlPlane: OperationUpgrade,
+	ScenarioAddNodePool:         OperationAddNodePool,
+	ScenarioDeleteNodePool:      OperationDeleteNodePool,
+	ScenarioScale:               OperationScale,
+}
+
+// scenarioUpgradableVersion is the kubernetes version of a create or import step using a version which can be upgraded
+const scenarioUpgradableVersion = "upgradable"
+
+// scenariosDir is the directory of the scenarios of the repository, relative to the suite directory
+const scenariosDir = "../helpers/assets/scenarios"
+
+/*
+Scenario is a lifecycle sequence of a cluster written in YAML, run against any registered provider by RunScenario; QA can write new regression
+scenarios without Go, for e.g.
+
+	name: upgrade-then-scale
+	description: the nodepools can be scaled once the control plane is upgraded
+	steps:
+	  - action: create
+	    kubernetesVersion: upgradable
+	    expect: {state: active, nodePools: 1}
+	  - action: upgrade-control-plane
+	  - action: add-node-pool
+	    expect: {nodePools: 2}
+	  - action: scale
+	    nodes: 2
+	    expect: {nodes: 4}
+	  - action: out-of-band-tags
+	    tags: {team: qa}
+	    expect: {tags: {team: qa}}
+	  - action: delete
+
+The scenarios of the repository are in hosted/helpers/assets/scenarios, one file per scenario.
+*/
+type Scenario struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Providers are the providers the scenario runs on; all of them if empty
+	Providers []string `json:"providers,omitempty"`
+	// Steps are run in order; the first one creates or imports the cluster
+	Steps []ScenarioStep `json:"steps"`
+}
+
+// ScenarioStep is an action of a scenario, along with the state of the cluster expected once it is done
+type ScenarioStep struct {
+	Action string `json:"action"`
+	// KubernetesVersion is the version of create, import and upgrade-control-plane: a version (for e.g. 1.30.4), upgradable for a version which can
+	// be upgraded (create and import only), or the default version of the provider if empty
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+	// Nodes is the number of nodes of every nodepool of scale
+	Nodes int64 `json:"nodes,omitempty"`
+	// Tags are the tags of out-of-band-tags
+	Tags map[string]string `json:"tags,omitempty"`
+	// Expect is the state of the cluster once the action is done
+	Expect *ScenarioExpectation `json:"expect,omitempty"`
+}
+
+// ScenarioExpectation is the state of the cluster expected after a step; the empty fields are not checked
+type ScenarioExpectation struct {
+	// State is the state of the cluster on Rancher, for e.g. active
+	State string `json:"state,omitempty"`
+	// NodePools is the number of nodepools of the cluster config
+	NodePools *int `json:"nodePools,omitempty"`
+	// Nodes is the number of ready nodes of the cluster
+	Nodes *int `json:"nodes,omitempty"`
+	// KubernetesVersion is the kubernetes version of the cluster config, or its prefix, for e.g. 1.30
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+	// Tags are tags (labels on GKE) of the upstream spec of the cluster, the other tags are ignored; they are waited for since the upstream spec is refreshed periodically
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// scenarioState is the state of the cluster the expectations of a step are checked against
+type scenarioState struct {
+	State             string
+	NodePools         int
+	KubernetesVersion string
+	Tags              map[string]string
+}
+
+// validate returns the problems of the scenario: unknown actions, the missing settings of the steps, and the steps which can not follow each other
+func (s *Scenario) validate() (problems []string) {
+	if s.Name == "" {
+		problems = append(problems, "the scenario has no name")
+	}
+	if len(s.Steps) == 0 || (s.Steps[0].Action != ScenarioCreate && s.Steps[0].Action != ScenarioImport) {
+		problems = append(problems, fmt.Sprintf("the first step must be %s or %s", ScenarioCreate, ScenarioImport))
+	}
+	for i, step := range s.Steps {
+		prefix := fmt.Sprintf("step %d (%s)", i+1, step.Action)
+		switch step.Action {
+		case ScenarioCreate, ScenarioImport:
+			if i > 0 && s.Steps[i-1].Action != ScenarioDelete {
+				problems = append(problems, prefix+": the cluster must be deleted first")
+			}
+		case ScenarioUpgradeControlPlane, ScenarioAddNodePool, ScenarioDeleteNodePool, ScenarioDelete:
+		case ScenarioScale:
+			if step.Nodes < 1 {
+				problems = append(problems, prefix+": nodes must be at least 1")
+			}
+		case ScenarioOutOfBandTags:
+			if len(step.Tags) == 0 {
+				problems = append(problems, prefix+": tags are required")
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("%s: unknown action; acceptable actions are %s", prefix, strings.Join([]string{ScenarioCreate, ScenarioImport,
+				ScenarioUpgradeControlPlane, ScenarioAddNodePool, ScenarioDeleteNodePool, ScenarioScale, ScenarioOutOfBandTags, ScenarioDelete}, ", ")))
+			continue
+		}
+		if i > 0 && step.Action != ScenarioCreate && step.Action != ScenarioImport && s.Steps[i-1].Action == ScenarioDelete {
+			problems = append(problems, prefix+": the cluster is deleted")
+		}
+		if step.KubernetesVersion == scenarioUpgradableVersion && step.Action != ScenarioCreate && step.Action != ScenarioImport {
+			problems = append(problems, fmt.Sprintf("%s: kubernetesVersion %s is only valid with %s and %s", prefix, scenarioUpgradableVersion, ScenarioCreate, ScenarioImport))
+		}
+		if step.Expect != nil && step.Action == ScenarioDelete {
+			problems = append(problems, prefix+": the cluster is removed, nothing can be expected from it")
+		}
+	}
+	return
+}
+
+// RunsOn returns true if the scenario runs on the provider
+func (s *Scenario) RunsOn(provider string) bool {
+	if len(s.Providers) == 0 {
+		return true
+	}
+	for _, p := range s.Providers {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadScenario reads and validates the scenario file
+func LoadScenario(path string) (*Scenario, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	scenario := &Scenario{}
+	if err = yaml.UnmarshalStrict(content, scenario); err != nil {
+		return nil, fmt.Errorf("scenario %s is not valid: %w", path, err)
+	}
+	if problems := scenario.validate(); len(problems) > 0 {
+		return nil, fmt.Errorf("scenario %s is not valid: %s", path, strings.Join(problems, "; "))
+	}
+	return scenario, nil
+}
+
+// LoadScenarios reads the scenarios of the *.yaml files of SCENARIOS_DIR, or of the scenarios of the repository if not set, sorted by name
+func LoadScenarios() ([]*Scenario, error) {
+	dir := runConfig.ScenariosDir
+	if dir == "" {
+		dir = scenariosDir
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no scenario found in %s", dir)
+	}
+	var scenarios []*Scenario
+	names := map[string]string{}
+	for _, path := range paths {
+		scenario, err := LoadScenario(path)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := names[scenario.Name]; ok {
+			return nil, fmt.Errorf("scenarios %s and %s have the same name %s", other, path, scenario.Name)
+		}
+		names[scenario.Name] = path
+		scenarios = append(scenarios, scenario)
+	}
+	sort.Slice(scenarios, func(i, j int) bool { return scenarios[i].Name < scenarios[j].Name })
+	return scenarios, nil
+}
+
+// expectationProblems returns the differences between the expected state of the cluster and its state; the nodes are checked apart
+func expectationProblems(expect *ScenarioExpectation, state scenarioState) (problems []string) {
+	if expect.State != "" && state.State != expect.State {
+		problems = append(problems, fmt.Sprintf("state is %s, %s is expected", state.State, expect.State))
+	}
+	if expect.NodePools != nil && state.NodePools != *expect.NodePools {
+		problems = append(problems, fmt.Sprintf("%d nodepools, %d are expected", state.NodePools, *expect.NodePools))
+	}
+	if expect.KubernetesVersion != "" && state.KubernetesVersion != expect.KubernetesVersion && !strings.HasPrefix(state.KubernetesVersion, expect.KubernetesVersion+".") {
+		problems = append(problems, fmt.Sprintf("kubernetes version is %s, %s is expected", state.KubernetesVersion, expect.KubernetesVersion))
+	}
+	keys := make([]string, 0, len(expect.Tags))
+	for key := range expect.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if value, ok := state.Tags[key]; !ok || value != expect.Tags[key] {
+			problems = append(problems, fmt.Sprintf("tag %s is %q, %q is expected", key, value, expect.Tags[key]))
+		}
+	}
+	return
+}
+
+// upstreamTags returns the tags (labels on GKE) of the upstream spec of the cluster
+func upstreamTags(cluster *management.Cluster) map[string]string {
+	_, upstreamSpec := providerSpecs(cluster)
+	switch spec := upstreamSpec.(type) {
+	case *management.EKSClusterConfigSpec:
+		if spec != nil && spec.Tags != nil {
+			return *spec.Tags
+		}
+	case *management.GKEClusterConfigSpec:
+		if spec != nil && spec.Labels != nil {
+			return *spec.Labels
+		}
+	case *management.AKSClusterConfigSpec:
+		if spec != nil {
+			return spec.Tags
+		}
+	}
+	return nil
+}
+
+// clusterScenarioState returns the state of the cluster the expectations are checked against
+func clusterScenarioState(cluster *management.Cluster) scenarioState {
+	return scenarioState{
+		State:             cluster.State,
+		NodePools:         len(clusterNodePools(cluster)),
+		KubernetesVersion: clusterKubernetesVersion(cluster),
+		Tags:              upstreamTags(cluster),
+	}
+}
+
+// ScenarioRun is the run of a scenario on a provider; Cluster is the cluster of the run, nil once deleted, to be deleted by the suite if the run fails
+type ScenarioRun struct {
+	Scenario    *Scenario
+	Provider    HostedProvider
+	ClusterName string
+	CloudCredID string
+	Cluster     *management.Cluster
+	// imported is true if the cluster was created on the cloud provider by the run
+	imported bool
+}
+
+/*
+RunScenario runs the steps of the scenario on the provider and checks the expectations of every step; every step is a ginkgo.By of the spec.
+  - @param client Rancher client
+  - @param run The run of the scenario; its Cluster is set as soon as the cluster is created so that the suite can delete it if the run fails
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func RunScenario(client *rancher.Client, run *ScenarioRun) {
+	for i, step := range run.Scenario.Steps {
+		ginkgo.By(fmt.Sprintf("Scenario %s, step %d: %s", run.Scenario.Name, i+1, step.Action), func() {
+			runScenarioStep(client, run, step)
+			if step.Expect != nil {
+				checkScenarioExpectation(client, run, step)
+			}
+		})
+	}
+}
+
+// scenarioK8sVersion returns the kubernetes version of the step, see ScenarioStep.KubernetesVersion
+func scenarioK8sVersion(client *rancher.Client, run *ScenarioRun, step ScenarioStep) string {
+	if step.KubernetesVersion != "" && step.KubernetesVersion != scenarioUpgradableVersion {
+		return step.KubernetesVersion
+	}
+	k8sVersion, err := run.Provider.K8sVersion(client, run.CloudCredID, step.KubernetesVersion == scenarioUpgradableVersion)
+	Expect(err).To(BeNil())
+	return k8sVersion
+}
+
+// runScenarioStep runs the action of the step
+func runScenarioStep(client *rancher.Client, run *ScenarioRun, step ScenarioStep) {
+	var err error
+	switch step.Action {
+	case ScenarioCreate:
+		k8sVersion := scenarioK8sVersion(client, run, step)
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Creating cluster %s with kubernetes version %s", run.ClusterName, k8sVersion))
+		run.Cluster, err = run.Provider.CreateCluster(client, run.ClusterName, run.CloudCredID, k8sVersion)
+		Expect(err).To(BeNil())
+		run.Cluster, err = WaitUntilClusterIsReady(run.Cluster, client)
+		Expect(err).To(BeNil())
+	case ScenarioImport:
+		k8sVersion := scenarioK8sVersion(client, run, step)
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Creating cluster %s to import with kubernetes version %s", run.ClusterName, k8sVersion))
+		Expect(run.Provider.OutOfBand().CreateCluster(run.ClusterName, k8sVersion)).To(Succeed())
+		run.imported = true
+		run.Cluster, err = run.Provider.ImportCluster(client, run.ClusterName, run.CloudCredID)
+		Expect(err).To(BeNil())
+		run.Cluster, err = WaitUntilClusterIsReady(run.Cluster, client)
+		Expect(err).To(BeNil())
+	case ScenarioUpgradeControlPlane:
+		k8sVersion := step.KubernetesVersion
+		if k8sVersion == "" {
+			availableVersions, err := run.Provider.AvailableVersions(client, run.Cluster)
+			Expect(err).To(BeNil())
+			Expect(availableVersions).NotTo(BeEmpty(), fmt.Sprintf("No version to upgrade cluster %s to", run.ClusterName))
+			k8sVersion = availableVersions[0]
+		}
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Upgrading the control plane of cluster %s to %s", run.ClusterName, k8sVersion))
+		run.Cluster, err = run.Provider.UpgradeCluster(run.Cluster, client, k8sVersion)
+		Expect(err).To(BeNil())
+	case ScenarioAddNodePool:
+		run.Cluster, err = run.Provider.AddNodePool(run.Cluster, client)
+		Expect(err).To(BeNil())
+	case ScenarioDeleteNodePool:
+		run.Cluster, err = run.Provider.DeleteNodePool(run.Cluster, client)
+		Expect(err).To(BeNil())
+	case ScenarioScale:
+		run.Cluster, err = run.Provider.ScaleNodePools(run.Cluster, client, step.Nodes)
+		Expect(err).To(BeNil())
+	case ScenarioOutOfBandTags:
+		Expect(run.Provider.OutOfBand().UpdateTags(run.ClusterName, run.Provider.Location(), step.Tags)).To(Succeed())
+	case ScenarioDelete:
+		DeleteScenarioCluster(client, run)
+	}
+}
+
+// checkScenarioExpectation waits for the cluster to reach the expected state; the upstream spec and the nodes can take a few minutes to be updated
+func checkScenarioExpectation(client *rancher.Client, run *ScenarioRun, step ScenarioStep) {
+	expect := step.Expect
+	start := time.Now()
+	EventuallyForOperation(scenarioOperations[step.Action], func() (problems []string, err error) {
+		run.Cluster, err = client.Management.Cluster.ByID(run.Cluster.ID)
+		if err != nil {
+			return nil, err
+		}
+		return expectationProblems(expect, clusterScenarioState(run.Cluster)), nil
+	}, tools.SetTimeout(15*time.Minute)).Should(BeEmpty(), fmt.Sprintf("Cluster %s is not in the expected state", run.ClusterName))
+	if expect.Nodes != nil {
+		WaitUntilNodeCount(client, run.Cluster, *expect.Nodes, start)
+	}
+}
+
+// DeleteScenarioCluster deletes the cluster of the run from Rancher and waits until it is removed, along with the cloud cluster if it was imported by the run
+func DeleteScenarioCluster(client *rancher.Client, run *ScenarioRun) {
+	if run.Cluster != nil {
+		Expect(run.Provider.DeleteCluster(run.Cluster, client)).To(Succeed())
+		WaitUntilClusterIsRemoved(client, run.Cluster.ID)
+		run.Cluster = nil
+	}
+	if run.imported {
+		Expect(run.Provider.OutOfBand().DeleteCluster(run.ClusterName, run.Provider.Location())).To(Succeed())
+		run.imported = false
+	}
+}
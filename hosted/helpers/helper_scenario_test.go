@@ -0,0 +1,97 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadScenario(t *testing.T) {
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		name    string
+		content string
+		problem string
+	}{
+		{name: "valid", content: "name: valid\nsteps:\n- action: import\n- action: scale\n  nodes: 2\n  expect: {nodes: 2}\n- action: delete\n- action: create\n"},
+		{name: "unknown-field", content: "name: unknown-field\nsteps:\n- action: create\n  replicas: 2\n", problem: "unknown field"},
+		{name: "first-step", content: "name: first-step\nsteps:\n- action: scale\n  nodes: 2\n", problem: "the first step must be"},
+		{name: "unknown-action", content: "name: unknown-action\nsteps:\n- action: create\n- action: reboot\n", problem: "step 2 (reboot): unknown action"},
+		{name: "after-delete", content: "name: after-delete\nsteps:\n- action: create\n- action: delete\n- action: add-node-pool\n", problem: "step 3 (add-node-pool): the cluster is deleted"},
+		{name: "create-twice", content: "name: create-twice\nsteps:\n- action: create\n- action: import\n", problem: "step 2 (import): the cluster must be deleted first"},
+		{name: "settings", content: "name: settings\nsteps:\n- action: create\n- action: scale\n- action: out-of-band-tags\n", problem: "step 2 (scale): nodes must be at least 1; step 3 (out-of-band-tags): tags are required"},
+		{name: "upgradable", content: "name: upgradable\nsteps:\n- action: create\n- action: upgrade-control-plane\n  kubernetesVersion: upgradable\n", problem: "only valid with create and import"},
+	} {
+		path := filepath.Join(dir, tc.name+".yaml")
+		if err := os.WriteFile(path, []byte(tc.content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		scenario, err := LoadScenario(path)
+		if tc.problem == "" {
+			if err != nil || scenario.Name != tc.name || len(scenario.Steps) != 4 || *scenario.Steps[1].Expect.Nodes != 2 {
+				t.Errorf("%s: got %+v, %v", tc.name, scenario, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), tc.problem) {
+			t.Errorf("%s: got %v, want an error containing %q", tc.name, err, tc.problem)
+		}
+	}
+}
+
+func TestLoadScenarios(t *testing.T) {
+	// The scenarios of the repository must be valid
+	defer func(dir string) { runConfig.ScenariosDir = dir }(runConfig.ScenariosDir)
+	runConfig.ScenariosDir = "assets/scenarios"
+	scenarios, err := LoadScenarios()
+	if err != nil || len(scenarios) == 0 {
+		t.Fatalf("got %d scenarios, %v", len(scenarios), err)
+	}
+	for i := 1; i < len(scenarios); i++ {
+		if scenarios[i-1].Name >= scenarios[i].Name {
+			t.Errorf("scenarios are not sorted: %s, %s", scenarios[i-1].Name, scenarios[i].Name)
+		}
+	}
+
+	runConfig.ScenariosDir = t.TempDir()
+	if _, err = LoadScenarios(); err == nil {
+		t.Error("expected an error without scenario")
+	}
+	for _, file := range []string{"a.yaml", "b.yaml"} {
+		if err = os.WriteFile(filepath.Join(runConfig.ScenariosDir, file), []byte("name: same\nsteps:\n- action: create\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = LoadScenarios(); err == nil || !strings.Contains(err.Error(), "the same name") {
+		t.Errorf("got %v, want an error with the duplicated name", err)
+	}
+}
+
+func TestScenarioRunsOn(t *testing.T) {
+	if !(&Scenario{}).RunsOn("gke") {
+		t.Error("a scenario without providers must run on all of them")
+	}
+	scenario := &Scenario{Providers: []string{"eks", "aks"}}
+	if !scenario.RunsOn("aks") || scenario.RunsOn("gke") {
+		t.Errorf("got the wrong providers for %v", scenario.Providers)
+	}
+}
+
+func TestExpectationProblems(t *testing.T) {
+	nodePools := 2
+	state := scenarioState{State: "active", NodePools: 2, KubernetesVersion: "1.30.4", Tags: map[string]string{"team": "qa", "owner": "ci"}}
+	for _, tc := range []struct {
+		expect   ScenarioExpectation
+		problems int
+	}{
+		{expect: ScenarioExpectation{}},
+		{expect: ScenarioExpectation{State: "active", NodePools: &nodePools, KubernetesVersion: "1.30", Tags: map[string]string{"team": "qa"}}},
+		{expect: ScenarioExpectation{KubernetesVersion: "1.30.4"}},
+		{expect: ScenarioExpectation{KubernetesVersion: "1.3"}, problems: 1},
+		{expect: ScenarioExpectation{State: "updating", KubernetesVersion: "1.31"}, problems: 2},
+		{expect: ScenarioExpectation{Tags: map[string]string{"team": "dev", "env": "ci"}}, problems: 2},
+	} {
+		if problems := expectationProblems(&tc.expect, state); len(problems) != tc.problems {
+			t.Errorf("%+v: got %q, want %d problems", tc.expect, problems, tc.problems)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	steveV1 "github.com/rancher/shepherd/clients/rancher/v1"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+)
+
+// Label and taint set through Rancher on the nodepool dedicated to the scheduling specs
+const (
+	SchedulingLabelKey   = "hosted-providers-e2e/pool"
+	SchedulingLabelValue = "scheduling"
+	SchedulingTaintKey   = "hosted-providers-e2e/dedicated"
+	SchedulingTaintValue = "scheduling"
+)
+
+// SchedulingLabels returns the node labels of the nodepool dedicated to the scheduling specs
+func SchedulingLabels() map[string]string {
+	return map[string]string{SchedulingLabelKey: SchedulingLabelValue}
+}
+
+// SchedulingTaint returns the node taint of the nodepool dedicated to the scheduling specs
+func SchedulingTaint() corev1.Taint {
+	return corev1.Taint{Key: SchedulingTaintKey, Value: SchedulingTaintValue, Effect: corev1.TaintEffectNoSchedule}
+}
+
+// newSchedulingPod returns a pause pod selecting the nodes with the given labels, if any, and tolerating the given taint, if any
+func newSchedulingPod(namespace, name string, nodeSelector map[string]string, taint *corev1.Taint) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: corev1.PodSpec{
+			NodeSelector: nodeSelector,
+			Containers:   []corev1.Container{{Name: "pause", Image: "registry.k8s.io/pause:3.9"}},
+		},
+	}
+	if taint != nil {
+		pod.Spec.Tolerations = []corev1.Toleration{{Key: taint.Key, Operator: corev1.TolerationOpEqual, Value: taint.Value, Effect: taint.Effect}}
+	}
+	return pod
+}
+
+// schedulingNodeProblems returns the nodes which do not carry the given taint, if any; an empty list of nodes is a problem
+// since the labelled nodes may not have joined the cluster yet.
+func schedulingNodeProblems(nodes []corev1.Node, taint *corev1.Taint) []string {
+	if len(nodes) == 0 {
+		return []string{"no labelled node"}
+	}
+	var problems []string
+	for _, node := range nodes {
+		if taint == nil {
+			continue
+		}
+		found := false
+		for _, nodeTaint := range node.Spec.Taints {
+			found = found || nodeTaint.MatchTaint(taint) && nodeTaint.Value == taint.Value
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("node %s is not tainted with %s", node.Name, taint.ToString()))
+		}
+	}
+	return problems
+}
+
+// podPlacementProblem returns why a pod is not placed as expected: if schedulable, it must be running, on a labelled node if onLabelledNode
+// and on another node otherwise; if not, it must be reported unschedulable by the scheduler. An empty string means the pod is placed as expected.
+func podPlacementProblem(pod *corev1.Pod, schedulable, onLabelledNode bool, labelledNodes map[string]bool) string {
+	if !schedulable {
+		if pod.Spec.NodeName != "" {
+			return fmt.Sprintf("pod %s is scheduled on node %s", pod.Name, pod.Spec.NodeName)
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse && condition.Reason == corev1.PodReasonUnschedulable {
+				return ""
+			}
+		}
+		return fmt.Sprintf("pod %s is not reported unschedulable yet", pod.Name)
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return fmt.Sprintf("pod %s is %s", pod.Name, pod.Status.Phase)
+	}
+	if labelledNodes[pod.Spec.NodeName] != onLabelledNode {
+		return fmt.Sprintf("pod %s runs on node %s, labelled=%t", pod.Name, pod.Spec.NodeName, labelledNodes[pod.Spec.NodeName])
+	}
+	return ""
+}
+
+/*
+Check the scheduling of pods on a downstream cluster whose nodepool has been given labels, and optionally a taint, through Rancher:
+the nodes of the nodepool must carry them, then pods are deployed and their placement is checked by the scheduler itself.
+  - A pod selecting the labels, and tolerating the taint, must run on a labelled node.
+  - With a taint, a pod selecting the labels without tolerating the taint must stay unschedulable, and a pod without any constraint must run on another node.
+  - Without a taint, a pod selecting another value of the labels must stay unschedulable.
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @param labels Node labels set on the nodepool, for e.g. SchedulingLabels()
+  - @param taint Node taint set on the nodepool, for e.g. SchedulingTaint(); nil if the provider does not support taints
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckNodeScheduling(client *rancher.Client, cluster *management.Cluster, labels map[string]string, taint *corev1.Taint) {
+	steveClient, err := client.Steve.ProxyDownstream(cluster.ID)
+	Expect(err).To(BeNil())
+
+	labelledNodes := map[string]bool{}
+	ginkgo.By(fmt.Sprintf("Checking the labels and taints of the nodes of cluster %s", cluster.Name), func() {
+		EventuallyWithBackoff(func() ([]string, error) {
+			collection, err := steveClient.SteveType("node").List(map[string][]string{"labelSelector": {k8slabels.FormatLabels(labels)}})
+			if err != nil {
+				return nil, err
+			}
+			var nodes []corev1.Node
+			for _, object := range collection.Data {
+				node := corev1.Node{}
+				if err = steveV1.ConvertToK8sType(object.JSONResp, &node); err != nil {
+					return nil, err
+				}
+				nodes = append(nodes, node)
+			}
+			problems := schedulingNodeProblems(nodes, taint)
+			if len(problems) == 0 {
+				for _, node := range nodes {
+					labelledNodes[node.Name] = true
+				}
+			}
+			return problems, nil
+		}, tools.SetTimeout(15*time.Minute), 15*time.Second).Should(BeEmpty(), fmt.Sprintf("The nodes of cluster %s do not carry the nodepool labels and taints", cluster.Name))
+	})
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namegen.AppendRandomString("hp-scheduling")}}
+	_, err = steveClient.SteveType("namespace").Create(namespace)
+	Expect(err).To(BeNil())
+	ginkgo.DeferCleanup(deletePSANamespace, steveClient, namespace.Name)
+
+	type placement struct {
+		pod                         *corev1.Pod
+		schedulable, onLabelledNode bool
+	}
+	placements := []placement{{pod: newSchedulingPod(namespace.Name, "selecting", labels, taint), schedulable: true, onLabelledNode: true}}
+	if taint != nil {
+		placements = append(placements,
+			placement{pod: newSchedulingPod(namespace.Name, "not-tolerating", labels, nil)},
+			placement{pod: newSchedulingPod(namespace.Name, "unconstrained", nil, nil), schedulable: true})
+	} else {
+		otherLabels := map[string]string{}
+		for key, value := range labels {
+			otherLabels[key] = value + "-other"
+		}
+		placements = append(placements, placement{pod: newSchedulingPod(namespace.Name, "mismatching", otherLabels, nil)})
+	}
+
+	ginkgo.By(fmt.Sprintf("Checking the scheduling of pods in namespace %s of cluster %s", namespace.Name, cluster.Name), func() {
+		// the service account of a new namespace must exist for a pod to be admitted
+		for _, p := range placements {
+			EventuallyWithBackoff(func() error {
+				_, err := steveClient.SteveType(podSteveType).Create(p.pod)
+				return err
+			}, tools.SetTimeout(2*time.Minute), 5*time.Second).Should(Succeed())
+		}
+
+		EventuallyWithBackoff(func() (string, error) {
+			var problems []string
+			for _, p := range placements {
+				object, err := steveClient.SteveType(podSteveType).ByID(namespace.Name + "/" + p.pod.Name)
+				if err != nil {
+					return "", err
+				}
+				pod := &corev1.Pod{}
+				if err = steveV1.ConvertToK8sType(object.JSONResp, pod); err != nil {
+					return "", err
+				}
+				if problem := podPlacementProblem(pod, p.schedulable, p.onLabelledNode, labelledNodes); problem != "" {
+					problems = append(problems, problem)
+				}
+			}
+			return strings.Join(problems, "\n"), nil
+		}, tools.SetTimeout(5*time.Minute), 10*time.Second).Should(BeEmpty(), fmt.Sprintf("The pods of namespace %s are not placed as expected", namespace.Name))
+	})
+}
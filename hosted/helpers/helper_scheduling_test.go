@@ -0,0 +1,60 @@
+package helpers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSchedulingNodeProblems(t *testing.T) {
+	taint := SchedulingTaint()
+	tainted := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "tainted"}, Spec: corev1.NodeSpec{Taints: []corev1.Taint{taint}}}
+	otherValue := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "other"}, Spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: taint.Key, Value: "other", Effect: taint.Effect}}}}
+	untainted := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "untainted"}}
+
+	for _, tc := range []struct {
+		nodes    []corev1.Node
+		taint    *corev1.Taint
+		problems int
+	}{
+		{nodes: nil, taint: nil, problems: 1},
+		{nodes: []corev1.Node{untainted}, taint: nil, problems: 0},
+		{nodes: []corev1.Node{tainted}, taint: &taint, problems: 0},
+		{nodes: []corev1.Node{tainted, otherValue, untainted}, taint: &taint, problems: 2},
+	} {
+		if problems := schedulingNodeProblems(tc.nodes, tc.taint); len(problems) != tc.problems {
+			t.Errorf("schedulingNodeProblems(%d nodes, %v) = %v, want %d problems", len(tc.nodes), tc.taint, problems, tc.problems)
+		}
+	}
+}
+
+func TestPodPlacementProblem(t *testing.T) {
+	labelledNodes := map[string]bool{"labelled": true}
+	running := func(node string) *corev1.Pod {
+		return &corev1.Pod{Spec: corev1.PodSpec{NodeName: node}, Status: corev1.PodStatus{Phase: corev1.PodRunning}}
+	}
+	unschedulable := &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodPending, Conditions: []corev1.PodCondition{
+		{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable},
+	}}}
+
+	for _, tc := range []struct {
+		description                 string
+		pod                         *corev1.Pod
+		schedulable, onLabelledNode bool
+		wantProblem                 bool
+	}{
+		{description: "running on a labelled node", pod: running("labelled"), schedulable: true, onLabelledNode: true},
+		{description: "running on another node", pod: running("other"), schedulable: true, onLabelledNode: true, wantProblem: true},
+		{description: "unconstrained on another node", pod: running("other"), schedulable: true},
+		{description: "unconstrained on a labelled node", pod: running("labelled"), schedulable: true, wantProblem: true},
+		{description: "pending", pod: &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodPending}}, schedulable: true, wantProblem: true},
+		{description: "unschedulable", pod: unschedulable},
+		{description: "not evaluated by the scheduler", pod: &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodPending}}, wantProblem: true},
+		{description: "scheduled while it should not", pod: running("labelled"), wantProblem: true},
+	} {
+		if problem := podPlacementProblem(tc.pod, tc.schedulable, tc.onLabelledNode, labelledNodes); (problem != "") != tc.wantProblem {
+			t.Errorf("podPlacementProblem() for a pod %s = %q, want a problem: %t", tc.description, problem, tc.wantProblem)
+		}
+	}
+}
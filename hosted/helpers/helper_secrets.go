@@ -0,0 +1,202 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+)
+
+// SECRETS_PROVIDER values: the secrets are read from the env only (default), or fetched from Vault or AWS Secrets Manager
+const (
+	secretsProviderEnv   = "env"
+	secretsProviderVault = "vault"
+	secretsProviderAWS   = "aws-secrets-manager"
+)
+
+// envNameRegex matches the names of the secrets exported to the env
+var envNameRegex = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+
+// SecretsProvider fetches the secrets of the run from a secret manager, for e.g. the cloud credentials, RANCHER_PASSWORD or the API tokens
+type SecretsProvider interface {
+	// Secrets returns the secrets stored at the path, by env var name
+	Secrets(path string) (map[string]string, error)
+}
+
+// newSecretsProvider returns the SecretsProvider of the SECRETS_PROVIDER
+func newSecretsProvider(name string) (SecretsProvider, error) {
+	switch name {
+	case secretsProviderVault:
+		if os.Getenv("VAULT_ADDR") == "" || os.Getenv("VAULT_TOKEN") == "" {
+			return nil, errors.New("VAULT_ADDR and VAULT_TOKEN are required with SECRETS_PROVIDER=vault")
+		}
+		return &vaultSecrets{addr: os.Getenv("VAULT_ADDR"), token: os.Getenv("VAULT_TOKEN"), client: &http.Client{Timeout: 30 * time.Second}}, nil
+	case secretsProviderAWS:
+		return awsSecretsManager{}, nil
+	}
+	return nil, fmt.Errorf("unsupported secrets provider %q", name)
+}
+
+// secretValues returns the values of a secret as strings; the values which are not strings, for e.g. the GCP_CREDENTIALS JSON key
+// stored as an object, are encoded to JSON
+func secretValues(data map[string]any) (map[string]string, error) {
+	secrets := map[string]string{}
+	for name, value := range data {
+		if text, ok := value.(string); ok {
+			secrets[name] = text
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("secret %s can not be encoded: %w", name, err)
+		}
+		secrets[name] = string(encoded)
+	}
+	return secrets, nil
+}
+
+// vaultSecrets reads the secrets from a KV secrets engine of Vault (version 1 or 2) with the HTTP API, authenticated with VAULT_TOKEN
+type vaultSecrets struct {
+	addr   string
+	token  string
+	client *http.Client
+}
+
+// parseVaultSecret returns the secrets of the response of Vault; the data of a KV version 2 secret is nested along with its metadata
+func parseVaultSecret(body []byte) (map[string]string, error) {
+	var response struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode the secret: %w", err)
+	}
+	data := response.Data
+	if nested, ok := data["data"].(map[string]any); ok {
+		if _, ok = data["metadata"]; ok {
+			data = nested
+		}
+	}
+	return secretValues(data)
+}
+
+func (v *vaultSecrets) Secrets(path string) (map[string]string, error) {
+	request, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(v.addr, "/")+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("X-Vault-Token", v.token)
+	response, err := v.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the secret %s from Vault: %w", path, err)
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to read the secret %s from Vault: %s %s", path, response.Status, strings.TrimSpace(string(body)))
+	}
+	return parseVaultSecret(body)
+}
+
+// awsSecretsManager reads the secrets from a secret of AWS Secrets Manager with the aws CLI; the secret string is a JSON object of the secrets
+type awsSecretsManager struct{}
+
+func (awsSecretsManager) Secrets(secretID string) (map[string]string, error) {
+	out, err := extcli.AWS.Sensitive().Output("secretsmanager", "get-secret-value", "--secret-id", secretID, "--query", "SecretString", "--output", "text")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the secret %s from AWS Secrets Manager: %w", secretID, err)
+	}
+	var data map[string]any
+	if err = json.Unmarshal([]byte(out), &data); err != nil {
+		return nil, fmt.Errorf("the secret %s of AWS Secrets Manager is not a JSON object: %w", secretID, err)
+	}
+	return secretValues(data)
+}
+
+// exportSecrets sets the env vars of the secrets which are not set already, so that the env of the runner overrides the secret manager;
+// it returns the names of the exported secrets
+func exportSecrets(secrets map[string]string) (exported []string, err error) {
+	for name, value := range secrets {
+		if !envNameRegex.MatchString(name) {
+			return nil, fmt.Errorf("secret %q is not a valid env var name", name)
+		}
+		if _, ok := os.LookupEnv(name); ok {
+			continue
+		}
+		if err = os.Setenv(name, value); err != nil {
+			return nil, err
+		}
+		exported = append(exported, name)
+	}
+	sort.Strings(exported)
+	return exported, nil
+}
+
+// loadedSecrets are the secrets exported to the env of the process, once secretsLoaded; see LoadSecrets
+var (
+	loadedSecrets map[string]string
+	secretsLoaded bool
+)
+
+// fetchSecrets fetches the secrets of SECRETS_PATH from the SECRETS_PROVIDER; nothing is fetched with SECRETS_PROVIDER=env
+func fetchSecrets() (map[string]string, error) {
+	name := os.Getenv("SECRETS_PROVIDER")
+	if name == "" || name == secretsProviderEnv {
+		return nil, nil
+	}
+	path := os.Getenv("SECRETS_PATH")
+	if path == "" {
+		return nil, fmt.Errorf("SECRETS_PATH is required with SECRETS_PROVIDER=%s", name)
+	}
+	provider, err := newSecretsProvider(name)
+	if err != nil {
+		return nil, err
+	}
+	return provider.Secrets(path)
+}
+
+// applySecrets exports the secrets to the env, and reloads the run config from it since the secrets are read from the env,
+// for e.g. RANCHER_PASSWORD or QASE_API_TOKEN
+func applySecrets(secrets map[string]string) error {
+	if len(secrets) > 0 {
+		if _, err := exportSecrets(secrets); err != nil {
+			return err
+		}
+		*runConfig = *LoadRunConfig()
+		RancherPassword = runConfig.RancherPassword
+	}
+	loadedSecrets, secretsLoaded = secrets, true
+	return nil
+}
+
+/*
+LoadSecrets fetches the secrets of SECRETS_PATH from the SECRETS_PROVIDER and exports them to the env, so that they do not have to be set
+in plaintext on the runners: the secret is a map of env var names to values, for e.g. AWS_SECRET_ACCESS_KEY, GCP_CREDENTIALS, RANCHER_PASSWORD
+or QASE_API_TOKEN; the env vars already set are kept. It is called by the suite setup on the first parallel process, which passes the secrets
+to the other processes (see ParallelSynchronizedBeforeSuite), instead of every importer of the helpers fetching them when the package is loaded;
+the secrets are fetched once per process, and nothing is done with SECRETS_PROVIDER=env.
+  - @returns The secrets exported to the env, and the error of the secrets provider
+*/
+func LoadSecrets() (map[string]string, error) {
+	if secretsLoaded {
+		return loadedSecrets, nil
+	}
+	secrets, err := fetchSecrets()
+	if err != nil {
+		return nil, err
+	}
+	if err = applySecrets(secrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
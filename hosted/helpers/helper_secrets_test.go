@@ -0,0 +1,102 @@
+package helpers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseVaultSecret(t *testing.T) {
+	for _, body := range []string{
+		// KV version 1
+		`{"data": {"RANCHER_PASSWORD": "admin123", "GCP_CREDENTIALS": {"type": "service_account"}}}`,
+		// KV version 2
+		`{"data": {"data": {"RANCHER_PASSWORD": "admin123", "GCP_CREDENTIALS": {"type": "service_account"}}, "metadata": {"version": 3}}}`,
+	} {
+		secrets, err := parseVaultSecret([]byte(body))
+		if want := map[string]string{"RANCHER_PASSWORD": "admin123", "GCP_CREDENTIALS": `{"type":"service_account"}`}; err != nil || !reflect.DeepEqual(secrets, want) {
+			t.Errorf("got %v, %v, want %v", secrets, err, want)
+		}
+	}
+}
+
+func TestLoadSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" || r.URL.Path != "/v1/secret/data/hosted-providers-e2e" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"data": {"TEST_SECRET_PASSWORD": "from-vault", "TEST_SECRET_TOKEN": "from-vault"}, "metadata": {}}}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("SECRETS_PROVIDER", secretsProviderVault)
+	t.Setenv("SECRETS_PATH", "secret/data/hosted-providers-e2e")
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "vault-token")
+	// the env of the runner overrides the secret manager
+	t.Setenv("TEST_SECRET_TOKEN", "from-env")
+	t.Setenv("TEST_SECRET_PASSWORD", "")
+	os.Unsetenv("TEST_SECRET_PASSWORD")
+
+	// restore the run config reloaded with the secrets
+	savedConfig, savedPassword := *runConfig, RancherPassword
+	t.Cleanup(func() {
+		*runConfig, RancherPassword = savedConfig, savedPassword
+		loadedSecrets, secretsLoaded = nil, false
+	})
+
+	secretsLoaded = false
+	secrets, err := LoadSecrets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if password, token := os.Getenv("TEST_SECRET_PASSWORD"), os.Getenv("TEST_SECRET_TOKEN"); password != "from-vault" || token != "from-env" {
+		t.Errorf("got %q and %q", password, token)
+	}
+	if len(secrets) != 2 {
+		t.Errorf("got secrets %v", secrets)
+	}
+
+	// the secrets are fetched once per process
+	t.Setenv("VAULT_TOKEN", "expired")
+	if _, err = LoadSecrets(); err != nil {
+		t.Errorf("expected the loaded secrets, got %v", err)
+	}
+	secretsLoaded = false
+	if _, err = LoadSecrets(); err == nil {
+		t.Error("expected an error with a forbidden secret")
+	}
+	t.Setenv("SECRETS_PROVIDER", "keychain")
+	if _, err = LoadSecrets(); err == nil || !strings.Contains(err.Error(), `unsupported secrets provider "keychain"`) {
+		t.Errorf("expected an error with an unsupported secrets provider, got %v", err)
+	}
+}
+
+func TestApplySecrets(t *testing.T) {
+	savedConfig, savedPassword := *runConfig, RancherPassword
+	t.Cleanup(func() {
+		*runConfig, RancherPassword = savedConfig, savedPassword
+		loadedSecrets, secretsLoaded = nil, false
+	})
+	t.Setenv("RANCHER_PASSWORD", "")
+	os.Unsetenv("RANCHER_PASSWORD")
+
+	// the secrets passed by the first process are exported and read by the run config
+	if err := applySecrets(map[string]string{"RANCHER_PASSWORD": "from-setup"}); err != nil {
+		t.Fatal(err)
+	}
+	if runConfig.RancherPassword != "from-setup" || RancherPassword != "from-setup" {
+		t.Errorf("got password %q and %q", runConfig.RancherPassword, RancherPassword)
+	}
+}
+
+func TestExportSecrets(t *testing.T) {
+	if _, err := exportSecrets(map[string]string{"rancher-password": "admin123"}); err == nil {
+		t.Error("expected an error with a secret name which is not an env var")
+	}
+}
@@ -0,0 +1,189 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+)
+
+// snapshotSettings are the Rancher settings recorded by SnapshotRancherSettings; they are the ones the chart support tests may change,
+// directly or through a Rancher upgrade
+var snapshotSettings = []string{
+	"agent-image",
+	"system-default-registry",
+	"system-catalog",
+	"chart-default-branch",
+	"rke-metadata-config",
+}
+
+// RancherSettingsSnapshot holds the Rancher settings that the destructive chart support tests may change
+type RancherSettingsSnapshot struct {
+	// Settings holds the values of the snapshotSettings; an empty value means the default value is used
+	Settings map[string]string
+	// KontainerDrivers holds whether the kontainer drivers are active, by name
+	KontainerDrivers map[string]bool
+	// ClusterRepos holds the spec of the chart repositories, by name
+	ClusterRepos map[string]json.RawMessage
+	// OperatorCharts holds the versions of the installed operator charts of the Provider, by name
+	OperatorCharts map[string]string
+}
+
+// k8sList is the part of a kubectl list output needed by the snapshot
+type k8sList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Value string          `json:"value"`
+		Spec  json.RawMessage `json:"spec"`
+	} `json:"items"`
+}
+
+// getUpstreamList returns the resources of the given type on the upstream cluster
+func getUpstreamList(resource string) k8sList {
+	out, err := extcli.Kubectl.Output(upstreamKubectlArgs("get", resource, "-o", "json")...)
+	Expect(err).To(BeNil(), "Failed to list %s", resource)
+	var list k8sList
+	Expect(json.Unmarshal([]byte(out), &list)).To(Succeed(), "Failed to decode %s", resource)
+	return list
+}
+
+// operatorChartVersions returns the versions of the installed operator charts of the Provider, by name
+func operatorChartVersions() map[string]string {
+	versions := map[string]string{}
+	for _, chart := range ListOperatorChart() {
+		versions[chart.Name] = chart.DerivedVersion
+	}
+	return versions
+}
+
+/*
+Snapshot the Rancher settings that the destructive chart support tests may change (agent image and registry settings, kontainer drivers state,
+chart repositories config and operator charts); it requires the upstream cluster to be reachable via kubectl
+  - @returns The snapshot, to be restored once the test is done, for e.g. DeferCleanup(helpers.SnapshotRancherSettings().Restore)
+*/
+func SnapshotRancherSettings() *RancherSettingsSnapshot {
+	snapshot := &RancherSettingsSnapshot{
+		Settings:         map[string]string{},
+		KontainerDrivers: map[string]bool{},
+		ClusterRepos:     map[string]json.RawMessage{},
+		OperatorCharts:   operatorChartVersions(),
+	}
+
+	wanted := map[string]bool{}
+	for _, name := range snapshotSettings {
+		wanted[name] = true
+	}
+	for _, setting := range getUpstreamList("settings.management.cattle.io").Items {
+		if wanted[setting.Metadata.Name] {
+			snapshot.Settings[setting.Metadata.Name] = setting.Value
+		}
+	}
+
+	for _, driver := range getUpstreamList("kontainerdrivers.management.cattle.io").Items {
+		var spec struct {
+			Active bool `json:"active"`
+		}
+		Expect(json.Unmarshal(driver.Spec, &spec)).To(Succeed())
+		snapshot.KontainerDrivers[driver.Metadata.Name] = spec.Active
+	}
+
+	for _, repo := range getUpstreamList("clusterrepos.catalog.cattle.io").Items {
+		snapshot.ClusterRepos[repo.Metadata.Name] = repo.Spec
+	}
+	return snapshot
+}
+
+// patchUpstream patches the resource on the upstream cluster with the JSON patch
+func patchUpstream(resource, name string, patch any) {
+	content, err := json.Marshal(patch)
+	Expect(err).To(BeNil())
+	_, err = runUpstreamKubectl("patch", resource, name, "--type=json", "-p", string(content))
+	Expect(err).To(BeNil(), "Failed to restore %s %s", resource, name)
+}
+
+// sameJSON returns true if both JSON documents hold the same data, regardless of the keys order and formatting
+func sameJSON(a, b json.RawMessage) bool {
+	var x, y any
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+/*
+Restore the Rancher settings of the snapshot; only the settings that changed are updated, and the deleted chart repositories are recreated.
+The operator charts are uninstalled if they differ from the snapshot, Rancher then reinstalls its own version when needed.
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func (s *RancherSettingsSnapshot) Restore() {
+	current := SnapshotRancherSettings()
+
+	ginkgo.By("Restoring the Rancher settings", func() {
+		for _, name := range sortedKeys(s.Settings) {
+			if value := s.Settings[name]; current.Settings[name] != value {
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Restoring setting %s to %q", name, value))
+				patchUpstream("settings.management.cattle.io", name, []map[string]any{{"op": "replace", "path": "/value", "value": value}})
+			}
+		}
+	})
+
+	ginkgo.By("Restoring the kontainer drivers", func() {
+		for _, name := range sortedKeys(s.KontainerDrivers) {
+			active, found := current.KontainerDrivers[name]
+			if !found {
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Kontainer driver %s was deleted, it can not be restored", name))
+				continue
+			}
+			if active != s.KontainerDrivers[name] {
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Restoring kontainer driver %s to active=%t", name, s.KontainerDrivers[name]))
+				patchUpstream("kontainerdrivers.management.cattle.io", name, []map[string]any{{"op": "replace", "path": "/spec/active", "value": s.KontainerDrivers[name]}})
+			}
+		}
+	})
+
+	ginkgo.By("Restoring the chart repositories", func() {
+		for _, name := range sortedKeys(s.ClusterRepos) {
+			spec, found := current.ClusterRepos[name]
+			switch {
+			case !found:
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Recreating chart repository %s", name))
+				repo, err := json.Marshal(map[string]any{
+					"apiVersion": "catalog.cattle.io/v1",
+					"kind":       "ClusterRepo",
+					"metadata":   map[string]string{"name": name},
+					"spec":       s.ClusterRepos[name],
+				})
+				Expect(err).To(BeNil())
+				_, err = extcli.Kubectl.WithStdin(repo).Run(upstreamKubectlArgs("apply", "-f", "-")...)
+				Expect(err).To(BeNil(), "Failed to recreate chart repository %s", name)
+			case !sameJSON(spec, s.ClusterRepos[name]):
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Restoring chart repository %s", name))
+				patchUpstream("clusterrepos.catalog.cattle.io", name, []map[string]any{{"op": "replace", "path": "/spec", "value": s.ClusterRepos[name]}})
+			}
+		}
+	})
+
+	if !reflect.DeepEqual(current.OperatorCharts, s.OperatorCharts) {
+		ginkgo.By("Uninstalling the operator charts", func() {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Operator charts %v differ from %v", current.OperatorCharts, s.OperatorCharts))
+			UninstallOperatorCharts()
+		})
+	}
+}
+
+// sortedKeys returns the keys of the map in order, so that the settings are restored in a stable order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,89 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// maxSoakDuration is the longest SOAK_DURATION allowed by the run config
+const maxSoakDuration = 20 * time.Hour
+
+// soakSettleTime is the time left to Rancher to settle down after a change converged, before the cluster is expected to stay still
+const soakSettleTime = 2 * time.Minute
+
+// SoakChange is an out-of-band change made on the cloud provider during a soak cycle; the field of the UpstreamSpec returned by Extract
+// must converge to Expected, see WaitForUpstreamField
+type SoakChange struct {
+	Description string
+	Apply       func() error
+	Extract     func(*management.Cluster) any
+	Expected    any
+}
+
+// unexpectedTransitions returns the transitions of a cluster that is expected to stay still, i.e. the ones leaving the active state;
+// a transition of a still cluster means it was reconciled without any reason
+func unexpectedTransitions(transitions []ClusterTransition) []ClusterTransition {
+	var unexpected []ClusterTransition
+	for _, transition := range transitions {
+		if transition.State != activeState || transition.Transitioning != "" {
+			unexpected = append(unexpected, transition)
+		}
+	}
+	return unexpected
+}
+
+/*
+Keep a cluster under Rancher management for the given duration to catch the slow-burn operator bugs: every cycle makes an out-of-band change
+on the cloud provider and waits for the UpstreamSpec to converge, then leaves the cluster alone for the rest of the interval and checks that
+it stayed active without being reconciled, and that the change was not reverted.
+It requires RecordClusterTransitions to be called by the suite.
+  - @param client Rancher client
+  - @param cluster Downstream cluster, active
+  - @param duration Duration of the soak, for e.g. the SOAK_DURATION of the run config
+  - @param interval Duration of a cycle, for e.g. the SOAK_INTERVAL of the run config
+  - @param nextChange Returns the change of the given cycle, starting at 1; the value of the change should differ from one cycle to the next
+  - @returns The up-to-date cluster; the function will fail through Ginkgo in case of issue
+*/
+func SoakCluster(client *rancher.Client, cluster *management.Cluster, duration, interval time.Duration, nextChange func(cycle int) SoakChange) *management.Cluster {
+	deadline := time.Now().Add(duration)
+	for cycle := 1; time.Now().Before(deadline); cycle++ {
+		cycleStart := time.Now()
+		change := nextChange(cycle)
+
+		ginkgo.By(fmt.Sprintf("Soak cycle %d of cluster %s: %s", cycle, cluster.Name, change.Description), func() {
+			Expect(change.Apply()).To(Succeed())
+
+			var err error
+			cluster, err = WaitForUpstreamField(client, cluster.ID, change.Extract, change.Expected, 15*time.Minute)
+			Expect(err).To(BeNil())
+			cluster, err = WaitUntilClusterIsReady(cluster, client)
+			Expect(err).To(BeNil())
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Soak cycle %d of cluster %s converged after %s", cycle, cluster.Name, time.Since(cycleStart).Round(time.Second)))
+		})
+
+		ginkgo.By(fmt.Sprintf("Soak cycle %d of cluster %s: leaving the cluster alone", cycle, cluster.Name), func() {
+			time.Sleep(soakSettleTime)
+			stillSince := time.Now()
+			time.Sleep(max(min(time.Until(cycleStart.Add(interval)), time.Until(deadline)), 0))
+
+			var lines []string
+			for _, transition := range unexpectedTransitions(ClusterTransitionsOf(cluster.ID, stillSince)) {
+				lines = append(lines, transition.String())
+			}
+			Expect(lines).To(BeEmpty(), fmt.Sprintf("Cluster %s was reconciled without any change:\n%s", cluster.Name, strings.Join(lines, "\n")))
+
+			var err error
+			// a zero timeout checks the field once
+			cluster, err = WaitForUpstreamField(client, cluster.ID, change.Extract, change.Expected, 0)
+			Expect(err).To(BeNil(), fmt.Sprintf("The change of soak cycle %d was reverted", cycle))
+			Expect(cluster.State).To(Equal(activeState))
+		})
+	}
+	return cluster
+}
@@ -0,0 +1,19 @@
+package helpers
+
+import "testing"
+
+func TestUnexpectedTransitions(t *testing.T) {
+	transitions := []ClusterTransition{
+		{ClusterName: "c1", State: "active"},
+		{ClusterName: "c2", State: "updating", Transitioning: "yes"},
+		{ClusterName: "c3", State: "active", Transitioning: "error", TransitioningMessage: "throttled"},
+		{ClusterName: "c4", State: "active"},
+	}
+	got := unexpectedTransitions(transitions)
+	if len(got) != 2 || got[0].ClusterName != "c2" || got[1].ClusterName != "c3" {
+		t.Errorf("unexpectedTransitions() = %v, want the transitions of c2 and c3", got)
+	}
+	if got := unexpectedTransitions(nil); len(got) != 0 {
+		t.Errorf("unexpectedTransitions(nil) = %v, want none", got)
+	}
+}
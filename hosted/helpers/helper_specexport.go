@@ -0,0 +1,179 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"sigs.k8s.io/yaml"
+)
+
+// ClusterSpec is the hosted config of a cluster as exported to YAML, from which the cluster can be created again
+type ClusterSpec struct {
+	Name      string                           `json:"name"`
+	EKSConfig *management.EKSClusterConfigSpec `json:"eksConfig,omitempty"`
+	GKEConfig *management.GKEClusterConfigSpec `json:"gkeConfig,omitempty"`
+	AKSConfig *management.AKSClusterConfigSpec `json:"aksConfig,omitempty"`
+}
+
+// clusterSpecOf returns the exportable spec of a cluster, or an error if it has no hosted config
+func clusterSpecOf(cluster *management.Cluster) (*ClusterSpec, error) {
+	spec := &ClusterSpec{Name: cluster.Name, EKSConfig: cluster.EKSConfig, GKEConfig: cluster.GKEConfig, AKSConfig: cluster.AKSConfig}
+	if spec.EKSConfig == nil && spec.GKEConfig == nil && spec.AKSConfig == nil {
+		return nil, fmt.Errorf("cluster %s has no hosted config", cluster.Name)
+	}
+	return spec, nil
+}
+
+// locationAndTags returns the region/zone/location of the spec and its tags/labels, as tracked for the created resources
+func (spec *ClusterSpec) locationAndTags() (string, map[string]string) {
+	var tags *map[string]string
+	switch {
+	case spec.EKSConfig != nil:
+		if tags = spec.EKSConfig.Tags; tags != nil {
+			return spec.EKSConfig.Region, *tags
+		}
+		return spec.EKSConfig.Region, nil
+	case spec.GKEConfig != nil:
+		location := spec.GKEConfig.Zone
+		if location == "" {
+			location = spec.GKEConfig.Region
+		}
+		if tags = spec.GKEConfig.Labels; tags != nil {
+			return location, *tags
+		}
+		return location, nil
+	case spec.AKSConfig != nil:
+		return spec.AKSConfig.ResourceLocation, spec.AKSConfig.Tags
+	}
+	return "", nil
+}
+
+// specSubsetProblems returns the values of the exported spec which differ in the actual one, for e.g. "eksConfig.nodeGroups[0].desiredSize: 1 != 2";
+// the fields only set in the actual spec, for e.g. defaulted by Rancher, are ignored.
+func specSubsetProblems(exported, actual any, path string) []string {
+	switch exportedValue := exported.(type) {
+	case map[string]any:
+		actualValue, ok := actual.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: %v != %v", path, exported, actual)}
+		}
+		var problems []string
+		for key, value := range exportedValue {
+			subPath := key
+			if path != "" {
+				subPath = path + "." + key
+			}
+			problems = append(problems, specSubsetProblems(value, actualValue[key], subPath)...)
+		}
+		return problems
+	case []any:
+		actualValue, ok := actual.([]any)
+		if !ok || len(actualValue) != len(exportedValue) {
+			return []string{fmt.Sprintf("%s: %v != %v", path, exported, actual)}
+		}
+		var problems []string
+		for i := range exportedValue {
+			problems = append(problems, specSubsetProblems(exportedValue[i], actualValue[i], fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return problems
+	}
+	if !reflect.DeepEqual(exported, actual) {
+		return []string{fmt.Sprintf("%s: %v != %v", path, exported, actual)}
+	}
+	return nil
+}
+
+// toGeneric converts a value to its generic JSON form, with maps, lists and scalars only
+func toGeneric(value any) (any, error) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var generic any
+	err = json.Unmarshal(content, &generic)
+	return generic, err
+}
+
+/*
+Export the hosted config of a cluster as YAML to the artifacts directory of the spec
+  - @param cluster Downstream cluster
+  - @returns The path of the YAML file; the function will fail through Ginkgo in case of issue
+*/
+func ExportClusterSpec(cluster *management.Cluster) string {
+	spec, err := clusterSpecOf(cluster)
+	Expect(err).To(BeNil())
+	content, err := yaml.Marshal(spec)
+	Expect(err).To(BeNil())
+
+	path := filepath.Join(CurrentArtifactDir(), cluster.Name+"-spec.yaml")
+	Expect(os.WriteFile(path, content, 0o644)).To(Succeed())
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Exported the spec of cluster %s to %s", cluster.Name, path))
+	return path
+}
+
+// LoadClusterSpec reads a cluster spec exported by ExportClusterSpec
+func LoadClusterSpec(path string) (*ClusterSpec, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec := &ClusterSpec{}
+	if err = yaml.UnmarshalStrict(content, spec); err != nil {
+		return nil, err
+	}
+	if spec.EKSConfig == nil && spec.GKEConfig == nil && spec.AKSConfig == nil {
+		return nil, fmt.Errorf("spec %s has no hosted config", path)
+	}
+	return spec, nil
+}
+
+/*
+Create a hosted cluster from a spec exported by ExportClusterSpec; the cluster of the spec must have been deleted from Rancher
+and from the cloud provider beforehand, since the same names are used. With PROVISIONING_BACKEND=terraform, it is created with CreateClusterWithTerraform;
+with DRY_RUN=true, it is only validated against the Rancher schema, see DryRunCluster.
+  - @param client Rancher client
+  - @param spec Cluster spec, for e.g. from LoadClusterSpec
+  - @returns The created cluster, or an error
+*/
+func CreateClusterFromSpec(client *rancher.Client, spec *ClusterSpec) (*management.Cluster, error) {
+	if IsDryRun() {
+		return DryRunCluster(client, spec, DryRunChecks{})
+	}
+	if IsTerraformBackend() {
+		return CreateClusterWithTerraform(client, spec)
+	}
+	cluster, err := client.Management.Cluster.Create(&management.Cluster{
+		DockerRootDir: "/var/lib/docker",
+		Name:          spec.Name,
+		EKSConfig:     spec.EKSConfig,
+		GKEConfig:     spec.GKEConfig,
+		AKSConfig:     spec.AKSConfig,
+	})
+	location, tags := spec.locationAndTags()
+	return TrackRancherCluster(OperationProvision, cluster, err, location, tags)
+}
+
+/*
+Check that the hosted config of a cluster created from an exported spec still holds every value of the spec, once provisioned
+  - @param cluster Cluster created with CreateClusterFromSpec
+  - @param spec Cluster spec it was created from
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckClusterMatchesSpec(cluster *management.Cluster, spec *ClusterSpec) {
+	ginkgo.By(fmt.Sprintf("Checking cluster %s matches its exported spec", cluster.Name), func() {
+		actual, err := clusterSpecOf(cluster)
+		Expect(err).To(BeNil())
+		exportedValue, err := toGeneric(spec)
+		Expect(err).To(BeNil())
+		actualValue, err := toGeneric(actual)
+		Expect(err).To(BeNil())
+		Expect(specSubsetProblems(exportedValue, actualValue, "")).To(BeEmpty())
+	})
+}
@@ -0,0 +1,68 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/yaml"
+)
+
+func TestClusterSpecRoundTrip(t *testing.T) {
+	cluster := &management.Cluster{Name: "hp-ci-abcde", EKSConfig: &management.EKSClusterConfigSpec{
+		DisplayName:       "hp-ci-abcde",
+		Region:            "ap-south-1",
+		KubernetesVersion: pointer.String("1.31"),
+		NodeGroups:        &[]management.NodeGroup{{NodegroupName: pointer.String("ng"), DesiredSize: pointer.Int64(1)}},
+	}}
+	spec, err := clusterSpecOf(cluster)
+	if err != nil {
+		t.Fatalf("clusterSpecOf() = %v", err)
+	}
+	content, err := yaml.Marshal(spec)
+	if err != nil {
+		t.Fatalf("yaml.Marshal() = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	if err = os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadClusterSpec(path)
+	if err != nil {
+		t.Fatalf("LoadClusterSpec() = %v", err)
+	}
+	if !reflect.DeepEqual(loaded, spec) {
+		t.Errorf("LoadClusterSpec() = %+v, want %+v", loaded, spec)
+	}
+	if location, _ := loaded.locationAndTags(); location != "ap-south-1" {
+		t.Errorf("locationAndTags() = %s, want ap-south-1", location)
+	}
+
+	if _, err = clusterSpecOf(&management.Cluster{Name: "custom"}); err == nil {
+		t.Errorf("clusterSpecOf() of a cluster without hosted config returned no error")
+	}
+}
+
+func TestSpecSubsetProblems(t *testing.T) {
+	exported := map[string]any{"eksConfig": map[string]any{"region": "ap-south-1", "nodeGroups": []any{map[string]any{"desiredSize": 1.0}}}}
+
+	defaulted := map[string]any{"eksConfig": map[string]any{"region": "ap-south-1", "imported": false, "nodeGroups": []any{map[string]any{"desiredSize": 1.0, "diskSize": 20.0}}}}
+	if problems := specSubsetProblems(exported, defaulted, ""); len(problems) != 0 {
+		t.Errorf("specSubsetProblems() with defaulted fields = %v, want none", problems)
+	}
+
+	changed := map[string]any{"eksConfig": map[string]any{"region": "us-west-2", "nodeGroups": []any{map[string]any{"desiredSize": 2.0}}}}
+	problems := specSubsetProblems(exported, changed, "")
+	if len(problems) != 2 {
+		t.Errorf("specSubsetProblems() with changed fields = %v, want 2 problems", problems)
+	}
+
+	missing := map[string]any{"eksConfig": map[string]any{"region": "ap-south-1", "nodeGroups": []any{}}}
+	if problems := specSubsetProblems(exported, missing, ""); len(problems) != 1 || problems[0] != "eksConfig.nodeGroups: [map[desiredSize:1]] != []" {
+		t.Errorf("specSubsetProblems() with a missing nodegroup = %v, want 1 problem on eksConfig.nodeGroups", problems)
+	}
+}
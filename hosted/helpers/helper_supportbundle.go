@@ -0,0 +1,89 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+)
+
+// upstreamKubectlArgs prefixes the kubectl args with the upstream (Rancher) cluster kubeconfig, if set
+func upstreamKubectlArgs(args ...string) []string {
+	if Kubeconfig != "" {
+		return append([]string{"--kubeconfig", Kubeconfig}, args...)
+	}
+	return args
+}
+
+// runUpstreamKubectl runs a kubectl command against the upstream (Rancher) cluster
+func runUpstreamKubectl(args ...string) (string, error) {
+	return kubectl.RunWithoutErr(upstreamKubectlArgs(args...)...)
+}
+
+// CollectSupportBundleOnFailure collects a support bundle for the current spec if it has failed;
+// see CollectSupportBundle for the contents of the bundle.
+// It is meant to be used in a JustAfterEach node so that the data is collected before the cluster is deleted by AfterEach.
+func CollectSupportBundleOnFailure(clusterName string) {
+	report := ginkgo.CurrentSpecReport()
+	if !report.Failed() {
+		return
+	}
+	dir, err := CollectSupportBundle(report, clusterName)
+	if err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to collect the support bundle: %v", err))
+		return
+	}
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Support bundle collected in %s", dir))
+}
+
+// CollectSupportBundle collects rancher pod logs, provider operator pod logs, the management cluster object (if clusterName is non-empty)
+// and the recent events into the spec artifact directory; it returns the directory path.
+// Collection is best-effort: a failing command is recorded in the bundle instead of failing the spec.
+func CollectSupportBundle(report ginkgo.SpecReport, clusterName string) (string, error) {
+	dir, err := ArtifactDir(report)
+	if err != nil {
+		return "", err
+	}
+
+	// only fetch the logs generated since the spec started
+	since := time.Minute
+	if !report.StartTime.IsZero() {
+		since += time.Since(report.StartTime).Round(time.Minute)
+	}
+	sinceArg := fmt.Sprintf("--since=%s", since)
+
+	commands := map[string][]string{
+		"rancher.log":              {"logs", "--namespace", CattleSystemNS, "-l", "app=rancher", "--tail=-1", "--prefix", sinceArg},
+		"operator.log":             {"logs", "--namespace", CattleSystemNS, "-l", operatorSelector(Provider), "--tail=-1", "--prefix", sinceArg},
+		"events.txt":               {"get", "events", "--all-namespaces", "--sort-by=.lastTimestamp"},
+		"cattle-system-pods.txt":   {"get", "pods", "--namespace", CattleSystemNS, "-o", "wide"},
+		"kontainerdrivers.txt":     {"get", "kontainerdrivers.management.cattle.io"},
+		"management-clusters.yaml": {"get", "clusters.management.cattle.io", "-o", "yaml"},
+	}
+
+	if clusterName != "" {
+		clusterID, err := runUpstreamKubectl("get", "clusters.management.cattle.io", "-o", fmt.Sprintf("jsonpath={.items[?(@.spec.displayName==\"%s\")].metadata.name}", clusterName))
+		if clusterID = strings.TrimSpace(clusterID); err == nil && clusterID != "" {
+			// the cluster object is more relevant than the complete list of clusters
+			delete(commands, "management-clusters.yaml")
+			commands[fmt.Sprintf("cluster-%s.yaml", clusterName)] = []string{"get", "clusters.management.cattle.io", clusterID, "-o", "yaml"}
+		}
+	}
+
+	for file, args := range commands {
+		out, err := runUpstreamKubectl(args...)
+		if err != nil {
+			out = fmt.Sprintf("kubectl %s failed: %v\n%s", strings.Join(args, " "), err, out)
+		}
+		if err = os.WriteFile(filepath.Join(dir, file), []byte(out), 0o644); err != nil {
+			return dir, err
+		}
+	}
+
+	failure := fmt.Sprintf("%s\n\n%s\n%s\n", report.FullText(), report.Failure.Message, report.Failure.Location.String())
+	return dir, os.WriteFile(filepath.Join(dir, "failure.txt"), []byte(failure), 0o644)
+}
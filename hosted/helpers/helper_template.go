@@ -0,0 +1,197 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"sigs.k8s.io/yaml"
+)
+
+// ClusterTemplate is a hosted config shared by several clusters, each created from it with its own answers;
+// the locked fields are set by the template only, for e.g. the network or the instance type imposed by a platform team.
+type ClusterTemplate struct {
+	Spec ClusterSpec `json:"spec"`
+	// Locked are the paths of the locked fields in the spec, with dots and list indexes, for e.g. "eksConfig.nodeGroups.0.instanceType"
+	Locked []string `json:"locked,omitempty"`
+}
+
+// ClusterTemplatePath returns the path of the cluster template of a provider, for e.g. "eks", from the directory of a suite
+func ClusterTemplatePath(provider string) string {
+	return fmt.Sprintf("../../helpers/assets/templates/%s.yaml", provider)
+}
+
+// LoadClusterTemplate reads a cluster template, for e.g. from ClusterTemplatePath
+func LoadClusterTemplate(path string) (*ClusterTemplate, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	template := &ClusterTemplate{}
+	if err = yaml.UnmarshalStrict(content, template); err != nil {
+		return nil, err
+	}
+	if template.Spec.EKSConfig == nil && template.Spec.GKEConfig == nil && template.Spec.AKSConfig == nil {
+		return nil, fmt.Errorf("template %s has no hosted config", path)
+	}
+	return template, nil
+}
+
+// lockedPath returns the locked path an answer path is, is part of or contains, if any
+func (t *ClusterTemplate) lockedPath(path string) string {
+	for _, locked := range t.Locked {
+		if path == locked || strings.HasPrefix(path, locked+".") || strings.HasPrefix(locked, path+".") {
+			return locked
+		}
+	}
+	return ""
+}
+
+// setTemplateValue returns the generic value with the field at the given path set, creating the missing maps along the way;
+// a list index must exist in the value, since the other items of the list would be unknown.
+func setTemplateValue(value any, keys []string, fieldValue any) (any, error) {
+	if len(keys) == 0 {
+		return fieldValue, nil
+	}
+	key, rest := keys[0], keys[1:]
+	switch container := value.(type) {
+	case nil:
+		child, err := setTemplateValue(nil, rest, fieldValue)
+		return map[string]any{key: child}, err
+	case map[string]any:
+		child, err := setTemplateValue(container[key], rest, fieldValue)
+		container[key] = child
+		return container, err
+	case []any:
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= len(container) {
+			return nil, fmt.Errorf("%s is not an index of a list of %d items", key, len(container))
+		}
+		container[index], err = setTemplateValue(container[index], rest, fieldValue)
+		return container, err
+	}
+	return nil, fmt.Errorf("%v is neither a map nor a list", value)
+}
+
+// templateValue returns the field at the given path of a generic value, and whether it is set
+func templateValue(value any, path string) (any, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch container := value.(type) {
+		case map[string]any:
+			var ok bool
+			if value, ok = container[key]; !ok {
+				return nil, false
+			}
+		case []any:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(container) {
+				return nil, false
+			}
+			value = container[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// setClusterName sets the name of a cluster in its spec, along with the hosted config fields derived from it
+func (spec *ClusterSpec) setClusterName(name string) {
+	spec.Name = name
+	switch {
+	case spec.EKSConfig != nil:
+		spec.EKSConfig.DisplayName = name
+	case spec.GKEConfig != nil:
+		spec.GKEConfig.ClusterName = name
+	case spec.AKSConfig != nil:
+		spec.AKSConfig.ClusterName = name
+		spec.AKSConfig.ResourceGroup = name
+		dnsPrefix := name + "-dns"
+		spec.AKSConfig.DNSPrefix = &dnsPrefix
+	}
+}
+
+/*
+Render the spec of a cluster from a template and its answers; an answer to a locked field, or to a field containing or contained by one, is rejected.
+  - @param name Name of the cluster
+  - @param answers Values of the fields of the cluster by path, like the locked fields of the template, for e.g. {"eksConfig.region": "ap-south-1"}
+  - @returns The cluster spec, to be created with CreateClusterFromSpec, or an error
+*/
+func (t *ClusterTemplate) Render(name string, answers map[string]any) (*ClusterSpec, error) {
+	paths := make([]string, 0, len(answers))
+	for path := range answers {
+		if locked := t.lockedPath(path); locked != "" {
+			return nil, fmt.Errorf("answer %s overrides the locked field %s of the template", path, locked)
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	value, err := toGeneric(t.Spec)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		if value, err = setTemplateValue(value, strings.Split(path, "."), answers[path]); err != nil {
+			return nil, fmt.Errorf("answer %s: %w", path, err)
+		}
+	}
+
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	spec := &ClusterSpec{}
+	if err = decoder.Decode(spec); err != nil {
+		return nil, err
+	}
+	spec.setClusterName(name)
+	return spec, nil
+}
+
+// lockedFieldProblems returns the locked fields of a template whose value differs in the given spec
+func (t *ClusterTemplate) lockedFieldProblems(spec *ClusterSpec) ([]string, error) {
+	templateSpec, err := toGeneric(t.Spec)
+	if err != nil {
+		return nil, err
+	}
+	actualSpec, err := toGeneric(spec)
+	if err != nil {
+		return nil, err
+	}
+	var problems []string
+	for _, path := range t.Locked {
+		expected, ok := templateValue(templateSpec, path)
+		if !ok {
+			return nil, fmt.Errorf("locked field %s is not set by the template", path)
+		}
+		actual, _ := templateValue(actualSpec, path)
+		problems = append(problems, specSubsetProblems(expected, actual, path)...)
+	}
+	return problems, nil
+}
+
+/*
+Check that the locked fields of a cluster created from a template still have the values of the template, once provisioned
+  - @param cluster Cluster created from a spec rendered by the template
+  - @param template Cluster template
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckClusterTemplateLocks(cluster *management.Cluster, template *ClusterTemplate) {
+	ginkgo.By(fmt.Sprintf("Checking the locked fields of cluster %s match its template", cluster.Name), func() {
+		spec, err := clusterSpecOf(cluster)
+		Expect(err).To(BeNil())
+		problems, err := template.lockedFieldProblems(spec)
+		Expect(err).To(BeNil())
+		Expect(problems).To(BeEmpty())
+	})
+}
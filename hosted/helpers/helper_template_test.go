@@ -0,0 +1,93 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClusterTemplatesLoad(t *testing.T) {
+	for _, provider := range []string{"eks", "gke", "aks"} {
+		template, err := LoadClusterTemplate("assets/templates/" + provider + ".yaml")
+		if err != nil {
+			t.Fatalf("LoadClusterTemplate(%s) = %v", provider, err)
+		}
+		spec, err := template.Render("hp-ci-abcde", nil)
+		if err != nil {
+			t.Fatalf("Render() of template %s = %v", provider, err)
+		}
+		problems, err := template.lockedFieldProblems(spec)
+		if err != nil || len(problems) != 0 {
+			t.Errorf("lockedFieldProblems() of template %s = %v, %v, want none", provider, problems, err)
+		}
+	}
+}
+
+func TestClusterTemplateRender(t *testing.T) {
+	template, err := LoadClusterTemplate("assets/templates/eks.yaml")
+	if err != nil {
+		t.Fatalf("LoadClusterTemplate() = %v", err)
+	}
+	answers := map[string]any{
+		"eksConfig.region":                   "us-west-2",
+		"eksConfig.kubernetesVersion":        "1.31",
+		"eksConfig.nodeGroups.0.version":     "1.31",
+		"eksConfig.nodeGroups.0.desiredSize": 2,
+		"eksConfig.tags.owner":               "hosted-providers-qa",
+	}
+	first, err := template.Render("hp-ci-first", answers)
+	if err != nil {
+		t.Fatalf("Render() = %v", err)
+	}
+	second, err := template.Render("hp-ci-second", answers)
+	if err != nil {
+		t.Fatalf("Render() = %v", err)
+	}
+
+	if first.Name != "hp-ci-first" || first.EKSConfig.DisplayName != "hp-ci-first" || second.EKSConfig.DisplayName != "hp-ci-second" {
+		t.Errorf("Render() names = %s/%s and %s, want the cluster names", first.Name, first.EKSConfig.DisplayName, second.EKSConfig.DisplayName)
+	}
+	nodeGroup := (*first.EKSConfig.NodeGroups)[0]
+	if first.EKSConfig.Region != "us-west-2" || *nodeGroup.DesiredSize != 2 || *nodeGroup.Version != "1.31" || (*first.EKSConfig.Tags)["owner"] != "hosted-providers-qa" {
+		t.Errorf("Render() = %+v, want the answers set", first.EKSConfig)
+	}
+	if *nodeGroup.InstanceType != "t3.large" || *nodeGroup.NodegroupName != "ng" {
+		t.Errorf("Render() nodegroup = %+v, want the template values kept", nodeGroup)
+	}
+	if (*template.Spec.EKSConfig.NodeGroups)[0].Version != nil {
+		t.Errorf("Render() modified the template")
+	}
+
+	for _, path := range []string{"eksConfig.privateAccess", "eksConfig.nodeGroups.0.instanceType", "eksConfig.nodeGroups.0", "eksConfig.nodeGroups"} {
+		if _, err = template.Render("hp-ci-locked", map[string]any{path: "value"}); err == nil || !strings.Contains(err.Error(), "locked") {
+			t.Errorf("Render() with an answer to %s = %v, want a locked field error", path, err)
+		}
+	}
+	for _, path := range []string{"eksConfig.nodeGroups.1.desiredSize", "eksConfig.region.name", "eksConfig.unknown"} {
+		if _, err = template.Render("hp-ci-invalid", map[string]any{path: "value"}); err == nil {
+			t.Errorf("Render() with an answer to %s returned no error", path)
+		}
+	}
+}
+
+func TestClusterTemplateLockedFieldProblems(t *testing.T) {
+	template, err := LoadClusterTemplate("assets/templates/aks.yaml")
+	if err != nil {
+		t.Fatalf("LoadClusterTemplate() = %v", err)
+	}
+	spec, err := template.Render("hp-ci-abcde", map[string]any{"aksConfig.nodePools.0.count": 2})
+	if err != nil {
+		t.Fatalf("Render() = %v", err)
+	}
+	if spec.AKSConfig.ResourceGroup != "hp-ci-abcde" || *spec.AKSConfig.DNSPrefix != "hp-ci-abcde-dns" {
+		t.Errorf("Render() = %+v, want the resource group and DNS prefix of the cluster", spec.AKSConfig)
+	}
+
+	(*spec.AKSConfig.NodePools)[0].VMSize = "Standard_D4s_v3"
+	problems, err := template.lockedFieldProblems(spec)
+	if err != nil {
+		t.Fatalf("lockedFieldProblems() = %v", err)
+	}
+	if len(problems) != 1 || !strings.HasPrefix(problems[0], "aksConfig.nodePools.0.vmSize") {
+		t.Errorf("lockedFieldProblems() = %v, want the vmSize only", problems)
+	}
+}
@@ -0,0 +1,298 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/clusters"
+)
+
+// provisioningBackendTerraform is the PROVISIONING_BACKEND creating the hosted clusters with the rancher2 Terraform provider instead of the Rancher API
+const provisioningBackendTerraform = "terraform"
+
+const (
+	// terraformTemplate is the HCL template of the Terraform configuration of a cluster, from the directory of a suite
+	terraformTemplate = "../../helpers/assets/terraform/main.tf.tmpl"
+	// terraformResource is the name of the rancher2_cluster resource of the configuration
+	terraformResource = "hosted"
+	// terraformClusterTimeout is how long the cluster applied by Terraform can take to appear on Rancher
+	terraformClusterTimeout = 5 * time.Minute
+)
+
+// terraformRenames are the fields of the hosted configs which have another name in the rancher2 provider than their snake case name
+var terraformRenames = map[string]string{
+	"amazonCredentialSecret":   "cloud_credential_id",
+	"azureCredentialSecret":    "cloud_credential_id",
+	"displayName":              "name",
+	"clusterName":              "name",
+	"nodegroupName":            "name",
+	"clusterIpv4Cidr":          "cluster_ipv4_cidr_block",
+	"masterAuthorizedNetworks": "master_authorized_networks_config",
+}
+
+// IsTerraformBackend returns true if the hosted clusters are created with the rancher2 Terraform provider (PROVISIONING_BACKEND=terraform)
+func IsTerraformBackend() bool {
+	return runConfig.ProvisioningBackend == provisioningBackendTerraform
+}
+
+// snakeCase returns the snake case name of a field of the hosted configs, the acronyms being a single word, for e.g. ebs_csi_driver for ebsCSIDriver
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			previous := runes[i-1]
+			acronymEnd := unicode.IsUpper(previous) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || acronymEnd {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// terraformValue returns the value of a hosted config in the JSON syntax of Terraform: the fields are renamed after the arguments
+// of the rancher2 provider, while the keys of the maps, for e.g. the tags, are kept; the unset fields are left out.
+func terraformValue(value reflect.Value) (any, bool) {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return nil, false
+		}
+		return terraformValue(value.Elem())
+	case reflect.Struct:
+		fields := map[string]any{}
+		for i := 0; i < value.NumField(); i++ {
+			name, options, _ := strings.Cut(value.Type().Field(i).Tag.Get("json"), ",")
+			if name == "" || name == "-" || (strings.Contains(options, "omitempty") && value.Field(i).IsZero()) {
+				continue
+			}
+			if fieldValue, ok := terraformValue(value.Field(i)); ok {
+				if renamed, found := terraformRenames[name]; found {
+					fields[renamed] = fieldValue
+				} else {
+					fields[snakeCase(name)] = fieldValue
+				}
+			}
+		}
+		return fields, true
+	case reflect.Map:
+		if value.IsNil() {
+			return nil, false
+		}
+		entries := map[string]any{}
+		for _, key := range value.MapKeys() {
+			if entryValue, ok := terraformValue(value.MapIndex(key)); ok {
+				entries[fmt.Sprint(key.Interface())] = entryValue
+			}
+		}
+		return entries, true
+	case reflect.Slice:
+		if value.IsNil() {
+			return nil, false
+		}
+		items := make([]any, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			if item, ok := terraformValue(value.Index(i)); ok {
+				items = append(items, item)
+			}
+		}
+		return items, true
+	}
+	return value.Interface(), true
+}
+
+// terraformClusterConfig returns the content of cluster.tf.json, the rancher2_cluster resource of the cluster spec
+func terraformClusterConfig(spec *ClusterSpec) ([]byte, error) {
+	resource := map[string]any{"name": spec.Name}
+	switch {
+	case spec.EKSConfig != nil:
+		resource["eks_config_v2"], _ = terraformValue(reflect.ValueOf(spec.EKSConfig))
+	case spec.GKEConfig != nil:
+		resource["gke_config_v2"], _ = terraformValue(reflect.ValueOf(spec.GKEConfig))
+	case spec.AKSConfig != nil:
+		resource["aks_config_v2"], _ = terraformValue(reflect.ValueOf(spec.AKSConfig))
+	default:
+		return nil, fmt.Errorf("cluster %s has no hosted config", spec.Name)
+	}
+	return json.MarshalIndent(map[string]any{
+		"resource": map[string]any{"rancher2_cluster": map[string]any{terraformResource: resource}},
+	}, "", "  ")
+}
+
+// renderTerraformConfig writes the Terraform configuration of the cluster spec to the directory: main.tf, rendered from the HCL template,
+// and cluster.tf.json, the resource of the cluster
+func renderTerraformConfig(dir, templatePath string, spec *ClusterSpec) error {
+	tmpl, err := template.ParseFiles(templatePath)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	main, err := os.Create(filepath.Join(dir, "main.tf"))
+	if err != nil {
+		return err
+	}
+	defer main.Close()
+	err = tmpl.Execute(main, struct {
+		ClusterName, ProviderVersion, Resource string
+	}{ClusterName: spec.Name, ProviderVersion: runConfig.TerraformProviderVersion, Resource: terraformResource})
+	if err != nil {
+		return err
+	}
+
+	content, err := terraformClusterConfig(spec)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "cluster.tf.json"), content, 0o644)
+}
+
+// terraformWorkspace is the Terraform configuration and state of a cluster created with CreateClusterWithTerraform
+type terraformWorkspace struct {
+	dir string
+	cli *extcli.CLI
+	// applied is closed once terraform apply is done, applyErr is its error
+	applied  chan struct{}
+	applyErr error
+}
+
+// terraformWorkspaces are the workspaces of the clusters created with Terraform by the process, by cluster name
+var terraformWorkspaces = struct {
+	sync.Mutex
+	byName map[string]*terraformWorkspace
+}{byName: map[string]*terraformWorkspace{}}
+
+// run runs the terraform command in the workspace
+func (w *terraformWorkspace) run(args ...string) error {
+	_, err := w.cli.Run(append([]string{"-chdir=" + w.dir}, append(args, "-input=false", "-no-color")...)...)
+	return err
+}
+
+// isApplied returns true once terraform apply is done
+func (w *terraformWorkspace) isApplied() bool {
+	select {
+	case <-w.applied:
+		return true
+	default:
+		return false
+	}
+}
+
+// waitForCluster waits for the cluster applied by Terraform to appear on Rancher, or for the apply to fail
+func (w *terraformWorkspace) waitForCluster(client *rancher.Client, clusterName string) (*management.Cluster, error) {
+	deadline := time.Now().Add(terraformClusterTimeout)
+	for {
+		if w.isApplied() && w.applyErr != nil {
+			return nil, w.applyErr
+		}
+		if clusterID, err := clusters.GetClusterIDByName(client, clusterName); err == nil && clusterID != "" {
+			return client.Management.Cluster.ByID(clusterID)
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("cluster %s applied by terraform is not on Rancher after %s", clusterName, terraformClusterTimeout)
+		}
+		select {
+		case <-w.applied:
+		case <-time.After(clusterWatchInterval):
+		}
+	}
+}
+
+// ConvertHostedConfig converts the hosted config of the cattle config, for e.g. eks.ClusterConfig, to the hosted config of the Rancher API,
+// for e.g. management.EKSClusterConfigSpec, to create the cluster with CreateClusterWithTerraform; their fields have the same JSON names
+func ConvertHostedConfig(config, spec any) error {
+	content, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, spec)
+}
+
+/*
+CreateClusterWithTerraform creates a hosted cluster with the rancher2 Terraform provider, like the customers managing their clusters with Terraform:
+the configuration is rendered to ArtifactsDir/terraform/<cluster name>, from the HCL template assets/terraform/main.tf.tmpl and the rancher2_cluster
+resource generated from the hosted config of the spec, then applied with terraform. The cluster is returned once it appears on Rancher, while terraform
+apply keeps waiting for it in the background, so that the same checks run as for the clusters created with the API, for e.g. WaitUntilClusterIsReady.
+It is used by the provider helpers creating the clusters and by CreateClusterFromSpec with PROVISIONING_BACKEND=terraform; the cluster must be deleted
+with DeleteRancherCluster, which destroys it with terraform.
+  - @param client Rancher client, whose URL and token are used by the provider
+  - @param spec Cluster spec
+  - @returns The created cluster, or an error
+*/
+func CreateClusterWithTerraform(client *rancher.Client, spec *ClusterSpec) (*management.Cluster, error) {
+	workspace := &terraformWorkspace{
+		dir: filepath.Join(ArtifactsDir, "terraform", spec.Name),
+		cli: extcli.Terraform.WithEnv("RANCHER_URL=https://"+client.RancherConfig.Host, "RANCHER_TOKEN_KEY="+client.Management.Opts.TokenKey,
+			"RANCHER_INSECURE="+strconv.FormatBool(client.Management.Opts.Insecure), "TF_IN_AUTOMATION=true"),
+		applied: make(chan struct{}),
+	}
+	if err := renderTerraformConfig(workspace.dir, terraformTemplate, spec); err != nil {
+		return nil, fmt.Errorf("failed to render the terraform configuration of cluster %s: %w", spec.Name, err)
+	}
+	// the providers are downloaded from the registry
+	workspace.cli = workspace.cli.WithRetries(3, 30*time.Second)
+	if err := workspace.run("init"); err != nil {
+		return nil, err
+	}
+	workspace.cli = workspace.cli.WithRetries(0, 0)
+
+	terraformWorkspaces.Lock()
+	terraformWorkspaces.byName[spec.Name] = workspace
+	terraformWorkspaces.Unlock()
+	go func() {
+		defer ginkgo.GinkgoRecover()
+		workspace.applyErr = workspace.run("apply", "-auto-approve")
+		if workspace.applyErr != nil {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to apply the terraform configuration of cluster %s: %v", spec.Name, workspace.applyErr))
+		}
+		close(workspace.applied)
+	}()
+
+	cluster, err := workspace.waitForCluster(client, spec.Name)
+	location, tags := spec.locationAndTags()
+	return TrackRancherCluster(OperationProvision, cluster, err, location, tags)
+}
+
+/*
+DeleteRancherCluster deletes a hosted cluster from Rancher; a cluster created with CreateClusterWithTerraform is destroyed with terraform,
+unless its apply is still running, since its state is not written yet.
+  - @param client Rancher client
+  - @param cluster Cluster to delete
+  - @returns The error of the deletion
+*/
+func DeleteRancherCluster(client *rancher.Client, cluster *management.Cluster) error {
+	terraformWorkspaces.Lock()
+	workspace := terraformWorkspaces.byName[cluster.Name]
+	terraformWorkspaces.Unlock()
+	if workspace == nil {
+		return client.Management.Cluster.Delete(cluster)
+	}
+	if !workspace.isApplied() {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("The terraform configuration of cluster %s is still being applied, deleting it with the API", cluster.Name))
+		return client.Management.Cluster.Delete(cluster)
+	}
+
+	if err := workspace.run("destroy", "-auto-approve"); err != nil {
+		return err
+	}
+	terraformWorkspaces.Lock()
+	delete(terraformWorkspaces.byName, cluster.Name)
+	terraformWorkspaces.Unlock()
+	return nil
+}
@@ -0,0 +1,104 @@
+package helpers
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/clusters/eks"
+	"k8s.io/utils/pointer"
+)
+
+func TestSnakeCase(t *testing.T) {
+	for name, want := range map[string]string{
+		"kubernetesVersion": "kubernetes_version",
+		"ebsCSIDriver":      "ebs_csi_driver",
+		"ec2SshKey":         "ec2_ssh_key",
+		"projectID":         "project_id",
+		"osDiskSizeGB":      "os_disk_size_gb",
+		"linuxSSHPublicKey": "linux_ssh_public_key",
+		"region":            "region",
+	} {
+		if got := snakeCase(name); got != want {
+			t.Errorf("snakeCase(%s) = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestTerraformClusterConfig(t *testing.T) {
+	eksClusterConfig := eks.ClusterConfig{
+		KubernetesVersion: pointer.String("1.31"),
+		Region:            "us-west-2",
+		Tags:              map[string]string{"Owner": "hosted-providers-e2e"},
+		NodeGroupsConfig:  &[]eks.NodeGroupConfig{{NodegroupName: pointer.String("ng-1"), DesiredSize: pointer.Int64(2), InstanceType: pointer.String("t3.large")}},
+	}
+	eksConfig := &management.EKSClusterConfigSpec{}
+	if err := ConvertHostedConfig(eksClusterConfig, eksConfig); err != nil {
+		t.Fatal(err)
+	}
+	eksConfig.AmazonCredentialSecret = "cattle-global-data:cc-abc12"
+
+	content, err := terraformClusterConfig(&ClusterSpec{Name: "hp-eks-abc12", EKSConfig: eksConfig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config struct {
+		Resource struct {
+			Cluster map[string]struct {
+				Name string         `json:"name"`
+				EKS  map[string]any `json:"eks_config_v2"`
+			} `json:"rancher2_cluster"`
+		} `json:"resource"`
+	}
+	if err = json.Unmarshal(content, &config); err != nil {
+		t.Fatal(err)
+	}
+	resource := config.Resource.Cluster[terraformResource]
+	if resource.Name != "hp-eks-abc12" {
+		t.Errorf("got cluster name %q", resource.Name)
+	}
+	eksValue := resource.EKS
+	if eksValue["cloud_credential_id"] != "cattle-global-data:cc-abc12" || eksValue["kubernetes_version"] != "1.31" || eksValue["region"] != "us-west-2" {
+		t.Errorf("got eks_config_v2 %v", eksValue)
+	}
+	if tags, _ := eksValue["tags"].(map[string]any); tags["Owner"] != "hosted-providers-e2e" {
+		t.Errorf("the keys of the tags must be kept, got %v", eksValue["tags"])
+	}
+	nodeGroups, _ := eksValue["node_groups"].([]any)
+	if len(nodeGroups) != 1 {
+		t.Fatalf("got node_groups %v", eksValue["node_groups"])
+	}
+	if nodeGroup := nodeGroups[0].(map[string]any); nodeGroup["name"] != "ng-1" || nodeGroup["desired_size"] != float64(2) || nodeGroup["instance_type"] != "t3.large" {
+		t.Errorf("got node group %v", nodeGroup)
+	}
+	if _, found := eksValue["imported"]; found {
+		t.Errorf("the unset fields must be left out, got %v", eksValue)
+	}
+
+	if _, err = terraformClusterConfig(&ClusterSpec{Name: "hp-eks-abc12"}); err == nil {
+		t.Error("a spec without hosted config must be rejected")
+	}
+}
+
+func TestRenderTerraformConfig(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "hp-aks-abc12")
+	spec := &ClusterSpec{Name: "hp-aks-abc12", AKSConfig: &management.AKSClusterConfigSpec{ResourceLocation: "westeurope"}}
+	if err := renderTerraformConfig(dir, "assets/terraform/main.tf.tmpl", spec); err != nil {
+		t.Fatal(err)
+	}
+	main, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{`version = "` + runConfig.TerraformProviderVersion + `"`, "value = rancher2_cluster.hosted.id"} {
+		if !strings.Contains(string(main), line) {
+			t.Errorf("main.tf does not contain %q:\n%s", line, main)
+		}
+	}
+	if _, err = os.Stat(filepath.Join(dir, "cluster.tf.json")); err != nil {
+		t.Error(err)
+	}
+}
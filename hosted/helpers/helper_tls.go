@@ -0,0 +1,195 @@
+package helpers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+	"github.com/rancher/shepherd/clients/rancher"
+	v1 "github.com/rancher/shepherd/clients/rancher/v1"
+)
+
+const (
+	rancherTLSSecret = "tls-rancher-ingress"
+	rancherCASecret  = "tls-ca"
+)
+
+// IsRancherPrivateCA returns true if rancher is installed with a certificate signed by a private CA (RANCHER_CA=private)
+func IsRancherPrivateCA() bool {
+	return RancherCA == "private"
+}
+
+// rancherTLSFiles returns the paths of the CA certificate, the certificate and the key to use for rancher;
+// they are either provided by RANCHER_CA_CERT, RANCHER_TLS_CERT and RANCHER_TLS_KEY, or generated for the hostname in the artifacts directory.
+func rancherTLSFiles(hostname string) (caFile, certFile, keyFile string, err error) {
+	caFile, certFile, keyFile = os.Getenv("RANCHER_CA_CERT"), os.Getenv("RANCHER_TLS_CERT"), os.Getenv("RANCHER_TLS_KEY")
+	if caFile != "" && certFile != "" && keyFile != "" {
+		return
+	}
+
+	dir := filepath.Join(ArtifactsDir, "tls")
+	caFile, certFile, keyFile = filepath.Join(dir, "cacerts.pem"), filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	if _, err = os.Stat(caFile); err == nil {
+		// reuse the generated certificates, for e.g. when rancher is upgraded, so that the downstream agents still trust it
+		return
+	}
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return
+	}
+	err = generateRancherCertificates(hostname, caFile, certFile, keyFile)
+	return
+}
+
+// generateRancherCertificates generates a private CA and a certificate signed by it for the hostname
+func generateRancherCertificates(hostname, caFile, certFile, keyFile string) error {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "hosted-providers-e2e-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return err
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: hostname},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if ip := net.ParseIP(hostname); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{hostname}
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	files := map[string]*pem.Block{
+		caFile:   {Type: "CERTIFICATE", Bytes: caDER},
+		certFile: {Type: "CERTIFICATE", Bytes: certDER},
+		keyFile:  {Type: "EC PRIVATE KEY", Bytes: keyDER},
+	}
+	for file, block := range files {
+		if err = os.WriteFile(file, pem.EncodeToMemory(block), 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateRancherTLSSecrets creates the tls-rancher-ingress and tls-ca secrets needed to install rancher with ingress.tls.source=secret and privateCA=true;
+// nothing is done if the secrets already exist
+func CreateRancherTLSSecrets(hostname string) {
+	if _, err := kubectl.RunWithoutErr("get", "secret", rancherCASecret, "--namespace", CattleSystemNS); err == nil {
+		ginkgo.GinkgoLogr.Info("Rancher TLS secrets already exist")
+		return
+	}
+
+	caFile, certFile, keyFile, err := rancherTLSFiles(hostname)
+	Expect(err).To(BeNil())
+
+	// the namespace may already exist, the error is then ignored
+	_, _ = kubectl.RunWithoutErr("create", "namespace", CattleSystemNS)
+	out, err := kubectl.RunWithoutErr("create", "secret", "tls", rancherTLSSecret, "--namespace", CattleSystemNS, "--cert", certFile, "--key", keyFile)
+	Expect(err).To(BeNil(), out)
+	out, err = kubectl.RunWithoutErr("create", "secret", "generic", rancherCASecret, "--namespace", CattleSystemNS, "--from-file=cacerts.pem="+caFile)
+	Expect(err).To(BeNil(), out)
+}
+
+// GetRancherCACert returns the private CA certificate from the tls-ca secret
+func GetRancherCACert() (string, error) {
+	out, err := runUpstreamKubectl("get", "secret", rancherCASecret, "--namespace", CattleSystemNS, "-o", `jsonpath={.data.cacerts\.pem}`)
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %s: %v: %s", rancherCASecret, err, out)
+	}
+	caCert, err := base64.StdEncoding.DecodeString(strings.TrimSpace(out))
+	return string(caCert), err
+}
+
+// CheckRancherPrivateCA checks that rancher serves the private CA in the cacerts Setting,
+// and that the cluster agent of the downstream cluster is configured with its checksum, so that it can connect to rancher
+func CheckRancherPrivateCA(client *rancher.Client, clusterID string) {
+	caCert, err := GetRancherCACert()
+	Expect(err).To(BeNil())
+
+	ginkgo.By("checking the cacerts setting contains the private CA", func() {
+		setting, err := client.Management.Setting.ByID("cacerts")
+		Expect(err).To(BeNil())
+		Expect(strings.TrimSpace(setting.Value)).To(Equal(strings.TrimSpace(caCert)))
+	})
+
+	ginkgo.By("checking the cluster agent trusts the private CA", func() {
+		setting, err := client.Management.Setting.ByID("cacerts")
+		Expect(err).To(BeNil())
+		checksum := sha256.Sum256([]byte(setting.Value))
+
+		downstreamClient, err := client.Steve.ProxyDownstream(clusterID)
+		Expect(err).To(BeNil())
+		deployment, err := downstreamClient.SteveType("apps.deployment").ByID(CattleSystemNS + "/cattle-cluster-agent")
+		Expect(err).To(BeNil())
+		deploymentSpec := struct {
+			Template struct {
+				Spec struct {
+					Containers []struct {
+						Env []struct {
+							Name  string `json:"name"`
+							Value string `json:"value"`
+						} `json:"env"`
+					} `json:"containers"`
+				} `json:"spec"`
+			} `json:"template"`
+		}{}
+		Expect(v1.ConvertToK8sType(deployment.Spec, &deploymentSpec)).To(Succeed())
+		Expect(deploymentSpec.Template.Spec.Containers).ToNot(BeEmpty())
+
+		var agentChecksum string
+		for _, env := range deploymentSpec.Template.Spec.Containers[0].Env {
+			if env.Name == "CATTLE_CA_CHECKSUM" {
+				agentChecksum = env.Value
+			}
+		}
+		Expect(agentChecksum).To(Equal(hex.EncodeToString(checksum[:])))
+	})
+}
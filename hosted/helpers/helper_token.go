@@ -0,0 +1,103 @@
+package helpers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/pkg/session"
+)
+
+// tokenRenewBefore is how long before its expiry a token is renewed
+const tokenRenewBefore = 30 * time.Minute
+
+// activeTokenManager is the token manager of the RancherContext created by CommonBeforeSuite in this process
+var activeTokenManager *TokenManager
+
+// Renew the admin token before every spec, if the suite uses a RancherContext
+var _ = ginkgo.BeforeEach(func() {
+	if activeTokenManager != nil {
+		Expect(activeTokenManager.RenewIfExpiring()).To(Succeed())
+	}
+})
+
+// TokenManager keeps the tokens used by the clients of a RancherContext valid for long-running suites.
+// The clients are refreshed in place, so every copy of the RancherContext and every spec holding a client gets the new token.
+type TokenManager struct {
+	session     *session.Session
+	adminClient *rancher.Client
+	adminToken  *management.Token
+	stdUser     *management.User
+	stdClient   *rancher.Client
+}
+
+// NewTokenManager creates a long-lived admin token from the given admin client and switches the client to it
+func NewTokenManager(adminClient *rancher.Client, testSession *session.Session) (*TokenManager, error) {
+	t := &TokenManager{
+		session:     testSession,
+		adminClient: adminClient,
+	}
+	return t, t.Renew()
+}
+
+// TrackStdUser registers the std user client so that it is refreshed along with the admin client
+func (t *TokenManager) TrackStdUser(user *management.User, client *rancher.Client) {
+	t.stdUser = user
+	t.stdClient = client
+}
+
+// expiresSoon returns true if the admin token expires within tokenRenewBefore; tokens without expiry never expire
+func (t *TokenManager) expiresSoon() bool {
+	if t.adminToken == nil || t.adminToken.Expired {
+		return true
+	}
+	if t.adminToken.ExpiresAt == "" {
+		return false
+	}
+	expiresAt, err := time.Parse(time.RFC3339, t.adminToken.ExpiresAt)
+	if err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Unable to parse token expiry %q: %v", t.adminToken.ExpiresAt, err))
+		return true
+	}
+	return time.Until(expiresAt) < tokenRenewBefore
+}
+
+// RenewIfExpiring renews the tokens if the admin token is about to expire
+func (t *TokenManager) RenewIfExpiring() error {
+	if !t.expiresSoon() {
+		return nil
+	}
+	return t.Renew()
+}
+
+// Renew creates a new admin token, and a new std user token if one is tracked, and refreshes the clients with them
+func (t *TokenManager) Renew() error {
+	token, err := t.adminClient.Management.Token.Create(&management.Token{
+		Description: "hosted-providers-e2e long-lived token",
+	})
+	if err != nil {
+		return err
+	}
+
+	adminClient, err := rancher.NewClient(token.Token, t.session)
+	if err != nil {
+		return err
+	}
+	ProfileClient(adminClient)
+	*t.adminClient = *adminClient
+	t.adminToken = token
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Using admin token %s (expires at: %q)", token.Name, token.ExpiresAt))
+
+	if t.stdUser != nil {
+		stdClient, err := t.adminClient.AsUser(t.stdUser)
+		if err != nil {
+			return err
+		}
+		ProfileClient(stdClient)
+		*t.stdClient = *stdClient
+	}
+	return nil
+}
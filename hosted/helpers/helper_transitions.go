@@ -0,0 +1,324 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterTransition is a change of the state, transitioning or transitioning message of a cluster, or of its nodegroups/nodepools;
+// the Rancher API calls creating or updating the cluster are recorded in the same timeline, with Call set and without state.
+type ClusterTransition struct {
+	Time                 time.Time `json:"time"`
+	ClusterID            string    `json:"clusterID"`
+	ClusterName          string    `json:"clusterName"`
+	State                string    `json:"state,omitempty"`
+	Transitioning        string    `json:"transitioning,omitempty"`
+	TransitioningMessage string    `json:"transitioningMessage,omitempty"`
+	// NodePools are the nodegroups/nodepools of the UpstreamSpec of the cluster
+	NodePools []NodePoolState `json:"nodePools,omitempty"`
+	// Call is the operation creating the cluster (for e.g. provision) or the helper updating it, see UpdateRancherCluster
+	Call string `json:"call,omitempty"`
+	// Step is the By step of the spec the call was made in
+	Step string `json:"step,omitempty"`
+	// Error is the error returned by the call
+	Error string `json:"error,omitempty"`
+}
+
+// NodePoolState is the name, kubernetes version and node count of a nodegroup/nodepool of the UpstreamSpec of a cluster
+type NodePoolState struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Nodes   int64  `json:"nodes"`
+}
+
+func (t ClusterTransition) String() string {
+	if t.Call != "" {
+		return fmt.Sprintf("%s %s %s", t.Time.Format(time.RFC3339), t.ClusterName, t.callString())
+	}
+	return fmt.Sprintf("%s %s %s", t.Time.Format(time.RFC3339), t.ClusterName, t.stateString())
+}
+
+// callString returns the call of the transition, with its By step and error
+func (t ClusterTransition) callString() string {
+	line := "call=" + t.Call
+	if t.Step != "" {
+		line += fmt.Sprintf(" step=%q", t.Step)
+	}
+	if t.Error != "" {
+		line += fmt.Sprintf(" error=%q", t.Error)
+	}
+	return line
+}
+
+// stateString returns the state of the cluster and of its nodegroups/nodepools
+func (t ClusterTransition) stateString() string {
+	line := fmt.Sprintf("state=%s transitioning=%s", t.State, t.Transitioning)
+	if t.TransitioningMessage != "" {
+		line += fmt.Sprintf(" message=%q", t.TransitioningMessage)
+	}
+	for _, np := range t.NodePools {
+		line += fmt.Sprintf(" %s=%s/%d", np.Name, np.Version, np.Nodes)
+	}
+	return line
+}
+
+// sameState returns true if the cluster and its nodegroups/nodepools did not change between the transitions
+func (t ClusterTransition) sameState(other ClusterTransition) bool {
+	return t.State == other.State && t.Transitioning == other.Transitioning && t.TransitioningMessage == other.TransitioningMessage &&
+		slices.Equal(t.NodePools, other.NodePools)
+}
+
+const (
+	// clusterTransitionsEntry is the name of the report entry holding the transitions of the clusters of a failed spec
+	clusterTransitionsEntry = "Cluster transitions"
+	// clusterTimelineEntry is the name of the report entry holding the structured timeline of the clusters of every spec;
+	// it is only part of the JSON report.
+	clusterTimelineEntry = "Cluster timeline"
+	// clusterPollInterval is the interval at which the watched clusters are polled, in addition to the watch events
+	clusterPollInterval = 30 * time.Second
+)
+
+// upstreamNodePoolStates returns the state of every nodegroup/nodepool of the UpstreamSpec of the cluster, whatever its provider
+func upstreamNodePoolStates(cluster *management.Cluster) []NodePoolState {
+	var states []NodePoolState
+	_, upstreamSpec := providerSpecs(cluster)
+	switch spec := upstreamSpec.(type) {
+	case *management.EKSClusterConfigSpec:
+		if spec != nil && spec.NodeGroups != nil {
+			for _, ng := range *spec.NodeGroups {
+				states = append(states, NodePoolState{Name: derefVersion(ng.NodegroupName), Version: derefVersion(ng.Version), Nodes: derefCount(ng.DesiredSize)})
+			}
+		}
+	case *management.GKEClusterConfigSpec:
+		if spec != nil && spec.NodePools != nil {
+			for _, np := range *spec.NodePools {
+				states = append(states, NodePoolState{Name: derefVersion(np.Name), Version: derefVersion(np.Version), Nodes: derefCount(np.InitialNodeCount)})
+			}
+		}
+	case *management.AKSClusterConfigSpec:
+		if spec != nil && spec.NodePools != nil {
+			for _, np := range *spec.NodePools {
+				states = append(states, NodePoolState{Name: derefVersion(np.Name), Version: derefVersion(np.OrchestratorVersion), Nodes: derefCount(np.Count)})
+			}
+		}
+	}
+	return states
+}
+
+// transitionHistory holds the transitions of the clusters created by the helpers during the current spec, by cluster ID;
+// there is only one spec running per process at a time.
+var transitionHistory = struct {
+	sync.Mutex
+	recording bool
+	last      map[string]ClusterTransition
+	timeline  []ClusterTransition
+}{}
+
+// recordClusterTransition appends the transition to the timeline if the state of the cluster changed since its last transition;
+// nothing is recorded for the clusters that are not watched by the current spec unless watch is true.
+func recordClusterTransition(cluster *management.Cluster, watch bool) {
+	transitionHistory.Lock()
+	defer transitionHistory.Unlock()
+
+	last, watched := transitionHistory.last[cluster.ID]
+	if !transitionHistory.recording || (!watched && !watch) {
+		return
+	}
+	transition := ClusterTransition{
+		Time:                 time.Now().UTC(),
+		ClusterID:            cluster.ID,
+		ClusterName:          cluster.Name,
+		State:                cluster.State,
+		Transitioning:        cluster.Transitioning,
+		TransitioningMessage: cluster.TransitioningMessage,
+		NodePools:            upstreamNodePoolStates(cluster),
+	}
+	if watched && last.sameState(transition) {
+		return
+	}
+	transitionHistory.last[cluster.ID] = transition
+	transitionHistory.timeline = append(transitionHistory.timeline, transition)
+}
+
+// ClusterTransitionsOf returns the transitions of a cluster recorded by RecordClusterTransitions since the given time, oldest first;
+// the calls made on the cluster are left out
+func ClusterTransitionsOf(clusterID string, since time.Time) []ClusterTransition {
+	transitionHistory.Lock()
+	defer transitionHistory.Unlock()
+
+	var transitions []ClusterTransition
+	for _, transition := range transitionHistory.timeline {
+		if transition.ClusterID == clusterID && transition.Call == "" && !transition.Time.Before(since) {
+			transitions = append(transitions, transition)
+		}
+	}
+	return transitions
+}
+
+// ObserveCluster records the state of a cluster fetched by the spec, for e.g. while polling it in an Eventually, into the timeline
+// of RecordClusterTransitions; nothing is recorded if the cluster is not watched or did not change since its last transition.
+func ObserveCluster(cluster *management.Cluster) {
+	recordClusterTransition(cluster, false)
+}
+
+// watchClusterTransitions records the operation creating the cluster, for e.g. OperationProvision, and adds the cluster
+// to the clusters whose transitions are recorded by RecordClusterTransitions
+func watchClusterTransitions(operation string, cluster *management.Cluster) {
+	recordClusterCall(cluster, operation, time.Now(), nil)
+}
+
+// RecordClusterTransitions records every change of the State, Transitioning, TransitioningMessage and nodegroups/nodepools of the clusters
+// created or imported by the helpers during the spec, from the watch events and by polling them every clusterPollInterval.
+// The timeline of every spec is written to transitions.json in the spec artifact directory and added to the JSON report; if the spec fails,
+// it is also added to the failure report and written to transitions.txt, so that a timed out assertion shows what the cluster actually went through,
+// along with the lifecycle of the clusters showing the call the failure occurred after (see clusterLifecycle) and the known issues
+// the failure matches (see annotateKnownIssues).
+// It must be called from a BeforeEach node; the recording is stopped by DeferCleanup once the spec is done.
+func RecordClusterTransitions(client *rancher.Client) {
+	transitionHistory.Lock()
+	transitionHistory.recording = true
+	transitionHistory.last = map[string]ClusterTransition{}
+	transitionHistory.timeline = nil
+	transitionHistory.Unlock()
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	var done sync.WaitGroup
+	done.Add(2)
+	go func() {
+		defer done.Done()
+		pollClusterTransitions(watchCtx, client)
+	}()
+	go func() {
+		defer done.Done()
+		for watchCtx.Err() == nil {
+			watchClusterEvents(watchCtx, client)
+
+			// the watch expires after a while or fails if rancher is restarted; start a new one
+			select {
+			case <-watchCtx.Done():
+			case <-time.After(10 * time.Second):
+			}
+		}
+	}()
+
+	ginkgo.DeferCleanup(func() {
+		cancel()
+		done.Wait()
+
+		transitionHistory.Lock()
+		transitionHistory.recording = false
+		timeline := transitionHistory.timeline
+		transitionHistory.Unlock()
+
+		if ginkgo.CurrentSpecReport().Failed() {
+			annotateKnownIssues(ginkgo.CurrentSpecReport(), timeline)
+		}
+		if len(timeline) == 0 {
+			return
+		}
+		ginkgo.AddReportEntry(clusterTimelineEntry, timeline, ginkgo.ReportEntryVisibilityNever)
+		if dir, err := ArtifactDir(ginkgo.CurrentSpecReport()); err == nil {
+			if err = writeClusterTimeline(filepath.Join(dir, "transitions.json"), timeline); err != nil {
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Could not write the cluster timeline: %v", err))
+			}
+		}
+		spec := ginkgo.CurrentSpecReport()
+		if !spec.Failed() {
+			return
+		}
+		if lifecycle := clusterLifecycle(timeline, spec.Failure.TimelineLocation.Time); len(lifecycle) > 0 {
+			ginkgo.AddReportEntry(clusterLifecycleEntry, formatLifecycle(lifecycle), ginkgo.ReportEntryVisibilityFailureOrVerbose)
+		}
+		lines := make([]string, 0, len(timeline))
+		for _, transition := range timeline {
+			lines = append(lines, transition.String())
+		}
+		history := strings.Join(lines, "\n")
+		ginkgo.AddReportEntry(clusterTransitionsEntry, history, ginkgo.ReportEntryVisibilityFailureOrVerbose)
+		if dir, err := ArtifactDir(ginkgo.CurrentSpecReport()); err == nil {
+			_ = os.WriteFile(filepath.Join(dir, "transitions.txt"), []byte(history+"\n"), 0o644)
+		}
+	})
+}
+
+// writeClusterTimeline writes the timeline of the clusters of a spec as JSON
+func writeClusterTimeline(path string, timeline []ClusterTransition) error {
+	content, err := json.MarshalIndent(timeline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0o644)
+}
+
+// pollClusterTransitions fetches the watched clusters every clusterPollInterval until ctx is done, so that the changes missed
+// while the watch is restarted are recorded as well
+func pollClusterTransitions(ctx context.Context, client *rancher.Client) {
+	ticker := time.NewTicker(clusterPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		transitionHistory.Lock()
+		clusterIDs := make([]string, 0, len(transitionHistory.last))
+		for clusterID := range transitionHistory.last {
+			clusterIDs = append(clusterIDs, clusterID)
+		}
+		transitionHistory.Unlock()
+		for _, clusterID := range clusterIDs {
+			if cluster, err := client.Management.Cluster.ByID(clusterID); err == nil {
+				recordClusterTransition(cluster, false)
+			}
+		}
+	}
+}
+
+// watchClusterEvents watches the management clusters until the watch expires or ctx is done,
+// and records the transitions of the watched clusters that are modified
+func watchClusterEvents(ctx context.Context, client *rancher.Client) {
+	watchInterface, err := client.GetManagementWatchInterface(management.ClusterType, metav1.ListOptions{})
+	if err != nil {
+		return
+	}
+	defer watchInterface.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watchInterface.ResultChan():
+			if !ok {
+				return
+			}
+			object, err := meta.Accessor(event.Object)
+			if err != nil {
+				continue
+			}
+			transitionHistory.Lock()
+			_, watched := transitionHistory.last[object.GetName()]
+			transitionHistory.Unlock()
+			if !watched {
+				continue
+			}
+			// the transitioning fields are computed by the Rancher API, they are not part of the watched object
+			if cluster, err := client.Management.Cluster.ByID(object.GetName()); err == nil {
+				recordClusterTransition(cluster, false)
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package helpers
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+func TestRecordClusterTransition(t *testing.T) {
+	transitionHistory.recording = true
+	transitionHistory.last = map[string]ClusterTransition{}
+	transitionHistory.timeline = nil
+	defer func() { transitionHistory.recording = false }()
+
+	cluster := &management.Cluster{Name: "auto-eks-hp-ci-1-abcde", State: "provisioning", Transitioning: "yes"}
+	cluster.ID = "c-abcde"
+	other := &management.Cluster{Name: "other", State: "active"}
+	other.ID = "c-other"
+
+	// the clusters not watched by the spec are ignored
+	recordClusterTransition(other, false)
+	watchClusterTransitions(OperationProvision, cluster)
+	// unchanged
+	recordClusterTransition(cluster, false)
+	cluster.Transitioning, cluster.TransitioningMessage = "error", "InsufficientMaxPods"
+	recordClusterTransition(cluster, false)
+	cluster.State, cluster.Transitioning, cluster.TransitioningMessage = "active", "no", ""
+	recordClusterTransition(cluster, false)
+
+	timeline := ClusterTransitionsOf(cluster.ID, time.Time{})
+	if len(timeline) != 3 {
+		t.Fatalf("got %d transitions, want 3: %v", len(timeline), timeline)
+	}
+	if timeline[1].Transitioning != "error" || timeline[1].TransitioningMessage != "InsufficientMaxPods" || timeline[2].State != "active" {
+		t.Errorf("unexpected timeline: %v", timeline)
+	}
+}
+
+func TestRecordNodePoolTransition(t *testing.T) {
+	transitionHistory.recording = true
+	transitionHistory.last = map[string]ClusterTransition{}
+	transitionHistory.timeline = nil
+	defer func() { transitionHistory.recording = false }()
+
+	name, version, count := "ng-1", "1.30", int64(2)
+	cluster := &management.Cluster{
+		Name:      "auto-eks-hp-ci-1-abcde",
+		State:     "updating",
+		EKSConfig: &management.EKSClusterConfigSpec{},
+		EKSStatus: &management.EKSStatus{UpstreamSpec: &management.EKSClusterConfigSpec{}},
+	}
+	cluster.ID = "c-abcde"
+	nodeGroups := []management.NodeGroup{{NodegroupName: &name, Version: &version, DesiredSize: &count}}
+	cluster.EKSStatus.UpstreamSpec.NodeGroups = &nodeGroups
+
+	watchClusterTransitions(OperationProvision, cluster)
+	ObserveCluster(cluster)
+	// only the nodegroup is scaled
+	count = 3
+	ObserveCluster(cluster)
+
+	timeline := ClusterTransitionsOf(cluster.ID, time.Time{})
+	if len(timeline) != 2 {
+		t.Fatalf("got %d transitions, want 2: %v", len(timeline), timeline)
+	}
+	want := NodePoolState{Name: "ng-1", Version: "1.30", Nodes: 3}
+	if len(timeline[1].NodePools) != 1 || timeline[1].NodePools[0] != want {
+		t.Errorf("got nodegroups %v, want %v", timeline[1].NodePools, want)
+	}
+
+	path := filepath.Join(t.TempDir(), "transitions.json")
+	if err := writeClusterTimeline(path, timeline); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var read []ClusterTransition
+	if err = json.Unmarshal(content, &read); err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != 2 || read[1].NodePools[0] != want || read[0].ClusterID != "c-abcde" {
+		t.Errorf("unexpected timeline read back: %v", read)
+	}
+}
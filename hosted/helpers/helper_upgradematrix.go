@@ -0,0 +1,119 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/blang/semver"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// UpgradeSteps holds the provider specific functions used to upgrade a cluster to a given k8s version
+type UpgradeSteps struct {
+	// ControlPlane upgrades the control plane of the cluster and waits for the upgrade to be done
+	ControlPlane func(cluster *management.Cluster, client *rancher.Client, version string) (*management.Cluster, error)
+	// NodeGroups upgrades all the nodegroups/nodepools of the cluster and waits for the upgrade to be done;
+	// it can be nil if the provider upgrades them along with the control plane
+	NodeGroups func(cluster *management.Cluster, client *rancher.Client, version string) (*management.Cluster, error)
+}
+
+// latestPatchPerMinor returns the highest version of each minor version, sorted in ascending order
+func latestPatchPerMinor(versions []string) ([]string, error) {
+	type parsedVersion struct {
+		raw    string
+		semver semver.Version
+	}
+
+	latest := map[string]parsedVersion{}
+	for _, version := range versions {
+		v, err := semver.ParseTolerant(version)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse version %s: %w", version, err)
+		}
+		minor := fmt.Sprintf("%d.%d", v.Major, v.Minor)
+		if current, ok := latest[minor]; !ok || v.GT(current.semver) {
+			latest[minor] = parsedVersion{raw: version, semver: v}
+		}
+	}
+
+	parsed := make([]parsedVersion, 0, len(latest))
+	for _, v := range latest {
+		parsed = append(parsed, v)
+	}
+	sort.Slice(parsed, func(i, j int) bool {
+		return parsed[i].semver.LT(parsed[j].semver)
+	})
+
+	sorted := make([]string, 0, len(parsed))
+	for _, v := range parsed {
+		sorted = append(sorted, v.raw)
+	}
+	return sorted, nil
+}
+
+// UpgradePaths returns the multi-hop upgrade paths available in the given provider version list (in any order);
+// each path starts on a minor version and upgrades one minor version at a time, for e.g. with hops=2: [1.28.x 1.29.y 1.30.z] (N-2 -> N-1 -> N).
+// Only the latest patch of each minor version is used, and the paths are sorted from the most recent to the oldest one.
+func UpgradePaths(versions []string, hops int) ([][]string, error) {
+	if hops < 1 {
+		return nil, fmt.Errorf("an upgrade path needs at least one hop, got %d", hops)
+	}
+
+	minors, err := latestPatchPerMinor(versions)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths [][]string
+	for start := len(minors) - hops - 1; start >= 0; start-- {
+		path := minors[start : start+hops+1]
+		if !consecutiveMinors(path) {
+			// a minor version is missing from the list, this path cannot be upgraded one minor at a time
+			continue
+		}
+		paths = append(paths, append([]string{}, path...))
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no %d-hop upgrade path available in versions: %v", hops, versions)
+	}
+	return paths, nil
+}
+
+// consecutiveMinors returns true if each version of the list is the next minor version of the previous one
+func consecutiveMinors(versions []string) bool {
+	for i := 1; i < len(versions); i++ {
+		previous, _ := semver.ParseTolerant(versions[i-1])
+		current, _ := semver.ParseTolerant(versions[i])
+		if current.Major != previous.Major || current.Minor != previous.Minor+1 {
+			return false
+		}
+	}
+	return true
+}
+
+// UpgradeAlongPath upgrades the cluster sequentially to each version of the path, skipping the first one which is expected to be the current version;
+// the control plane is upgraded first, then the nodegroups, on each hop.
+// It returns the up-to-date cluster.
+func UpgradeAlongPath(cluster *management.Cluster, client *rancher.Client, path []string, steps UpgradeSteps) (*management.Cluster, error) {
+	var err error
+	for i := 1; i < len(path); i++ {
+		version := path[i]
+		ginkgo.By(fmt.Sprintf("upgrading hop %d/%d: %s -> %s", i, len(path)-1, path[i-1], version), func() {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Upgrading control plane of cluster %s to %s", cluster.Name, version))
+			cluster, err = steps.ControlPlane(cluster, client, version)
+			if err != nil || steps.NodeGroups == nil {
+				return
+			}
+
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Upgrading nodegroups of cluster %s to %s", cluster.Name, version))
+			cluster, err = steps.NodeGroups(cluster, client, version)
+		})
+		if err != nil {
+			return cluster, fmt.Errorf("upgrade to %s failed: %w", version, err)
+		}
+	}
+	return cluster, nil
+}
@@ -0,0 +1,119 @@
+package helpers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// derefVersion returns the version, or an empty string if it is not set
+func derefVersion(version *string) string {
+	if version == nil {
+		return ""
+	}
+	return *version
+}
+
+// upstreamVersions returns the kubernetes version of the control plane and of every nodegroup/nodepool of the UpstreamSpec of the cluster,
+// whatever its provider; the versions are empty while the UpstreamSpec is not populated
+func upstreamVersions(cluster *management.Cluster) (controlPlane string, nodePools []string) {
+	_, upstreamSpec := providerSpecs(cluster)
+	switch spec := upstreamSpec.(type) {
+	case *management.EKSClusterConfigSpec:
+		if spec == nil {
+			return "", nil
+		}
+		if spec.NodeGroups != nil {
+			for _, ng := range *spec.NodeGroups {
+				nodePools = append(nodePools, derefVersion(ng.Version))
+			}
+		}
+		return derefVersion(spec.KubernetesVersion), nodePools
+	case *management.GKEClusterConfigSpec:
+		if spec == nil {
+			return "", nil
+		}
+		if spec.NodePools != nil {
+			for _, np := range *spec.NodePools {
+				nodePools = append(nodePools, derefVersion(np.Version))
+			}
+		}
+		return derefVersion(spec.KubernetesVersion), nodePools
+	case *management.AKSClusterConfigSpec:
+		if spec == nil {
+			return "", nil
+		}
+		if spec.NodePools != nil {
+			for _, np := range *spec.NodePools {
+				nodePools = append(nodePools, derefVersion(np.OrchestratorVersion))
+			}
+		}
+		return derefVersion(spec.KubernetesVersion), nodePools
+	}
+	return "", nil
+}
+
+// upgradeOrderViolated returns true if a nodegroup/nodepool runs the version while the control plane does not run it yet
+func upgradeOrderViolated(controlPlane string, nodePools []string, version string) bool {
+	return controlPlane != version && slices.Contains(nodePools, version)
+}
+
+// upgradeDone returns true if the control plane and all the nodegroups/nodepools run the version
+func upgradeDone(controlPlane string, nodePools []string, version string) bool {
+	if controlPlane != version || len(nodePools) == 0 {
+		return false
+	}
+	for _, nodePool := range nodePools {
+		if nodePool != version {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Wait for the upgrade of the control plane and of the nodegroups/nodepools of a cluster, submitted in a single cluster update, to be done,
+checking all along that the operator upgrades them in order: no nodegroup/nodepool may run the new version before the control plane does.
+It complements the sequential upgrade checks, where the nodegroups/nodepools are only upgraded once the control plane upgrade is done.
+  - @param client Rancher client
+  - @param cluster Downstream cluster whose control plane and nodegroups/nodepools versions were updated at once
+  - @param version Kubernetes version the cluster is upgraded to
+  - @returns The up-to-date cluster; the function will fail through Ginkgo in case of issue
+*/
+func WaitForOrderedUpgrade(client *rancher.Client, cluster *management.Cluster, version string) *management.Cluster {
+	ginkgo.By(fmt.Sprintf("Waiting for the control plane and the nodepools of cluster %s to be upgraded to %s in order", cluster.Name, version), func() {
+		start := time.Now()
+		var controlPlaneUpgraded time.Duration
+		// the version of the nodepools is polled at a fixed interval so that a nodepool upgraded ahead of the control plane is not missed
+		Eventually(func() (bool, error) {
+			current, err := client.Management.Cluster.ByID(cluster.ID)
+			if err != nil {
+				return false, err
+			}
+			cluster = current
+			controlPlane, nodePools := upstreamVersions(cluster)
+			if upgradeOrderViolated(controlPlane, nodePools, version) {
+				return false, StopTrying(fmt.Sprintf("A nodepool of cluster %s was upgraded to %s before the control plane (%s): %s", cluster.Name, version, controlPlane, strings.Join(nodePools, ", ")))
+			}
+			if controlPlane == version && controlPlaneUpgraded == 0 {
+				controlPlaneUpgraded = time.Since(start).Round(time.Second)
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("The control plane of cluster %s was upgraded to %s after %s", cluster.Name, version, controlPlaneUpgraded))
+			}
+			return upgradeDone(controlPlane, nodePools, version), nil
+		}, tools.SetTimeout(60*time.Minute), 10*time.Second).Should(BeTrue(), fmt.Sprintf("Cluster %s was not upgraded to %s", cluster.Name, version))
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("The nodepools of cluster %s were upgraded to %s after %s", cluster.Name, version, time.Since(start).Round(time.Second)))
+
+		Expect(WaitClusterToBeUpgraded(client, cluster.ID)).To(Succeed())
+		var err error
+		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		Expect(err).To(BeNil())
+	})
+	return cluster
+}
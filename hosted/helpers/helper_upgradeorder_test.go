@@ -0,0 +1,49 @@
+package helpers
+
+import (
+	"testing"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+func TestUpgradeOrder(t *testing.T) {
+	for _, tc := range []struct {
+		controlPlane string
+		nodePools    []string
+		violated     bool
+		done         bool
+	}{
+		{controlPlane: "1.29", nodePools: []string{"1.29", "1.29"}},
+		{controlPlane: "1.30", nodePools: []string{"1.29", "1.29"}},
+		{controlPlane: "1.30", nodePools: []string{"1.30", "1.29"}},
+		{controlPlane: "1.29", nodePools: []string{"1.30", "1.29"}, violated: true},
+		{controlPlane: "1.30", nodePools: []string{"1.30", "1.30"}, done: true},
+		{controlPlane: "1.30", nodePools: nil},
+	} {
+		if got := upgradeOrderViolated(tc.controlPlane, tc.nodePools, "1.30"); got != tc.violated {
+			t.Errorf("upgradeOrderViolated(%s, %v) = %t, want %t", tc.controlPlane, tc.nodePools, got, tc.violated)
+		}
+		if got := upgradeDone(tc.controlPlane, tc.nodePools, "1.30"); got != tc.done {
+			t.Errorf("upgradeDone(%s, %v) = %t, want %t", tc.controlPlane, tc.nodePools, got, tc.done)
+		}
+	}
+}
+
+func TestUpstreamVersions(t *testing.T) {
+	version, upgraded := "1.29", "1.30"
+	cluster := &management.Cluster{
+		AKSConfig: &management.AKSClusterConfigSpec{},
+		AKSStatus: &management.AKSStatus{UpstreamSpec: &management.AKSClusterConfigSpec{
+			KubernetesVersion: &upgraded,
+			NodePools:         &[]management.AKSNodePool{{OrchestratorVersion: &version}, {}},
+		}},
+	}
+	controlPlane, nodePools := upstreamVersions(cluster)
+	if controlPlane != upgraded || len(nodePools) != 2 || nodePools[0] != version || nodePools[1] != "" {
+		t.Errorf("upstreamVersions() = %s, %v", controlPlane, nodePools)
+	}
+
+	if controlPlane, nodePools := upstreamVersions(&management.Cluster{EKSConfig: &management.EKSClusterConfigSpec{}}); controlPlane != "" || nodePools != nil {
+		t.Errorf("upstreamVersions() without status = %s, %v", controlPlane, nodePools)
+	}
+}
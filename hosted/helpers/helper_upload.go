@@ -0,0 +1,78 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+
+	"github.com/rancher/hosted-providers-e2e/pkg/extcli"
+)
+
+// Schemes of ARTIFACTS_BUCKET
+const (
+	s3Scheme  = "s3://"
+	gcsScheme = "gs://"
+)
+
+// artifactsBucketURL returns the URL the artifact directory of a spec is uploaded to: <bucket>/<run ID>/<artifact directory name>,
+// so that the artifacts of a run are kept together and apart from the ones of the other runs
+func artifactsBucketURL(bucket, runID, dir string) string {
+	return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(bucket, "/"), sanitizeName(runID), filepath.Base(dir))
+}
+
+// uploadCommand returns the CLI and its arguments copying the directory to the bucket URL: aws for S3 buckets and gcloud for GCS buckets
+func uploadCommand(dir, url string) (*extcli.CLI, []string) {
+	if strings.HasPrefix(url, s3Scheme) {
+		return extcli.AWS, []string{"s3", "sync", "--only-show-errors", dir, url}
+	}
+	return extcli.Gcloud, []string{"storage", "rsync", "--recursive", dir, url}
+}
+
+// checkArtifactsBucket validates ARTIFACTS_BUCKET and that the CLI uploading to it is present
+func checkArtifactsBucket(bucket string) (problems []string) {
+	if bucket == "" {
+		return nil
+	}
+	if !strings.HasPrefix(bucket, s3Scheme) && !strings.HasPrefix(bucket, gcsScheme) {
+		return []string{fmt.Sprintf("ARTIFACTS_BUCKET %q is not valid; an S3 or GCS bucket URL is expected, for e.g. s3://hosted-providers-e2e/artifacts or gs://hosted-providers-e2e", bucket)}
+	}
+	cli, _ := uploadCommand("", bucket)
+	if _, err := exec.LookPath(cli.Name()); err != nil {
+		problems = append(problems, fmt.Sprintf("%s is not installed or not in PATH; it is required to upload the artifacts to %s", cli.Name(), bucket))
+	}
+	return problems
+}
+
+// UploadArtifactsOnFailure uploads the artifact directory of the spec to ARTIFACTS_BUCKET if the spec failed, since the retention of the
+// CI artifacts is often too short to investigate the flaky specs: the operator logs, the records of the external commands, the support bundle,
+// the log of the spec and the kubeconfigs of the downstream clusters which were not deleted. The directory is uploaded to
+// <ARTIFACTS_BUCKET>/<RUN_ID>/<artifact directory name> (see ArtifactDir) with the credentials of the aws or gcloud CLI.
+// It must be called from the ReportAfterEach node of the suites; a failed upload is only logged.
+func UploadArtifactsOnFailure(report ginkgo.SpecReport) {
+	if runConfig.ArtifactsBucket == "" || !report.Failed() {
+		return
+	}
+	dir, err := ArtifactDir(report)
+	if err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to create the artifact directory of the spec: %v", err))
+		return
+	}
+	if _, err = os.Stat(filepath.Join(dir, "spec.log")); os.IsNotExist(err) {
+		if _, err = writeSpecLog(report, dir); err != nil {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to write the spec log: %v", err))
+		}
+	}
+
+	url := artifactsBucketURL(runConfig.ArtifactsBucket, runConfig.RunID, dir)
+	cli, args := uploadCommand(dir, url)
+	if _, err = cli.WithRetries(3, 10*time.Second).Run(args...); err != nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Failed to upload the artifacts of the spec to %s: %v", url, err))
+		return
+	}
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Uploaded the artifacts of the spec to %s", url))
+}
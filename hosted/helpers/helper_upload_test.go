@@ -0,0 +1,32 @@
+package helpers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestArtifactsBucketURL(t *testing.T) {
+	dir := "artifacts/P1Provisioning_should_upgrade_p2"
+	if url := artifactsBucketURL("s3://hosted-providers-e2e/artifacts/", "Run 42", dir); url != "s3://hosted-providers-e2e/artifacts/run-42/P1Provisioning_should_upgrade_p2" {
+		t.Errorf("artifactsBucketURL() = %q", url)
+	}
+
+	cli, args := uploadCommand(dir, "s3://bucket/42/spec")
+	if cli.Name() != "aws" || !reflect.DeepEqual(args, []string{"s3", "sync", "--only-show-errors", dir, "s3://bucket/42/spec"}) {
+		t.Errorf("uploadCommand() to S3 = %s %v", cli.Name(), args)
+	}
+	cli, args = uploadCommand(dir, "gs://bucket/42/spec")
+	if cli.Name() != "gcloud" || !reflect.DeepEqual(args, []string{"storage", "rsync", "--recursive", dir, "gs://bucket/42/spec"}) {
+		t.Errorf("uploadCommand() to GCS = %s %v", cli.Name(), args)
+	}
+}
+
+func TestCheckArtifactsBucket(t *testing.T) {
+	if problems := checkArtifactsBucket(""); problems != nil {
+		t.Errorf("checkArtifactsBucket() without bucket = %v, want none", problems)
+	}
+	if problems := checkArtifactsBucket("hosted-providers-e2e"); len(problems) != 1 || !strings.Contains(problems[0], "ARTIFACTS_BUCKET") {
+		t.Errorf("checkArtifactsBucket() without scheme = %v, want a problem", problems)
+	}
+}
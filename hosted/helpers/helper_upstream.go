@@ -0,0 +1,83 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+const upstreamPollInitialInterval = 5 * time.Second
+
+// extractUpstreamField runs the extractor, recovering from nil dereferences while the status is not populated yet
+func extractUpstreamField(cluster *management.Cluster, extractor func(*management.Cluster) any) (value any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			value, err = nil, fmt.Errorf("field not available yet: %v", r)
+		}
+	}()
+	return extractor(cluster), nil
+}
+
+// WaitForUpstreamField polls the cluster until the value returned by extractor matches expected, for e.g. a field of EKSStatus/GKEStatus/AKSStatus.UpstreamSpec.
+// expected is either a Gomega matcher (for e.g. ContainElements(...)) or a value compared with Equal.
+// The polling interval backs off from 5s, see NewBackoff; on timeout, the error contains the last observed value.
+// It returns the last fetched cluster.
+func WaitForUpstreamField(client *rancher.Client, clusterID string, extractor func(*management.Cluster) any, expected any, timeout time.Duration) (*management.Cluster, error) {
+	return waitForUpstreamField(client, clusterID, extractor, expected, timeout, NewBackoff(upstreamPollInitialInterval))
+}
+
+// waitForUpstreamField is WaitForUpstreamField polling with the given backoff, for e.g. the one of an operation (see NewOperationBackoff);
+// the cluster is read from its watcher, see WatchedCluster, and the wait is aborted once the cluster enters a terminal error state
+func waitForUpstreamField(client *rancher.Client, clusterID string, extractor func(*management.Cluster) any, expected any, timeout time.Duration,
+	backoff *Backoff) (*management.Cluster, error) {
+	matcher, ok := expected.(types.GomegaMatcher)
+	if !ok {
+		if expected == nil {
+			matcher = BeNil()
+		} else {
+			matcher = Equal(expected)
+		}
+	}
+
+	var (
+		cluster      *management.Cluster
+		lastObserved any
+		lastErr      error
+	)
+	deadline := time.Now().Add(timeout)
+	for {
+		cluster, lastErr = WatchedCluster(client, clusterID)
+		var clusterErr *ClusterError
+		if errors.As(lastErr, &clusterErr) {
+			return cluster, lastErr
+		}
+		if lastErr == nil {
+			var value any
+			if value, lastErr = extractUpstreamField(cluster, extractor); lastErr == nil {
+				lastObserved = value
+				var matched bool
+				if matched, lastErr = matcher.Match(value); lastErr == nil && matched {
+					return cluster, nil
+				}
+			}
+		}
+
+		if time.Now().After(deadline) {
+			break
+		}
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Waiting for the upstream field of cluster %s to converge; last observed: %v", clusterID, format.Object(lastObserved, 0)))
+		time.Sleep(backoff.Next())
+	}
+
+	if lastErr != nil {
+		return cluster, fmt.Errorf("timed out after %s waiting for the upstream field of cluster %s: %w", timeout, clusterID, lastErr)
+	}
+	return cluster, fmt.Errorf("timed out after %s waiting for the upstream field of cluster %s: %s", timeout, clusterID, matcher.FailureMessage(lastObserved))
+}
@@ -0,0 +1,100 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/rancher/shepherd/clients/rancher"
+)
+
+// versionCatalog memoizes the version related queries made to Rancher (settings and provider version lists),
+// since they do not change during a run unless Rancher is reinstalled; with VERSION_CATALOG_TTL, they are refreshed once expired,
+// for the long runs during which the cloud providers may release or retire versions.
+var versionCatalog = struct {
+	sync.Mutex
+	entries map[string]catalogEntry
+}{entries: map[string]catalogEntry{}}
+
+// catalogEntry is a cached query of the version catalog, with the time it was fetched at
+type catalogEntry struct {
+	value     interface{}
+	fetchedAt time.Time
+}
+
+// catalogNow returns the current time of the version catalog, replaced by the tests
+var catalogNow = time.Now
+
+// memoize returns the cached value of the key, or fetches and caches it if it is not cached or expired; errors are not cached
+func memoize[T any](key string, fetch func() (T, error)) (T, error) {
+	versionCatalog.Lock()
+	defer versionCatalog.Unlock()
+
+	if entry, ok := versionCatalog.entries[key]; ok {
+		if ttl := runConfig.VersionCatalogTTL; ttl <= 0 || catalogNow().Sub(entry.fetchedAt) < ttl {
+			return entry.value.(T), nil
+		}
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Refreshing %s in the version catalog, fetched more than %s ago", key, runConfig.VersionCatalogTTL))
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+	versionCatalog.entries[key] = catalogEntry{value: value, fetchedAt: catalogNow()}
+	return value, nil
+}
+
+// ResetVersionCatalog drops all the cached version queries; it must be called whenever Rancher is (re)installed or upgraded
+func ResetVersionCatalog() {
+	versionCatalog.Lock()
+	defer versionCatalog.Unlock()
+
+	if len(versionCatalog.entries) > 0 {
+		ginkgo.GinkgoLogr.Info("Resetting the version catalog ...")
+	}
+	versionCatalog.entries = map[string]catalogEntry{}
+}
+
+// settingKey returns the key of the Rancher setting in the version catalog; the settings are cached per client,
+// since the clients of different users may not be allowed to read the same settings
+func settingKey(client *rancher.Client, id string) string {
+	return fmt.Sprintf("%s/%p/setting/%s", client.RancherConfig.Host, client, id)
+}
+
+// cachedSettingValue returns the value of the Rancher setting, fetched once per run and client, for e.g. the UI capabilities
+// queried by the version selection of every spec; the setting is fetched again once it is updated with setRancherSetting
+func cachedSettingValue(client *rancher.Client, id string) (string, error) {
+	return memoize(settingKey(client, id), func() (string, error) {
+		setting, err := client.Management.Setting.ByID(id)
+		if err != nil {
+			return "", err
+		}
+		return setting.Value, nil
+	})
+}
+
+// forgetCachedSetting drops the cached values of the Rancher setting for all the clients, once it is updated
+func forgetCachedSetting(id string) {
+	versionCatalog.Lock()
+	defer versionCatalog.Unlock()
+
+	for key := range versionCatalog.entries {
+		if strings.HasSuffix(key, "/setting/"+id) {
+			delete(versionCatalog.entries, key)
+		}
+	}
+}
+
+// CachedVersions returns the version list identified by the key, fetched once per run;
+// the key must contain every parameter the list depends on, for e.g. the region and the cloud credential.
+// A copy is returned so that callers can modify it.
+func CachedVersions(client *rancher.Client, key string, fetch func() ([]string, error)) ([]string, error) {
+	versions, err := memoize(fmt.Sprintf("%s/versions/%s", client.RancherConfig.Host, key), fetch)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{}, versions...), nil
+}
@@ -0,0 +1,90 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rancher/shepherd/clients/rancher"
+)
+
+func TestMemoizeTTL(t *testing.T) {
+	defer func(ttl time.Duration) { runConfig.VersionCatalogTTL, catalogNow = ttl, time.Now }(runConfig.VersionCatalogTTL)
+	defer ResetVersionCatalog()
+	ResetVersionCatalog()
+
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	catalogNow = func() time.Time { return now }
+	fetches := 0
+	fetch := func() ([]string, error) {
+		fetches++
+		return []string{"1.31", "1.30"}, nil
+	}
+
+	// without TTL, the versions are fetched once
+	runConfig.VersionCatalogTTL = 0
+	for i := 0; i < 2; i++ {
+		now = now.Add(24 * time.Hour)
+		if versions, err := memoize("eks/versions", fetch); err != nil || len(versions) != 2 {
+			t.Fatalf("memoize() = %v, %v", versions, err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("got %d fetches without TTL, want 1", fetches)
+	}
+
+	// with a TTL, they are fetched again once expired
+	runConfig.VersionCatalogTTL = time.Hour
+	_, _ = memoize("eks/versions", fetch)
+	if fetches != 2 {
+		t.Errorf("got %d fetches, want the expired versions to be fetched again", fetches)
+	}
+	now = now.Add(30 * time.Minute)
+	_, _ = memoize("eks/versions", fetch)
+	if fetches != 2 {
+		t.Errorf("got %d fetches, want the versions to be cached for an hour", fetches)
+	}
+
+	// the errors are not cached
+	failing := func() ([]string, error) { return nil, errors.New("rancher is not reachable") }
+	if _, err := memoize("gke/versions", failing); err == nil {
+		t.Fatal("memoize() must return the error of the fetch")
+	}
+	if versions, err := memoize("gke/versions", fetch); err != nil || len(versions) != 2 {
+		t.Errorf("memoize() after an error = %v, %v", versions, err)
+	}
+}
+
+func TestCachedSettingPerClient(t *testing.T) {
+	defer ResetVersionCatalog()
+	ResetVersionCatalog()
+
+	admin := &rancher.Client{RancherConfig: &rancher.Config{Host: "rancher.example.com"}}
+	user := &rancher.Client{RancherConfig: &rancher.Config{Host: "rancher.example.com"}}
+	if settingKey(admin, "server-version") == settingKey(user, "server-version") {
+		t.Fatal("the settings must be cached per client")
+	}
+
+	fetches := 0
+	fetch := func() (string, error) {
+		fetches++
+		return "<=v1.31.x", nil
+	}
+	for _, client := range []*rancher.Client{admin, admin, user} {
+		_, _ = memoize(settingKey(client, "ui-k8s-default-version-range"), fetch)
+		_, _ = memoize(settingKey(client, "server-version"), fetch)
+	}
+	if fetches != 4 {
+		t.Errorf("got %d fetches, want the 2 settings to be fetched once per client", fetches)
+	}
+
+	// an updated setting is fetched again by all the clients, the others stay cached
+	forgetCachedSetting("ui-k8s-default-version-range")
+	for _, client := range []*rancher.Client{admin, user} {
+		_, _ = memoize(settingKey(client, "ui-k8s-default-version-range"), fetch)
+		_, _ = memoize(settingKey(client, "server-version"), fetch)
+	}
+	if fetches != 6 {
+		t.Errorf("got %d fetches, want only the updated setting to be fetched again", fetches)
+	}
+}
@@ -0,0 +1,108 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// VersionSkew is a skew between the k8s minor version of the nodes and the one of the control plane of a cluster, and how Rancher must handle it
+type VersionSkew struct {
+	Description string
+	// NodeMinorOffset is the minor version of the nodes relative to the one of the control plane, for e.g. -1 for nodes one minor behind
+	NodeMinorOffset int
+	// Rejected is true if the skew is not supported by the provider
+	Rejected bool
+	// Message is part of the error reported on the cluster when the skew is rejected; empty if it depends on the cloud provider API
+	Message string
+}
+
+// versionSkews are the skew rules of every provider, as enforced by its operator or its cloud API:
+//   - EKS: the nodegroups must be equal to or one minor version lower than the control plane
+//   - GKE: the nodepools can not be newer than the control plane, and can be up to two minor versions older
+//   - AKS: the nodepools can not be newer than the control plane
+var versionSkews = map[string][]VersionSkew{
+	"eks": {
+		{Description: "nodegroup one minor ahead of the control plane", NodeMinorOffset: 1, Rejected: true, Message: "not compatible"},
+		{Description: "nodegroup one minor behind the control plane", NodeMinorOffset: -1},
+		{Description: "nodegroup two minors behind the control plane", NodeMinorOffset: -2, Rejected: true, Message: "not compatible"},
+	},
+	"gke": {
+		{Description: "nodepool one minor ahead of the control plane", NodeMinorOffset: 1, Rejected: true},
+		{Description: "nodepool one minor behind the control plane", NodeMinorOffset: -1},
+		{Description: "nodepool two minors behind the control plane", NodeMinorOffset: -2},
+	},
+	"aks": {
+		{Description: "nodepool one minor ahead of the control plane", NodeMinorOffset: 1, Rejected: true, Message: "are incompatible"},
+		{Description: "nodepool one minor behind the control plane", NodeMinorOffset: -1},
+	},
+}
+
+// VersionSkews returns the version skew rules of a provider, for e.g. "eks"
+func VersionSkews(provider string) []VersionSkew {
+	return versionSkews[provider]
+}
+
+/*
+Get the versions to reach a version skew: the version to create the cluster with, then the versions the nodes are upgraded to if the nodes
+must be ahead of the control plane, or the versions the control plane is upgraded to, one minor at a time, if the nodes must be behind.
+  - @param versions A version of every minor in descending order, for e.g. from ListSingleVariantAKSAllVersions
+  - @param offset Minor version of the nodes relative to the one of the control plane, see VersionSkew
+  - @returns The version of the cluster and the upgrade steps, or an error if there are not enough minor versions
+*/
+func VersionSkewPath(versions []string, offset int) (base string, steps []string, err error) {
+	distance := offset
+	if distance < 0 {
+		distance = -distance
+	}
+	if distance == 0 || len(versions) <= distance {
+		return "", nil, fmt.Errorf("a skew of %d minor versions needs more than the versions %v", offset, versions)
+	}
+	base = versions[distance]
+	if offset > 0 {
+		return base, []string{versions[0]}, nil
+	}
+	for i := distance - 1; i >= 0; i-- {
+		steps = append(steps, versions[i])
+	}
+	return base, steps, nil
+}
+
+/*
+Check how Rancher handles a version skew once the last upgrade step of VersionSkewPath has been submitted: a rejected skew must be reported
+as an error on the cluster, while a supported one must be applied and the cluster become active.
+  - @param client Rancher client
+  - @param cluster Downstream cluster
+  - @param skew Version skew, for e.g. from VersionSkews
+  - @returns The up-to-date cluster; the function will fail through Ginkgo in case of issue
+*/
+func CheckVersionSkew(client *rancher.Client, cluster *management.Cluster, skew VersionSkew) *management.Cluster {
+	ginkgo.By(fmt.Sprintf("Checking cluster %s with a %s: rejected=%t", cluster.Name, skew.Description, skew.Rejected), func() {
+		var err error
+		if skew.Rejected {
+			// the error of the rejected version is left on the cluster until the next supported update is reconciled
+			AllowClusterErrors()
+			EventuallyWithBackoff(func() (bool, error) {
+				cluster, err = client.Management.Cluster.ByID(cluster.ID)
+				if err != nil {
+					return false, err
+				}
+				return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, skew.Message), nil
+			}, tools.SetTimeout(2*time.Minute), 3*time.Second).Should(BeTrue(), fmt.Sprintf("The %s was not rejected", skew.Description))
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("The %s was rejected: %s", skew.Description, cluster.TransitioningMessage))
+			return
+		}
+
+		Expect(WaitClusterToBeUpgraded(client, cluster.ID)).To(Succeed())
+		cluster, err = WaitUntilClusterIsReady(cluster, client)
+		Expect(err).To(BeNil())
+		Expect(cluster.Transitioning).ToNot(Equal("error"), cluster.TransitioningMessage)
+	})
+	return cluster
+}
@@ -0,0 +1,45 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVersionSkewPath(t *testing.T) {
+	versions := []string{"1.31.1", "1.30.4", "1.29.8"}
+	for _, tc := range []struct {
+		offset  int
+		base    string
+		steps   []string
+		wantErr bool
+	}{
+		{offset: 1, base: "1.30.4", steps: []string{"1.31.1"}},
+		{offset: -1, base: "1.30.4", steps: []string{"1.31.1"}},
+		{offset: -2, base: "1.29.8", steps: []string{"1.30.4", "1.31.1"}},
+		{offset: -3, wantErr: true},
+		{offset: 0, wantErr: true},
+	} {
+		base, steps, err := VersionSkewPath(versions, tc.offset)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("VersionSkewPath(%d) error = %v, want an error: %t", tc.offset, err, tc.wantErr)
+			continue
+		}
+		if base != tc.base || !reflect.DeepEqual(steps, tc.steps) {
+			t.Errorf("VersionSkewPath(%d) = %s, %v, want %s, %v", tc.offset, base, steps, tc.base, tc.steps)
+		}
+	}
+}
+
+func TestVersionSkews(t *testing.T) {
+	for _, provider := range []string{"eks", "gke", "aks"} {
+		skews := VersionSkews(provider)
+		if len(skews) == 0 {
+			t.Errorf("VersionSkews(%s) is empty", provider)
+		}
+		for _, skew := range skews {
+			if skew.NodeMinorOffset > 0 && !skew.Rejected {
+				t.Errorf("VersionSkews(%s): the %s must be rejected, the nodes can not be newer than the control plane", provider, skew.Description)
+			}
+		}
+	}
+}
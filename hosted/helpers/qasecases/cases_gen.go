@@ -0,0 +1,415 @@
+// Code generated by qasegen; DO NOT EDIT.
+
+package qasecases
+
+// AKS > BackupRestore
+const (
+	// AKSBackupRestoreDoFullBackupRestoreTest246 is "Do a full backup/restore test"
+	AKSBackupRestoreDoFullBackupRestoreTest246 CaseID = 246
+	// AKSBackupRestoreDoFullBackupRestoreTest315 is "Do a full backup/restore test"
+	AKSBackupRestoreDoFullBackupRestoreTest315 CaseID = 315
+)
+
+// AKS > K8sChartSupport
+const (
+	// AKSK8sChartSupportTestK8sChartSupportProvisioning is "should successfully test k8s chart support provisioning"
+	AKSK8sChartSupportTestK8sChartSupportProvisioning CaseID = 252
+	// AKSK8sChartSupportTestK8sChartSupportImport is "should successfully test k8s chart support import"
+	AKSK8sChartSupportTestK8sChartSupportImport CaseID = 254
+)
+
+// AKS > K8sChartSupport Upgrade
+const (
+	// AKSK8sChartSupportUpgradeTestK8sChartSupportProvisioningInUpgradeScenario is "should successfully test k8s chart support provisioning in an upgrade scenario"
+	AKSK8sChartSupportUpgradeTestK8sChartSupportProvisioningInUpgradeScenario CaseID = 251
+	// AKSK8sChartSupportUpgradeTestK8sChartSupportImportInUpgradeScenario is "should successfully test k8s chart support import in an upgrade scenario"
+	AKSK8sChartSupportUpgradeTestK8sChartSupportImportInUpgradeScenario CaseID = 253
+)
+
+// AKS > P0
+const (
+	// AKSP0ProvisionClusterAddDeleteScaleNodepool is "should successfully provision the cluster & add, delete, scale nodepool"
+	AKSP0ProvisionClusterAddDeleteScaleNodepool CaseID = 172
+	// AKSP0UpgradeK8sVersionOfCluster175 is "should be able to upgrade k8s version of the cluster"
+	AKSP0UpgradeK8sVersionOfCluster175 CaseID = 175
+	// AKSP0ImportClusterAddDeleteScaleNodepool is "should successfully import the cluster & add, delete, scale nodepool"
+	AKSP0ImportClusterAddDeleteScaleNodepool CaseID = 213
+	// AKSP0UpgradeK8sVersionOfCluster232 is "should be able to upgrade k8s version of the cluster"
+	AKSP0UpgradeK8sVersionOfCluster232 CaseID = 232
+)
+
+// AKS > P1
+const (
+	// AKSP1UpdateAutoscaling176 is "should be able to update autoscaling"
+	AKSP1UpdateAutoscaling176 CaseID = 176
+	// AKSP1UpdateTags177 is "should be able to update tags"
+	AKSP1UpdateTags177 CaseID = 177
+	// AKSP1NotSelectNPK8sVersionCPK8sVersion is "should not be able to select NP K8s version; CP K8s version should take precedence"
+	AKSP1NotSelectNPK8sVersionCPK8sVersion CaseID = 182
+	// AKSP1NPCannotUpgradedK8sVersionGreaterThanCP183 is "NP cannot be upgraded to k8s version greater than CP k8s version"
+	AKSP1NPCannotUpgradedK8sVersionGreaterThanCP183 CaseID = 183
+	// AKSP1FailCreateClusterWith0Nodecount is "should fail to create a cluster with 0 nodecount"
+	AKSP1FailCreateClusterWith0Nodecount CaseID = 186
+	// AKSP1FailCreateClusterWithNilNodepool is "should fail to create a cluster with nil nodepool"
+	AKSP1FailCreateClusterWithNilNodepool CaseID = 187
+	// AKSP1CreateCluster is "should successfully create the cluster"
+	AKSP1CreateCluster CaseID = 189
+	// AKSP1DeleteNodepoolAndAddNewOneWithDifferent190 is "should to able to delete a nodepool and add a new one with different availability zone"
+	AKSP1DeleteNodepoolAndAddNewOneWithDifferent190 CaseID = 190
+	// AKSP1NotRemoveSystemNodepool191 is "should not be able to remove system nodepool"
+	AKSP1NotRemoveSystemNodepool191 CaseID = 191
+	// AKSP1CreateClusterWithMultipleNodepoolsInMultipleAZs is "should successfully create cluster with multiple nodepools in multiple AZs"
+	AKSP1CreateClusterWithMultipleNodepoolsInMultipleAZs CaseID = 193
+	// AKSP1DeleteNodepoolAndAddNewOneWithDifferent194 is "should to able to delete a nodepool and add a new one with different availability zone"
+	AKSP1DeleteNodepoolAndAddNewOneWithDifferent194 CaseID = 194
+	// AKSP1NotEditAvailabilityZoneOfNodepool is "should not be able to edit availability zone of a nodepool"
+	AKSP1NotEditAvailabilityZoneOfNodepool CaseID = 195
+	// AKSP1CreateNPWithAZForRegionWhereAZ is "should Create NP with AZ for region where AZ is not supported"
+	AKSP1CreateNPWithAZForRegionWhereAZ CaseID = 196
+	// AKSP1HaveClusterMonitoringDisabledByDefault is "should have cluster monitoring disabled by default"
+	AKSP1HaveClusterMonitoringDisabledByDefault CaseID = 198
+	// AKSP1CreateClusterWithContainerMonitoringEnabled is "should be able to create cluster with container monitoring enabled"
+	AKSP1CreateClusterWithContainerMonitoringEnabled CaseID = 199
+	// AKSP1UpdateClusterMonitoring200 is "should be able to update cluster monitoring"
+	AKSP1UpdateClusterMonitoring200 CaseID = 200
+	// AKSP1FailChangeSystemNodepoolCount0202 is "should fail to change system nodepool count to 0"
+	AKSP1FailChangeSystemNodepoolCount0202 CaseID = 202
+	// AKSP1FailCreateClusterWithNodepoolMaxPodsPer is "should fail to create cluster with Nodepool Max pods per node 9"
+	AKSP1FailCreateClusterWithNodepoolMaxPodsPer CaseID = 203
+	// AKSP1EditSystemNodePool204 is "should successfully edit System NodePool"
+	AKSP1EditSystemNodePool204 CaseID = 204
+	// AKSP1CreateClusterWithEmptyTag is "should be able to create a cluster with empty tag"
+	AKSP1CreateClusterWithEmptyTag CaseID = 205
+	// AKSP1NotDeleteResourceGroupWhenClusterIsDeleted is "should not delete the resource group when cluster is deleted"
+	AKSP1NotDeleteResourceGroupWhenClusterIsDeleted CaseID = 207
+	// AKSP1CreateClusterWithCustomNodepoolParameters is "should successfully create cluster with custom nodepool parameters"
+	AKSP1CreateClusterWithCustomNodepoolParameters CaseID = 209
+	// AKSP1CreateClusterWithNetworkPolicyCalicoAndPlugin is "create cluster with network policy: calico and plugin: kubenet"
+	AKSP1CreateClusterWithNetworkPolicyCalicoAndPlugin CaseID = 210
+	// AKSP1CreateClusterWithNetworkPolicyCalicoPolicyNetworkPluginAzure is "Create cluster with NetworkPolicy calicoPolicy & Network plugin azure"
+	AKSP1CreateClusterWithNetworkPolicyCalicoPolicyNetworkPluginAzure CaseID = 211
+	// AKSP1CreateClusterWithNetworkPolicyNoneNetworkPluginAzure is "Create cluster with NetworkPolicy none & Network plugin azure"
+	AKSP1CreateClusterWithNetworkPolicyNoneNetworkPluginAzure CaseID = 212
+	// AKSP1Create2ClustersInSameRG is "should successfully create 2 clusters in the same RG"
+	AKSP1Create2ClustersInSameRG CaseID = 214
+	// AKSP1FailCreate2ClustersWithSameNameIn is "should fail to create 2 clusters with same name in 2 different resource groups"
+	AKSP1FailCreate2ClustersWithSameNameIn CaseID = 217
+	// AKSP1DeletingClusterWhileItIsInCreationState is "deleting a cluster while it is in creation state should delete it from rancher and cloud console"
+	AKSP1DeletingClusterWhileItIsInCreationState CaseID = 218
+	// AKSP1RecreatingClusterWhileItIsBeingDeletedRecreate is "recreating a cluster while it is being deleted should recreate the cluster"
+	AKSP1RecreatingClusterWhileItIsBeingDeletedRecreate CaseID = 219
+	// AKSP1UpdateWithNewCloudCredentials221 is "should successfully update with new cloud credentials"
+	AKSP1UpdateWithNewCloudCredentials221 CaseID = 221
+	// AKSP1UpdatingClusterWhileItIsStillProvisioning is "updating a cluster while it is still provisioning"
+	AKSP1UpdatingClusterWhileItIsStillProvisioning CaseID = 222
+	// AKSP1UpdateClusterWhenClusterIsInUpdatingState223 is "should Update a cluster when a cluster is in Updating State"
+	AKSP1UpdateClusterWhenClusterIsInUpdatingState223 CaseID = 223
+	// AKSP1AddNPFromAzureAndThenFromRancher224 is "should successfully Add NP from Azure and then from Rancher"
+	AKSP1AddNPFromAzureAndThenFromRancher224 CaseID = 224
+	// AKSP1ChangeK8sVersionFromAzureChangeCPK8s225 is "should successfully Change k8s version from Azure should change the CP k8s version and list of available version for NPs"
+	AKSP1ChangeK8sVersionFromAzureChangeCPK8s225 CaseID = 225
+	// AKSP1EditModeOfNodepool230 is "should successfully edit mode of the nodepool"
+	AKSP1EditModeOfNodepool230 CaseID = 230
+	// AKSP1SyncChangesFromAzureConsoleBackRancher233 is "should sync changes from Azure console back to Rancher"
+	AKSP1SyncChangesFromAzureConsoleBackRancher233 CaseID = 233
+	// AKSP1FailReimportImportedCluster is "should fail to reimport an imported cluster"
+	AKSP1FailReimportImportedCluster CaseID = 235
+	// AKSP1RegisterClusterWithNoRbac is "should be able to register a cluster with no rbac"
+	AKSP1RegisterClusterWithNoRbac CaseID = 237
+	// AKSP1FailUpdateWithInvalidDeletedCloudCredentialAnd238 is "should fail to update with invalid (deleted) cloud credential and update when the cloud credentials becomes valid"
+	AKSP1FailUpdateWithInvalidDeletedCloudCredentialAnd238 CaseID = 238
+	// AKSP1PossibleReImportDeletedCluster is "should be possible to re-import a deleted cluster"
+	AKSP1PossibleReImportDeletedCluster CaseID = 239
+	// AKSP1CreatePrivateCluster240 is "should successfully Create a private cluster"
+	AKSP1CreatePrivateCluster240 CaseID = 240
+	// AKSP1CreatePrivateCluster241 is "should successfully Create a private cluster"
+	AKSP1CreatePrivateCluster241 CaseID = 241
+	// AKSP1CreatePrivateCluster242 is "should successfully Create a private cluster"
+	AKSP1CreatePrivateCluster242 CaseID = 242
+	// AKSP1UpgradeCluster is "should successfully upgrade the cluster"
+	AKSP1UpgradeCluster CaseID = 260
+	// AKSP1CreateClusterWithUnderscoreInName is "should successfully create cluster with underscore in the name"
+	AKSP1CreateClusterWithUnderscoreInName CaseID = 261
+	// AKSP1UpdateAutoscaling266 is "should be able to update autoscaling"
+	AKSP1UpdateAutoscaling266 CaseID = 266
+	// AKSP1NotRemoveSystemNodepool267 is "should not be able to remove system nodepool"
+	AKSP1NotRemoveSystemNodepool267 CaseID = 267
+	// AKSP1DeleteNodepoolAndAddNewOne is "should to able to delete a nodepool and add a new one"
+	AKSP1DeleteNodepoolAndAddNewOne CaseID = 268
+	// AKSP1NPCannotUpgradedK8sVersionGreaterThanCP269 is "NP cannot be upgraded to k8s version greater than CP k8s version"
+	AKSP1NPCannotUpgradedK8sVersionGreaterThanCP269 CaseID = 269
+	// AKSP1UpdateTags270 is "should be able to update tags"
+	AKSP1UpdateTags270 CaseID = 270
+	// AKSP1UpdateClusterMonitoring271 is "should be able to update cluster monitoring"
+	AKSP1UpdateClusterMonitoring271 CaseID = 271
+	// AKSP1CreateClusterInRegionWithoutAZ is "should successfully Create a cluster in Region without AZ"
+	AKSP1CreateClusterInRegionWithoutAZ CaseID = 275
+	// AKSP1ImportClusterInRegionWithoutAZ is "should successfully Import a cluster in Region without AZ"
+	AKSP1ImportClusterInRegionWithoutAZ CaseID = 276
+	// AKSP1EditSystemNodePool289 is "should successfully edit System NodePool"
+	AKSP1EditSystemNodePool289 CaseID = 289
+	// AKSP1FailChangeSystemNodepoolCount0290 is "should fail to change system nodepool count to 0"
+	AKSP1FailChangeSystemNodepoolCount0290 CaseID = 290
+	// AKSP1EditModeOfNodepool291 is "should successfully edit mode of the nodepool"
+	AKSP1EditModeOfNodepool291 CaseID = 291
+	// AKSP1UpdateWithNewCloudCredentials292 is "should successfully update with new cloud credentials"
+	AKSP1UpdateWithNewCloudCredentials292 CaseID = 292
+	// AKSP1AddNPFromAzureAndThenFromRancher293 is "should successfully Add NP from Azure and then from Rancher"
+	AKSP1AddNPFromAzureAndThenFromRancher293 CaseID = 293
+	// AKSP1ChangeK8sVersionFromAzureChangeCPK8s294 is "should successfully Change k8s version from Azure should change the CP k8s version and list of available version for NPs"
+	AKSP1ChangeK8sVersionFromAzureChangeCPK8s294 CaseID = 294
+	// AKSP1FailUpdateWithInvalidDeletedCloudCredentialAnd299 is "should fail to update with invalid (deleted) cloud credential and update when the cloud credentials becomes valid"
+	AKSP1FailUpdateWithInvalidDeletedCloudCredentialAnd299 CaseID = 299
+	// AKSP1SyncChangesFromAzureConsoleBackRancher302 is "should sync changes from Azure console back to Rancher"
+	AKSP1SyncChangesFromAzureConsoleBackRancher302 CaseID = 302
+	// AKSP1UpdateClusterWhenClusterIsInUpdatingState303 is "should Update a cluster when a cluster is in Updating State"
+	AKSP1UpdateClusterWhenClusterIsInUpdatingState303 CaseID = 303
+)
+
+// AKS > SupportMatrix
+const (
+	// AKSSupportMatrixProvisionCluster is "should successfully provision the cluster"
+	AKSSupportMatrixProvisionCluster CaseID = 249
+	// AKSSupportMatrixImportCluster is "should successfully import the cluster"
+	AKSSupportMatrixImportCluster CaseID = 250
+)
+
+// EKS > BackupRestore
+const (
+	// EKSBackupRestoreDoFullBackupRestoreTest164 is "Do a full backup/restore test"
+	EKSBackupRestoreDoFullBackupRestoreTest164 CaseID = 164
+	// EKSBackupRestoreDoFullBackupRestoreTest314 is "Do a full backup/restore test"
+	EKSBackupRestoreDoFullBackupRestoreTest314 CaseID = 314
+)
+
+// EKS > K8sChartSupport
+const (
+	// EKSK8sChartSupportTestK8sChartSupportImport is "should successfully test k8s chart support import"
+	EKSK8sChartSupportTestK8sChartSupportImport CaseID = 65
+	// EKSK8sChartSupportTestK8sChartSupportProvisioning is "should successfully test k8s chart support provisioning"
+	EKSK8sChartSupportTestK8sChartSupportProvisioning CaseID = 166
+)
+
+// EKS > K8sChartSupport Upgrade
+const (
+	// EKSK8sChartSupportUpgradeTestK8sChartSupportProvisioningInUpgradeScenario is "should successfully test k8s chart support provisioning in an upgrade scenario"
+	EKSK8sChartSupportUpgradeTestK8sChartSupportProvisioningInUpgradeScenario CaseID = 165
+	// EKSK8sChartSupportUpgradeTestK8sChartSupportImportInUpgradeScenario is "should successfully test k8s chart support import in an upgrade scenario"
+	EKSK8sChartSupportUpgradeTestK8sChartSupportImportInUpgradeScenario CaseID = 167
+)
+
+// EKS > P0
+const (
+	// EKSP0ProvisionClusterAddDeleteScaleNodepool71 is "should successfully provision the cluster & add, delete, scale nodepool"
+	EKSP0ProvisionClusterAddDeleteScaleNodepool71 CaseID = 71
+	// EKSP0UpgradeK8sVersionOfImportedCluster is "should be able to upgrade k8s version of the imported cluster"
+	EKSP0UpgradeK8sVersionOfImportedCluster CaseID = 73
+	// EKSP0UpgradeK8sVersionOfProvisionedCluster is "should be able to upgrade k8s version of the provisioned cluster"
+	EKSP0UpgradeK8sVersionOfProvisionedCluster CaseID = 74
+	// EKSP0ProvisionClusterAddDeleteScaleNodepool234 is "should successfully provision the cluster & add, delete, scale nodepool"
+	EKSP0ProvisionClusterAddDeleteScaleNodepool234 CaseID = 234
+)
+
+// EKS > P1
+const (
+	// EKSP1UpdateClusterLoggingTypes77 is "Update cluster logging types"
+	EKSP1UpdateClusterLoggingTypes77 CaseID = 77
+	// EKSP1UpdateTagsAndLabels81 is "Update Tags and Labels"
+	EKSP1UpdateTagsAndLabels81 CaseID = 81
+	// EKSP1AddNodegroupInEKSSyncsRancherUpdateCluster is "Add a nodegroup in EKS -> Syncs to Rancher -> Update cluster, the nodegroup is intact"
+	EKSP1AddNodegroupInEKSSyncsRancherUpdateCluster CaseID = 87
+	// EKSP1UpgradeVersionOfNodeGroupOnly88 is "Upgrade version of node group only"
+	EKSP1UpgradeVersionOfNodeGroupOnly88 CaseID = 88
+	// EKSP1UpdateK8sVersionOfClusterAndAddNode90 is "Update k8s version of cluster and add node groups"
+	EKSP1UpdateK8sVersionOfClusterAndAddNode90 CaseID = 90
+	// EKSP1ImportClusterWithONLYControlPlane is "should successfully Import cluster with ONLY control plane"
+	EKSP1ImportClusterWithONLYControlPlane CaseID = 94
+	// EKSP1AddNodeGroupsControlPlaneOnlyCluster is "Add node groups to the control-plane only cluster"
+	EKSP1AddNodeGroupsControlPlaneOnlyCluster CaseID = 95
+	// EKSP1ReimportClusterRancherFail is "Reimport a cluster to Rancher should fail"
+	EKSP1ReimportClusterRancherFail CaseID = 101
+	// EKSP1FailUpdateBothPublicPrivateAccessAsFalse102 is "Fail to update both Public/Private access as false and invalid values of the access"
+	EKSP1FailUpdateBothPublicPrivateAccessAsFalse102 CaseID = 102
+	// EKSP1FailUpdateBothPublicPrivateAccessAsFalse103 is "Fail to update both Public/Private access as false and invalid values of the access"
+	EKSP1FailUpdateBothPublicPrivateAccessAsFalse103 CaseID = 103
+	// EKSP1UpdateClusterWhileItIsStillInUpdating104 is "should successfully update a cluster while it is still in updating state"
+	EKSP1UpdateClusterWhileItIsStillInUpdating104 CaseID = 104
+	// EKSP1ImportClusterWithAtLeast2Nodegroups is "should successfully Import cluster with at least 2 nodegroups"
+	EKSP1ImportClusterWithAtLeast2Nodegroups CaseID = 105
+	// EKSP1DeleteReImportCluster is "Delete & re-import cluster"
+	EKSP1DeleteReImportCluster CaseID = 106
+	// EKSP1ImportEKSClusterWithSelfManagedNodes is "successfully import EKS cluster with self-managed nodes"
+	EKSP1ImportEKSClusterWithSelfManagedNodes CaseID = 107
+	// EKSP1UpdateCloudCreds109 is "Update the cloud creds"
+	EKSP1UpdateCloudCreds109 CaseID = 109
+	// EKSP1SyncFromAWSConsoleRancher111 is "Sync from AWS console to Rancher"
+	EKSP1SyncFromAWSConsoleRancher111 CaseID = 111
+	// EKSP1SyncFromRancherAWSConsoleAfterSyncFrom112 is "Sync from Rancher to AWS console after a sync from AWS console to Rancher"
+	EKSP1SyncFromRancherAWSConsoleAfterSyncFrom112 CaseID = 112
+	// EKSP1UpgradeK8sVersionOfClusterFromEKSAnd114 is "Upgrade k8s version of cluster from EKS and verify it is synced back to Rancher"
+	EKSP1UpgradeK8sVersionOfClusterFromEKSAnd114 CaseID = 114
+	// EKSP1FailCreateClusterWithOnlySecurityGroups is "Fail to create cluster with only Security groups"
+	EKSP1FailCreateClusterWithOnlySecurityGroups CaseID = 120
+	// EKSP1UpdateK8sVersionOfClusterAndAddNode125 is "Update k8s version of cluster and add node groups"
+	EKSP1UpdateK8sVersionOfClusterAndAddNode125 CaseID = 125
+	// EKSP1UpgradeVersionOfNodeGroupOnly126 is "Upgrade version of node group only"
+	EKSP1UpgradeVersionOfNodeGroupOnly126 CaseID = 126
+	// EKSP1FailCreateClusterWithDifferentK8sVersionsOn is "Fail to create cluster with different k8s versions on control plane and on nodegroup"
+	EKSP1FailCreateClusterWithDifferentK8sVersionsOn CaseID = 127
+	// EKSP1UpdateClusterLoggingTypes128 is "Update cluster logging types"
+	EKSP1UpdateClusterLoggingTypes128 CaseID = 128
+	// EKSP1UpdateTagsAndLabels131 is "Update Tags and Labels"
+	EKSP1UpdateTagsAndLabels131 CaseID = 131
+	// EKSP1FailDeleteAllNodeGroups is "should fail to Delete all Node groups"
+	EKSP1FailDeleteAllNodeGroups CaseID = 134
+	// EKSP1ErrorOutProvisionClusterWhenNodegroupsIsNil is "should error out to provision a cluster when nodegroups is nil"
+	EKSP1ErrorOutProvisionClusterWhenNodegroupsIsNil CaseID = 141
+	// EKSP1FailUpdateBothPublicPrivateAccessAsFalse146 is "Fail to update both Public/Private access as false and invalid values of the access"
+	EKSP1FailUpdateBothPublicPrivateAccessAsFalse146 CaseID = 146
+	// EKSP1FailUpdateBothPublicPrivateAccessAsFalse147 is "Fail to update both Public/Private access as false and invalid values of the access"
+	EKSP1FailUpdateBothPublicPrivateAccessAsFalse147 CaseID = 147
+	// EKSP1UpdateClusterWhileItIsStillInUpdating148 is "should successfully update a cluster while it is still in updating state"
+	EKSP1UpdateClusterWhileItIsStillInUpdating148 CaseID = 148
+	// EKSP1ProvisionEKSWithSecretsEncryptionKMS is "should successfully Provision EKS with secrets encryption (KMS)"
+	EKSP1ProvisionEKSWithSecretsEncryptionKMS CaseID = 149
+	// EKSP1DeployClusterWithPublicPrivAccessThenDisable is "Deploy a cluster with Public/Priv access then disable Public access"
+	EKSP1DeployClusterWithPublicPrivAccessThenDisable CaseID = 151
+	// EKSP1UpdateK8sVersionOfNodeGroupsSequentialSimultaneous is "Update k8s version of node groups - sequential & simultaneous upgrade of multiple node groups"
+	EKSP1UpdateK8sVersionOfNodeGroupsSequentialSimultaneous CaseID = 153
+	// EKSP1UpdateCloudCreds155 is "Update the cloud creds"
+	EKSP1UpdateCloudCreds155 CaseID = 155
+	// EKSP1SyncFromAWSConsoleRancher156 is "Sync from AWS console to Rancher"
+	EKSP1SyncFromAWSConsoleRancher156 CaseID = 156
+	// EKSP1SyncFromRancherAWSConsoleAfterSyncFrom157 is "Sync from Rancher to AWS console after a sync from AWS console to Rancher"
+	EKSP1SyncFromRancherAWSConsoleAfterSyncFrom157 CaseID = 157
+	// EKSP1UpgradeK8sVersionOfClusterFromEKSAnd159 is "Upgrade k8s version of cluster from EKS and verify it is synced back to Rancher"
+	EKSP1UpgradeK8sVersionOfClusterFromEKSAnd159 CaseID = 159
+	// EKSP1FailProvisionClusterWithDuplicateNodegroupNames is "should fail to provision a cluster with duplicate nodegroup names"
+	EKSP1FailProvisionClusterWithDuplicateNodegroupNames CaseID = 255
+	// EKSP1ProvisionEKSFromRancherWithEnabledGPUFeature is "should successfully Provision EKS from Rancher with Enabled GPU feature"
+	EKSP1ProvisionEKSFromRancherWithEnabledGPUFeature CaseID = 274
+)
+
+// EKS > SupportMatrix
+const (
+	// EKSSupportMatrixProvisionCluster is "should successfully provision the cluster"
+	EKSSupportMatrixProvisionCluster CaseID = 69
+	// EKSSupportMatrixImportCluster is "should successfully import the cluster"
+	EKSSupportMatrixImportCluster CaseID = 70
+)
+
+// GKE > BackupRestore
+const (
+	// GKEBackupRestoreDoFullBackupRestoreTest21 is "Do a full backup/restore test"
+	GKEBackupRestoreDoFullBackupRestoreTest21 CaseID = 21
+	// GKEBackupRestoreDoFullBackupRestoreTest308 is "Do a full backup/restore test"
+	GKEBackupRestoreDoFullBackupRestoreTest308 CaseID = 308
+)
+
+// GKE > K8sChartSupport
+const (
+	// GKEK8sChartSupportTestK8sChartSupportProvisioning is "should successfully test k8s chart support provisioning"
+	GKEK8sChartSupportTestK8sChartSupportProvisioning CaseID = 63
+)
+
+// GKE > K8sChartSupport Upgrade
+const (
+	// GKEK8sChartSupportUpgradeTestK8sChartSupportProvisioningInUpgradeScenario is "should successfully test k8s chart support provisioning in an upgrade scenario"
+	GKEK8sChartSupportUpgradeTestK8sChartSupportProvisioningInUpgradeScenario CaseID = 62
+	// GKEK8sChartSupportUpgradeTestK8sChartSupportImportInUpgradeScenario is "should successfully test k8s chart support import in an upgrade scenario"
+	GKEK8sChartSupportUpgradeTestK8sChartSupportImportInUpgradeScenario CaseID = 64
+)
+
+// GKE > P0
+const (
+	// GKEP0ProvisionZonalClusterAddDeleteScaleNodepool is "should successfully provision the zonal cluster & add, delete, scale nodepool"
+	GKEP0ProvisionZonalClusterAddDeleteScaleNodepool CaseID = 8
+	// GKEP0ImportClusterAddDeleteScaleNodepool is "should successfully import the cluster & add, delete, scale nodepool"
+	GKEP0ImportClusterAddDeleteScaleNodepool CaseID = 9
+	// GKEP0UpgradeK8sVersionOfImportedCluster is "should be able to upgrade k8s version of the imported cluster"
+	GKEP0UpgradeK8sVersionOfImportedCluster CaseID = 10
+	// GKEP0UpgradeK8sVersionOfZonalProvisionedCluster is "should be able to upgrade k8s version of the zonal provisioned cluster"
+	GKEP0UpgradeK8sVersionOfZonalProvisionedCluster CaseID = 11
+	// GKEP0ProvisionRegionalClusterAddDeleteScaleNodepool is "should successfully provision the regional cluster & add, delete, scale nodepool"
+	GKEP0ProvisionRegionalClusterAddDeleteScaleNodepool CaseID = 300
+	// GKEP0UpgradeK8sVersionOfRegionalProvisionedCluster is "should be able to upgrade k8s version of the regional provisioned cluster"
+	GKEP0UpgradeK8sVersionOfRegionalProvisionedCluster CaseID = 301
+)
+
+// GKE > P1
+const (
+	// GKEP1UserNotAddClusterWithInvalidGKECreds is "User should not be able to add cluster with invalid GKE creds in Rancher"
+	GKEP1UserNotAddClusterWithInvalidGKECreds CaseID = 2
+	// GKEP1UpdateWithNewCloudCredentials is "should successfully update with new cloud credentials"
+	GKEP1UpdateWithNewCloudCredentials CaseID = 5
+	// GKEP1UserNotAddClusterUsingExpiredGKECreds is "User should not be able to add a cluster using an expired GKE creds"
+	GKEP1UserNotAddClusterUsingExpiredGKECreds CaseID = 6
+	// GKEP1CreateWithPublicEndpoint is "should successfully create with public endpoint"
+	GKEP1CreateWithPublicEndpoint CaseID = 22
+	// GKEP1CreateWithPublicEndpointAndMasterAuthorizedNetworks is "should successfully create with public endpoint and MasterAuthorizedNetworks"
+	GKEP1CreateWithPublicEndpointAndMasterAuthorizedNetworks CaseID = 24
+	// GKEP1DeletingClusterWhileItIsInCreationState is "deleting a cluster while it is in creation state should delete it from rancher and cloud console"
+	GKEP1DeletingClusterWhileItIsInCreationState CaseID = 25
+	// GKEP1RecreatingClusterWhileItIsBeingDeletedRecreate is "recreating a cluster while it is being deleted should recreate the cluster"
+	GKEP1RecreatingClusterWhileItIsBeingDeletedRecreate CaseID = 26
+	// GKEP1FailProvisionClusterNodepoolsIsNil is "should fail to provision a cluster nodepools is nil"
+	GKEP1FailProvisionClusterNodepoolsIsNil CaseID = 27
+	// GKEP1UpdateMutableParameterLoggingServiceAndMonitoringService is "should be able to update mutable parameter loggingService and monitoringService"
+	GKEP1UpdateMutableParameterLoggingServiceAndMonitoringService CaseID = 28
+	// GKEP1UpdateAutoscaling29 is "should be able to update autoscaling"
+	GKEP1UpdateAutoscaling29 CaseID = 29
+	// GKEP1AddWindowsNodepool30 is "should successfully add a windows nodepool"
+	GKEP1AddWindowsNodepool30 CaseID = 30
+	// GKEP1UpdateCombinationMutableParameter31 is "should be able to update combination mutable parameter"
+	GKEP1UpdateCombinationMutableParameter31 CaseID = 31
+	// GKEP1CreateClusterWithCPK8sVersionVXX is "should be able to create a cluster with CP K8s version v-XX-1 and NP K8s version v-XX should use v-XX-1 for both CP and NP"
+	GKEP1CreateClusterWithCPK8sVersionVXX CaseID = 33
+	// GKEP1ForGivenNodePoolWithNonWindowsImageTypeUpdating34 is "for a given NodePool with a non-windows imageType, updating it to a windows imageType should fail"
+	GKEP1ForGivenNodePoolWithNonWindowsImageTypeUpdating34 CaseID = 34
+	// GKEP1UpdateClusterWhileItIsStillInUpdating35 is "should successfully update a cluster while it is still in updating state"
+	GKEP1UpdateClusterWhileItIsStillInUpdating35 CaseID = 35
+	// GKEP1FailProvisionClusterWhenCreatingClusterWithInvalid is "should fail to provision a cluster when creating cluster with invalid name"
+	GKEP1FailProvisionClusterWhenCreatingClusterWithInvalid CaseID = 36
+	// GKEP1FailProvisionClusterWithInvalidNodepoolName is "should fail to provision a cluster with invalid nodepool name"
+	GKEP1FailProvisionClusterWithInvalidNodepoolName CaseID = 37
+	// GKEP1SyncFromGCERancherChangedK8sVersion39 is "should Sync from GCE to Rancher - changed k8s version"
+	GKEP1SyncFromGCERancherChangedK8sVersion39 CaseID = 39
+	// GKEP1SyncFromGCERancherAddDeleteNodepool40 is "should Sync from GCE to Rancher - add/delete nodepool"
+	GKEP1SyncFromGCERancherAddDeleteNodepool40 CaseID = 40
+	// GKEP1FailReimportImportedCluster is "should fail to reimport an imported cluster"
+	GKEP1FailReimportImportedCluster CaseID = 49
+	// GKEP1UpdateMutableParameter is "should be able to update mutable parameter"
+	GKEP1UpdateMutableParameter CaseID = 52
+	// GKEP1UpdateAutoscaling53 is "should be able to update autoscaling"
+	GKEP1UpdateAutoscaling53 CaseID = 53
+	// GKEP1AddWindowsNodepool54 is "should successfully add a windows nodepool"
+	GKEP1AddWindowsNodepool54 CaseID = 54
+	// GKEP1ForGivenNodePoolWithNonWindowsImageTypeUpdating55 is "for a given NodePool with a non-windows imageType, updating it to a windows imageType should fail"
+	GKEP1ForGivenNodePoolWithNonWindowsImageTypeUpdating55 CaseID = 55
+	// GKEP1UpdateCombinationMutableParameter56 is "should be able to update combination mutable parameter"
+	GKEP1UpdateCombinationMutableParameter56 CaseID = 56
+	// GKEP1ReimportDeletedCluster is "should be able to reimport a deleted cluster"
+	GKEP1ReimportDeletedCluster CaseID = 57
+	// GKEP1SyncFromGCERancherChangedK8sVersion58 is "should Sync from GCE to Rancher - changed k8s version"
+	GKEP1SyncFromGCERancherChangedK8sVersion58 CaseID = 58
+	// GKEP1SyncFromGCERancherAddDeleteNodepool61 is "should Sync from GCE to Rancher - add/delete nodepool"
+	GKEP1SyncFromGCERancherAddDeleteNodepool61 CaseID = 61
+	// GKEP1UpdatingClusterAllWindowsNodepoolFail263 is "updating a cluster to all windows nodepool should fail"
+	GKEP1UpdatingClusterAllWindowsNodepoolFail263 CaseID = 263
+	// GKEP1UpdatingClusterAllWindowsNodepoolFail264 is "updating a cluster to all windows nodepool should fail"
+	GKEP1UpdatingClusterAllWindowsNodepoolFail264 CaseID = 264
+	// GKEP1UpdateClusterWhileItIsStillInUpdating265 is "should successfully update a cluster while it is still in updating state"
+	GKEP1UpdateClusterWhileItIsStillInUpdating265 CaseID = 265
+	// GKEP1UserNotImportClusterUsingExpiredGKECreds is "User should not be able to import a cluster using an expired GKE creds"
+	GKEP1UserNotImportClusterUsingExpiredGKECreds CaseID = 305
+	// GKEP1UserNotImportClusterWithInvalidGKECreds is "User should not be able to import cluster with invalid GKE creds in Rancher"
+	GKEP1UserNotImportClusterWithInvalidGKECreds CaseID = 306
+)
+
+// GKE > SupportMatrix
+const (
+	// GKESupportMatrixProvisionCluster is "should successfully provision the cluster"
+	GKESupportMatrixProvisionCluster CaseID = 12
+	// GKESupportMatrixImportCluster is "should successfully import the cluster"
+	GKESupportMatrixImportCluster CaseID = 13
+)
@@ -0,0 +1,9 @@
+// Package qasecases holds the IDs of the test cases of the Qase project, generated by cmd/qasegen from the cases of the project: the specs reference
+// their cases with helpers.QaseLabel(qasecases.<case>) instead of a qase:<ID> label, so that a case retired or mistyped fails to compile instead of
+// reporting the results of the spec to another case. Run make qase-cases to regenerate cases_gen.go once cases are added to Qase.
+package qasecases
+
+//go:generate go run ../../../cmd/qasegen --output cases_gen.go
+
+// CaseID is the ID of a test case of the Qase project
+type CaseID int64
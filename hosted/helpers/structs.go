@@ -2,10 +2,7 @@ package helpers
 
 import (
 	"fmt"
-	"os"
 	"os/user"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/rancher/shepherd/clients/rancher"
@@ -17,44 +14,48 @@ const (
 	CattleSystemNS = "cattle-system"
 )
 
+// The globals below are kept for the existing callers; they all come from the run config, see RunConfig.
 var (
-	RancherPassword   = os.Getenv("RANCHER_PASSWORD")
-	RancherHostname   = os.Getenv("RANCHER_HOSTNAME")
-	Provider          = os.Getenv("PROVIDER")
-	testuser, _       = user.Current()
-	clusterCleanup, _ = strconv.ParseBool(os.Getenv("DOWNSTREAM_CLUSTER_CLEANUP"))
-	ClusterNamePrefix = func() string {
-		if clusterCleanup {
-			return fmt.Sprintf("%s-hp-ci", Provider)
-		} else {
-			return fmt.Sprintf("%s-%s-hp-ci", Provider, testuser.Username)
-		}
-	}()
-	RancherFullVersion        = os.Getenv("RANCHER_VERSION")
-	RancherUpgradeFullVersion = os.Getenv("RANCHER_UPGRADE_VERSION")
-	Kubeconfig                = os.Getenv("KUBECONFIG")
+	RancherPassword           = runConfig.RancherPassword
+	RancherHostname           = runConfig.RancherHostname
+	Provider                  = runConfig.Provider
+	testuser, _               = user.Current()
+	clusterCleanup            = runConfig.ClusterCleanup
+	ClusterNamePrefix         = ProviderClusterNamePrefix(Provider)
+	RancherFullVersion        = runConfig.RancherVersion
+	RancherUpgradeFullVersion = runConfig.RancherUpgradeVersion
+	Kubeconfig                = runConfig.Kubeconfig
 	DownstreamKubeconfig      = func(clusterName string) string {
 		return fmt.Sprintf("%s_KUBECONFIG", clusterName)
 	}
-	K8sUpgradedMinorVersion   = os.Getenv("K8S_UPGRADE_MINOR_VERSION")
-	DownstreamK8sMinorVersion = os.Getenv("DOWNSTREAM_K8S_MINOR_VERSION")
-	IsImport                  = func() bool {
-		if strings.Contains(os.Getenv("CATTLE_TEST_CONFIG"), "import") {
-			return true
-		}
-		return false
-	}()
-	SkipUpgradeTests = func() bool {
-		// Skip upgrade tests since lowest k8s version not available
-		return strings.Contains((RancherFullVersion), "2.8")
-	}()
-	SkipTest = func() bool {
-		// Some features not available on v2.8, v2.9
-		return strings.Contains((RancherFullVersion), "2.8") || strings.Contains((RancherFullVersion), "2.9")
-	}()
-	SkipUpgradeTestsLog = "Skipping upgrade tests since only one minor k8s version is supported by the current rancher version ..."
+	K8sUpgradedMinorVersion   = runConfig.K8sUpgradedMinorVersion
+	DownstreamK8sMinorVersion = runConfig.DownstreamK8sMinorVersion
+	IsImport                  = runConfig.IsImport
+	SkipUpgradeTests          = runConfig.SkipUpgradeTests()
+	SkipTest                  = runConfig.SkipTest()
+	SkipUpgradeTestsLog       = "Skipping upgrade tests since only one minor k8s version is supported by the current rancher version ..."
+	RancherBehindProxy        = runConfig.RancherBehindProxy
+	ProxyHost                 = runConfig.ProxyHost
+	NoProxy                   = runConfig.NoProxy
+	PrivateRegistry           = runConfig.PrivateRegistry
+	RancherCA                 = runConfig.RancherCA
+	RancherHA                 = runConfig.RancherHA
+	RancherInstallBackend     = runConfig.RancherInstallBackend
+	K3SServerIP               = runConfig.K3SServerIP
+	ArtifactsDir              = runConfig.ArtifactsDir
+	ScaleNodePools            = runConfig.ScaleNodePools
+	ScaleNodesPerPool         = int64(runConfig.ScaleNodesPerPool)
+	ChartDowngradeVersions    = runConfig.ChartDowngradeVersions
 )
 
+// ProviderClusterNamePrefix returns the prefix of the cluster names of the provider; ClusterNamePrefix for the current provider
+func ProviderClusterNamePrefix(provider string) string {
+	if clusterCleanup {
+		return fmt.Sprintf("%s-hp-ci", provider)
+	}
+	return fmt.Sprintf("%s-%s-hp-ci", provider, testuser.Username)
+}
+
 type HelmChart struct {
 	Name           string `json:"name"`
 	Chart          string `json:"chart"`
@@ -68,6 +69,9 @@ type RancherContext struct {
 	Session            *session.Session
 	ClusterCleanup     bool
 	CloudCredID        string
+	// CloudCredIDs holds the cloud credentials by provider; it is only set by MultiProviderParallelBeforeSuite
+	CloudCredIDs map[string]string
+	TokenManager *TokenManager
 }
 
 type RancherVersionInfo struct {
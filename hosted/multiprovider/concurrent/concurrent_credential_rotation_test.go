@@ -0,0 +1,78 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package concurrent_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("MultiProviderCredentialRotation", Label("multi-provider"), func() {
+	// the keys of these cloud credentials are rotated, so the clusters do not use the ones shared by the suite
+	var cloudCredIDs map[string]string
+
+	BeforeEach(func() {
+		cloudCredIDs = map[string]string{}
+		for _, provider := range helpers.MultiProviders {
+			cloudCredID, err := helpers.CreateProviderCloudCredentials(ctx.RancherAdminClient, provider)
+			Expect(err).To(BeNil())
+			cloudCredIDs[provider] = cloudCredID
+		}
+		provisionClusters(cloudCredIDs, !helpers.SkipUpgradeTests)
+	})
+	AfterEach(deleteClusters)
+
+	It("should keep managing the clusters of all the providers once their cloud credentials are rotated", func() {
+		By("rotating the keys of the cloud credentials", func() {
+			for _, provider := range helpers.MultiProviders {
+				Expect(helpers.RotateCloudCredential(ctx.RancherAdminClient, provider, cloudCredIDs[provider], helpers.RotatedCredentialProfile)).To(Succeed())
+			}
+		})
+
+		// the operators must sync the clusters with the new keys
+		inParallel("Scaling up the nodepools to 2 nodes", clusters, func(pc *providerCluster) {
+			start := time.Now()
+			var err error
+			pc.cluster, err = pc.hosted.ScaleNodePools(pc.cluster, ctx.RancherAdminClient, 2)
+			Expect(err).To(BeNil())
+			helpers.WaitUntilNodeCount(ctx.RancherAdminClient, pc.cluster, 2*nodePoolCount(pc.cluster), start)
+		})
+
+		if !helpers.SkipUpgradeTests {
+			inParallel("Upgrading the control planes", clusters, func(pc *providerCluster) {
+				upgradeToVersion, err := pc.hosted.K8sVersion(ctx.RancherAdminClient, cloudCredIDs[pc.provider], false)
+				Expect(err).To(BeNil())
+				pc.cluster, err = pc.hosted.UpgradeCluster(pc.cluster, ctx.RancherAdminClient, upgradeToVersion)
+				Expect(err).To(BeNil())
+			})
+		}
+
+		inParallel("Checking the clusters", clusters, func(pc *providerCluster) {
+			helpers.ClusterIsReadyChecks(pc.cluster, ctx.RancherAdminClient, pc.name)
+		})
+
+		// the cloud clusters are deleted by the operators with the new keys before the clusters are removed from Rancher
+		inParallel("Deleting the clusters", clusters, func(pc *providerCluster) {
+			Expect(pc.hosted.DeleteCluster(pc.cluster, ctx.RancherAdminClient)).To(Succeed())
+			helpers.WaitUntilClusterIsRemoved(ctx.RancherAdminClient, pc.cluster.ID)
+			// marking as nil so that AfterEach does not delete it again
+			pc.cluster = nil
+		})
+	})
+})
@@ -0,0 +1,91 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package concurrent_test
+
+import (
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+const (
+	backupResourceName  = "hp-backup"
+	restoreResourceName = "hp-restore"
+)
+
+// the upstream cluster is wiped, so the spec must not run alongside the other ones
+var _ = Describe("MultiProviderDisasterRecovery", Label("multi-provider"), Serial, func() {
+	k := kubectl.New()
+
+	BeforeEach(func() {
+		helpers.ValidateRunConfig(helpers.SuiteMultiProviderBackupRestore)
+		provisionClusters(ctx.CloudCredIDs, false)
+	})
+	AfterEach(deleteClusters)
+
+	It("should recover and keep managing the clusters of all the providers once Rancher is restored from a backup", func() {
+		inParallel("Checking the clusters", clusters, func(pc *providerCluster) {
+			helpers.ClusterIsReadyChecks(pc.cluster, ctx.RancherAdminClient, pc.name)
+		})
+
+		var (
+			mu        sync.Mutex
+			snapshots = map[string]*helpers.ClusterSnapshot{}
+		)
+		inParallel("Recording the cluster states", clusters, func(pc *providerCluster) {
+			snapshot, err := helpers.SnapshotHostedCluster(ctx.RancherAdminClient, pc.cluster.ID)
+			Expect(err).To(BeNil())
+			mu.Lock()
+			defer mu.Unlock()
+			snapshots[pc.provider] = snapshot
+		})
+
+		var backupFile string
+		By("Performing a backup", func() {
+			backupFile = helpers.ExecuteBackup(k, backupResourceName)
+		})
+
+		By("Simulating the loss of the upstream cluster: Wiping Rancher", func() {
+			helpers.WipeRancher(k, helpers.CurrentRunConfig().K3sVersion)
+		})
+
+		helpers.RestoreRancher(k, restoreResourceName, backupFile)
+
+		// the config and the upstream spec of every cluster must be the ones of the backup
+		inParallel("Checking the clusters have been recovered", clusters, func(pc *providerCluster) {
+			pc.cluster = helpers.CheckHostedClusterRecovered(ctx.RancherAdminClient, snapshots[pc.provider])
+			helpers.ClusterIsReadyChecks(pc.cluster, ctx.RancherAdminClient, pc.name)
+		})
+
+		inParallel("Scaling up the nodepools of the recovered clusters to 2 nodes", clusters, func(pc *providerCluster) {
+			start := time.Now()
+			var err error
+			pc.cluster, err = pc.hosted.ScaleNodePools(pc.cluster, ctx.RancherAdminClient, 2)
+			Expect(err).To(BeNil())
+			helpers.WaitUntilNodeCount(ctx.RancherAdminClient, pc.cluster, 2*nodePoolCount(pc.cluster), start)
+		})
+
+		inParallel("Adding a nodepool to the recovered clusters", clusters, func(pc *providerCluster) {
+			var err error
+			pc.cluster, err = pc.hosted.AddNodePool(pc.cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,75 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package concurrent_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("MultiProviderConcurrent", Label("multi-provider"), func() {
+	BeforeEach(func() {
+		provisionClusters(ctx.CloudCredIDs, false)
+	})
+	AfterEach(deleteClusters)
+
+	It("should provision and update the clusters of all the providers at the same time", func() {
+		inParallel("Checking the clusters", clusters, func(pc *providerCluster) {
+			helpers.ClusterIsReadyChecks(pc.cluster, ctx.RancherAdminClient, pc.name)
+		})
+
+		inParallel("Adding a nodepool", clusters, func(pc *providerCluster) {
+			var err error
+			pc.cluster, err = pc.hosted.AddNodePool(pc.cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+		})
+
+		inParallel("Scaling up the nodepools to 2 nodes", clusters, func(pc *providerCluster) {
+			start := time.Now()
+			var err error
+			pc.cluster, err = pc.hosted.ScaleNodePools(pc.cluster, ctx.RancherAdminClient, 2)
+			Expect(err).To(BeNil())
+			helpers.WaitUntilNodeCount(ctx.RancherAdminClient, pc.cluster, 2*nodePoolCount(pc.cluster), start)
+		})
+
+		inParallel("Deleting a nodepool", clusters, func(pc *providerCluster) {
+			var err error
+			pc.cluster, err = pc.hosted.DeleteNodePool(pc.cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+		})
+
+		inParallel("Checking the clusters", clusters, func(pc *providerCluster) {
+			helpers.ClusterIsReadyChecks(pc.cluster, ctx.RancherAdminClient, pc.name)
+		})
+	})
+})
+
+// nodePoolCount returns the number of nodepools in the provider config of the cluster
+func nodePoolCount(cluster *management.Cluster) int {
+	switch {
+	case cluster.EKSConfig != nil:
+		return len(*cluster.EKSConfig.NodeGroups)
+	case cluster.GKEConfig != nil:
+		return len(*cluster.GKEConfig.NodePools)
+	case cluster.AKSConfig != nil:
+		return len(*cluster.AKSConfig.NodePools)
+	}
+	return 0
+}
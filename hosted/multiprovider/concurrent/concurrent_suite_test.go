@@ -0,0 +1,162 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package concurrent_test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	// the helper packages register their providers
+	_ "github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
+	_ "github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
+	_ "github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var (
+	ctx      helpers.RancherContext
+	clusters []*providerCluster
+)
+
+func TestConcurrent(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Concurrent Suite", helpers.SuiteConfig(t))
+}
+
+// The Rancher setup and the cloud credentials of the providers are shared by the parallel processes
+var _ = SynchronizedBeforeSuite(helpers.MultiProviderParallelSynchronizedBeforeSuite, func(setup []byte) {
+	ctx = helpers.MultiProviderParallelBeforeSuite(setup)
+})
+
+var _ = BeforeEach(func() {
+	helpers.CollectSpecArtifacts()
+	helpers.RecordClusterTransitions(ctx.RancherAdminClient)
+
+	// Setting this to nil ensures we do not use the clusters of another test running in parallel with this one.
+	clusters = nil
+	for _, provider := range helpers.MultiProviders {
+		hostedProvider, err := helpers.LookupProvider(provider)
+		Expect(err).To(BeNil())
+		clusters = append(clusters, &providerCluster{
+			provider: provider,
+			hosted:   hostedProvider,
+			name:     helpers.GenerateClusterName(helpers.ProviderClusterNamePrefix(provider)),
+		})
+	}
+})
+
+var _ = JustAfterEach(func() {
+	// Collect the support bundles before AfterEach deletes the clusters
+	for _, pc := range clusters {
+		helpers.CollectSupportBundleOnFailure(pc.name)
+	}
+})
+
+// Delete the clusters leaked by all the processes once they are done
+var _ = SynchronizedAfterSuite(func() {}, helpers.CommonSynchronizedAfterSuite)
+
+var _ = ReportAfterEach(func(report SpecReport) {
+	// Add result in Qase if asked
+	helpers.ReportToQase(report)
+	// Upload the artifacts of the failed spec to ARTIFACTS_BUCKET if set
+	helpers.UploadArtifactsOnFailure(report)
+})
+
+var _ = ReportAfterSuite("Reports", func(report Report) {
+	// Write the JSON, JUnit and summary reports to the artifacts directory
+	helpers.GenerateSuiteReports(report)
+	// Submit the Qase results of all the parallel processes
+	helpers.SubmitQaseResults()
+})
+
+// providerCluster is the cluster of a provider provisioned by the suite; it is managed with the operations of the registered provider,
+// which wait for the cluster to be updated and check its config
+type providerCluster struct {
+	provider string
+	hosted   helpers.HostedProvider
+	name     string
+	cluster  *management.Cluster
+}
+
+// provisionClusters provisions the clusters of all the providers in parallel with the given cloud credentials, by provider;
+// a kubernetes version that can be upgraded is used if forUpgrade is true
+func provisionClusters(cloudCredIDs map[string]string, forUpgrade bool) {
+	inParallel("Provisioning the clusters", clusters, func(pc *providerCluster) {
+		k8sVersion, err := pc.hosted.K8sVersion(ctx.RancherAdminClient, cloudCredIDs[pc.provider], forUpgrade)
+		Expect(err).To(BeNil())
+		GinkgoLogr.Info(fmt.Sprintf("While provisioning, using K8s version %s for cluster %s", k8sVersion, pc.name))
+
+		cluster, err := pc.hosted.CreateCluster(ctx.RancherAdminClient, pc.name, cloudCredIDs[pc.provider], k8sVersion)
+		Expect(err).To(BeNil())
+		pc.cluster = cluster
+		pc.cluster, err = helpers.WaitUntilClusterIsReady(pc.cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+	})
+}
+
+// deleteClusters deletes the clusters of all the providers in parallel, if the cleanup is enabled
+func deleteClusters() {
+	if !ctx.ClusterCleanup {
+		for _, pc := range clusters {
+			fmt.Println("Skipping downstream cluster deletion: ", pc.name)
+		}
+		return
+	}
+	inParallel("Deleting the clusters", clusters, func(pc *providerCluster) {
+		if pc.cluster != nil && pc.cluster.ID != "" {
+			GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", pc.cluster.Name, pc.cluster.ID))
+			Expect(pc.hosted.DeleteCluster(pc.cluster, ctx.RancherAdminClient)).To(Succeed())
+		}
+	})
+}
+
+// inParallel runs the operation on all the clusters at the same time and waits for all of them to complete;
+// a failing operation does not interrupt the others, so that every provider is exercised, and the spec fails once they are all done.
+func inParallel(description string, clusters []*providerCluster, operation func(pc *providerCluster)) {
+	By(fmt.Sprintf("%s on %d providers in parallel", description, len(clusters)), func() {
+		var (
+			wg     sync.WaitGroup
+			mu     sync.Mutex
+			failed []string
+		)
+		for _, pc := range clusters {
+			wg.Add(1)
+			go func(pc *providerCluster) {
+				defer wg.Done()
+				defer GinkgoRecover()
+				defer func() {
+					if r := recover(); r != nil {
+						mu.Lock()
+						failed = append(failed, pc.provider)
+						mu.Unlock()
+						panic(r)
+					}
+				}()
+				operation(pc)
+			}(pc)
+		}
+		wg.Wait()
+
+		sort.Strings(failed)
+		Expect(failed).To(BeEmpty(), fmt.Sprintf("%s failed on %s", description, strings.Join(failed, ", ")))
+	})
+}
@@ -0,0 +1,48 @@
+/*
+Copyright © 2023 - 2024 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scenario_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("Scenario", func() {
+	// The scenarios are read while the specs are built, one spec per scenario; a scenario which is not valid fails the suite
+	scenarios, err := helpers.LoadScenarios()
+	if err != nil {
+		It("should load the scenarios", func() {
+			Expect(err).To(BeNil())
+		})
+		return
+	}
+
+	for _, scenario := range scenarios {
+		It(fmt.Sprintf("should run the %s scenario", scenario.Name), func() {
+			if !scenario.RunsOn(helpers.Provider) {
+				Skip(fmt.Sprintf("Scenario %s does not run on %s", scenario.Name, helpers.Provider))
+			}
+			if scenario.Description != "" {
+				GinkgoLogr.Info(fmt.Sprintf("Scenario %s: %s", scenario.Name, scenario.Description))
+			}
+			run.Scenario = scenario
+			helpers.RunScenario(ctx.RancherAdminClient, run)
+		})
+	}
+})
@@ -28,9 +28,28 @@ var _ = Describe("Provision k3s cluster and Rancher", Label("install"), func() {
 	k := kubectl.New()
 
 	It("Install upstream k3s cluster", func() {
-		By("Installing K3S", func() {
-			helpers.InstallK3S(k, k3sVersion, proxy, proxyHost)
-		})
+		if helpers.IsDockerBackend() {
+			By("Running Rancher Manager in docker", func() {
+				helpers.InstallRancherDocker(rancherHostname, rancherChannel, rancherVersion, rancherHeadVersion)
+			})
+			return
+		}
+
+		if helpers.IsLocalClusterBackend() {
+			By("Creating the local cluster", func() {
+				helpers.InstallLocalCluster(k, k3sVersion)
+			})
+		} else {
+			By("Installing K3S", func() {
+				helpers.InstallK3S(k, k3sVersion, proxy, proxyHost)
+			})
+
+			if helpers.IsRancherHA() {
+				By("Adding K3S server nodes", func() {
+					helpers.AddK3SServerNodes(k, k3sVersion)
+				})
+			}
+		}
 
 		By("Installing CertManager", func() {
 			helpers.InstallCertManager(k, proxy, proxyHost)
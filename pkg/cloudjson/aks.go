@@ -0,0 +1,38 @@
+package cloudjson
+
+// AKSStateDeleting is the provisioning state of an AKS cluster or node pool being deleted
+const AKSStateDeleting = "Deleting"
+
+// AKSCluster is a cluster of az aks show/list --output json
+type AKSCluster struct {
+	Name                     string            `json:"name"`
+	Location                 string            `json:"location"`
+	ResourceGroup            string            `json:"resourceGroup"`
+	ProvisioningState        string            `json:"provisioningState"`
+	PowerState               AKSPowerState     `json:"powerState"`
+	KubernetesVersion        string            `json:"kubernetesVersion"`
+	CurrentKubernetesVersion string            `json:"currentKubernetesVersion"`
+	Tags                     map[string]string `json:"tags"`
+	AgentPoolProfiles        []AKSNodePool     `json:"agentPoolProfiles"`
+}
+
+// AKSNodePool is a node pool of az aks nodepool show/list --output json, or an agent pool profile of an AKSCluster
+type AKSNodePool struct {
+	Name                string            `json:"name"`
+	Mode                string            `json:"mode"`
+	ProvisioningState   string            `json:"provisioningState"`
+	PowerState          AKSPowerState     `json:"powerState"`
+	OrchestratorVersion string            `json:"orchestratorVersion"`
+	VMSize              string            `json:"vmSize"`
+	Count               int64             `json:"count"`
+	EnableAutoScaling   bool              `json:"enableAutoScaling"`
+	MinCount            *int64            `json:"minCount"`
+	MaxCount            *int64            `json:"maxCount"`
+	NodeLabels          map[string]string `json:"nodeLabels"`
+	Tags                map[string]string `json:"tags"`
+}
+
+// AKSPowerState is the power state of an AKS cluster or node pool, Running or Stopped
+type AKSPowerState struct {
+	Code string `json:"code"`
+}
@@ -0,0 +1,33 @@
+// Package cloudjson decodes the JSON printed by the CLIs of the cloud providers into typed values: eksctl get cluster/nodegroup -ojson,
+// gcloud container clusters describe/list --format json and az aks show/nodepool list --output json. Only the fields checked by the
+// tests are modelled, the other ones are ignored, so that a new version of a CLI adding fields does not break the decoding.
+package cloudjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Decode decodes the JSON output of a CLI into a T, for e.g. Decode[[]EKSNodegroup](out)
+func Decode[T any](out string) (T, error) {
+	var value T
+	if err := json.Unmarshal([]byte(strings.TrimSpace(out)), &value); err != nil {
+		return value, fmt.Errorf("failed to decode the output as %T: %w", value, err)
+	}
+	return value, nil
+}
+
+// DecodeOne decodes the JSON list printed by a CLI for a single resource, for e.g. eksctl get cluster --name; it fails if the list is
+// empty or holds more than one item
+func DecodeOne[T any](out string) (*T, error) {
+	items, err := Decode[[]T](out)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) != 1 {
+		var value T
+		return nil, fmt.Errorf("got %d %T in the output, want 1", len(items), value)
+	}
+	return &items[0], nil
+}
@@ -0,0 +1,131 @@
+package cloudjson
+
+import (
+	"reflect"
+	"testing"
+)
+
+// the outputs are trimmed down from the ones of the CLIs, with a field unknown to the models left in each of them
+
+const eksctlGetCluster = `[
+    {
+        "Name": "hp-ci-eks",
+        "Arn": "arn:aws:eks:ap-south-1:123456789012:cluster/hp-ci-eks",
+        "CreatedAt": "2026-10-16T08:00:00Z",
+        "Version": "1.30",
+        "Status": "ACTIVE",
+        "Tags": {"owner": "hosted-providers-qa"},
+        "Logging": {
+            "ClusterLogging": [
+                {"Types": ["api", "audit"], "Enabled": true},
+                {"Types": ["authenticator", "controllerManager", "scheduler"], "Enabled": false}
+            ]
+        },
+        "ResourcesVpcConfig": {"VpcId": "vpc-1", "EndpointPublicAccess": true, "EndpointPrivateAccess": false, "PublicAccessCidrs": ["0.0.0.0/0"]}
+    }
+]`
+
+const eksctlGetNodegroup = `[
+    {"StackName": "", "Cluster": "hp-ci-eks", "Name": "ng1", "Status": "ACTIVE", "MaxSize": 3, "MinSize": 1, "DesiredCapacity": 2,
+     "InstanceType": "t3.large", "ImageID": "AL2023_x86_64_STANDARD", "Version": "1.30", "NodeGroupType": "managed"},
+    {"StackName": "", "Cluster": "hp-ci-eks", "Name": "gpu", "Status": "CREATING", "MaxSize": 1, "MinSize": 1, "DesiredCapacity": 1,
+     "InstanceType": "g4dn.xlarge", "ImageID": "AL2023_x86_64_NVIDIA", "Version": "1.30", "NodeGroupType": "managed"}
+]`
+
+const gcloudClustersList = `[
+  {
+    "name": "hp-ci-gke",
+    "status": "RUNNING",
+    "location": "asia-south2-c",
+    "currentMasterVersion": "1.30.5-gke.1014001",
+    "currentNodeCount": 3,
+    "resourceLabels": {"owner": "hosted-providers-qa"},
+    "nodePools": [
+      {"name": "np1", "status": "RUNNING", "version": "1.30.5-gke.1014001", "initialNodeCount": 3,
+       "config": {"machineType": "n2-standard-2", "diskSizeGb": 100, "imageType": "COS_CONTAINERD"},
+       "autoscaling": {"enabled": true, "minNodeCount": 1, "maxNodeCount": 5}, "locations": ["asia-south2-c"]}
+    ]
+  },
+  {"name": "hp-ci-gke-old", "status": "STOPPING", "location": "asia-south2-c"}
+]`
+
+const azAKSShow = `{
+  "name": "hp-ci-aks",
+  "location": "centralindia",
+  "resourceGroup": "hp-ci-aks",
+  "provisioningState": "Succeeded",
+  "powerState": {"code": "Running"},
+  "kubernetesVersion": "1.30",
+  "currentKubernetesVersion": "1.30.4",
+  "tags": {"owner": "hosted-providers-qa"},
+  "agentPoolProfiles": [
+    {"name": "agentpool", "mode": "System", "provisioningState": "Succeeded", "powerState": {"code": "Running"}, "orchestratorVersion": "1.30.4",
+     "vmSize": "Standard_DS2_v2", "count": 1, "enableAutoScaling": false, "minCount": null, "maxCount": null, "osType": "Linux"}
+  ]
+}`
+
+func TestDecodeEKS(t *testing.T) {
+	cluster, err := DecodeOne[EKSCluster](eksctlGetCluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cluster.Name != "hp-ci-eks" || cluster.Version != "1.30" || cluster.Status != "ACTIVE" || cluster.Tags["owner"] != "hosted-providers-qa" ||
+		!cluster.ResourcesVpcConfig.EndpointPublicAccess {
+		t.Errorf("got cluster %+v", cluster)
+	}
+	if got := cluster.EnabledLoggingTypes(); !reflect.DeepEqual(got, []string{"api", "audit"}) {
+		t.Errorf("got enabled logging types %q, want [api audit]", got)
+	}
+
+	nodegroups, err := Decode[[]EKSNodegroup](eksctlGetNodegroup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodegroups) != 2 || nodegroups[0].DesiredCapacity != 2 || nodegroups[1].ImageID != "AL2023_x86_64_NVIDIA" {
+		t.Errorf("got nodegroups %+v", nodegroups)
+	}
+	if _, err = DecodeOne[EKSNodegroup](eksctlGetNodegroup); err == nil {
+		t.Error("got no error decoding a single nodegroup out of two")
+	}
+}
+
+func TestDecodeGKE(t *testing.T) {
+	clusters, err := Decode[[]GKECluster](gcloudClustersList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 2 || !clusters[0].Usable() || clusters[1].Usable() {
+		t.Fatalf("got clusters %+v", clusters)
+	}
+	want := GKENodePool{
+		Name: "np1", Status: "RUNNING", Version: "1.30.5-gke.1014001", InitialNodeCount: 3,
+		Config:      GKENodeConfig{MachineType: "n2-standard-2", DiskSizeGb: 100, ImageType: "COS_CONTAINERD"},
+		Autoscaling: GKENodeAutoscaler{Enabled: true, MinNodeCount: 1, MaxNodeCount: 5},
+	}
+	if !reflect.DeepEqual(clusters[0].NodePools, []GKENodePool{want}) {
+		t.Errorf("got node pools %+v", clusters[0].NodePools)
+	}
+}
+
+func TestDecodeAKS(t *testing.T) {
+	cluster, err := Decode[AKSCluster](azAKSShow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cluster.ProvisioningState == AKSStateDeleting || cluster.PowerState.Code != "Running" || cluster.Tags["owner"] != "hosted-providers-qa" {
+		t.Errorf("got cluster %+v", cluster)
+	}
+	if len(cluster.AgentPoolProfiles) != 1 || cluster.AgentPoolProfiles[0].VMSize != "Standard_DS2_v2" || cluster.AgentPoolProfiles[0].MinCount != nil {
+		t.Errorf("got node pools %+v", cluster.AgentPoolProfiles)
+	}
+}
+
+func TestDecodeInvalidOutput(t *testing.T) {
+	// for e.g. a table printed instead of JSON
+	if _, err := Decode[[]GKECluster]("NAME  LOCATION  STATUS\nhp-ci-gke  asia-south2-c  RUNNING"); err == nil {
+		t.Error("got no error decoding a table")
+	}
+	if _, err := DecodeOne[EKSCluster]("[]"); err == nil {
+		t.Error("got no error decoding a missing cluster")
+	}
+}
@@ -0,0 +1,59 @@
+package cloudjson
+
+// EKSCluster is a cluster of eksctl get cluster -ojson, the cluster of the EKS API with its field names as keys
+type EKSCluster struct {
+	Name               string
+	Arn                string
+	Status             string
+	Version            string
+	PlatformVersion    string
+	Tags               map[string]string
+	Logging            EKSLogging
+	ResourcesVpcConfig EKSVpcConfig
+}
+
+// EKSLogging is the logging of the control plane of an EKS cluster, the types are grouped by state
+type EKSLogging struct {
+	ClusterLogging []EKSLogSetup
+}
+
+// EKSLogSetup is a group of log types of an EKS cluster which are all enabled or disabled
+type EKSLogSetup struct {
+	Types   []string
+	Enabled bool
+}
+
+// EKSVpcConfig is the network configuration of the control plane of an EKS cluster
+type EKSVpcConfig struct {
+	VpcId                 string
+	SubnetIds             []string
+	SecurityGroupIds      []string
+	EndpointPublicAccess  bool
+	EndpointPrivateAccess bool
+	PublicAccessCidrs     []string
+}
+
+// EnabledLoggingTypes returns the log types enabled on the cluster, for e.g. [api audit]
+func (c *EKSCluster) EnabledLoggingTypes() []string {
+	types := []string{}
+	for _, setup := range c.Logging.ClusterLogging {
+		if setup.Enabled {
+			types = append(types, setup.Types...)
+		}
+	}
+	return types
+}
+
+// EKSNodegroup is a nodegroup of eksctl get nodegroup -ojson
+type EKSNodegroup struct {
+	Cluster         string
+	Name            string
+	Status          string
+	MinSize         int64
+	MaxSize         int64
+	DesiredCapacity int64
+	InstanceType    string
+	ImageID         string
+	Version         string
+	NodeGroupType   string
+}
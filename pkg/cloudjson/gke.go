@@ -0,0 +1,49 @@
+package cloudjson
+
+// The states of a GKE cluster or node pool in which it is or will be usable
+const (
+	GKEStatusProvisioning = "PROVISIONING"
+	GKEStatusRunning      = "RUNNING"
+)
+
+// GKECluster is a cluster of gcloud container clusters describe/list --format json
+type GKECluster struct {
+	Name                 string            `json:"name"`
+	Status               string            `json:"status"`
+	Location             string            `json:"location"`
+	CurrentMasterVersion string            `json:"currentMasterVersion"`
+	CurrentNodeVersion   string            `json:"currentNodeVersion"`
+	CurrentNodeCount     int64             `json:"currentNodeCount"`
+	ResourceLabels       map[string]string `json:"resourceLabels"`
+	NodePools            []GKENodePool     `json:"nodePools"`
+}
+
+// GKENodePool is a node pool of gcloud container node-pools describe/list --format json, or of a GKECluster
+type GKENodePool struct {
+	Name             string            `json:"name"`
+	Status           string            `json:"status"`
+	Version          string            `json:"version"`
+	InitialNodeCount int64             `json:"initialNodeCount"`
+	Config           GKENodeConfig     `json:"config"`
+	Autoscaling      GKENodeAutoscaler `json:"autoscaling"`
+}
+
+// GKENodeConfig is the configuration of the nodes of a GKE node pool
+type GKENodeConfig struct {
+	MachineType string            `json:"machineType"`
+	DiskSizeGb  int64             `json:"diskSizeGb"`
+	ImageType   string            `json:"imageType"`
+	Labels      map[string]string `json:"labels"`
+}
+
+// GKENodeAutoscaler is the autoscaling of a GKE node pool
+type GKENodeAutoscaler struct {
+	Enabled      bool  `json:"enabled"`
+	MinNodeCount int64 `json:"minNodeCount"`
+	MaxNodeCount int64 `json:"maxNodeCount"`
+}
+
+// Usable returns true if the cluster is running or being provisioned
+func (c *GKECluster) Usable() bool {
+	return c.Status == GKEStatusRunning || c.Status == GKEStatusProvisioning
+}
@@ -0,0 +1,233 @@
+// Package extcli runs the external command line tools used by the tests (eksctl, gcloud, az, aws, helm, kubectl, ...)
+// with the same behaviour everywhere: no shell is involved, the environment can be extended, every attempt has a timeout,
+// failed commands can be retried, stdout and stderr are captured separately and every invocation is logged.
+package extcli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout is the timeout of a single attempt; it is long enough for the slowest operations, for e.g. creating an EKS cluster
+const DefaultTimeout = 60 * time.Minute
+
+// CLI is an external command line tool; its With* methods return a modified copy so that the shared CLIs are never altered
+type CLI struct {
+	name       string
+	env        []string
+	stdin      []byte
+	stdout     io.Writer
+	timeout    time.Duration
+	retries    int
+	retryDelay time.Duration
+	sensitive  bool
+}
+
+// The CLIs used by the helpers
+var (
+	AWS     = New("aws")
+	Az      = New("az")
+	Docker  = New("docker")
+	Eksctl  = New("eksctl")
+	Gcloud  = New("gcloud")
+	Helm    = New("helm")
+	Kubectl = New("kubectl")
+	// K3d and Kind are only used with RANCHER_INSTALL_BACKEND=k3d or kind
+	K3d  = New("k3d")
+	Kind = New("kind")
+	// Terraform is only used with PROVISIONING_BACKEND=terraform
+	Terraform = New("terraform")
+)
+
+// New returns the CLI running the given binary, looked up in PATH
+func New(name string) *CLI {
+	return &CLI{name: name, timeout: DefaultTimeout}
+}
+
+// Name returns the binary run by the CLI
+func (c *CLI) Name() string {
+	return c.name
+}
+
+// WithEnv returns a copy of the CLI adding the given KEY=value variables to the environment of the current process
+func (c *CLI) WithEnv(env ...string) *CLI {
+	cli := *c
+	cli.env = append(append([]string{}, c.env...), env...)
+	return &cli
+}
+
+// WithStdin returns a copy of the CLI writing the given content to the standard input of the command
+func (c *CLI) WithStdin(stdin []byte) *CLI {
+	cli := *c
+	cli.stdin = stdin
+	return &cli
+}
+
+// WithStdout returns a copy of the CLI writing the standard output of the command to w as it comes instead of capturing it,
+// for e.g. followed logs; the stdout of the result is then empty
+func (c *CLI) WithStdout(w io.Writer) *CLI {
+	cli := *c
+	cli.stdout = w
+	return &cli
+}
+
+// WithTimeout returns a copy of the CLI with the given timeout for each attempt
+func (c *CLI) WithTimeout(timeout time.Duration) *CLI {
+	cli := *c
+	cli.timeout = timeout
+	return &cli
+}
+
+// WithRetries returns a copy of the CLI retrying a failed command up to retries times, waiting delay between the attempts
+func (c *CLI) WithRetries(retries int, delay time.Duration) *CLI {
+	cli := *c
+	cli.retries = retries
+	cli.retryDelay = delay
+	return &cli
+}
+
+// Sensitive returns a copy of the CLI whose arguments (the first one aside) and stdout are redacted from the logs, the records and the errors,
+// for e.g. the commands minting credentials; the stdout is still returned to the caller
+func (c *CLI) Sensitive() *CLI {
+	cli := *c
+	cli.sensitive = true
+	return &cli
+}
+
+// Result is the outcome of a command; for a retried command, it is the outcome of the last attempt
+type Result struct {
+	Name     string
+	Args     []string
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	Duration time.Duration
+	Attempts int
+}
+
+// String returns the command line of the result
+func (r *Result) String() string {
+	return strings.TrimSpace(r.Name + " " + strings.Join(r.Args, " "))
+}
+
+// Error is returned when a command fails, times out or can not be started
+type Error struct {
+	Result *Result
+	Err    error
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s failed after %d attempt(s): %v", e.Result, e.Result.Attempts, e.Err)
+	if stderr := strings.TrimSpace(e.Result.Stderr); stderr != "" {
+		msg += ": " + stderr
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Run runs the command with the given arguments and returns its result; the result is returned even if the command failed
+func (c *CLI) Run(args ...string) (*Result, error) {
+	return c.RunContext(context.Background(), args...)
+}
+
+// RunContext is Run with a context; the command is killed and not retried anymore when the context is done
+func (c *CLI) RunContext(ctx context.Context, args ...string) (*Result, error) {
+	logf("Running command: %s %v", c.name, c.redactedArgs(args))
+
+	var (
+		result *Result
+		err    error
+	)
+	for attempt := 1; ; attempt++ {
+		result, err = c.runOnce(ctx, args)
+		result.Attempts = attempt
+		logResult(c.redacted(result), err)
+		if err == nil || attempt > c.retries || ctx.Err() != nil {
+			break
+		}
+		logf("Command %s failed (attempt %d/%d), retrying in %s: %v", c.redacted(result), attempt, c.retries+1, c.retryDelay, err)
+		select {
+		case <-ctx.Done():
+		case <-time.After(c.retryDelay):
+		}
+	}
+
+	if err != nil {
+		redacted := c.redacted(result)
+		redacted.Stdout = result.Stdout
+		return result, &Error{Result: redacted, Err: err}
+	}
+	return result, nil
+}
+
+// redactedArgs returns the arguments as they are logged: only the first one for the sensitive CLIs, for e.g. the subcommand
+func (c *CLI) redactedArgs(args []string) []string {
+	if !c.sensitive || len(args) == 0 {
+		return args
+	}
+	return []string{args[0], "[redacted]"}
+}
+
+// redacted returns the result as it is logged, without the arguments and the stdout of the sensitive CLIs
+func (c *CLI) redacted(result *Result) *Result {
+	if !c.sensitive {
+		return result
+	}
+	redacted := *result
+	redacted.Args = c.redactedArgs(result.Args)
+	redacted.Stdout = "[redacted]"
+	return &redacted
+}
+
+// Output runs the command and returns its trimmed stdout
+func (c *CLI) Output(args ...string) (string, error) {
+	result, err := c.Run(args...)
+	return strings.TrimSpace(result.Stdout), err
+}
+
+func (c *CLI) runOnce(ctx context.Context, args []string) (*Result, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.name, args...)
+	if len(c.env) > 0 {
+		cmd.Env = append(os.Environ(), c.env...)
+	}
+	if c.stdin != nil {
+		cmd.Stdin = bytes.NewReader(c.stdin)
+	}
+	cmd.Stdout = &stdout
+	if c.stdout != nil {
+		cmd.Stdout = c.stdout
+	}
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	result := &Result{
+		Name:     c.name,
+		Args:     args,
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		ExitCode: cmd.ProcessState.ExitCode(),
+		Duration: time.Since(start),
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s: %w", c.timeout, ctx.Err())
+	}
+	return result, err
+}
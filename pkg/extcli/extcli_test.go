@@ -0,0 +1,151 @@
+package extcli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func init() {
+	Logf = nil
+}
+
+func TestOutputSeparatesStdoutAndStderr(t *testing.T) {
+	out, err := New("sh").Output("-c", "echo out; echo err >&2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "out" {
+		t.Errorf("got %q, want %q", out, "out")
+	}
+}
+
+func TestRunPassesArgumentsAsIs(t *testing.T) {
+	arg := "$(id) `id` ; | & > < 'quoted' \"double\""
+	out, err := New("printf").Output("%s", arg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != arg {
+		t.Errorf("got %q, want %q", out, arg)
+	}
+}
+
+func TestWithEnvAndStdin(t *testing.T) {
+	base := New("sh")
+	cli := base.WithEnv("EXTCLI_TEST=injected").WithStdin([]byte("from stdin"))
+	out, err := cli.Output("-c", `echo "$EXTCLI_TEST"; cat`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "injected\nfrom stdin" {
+		t.Errorf("got %q", out)
+	}
+
+	// the base CLI must not be modified
+	if out, _ := base.Output("-c", `echo "$EXTCLI_TEST"`); out != "" {
+		t.Errorf("env leaked to the base CLI: %q", out)
+	}
+}
+
+func TestWithStdout(t *testing.T) {
+	var stdout strings.Builder
+	result, err := New("sh").WithStdout(&stdout).Run("-c", "echo line1; echo line2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout.String() != "line1\nline2\n" || result.Stdout != "" {
+		t.Errorf("got %q written, %q captured", stdout.String(), result.Stdout)
+	}
+}
+
+func TestErrorContainsStderrAndExitCode(t *testing.T) {
+	result, err := New("sh").Run("-c", "echo boom >&2; exit 3")
+	var cliErr *Error
+	if !errors.As(err, &cliErr) {
+		t.Fatalf("expected an *Error, got %v", err)
+	}
+	if result.ExitCode != 3 {
+		t.Errorf("got exit code %d, want 3", result.ExitCode)
+	}
+	if !strings.Contains(err.Error(), "boom") {
+		t.Errorf("error %q does not contain the stderr of the command", err)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	start := time.Now()
+	_, err := New("sleep").WithTimeout(100 * time.Millisecond).Run("10")
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("the command was not killed on timeout")
+	}
+}
+
+func TestRetries(t *testing.T) {
+	// the command succeeds on the third attempt
+	counter := filepath.Join(t.TempDir(), "counter")
+	script := `echo x >> "$0"; [ "$(wc -l < "$0")" -ge 3 ]`
+	result, err := New("sh").WithRetries(3, 10*time.Millisecond).Run("-c", script, counter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Attempts != 3 {
+		t.Errorf("got %d attempts, want 3", result.Attempts)
+	}
+
+	// no more attempts than allowed
+	_ = os.Remove(counter)
+	result, err = New("sh").WithRetries(1, 10*time.Millisecond).Run("-c", script, counter)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if result.Attempts != 2 {
+		t.Errorf("got %d attempts, want 2", result.Attempts)
+	}
+}
+
+func TestLogDir(t *testing.T) {
+	dir := t.TempDir()
+	SetLogDir(dir)
+	defer SetLogDir("")
+
+	if _, err := New("echo").Run("logged"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "extcli-"+strconv.Itoa(os.Getpid())+".log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "echo logged") || !strings.Contains(string(content), "exit code: 0") {
+		t.Errorf("unexpected log content: %s", content)
+	}
+}
+
+func TestSensitive(t *testing.T) {
+	dir := t.TempDir()
+	SetLogDir(dir)
+	defer SetLogDir("")
+
+	out, err := New("echo").Sensitive().Output("-n", "secret-key")
+	if err != nil || out != "secret-key" {
+		t.Fatalf("got %q, %v", out, err)
+	}
+	_, err = New("sh").Sensitive().Run("-c", "echo secret-key; exit 3")
+	if err == nil || strings.Contains(err.Error(), "secret-key") {
+		t.Errorf("the error must not contain the arguments: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "extcli-"+strconv.Itoa(os.Getpid())+".log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "secret-key") || !strings.Contains(string(content), "echo -n [redacted]") {
+		t.Errorf("the record is not redacted: %s", content)
+	}
+}
@@ -0,0 +1,60 @@
+package extcli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var (
+	// Logf prints the commands being run and their retries; it defaults to stdout
+	Logf = func(format string, args ...any) {
+		fmt.Printf(format+"\n", args...)
+	}
+
+	logDir   string
+	logMutex sync.Mutex
+)
+
+func logf(format string, args ...any) {
+	if Logf != nil {
+		Logf(format, args...)
+	}
+}
+
+// SetLogDir sets the directory where the full record (command, duration, exit code, stdout and stderr) of every attempt is appended;
+// records are written to extcli-<pid>.log so that parallel processes do not share a file. An empty dir disables the records.
+func SetLogDir(dir string) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	logDir = dir
+}
+
+// logResult appends the record of an attempt to the log file; failing to write it must not fail the command
+func logResult(result *Result, err error) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	if logDir == "" {
+		return
+	}
+
+	if mkErr := os.MkdirAll(logDir, 0o755); mkErr != nil {
+		logf("Failed to create the command log dir: %v", mkErr)
+		return
+	}
+	f, openErr := os.OpenFile(filepath.Join(logDir, fmt.Sprintf("extcli-%d.log", os.Getpid())), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if openErr != nil {
+		logf("Failed to open the command log: %v", openErr)
+		return
+	}
+	defer f.Close()
+
+	status := "ok"
+	if err != nil {
+		status = err.Error()
+	}
+	fmt.Fprintf(f, "=== %s %s\nattempt: %d, duration: %s, exit code: %d, status: %s\n--- stdout\n%s\n--- stderr\n%s\n",
+		time.Now().UTC().Format(time.RFC3339), result, result.Attempts, result.Duration.Round(time.Millisecond), result.ExitCode, status, result.Stdout, result.Stderr)
+}
@@ -0,0 +1,280 @@
+// Package helmsdk adds the helm repositories and installs, upgrades and uninstalls the helm releases with the helm Go SDK instead of the helm binary:
+// the values are given as maps, the errors tell the operation and the release or repository they come from, and no helm binary is needed.
+// The settings are read from the same environment as the helm binary (HELM_*, KUBECONFIG), so that the repositories are shared with it.
+package helmsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"helm.sh/helm/v3/pkg/strvals"
+)
+
+// DefaultTimeout is the timeout of the waits of an install or upgrade, the default of the helm binary
+const DefaultTimeout = 5 * time.Minute
+
+// repoLockTimeout is how long AddRepo waits for the other processes adding a repository
+const repoLockTimeout = 30 * time.Second
+
+// Error is the error of a helm operation on a release or a repository
+type Error struct {
+	// Op is the operation, for e.g. install, upgrade, uninstall or repo add
+	Op string
+	// Name is the name of the release or of the repository
+	Name string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("helm %s %s: %v", e.Op, e.Name, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// IsReleaseNotFound returns true if the error is about a release which is not installed, for e.g. when uninstalling it
+func IsReleaseNotFound(err error) bool {
+	return errors.Is(err, driver.ErrReleaseNotFound)
+}
+
+// Release is a release to install or upgrade with InstallOrUpgrade, along with the helm flags it is installed with
+type Release struct {
+	Name      string
+	Namespace string
+	// Chart is the chart reference, for e.g. rancher-latest/rancher, or the path of a chart archive, for e.g. from the chart cache
+	Chart string
+	// Version is the version or version constraint of the chart; its newest stable version if empty, or its newest version with Devel
+	Version string
+	// Devel allows the development versions of the chart, for e.g. the release candidates, like helm --devel
+	Devel bool
+	// Values are the values of the release, see SetValues
+	Values          map[string]any
+	CreateNamespace bool
+	Wait            bool
+	WaitForJobs     bool
+	// Timeout is the timeout of the waits, DefaultTimeout if zero
+	Timeout time.Duration
+}
+
+// Client runs the helm operations; its zero value is ready to use
+type Client struct {
+	// Logf prints the progress of the operations, for e.g. the resources being waited for; nothing is printed if nil
+	Logf func(format string, args ...any)
+}
+
+// New returns a client printing the progress of the operations with logf
+func New(logf func(format string, args ...any)) *Client {
+	return &Client{Logf: logf}
+}
+
+func (c *Client) logf(format string, args ...any) {
+	if c.Logf != nil {
+		c.Logf(format, args...)
+	}
+}
+
+// settings returns the helm settings of the environment for the namespace
+func (c *Client) settings(namespace string) *cli.EnvSettings {
+	settings := cli.New()
+	if namespace != "" {
+		settings.SetNamespace(namespace)
+	}
+	return settings
+}
+
+// configuration returns the configuration of the actions on the releases of the namespace, in the cluster of KUBECONFIG
+func (c *Client) configuration(settings *cli.EnvSettings) (*action.Configuration, error) {
+	config := &action.Configuration{}
+	if err := config.Init(settings.RESTClientGetter(), settings.Namespace(), os.Getenv("HELM_DRIVER"), c.logf); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// RepositoryCache returns the directory the indexes of the repositories are kept in, HELM_REPOSITORY_CACHE
+func (c *Client) RepositoryCache() string {
+	return c.settings("").RepositoryCache
+}
+
+// Repositories returns the repositories added to HELM_REPOSITORY_CONFIG; none if no repository is added yet
+func (c *Client) Repositories() ([]*repo.Entry, error) {
+	file, err := repo.LoadFile(c.settings("").RepositoryConfig)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, &Error{Op: "repo list", Err: err}
+	}
+	return file.Repositories, nil
+}
+
+// AddRepo adds the helm repository and fetches its index, or replaces it if it was added with another URL, like helm repo add --force-update;
+// the repository file is locked like the helm binary does, so that the parallel processes can add repositories at the same time.
+func (c *Client) AddRepo(name, url string) error {
+	if err := c.addRepo(name, url); err != nil {
+		return &Error{Op: "repo add", Name: name, Err: err}
+	}
+	return nil
+}
+
+func (c *Client) addRepo(name, url string) error {
+	if strings.Contains(name, "/") {
+		return fmt.Errorf("the repository name contains '/'")
+	}
+	settings := c.settings("")
+	if err := os.MkdirAll(filepath.Dir(settings.RepositoryConfig), 0o755); err != nil {
+		return err
+	}
+	lock := flock.New(strings.TrimSuffix(settings.RepositoryConfig, filepath.Ext(settings.RepositoryConfig)) + ".lock")
+	ctx, cancel := context.WithTimeout(context.Background(), repoLockTimeout)
+	defer cancel()
+	if _, err := lock.TryLockContext(ctx, time.Second); err != nil {
+		return fmt.Errorf("failed to lock the repository file: %w", err)
+	}
+	defer lock.Unlock()
+
+	file, err := repo.LoadFile(settings.RepositoryConfig)
+	if errors.Is(err, os.ErrNotExist) {
+		file, err = repo.NewFile(), nil
+	}
+	if err != nil {
+		return err
+	}
+
+	entry := &repo.Entry{Name: name, URL: url}
+	chartRepo, err := repo.NewChartRepository(entry, getter.All(settings))
+	if err != nil {
+		return err
+	}
+	chartRepo.CachePath = settings.RepositoryCache
+	if _, err = chartRepo.DownloadIndexFile(); err != nil {
+		return fmt.Errorf("%s is not a valid chart repository or can not be reached: %w", url, err)
+	}
+	c.logf("Added helm repository %s (%s)", name, url)
+	file.Update(entry)
+	return file.WriteFile(settings.RepositoryConfig, 0o600)
+}
+
+// InstallOrUpgrade installs the release, or upgrades it if it is already installed, like helm upgrade --install; the values of the release are reused
+// by the upgrade if none is given, like the helm binary does.
+func (c *Client) InstallOrUpgrade(ctx context.Context, r Release) (*release.Release, error) {
+	settings := c.settings(r.Namespace)
+	config, err := c.configuration(settings)
+	if err != nil {
+		return nil, &Error{Op: "upgrade", Name: r.Name, Err: err}
+	}
+	timeout := r.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	version := r.Version
+	if r.Devel && version == "" {
+		// the constraint of helm --devel, matching the pre-releases
+		version = ">0.0.0-0"
+	}
+
+	history := action.NewHistory(config)
+	history.Max = 1
+	if _, err = history.Run(r.Name); IsReleaseNotFound(err) {
+		install := action.NewInstall(config)
+		install.ReleaseName, install.Namespace, install.CreateNamespace = r.Name, settings.Namespace(), r.CreateNamespace
+		install.Wait, install.WaitForJobs, install.Timeout = r.Wait, r.WaitForJobs, timeout
+		install.Version = version
+		chart, err := c.loadChart(&install.ChartPathOptions, r.Chart, settings)
+		if err != nil {
+			return nil, &Error{Op: "install", Name: r.Name, Err: err}
+		}
+		c.logf("Installing release %s of chart %s %s in namespace %s", r.Name, chart.Name(), chart.Metadata.Version, settings.Namespace())
+		installed, err := install.RunWithContext(ctx, chart, r.Values)
+		if err != nil {
+			return nil, &Error{Op: "install", Name: r.Name, Err: err}
+		}
+		return installed, nil
+	} else if err != nil {
+		return nil, &Error{Op: "upgrade", Name: r.Name, Err: err}
+	}
+
+	upgrade := action.NewUpgrade(config)
+	upgrade.Namespace, upgrade.Wait, upgrade.WaitForJobs, upgrade.Timeout = settings.Namespace(), r.Wait, r.WaitForJobs, timeout
+	upgrade.Version = version
+	chart, err := c.loadChart(&upgrade.ChartPathOptions, r.Chart, settings)
+	if err != nil {
+		return nil, &Error{Op: "upgrade", Name: r.Name, Err: err}
+	}
+	c.logf("Upgrading release %s to chart %s %s in namespace %s", r.Name, chart.Name(), chart.Metadata.Version, settings.Namespace())
+	upgraded, err := upgrade.RunWithContext(ctx, r.Name, chart, r.Values)
+	if err != nil {
+		return nil, &Error{Op: "upgrade", Name: r.Name, Err: err}
+	}
+	return upgraded, nil
+}
+
+// Pull downloads the archive of a version of the chart to the directory, like helm pull --destination; the reference is the chart of a repository
+// added beforehand, for e.g. rancher-charts/rancher-backup, and the version is a version or version constraint, its newest stable version if empty
+func (c *Client) Pull(reference, version, destination string) error {
+	pull := action.NewPullWithOpts(action.WithConfig(&action.Configuration{}))
+	pull.Settings = c.settings("")
+	pull.Version, pull.DestDir = version, destination
+	if _, err := pull.Run(reference); err != nil {
+		return &Error{Op: "pull", Name: reference, Err: err}
+	}
+	c.logf("Pulled chart %s %s to %s", reference, version, destination)
+	return nil
+}
+
+// loadChart locates the chart, downloading it from its repository if it is not a local archive, and loads it
+func (c *Client) loadChart(options *action.ChartPathOptions, reference string, settings *cli.EnvSettings) (*chart.Chart, error) {
+	path, err := options.LocateChart(reference, settings)
+	if err != nil {
+		return nil, err
+	}
+	return loader.Load(path)
+}
+
+// Uninstall uninstalls the release of the namespace, like helm uninstall
+func (c *Client) Uninstall(name, namespace string) error {
+	config, err := c.configuration(c.settings(namespace))
+	if err != nil {
+		return &Error{Op: "uninstall", Name: name, Err: err}
+	}
+	if _, err = action.NewUninstall(config).Run(name); err != nil {
+		return &Error{Op: "uninstall", Name: name, Err: err}
+	}
+	c.logf("Uninstalled release %s of namespace %s", name, namespace)
+	return nil
+}
+
+// SetValues sets the values of the key=value pairs like helm --set, for e.g. extraEnv[0].name=CATTLE_SERVER_URL; the commas of a value must be escaped, \,
+func SetValues(values map[string]any, set ...string) error {
+	for _, value := range set {
+		if err := strvals.ParseInto(value, values); err != nil {
+			return fmt.Errorf("failed to set %q: %w", value, err)
+		}
+	}
+	return nil
+}
+
+// SetStringValues is SetValues keeping the values as strings, like helm --set-string
+func SetStringValues(values map[string]any, set ...string) error {
+	for _, value := range set {
+		if err := strvals.ParseIntoString(value, values); err != nil {
+			return fmt.Errorf("failed to set %q: %w", value, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package helmsdk
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+// chartRepository serves an empty helm repository index
+func chartRepository(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/index.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, "apiVersion: v1\nentries: {}\n")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// helmHome points the helm settings to a temporary repository file and cache
+func helmHome(t *testing.T) (string, string) {
+	dir := t.TempDir()
+	config, cache := filepath.Join(dir, "config", "repositories.yaml"), filepath.Join(dir, "cache")
+	t.Setenv("HELM_REPOSITORY_CONFIG", config)
+	t.Setenv("HELM_REPOSITORY_CACHE", cache)
+	return config, cache
+}
+
+func TestAddRepoReplacesTheURL(t *testing.T) {
+	_, cache := helmHome(t)
+	first, second := chartRepository(t), chartRepository(t)
+	client := &Client{}
+
+	if repos, err := client.Repositories(); err != nil || len(repos) != 0 {
+		t.Fatalf("got %v, %v before adding a repository", repos, err)
+	}
+	for _, url := range []string{first.URL, second.URL} {
+		if err := client.AddRepo("charts", url); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	repos, err := client.Repositories()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repos) != 1 || repos[0].Name != "charts" || repos[0].URL != second.URL {
+		t.Errorf("got %+v, want charts with %s", repos, second.URL)
+	}
+	if client.RepositoryCache() != cache {
+		t.Errorf("got cache %s, want %s", client.RepositoryCache(), cache)
+	}
+	if _, err = os.Stat(filepath.Join(cache, "charts-index.yaml")); err != nil {
+		t.Errorf("the index is not in the cache: %v", err)
+	}
+}
+
+func TestAddRepoError(t *testing.T) {
+	helmHome(t)
+	server := chartRepository(t)
+	client := &Client{}
+
+	var helmErr *Error
+	err := client.AddRepo("charts", server.URL+"/missing")
+	if !errors.As(err, &helmErr) || helmErr.Op != "repo add" || helmErr.Name != "charts" {
+		t.Fatalf("expected a repo add *Error, got %v", err)
+	}
+	if err = client.AddRepo("rancher/charts", server.URL); err == nil {
+		t.Error("expected an error for a repository name with a /")
+	}
+	if repos, _ := client.Repositories(); len(repos) != 0 {
+		t.Errorf("got %v, no repository must be added", repos)
+	}
+}
+
+func TestPull(t *testing.T) {
+	helmHome(t)
+	// a repository serving the archives of two versions of a chart
+	dir := t.TempDir()
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		metadata := &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "demo", Version: version}
+		if _, err := chartutil.Save(&chart.Chart{Metadata: metadata}, dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	t.Cleanup(server.Close)
+	index, err := repo.IndexDirectory(dir, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = index.WriteFile(filepath.Join(dir, "index.yaml"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := &Client{}
+	if err = client.AddRepo("charts", server.URL); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	destination := t.TempDir()
+	if err = client.Pull("charts/demo", "1.0.0", destination); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pulled, _ := filepath.Glob(filepath.Join(destination, "*.tgz")); len(pulled) != 1 || filepath.Base(pulled[0]) != "demo-1.0.0.tgz" {
+		t.Errorf("got %v, want demo-1.0.0.tgz", pulled)
+	}
+
+	var helmErr *Error
+	if err = client.Pull("charts/demo", "2.0.0", destination); !errors.As(err, &helmErr) || helmErr.Op != "pull" || helmErr.Name != "charts/demo" {
+		t.Errorf("expected a pull *Error for a missing version, got %v", err)
+	}
+}
+
+func TestIsReleaseNotFound(t *testing.T) {
+	err := &Error{Op: "uninstall", Name: "rancher", Err: fmt.Errorf("uninstall: Release not loaded: rancher: %w", driver.ErrReleaseNotFound)}
+	if !IsReleaseNotFound(err) {
+		t.Errorf("%v must be a release not found", err)
+	}
+	if IsReleaseNotFound(&Error{Op: "install", Name: "rancher", Err: errors.New("timed out waiting for the condition")}) {
+		t.Error("a timeout must not be a release not found")
+	}
+}
+
+func TestSetValues(t *testing.T) {
+	values := map[string]any{}
+	if err := SetValues(values, "extraEnv[0].name=CATTLE_SERVER_URL", "noProxy=127.0.0.0/8\\,.svc", "replicas=1", "replicas=3"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := SetStringValues(values, "extraEnv[0].value=true"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]any{
+		"extraEnv": []any{map[string]any{"name": "CATTLE_SERVER_URL", "value": "true"}},
+		"noProxy":  "127.0.0.0/8,.svc",
+		"replicas": int64(3),
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("got %#v, want %#v", values, want)
+	}
+
+	if err := SetValues(values, "extraEnv[x].name=invalid"); err == nil {
+		t.Error("expected an error for an invalid index")
+	}
+}
@@ -24,6 +24,8 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 )
 
 var (
@@ -73,14 +75,17 @@ var _ = BeforeSuite(func() {
 	rancherVersion = os.Getenv("RANCHER_VERSION")
 	Expect(rancherVersion).ToNot(BeEmpty(), "RANCHER_VERSION environment variable is required")
 	kubeConfig = os.Getenv("KUBECONFIG")
-	Expect(kubeConfig).ToNot(BeEmpty(), "KUBECONFIG environment variable is required")
 	k3sVersion = os.Getenv("INSTALL_K3S_VERSION")
-	Expect(k3sVersion).ToNot(BeEmpty(), "INSTALL_K3S_VERSION environment variable is required")
-	proxy = os.Getenv("RANCHER_BEHIND_PROXY")
-	proxyHost = os.Getenv("PROXY_HOST")
-	if proxyHost == "" {
-		proxyHost = "172.17.0.1:3128"
+	if !helpers.IsDockerBackend() {
+		// The docker backend does not need an upstream k3s cluster
+		Expect(kubeConfig).ToNot(BeEmpty(), "KUBECONFIG environment variable is required")
+	}
+	if !helpers.IsDockerBackend() && !helpers.IsLocalClusterBackend() {
+		// The k3d and kind clusters use the default image of their backend without INSTALL_K3S_VERSION
+		Expect(k3sVersion).ToNot(BeEmpty(), "INSTALL_K3S_VERSION environment variable is required")
 	}
+	proxy = os.Getenv("RANCHER_BEHIND_PROXY")
+	proxyHost = helpers.ProxyHost
 	nightlyChart = os.Getenv("NIGHTLY_CHART")
 	providerOperator = os.Getenv("PROVIDER")
 	skipInstallRancher = os.Getenv("SKIP_RANCHER_INSTALL")
@@ -94,3 +99,8 @@ var _ = BeforeSuite(func() {
 		rancherHeadVersion = s[2]
 	}
 })
+
+var _ = ReportAfterSuite("Reports", func(report Report) {
+	// Write the JSON, JUnit and summary reports to the artifacts directory
+	helpers.GenerateSuiteReports(report)
+})